@@ -1,6 +1,6 @@
 import { describe, it, expect } from "vitest";
 import type { TDM } from "@thirdwatch/tdm";
-import { diffTDMs, extractAllEntries, formatDiffAsMarkdown } from "../diff.js";
+import { diffTDMs, extractAllEntries, formatDiffAsMarkdown, collectSuggestedFixes } from "../diff.js";
 
 // ---------------------------------------------------------------------------
 // Helpers
@@ -258,4 +258,24 @@ describe("formatDiffAsMarkdown", () => {
 
     expect(md).toContain("[Thirdwatch](https://thirdwatch.dev)");
   });
+
+  it("lists suggested fixes for new dependencies with their diffs", () => {
+    const remediation = {
+      kind: "add_timeout" as const,
+      description: "Pass a timeout",
+      replacement: "resp = requests.post(url, timeout=10)",
+      diff: "--- a/payments.py\n+++ b/payments.py\n@@ -10,1 +10,1 @@\n-resp = requests.post(url)\n+resp = requests.post(url, timeout=10)\n",
+    };
+    const api = { ...stripeApi, locations: [{ file: "payments.py", line: 10, fingerprint: "a1b2c3d4e5f60718", remediation }] };
+    const current = makeTDM({ apis: [api], sdks: [awsSdk] });
+    const diff = diffTDMs(makeTDM({ sdks: [awsSdk] }), current);
+
+    expect(collectSuggestedFixes(diff.added)).toEqual([
+      { key: "api:POST:https://api.stripe.com/v1/charges", file: "payments.py", line: 10, fingerprint: "a1b2c3d4e5f60718", remediation },
+    ]);
+    const md = formatDiffAsMarkdown(diff, current);
+    expect(md).toContain("### 🛠️ Suggested Fixes (1)");
+    expect(md).toContain("- `payments.py:10` — Pass a timeout");
+    expect(md).toContain("  +resp = requests.post(url, timeout=10)");
+  });
 });
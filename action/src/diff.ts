@@ -7,6 +7,7 @@ import type {
   TDMSdk,
   TDMInfrastructure,
   TDMWebhook,
+  TDMRemediation,
 } from "@thirdwatch/tdm";
 
 // ---------------------------------------------------------------------------
@@ -39,6 +40,15 @@ export interface TDMDiff {
   summary: DiffSummary;
 }
 
+/** A remediation the scan attached to one location of a dependency */
+export interface SuggestedFix {
+  key: string;
+  file: string;
+  line: number;
+  fingerprint?: string;
+  remediation: TDMRemediation;
+}
+
 // ---------------------------------------------------------------------------
 // Key generation — stable identity for each dependency type
 // ---------------------------------------------------------------------------
@@ -130,6 +140,28 @@ export function diffTDMs(baseline: TDM | null, current: TDM): TDMDiff {
   };
 }
 
+// ---------------------------------------------------------------------------
+// Suggested fixes — remediations on the given entries' locations
+// ---------------------------------------------------------------------------
+
+export function collectSuggestedFixes(entries: DiffEntry[]): SuggestedFix[] {
+  return entries.flatMap(({ key, entry }) =>
+    entry.locations.flatMap((loc) =>
+      loc.remediation
+        ? [
+            {
+              key,
+              file: loc.file,
+              line: loc.line,
+              ...(loc.fingerprint ? { fingerprint: loc.fingerprint } : {}),
+              remediation: loc.remediation,
+            },
+          ]
+        : [],
+    ),
+  );
+}
+
 // ---------------------------------------------------------------------------
 // Format diff as a Markdown PR comment
 // ---------------------------------------------------------------------------
@@ -185,6 +217,24 @@ export function formatDiffAsMarkdown(diff: TDMDiff, tdm: TDM): string {
     lines.push("");
   }
 
+  const fixes = collectSuggestedFixes(added);
+  if (fixes.length > 0) {
+    lines.push(`### 🛠️ Suggested Fixes (${fixes.length})`);
+    for (const fix of fixes.slice(0, 10)) {
+      lines.push(`- \`${fix.file}:${fix.line}\` — ${fix.remediation.description}`);
+      if (fix.remediation.diff) {
+        lines.push("");
+        lines.push("  ```diff");
+        for (const line of fix.remediation.diff.trimEnd().split("\n")) lines.push(`  ${line}`);
+        lines.push("  ```");
+      }
+    }
+    if (fixes.length > 10) {
+      lines.push(`*...and ${fixes.length - 10} more*`);
+    }
+    lines.push("");
+  }
+
   if (added.length === 0 && removed.length === 0) {
     lines.push(
       "✅ No dependency surface area changes detected in this PR.",
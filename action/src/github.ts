@@ -2,6 +2,7 @@
 
 import type { GitHub } from "@actions/github/lib/utils.js";
 import type { Context } from "@actions/github/lib/context.js";
+import type { SuggestedFix } from "./diff.js";
 
 type Octokit = InstanceType<typeof GitHub>;
 
@@ -52,3 +53,57 @@ export async function postPRComment(
     });
   }
 }
+
+// ---------------------------------------------------------------------------
+// Post suggested fixes as one-click review suggestions on the changed lines
+// ---------------------------------------------------------------------------
+
+const FIX_MARKER = "<!-- thirdwatch-fix:";
+
+/**
+ * Comment a ```suggestion block on each fix's line. GitHub only accepts
+ * review comments on lines the PR changed, so fixes elsewhere are skipped;
+ * fixes already suggested on an earlier run are not repeated.
+ * Returns the number of suggestions posted.
+ */
+export async function postFixSuggestions(
+  octokit: Octokit,
+  context: Context,
+  fixes: SuggestedFix[],
+): Promise<number> {
+  const pr = context.payload.pull_request;
+  if (!pr?.number) {
+    return 0;
+  }
+
+  const { owner, repo } = context.repo;
+  const { data: existing } = await octokit.rest.pulls.listReviewComments({
+    owner,
+    repo,
+    pull_number: pr.number,
+    per_page: 100,
+  });
+  const posted = new Set(existing.flatMap((c) => c.body?.match(/<!-- thirdwatch-fix:(\S+) -->/)?.[1] ?? []));
+
+  let count = 0;
+  for (const fix of fixes) {
+    const id = fix.fingerprint ?? `${fix.file}:${fix.line}`;
+    if (fix.remediation.replacement === undefined || posted.has(id)) continue;
+    try {
+      await octokit.rest.pulls.createReviewComment({
+        owner,
+        repo,
+        pull_number: pr.number,
+        commit_id: pr.head.sha,
+        path: fix.file,
+        line: fix.line,
+        side: "RIGHT",
+        body: `${FIX_MARKER}${id} -->\n${fix.remediation.description}\n\n\`\`\`suggestion\n${fix.remediation.replacement}\n\`\`\``,
+      });
+      count++;
+    } catch {
+      // Line not part of the PR diff
+    }
+  }
+  return count;
+}
@@ -9,9 +9,9 @@ import { parseTDM } from "@thirdwatch/tdm";
 import type { TDM } from "@thirdwatch/tdm";
 import { writeFile, readFile } from "node:fs/promises";
 import { resolve } from "node:path";
-import { diffTDMs, formatDiffAsMarkdown } from "./diff.js";
+import { diffTDMs, formatDiffAsMarkdown, collectSuggestedFixes } from "./diff.js";
 import { downloadBaselineTDM, uploadTDM } from "./cloud.js";
-import { postPRComment } from "./github.js";
+import { postPRComment, postFixSuggestions } from "./github.js";
 
 async function run(): Promise<void> {
   try {
@@ -20,7 +20,11 @@ async function run(): Promise<void> {
     const token = core.getInput("token");
     const githubToken = core.getInput("github-token");
     const failOnNew = core.getBooleanInput("fail-on-new-dependencies");
+    const suggestFixes = core.getBooleanInput("suggest-fixes");
     const outFile = core.getInput("out-file") || "thirdwatch.json";
+    // Anyone who opens a PR controls the checked-out .thirdwatch.yml, so its
+    // detector commands only run from a config the workflow names explicitly
+    const detectorsConfig = core.getInput("detectors-config");
 
     // 1. Scan
     core.startGroup("Thirdwatch: Scanning dependencies");
@@ -28,6 +32,9 @@ async function run(): Promise<void> {
     const result = await scan({
       root: scanPath,
       plugins: [new PythonPlugin(), new JavaScriptPlugin()],
+      ...(detectorsConfig
+        ? { configFile: resolve(detectorsConfig), detectors: true }
+        : { detectors: false }),
     });
     const tdm = result.tdm;
     // Inject repository from GitHub context for cloud scoping
@@ -93,6 +100,8 @@ async function run(): Promise<void> {
       JSON.stringify(diff.removed.map((d) => d.key)),
     );
     core.setOutput("new-dependency-count", String(diff.added.length));
+    const fixes = collectSuggestedFixes(diff.added);
+    core.setOutput("suggested-fixes", JSON.stringify(fixes));
 
     core.info(
       `Diff: +${diff.summary.addedCount} new, -${diff.summary.removedCount} removed, ${diff.summary.unchangedCount} unchanged`,
@@ -106,6 +115,10 @@ async function run(): Promise<void> {
         const comment = formatDiffAsMarkdown(diff, tdm);
         await postPRComment(octokit, github.context, comment);
         core.info("PR comment posted");
+        if (suggestFixes && fixes.length > 0) {
+          const posted = await postFixSuggestions(octokit, github.context, fixes);
+          core.info(`${posted} fix suggestion(s) posted on changed lines`);
+        }
       } catch (err) {
         core.warning(
           `Failed to post PR comment: ${err instanceof Error ? err.message : String(err)}`,
@@ -0,0 +1,178 @@
+import { describe, it, expect } from "vitest";
+import { generateKeyPairSync, sign, createHmac, constants } from "node:crypto";
+import type { KeyObject } from "node:crypto";
+import { checkIdToken, roleFromClaims, rolePolicy, signInRole } from "../oidc.js";
+import type { IdTokenClaims } from "../oidc.js";
+
+const ISSUER = "https://idp.example.com";
+const CLIENT_ID = "thirdwatch";
+const NOW = 1_700_000_000;
+
+const rsa = generateKeyPairSync("rsa", { modulusLength: 2048 });
+const ec = generateKeyPairSync("ec", { namedCurve: "P-256" });
+const other = generateKeyPairSync("rsa", { modulusLength: 2048 });
+
+const segment = (value: unknown) => Buffer.from(JSON.stringify(value)).toString("base64url");
+
+function claims(overrides: Partial<IdTokenClaims> = {}): IdTokenClaims {
+  return { iss: ISSUER, sub: "user-1", aud: CLIENT_ID, exp: NOW + 300, iat: NOW, nonce: "n-1", ...overrides };
+}
+
+function jwt(body: Record<string, unknown>, alg = "RS256", key: KeyObject = rsa.privateKey): string {
+  const signingInput = `${segment({ alg, kid: "k1" })}.${segment(body)}`;
+  let signature: Buffer;
+  if (alg === "none") signature = Buffer.alloc(0);
+  else if (alg === "HS256") signature = createHmac("sha256", "secret").update(signingInput).digest();
+  else if (alg === "ES256") signature = sign("sha256", Buffer.from(signingInput), { key, dsaEncoding: "ieee-p1363" });
+  else if (alg === "PS256") {
+    signature = sign("sha256", Buffer.from(signingInput), { key, padding: constants.RSA_PKCS1_PSS_PADDING, saltLength: 32 });
+  } else signature = sign("sha256", Buffer.from(signingInput), key);
+  return `${signingInput}.${signature.toString("base64url")}`;
+}
+
+const keys = (key: KeyObject) => async () => key;
+const expected = { issuer: ISSUER, clientId: CLIENT_ID, nonce: "n-1", now: NOW };
+
+describe("checkIdToken", () => {
+  it("accepts a valid RS256 token and returns its claims", async () => {
+    const verified = await checkIdToken(jwt(claims({ email: "a@example.com" })), keys(rsa.publicKey), expected);
+    expect(verified.sub).toBe("user-1");
+    expect(verified.email).toBe("a@example.com");
+  });
+
+  it("accepts ES256 and PS256 tokens", async () => {
+    await expect(checkIdToken(jwt(claims(), "ES256", ec.privateKey), keys(ec.publicKey), expected)).resolves.toBeTruthy();
+    await expect(checkIdToken(jwt(claims(), "PS256"), keys(rsa.publicKey), expected)).resolves.toBeTruthy();
+  });
+
+  it("passes the token's key ID to the key lookup", async () => {
+    const kids: Array<string | undefined> = [];
+    await checkIdToken(jwt(claims()), async (kid) => (kids.push(kid), rsa.publicKey), expected);
+    expect(kids).toEqual(["k1"]);
+  });
+
+  it("rejects alg none and HMAC tokens", async () => {
+    await expect(checkIdToken(jwt(claims(), "none"), keys(rsa.publicKey), expected)).rejects.toThrow(/algorithm none/);
+    await expect(checkIdToken(jwt(claims(), "HS256"), keys(rsa.publicKey), expected)).rejects.toThrow(/algorithm HS256/);
+  });
+
+  it("rejects a token signed by another key or altered after signing", async () => {
+    await expect(checkIdToken(jwt(claims(), "RS256", other.privateKey), keys(rsa.publicKey), expected)).rejects.toThrow(/signature/);
+    const [header, , signature] = jwt(claims()).split(".");
+    const forged = `${header}.${segment(claims({ sub: "admin" }))}.${signature}`;
+    await expect(checkIdToken(forged, keys(rsa.publicKey), expected)).rejects.toThrow(/signature/);
+  });
+
+  it("rejects malformed tokens", async () => {
+    await expect(checkIdToken("a.b", keys(rsa.publicKey), expected)).rejects.toThrow(/not a JWT/);
+  });
+
+  it("checks the issuer, ignoring a trailing slash", async () => {
+    await expect(checkIdToken(jwt(claims({ iss: `${ISSUER}/` })), keys(rsa.publicKey), expected)).resolves.toBeTruthy();
+    await expect(checkIdToken(jwt(claims({ iss: "https://evil.example.com" })), keys(rsa.publicKey), expected)).rejects.toThrow(/issuer/);
+  });
+
+  it("requires this client among the audiences", async () => {
+    await expect(checkIdToken(jwt(claims({ aud: "someone-else" })), keys(rsa.publicKey), expected)).rejects.toThrow(/not for this client/);
+    await expect(checkIdToken(jwt(claims({ aud: ["a", "b"] })), keys(rsa.publicKey), expected)).rejects.toThrow(/not for this client/);
+  });
+
+  it("requires azp to name this client when there are several audiences", async () => {
+    const aud = [CLIENT_ID, "other-app"];
+    await expect(checkIdToken(jwt(claims({ aud, azp: CLIENT_ID })), keys(rsa.publicKey), expected)).resolves.toBeTruthy();
+    await expect(checkIdToken(jwt(claims({ aud })), keys(rsa.publicKey), expected)).rejects.toThrow(/another party/);
+    await expect(checkIdToken(jwt(claims({ aud, azp: "other-app" })), keys(rsa.publicKey), expected)).rejects.toThrow(/another party/);
+    await expect(checkIdToken(jwt(claims({ azp: "other-app" })), keys(rsa.publicKey), expected)).rejects.toThrow(/another party/);
+  });
+
+  it("rejects expired tokens beyond the clock skew", async () => {
+    await expect(checkIdToken(jwt(claims({ exp: NOW - 30 })), keys(rsa.publicKey), expected)).resolves.toBeTruthy();
+    await expect(checkIdToken(jwt(claims({ exp: NOW - 61 })), keys(rsa.publicKey), expected)).rejects.toThrow(/expired/);
+    const { exp: _exp, ...noExp } = claims();
+    await expect(checkIdToken(jwt(noExp), keys(rsa.publicKey), expected)).rejects.toThrow(/expired/);
+  });
+
+  it("requires a subject", async () => {
+    await expect(checkIdToken(jwt(claims({ sub: "" })), keys(rsa.publicKey), expected)).rejects.toThrow(/subject/);
+  });
+
+  it("checks the nonce when one is expected", async () => {
+    await expect(checkIdToken(jwt(claims({ nonce: "replayed" })), keys(rsa.publicKey), expected)).rejects.toThrow(/nonce/);
+    const { nonce: _nonce, ...noNonce } = claims();
+    await expect(checkIdToken(jwt(noNonce), keys(rsa.publicKey), expected)).rejects.toThrow(/nonce/);
+    const { nonce: _expected, ...anyNonce } = expected;
+    await expect(checkIdToken(jwt(claims({ nonce: "whatever" })), keys(rsa.publicKey), anyNonce)).resolves.toBeTruthy();
+  });
+});
+
+describe("rolePolicy", () => {
+  it("parses the role map, skipping malformed pairs and unknown roles", () => {
+    const policy = rolePolicy({ OIDC_ROLE_MAP: " admins = admin ,security=approver,broken,ops=root,=viewer" });
+    expect(policy.map).toEqual([
+      ["admins", "admin"],
+      ["security", "approver"],
+    ]);
+  });
+
+  it("defaults to the groups claim and the viewer role", () => {
+    const policy = rolePolicy({ OIDC_DEFAULT_ROLE: "superuser" });
+    expect(policy.claim).toBe("groups");
+    expect(policy.defaultRole).toBe("viewer");
+    expect(policy.map).toEqual([]);
+  });
+
+  it("lowercases admin emails", () => {
+    expect(rolePolicy({ OIDC_ADMIN_EMAILS: " Ops@Example.com, ,sec@example.com" }).adminEmails).toEqual([
+      "ops@example.com",
+      "sec@example.com",
+    ]);
+  });
+});
+
+describe("roleFromClaims", () => {
+  const policy = rolePolicy({ OIDC_ROLE_MAP: "everyone=viewer,thirdwatch-admins=admin,security=approver", OIDC_DEFAULT_ROLE: "viewer" });
+
+  it("returns null when no role map is configured", () => {
+    expect(roleFromClaims(claims({ groups: ["thirdwatch-admins"] }), rolePolicy({}))).toBeNull();
+  });
+
+  it("picks the highest mapped role whatever the group order", () => {
+    expect(roleFromClaims(claims({ groups: ["everyone", "security"] }), policy)).toBe("approver");
+    expect(roleFromClaims(claims({ groups: ["thirdwatch-admins", "security", "everyone"] }), policy)).toBe("admin");
+    expect(roleFromClaims(claims({ groups: ["security", "thirdwatch-admins"] }), policy)).toBe("admin");
+  });
+
+  it("falls back to the default role for unmapped users", () => {
+    expect(roleFromClaims(claims({ groups: ["marketing"] }), policy)).toBe("viewer");
+    expect(roleFromClaims(claims(), rolePolicy({ OIDC_ROLE_MAP: "security=approver", OIDC_DEFAULT_ROLE: "approver" }))).toBe("approver");
+  });
+
+  it("reads a space- or comma-separated string claim and a custom claim name", () => {
+    expect(roleFromClaims(claims({ groups: "everyone security" }), policy)).toBe("approver");
+    const custom = rolePolicy({ OIDC_ROLES_CLAIM: "roles", OIDC_ROLE_MAP: "tw-admin=admin" });
+    expect(roleFromClaims(claims({ roles: "x,tw-admin" }), custom)).toBe("admin");
+    expect(roleFromClaims(claims({ groups: ["tw-admin"] }), custom)).toBe("viewer");
+  });
+});
+
+describe("signInRole", () => {
+  const admins = rolePolicy({ OIDC_ADMIN_EMAILS: "ops@example.com" });
+
+  it("uses the mapped role even for a listed admin email", () => {
+    const mapped = rolePolicy({ OIDC_ROLE_MAP: "security=approver", OIDC_ADMIN_EMAILS: "ops@example.com" });
+    expect(signInRole(claims({ email: "ops@example.com", groups: ["security"] }), mapped)).toBe("approver");
+    expect(signInRole(claims({ email: "ops@example.com" }), mapped)).toBe("viewer");
+  });
+
+  it("makes listed emails admins unless the provider says they are unverified", () => {
+    expect(signInRole(claims({ email: "OPS@example.com" }), admins)).toBe("admin");
+    expect(signInRole(claims({ email: "ops@example.com", email_verified: true }), admins)).toBe("admin");
+    expect(signInRole(claims({ email: "ops@example.com", email_verified: false }), admins)).toBeNull();
+  });
+
+  it("confers nothing otherwise", () => {
+    expect(signInRole(claims({ email: "someone@example.com" }), admins)).toBeNull();
+    expect(signInRole(claims(), admins)).toBeNull();
+    expect(signInRole(claims({ email: "ops@example.com" }), rolePolicy({}))).toBeNull();
+  });
+});
@@ -0,0 +1,59 @@
+import { describe, it, expect } from "vitest";
+import type { FastifyReply, FastifyRequest } from "fastify";
+import { hasRole, isRole, requireRole } from "../middleware/rbac.js";
+import type { Role } from "../middleware/rbac.js";
+
+function fakeReply() {
+  const sent: { status?: number; body?: unknown } = {};
+  const reply = {
+    status(code: number) {
+      sent.status = code;
+      return reply;
+    },
+    send(body: unknown) {
+      sent.body = body;
+      return reply;
+    },
+  };
+  return { reply: reply as unknown as FastifyReply, sent };
+}
+
+async function run(required: Role, role: Role) {
+  const req = { actor: { userId: "u1", name: "Ada", role } } as unknown as FastifyRequest;
+  const { reply, sent } = fakeReply();
+  await requireRole(required)(req, reply);
+  return sent;
+}
+
+describe("hasRole", () => {
+  it("orders viewer < approver < admin", () => {
+    expect(hasRole("admin", "approver")).toBe(true);
+    expect(hasRole("approver", "approver")).toBe(true);
+    expect(hasRole("approver", "admin")).toBe(false);
+    expect(hasRole("viewer", "approver")).toBe(false);
+  });
+});
+
+describe("isRole", () => {
+  it("accepts only known roles", () => {
+    expect(isRole("approver")).toBe(true);
+    expect(isRole("owner")).toBe(false);
+    expect(isRole(undefined)).toBe(false);
+  });
+});
+
+describe("requireRole", () => {
+  it("lets equal and higher roles through", async () => {
+    expect(await run("approver", "approver")).toEqual({});
+    expect(await run("approver", "admin")).toEqual({});
+    expect(await run("viewer", "viewer")).toEqual({});
+  });
+
+  it("answers 403 with the caller's role for lower roles", async () => {
+    expect(await run("admin", "approver")).toEqual({
+      status: 403,
+      body: { error: "forbidden", message: "This action requires the admin role.", role: "approver" },
+    });
+    expect((await run("approver", "viewer")).status).toBe(403);
+  });
+});
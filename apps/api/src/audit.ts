@@ -0,0 +1,87 @@
+import type { FastifyRequest } from "fastify";
+import { formatCsv } from "@thirdwatch/core";
+import { actorOf } from "./middleware/rbac.js";
+import { db, transaction } from "./db.js";
+import type { AuditEntry, Queryable } from "./db.js";
+
+/**
+ * Who accepted which third-party dependency, and who changed the rules
+ * around it. Entries are written in the same transaction as the change and
+ * can't be edited or deleted afterwards (see 011_audit_log.sql).
+ */
+export type AuditAction =
+  | "vendor.approved"
+  | "vendor.denied"
+  | "vendor.approval_removed"
+  | "suppression.created"
+  | "suppression.deleted"
+  | "team.created"
+  | "team.updated"
+  | "team.deleted"
+  | "user.role_changed"
+  | "api_key.created"
+  | "api_key.deleted"
+  | "badge_token.created"
+  | "badge_token.deleted"
+  | "notification_channel.created"
+  | "notification_channel.updated"
+  | "notification_channel.deleted"
+  | "routing_rule.created"
+  | "routing_rule.deleted"
+  | "publisher.created"
+  | "publisher.updated"
+  | "publisher.deleted"
+  | "vendor_feed_subscription.created"
+  | "vendor_feed_subscription.deleted";
+
+export interface AuditRecord {
+  action: AuditAction;
+  target: string;
+  details?: Record<string, unknown>;
+}
+
+/**
+ * Make a change and record it atomically: `change` runs its queries on the
+ * transaction's client, and `entry` describes the result for the audit log
+ * (null for a no-op). If either write fails, neither is kept. A change that
+ * finds nothing (null or undefined) records nothing.
+ */
+export async function audited<T>(
+  req: FastifyRequest,
+  change: (client: Queryable) => Promise<T>,
+  entry: (result: NonNullable<T>) => AuditRecord | null,
+): Promise<T> {
+  const orgId = (req as any).orgId as string;
+  return transaction(async (client) => {
+    const result = await change(client);
+    const record = result == null ? null : entry(result);
+    if (record) {
+      await db.insertAuditEntry(
+        orgId,
+        actorOf(req),
+        { action: record.action, target: record.target, details: record.details ?? {} },
+        client,
+      );
+    }
+    return result;
+  });
+}
+
+const CSV_COLUMNS = ["seq", "occurred_at", "actor_id", "actor_name", "actor_role", "action", "target", "details"];
+
+/** One row per entry; details stay JSON in their own column */
+export function auditCsv(entries: AuditEntry[]): string {
+  return formatCsv([
+    CSV_COLUMNS,
+    ...entries.map((e) => [
+      e.seq,
+      new Date(e.occurredAt).toISOString(),
+      e.actor.id ?? "",
+      e.actor.name,
+      e.actor.role,
+      e.action,
+      e.target,
+      JSON.stringify(e.details),
+    ]),
+  ]);
+}
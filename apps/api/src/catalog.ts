@@ -0,0 +1,17 @@
+import { dirname, resolve } from "node:path";
+import { fileURLToPath } from "node:url";
+import { loadSDKRegistry } from "@thirdwatch/core";
+import type { SDKRegistryEntry } from "@thirdwatch/core";
+
+const __dirname = dirname(fileURLToPath(import.meta.url));
+const REGISTRIES_DIR =
+  process.env["THIRDWATCH_REGISTRIES_DIR"] ??
+  resolve(__dirname, "../../../registries");
+
+let registry: Promise<SDKRegistryEntry[]> | undefined;
+
+/** The built-in vendor catalog, loaded once */
+export function catalog(): Promise<SDKRegistryEntry[]> {
+  registry ??= loadSDKRegistry(REGISTRIES_DIR);
+  return registry;
+}
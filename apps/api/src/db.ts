@@ -1,4 +1,5 @@
 import pg from "pg";
+import type { Actor, Role } from "./middleware/rbac.js";
 
 const { Pool } = pg;
 
@@ -8,6 +9,30 @@ const DATABASE_URL =
 
 export const pool = new Pool({ connectionString: DATABASE_URL });
 
+/** The pool, or a client inside `transaction` */
+export type Queryable = Pick<pg.PoolClient, "query">;
+
+/**
+ * Run `fn` on one client inside BEGIN/COMMIT, rolling back if it throws.
+ * The caller sees the original error even when ROLLBACK fails too, and a
+ * client that failed is destroyed rather than returned to the pool, since
+ * it may still be inside the aborted transaction.
+ */
+export async function transaction<T>(fn: (client: pg.PoolClient) => Promise<T>): Promise<T> {
+  const client = await pool.connect();
+  try {
+    await client.query("BEGIN");
+    const result = await fn(client);
+    await client.query("COMMIT");
+    client.release();
+    return result;
+  } catch (err) {
+    await client.query("ROLLBACK").catch(() => {});
+    client.release(err instanceof Error ? err : true);
+    throw err;
+  }
+}
+
 const COLUMN_MAP: Record<string, string> = {
   changeType: "change_type",
   classificationConfidence: "classification_confidence",
@@ -31,20 +56,89 @@ function toColumn(key: string): string {
   return COLUMN_MAP[key] ?? key;
 }
 
+export interface SuppressionFields {
+  repository?: string;
+  dependency?: string;
+  changeCategory?: string;
+  minPriority?: string;
+  reason: string;
+  expiresAt?: string;
+}
+
+export interface TeamFields {
+  repositories?: string[];
+  deniedVendors?: string[];
+  digestSchedule?: "daily" | "weekly" | "off";
+  digestEmails?: string[];
+  /** null clears it */
+  slackWebhookUrl?: string | null;
+}
+
+function vendorEventRow(r: Record<string, unknown>) {
+  return {
+    // BIGSERIAL comes back as a string; cursors stay well inside 2^53
+    seq: Number(r.seq),
+    repository: r.repository as string,
+    vendor: r.vendor as string,
+    change: r.change as "added" | "removed",
+    commit: (r.commit_sha as string | null) ?? null,
+    occurredAt: r.occurred_at,
+  };
+}
+
+function auditEntryRow(r: Record<string, unknown>) {
+  return {
+    seq: Number(r.seq),
+    actor: {
+      id: (r.actor_id as string | null) ?? null,
+      name: r.actor_name as string,
+      role: r.actor_role as Role,
+    },
+    action: r.action as string,
+    target: r.target as string,
+    details: r.details as Record<string, unknown>,
+    occurredAt: r.occurred_at as Date,
+  };
+}
+
+export type AuditEntry = ReturnType<typeof auditEntryRow>;
+
 export const db = {
   async getOrgByApiKeyHash(keyHash: string) {
     await pool.query(
       `UPDATE api_keys SET last_used_at = now() WHERE key_hash = $1`,
       [keyHash],
     );
+    // A key never outranks its user, so demoting a user demotes their keys
     const result = await pool.query(
-      `SELECT ak.org_id, ak.permissions, o.plan
+      `SELECT ak.org_id, ak.permissions, o.plan, ak.user_id, ak.name AS key_name, ak.key_prefix,
+              COALESCE(u.github_login, u.email, u.display_name) AS user_name,
+              CASE
+                WHEN u.id IS NULL THEN ak.role
+                WHEN array_position(ARRAY['viewer','approver','admin'], u.role)
+                   < array_position(ARRAY['viewer','approver','admin'], ak.role) THEN u.role
+                ELSE ak.role
+              END AS role
        FROM api_keys ak
        JOIN organizations o ON o.id = ak.org_id
+       LEFT JOIN users u ON u.id = ak.user_id
        WHERE ak.key_hash = $1`,
       [keyHash],
     );
-    return result.rows[0] ?? null;
+    return (
+      (result.rows[0] as
+        | {
+            org_id: string;
+            permissions: string[];
+            plan: string;
+            user_id: string | null;
+            key_name: string | null;
+            key_prefix: string;
+            user_name: string | null;
+            role: Role;
+          }
+        | undefined) ?? null
+    );
   },
 
   async getOrg(orgId: string) {
@@ -113,6 +207,47 @@ export const db = {
         `DELETE FROM tdm_uploads WHERE org_id = $1`,
         [orgId],
       );
+      await client.query(
+        `DELETE FROM runtime_usage WHERE org_id = $1`,
+        [orgId],
+      );
+      await client.query(
+        `DELETE FROM vendor_events WHERE org_id = $1`,
+        [orgId],
+      );
+      await client.query(
+        `DELETE FROM vendor_feed_subscriptions WHERE org_id = $1`,
+        [orgId],
+      );
+      await client.query(
+        `DELETE FROM teams WHERE org_id = $1`,
+        [orgId],
+      );
+      await client.query(
+        `DELETE FROM badge_tokens WHERE org_id = $1`,
+        [orgId],
+      );
+      await client.query(
+        `DELETE FROM inventory_publishers WHERE org_id = $1`,
+        [orgId],
+      );
+      await client.query(
+        `DELETE FROM suppressions WHERE org_id = $1`,
+        [orgId],
+      );
+      await client.query(
+        `DELETE FROM vendor_approvals WHERE org_id = $1`,
+        [orgId],
+      );
+      // The audit log refuses deletes outside of this (see 011_audit_log.sql)
+      await client.query(
+        `SELECT set_config('thirdwatch.deleting_org', $1, true)`,
+        [orgId],
+      );
+      await client.query(
+        `DELETE FROM audit_log WHERE org_id = $1`,
+        [orgId],
+      );
       await client.query(`DELETE FROM api_keys WHERE org_id = $1`, [orgId]);
       await client.query(`DELETE FROM users WHERE org_id = $1`, [orgId]);
       await client.query(`DELETE FROM organizations WHERE id = $1`, [orgId]);
@@ -130,7 +265,7 @@ export const db = {
     githubLogin: string,
     githubId: number,
     avatarUrl: string | null,
-    role = "member",
+    role: Role = "viewer",
   ) {
     const result = await pool.query(
       `INSERT INTO users (org_id, github_login, github_id, avatar_url, role)
@@ -150,32 +285,97 @@ export const db = {
     return result.rows[0] ?? null;
   },
 
+  async getUserByOidcSubject(issuer: string, subject: string) {
+    const result = await pool.query(
+      `SELECT * FROM users WHERE oidc_issuer = $1 AND oidc_subject = $2`,
+      [issuer, subject],
+    );
+    return result.rows[0] ?? null;
+  },
+
+  /** Create or refresh an OIDC user; `role` null keeps an existing user's role */
+  async upsertOidcUser(
+    orgId: string,
+    user: { issuer: string; subject: string; email: string | null; name: string | null; role: Role | null },
+  ) {
+    const result = await pool.query(
+      `INSERT INTO users (org_id, oidc_issuer, oidc_subject, email, display_name, role)
+       VALUES ($1, $2, $3, $4, $5, COALESCE($6, 'viewer'))
+       ON CONFLICT (oidc_issuer, oidc_subject) WHERE oidc_subject IS NOT NULL
+       DO UPDATE SET email = $4, display_name = $5, role = COALESCE($6, users.role)
+       RETURNING *`,
+      [orgId, user.issuer, user.subject, user.email, user.name, user.role],
+    );
+    return result.rows[0];
+  },
+
+  /** The organization OIDC users named `name` join, created if it doesn't exist yet */
+  async getOrCreateOidcOrg(name: string) {
+    const inserted = await pool.query(
+      `INSERT INTO organizations (name, github_org, plan, oidc_name) VALUES ($1, NULL, 'free', $1)
+       ON CONFLICT (oidc_name) WHERE oidc_name IS NOT NULL DO NOTHING
+       RETURNING *`,
+      [name],
+    );
+    if (inserted.rows[0]) return { org: inserted.rows[0], created: true };
+    const existing = await pool.query(`SELECT * FROM organizations WHERE oidc_name = $1`, [name]);
+    return { org: existing.rows[0], created: false };
+  },
+
+  async listUsers(orgId: string) {
+    const result = await pool.query(
+      `SELECT id, github_login, email, display_name, avatar_url, role, created_at FROM users
+       WHERE org_id = $1 ORDER BY created_at`,
+      [orgId],
+    );
+    return result.rows;
+  },
+
+  /** Change a user's role; refuses (returns null) to demote the org's last admin */
+  async updateUserRole(userId: string, orgId: string, role: Role, q: Queryable = pool) {
+    const result = await q.query(
+      `UPDATE users SET role = $3
+       FROM (SELECT role FROM users WHERE id = $1) previous
+       WHERE users.id = $1 AND users.org_id = $2
+         AND ($3 = 'admin' OR users.role <> 'admin'
+              OR (SELECT COUNT(*) FROM users WHERE org_id = $2 AND role = 'admin') > 1)
+       RETURNING users.id, users.github_login, users.email, users.display_name, users.role,
+                 previous.role AS previous_role`,
+      [userId, orgId, role],
+    );
+    return result.rows[0] ?? null;
+  },
+
   async createApiKey(
     orgId: string,
     keyHash: string,
     keyPrefix: string,
     name: string | null,
+    owner: { userId: string | null; role: Role } = { userId: null, role: "admin" },
+    q: Queryable = pool,
   ) {
-    const result = await pool.query(
-      `INSERT INTO api_keys (org_id, key_hash, key_prefix, name) VALUES ($1, $2, $3, $4) RETURNING id, key_prefix, name, permissions, created_at`,
-      [orgId, keyHash, keyPrefix, name],
+    const result = await q.query(
+      `INSERT INTO api_keys (org_id, key_hash, key_prefix, name, user_id, role) VALUES ($1, $2, $3, $4, $5, $6)
+       RETURNING id, key_prefix, name, permissions, role, user_id, created_at`,
+      [orgId, keyHash, keyPrefix, name, owner.userId, owner.role],
     );
     return result.rows[0];
   },
 
   async listApiKeys(orgId: string) {
     const result = await pool.query(
-      `SELECT id, key_prefix, name, permissions, last_used_at, created_at FROM api_keys WHERE org_id = $1 ORDER BY created_at DESC`,
+      `SELECT id, key_prefix, name, permissions, role, user_id, last_used_at, created_at FROM api_keys WHERE org_id = $1 ORDER BY created_at DESC`,
       [orgId],
     );
     return result.rows;
   },
 
-  async deleteApiKey(keyId: string, orgId: string) {
-    await pool.query(
-      `DELETE FROM api_keys WHERE id = $1 AND org_id = $2`,
+  async deleteApiKey(keyId: string, orgId: string, q: Queryable = pool) {
+    const result = await q.query(
+      `DELETE FROM api_keys WHERE id = $1 AND org_id = $2 RETURNING id, key_prefix, name, role`,
       [keyId, orgId],
     );
+    return result.rows[0] ?? null;
   },
 
   async insertTdmUpload(
@@ -186,14 +386,15 @@ export const db = {
     dependencyCount: number,
     tdm: unknown,
     isBaseline: boolean,
+    q: Queryable = pool,
   ) {
     if (isBaseline) {
-      await pool.query(
+      await q.query(
         `UPDATE tdm_uploads SET is_baseline = false WHERE org_id = $1 AND repository = $2 AND is_baseline = true`,
         [orgId, repository],
       );
     }
-    const result = await pool.query(
+    const result = await q.query(
       `INSERT INTO tdm_uploads (org_id, repository, scanner_version, languages, dependency_count, tdm, is_baseline)
        VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *`,
       [orgId, repository, scannerVersion, languages, dependencyCount, JSON.stringify(tdm), isBaseline],
@@ -209,6 +410,16 @@ export const db = {
     return result.rows[0] ?? null;
   },
 
+  async listLatestTDMs(orgId: string) {
+    const result = await pool.query(
+      `SELECT DISTINCT ON (repository) repository, tdm FROM tdm_uploads
+       WHERE org_id = $1 AND is_baseline = true
+       ORDER BY repository, uploaded_at DESC`,
+      [orgId],
+    );
+    return result.rows as Array<{ repository: string; tdm: unknown }>;
+  },
+
   async countDistinctRepos(orgId: string) {
     const result = await pool.query(
       `SELECT COUNT(DISTINCT repository) as count FROM tdm_uploads WHERE org_id = $1`,
@@ -411,6 +622,33 @@ export const db = {
     };
   },
 
+  /** The newest `limit` changes for each repository, in one query */
+  async listChangeEventsByRepository(
+    orgId: string,
+    repositories: string[],
+    opts: { priority?: string[]; limit: number },
+  ) {
+    const result = await pool.query(
+      `SELECT r.repo_name, c.*
+       FROM unnest($2::text[]) AS r(repo_name)
+       CROSS JOIN LATERAL (
+         SELECT ce.*, wd.identifier as dep_identifier, wd.kind as dep_kind, wd.ecosystem as dep_ecosystem, wd.provider as dep_provider
+         FROM change_events ce
+         JOIN watched_dependencies wd ON wd.id = ce.dependency_id
+         WHERE ce.org_id = $1 AND r.repo_name = ANY(wd.repositories)
+           AND ($3::text[] IS NULL OR ce.priority = ANY($3))
+         ORDER BY ce.detected_at DESC
+         LIMIT $4
+       ) c`,
+      [orgId, repositories, opts.priority?.length ? opts.priority : null, opts.limit],
+    );
+    const byRepository = new Map<string, Record<string, unknown>[]>();
+    for (const { repo_name, ...change } of result.rows) {
+      byRepository.set(repo_name, [...(byRepository.get(repo_name) ?? []), change]);
+    }
+    return byRepository;
+  },
+
   async getChangeEvent(id: string, orgId: string) {
     const result = await pool.query(
       `SELECT ce.*, wd.identifier as dep_identifier, wd.kind as dep_kind, wd.ecosystem as dep_ecosystem, wd.provider as dep_provider
@@ -484,8 +722,9 @@ export const db = {
     type: string,
     name: string,
     config: unknown,
+    q: Queryable = pool,
   ) {
-    const result = await pool.query(
+    const result = await q.query(
       `INSERT INTO notification_channels (org_id, type, name, config) VALUES ($1, $2, $3, $4) RETURNING *`,
       [orgId, type, name, JSON.stringify(config)],
     );
@@ -504,6 +743,7 @@ export const db = {
     id: string,
     orgId: string,
     updates: { name?: string; config?: unknown; enabled?: boolean },
+    q: Queryable = pool,
   ) {
     const sets: string[] = [];
     const values: unknown[] = [];
@@ -522,22 +762,23 @@ export const db = {
     }
     if (sets.length === 0) return null;
     values.push(id, orgId);
-    const result = await pool.query(
+    const result = await q.query(
       `UPDATE notification_channels SET ${sets.join(", ")} WHERE id = $${idx++} AND org_id = $${idx} RETURNING *`,
       values,
     );
     return result.rows[0] ?? null;
   },
 
-  async deleteNotificationChannel(id: string, orgId: string) {
-    await pool.query(
+  async deleteNotificationChannel(id: string, orgId: string, q: Queryable = pool) {
+    await q.query(
       `DELETE FROM routing_rules WHERE channel_id = $1 AND org_id = $2`,
       [id, orgId],
     );
-    await pool.query(
-      `DELETE FROM notification_channels WHERE id = $1 AND org_id = $2`,
+    const result = await q.query(
+      `DELETE FROM notification_channels WHERE id = $1 AND org_id = $2 RETURNING id, type, name`,
       [id, orgId],
     );
+    return (result.rows[0] as { id: string; type: string; name: string } | undefined) ?? null;
   },
 
   async createRoutingRule(
@@ -549,8 +790,9 @@ export const db = {
       repositories?: string[];
       schedule?: string;
     },
+    q: Queryable = pool,
   ) {
-    const result = await pool.query(
+    const result = await q.query(
       `INSERT INTO routing_rules (org_id, channel_id, priority, change_category, repositories, schedule)
        VALUES ($1, $2, $3, $4, $5, $6) RETURNING *`,
       [
@@ -576,11 +818,12 @@ export const db = {
     return result.rows;
   },
 
-  async deleteRoutingRule(id: string, orgId: string) {
-    await pool.query(
-      `DELETE FROM routing_rules WHERE id = $1 AND org_id = $2`,
+  async deleteRoutingRule(id: string, orgId: string, q: Queryable = pool) {
+    const result = await q.query(
+      `DELETE FROM routing_rules WHERE id = $1 AND org_id = $2 RETURNING *`,
       [id, orgId],
     );
+    return result.rows[0] ?? null;
   },
 
   async getRoutingRules(orgId: string) {
@@ -664,17 +907,530 @@ export const db = {
     };
   },
 
+  async insertRuntimeUsage(
+    orgId: string,
+    report: {
+      service: string;
+      environment: string | null;
+      windowStart: string;
+      windowEnd: string;
+    },
+    calls: Array<{
+      kind: string;
+      host: string;
+      method: string | null;
+      vendor: string | null;
+      count: number;
+      errors: number;
+      latencyMsTotal: number;
+      latencyMsMax: number;
+    }>,
+  ) {
+    const client = await pool.connect();
+    try {
+      await client.query("BEGIN");
+      for (const c of calls) {
+        await client.query(
+          `INSERT INTO runtime_usage (org_id, service, environment, kind, host, method, vendor, window_start, window_end, call_count, error_count, latency_ms_total, latency_ms_max)
+           VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+          [
+            orgId,
+            report.service,
+            report.environment,
+            c.kind,
+            c.host,
+            c.method,
+            c.vendor,
+            report.windowStart,
+            report.windowEnd,
+            c.count,
+            c.errors,
+            c.latencyMsTotal,
+            c.latencyMsMax,
+          ],
+        );
+      }
+      await client.query("COMMIT");
+    } catch (err) {
+      await client.query("ROLLBACK");
+      throw err;
+    } finally {
+      client.release();
+    }
+  },
+
+  async getRuntimeUsageSummary(
+    orgId: string,
+    opts: { since: string; service?: string },
+  ) {
+    const params: unknown[] = [orgId, opts.since];
+    let where = `org_id = $1 AND window_end >= $2`;
+    if (opts.service) {
+      params.push(opts.service);
+      where += ` AND service = $${params.length}`;
+    }
+    const result = await pool.query(
+      `SELECT service, vendor, host, kind,
+              SUM(call_count)::bigint AS calls,
+              SUM(error_count)::bigint AS errors,
+              SUM(latency_ms_total) / NULLIF(SUM(call_count), 0) AS avg_latency_ms,
+              MAX(latency_ms_max) AS max_latency_ms,
+              MIN(window_start) AS first_seen,
+              MAX(window_end) AS last_seen
+       FROM runtime_usage
+       WHERE ${where}
+       GROUP BY service, vendor, host, kind
+       ORDER BY service, calls DESC`,
+      params,
+    );
+    return result.rows.map((r) => ({
+      service: r.service as string,
+      vendor: (r.vendor as string | null) ?? null,
+      host: r.host as string,
+      kind: r.kind as string,
+      calls: Number(r.calls),
+      errors: Number(r.errors),
+      avgLatencyMs: r.avg_latency_ms === null ? null : Number(r.avg_latency_ms),
+      maxLatencyMs: Number(r.max_latency_ms),
+      firstSeen: r.first_seen,
+      lastSeen: r.last_seen,
+    }));
+  },
+
+  /** Per-namespace vendor rollup of usage pushed by cluster agents ("<namespace>/<workload>" services) */
+  async getRuntimeNamespaceRollup(
+    orgId: string,
+    opts: { since: string; cluster?: string },
+  ) {
+    const params: unknown[] = [orgId, opts.since];
+    let where = `org_id = $1 AND window_end >= $2 AND position('/' in service) > 0`;
+    if (opts.cluster) {
+      params.push(opts.cluster);
+      where += ` AND environment = $${params.length}`;
+    }
+    const result = await pool.query(
+      `SELECT environment AS cluster,
+              split_part(service, '/', 1) AS namespace,
+              vendor,
+              SUM(call_count)::bigint AS calls,
+              COUNT(DISTINCT service)::int AS workloads,
+              array_agg(DISTINCT host ORDER BY host) AS hosts,
+              MAX(window_end) AS last_seen
+       FROM runtime_usage
+       WHERE ${where}
+       GROUP BY environment, split_part(service, '/', 1), vendor
+       ORDER BY cluster, namespace, calls DESC`,
+      params,
+    );
+    return result.rows.map((r) => ({
+      cluster: (r.cluster as string | null) ?? null,
+      namespace: r.namespace as string,
+      vendor: (r.vendor as string | null) ?? null,
+      calls: Number(r.calls),
+      workloads: r.workloads as number,
+      hosts: (r.hosts as string[]).slice(0, 50),
+      lastSeen: r.last_seen,
+    }));
+  },
+
+  /**
+   * Call inside `transaction`: the per-org lock is held until commit, so an
+   * org's events commit in seq order and a reader paging with ?after=<seq>
+   * never passes a seq that a slower upload has yet to commit.
+   */
+  async insertVendorEvents(
+    orgId: string,
+    events: Array<{
+      repository: string;
+      vendor: string;
+      change: "added" | "removed";
+      commitSha: string | null;
+      tdmUploadId: string;
+    }>,
+    q: Queryable = pool,
+  ) {
+    if (events.length === 0) return [];
+    await q.query(`SELECT pg_advisory_xact_lock(hashtext($1))`, [orgId]);
+    const values: string[] = [];
+    const params: unknown[] = [orgId];
+    for (const e of events) {
+      params.push(e.repository, e.vendor, e.change, e.commitSha, e.tdmUploadId);
+      const n = params.length;
+      values.push(`($1, $${n - 4}, $${n - 3}, $${n - 2}, $${n - 1}, $${n})`);
+    }
+    const result = await q.query(
+      `INSERT INTO vendor_events (org_id, repository, vendor, change, commit_sha, tdm_upload_id)
+       VALUES ${values.join(", ")}
+       RETURNING seq, repository, vendor, change, commit_sha, occurred_at`,
+      params,
+    );
+    return result.rows.map(vendorEventRow);
+  },
+
+  async listVendorEvents(
+    orgId: string,
+    opts: { after: number; limit: number; repository?: string; vendor?: string },
+  ) {
+    const params: unknown[] = [orgId, opts.after];
+    let where = `org_id = $1 AND seq > $2`;
+    if (opts.repository) {
+      params.push(opts.repository);
+      where += ` AND repository = $${params.length}`;
+    }
+    if (opts.vendor) {
+      params.push(opts.vendor);
+      where += ` AND vendor = $${params.length}`;
+    }
+    params.push(opts.limit);
+    const result = await pool.query(
+      `SELECT seq, repository, vendor, change, commit_sha, occurred_at
+       FROM vendor_events WHERE ${where}
+       ORDER BY seq LIMIT $${params.length}`,
+      params,
+    );
+    return result.rows.map(vendorEventRow);
+  },
+
+  async createVendorFeedSubscription(orgId: string, url: string, secret: string | null, q: Queryable = pool) {
+    const result = await q.query(
+      `INSERT INTO vendor_feed_subscriptions (org_id, url, secret) VALUES ($1, $2, $3)
+       RETURNING id, url, created_at`,
+      [orgId, url, secret],
+    );
+    return result.rows[0];
+  },
+
+  async listVendorFeedSubscriptions(orgId: string) {
+    const result = await pool.query(
+      `SELECT id, url, secret FROM vendor_feed_subscriptions WHERE org_id = $1 ORDER BY created_at`,
+      [orgId],
+    );
+    return result.rows as Array<{ id: string; url: string; secret: string | null }>;
+  },
+
+  async deleteVendorFeedSubscription(id: string, orgId: string, q: Queryable = pool) {
+    const result = await q.query(
+      `DELETE FROM vendor_feed_subscriptions WHERE id = $1 AND org_id = $2 RETURNING id, url`,
+      [id, orgId],
+    );
+    return (result.rows[0] as { id: string; url: string } | undefined) ?? null;
+  },
+
+  async createInventoryPublisher(orgId: string, type: string, name: string | null, config: unknown, q: Queryable = pool) {
+    const result = await q.query(
+      `INSERT INTO inventory_publishers (org_id, type, name, config) VALUES ($1, $2, $3, $4)
+       RETURNING id, type, name, enabled, last_published_at, last_error, created_at`,
+      [orgId, type, name, JSON.stringify(config)],
+    );
+    return result.rows[0];
+  },
+
+  async listInventoryPublishers(orgId: string) {
+    const result = await pool.query(
+      `SELECT id, type, name, config, enabled, last_published_at, last_error, created_at
+       FROM inventory_publishers WHERE org_id = $1 ORDER BY created_at`,
+      [orgId],
+    );
+    return result.rows as Array<{
+      id: string;
+      type: "confluence" | "notion";
+      name: string | null;
+      config: Record<string, unknown>;
+      enabled: boolean;
+      last_published_at: string | null;
+      last_error: string | null;
+      created_at: string;
+    }>;
+  },
+
+  async setInventoryPublisherEnabled(id: string, orgId: string, enabled: boolean, q: Queryable = pool) {
+    const result = await q.query(
+      `UPDATE inventory_publishers SET enabled = $3 WHERE id = $1 AND org_id = $2
+       RETURNING id, type, name, enabled, last_published_at, last_error, created_at`,
+      [id, orgId, enabled],
+    );
+    return result.rows[0] ?? null;
+  },
+
+  async recordInventoryPublish(id: string, error: string | null) {
+    await pool.query(
+      `UPDATE inventory_publishers
+       SET last_error = $2, last_published_at = CASE WHEN $2::text IS NULL THEN now() ELSE last_published_at END
+       WHERE id = $1`,
+      [id, error],
+    );
+  },
+
+  async deleteInventoryPublisher(id: string, orgId: string, q: Queryable = pool) {
+    const result = await q.query(
+      `DELETE FROM inventory_publishers WHERE id = $1 AND org_id = $2 RETURNING id, type, name`,
+      [id, orgId],
+    );
+    return (result.rows[0] as { id: string; type: string; name: string | null } | undefined) ?? null;
+  },
+
+  async createBadgeToken(
+    orgId: string,
+    tokenHash: string,
+    tokenPrefix: string,
+    name: string | null,
+    repositories: string[],
+    q: Queryable = pool,
+  ) {
+    const result = await q.query(
+      `INSERT INTO badge_tokens (org_id, token_hash, token_prefix, name, repositories) VALUES ($1, $2, $3, $4, $5)
+       RETURNING id, token_prefix, name, repositories, created_at`,
+      [orgId, tokenHash, tokenPrefix, name, repositories],
+    );
+    return result.rows[0];
+  },
+
+  async listBadgeTokens(orgId: string) {
+    const result = await pool.query(
+      `SELECT id, token_prefix, name, repositories, last_used_at, created_at FROM badge_tokens
+       WHERE org_id = $1 ORDER BY created_at DESC`,
+      [orgId],
+    );
+    return result.rows;
+  },
+
+  async deleteBadgeToken(id: string, orgId: string, q: Queryable = pool) {
+    const result = await q.query(
+      `DELETE FROM badge_tokens WHERE id = $1 AND org_id = $2 RETURNING id, token_prefix, name, repositories`,
+      [id, orgId],
+    );
+    return result.rows[0] ?? null;
+  },
+
+  async getBadgeTokenByHash(tokenHash: string) {
+    const result = await pool.query(
+      `UPDATE badge_tokens SET last_used_at = now() WHERE token_hash = $1
+       RETURNING org_id, repositories`,
+      [tokenHash],
+    );
+    return (result.rows[0] as { org_id: string; repositories: string[] } | undefined) ?? null;
+  },
+
+  async listVendorApprovals(orgId: string) {
+    const result = await pool.query(
+      `SELECT vendor, status, note, decided_by, decided_by_name, decided_at FROM vendor_approvals
+       WHERE org_id = $1 ORDER BY vendor`,
+      [orgId],
+    );
+    return result.rows as Array<{
+      vendor: string;
+      status: "approved" | "denied";
+      note: string | null;
+      decided_by: string | null;
+      decided_by_name: string;
+      decided_at: string;
+    }>;
+  },
+
+  async setVendorApproval(
+    orgId: string,
+    vendor: string,
+    decision: { status: "approved" | "denied"; note: string | null; userId: string | null; name: string },
+    q: Queryable = pool,
+  ) {
+    const result = await q.query(
+      `WITH previous AS (SELECT status FROM vendor_approvals WHERE org_id = $1 AND vendor = $2)
+       INSERT INTO vendor_approvals (org_id, vendor, status, note, decided_by, decided_by_name)
+       VALUES ($1, $2, $3, $4, $5, $6)
+       ON CONFLICT (org_id, vendor) DO UPDATE
+         SET status = $3, note = $4, decided_by = $5, decided_by_name = $6, decided_at = now()
+       RETURNING vendor, status, note, decided_by, decided_by_name, decided_at,
+                 (SELECT status FROM previous) AS previous_status`,
+      [orgId, vendor, decision.status, decision.note, decision.userId, decision.name],
+    );
+    return result.rows[0];
+  },
+
+  async deleteVendorApproval(orgId: string, vendor: string, q: Queryable = pool) {
+    const result = await q.query(
+      `DELETE FROM vendor_approvals WHERE org_id = $1 AND vendor = $2 RETURNING vendor, status, note`,
+      [orgId, vendor],
+    );
+    return result.rows[0] ?? null;
+  },
+
+  async listSuppressions(orgId: string) {
+    const result = await pool.query(
+      `SELECT id, repository, dependency, change_category, min_priority, reason, expires_at,
+              created_by, created_by_name, created_at
+       FROM suppressions WHERE org_id = $1 ORDER BY created_at DESC`,
+      [orgId],
+    );
+    return result.rows;
+  },
+
+  async createSuppression(orgId: string, suppression: SuppressionFields & { userId: string | null; name: string }, q: Queryable = pool) {
+    const result = await q.query(
+      `INSERT INTO suppressions (org_id, repository, dependency, change_category, min_priority, reason, expires_at, created_by, created_by_name)
+       VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+       RETURNING id, repository, dependency, change_category, min_priority, reason, expires_at, created_by, created_by_name, created_at`,
+      [
+        orgId,
+        suppression.repository ?? "*",
+        suppression.dependency ?? null,
+        suppression.changeCategory ?? null,
+        suppression.minPriority ?? null,
+        suppression.reason,
+        suppression.expiresAt ?? null,
+        suppression.userId,
+        suppression.name,
+      ],
+    );
+    return result.rows[0];
+  },
+
+  async deleteSuppression(id: string, orgId: string, q: Queryable = pool) {
+    const result = await q.query(
+      `DELETE FROM suppressions WHERE id = $1 AND org_id = $2
+       RETURNING id, repository, dependency, change_category, min_priority, reason, expires_at`,
+      [id, orgId],
+    );
+    return result.rows[0] ?? null;
+  },
+
+  async insertAuditEntry(
+    orgId: string,
+    actor: Actor,
+    entry: { action: string; target: string; details: Record<string, unknown> },
+    q: Queryable = pool,
+  ) {
+    await q.query(
+      `INSERT INTO audit_log (org_id, actor_id, actor_name, actor_role, action, target, details)
+       VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+      [orgId, actor.userId, actor.name, actor.role, entry.action, entry.target, JSON.stringify(entry.details)],
+    );
+  },
+
+  async listAuditLog(
+    orgId: string,
+    opts: { after: number; limit: number | null; action?: string; actor?: string; target?: string; since?: Date; until?: Date },
+  ) {
+    const params: unknown[] = [orgId, opts.after];
+    let where = `org_id = $1 AND seq > $2`;
+    if (opts.action) {
+      // "vendor" matches vendor.approved, vendor.denied, ...
+      params.push(opts.action, `${opts.action}.%`);
+      where += ` AND (action = $${params.length - 1} OR action LIKE $${params.length})`;
+    }
+    if (opts.actor) {
+      params.push(opts.actor);
+      where += ` AND (actor_id::text = $${params.length} OR actor_name = $${params.length})`;
+    }
+    if (opts.target) {
+      params.push(opts.target);
+      where += ` AND target = $${params.length}`;
+    }
+    if (opts.since) {
+      params.push(opts.since);
+      where += ` AND occurred_at >= $${params.length}`;
+    }
+    if (opts.until) {
+      params.push(opts.until);
+      where += ` AND occurred_at < $${params.length}`;
+    }
+    let limit = "";
+    if (opts.limit !== null) {
+      params.push(opts.limit);
+      limit = ` LIMIT $${params.length}`;
+    }
+    const result = await pool.query(
+      `SELECT seq, actor_id, actor_name, actor_role, action, target, details, occurred_at
+       FROM audit_log WHERE ${where}
+       ORDER BY seq${limit}`,
+      params,
+    );
+    return result.rows.map(auditEntryRow);
+  },
+
+  async listTeams(orgId: string) {
+    const result = await pool.query(
+      `SELECT id, name, repositories, denied_vendors, digest_schedule, digest_emails,
+              slack_webhook_url IS NOT NULL AS slack_configured, last_digest_at, created_at
+       FROM teams WHERE org_id = $1 ORDER BY name`,
+      [orgId],
+    );
+    return result.rows;
+  },
+
+  async createTeam(orgId: string, team: TeamFields & { name: string }, q: Queryable = pool) {
+    const result = await q.query(
+      `INSERT INTO teams (org_id, name, repositories, denied_vendors, digest_schedule, digest_emails, slack_webhook_url)
+       VALUES ($1, $2, $3, $4, $5, $6, $7)
+       RETURNING id, name, repositories, denied_vendors, digest_schedule, digest_emails, created_at`,
+      [
+        orgId,
+        team.name,
+        team.repositories ?? [],
+        team.deniedVendors ?? [],
+        team.digestSchedule ?? "weekly",
+        team.digestEmails ?? [],
+        team.slackWebhookUrl ?? null,
+      ],
+    );
+    return result.rows[0];
+  },
+
+  async updateTeam(id: string, orgId: string, team: TeamFields & { name?: string }, q: Queryable = pool) {
+    const columns: Record<string, unknown> = {
+      name: team.name,
+      repositories: team.repositories,
+      denied_vendors: team.deniedVendors,
+      digest_schedule: team.digestSchedule,
+      digest_emails: team.digestEmails,
+      slack_webhook_url: team.slackWebhookUrl,
+    };
+    const sets: string[] = [];
+    const params: unknown[] = [id, orgId];
+    for (const [column, value] of Object.entries(columns)) {
+      if (value === undefined) continue;
+      params.push(value);
+      sets.push(`${column} = $${params.length}`);
+    }
+    if (sets.length === 0) return null;
+    const result = await q.query(
+      `UPDATE teams SET ${sets.join(", ")} WHERE id = $1 AND org_id = $2
+       RETURNING id, name, repositories, denied_vendors, digest_schedule, digest_emails, created_at`,
+      params,
+    );
+    return result.rows[0] ?? null;
+  },
+
+  async deleteTeam(id: string, orgId: string, q: Queryable = pool) {
+    const result = await q.query(
+      `DELETE FROM teams WHERE id = $1 AND org_id = $2 RETURNING id, name, repositories, denied_vendors`,
+      [id, orgId],
+    );
+    return result.rows[0] ?? null;
+  },
+
+  async listTeamDigests(teamId: string, orgId: string, limit: number) {
+    const result = await pool.query(
+      `SELECT d.id, d.period_start, d.period_end, d.digest, d.delivered_to, d.created_at
+       FROM team_digests d JOIN teams t ON t.id = d.team_id
+       WHERE d.team_id = $1 AND t.org_id = $2
+       ORDER BY d.period_end DESC LIMIT $3`,
+      [teamId, orgId, limit],
+    );
+    return result.rows;
+  },
+
   async exportOrgData(orgId: string) {
     const org = await pool.query(
       `SELECT id, name, github_org, plan, created_at FROM organizations WHERE id = $1`,
       [orgId],
     );
     const users = await pool.query(
-      `SELECT id, github_login, github_id, avatar_url, role, created_at FROM users WHERE org_id = $1`,
+      `SELECT id, github_login, github_id, oidc_issuer, oidc_subject, email, display_name, avatar_url, role, created_at
+       FROM users WHERE org_id = $1`,
       [orgId],
     );
     const keys = await pool.query(
-      `SELECT id, key_prefix, name, permissions, created_at FROM api_keys WHERE org_id = $1`,
+      `SELECT id, key_prefix, name, permissions, role, user_id, created_at FROM api_keys WHERE org_id = $1`,
       [orgId],
     );
     const tdms = await pool.query(
@@ -697,6 +1453,39 @@ export const db = {
       `SELECT * FROM routing_rules WHERE org_id = $1`,
       [orgId],
     );
+    const runtimeUsage = await pool.query(
+      `SELECT * FROM runtime_usage WHERE org_id = $1`,
+      [orgId],
+    );
+    const vendorEvents = await pool.query(
+      `SELECT * FROM vendor_events WHERE org_id = $1 ORDER BY seq`,
+      [orgId],
+    );
+    const teams = await pool.query(
+      `SELECT id, name, repositories, denied_vendors, digest_schedule, digest_emails, last_digest_at, created_at
+       FROM teams WHERE org_id = $1`,
+      [orgId],
+    );
+    const badgeTokens = await pool.query(
+      `SELECT id, token_prefix, name, repositories, created_at FROM badge_tokens WHERE org_id = $1`,
+      [orgId],
+    );
+    const publishers = await pool.query(
+      `SELECT id, type, name, enabled, last_published_at, created_at FROM inventory_publishers WHERE org_id = $1`,
+      [orgId],
+    );
+    const approvals = await pool.query(
+      `SELECT * FROM vendor_approvals WHERE org_id = $1`,
+      [orgId],
+    );
+    const suppressions = await pool.query(
+      `SELECT * FROM suppressions WHERE org_id = $1`,
+      [orgId],
+    );
+    const auditLog = await pool.query(
+      `SELECT * FROM audit_log WHERE org_id = $1 ORDER BY seq`,
+      [orgId],
+    );
 
     return {
       organization: org.rows[0],
@@ -707,6 +1496,14 @@ export const db = {
       changeEvents: changes.rows,
       notificationChannels: channels.rows,
       routingRules: rules.rows,
+      runtimeUsage: runtimeUsage.rows,
+      vendorEvents: vendorEvents.rows,
+      teams: teams.rows,
+      badgeTokens: badgeTokens.rows,
+      inventoryPublishers: publishers.rows,
+      vendorApprovals: approvals.rows,
+      suppressions: suppressions.rows,
+      auditLog: auditLog.rows,
     };
   },
 };
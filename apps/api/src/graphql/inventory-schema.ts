@@ -0,0 +1,312 @@
+// ---------------------------------------------------------------------------
+// Inventory schema — repositories (latest baseline scan of each), the vendors
+// they use, and detected changes, so portals can combine them freely, e.g.
+//
+//   { repositories(category: ["ai", "payments"]) { name vendors { vendor { slug } } } }
+//
+// lists every repository that uses an AI provider and a payments provider.
+// ---------------------------------------------------------------------------
+
+import { collectVendorUsages, createVendorMatcher } from "@thirdwatch/core";
+import type { SDKRegistryEntry } from "@thirdwatch/core";
+import type { TDM, TDMApi, TDMPackage } from "@thirdwatch/tdm";
+import { catalog } from "../catalog.js";
+import { db } from "../db.js";
+import { batchLoader } from "@thirdwatch/graphql";
+import type { Loader, Schema } from "@thirdwatch/graphql";
+
+interface RepositoryRecord {
+  name: string;
+  scannedAt: string;
+  scannerVersion: string;
+  languages: string[];
+  tdm: TDM;
+  /** Vendor slug → usages and hosts */
+  vendors: Map<string, { usages: number; hosts: string[] }>;
+}
+
+interface VendorRecord {
+  slug: string;
+  entry: SDKRegistryEntry | undefined;
+}
+
+export interface InventoryContext {
+  orgId: string;
+  entries: Map<string, SDKRegistryEntry>;
+  /** Loaded on first use and shared by every field in the request */
+  repositories(): Promise<RepositoryRecord[]>;
+  /** One query for every repository whose changes the request asks for with the same filters */
+  repositoryChanges(repository: string, args: { priority?: string[] | null; limit: number }): Promise<ChangeRow[]>;
+}
+
+type ChangeRow = Record<string, unknown>;
+
+const MAX_CHANGES = 200;
+
+export async function createInventoryContext(orgId: string): Promise<InventoryContext> {
+  const registry = await catalog();
+  const matchVendor = createVendorMatcher(registry);
+  let loaded: Promise<RepositoryRecord[]> | undefined;
+
+  const load = async (): Promise<RepositoryRecord[]> => {
+    const rows = await db.listLatestTDMs(orgId);
+    return rows.map((row) => {
+      // Stored by POST /api/v1/tdm after parseTDM
+      const tdm = row.tdm as TDM;
+      const vendors = new Map<string, { usages: number; hosts: string[] }>();
+      for (const usage of collectVendorUsages(tdm, matchVendor)) {
+        const v = vendors.get(usage.vendor) ?? { usages: 0, hosts: [] };
+        v.usages += usage.count;
+        if (usage.host && !v.hosts.includes(usage.host)) v.hosts.push(usage.host);
+        vendors.set(usage.vendor, v);
+      }
+      return {
+        name: row.repository,
+        scannedAt: tdm.metadata.scan_timestamp,
+        scannerVersion: tdm.metadata.scanner_version,
+        languages: tdm.metadata.languages_detected,
+        tdm,
+        vendors,
+      };
+    });
+  };
+
+  // Repository.changes under repositories { … } would otherwise query once per repository
+  const changeLoaders = new Map<string, Loader<string, ChangeRow[]>>();
+  const repositoryChanges = (repository: string, args: { priority?: string[] | null; limit: number }) => {
+    const priority = [...(args.priority ?? [])].sort();
+    const key = JSON.stringify([priority, limitArg(args.limit)]);
+    let loader = changeLoaders.get(key);
+    if (!loader) {
+      loader = batchLoader(
+        (repositories: string[]) => db.listChangeEventsByRepository(orgId, repositories, { priority, limit: args.limit }),
+        [],
+      );
+      changeLoaders.set(key, loader);
+    }
+    return loader(repository);
+  };
+
+  return {
+    orgId,
+    entries: new Map(registry.map((e) => [e.provider, e])),
+    repositories: () => (loaded ??= load()),
+    repositoryChanges,
+  };
+}
+
+function vendorRecord(slug: string, ctx: InventoryContext): VendorRecord {
+  return { slug, entry: ctx.entries.get(slug) };
+}
+
+function categoriesOf(repo: RepositoryRecord, ctx: InventoryContext): string[] {
+  const categories = new Set<string>();
+  for (const slug of repo.vendors.keys()) {
+    const category = ctx.entries.get(slug)?.category;
+    if (category) categories.add(category);
+  }
+  return [...categories].sort();
+}
+
+function limitArg(limit: number): number {
+  if (limit < 1 || limit > MAX_CHANGES) throw new Error(`limit must be between 1 and ${MAX_CHANGES}`);
+  return limit;
+}
+
+async function listChanges(
+  ctx: InventoryContext,
+  args: { priority?: string[] | null; repository?: string | null; since?: string | null; limit: number },
+) {
+  const { changes } = await db.listChangeEvents(ctx.orgId, {
+    limit: limitArg(args.limit),
+    ...(args.priority?.length ? { priority: args.priority.join(",") } : {}),
+    ...(args.repository ? { repository: args.repository } : {}),
+    ...(args.since ? { since: args.since } : {}),
+  });
+  return changes;
+}
+
+export const inventorySchema: Schema<InventoryContext> = {
+  query: "Query",
+  types: {
+    Query: {
+      fields: {
+        repositories: {
+          type: "[Repository!]!",
+          description: "Repositories using every listed vendor, category, and package",
+          args: {
+            vendor: { type: "[String!]", description: "Vendor slugs, e.g. \"openai\"" },
+            category: { type: "[String!]", description: "Vendor categories, e.g. \"ai\"" },
+            package: { type: "String", description: "Package name from a manifest" },
+          },
+          resolve: async (_, args: { vendor?: string[] | null; category?: string[] | null; package?: string | null }, ctx) => {
+            const repos = await ctx.repositories();
+            return repos
+              .filter((r) => (args.vendor ?? []).every((v) => r.vendors.has(v)))
+              .filter((r) => {
+                const categories = categoriesOf(r, ctx);
+                return (args.category ?? []).every((c) => categories.includes(c));
+              })
+              .filter((r) => !args.package || r.tdm.packages.some((p) => p.name === args.package))
+              .sort((a, b) => a.name.localeCompare(b.name));
+          },
+        },
+        repository: {
+          type: "Repository",
+          args: { name: { type: "String!" } },
+          resolve: async (_, args: { name: string }, ctx) =>
+            (await ctx.repositories()).find((r) => r.name === args.name) ?? null,
+        },
+        vendors: {
+          type: "[Vendor!]!",
+          description: "Vendors used by at least one repository, or the whole catalog with inUse: false",
+          args: {
+            category: { type: "String" },
+            inUse: { type: "Boolean", defaultValue: true },
+          },
+          resolve: async (_, args: { category?: string | null; inUse: boolean }, ctx) => {
+            const slugs = args.inUse
+              ? new Set((await ctx.repositories()).flatMap((r) => [...r.vendors.keys()]))
+              : new Set(ctx.entries.keys());
+            return [...slugs]
+              .sort()
+              .map((slug) => vendorRecord(slug, ctx))
+              .filter((v) => !args.category || v.entry?.category === args.category);
+          },
+        },
+        vendor: {
+          type: "Vendor",
+          args: { slug: { type: "String!" } },
+          resolve: async (_, args: { slug: string }, ctx) => {
+            if (ctx.entries.has(args.slug)) return vendorRecord(args.slug, ctx);
+            const used = (await ctx.repositories()).some((r) => r.vendors.has(args.slug));
+            return used ? vendorRecord(args.slug, ctx) : null;
+          },
+        },
+        changes: {
+          type: "[Change!]!",
+          description: "Detected dependency changes, newest first",
+          args: {
+            priority: { type: "[String!]", description: "P0 to P4" },
+            repository: { type: "String" },
+            since: { type: "String", description: "ISO 8601 timestamp" },
+            limit: { type: "Int", defaultValue: 50 },
+          },
+          resolve: (_, args: { priority?: string[]; repository?: string; since?: string; limit: number }, ctx) =>
+            listChanges(ctx, args),
+        },
+      },
+    },
+
+    Repository: {
+      description: "A repository, as of its latest baseline scan",
+      fields: {
+        name: { type: "String!" },
+        scannedAt: { type: "String!" },
+        scannerVersion: { type: "String!" },
+        languages: { type: "[String!]!" },
+        categories: {
+          type: "[String!]!",
+          description: "Categories of the vendors the repository uses",
+          resolve: (r: RepositoryRecord, _, ctx) => categoriesOf(r, ctx),
+        },
+        vendors: {
+          type: "[VendorUsage!]!",
+          args: { category: { type: "String" } },
+          resolve: (r: RepositoryRecord, args: { category?: string | null }, ctx) =>
+            [...r.vendors]
+              .map(([slug, v]) => ({ vendor: vendorRecord(slug, ctx), usages: v.usages, hosts: v.hosts }))
+              .filter((u) => !args.category || u.vendor.entry?.category === args.category)
+              .sort((a, b) => b.usages - a.usages || a.vendor.slug.localeCompare(b.vendor.slug)),
+        },
+        packages: {
+          type: "[Package!]!",
+          args: { ecosystem: { type: "String" }, name: { type: "String" } },
+          resolve: (r: RepositoryRecord, args: { ecosystem?: string | null; name?: string | null }) =>
+            r.tdm.packages.filter(
+              (p) => (!args.ecosystem || p.ecosystem === args.ecosystem) && (!args.name || p.name === args.name),
+            ),
+        },
+        apis: {
+          type: "[Api!]!",
+          args: { thirdPartyOnly: { type: "Boolean", defaultValue: true } },
+          resolve: (r: RepositoryRecord, args: { thirdPartyOnly: boolean }) =>
+            r.tdm.apis.filter((a) => !args.thirdPartyOnly || !a.first_party),
+        },
+        changes: {
+          type: "[Change!]!",
+          description: "Detected changes to dependencies this repository uses",
+          args: { priority: { type: "[String!]" }, limit: { type: "Int", defaultValue: 20 } },
+          resolve: (r: RepositoryRecord, args: { priority?: string[]; limit: number }, ctx) =>
+            ctx.repositoryChanges(r.name, args),
+        },
+      },
+    },
+
+    VendorUsage: {
+      fields: {
+        vendor: { type: "Vendor!" },
+        usages: { type: "Int!", description: "Code locations and runtime entries referencing the vendor" },
+        hosts: { type: "[String!]!" },
+      },
+    },
+
+    Vendor: {
+      fields: {
+        slug: { type: "String!" },
+        name: { type: "String!", resolve: (v: VendorRecord) => v.entry?.display_name ?? v.slug },
+        category: { type: "String", resolve: (v: VendorRecord) => v.entry?.category ?? null },
+        homepage: { type: "String", resolve: (v: VendorRecord) => v.entry?.homepage ?? null },
+        statusPage: { type: "String", resolve: (v: VendorRecord) => v.entry?.status_page_url ?? null },
+        uptime: {
+          type: "Float",
+          description: "Published uptime commitment, in percent",
+          resolve: (v: VendorRecord) => v.entry?.sla?.uptime ?? null,
+        },
+        repositories: {
+          type: "[Repository!]!",
+          resolve: async (v: VendorRecord, _, ctx) =>
+            (await ctx.repositories()).filter((r) => r.vendors.has(v.slug)).sort((a, b) => a.name.localeCompare(b.name)),
+        },
+      },
+    },
+
+    Package: {
+      fields: {
+        name: { type: "String!" },
+        ecosystem: { type: "String!" },
+        version: { type: "String", resolve: (p: TDMPackage) => p.current_version },
+        constraint: { type: "String", resolve: (p: TDMPackage) => p.version_constraint ?? null },
+        manifest: { type: "String!", resolve: (p: TDMPackage) => p.manifest_file },
+        usages: { type: "Int!", resolve: (p: TDMPackage) => p.usage_count },
+      },
+    },
+
+    Api: {
+      fields: {
+        url: { type: "String!" },
+        method: { type: "String" },
+        provider: { type: "String" },
+        firstParty: { type: "Boolean!", resolve: (a: TDMApi) => a.first_party ?? false },
+        usages: { type: "Int!", resolve: (a: TDMApi) => a.usage_count },
+      },
+    },
+
+    Change: {
+      fields: {
+        id: { type: "ID!" },
+        dependency: { type: "String", resolve: (c) => c.dep_identifier },
+        kind: { type: "String", resolve: (c) => c.dep_kind },
+        ecosystem: { type: "String", resolve: (c) => c.dep_ecosystem },
+        provider: { type: "String", resolve: (c) => c.dep_provider },
+        changeType: { type: "String!", resolve: (c) => c.change_type },
+        priority: { type: "String" },
+        title: { type: "String!" },
+        url: { type: "String" },
+        previousVersion: { type: "String", resolve: (c) => c.previous_version },
+        newVersion: { type: "String", resolve: (c) => c.new_version },
+        detectedAt: { type: "String!", resolve: (c) => c.detected_at },
+      },
+    },
+  },
+};
@@ -8,6 +8,15 @@ import { dependenciesRoutes } from "./routes/dependencies.js";
 import { notificationsRoutes } from "./routes/notifications.js";
 import { orgRoutes } from "./routes/org.js";
 import { billingRoutes } from "./routes/billing.js";
+import { runtimeRoutes } from "./routes/runtime.js";
+import { inventoryRoutes } from "./routes/inventory.js";
+import { vendorFeedRoutes } from "./routes/vendor-feed.js";
+import { teamsRoutes } from "./routes/teams.js";
+import { graphqlRoutes } from "./routes/graphql.js";
+import { badgesRoutes } from "./routes/badges.js";
+import { approvalsRoutes } from "./routes/approvals.js";
+import { auditLogRoutes } from "./routes/audit-log.js";
+import { publishersRoutes } from "./routes/publishers.js";
 
 const PORT = Number(process.env["PORT"] ?? "3001");
 const HOST = process.env["HOST"] ?? "0.0.0.0";
@@ -30,6 +39,15 @@ await dependenciesRoutes(app);
 await notificationsRoutes(app);
 await orgRoutes(app);
 await billingRoutes(app);
+await runtimeRoutes(app);
+await inventoryRoutes(app);
+await vendorFeedRoutes(app);
+await teamsRoutes(app);
+await graphqlRoutes(app);
+await badgesRoutes(app);
+await approvalsRoutes(app);
+await auditLogRoutes(app);
+await publishersRoutes(app);
 
 try {
   await app.listen({ port: PORT, host: HOST });
@@ -1,6 +1,7 @@
 import type { FastifyRequest, FastifyReply } from "fastify";
 import { createHash } from "node:crypto";
 import { db } from "../db.js";
+import type { Actor } from "./rbac.js";
 
 export async function authMiddleware(
   req: FastifyRequest,
@@ -24,4 +25,11 @@ export async function authMiddleware(
   (req as any).orgId = result.org_id;
   (req as any).orgPlan = result.plan;
   (req as any).keyPermissions = result.permissions;
+  // Attributed to the key's user, or to the key itself for keys without one
+  const actor: Actor = {
+    userId: result.user_id ?? null,
+    name: result.user_name ?? result.key_name ?? result.key_prefix,
+    role: result.role,
+  };
+  (req as any).actor = actor;
 }
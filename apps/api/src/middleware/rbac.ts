@@ -0,0 +1,41 @@
+import type { FastifyRequest, FastifyReply } from "fastify";
+
+/** Each role can do everything the roles before it can */
+export const ROLES = ["viewer", "approver", "admin"] as const;
+export type Role = (typeof ROLES)[number];
+
+export function isRole(value: unknown): value is Role {
+  return (ROLES as readonly unknown[]).includes(value);
+}
+
+export function hasRole(actual: Role, required: Role): boolean {
+  return ROLES.indexOf(actual) >= ROLES.indexOf(required);
+}
+
+/**
+ * Who a request acts as, for attributing approvals and suppressions. Set by
+ * authMiddleware; `userId` is null for keys created before roles existed.
+ */
+export interface Actor {
+  userId: string | null;
+  name: string;
+  role: Role;
+}
+
+export function actorOf(req: FastifyRequest): Actor {
+  return (req as any).actor as Actor;
+}
+
+/** Use after authMiddleware: 403 unless the key's role is at least `required` */
+export function requireRole(required: Role) {
+  return async (req: FastifyRequest, reply: FastifyReply): Promise<void> => {
+    const actor = actorOf(req);
+    if (!hasRole(actor.role, required)) {
+      return reply.status(403).send({
+        error: "forbidden",
+        message: `This action requires the ${required} role.`,
+        role: actor.role,
+      });
+    }
+  };
+}
@@ -0,0 +1,267 @@
+import { createPublicKey, verify as verifySignature, constants } from "node:crypto";
+import type { JsonWebKey, KeyObject } from "node:crypto";
+import { hasRole, isRole } from "./middleware/rbac.js";
+import type { Role } from "./middleware/rbac.js";
+
+/**
+ * OpenID Connect sign-in (authorization code flow with PKCE). The client
+ * redirects to the provider with a code challenge; the API exchanges the
+ * code, verifies the ID token against the provider's JWKS, and maps a
+ * groups claim to a role:
+ *
+ *   OIDC_ISSUER=https://acme.okta.com/oauth2/default
+ *   OIDC_CLIENT_ID=thirdwatch
+ *   OIDC_CLIENT_SECRET=...
+ *   OIDC_ROLE_MAP=thirdwatch-admins=admin,security=approver
+ *
+ * With OIDC_ROLE_MAP, the highest mapped role is applied on every sign-in
+ * and users matching no group get OIDC_DEFAULT_ROLE. Without it, roles are
+ * managed in thirdwatch: new users start as viewers and a returning user
+ * keeps theirs, except that users whose verified email is listed in
+ * OIDC_ADMIN_EMAILS are made admins. That is how the first admin is named;
+ * signing in first confers nothing.
+ */
+
+const OIDC_ISSUER = (process.env["OIDC_ISSUER"] ?? "").replace(/\/+$/, "");
+const OIDC_CLIENT_ID = process.env["OIDC_CLIENT_ID"] ?? "";
+const OIDC_CLIENT_SECRET = process.env["OIDC_CLIENT_SECRET"] ?? "";
+const OIDC_SCOPES = process.env["OIDC_SCOPES"] ?? "openid email profile";
+
+/** Organization OIDC users join; created on the first sign-in */
+export const OIDC_ORG = process.env["OIDC_ORG"] ?? "default";
+
+// Tolerated clock difference with the provider
+const CLOCK_SKEW_S = 60;
+// Unknown key IDs trigger a JWKS refresh at most this often
+const JWKS_REFRESH_MS = 60_000;
+
+interface Discovery {
+  issuer: string;
+  authorization_endpoint: string;
+  token_endpoint: string;
+  jwks_uri: string;
+}
+
+export interface IdTokenClaims {
+  iss: string;
+  sub: string;
+  aud: string | string[];
+  exp: number;
+  iat?: number;
+  nonce?: string;
+  azp?: string;
+  email?: string;
+  email_verified?: boolean;
+  name?: string;
+  preferred_username?: string;
+  [claim: string]: unknown;
+}
+
+export function oidcEnabled(): boolean {
+  return OIDC_ISSUER !== "" && OIDC_CLIENT_ID !== "";
+}
+
+let discovery: Promise<Discovery> | undefined;
+
+export function discover(): Promise<Discovery> {
+  discovery ??= (async () => {
+    const res = await fetch(`${OIDC_ISSUER}/.well-known/openid-configuration`, {
+      signal: AbortSignal.timeout(10_000),
+    });
+    if (!res.ok) throw new Error(`OIDC discovery failed (HTTP ${res.status})`);
+    const doc = (await res.json()) as Discovery;
+    if (doc.issuer.replace(/\/+$/, "") !== OIDC_ISSUER) {
+      throw new Error(`OIDC discovery issuer ${doc.issuer} does not match OIDC_ISSUER`);
+    }
+    return doc;
+  })().catch((err: unknown) => {
+    // Retry on the next sign-in instead of caching the failure
+    discovery = undefined;
+    throw err;
+  });
+  return discovery;
+}
+
+/** What the web app needs to start the flow */
+export async function clientConfig() {
+  const doc = await discover();
+  return {
+    issuer: doc.issuer,
+    authorization_endpoint: doc.authorization_endpoint,
+    client_id: OIDC_CLIENT_ID,
+    scopes: OIDC_SCOPES,
+  };
+}
+
+/** Redeem an authorization code; returns the raw ID token */
+export async function exchangeCode(code: string, codeVerifier: string, redirectUri: string): Promise<string> {
+  const doc = await discover();
+  const body = new URLSearchParams({
+    grant_type: "authorization_code",
+    code,
+    code_verifier: codeVerifier,
+    redirect_uri: redirectUri,
+    client_id: OIDC_CLIENT_ID,
+    ...(OIDC_CLIENT_SECRET ? { client_secret: OIDC_CLIENT_SECRET } : {}),
+  });
+  const res = await fetch(doc.token_endpoint, {
+    method: "POST",
+    headers: { "Content-Type": "application/x-www-form-urlencoded", Accept: "application/json" },
+    body,
+    signal: AbortSignal.timeout(10_000),
+  });
+  const data = (await res.json().catch(() => ({}))) as { id_token?: string; error?: string };
+  if (!res.ok || !data.id_token) throw new Error(`OIDC token exchange failed: ${data.error ?? `HTTP ${res.status}`}`);
+  return data.id_token;
+}
+
+let jwks = new Map<string, KeyObject>();
+let jwksFetchedAt = 0;
+
+async function signingKey(kid: string | undefined): Promise<KeyObject> {
+  const lookup = () => (kid ? jwks.get(kid) : jwks.size === 1 ? [...jwks.values()][0] : undefined);
+  let key = lookup();
+  if (!key && Date.now() - jwksFetchedAt > JWKS_REFRESH_MS) {
+    const res = await fetch((await discover()).jwks_uri, { signal: AbortSignal.timeout(10_000) });
+    if (!res.ok) throw new Error(`OIDC JWKS fetch failed (HTTP ${res.status})`);
+    const { keys } = (await res.json()) as { keys: Array<JsonWebKey & { kid?: string; use?: string }> };
+    const next = new Map<string, KeyObject>();
+    for (const jwk of keys) {
+      if (jwk.use && jwk.use !== "sig") continue;
+      try {
+        next.set(jwk.kid ?? "", createPublicKey({ key: jwk, format: "jwk" }));
+      } catch {
+        // Key types node can't import are never used to sign our tokens
+      }
+    }
+    jwks = next;
+    jwksFetchedAt = Date.now();
+    key = lookup();
+  }
+  if (!key) throw new Error("ID token is signed with an unknown key");
+  return key;
+}
+
+const ALGORITHMS: Record<string, { hash: string; options?: { padding?: number; saltLength?: number; dsaEncoding?: "ieee-p1363" } }> = {
+  RS256: { hash: "sha256" },
+  RS384: { hash: "sha384" },
+  RS512: { hash: "sha512" },
+  PS256: { hash: "sha256", options: { padding: constants.RSA_PKCS1_PSS_PADDING, saltLength: 32 } },
+  ES256: { hash: "sha256", options: { dsaEncoding: "ieee-p1363" } },
+  ES384: { hash: "sha384", options: { dsaEncoding: "ieee-p1363" } },
+};
+
+function decodeSegment(segment: string): Record<string, unknown> {
+  return JSON.parse(Buffer.from(segment, "base64url").toString("utf8")) as Record<string, unknown>;
+}
+
+export interface TokenExpectations {
+  issuer: string;
+  clientId: string;
+  nonce?: string;
+  /** Seconds since the epoch (default: now) */
+  now?: number;
+}
+
+/** Verify an ID token's signature, issuer, audience, expiry, and nonce */
+export function verifyIdToken(token: string, expectedNonce?: string): Promise<IdTokenClaims> {
+  return checkIdToken(token, signingKey, {
+    issuer: OIDC_ISSUER,
+    clientId: OIDC_CLIENT_ID,
+    ...(expectedNonce !== undefined ? { nonce: expectedNonce } : {}),
+  });
+}
+
+/** `verifyIdToken` with the signing keys and expectations passed in */
+export async function checkIdToken(
+  token: string,
+  keyFor: (kid: string | undefined) => Promise<KeyObject>,
+  expected: TokenExpectations,
+): Promise<IdTokenClaims> {
+  const parts = token.split(".");
+  if (parts.length !== 3) throw new Error("ID token is not a JWT");
+  const [headerPart, payloadPart, signaturePart] = parts as [string, string, string];
+  const header = decodeSegment(headerPart);
+  // "none" and HMAC algorithms are never accepted
+  const alg = ALGORITHMS[String(header["alg"])];
+  if (!alg) throw new Error(`ID token algorithm ${String(header["alg"])} is not supported`);
+
+  const key = await keyFor(typeof header["kid"] === "string" ? header["kid"] : undefined);
+  const valid = verifySignature(
+    alg.hash,
+    Buffer.from(`${headerPart}.${payloadPart}`),
+    alg.options ? { key, ...alg.options } : key,
+    Buffer.from(signaturePart, "base64url"),
+  );
+  if (!valid) throw new Error("ID token signature is invalid");
+
+  const claims = decodeSegment(payloadPart) as IdTokenClaims;
+  const now = expected.now ?? Math.floor(Date.now() / 1000);
+  if (String(claims.iss).replace(/\/+$/, "") !== expected.issuer) throw new Error("ID token issuer does not match");
+  const audiences = Array.isArray(claims.aud) ? claims.aud : [claims.aud];
+  if (!audiences.includes(expected.clientId)) throw new Error("ID token is not for this client");
+  // azp, when present, must name us even for a single audience
+  if ((audiences.length > 1 || claims.azp !== undefined) && claims.azp !== expected.clientId) {
+    throw new Error("ID token was issued to another party");
+  }
+  if (typeof claims.exp !== "number" || claims.exp + CLOCK_SKEW_S < now) throw new Error("ID token has expired");
+  if (typeof claims.sub !== "string" || !claims.sub) throw new Error("ID token has no subject");
+  if (expected.nonce !== undefined && claims.nonce !== expected.nonce) throw new Error("ID token nonce does not match");
+  return claims;
+}
+
+/** How sign-in assigns roles; read from OIDC_* variables by `rolePolicy` */
+export interface RolePolicy {
+  /** ID token claim holding groups */
+  claim: string;
+  /** Group → role pairs; empty when roles are managed in thirdwatch */
+  map: Array<[string, Role]>;
+  /** Role for users in no mapped group */
+  defaultRole: Role;
+  /** Lowercased emails made admins when `map` is empty */
+  adminEmails: string[];
+}
+
+export function rolePolicy(env: Record<string, string | undefined>): RolePolicy {
+  const defaultRole = env["OIDC_DEFAULT_ROLE"];
+  return {
+    claim: env["OIDC_ROLES_CLAIM"] ?? "groups",
+    map: (env["OIDC_ROLE_MAP"] ?? "")
+      .split(",")
+      .map((pair) => pair.split("=").map((s) => s.trim()))
+      .filter((pair): pair is [string, Role] => pair.length === 2 && pair[0] !== "" && isRole(pair[1])),
+    defaultRole: isRole(defaultRole) ? defaultRole : "viewer",
+    adminEmails: (env["OIDC_ADMIN_EMAILS"] ?? "")
+      .split(",")
+      .map((e) => e.trim().toLowerCase())
+      .filter(Boolean),
+  };
+}
+
+const ROLE_POLICY = rolePolicy(process.env);
+
+/** The role the claims map to; null when roles are managed in thirdwatch */
+export function roleFromClaims(claims: IdTokenClaims, policy: RolePolicy = ROLE_POLICY): Role | null {
+  if (policy.map.length === 0) return null;
+  const raw = claims[policy.claim];
+  const groups = new Set(Array.isArray(raw) ? raw.map(String) : typeof raw === "string" ? raw.split(/[\s,]+/) : []);
+  let role: Role | null = null;
+  for (const [group, mapped] of policy.map) {
+    if (groups.has(group) && (!role || hasRole(mapped, role))) role = mapped;
+  }
+  return role ?? policy.defaultRole;
+}
+
+/**
+ * The role to store on sign-in: the mapped role when OIDC_ROLE_MAP is set,
+ * admin for a verified email in OIDC_ADMIN_EMAILS, and otherwise null (a
+ * new user becomes a viewer, a returning one keeps their role).
+ */
+export function signInRole(claims: IdTokenClaims, policy: RolePolicy = ROLE_POLICY): Role | null {
+  const mapped = roleFromClaims(claims, policy);
+  if (mapped) return mapped;
+  const email = typeof claims.email === "string" ? claims.email.toLowerCase() : null;
+  // Providers that omit email_verified vouch for their addresses; one that says false doesn't
+  if (email && claims.email_verified !== false && policy.adminEmails.includes(email)) return "admin";
+  return null;
+}
@@ -0,0 +1,98 @@
+import { buildOrgReport } from "@thirdwatch/core";
+import { publishToConfluence, publishToNotion } from "@thirdwatch/notifier";
+import type { ConfluenceSettings, InventoryPage, NotionSettings } from "@thirdwatch/notifier";
+import type { TDM } from "@thirdwatch/tdm";
+import { catalog } from "./catalog.js";
+import { db } from "./db.js";
+
+type Publisher = Awaited<ReturnType<typeof db.listInventoryPublishers>>[number];
+
+/** The org's vendor inventory across every repository's latest baseline scan */
+export async function inventoryPage(orgId: string): Promise<InventoryPage> {
+  const [org, rows, approvals] = await Promise.all([
+    db.getOrg(orgId),
+    db.listLatestTDMs(orgId),
+    db.listVendorApprovals(orgId),
+  ]);
+  // Stored by POST /api/v1/tdm after parseTDM
+  const inventory = rows.map((row) => ({ service: row.repository, tdms: [row.tdm as TDM] }));
+  const report = buildOrgReport(inventory, await catalog());
+  const decisions = new Map(approvals.map((a) => [a.vendor, a.status]));
+  return {
+    organization: org?.name ?? "",
+    generated_at: report.generated_at,
+    total_repositories: report.repositories.length,
+    vendors: report.vendors.map((v) => {
+      const complianceUrl = v.compliance?.dpa_url ?? v.compliance?.trust_center_url ?? v.compliance?.privacy_policy_url;
+      return {
+        vendor: v.vendor,
+        display_name: v.display_name,
+        ...(v.category ? { category: v.category } : {}),
+        repositories: Object.keys(v.usages).sort(),
+        total_usages: v.total_usages,
+        ...(v.data_classification ? { data_classification: v.data_classification } : {}),
+        approval: decisions.get(v.vendor) ?? "unreviewed",
+        ...(complianceUrl ? { compliance_url: complianceUrl } : {}),
+      };
+    }),
+  };
+}
+
+async function publishOne(publisher: Publisher, page: InventoryPage): Promise<void> {
+  if (publisher.type === "confluence") {
+    await publishToConfluence(publisher.config as unknown as ConfluenceSettings, page);
+  } else {
+    await publishToNotion(publisher.config as unknown as NotionSettings, page);
+  }
+}
+
+// One publish per org at a time, so back-to-back uploads can't race to
+// create the same Notion rows; a publish requested mid-run runs once after it
+const running = new Map<string, Promise<void>>();
+const queued = new Map<string, Promise<void>>();
+
+/**
+ * Render the inventory to each of the org's enabled publishers, recording
+ * the outcome on each. Best effort, like vendor feed deliveries.
+ */
+export function publishInventory(
+  orgId: string,
+  onError: (err: unknown, publisherId: string) => void,
+  only?: string,
+): Promise<void> {
+  const run = async () => {
+    const publishers = (await db.listInventoryPublishers(orgId)).filter((p) => (only ? p.id === only : p.enabled));
+    if (publishers.length === 0) return;
+    const page = await inventoryPage(orgId);
+    for (const publisher of publishers) {
+      try {
+        await publishOne(publisher, page);
+        await db.recordInventoryPublish(publisher.id, null);
+      } catch (err) {
+        onError(err, publisher.id);
+        await db.recordInventoryPublish(publisher.id, err instanceof Error ? err.message : String(err));
+      }
+    }
+  };
+
+  // Manual runs of one publisher don't coalesce with scan-triggered ones
+  if (only) return (running.get(orgId) ?? Promise.resolve()).catch(() => {}).then(run);
+
+  const current = running.get(orgId);
+  if (!current) {
+    const started = run().finally(() => running.delete(orgId));
+    running.set(orgId, started);
+    return started;
+  }
+  let next = queued.get(orgId);
+  if (!next) {
+    next = current
+      .catch(() => {})
+      .then(() => {
+        queued.delete(orgId);
+        return publishInventory(orgId, onError);
+      });
+    queued.set(orgId, next);
+  }
+  return next;
+}
@@ -0,0 +1,191 @@
+import type { FastifyInstance } from "fastify";
+import { buildOpaBundle, buildVendorPolicy, renderTerraformVars } from "@thirdwatch/core";
+import { authMiddleware } from "../middleware/auth.js";
+import { actorOf, requireRole } from "../middleware/rbac.js";
+import { audited } from "../audit.js";
+import { catalog } from "../catalog.js";
+import { db } from "../db.js";
+import type { SuppressionFields } from "../db.js";
+
+const STATUSES = ["approved", "denied"] as const;
+const CATEGORIES = ["breaking", "deprecation", "major-update", "minor-update", "minor", "patch", "security", "informational"];
+const PRIORITIES = ["P0", "P1", "P2", "P3", "P4"];
+const VENDOR_RE = /^[a-z0-9][a-z0-9._-]{0,99}$/;
+
+interface SuppressionBody {
+  repository?: unknown;
+  dependency?: unknown;
+  changeCategory?: unknown;
+  minPriority?: unknown;
+  reason?: unknown;
+  expiresAt?: unknown;
+}
+
+function optionalPattern(value: unknown): value is string | undefined {
+  return value === undefined || (typeof value === "string" && value.length > 0 && value.length <= 253);
+}
+
+/** Validate a create body; returns an error message or the fields */
+function parseSuppression(body: SuppressionBody): string | SuppressionFields {
+  if (typeof body.reason !== "string" || !body.reason.trim()) return "reason is required";
+  if (!optionalPattern(body.repository)) return "repository must be a repository pattern";
+  if (!optionalPattern(body.dependency)) return "dependency must be a dependency pattern";
+  if (body.changeCategory !== undefined && !CATEGORIES.includes(body.changeCategory as string)) {
+    return `changeCategory must be one of ${CATEGORIES.join(", ")}`;
+  }
+  if (body.minPriority !== undefined && !PRIORITIES.includes(body.minPriority as string)) {
+    return `minPriority must be one of ${PRIORITIES.join(", ")}`;
+  }
+  if (body.dependency === undefined && body.changeCategory === undefined && body.minPriority === undefined) {
+    return "set at least one of dependency, changeCategory, or minPriority";
+  }
+  let expiresAt: Date | undefined;
+  if (body.expiresAt !== undefined) {
+    expiresAt = new Date(body.expiresAt as string);
+    if (typeof body.expiresAt !== "string" || Number.isNaN(expiresAt.getTime())) {
+      return "expiresAt must be an ISO 8601 timestamp";
+    }
+    if (expiresAt.getTime() <= Date.now()) return "expiresAt must be in the future";
+  }
+  return {
+    reason: body.reason.trim(),
+    ...(body.repository !== undefined ? { repository: body.repository } : {}),
+    ...(body.dependency !== undefined ? { dependency: body.dependency } : {}),
+    ...(body.changeCategory !== undefined ? { changeCategory: body.changeCategory as string } : {}),
+    ...(body.minPriority !== undefined ? { minPriority: body.minPriority as string } : {}),
+    ...(expiresAt ? { expiresAt: expiresAt.toISOString() } : {}),
+  };
+}
+
+/** What a suppression matched, for its audit entries */
+function suppressionDetails(row: Record<string, unknown>) {
+  const { repository, dependency, change_category, min_priority, reason, expires_at } = row;
+  return { repository, dependency, change_category, min_priority, reason, expires_at };
+}
+
+export async function approvalsRoutes(app: FastifyInstance): Promise<void> {
+  app.get(
+    "/api/v1/vendors/approvals",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const approvals = await db.listVendorApprovals(orgId);
+      return reply.send({ approvals });
+    },
+  );
+
+  // Egress policy for the approved vendors, for OPA or Terraform to enforce
+  app.get<{ Querystring: { format?: string } }>(
+    "/api/v1/vendors/approvals/policy",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const format = req.query.format ?? "json";
+      if (format !== "opa" && format !== "terraform" && format !== "json") {
+        return reply.status(400).send({ error: "format must be opa, terraform, or json" });
+      }
+      const approved = (await db.listVendorApprovals(orgId)).filter((a) => a.status === "approved").map((a) => a.vendor);
+      const policy = buildVendorPolicy(approved, await catalog());
+      if (format === "opa") {
+        reply.header("Content-Disposition", 'attachment; filename="thirdwatch-policy.tar.gz"');
+        return reply.type("application/gzip").send(buildOpaBundle(policy));
+      }
+      if (format === "terraform") {
+        reply.header("Content-Disposition", 'attachment; filename="thirdwatch-vendors.auto.tfvars"');
+        return reply.type("text/plain; charset=utf-8").send(renderTerraformVars(policy));
+      }
+      return reply.send(policy);
+    },
+  );
+
+  app.put<{ Params: { vendor: string }; Body: { status?: unknown; note?: unknown } }>(
+    "/api/v1/vendors/:vendor/approval",
+    { preHandler: [authMiddleware, requireRole("approver")] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const vendor = req.params.vendor.toLowerCase();
+      if (!VENDOR_RE.test(vendor)) return reply.status(400).send({ error: "vendor must be a vendor slug" });
+      const { status, note } = req.body ?? {};
+      if (!(STATUSES as readonly unknown[]).includes(status)) {
+        return reply.status(400).send({ error: `status must be one of ${STATUSES.join(", ")}` });
+      }
+      if (note !== undefined && note !== null && typeof note !== "string") {
+        return reply.status(400).send({ error: "note must be a string" });
+      }
+      const actor = actorOf(req);
+      const approval = await audited(
+        req,
+        (client) =>
+          db.setVendorApproval(
+            orgId,
+            vendor,
+            { status: status as (typeof STATUSES)[number], note: note ?? null, userId: actor.userId, name: actor.name },
+            client,
+          ),
+        (a) => ({
+          action: a.status === "approved" ? "vendor.approved" : "vendor.denied",
+          target: vendor,
+          details: { note: a.note, previous_status: a.previous_status },
+        }),
+      );
+      return reply.send(approval);
+    },
+  );
+
+  app.delete<{ Params: { vendor: string } }>(
+    "/api/v1/vendors/:vendor/approval",
+    { preHandler: [authMiddleware, requireRole("approver")] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const deleted = await audited(
+        req,
+        (client) => db.deleteVendorApproval(orgId, req.params.vendor.toLowerCase(), client),
+        (d) => ({ action: "vendor.approval_removed", target: d.vendor, details: { status: d.status, note: d.note } }),
+      );
+      if (!deleted) return reply.status(404).send({ error: "not_found" });
+      return reply.status(204).send();
+    },
+  );
+
+  app.get(
+    "/api/v1/suppressions",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const suppressions = await db.listSuppressions(orgId);
+      return reply.send({ suppressions });
+    },
+  );
+
+  app.post<{ Body: SuppressionBody }>(
+    "/api/v1/suppressions",
+    { preHandler: [authMiddleware, requireRole("approver")] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const suppression = parseSuppression(req.body ?? {});
+      if (typeof suppression === "string") return reply.status(400).send({ error: suppression });
+      const actor = actorOf(req);
+      const created = await audited(
+        req,
+        (client) => db.createSuppression(orgId, { ...suppression, userId: actor.userId, name: actor.name }, client),
+        (c) => ({ action: "suppression.created", target: c.id, details: suppressionDetails(c) }),
+      );
+      return reply.status(201).send(created);
+    },
+  );
+
+  app.delete<{ Params: { id: string } }>(
+    "/api/v1/suppressions/:id",
+    { preHandler: [authMiddleware, requireRole("approver")] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const deleted = await audited(
+        req,
+        (client) => db.deleteSuppression(req.params.id, orgId, client),
+        (d) => ({ action: "suppression.deleted", target: d.id, details: suppressionDetails(d) }),
+      );
+      if (!deleted) return reply.status(404).send({ error: "not_found" });
+      return reply.status(204).send();
+    },
+  );
+}
@@ -0,0 +1,81 @@
+import type { FastifyInstance } from "fastify";
+import { authMiddleware } from "../middleware/auth.js";
+import { auditCsv } from "../audit.js";
+import { db } from "../db.js";
+
+const MAX_PAGE = 500;
+
+interface AuditQuery {
+  after?: string;
+  limit?: string;
+  action?: string;
+  actor?: string;
+  target?: string;
+  since?: string;
+  until?: string;
+  format?: string;
+}
+
+/** Shared filters; returns an error message or the filter options */
+function parseFilters(query: AuditQuery) {
+  const filters: { action?: string; actor?: string; target?: string; since?: Date; until?: Date } = {};
+  if (query.action) filters.action = query.action;
+  if (query.actor) filters.actor = query.actor;
+  if (query.target) filters.target = query.target;
+  for (const key of ["since", "until"] as const) {
+    const value = query[key];
+    if (value === undefined) continue;
+    const date = new Date(value);
+    if (Number.isNaN(date.getTime())) return `${key} must be an ISO 8601 timestamp`;
+    filters[key] = date;
+  }
+  return filters;
+}
+
+export async function auditLogRoutes(app: FastifyInstance): Promise<void> {
+  // Every approval, suppression, and policy change, oldest first.
+  // Page with ?after=<next_cursor> like the vendor feed.
+  app.get<{ Querystring: AuditQuery }>(
+    "/api/v1/audit-log",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const after = Number(req.query.after ?? "0");
+      const limit = Number(req.query.limit ?? "100");
+      if (!Number.isInteger(after) || after < 0) {
+        return reply.status(400).send({ error: "after must be a non-negative integer cursor" });
+      }
+      if (!Number.isInteger(limit) || limit < 1 || limit > MAX_PAGE) {
+        return reply.status(400).send({ error: `limit must be between 1 and ${MAX_PAGE}` });
+      }
+      const filters = parseFilters(req.query);
+      if (typeof filters === "string") return reply.status(400).send({ error: filters });
+      const entries = await db.listAuditLog(orgId, { after, limit, ...filters });
+      return reply.send({
+        entries,
+        next_cursor: entries.length > 0 ? entries[entries.length - 1]!.seq : after,
+      });
+    },
+  );
+
+  // The whole (filtered) log as a download for compliance reviews
+  app.get<{ Querystring: AuditQuery }>(
+    "/api/v1/audit-log/export",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const format = req.query.format ?? "csv";
+      if (format !== "csv" && format !== "json") {
+        return reply.status(400).send({ error: "format must be csv or json" });
+      }
+      const filters = parseFilters(req.query);
+      if (typeof filters === "string") return reply.status(400).send({ error: filters });
+      const entries = await db.listAuditLog(orgId, { after: 0, limit: null, ...filters });
+
+      const filename = `thirdwatch-audit-log-${new Date().toISOString().slice(0, 10)}.${format}`;
+      reply.header("Content-Disposition", `attachment; filename="${filename}"`);
+      if (format === "json") return reply.send({ exported_at: new Date().toISOString(), entries });
+      return reply.type("text/csv; charset=utf-8").send(auditCsv(entries));
+    },
+  );
+}
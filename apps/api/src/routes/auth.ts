@@ -1,6 +1,8 @@
 import type { FastifyInstance } from "fastify";
 import { createHash, randomBytes } from "node:crypto";
 import { db } from "../db.js";
+import type { Role } from "../middleware/rbac.js";
+import { OIDC_ORG, clientConfig, exchangeCode, oidcEnabled, signInRole, verifyIdToken } from "../oidc.js";
 
 const GITHUB_CLIENT_ID = process.env["GITHUB_CLIENT_ID"] ?? "";
 const GITHUB_CLIENT_SECRET = process.env["GITHUB_CLIENT_SECRET"] ?? "";
@@ -61,16 +63,7 @@ export async function authRoutes(app: FastifyInstance): Promise<void> {
         );
       }
 
-      const rawKey = `tw_live_${randomBytes(24).toString("hex")}`;
-      const keyHash = createHash("sha256").update(rawKey).digest("hex");
-      const keyPrefix = rawKey.slice(0, 15) + "...";
-      await db.createApiKey(
-        user.org_id,
-        keyHash,
-        keyPrefix,
-        `oauth-${githubUser.login}`,
-      );
-
+      const rawKey = await issueSessionKey(user, `oauth-${githubUser.login}`);
       const org = await db.getOrg(user.org_id);
 
       return reply.send({
@@ -80,8 +73,70 @@ export async function authRoutes(app: FastifyInstance): Promise<void> {
           id: user.id,
           login: githubUser.login,
           avatar_url: githubUser.avatar_url,
+          role: user.role,
         },
       });
     },
   );
+
+  // Where the web app sends users to sign in; 404 when OIDC isn't configured
+  app.get("/api/v1/auth/oidc/config", async (_req, reply) => {
+    if (!oidcEnabled()) return reply.status(404).send({ error: "oidc_not_configured" });
+    try {
+      return reply.send(await clientConfig());
+    } catch (err) {
+      app.log.error(err);
+      return reply.status(502).send({ error: "oidc_unavailable" });
+    }
+  });
+
+  app.post<{ Body: { code?: string; codeVerifier?: string; redirectUri?: string; nonce?: string } }>(
+    "/api/v1/auth/oidc",
+    async (req, reply) => {
+      if (!oidcEnabled()) return reply.status(404).send({ error: "oidc_not_configured" });
+      const { code, codeVerifier, redirectUri, nonce } = req.body ?? {};
+      if (!code || !codeVerifier || !redirectUri || !nonce) {
+        return reply.status(400).send({ error: "code, codeVerifier, redirectUri, and nonce are required" });
+      }
+
+      let claims;
+      try {
+        claims = await verifyIdToken(await exchangeCode(code, codeVerifier, redirectUri), nonce);
+      } catch (err) {
+        req.log.warn({ err }, "OIDC sign-in rejected");
+        return reply.status(401).send({ error: "oidc_auth_failed" });
+      }
+
+      // Everyone signing in through the provider joins one organization.
+      // Admins come from OIDC_ROLE_MAP or OIDC_ADMIN_EMAILS, never from
+      // being first to sign in.
+      const { org, created } = await db.getOrCreateOidcOrg(OIDC_ORG);
+      const user = await db.upsertOidcUser(org.id, {
+        issuer: claims.iss,
+        subject: claims.sub,
+        email: typeof claims.email === "string" ? claims.email : null,
+        name: (claims.name ?? claims.preferred_username ?? null) as string | null,
+        role: signInRole(claims),
+      });
+      if (created && user.role !== "admin") {
+        req.log.warn(`Created organization "${OIDC_ORG}" without an admin; set OIDC_ADMIN_EMAILS or OIDC_ROLE_MAP to name one`);
+      }
+
+      const rawKey = await issueSessionKey(user, `oidc-${user.email ?? claims.sub}`);
+      return reply.send({
+        token: rawKey,
+        org: { id: org.id, name: org.name, plan: org.plan },
+        user: { id: user.id, email: user.email, name: user.display_name, role: user.role },
+      });
+    },
+  );
+}
+
+/** A key acting as `user`, returned to the web app after sign-in */
+async function issueSessionKey(user: { id: string; org_id: string; role: Role }, name: string): Promise<string> {
+  const rawKey = `tw_live_${randomBytes(24).toString("hex")}`;
+  const keyHash = createHash("sha256").update(rawKey).digest("hex");
+  const keyPrefix = rawKey.slice(0, 15) + "...";
+  await db.createApiKey(user.org_id, keyHash, keyPrefix, name, { userId: user.id, role: user.role });
+  return rawKey;
 }
@@ -0,0 +1,121 @@
+import type { FastifyInstance } from "fastify";
+import { createHash, randomBytes } from "node:crypto";
+import { badgeFor, renderBadge, summarizeRepository } from "@thirdwatch/core";
+import type { BadgeMetric } from "@thirdwatch/core";
+import { matchesRepository } from "@thirdwatch/notifier";
+import type { TDM } from "@thirdwatch/tdm";
+import { authMiddleware } from "../middleware/auth.js";
+import { requireRole } from "../middleware/rbac.js";
+import { catalog } from "../catalog.js";
+import { audited } from "../audit.js";
+import { db } from "../db.js";
+
+const METRICS: BadgeMetric[] = ["vendors", "policy"];
+
+// Badges are fetched by every README view; let image proxies cache them briefly
+const CACHE_CONTROL = "public, max-age=300";
+
+interface BadgeTokenBody {
+  name?: unknown;
+  repositories?: unknown;
+}
+
+export async function badgesRoutes(app: FastifyInstance): Promise<void> {
+  app.get(
+    "/api/v1/org/badge-tokens",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const tokens = await db.listBadgeTokens(orgId);
+      return reply.send({ tokens });
+    },
+  );
+
+  app.post<{ Body: BadgeTokenBody }>(
+    "/api/v1/org/badge-tokens",
+    { preHandler: [authMiddleware, requireRole("admin")] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const { name, repositories = ["*"] } = req.body ?? {};
+      if (name !== undefined && typeof name !== "string") {
+        return reply.status(400).send({ error: "name must be a string" });
+      }
+      if (
+        !Array.isArray(repositories) ||
+        repositories.length === 0 ||
+        !repositories.every((r) => typeof r === "string" && r.length > 0 && r.length <= 253)
+      ) {
+        return reply.status(400).send({ error: "repositories must be a non-empty array of repository patterns" });
+      }
+      const rawToken = `tw_badge_${randomBytes(24).toString("hex")}`;
+      const tokenHash = createHash("sha256").update(rawToken).digest("hex");
+      const token = await audited(
+        req,
+        (client) =>
+          db.createBadgeToken(orgId, tokenHash, rawToken.slice(0, 16) + "...", name ?? null, repositories as string[], client),
+        (t) => ({ action: "badge_token.created", target: t.id, details: { name: t.name, repositories: t.repositories } }),
+      );
+      return reply.status(201).send({ ...token, token: rawToken });
+    },
+  );
+
+  app.delete<{ Params: { id: string } }>(
+    "/api/v1/org/badge-tokens/:id",
+    { preHandler: [authMiddleware, requireRole("admin")] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      await audited(
+        req,
+        (client) => db.deleteBadgeToken(req.params.id, orgId, client),
+        (d) => ({ action: "badge_token.deleted", target: d.id, details: { name: d.name, repositories: d.repositories } }),
+      );
+      return reply.status(204).send();
+    },
+  );
+
+  // GET /api/v1/badges/github.com/acme/checkout.svg?token=...&metric=policy
+  // GET /api/v1/badges/github.com/acme/checkout.json?token=...
+  // The token goes in the query string because README images can't send headers
+  app.get<{ Params: { "*": string }; Querystring: { token?: string; metric?: string } }>(
+    "/api/v1/badges/*",
+    async (req, reply) => {
+      const match = req.params["*"].match(/^(.+)\.(svg|json)$/);
+      if (!match) return reply.status(404).send({ error: "not_found", message: "Use <repository>.svg or <repository>.json" });
+      const [, repository, format] = match as [string, string, "svg" | "json"];
+      const metric = (req.query.metric ?? "policy") as BadgeMetric;
+      if (!METRICS.includes(metric)) {
+        return reply.status(400).send({ error: `metric must be one of ${METRICS.join(", ")}` });
+      }
+
+      const token = req.query.token;
+      const scope = token
+        ? await db.getBadgeTokenByHash(createHash("sha256").update(token).digest("hex"))
+        : null;
+      if (!scope) return reply.status(401).send({ error: "unauthorized", message: "Missing or invalid badge token" });
+
+      // Repositories outside the token's scope look the same as unscanned ones
+      const row = matchesRepository(repository, scope.repositories)
+        ? await db.getLatestTDM(scope.org_id, repository)
+        : null;
+      let summary = null;
+      if (row) {
+        const teams = (await db.listTeams(scope.org_id)) as Array<{ repositories: string[]; denied_vendors: string[] }>;
+        const deniedVendors = teams
+          .filter((t) => matchesRepository(repository, t.repositories))
+          .flatMap((t) => t.denied_vendors);
+        // Stored by POST /api/v1/tdm after parseTDM
+        summary = summarizeRepository(repository, row.tdm as TDM, await catalog(), { deniedVendors });
+      }
+
+      reply.header("Cache-Control", CACHE_CONTROL);
+      if (format === "json") {
+        if (!summary) return reply.status(404).send({ error: "not_found" });
+        return reply.send(summary);
+      }
+      return reply
+        .status(summary ? 200 : 404)
+        .type("image/svg+xml; charset=utf-8")
+        .send(renderBadge(badgeFor(summary, metric)));
+    },
+  );
+}
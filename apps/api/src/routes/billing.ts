@@ -1,5 +1,6 @@
 import type { FastifyInstance } from "fastify";
 import { authMiddleware } from "../middleware/auth.js";
+import { requireRole } from "../middleware/rbac.js";
 import {
   createCheckoutSession,
   handleStripeWebhook,
@@ -11,7 +12,7 @@ const STRIPE_WEBHOOK_SECRET = process.env["STRIPE_WEBHOOK_SECRET"] ?? "";
 export async function billingRoutes(app: FastifyInstance): Promise<void> {
   app.post<{ Body: { plan: "team" | "enterprise" } }>(
     "/api/v1/billing/checkout",
-    { preHandler: authMiddleware },
+    { preHandler: [authMiddleware, requireRole("admin")] },
     async (req, reply) => {
       const orgId = (req as any).orgId as string;
       const { plan } = req.body;
@@ -0,0 +1,46 @@
+import type { FastifyInstance } from "fastify";
+import { execute, printSchema } from "@thirdwatch/graphql";
+import { authMiddleware } from "../middleware/auth.js";
+import { createInventoryContext, inventorySchema } from "../graphql/inventory-schema.js";
+
+const SDL = printSchema(inventorySchema);
+
+interface GraphQLBody {
+  query?: unknown;
+  variables?: unknown;
+  operationName?: unknown;
+}
+
+export async function graphqlRoutes(app: FastifyInstance): Promise<void> {
+  // Read-only queries over the inventory; errors are reported in the body
+  // with HTTP 200, as GraphQL clients expect
+  app.post<{ Body: GraphQLBody }>(
+    "/api/v1/graphql",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const { query, variables, operationName } = req.body ?? {};
+      if (typeof query !== "string" || !query.trim()) {
+        return reply.status(400).send({ errors: [{ message: "Body must include a query string" }] });
+      }
+      if (variables != null && (typeof variables !== "object" || Array.isArray(variables))) {
+        return reply.status(400).send({ errors: [{ message: "variables must be an object" }] });
+      }
+      if (operationName != null && typeof operationName !== "string") {
+        return reply.status(400).send({ errors: [{ message: "operationName must be a string" }] });
+      }
+      const result = await execute(
+        inventorySchema,
+        { query, variables: variables as Record<string, unknown> | null | undefined, operationName },
+        await createInventoryContext(orgId),
+      );
+      return reply.send(result);
+    },
+  );
+
+  app.get(
+    "/api/v1/graphql/schema",
+    { preHandler: authMiddleware },
+    async (_req, reply) => reply.type("text/plain; charset=utf-8").send(SDL),
+  );
+}
@@ -0,0 +1,27 @@
+import type { FastifyInstance } from "fastify";
+import { computeConcentration } from "@thirdwatch/core";
+import type { TDM } from "@thirdwatch/tdm";
+import { authMiddleware } from "../middleware/auth.js";
+import { catalog } from "../catalog.js";
+import { db } from "../db.js";
+
+export async function inventoryRoutes(app: FastifyInstance): Promise<void> {
+  // Vendor concentration across every repository's latest baseline scan
+  app.get<{ Querystring: { critical?: string } }>(
+    "/api/v1/inventory/concentration",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const critical = (req.query.critical ?? "").split(",").map((s) => s.trim()).filter(Boolean);
+
+      const rows = await db.listLatestTDMs(orgId);
+      const inventory = rows.map((row) => ({
+        service: row.repository,
+        // Stored by POST /api/v1/tdm after parseTDM
+        tdms: [row.tdm as TDM],
+      }));
+      const report = computeConcentration(inventory, await catalog(), { critical });
+      return reply.send(report);
+    },
+  );
+}
@@ -1,7 +1,19 @@
 import type { FastifyInstance } from "fastify";
 import { authMiddleware } from "../middleware/auth.js";
+import { requireRole } from "../middleware/rbac.js";
 import { tierGuard } from "../middleware/tier-guard.js";
 import { db } from "../db.js";
+import { audited } from "../audit.js";
+
+function routingRuleDetails(rule: Record<string, unknown>) {
+  return {
+    channel_id: rule.channel_id,
+    priority: rule.priority,
+    change_category: rule.change_category,
+    repositories: rule.repositories,
+    schedule: rule.schedule,
+  };
+}
 
 export async function notificationsRoutes(
   app: FastifyInstance,
@@ -18,7 +30,7 @@ export async function notificationsRoutes(
 
   app.post<{ Body: { type: string; name: string; config: unknown } }>(
     "/api/v1/notifications/channels",
-    { preHandler: [authMiddleware, tierGuard(["team", "enterprise"])] },
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
     async (req, reply) => {
       const orgId = (req as any).orgId as string;
       const { type, name, config } = req.body;
@@ -27,11 +39,11 @@ export async function notificationsRoutes(
           .status(400)
           .send({ error: "type and name are required" });
       }
-      const channel = await db.createNotificationChannel(
-        orgId,
-        type,
-        name,
-        config,
+      // Channel config holds webhook URLs and tokens, so it stays out of the log
+      const channel = await audited(
+        req,
+        (client) => db.createNotificationChannel(orgId, type, name, config, client),
+        (c) => ({ action: "notification_channel.created", target: c.id, details: { type: c.type, name: c.name } }),
       );
       return reply.status(201).send(channel);
     },
@@ -42,13 +54,21 @@ export async function notificationsRoutes(
     Body: { name?: string; config?: unknown; enabled?: boolean };
   }>(
     "/api/v1/notifications/channels/:id",
-    { preHandler: [authMiddleware, tierGuard(["team", "enterprise"])] },
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
     async (req, reply) => {
       const orgId = (req as any).orgId as string;
-      const updated = await db.updateNotificationChannel(
-        req.params.id,
-        orgId,
-        req.body,
+      const updated = await audited(
+        req,
+        (client) => db.updateNotificationChannel(req.params.id, orgId, req.body, client),
+        (u) => ({
+          action: "notification_channel.updated",
+          target: u.id,
+          details: {
+            name: u.name,
+            enabled: u.enabled,
+            ...(req.body.config !== undefined ? { config_changed: true } : {}),
+          },
+        }),
       );
       if (!updated) return reply.status(404).send({ error: "not_found" });
       return reply.send(updated);
@@ -57,10 +77,14 @@ export async function notificationsRoutes(
 
   app.delete<{ Params: { id: string } }>(
     "/api/v1/notifications/channels/:id",
-    { preHandler: [authMiddleware, tierGuard(["team", "enterprise"])] },
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
     async (req, reply) => {
       const orgId = (req as any).orgId as string;
-      await db.deleteNotificationChannel(req.params.id, orgId);
+      await audited(
+        req,
+        (client) => db.deleteNotificationChannel(req.params.id, orgId, client),
+        (d) => ({ action: "notification_channel.deleted", target: d.id, details: { type: d.type, name: d.name } }),
+      );
       return reply.status(204).send();
     },
   );
@@ -85,7 +109,7 @@ export async function notificationsRoutes(
     };
   }>(
     "/api/v1/notifications/routing",
-    { preHandler: [authMiddleware, tierGuard(["team", "enterprise"])] },
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
     async (req, reply) => {
       const orgId = (req as any).orgId as string;
       const { channelId, ...rule } = req.body;
@@ -94,17 +118,25 @@ export async function notificationsRoutes(
           .status(400)
           .send({ error: "channelId is required" });
       }
-      const created = await db.createRoutingRule(orgId, channelId, rule);
+      const created = await audited(
+        req,
+        (client) => db.createRoutingRule(orgId, channelId, rule, client),
+        (r) => ({ action: "routing_rule.created", target: r.id, details: routingRuleDetails(r) }),
+      );
       return reply.status(201).send(created);
     },
   );
 
   app.delete<{ Params: { id: string } }>(
     "/api/v1/notifications/routing/:id",
-    { preHandler: [authMiddleware, tierGuard(["team", "enterprise"])] },
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
     async (req, reply) => {
       const orgId = (req as any).orgId as string;
-      await db.deleteRoutingRule(req.params.id, orgId);
+      await audited(
+        req,
+        (client) => db.deleteRoutingRule(req.params.id, orgId, client),
+        (r) => ({ action: "routing_rule.deleted", target: r.id, details: routingRuleDetails(r) }),
+      );
       return reply.status(204).send();
     },
   );
@@ -1,6 +1,8 @@
 import type { FastifyInstance } from "fastify";
 import { createHash, randomBytes } from "node:crypto";
 import { authMiddleware } from "../middleware/auth.js";
+import { actorOf, hasRole, isRole, requireRole } from "../middleware/rbac.js";
+import { audited } from "../audit.js";
 import { db } from "../db.js";
 
 export async function orgRoutes(app: FastifyInstance): Promise<void> {
@@ -27,7 +29,7 @@ export async function orgRoutes(app: FastifyInstance): Promise<void> {
 
   app.get(
     "/api/v1/org/export",
-    { preHandler: authMiddleware },
+    { preHandler: [authMiddleware, requireRole("admin")] },
     async (req, reply) => {
       const orgId = (req as any).orgId as string;
       const data = await db.exportOrgData(orgId);
@@ -37,7 +39,7 @@ export async function orgRoutes(app: FastifyInstance): Promise<void> {
 
   app.delete(
     "/api/v1/org",
-    { preHandler: authMiddleware },
+    { preHandler: [authMiddleware, requireRole("admin")] },
     async (req, reply) => {
       const orgId = (req as any).orgId as string;
       await db.deleteOrg(orgId);
@@ -47,7 +49,7 @@ export async function orgRoutes(app: FastifyInstance): Promise<void> {
 
   app.get(
     "/api/v1/org/api-keys",
-    { preHandler: authMiddleware },
+    { preHandler: [authMiddleware, requireRole("admin")] },
     async (req, reply) => {
       const orgId = (req as any).orgId as string;
       const keys = await db.listApiKeys(orgId);
@@ -55,19 +57,28 @@ export async function orgRoutes(app: FastifyInstance): Promise<void> {
     },
   );
 
-  app.post<{ Body: { name?: string } }>(
+  // Keys act as their creator, with at most the creator's role (e.g. a
+  // viewer key for a dashboard, an approver key for a review bot)
+  app.post<{ Body: { name?: string; role?: string } }>(
     "/api/v1/org/api-keys",
-    { preHandler: authMiddleware },
+    { preHandler: [authMiddleware, requireRole("admin")] },
     async (req, reply) => {
       const orgId = (req as any).orgId as string;
+      const actor = actorOf(req);
+      const role = req.body?.role ?? actor.role;
+      if (!isRole(role)) {
+        return reply.status(400).send({ error: "role must be one of viewer, approver, admin" });
+      }
+      if (!hasRole(actor.role, role)) {
+        return reply.status(403).send({ error: "forbidden", message: `Cannot create a key with the ${role} role.` });
+      }
       const rawKey = `tw_live_${randomBytes(24).toString("hex")}`;
       const keyHash = createHash("sha256").update(rawKey).digest("hex");
       const keyPrefix = rawKey.slice(0, 15) + "...";
-      const key = await db.createApiKey(
-        orgId,
-        keyHash,
-        keyPrefix,
-        req.body?.name ?? null,
+      const key = await audited(
+        req,
+        (client) => db.createApiKey(orgId, keyHash, keyPrefix, req.body?.name ?? null, { userId: actor.userId, role }, client),
+        (k) => ({ action: "api_key.created", target: k.id, details: { name: k.name, key_prefix: k.key_prefix, role: k.role } }),
       );
       return reply.status(201).send({ ...key, token: rawKey });
     },
@@ -75,11 +86,52 @@ export async function orgRoutes(app: FastifyInstance): Promise<void> {
 
   app.delete<{ Params: { id: string } }>(
     "/api/v1/org/api-keys/:id",
-    { preHandler: authMiddleware },
+    { preHandler: [authMiddleware, requireRole("admin")] },
     async (req, reply) => {
       const orgId = (req as any).orgId as string;
-      await db.deleteApiKey(req.params.id, orgId);
+      await audited(
+        req,
+        (client) => db.deleteApiKey(req.params.id, orgId, client),
+        (d) => ({ action: "api_key.deleted", target: d.id, details: { name: d.name, key_prefix: d.key_prefix, role: d.role } }),
+      );
       return reply.status(204).send();
     },
   );
+
+  app.get(
+    "/api/v1/org/users",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const users = await db.listUsers(orgId);
+      return reply.send({ users });
+    },
+  );
+
+  app.patch<{ Params: { id: string }; Body: { role?: string } }>(
+    "/api/v1/org/users/:id",
+    { preHandler: [authMiddleware, requireRole("admin")] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const role = req.body?.role;
+      if (!isRole(role)) {
+        return reply.status(400).send({ error: "role must be one of viewer, approver, admin" });
+      }
+      const user = await audited(
+        req,
+        (client) => db.updateUserRole(req.params.id, orgId, role, client),
+        (u) =>
+          u.previous_role === u.role
+            ? null
+            : { action: "user.role_changed", target: u.id, details: { from: u.previous_role, to: u.role } },
+      );
+      if (!user) {
+        const exists = (await db.listUsers(orgId)).some((u: { id: string }) => u.id === req.params.id);
+        if (!exists) return reply.status(404).send({ error: "not_found" });
+        return reply.status(409).send({ error: "conflict", message: "An organization needs at least one admin." });
+      }
+      const { previous_role: _, ...updated } = user;
+      return reply.send(updated);
+    },
+  );
 }
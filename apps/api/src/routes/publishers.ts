@@ -0,0 +1,120 @@
+import type { FastifyInstance } from "fastify";
+import { authMiddleware } from "../middleware/auth.js";
+import { requireRole } from "../middleware/rbac.js";
+import { tierGuard } from "../middleware/tier-guard.js";
+import { publishInventory } from "../publishers.js";
+import { db } from "../db.js";
+import { audited } from "../audit.js";
+
+interface PublisherBody {
+  type?: unknown;
+  name?: unknown;
+  config?: Record<string, unknown>;
+}
+
+const isString = (v: unknown): v is string => typeof v === "string" && v.length > 0 && v.length <= 2048;
+
+/** Validate a publisher's target and credentials; returns an error message or the config to store */
+function parseConfig(type: unknown, config: Record<string, unknown> = {}): string | Record<string, string> {
+  if (type === "confluence") {
+    const { baseUrl, pageId, email, apiToken, title } = config;
+    if (!isString(baseUrl) || !/^https?:\/\//.test(baseUrl)) return "config.baseUrl must be the Confluence site URL";
+    if (!isString(pageId) || !/^\d+$/.test(pageId)) return "config.pageId must be a numeric page ID";
+    if (!isString(apiToken)) return "config.apiToken is required";
+    if (email !== undefined && !isString(email)) return "config.email must be a string";
+    if (title !== undefined && !isString(title)) return "config.title must be a string";
+    return { baseUrl, pageId, apiToken, ...(email ? { email } : {}), ...(title ? { title } : {}) };
+  }
+  if (type === "notion") {
+    const { token, databaseId } = config;
+    if (!isString(token)) return "config.token is required";
+    // Accept the database URL as well as its ID
+    const id = isString(databaseId) ? databaseId.split("?")[0]!.replace(/-/g, "").match(/[0-9a-f]{32}$/i)?.[0] : undefined;
+    if (!id) return "config.databaseId must be a Notion database ID or URL";
+    return { token, databaseId: id };
+  }
+  return "type must be confluence or notion";
+}
+
+export async function publishersRoutes(app: FastifyInstance): Promise<void> {
+  app.get(
+    "/api/v1/publishers",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const publishers = await db.listInventoryPublishers(orgId);
+      // Credentials are write-only
+      return reply.send({ publishers: publishers.map(({ config: _, ...publisher }) => publisher) });
+    },
+  );
+
+  app.post<{ Body: PublisherBody }>(
+    "/api/v1/publishers",
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const { type, name, config } = req.body ?? {};
+      if (name !== undefined && typeof name !== "string") {
+        return reply.status(400).send({ error: "name must be a string" });
+      }
+      const parsed = parseConfig(type, config);
+      if (typeof parsed === "string") return reply.status(400).send({ error: parsed });
+      // Credentials are write-only, in the log too
+      const publisher = await audited(
+        req,
+        (client) => db.createInventoryPublisher(orgId, type as string, name ?? null, parsed, client),
+        (p) => ({ action: "publisher.created", target: p.id, details: { type: p.type, name: p.name } }),
+      );
+      return reply.status(201).send(publisher);
+    },
+  );
+
+  app.patch<{ Params: { id: string }; Body: { enabled?: unknown } }>(
+    "/api/v1/publishers/:id",
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      if (typeof req.body?.enabled !== "boolean") {
+        return reply.status(400).send({ error: "enabled must be a boolean" });
+      }
+      const enabled = req.body.enabled;
+      const publisher = await audited(
+        req,
+        (client) => db.setInventoryPublisherEnabled(req.params.id, orgId, enabled, client),
+        (p) => ({ action: "publisher.updated", target: p.id, details: { type: p.type, name: p.name, enabled: p.enabled } }),
+      );
+      if (!publisher) return reply.status(404).send({ error: "not_found" });
+      return reply.send(publisher);
+    },
+  );
+
+  app.delete<{ Params: { id: string } }>(
+    "/api/v1/publishers/:id",
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      await audited(
+        req,
+        (client) => db.deleteInventoryPublisher(req.params.id, orgId, client),
+        (d) => ({ action: "publisher.deleted", target: d.id, details: { type: d.type, name: d.name } }),
+      );
+      return reply.status(204).send();
+    },
+  );
+
+  // Publish now, e.g. to check the credentials; scans publish automatically
+  app.post<{ Params: { id: string } }>(
+    "/api/v1/publishers/:id/publish",
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const exists = (await db.listInventoryPublishers(orgId)).some((p) => p.id === req.params.id);
+      if (!exists) return reply.status(404).send({ error: "not_found" });
+      await publishInventory(orgId, (err) => req.log.warn({ err }, "inventory publish failed"), req.params.id);
+      const publisher = (await db.listInventoryPublishers(orgId)).find((p) => p.id === req.params.id);
+      if (!publisher) return reply.status(404).send({ error: "not_found" });
+      const { config: _, ...result } = publisher;
+      return reply.status(result.last_error ? 502 : 200).send(result);
+    },
+  );
+}
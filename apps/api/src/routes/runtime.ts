@@ -0,0 +1,150 @@
+import type { FastifyInstance } from "fastify";
+import { createVendorMatcher } from "@thirdwatch/core";
+import type { VendorMatcher } from "@thirdwatch/core";
+import { authMiddleware } from "../middleware/auth.js";
+import { catalog } from "../catalog.js";
+import { db } from "../db.js";
+
+const MAX_CALLS_PER_REPORT = 1000;
+const MAX_FIELD_LENGTH = 253;
+
+let matcher: Promise<VendorMatcher> | undefined;
+
+/** Hosts the library could not attribute are classified with the catalog */
+function vendorMatcher(): Promise<VendorMatcher> {
+  matcher ??= catalog().then(createVendorMatcher);
+  return matcher;
+}
+
+interface UsageCallBody {
+  kind?: unknown;
+  host?: unknown;
+  method?: unknown;
+  vendor?: unknown;
+  count?: unknown;
+  errors?: unknown;
+  latency_ms_total?: unknown;
+  latency_ms_max?: unknown;
+}
+
+interface UsageReportBody {
+  service?: unknown;
+  environment?: unknown;
+  window_start?: unknown;
+  window_end?: unknown;
+  calls?: unknown;
+}
+
+function shortString(value: unknown): value is string {
+  return typeof value === "string" && value.length > 0 && value.length <= MAX_FIELD_LENGTH;
+}
+
+function count(value: unknown): number | null {
+  return typeof value === "number" && Number.isInteger(value) && value >= 0 ? value : null;
+}
+
+function latency(value: unknown): number {
+  return typeof value === "number" && Number.isFinite(value) && value >= 0 ? value : 0;
+}
+
+function timestamp(value: unknown): string | null {
+  return typeof value === "string" && !Number.isNaN(Date.parse(value))
+    ? new Date(value).toISOString()
+    : null;
+}
+
+export async function runtimeRoutes(app: FastifyInstance): Promise<void> {
+  // Usage windows posted by the instrumentation libraries (sdk/go)
+  app.post<{ Body: UsageReportBody }>(
+    "/api/v1/runtime/usage",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const body = req.body ?? {};
+
+      const windowStart = timestamp(body.window_start);
+      const windowEnd = timestamp(body.window_end);
+      if (!shortString(body.service) || !windowStart || !windowEnd) {
+        return reply
+          .status(400)
+          .send({ error: "service, window_start, and window_end are required" });
+      }
+      if (!Array.isArray(body.calls) || body.calls.length > MAX_CALLS_PER_REPORT) {
+        return reply.status(400).send({
+          error: `calls must be an array of at most ${MAX_CALLS_PER_REPORT} entries`,
+        });
+      }
+
+      const matchVendor = await vendorMatcher();
+      const calls = [];
+      for (const raw of body.calls as UsageCallBody[]) {
+        const callCount = count(raw?.count);
+        if (!shortString(raw?.host) || callCount === null) {
+          return reply
+            .status(400)
+            .send({ error: "each call needs a host and a non-negative integer count" });
+        }
+        const host = raw.host.toLowerCase();
+        calls.push({
+          kind: shortString(raw.kind) ? raw.kind : "http",
+          host,
+          method: shortString(raw.method) ? raw.method.toUpperCase() : null,
+          vendor: shortString(raw.vendor) ? raw.vendor : matchVendor(host),
+          count: callCount,
+          errors: Math.min(count(raw.errors) ?? 0, callCount),
+          latencyMsTotal: latency(raw.latency_ms_total),
+          latencyMsMax: latency(raw.latency_ms_max),
+        });
+      }
+
+      await db.insertRuntimeUsage(
+        orgId,
+        {
+          service: body.service,
+          environment: shortString(body.environment) ? body.environment : null,
+          windowStart,
+          windowEnd,
+        },
+        calls,
+      );
+      return reply.status(202).send({ accepted: calls.length });
+    },
+  );
+
+  app.get<{ Querystring: { service?: string; days?: string } }>(
+    "/api/v1/runtime/usage",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const days = Number(req.query.days ?? "7");
+      if (!Number.isFinite(days) || days <= 0 || days > 90) {
+        return reply.status(400).send({ error: "days must be between 1 and 90" });
+      }
+      const since = new Date(Date.now() - days * 86_400_000).toISOString();
+      const usage = await db.getRuntimeUsageSummary(orgId, {
+        since,
+        ...(req.query.service ? { service: req.query.service } : {}),
+      });
+      return reply.send({ since, usage });
+    },
+  );
+
+  // Cluster agents push "<namespace>/<workload>" services with the cluster as environment
+  app.get<{ Querystring: { cluster?: string; days?: string } }>(
+    "/api/v1/runtime/namespaces",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const days = Number(req.query.days ?? "7");
+      if (!Number.isFinite(days) || days <= 0 || days > 90) {
+        return reply.status(400).send({ error: "days must be between 1 and 90" });
+      }
+      const since = new Date(Date.now() - days * 86_400_000).toISOString();
+      const namespaces = await db.getRuntimeNamespaceRollup(orgId, {
+        since,
+        ...(req.query.cluster ? { cluster: req.query.cluster } : {}),
+      });
+      return reply.send({ since, namespaces });
+    },
+  );
+}
@@ -3,7 +3,11 @@ import { Queue } from "bullmq";
 import { parseTDM } from "@thirdwatch/tdm";
 import type { TDM } from "@thirdwatch/tdm";
 import { authMiddleware } from "../middleware/auth.js";
-import { db } from "../db.js";
+import { db, transaction } from "../db.js";
+import { deliverVendorEvents, recordVendorChanges } from "../vendor-feed.js";
+import { publishInventory } from "../publishers.js";
+
+const COMMIT_RE = /^[0-9a-f]{7,64}$/i;
 
 const PLAN_LIMITS: Record<string, { repositories: number | null }> = {
   free: { repositories: 3 },
@@ -35,12 +39,14 @@ export async function tdmRoutes(
       }
 
       const repository = tdm.metadata.repository ?? "unknown";
+      const commitHeader = req.headers["x-thirdwatch-commit"];
+      const commitSha = typeof commitHeader === "string" && COMMIT_RE.test(commitHeader) ? commitHeader : null;
+      const existingTdm = await db.getLatestTDM(orgId, repository);
 
       // Enforce repo limit
       const repoCount = await db.countDistinctRepos(orgId);
       const limit = PLAN_LIMITS[orgPlan]?.repositories ?? null;
       if (limit !== null) {
-        const existingTdm = await db.getLatestTDM(orgId, repository);
         if (!existingTdm && repoCount >= limit) {
           return reply.status(403).send({
             error: "plan_limit",
@@ -55,15 +61,36 @@ export async function tdmRoutes(
         tdm.sdks.length +
         tdm.apis.length;
 
-      const upload = await db.insertTdmUpload(
-        orgId,
-        repository,
-        tdm.metadata.scanner_version,
-        languages,
-        dependencyCount,
-        tdm,
-        true,
-      );
+      const { upload, vendorEvents } = await transaction(async (client) => {
+        const upload = await db.insertTdmUpload(
+          orgId,
+          repository,
+          tdm.metadata.scanner_version,
+          languages,
+          dependencyCount,
+          tdm,
+          true,
+          client,
+        );
+        const vendorEvents = await recordVendorChanges(
+          orgId,
+          {
+            id: upload.id,
+            repository,
+            tdm,
+            previous: (existingTdm?.tdm as TDM | undefined) ?? null,
+            commitSha,
+          },
+          client,
+        );
+        return { upload, vendorEvents };
+      });
+      deliverVendorEvents(orgId, vendorEvents, (err, url) =>
+        req.log.warn({ err, url }, "vendor feed delivery failed"),
+      ).catch((err: unknown) => req.log.warn({ err }, "vendor feed delivery failed"));
+      publishInventory(orgId, (err, publisherId) =>
+        req.log.warn({ err, publisherId }, "inventory publish failed"),
+      ).catch((err: unknown) => req.log.warn({ err }, "inventory publish failed"));
 
       const deps: Array<{
         id: string;
@@ -116,6 +143,7 @@ export async function tdmRoutes(
         tdmId: upload.id,
         repository,
         dependenciesRegistered: deps.length,
+        vendorChanges: vendorEvents.length,
         monitoringStarted: true,
       });
     },
@@ -0,0 +1,152 @@
+import type { FastifyInstance } from "fastify";
+import { authMiddleware } from "../middleware/auth.js";
+import { requireRole } from "../middleware/rbac.js";
+import { tierGuard } from "../middleware/tier-guard.js";
+import { audited } from "../audit.js";
+import { db } from "../db.js";
+import type { TeamFields } from "../db.js";
+
+const SCHEDULES = ["daily", "weekly", "off"] as const;
+const EMAIL_RE = /^[^\s@<>]+@[^\s@<>]+\.[^\s@<>]+$/;
+
+interface TeamBody {
+  name?: unknown;
+  repositories?: unknown;
+  deniedVendors?: unknown;
+  digestSchedule?: unknown;
+  digestEmails?: unknown;
+  slackWebhookUrl?: unknown;
+}
+
+function stringList(value: unknown): value is string[] {
+  return Array.isArray(value) && value.every((v) => typeof v === "string" && v.length > 0 && v.length <= 253);
+}
+
+/** Validate a create/update body; returns an error message or the fields */
+function parseTeam(body: TeamBody): string | (TeamFields & { name?: string }) {
+  const team: TeamFields & { name?: string } = {};
+  if (body.name !== undefined) {
+    if (typeof body.name !== "string" || !body.name.trim()) return "name must be a non-empty string";
+    team.name = body.name.trim();
+  }
+  if (body.repositories !== undefined) {
+    if (!stringList(body.repositories)) return "repositories must be an array of repository patterns";
+    team.repositories = body.repositories;
+  }
+  if (body.deniedVendors !== undefined) {
+    if (!stringList(body.deniedVendors)) return "deniedVendors must be an array of vendor slugs";
+    team.deniedVendors = body.deniedVendors;
+  }
+  if (body.digestSchedule !== undefined) {
+    if (!(SCHEDULES as readonly unknown[]).includes(body.digestSchedule)) {
+      return `digestSchedule must be one of ${SCHEDULES.join(", ")}`;
+    }
+    team.digestSchedule = body.digestSchedule as (typeof SCHEDULES)[number];
+  }
+  if (body.digestEmails !== undefined) {
+    if (!stringList(body.digestEmails) || !body.digestEmails.every((e) => EMAIL_RE.test(e))) {
+      return "digestEmails must be an array of email addresses";
+    }
+    team.digestEmails = body.digestEmails;
+  }
+  if (body.slackWebhookUrl !== undefined) {
+    if (body.slackWebhookUrl !== null && (typeof body.slackWebhookUrl !== "string" || !/^https:\/\//.test(body.slackWebhookUrl))) {
+      return "slackWebhookUrl must be an https URL or null";
+    }
+    team.slackWebhookUrl = body.slackWebhookUrl;
+  }
+  return team;
+}
+
+/** The fields a request set, for its audit entry; the Slack webhook URL is a secret */
+function teamDetails(team: TeamFields & { name?: string }): Record<string, unknown> {
+  const { slackWebhookUrl, ...fields } = team;
+  return slackWebhookUrl === undefined ? fields : { ...fields, slackConfigured: slackWebhookUrl !== null };
+}
+
+export async function teamsRoutes(app: FastifyInstance): Promise<void> {
+  app.get(
+    "/api/v1/teams",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const teams = await db.listTeams(orgId);
+      return reply.send({ teams });
+    },
+  );
+
+  app.post<{ Body: TeamBody }>(
+    "/api/v1/teams",
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const team = parseTeam(req.body ?? {});
+      if (typeof team === "string") return reply.status(400).send({ error: team });
+      if (!team.name) return reply.status(400).send({ error: "name is required" });
+      try {
+        const created = await audited(
+          req,
+          (client) => db.createTeam(orgId, { ...team, name: team.name! }, client),
+          (c) => ({ action: "team.created", target: c.id, details: teamDetails(team) }),
+        );
+        return reply.status(201).send(created);
+      } catch (err) {
+        // unique_violation on (org_id, name)
+        if ((err as { code?: string }).code === "23505") {
+          return reply.status(409).send({ error: `A team named "${team.name}" already exists` });
+        }
+        throw err;
+      }
+    },
+  );
+
+  app.patch<{ Params: { id: string }; Body: TeamBody }>(
+    "/api/v1/teams/:id",
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const team = parseTeam(req.body ?? {});
+      if (typeof team === "string") return reply.status(400).send({ error: team });
+      const updated = await audited(
+        req,
+        (client) => db.updateTeam(req.params.id, orgId, team, client),
+        (u) => ({ action: "team.updated", target: u.id, details: teamDetails(team) }),
+      );
+      if (!updated) return reply.status(404).send({ error: "not_found" });
+      return reply.send(updated);
+    },
+  );
+
+  app.delete<{ Params: { id: string } }>(
+    "/api/v1/teams/:id",
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      await audited(
+        req,
+        (client) => db.deleteTeam(req.params.id, orgId, client),
+        (d) => ({
+          action: "team.deleted",
+          target: d.id,
+          details: { name: d.name, repositories: d.repositories, deniedVendors: d.denied_vendors },
+        }),
+      );
+      return reply.status(204).send();
+    },
+  );
+
+  // Digests the worker generated for the team, newest first
+  app.get<{ Params: { id: string }; Querystring: { limit?: string } }>(
+    "/api/v1/teams/:id/digests",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const limit = Number(req.query.limit ?? "10");
+      if (!Number.isInteger(limit) || limit < 1 || limit > 100) {
+        return reply.status(400).send({ error: "limit must be between 1 and 100" });
+      }
+      const digests = await db.listTeamDigests(req.params.id, orgId, limit);
+      return reply.send({ digests });
+    },
+  );
+}
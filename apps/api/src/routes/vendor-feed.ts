@@ -0,0 +1,84 @@
+import type { FastifyInstance } from "fastify";
+import { authMiddleware } from "../middleware/auth.js";
+import { requireRole } from "../middleware/rbac.js";
+import { tierGuard } from "../middleware/tier-guard.js";
+import { db } from "../db.js";
+import { audited } from "../audit.js";
+
+const MAX_PAGE = 500;
+
+export async function vendorFeedRoutes(app: FastifyInstance): Promise<void> {
+  // Append-only stream of vendors appearing in and leaving repositories.
+  // Page with ?after=<next_cursor>; the cursor is stable across restarts.
+  app.get<{ Querystring: { after?: string; limit?: string; repository?: string; vendor?: string } }>(
+    "/api/v1/vendor-feed",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const after = Number(req.query.after ?? "0");
+      const limit = Number(req.query.limit ?? "100");
+      if (!Number.isInteger(after) || after < 0) {
+        return reply.status(400).send({ error: "after must be a non-negative integer cursor" });
+      }
+      if (!Number.isInteger(limit) || limit < 1 || limit > MAX_PAGE) {
+        return reply.status(400).send({ error: `limit must be between 1 and ${MAX_PAGE}` });
+      }
+      const events = await db.listVendorEvents(orgId, {
+        after,
+        limit,
+        ...(req.query.repository ? { repository: req.query.repository } : {}),
+        ...(req.query.vendor ? { vendor: req.query.vendor } : {}),
+      });
+      return reply.send({
+        events,
+        next_cursor: events.length > 0 ? events[events.length - 1]!.seq : after,
+      });
+    },
+  );
+
+  app.get(
+    "/api/v1/vendor-feed/subscriptions",
+    { preHandler: authMiddleware },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const subscriptions = await db.listVendorFeedSubscriptions(orgId);
+      // Secrets are write-only
+      return reply.send({ subscriptions: subscriptions.map(({ id, url }) => ({ id, url })) });
+    },
+  );
+
+  app.post<{ Body: { url?: string; secret?: string } }>(
+    "/api/v1/vendor-feed/subscriptions",
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      const { url, secret } = req.body ?? {};
+      if (typeof url !== "string" || !/^https:\/\//.test(url)) {
+        return reply.status(400).send({ error: "url must be an https URL" });
+      }
+      if (secret !== undefined && typeof secret !== "string") {
+        return reply.status(400).send({ error: "secret must be a string" });
+      }
+      const subscription = await audited(
+        req,
+        (client) => db.createVendorFeedSubscription(orgId, url, secret ?? null, client),
+        (s) => ({ action: "vendor_feed_subscription.created", target: s.id, details: { url: s.url, signed: secret !== undefined } }),
+      );
+      return reply.status(201).send(subscription);
+    },
+  );
+
+  app.delete<{ Params: { id: string } }>(
+    "/api/v1/vendor-feed/subscriptions/:id",
+    { preHandler: [authMiddleware, requireRole("admin"), tierGuard(["team", "enterprise"])] },
+    async (req, reply) => {
+      const orgId = (req as any).orgId as string;
+      await audited(
+        req,
+        (client) => db.deleteVendorFeedSubscription(req.params.id, orgId, client),
+        (d) => ({ action: "vendor_feed_subscription.deleted", target: d.id, details: { url: d.url } }),
+      );
+      return reply.status(204).send();
+    },
+  );
+}
@@ -0,0 +1,63 @@
+import { createVendorMatcher, diffVendors, vendorFeedRequest } from "@thirdwatch/core";
+import type { TDM } from "@thirdwatch/tdm";
+import { catalog } from "./catalog.js";
+import { db } from "./db.js";
+import type { Queryable } from "./db.js";
+
+type VendorEvent = Awaited<ReturnType<typeof db.insertVendorEvents>>[number];
+
+const DELIVERY_TIMEOUT_MS = 10_000;
+
+/**
+ * Append feed events for vendors that appeared in or left a repository
+ * between its previous baseline and this upload. A repository's first
+ * upload reports every vendor as added. Pass the client of the transaction
+ * that inserted the upload, so the upload and its events commit together.
+ */
+export async function recordVendorChanges(
+  orgId: string,
+  upload: { id: string; repository: string; tdm: TDM; previous: TDM | null; commitSha: string | null },
+  q: Queryable,
+): Promise<VendorEvent[]> {
+  const changes = diffVendors(upload.previous, upload.tdm, createVendorMatcher(await catalog()));
+  return db.insertVendorEvents(
+    orgId,
+    changes.map((c) => ({
+      ...c,
+      repository: upload.repository,
+      commitSha: upload.commitSha,
+      tdmUploadId: upload.id,
+    })),
+    q,
+  );
+}
+
+/**
+ * POST new events to the org's feed subscriptions, signed like notifier
+ * webhooks (X-Thirdwatch-Signature: sha256=<hmac>). Best effort: consumers
+ * that miss a delivery catch up from GET /api/v1/vendor-feed?after=<seq>.
+ */
+export async function deliverVendorEvents(
+  orgId: string,
+  events: VendorEvent[],
+  onError: (err: unknown, url: string) => void,
+): Promise<void> {
+  if (events.length === 0) return;
+  const subscriptions = await db.listVendorFeedSubscriptions(orgId);
+  await Promise.all(
+    subscriptions.map(async (sub) => {
+      const { body, headers } = vendorFeedRequest(events, sub.secret);
+      try {
+        const res = await fetch(sub.url, {
+          method: "POST",
+          headers,
+          body,
+          signal: AbortSignal.timeout(DELIVERY_TIMEOUT_MS),
+        });
+        if (!res.ok) throw new Error(`HTTP ${res.status}`);
+      } catch (err) {
+        onError(err, sub.url);
+      }
+    }),
+  );
+}
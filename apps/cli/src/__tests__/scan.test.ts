@@ -121,6 +121,18 @@ describe("thirdwatch scan", () => {
     expect(tdm.metadata.total_dependencies_found).toBeGreaterThan(0);
   });
 
+  it("supports --format html with --context-lines", () => {
+    const { stdout, exitCode } = run(["scan", join(FIXTURES, "python-app"), "--format", "html", "--context-lines", "2", "-o", "-"]);
+    expect(exitCode).toBe(0);
+    expect(stdout.startsWith("<!doctype html>")).toBe(true);
+    expect(stdout).toContain('<span class="hit">');
+  });
+
+  it("exits with code 2 on an out-of-range --context-lines", () => {
+    const { exitCode } = run(["scan", join(FIXTURES, "python-app"), "--context-lines", "50", "-o", "-"]);
+    expect(exitCode).toBe(2);
+  });
+
   it("--quiet outputs only JSON to stdout", () => {
     const { stdout, exitCode } = run([
       "scan",
@@ -0,0 +1,24 @@
+// apps/cli/src/approved-list.ts — The approved-vendor list, from a flag, a file, or the Thirdwatch API
+import { existsSync } from "node:fs";
+import { readFile } from "node:fs/promises";
+
+/** A comma-separated list of vendor slugs, or a file with one per line (# comments allowed) */
+export async function readApproved(value: string): Promise<string[]> {
+  const text = existsSync(value) ? await readFile(value, "utf8") : value.replace(/,/g, "\n");
+  return text
+    .split("\n")
+    .map((line) => line.replace(/#.*/, "").trim())
+    .filter(Boolean);
+}
+
+/** Vendors with an "approved" decision in the org's approval list */
+export async function fetchApproved(apiUrl: string, token: string): Promise<string[]> {
+  const response = await fetch(`${apiUrl}/api/v1/vendors/approvals`, {
+    headers: { Authorization: `Bearer ${token}` },
+  });
+  if (!response.ok) {
+    throw new Error(`Error: Fetching vendor approvals failed with HTTP ${response.status}.`);
+  }
+  const { approvals } = (await response.json()) as { approvals: Array<{ vendor: string; status: string }> };
+  return approvals.filter((a) => a.status === "approved").map((a) => a.vendor);
+}
@@ -0,0 +1,218 @@
+// apps/cli/src/commands/agent.ts — `thirdwatch agent` runtime egress observer
+import { Command } from "commander";
+import { hostname } from "node:os";
+import { withMeteringOptions, startMetering } from "../metering.js";
+import type { MeteringOpts } from "../metering.js";
+import {
+  runEgressAgent,
+  buildRuntimeTDM,
+  buildUsageReports,
+  sendUsageReport,
+  createVendorMatcher,
+  createIpRangeMatcher,
+  PodDirectory,
+  inClusterPodLister,
+  NODE_NAMESPACE,
+} from "@thirdwatch/core";
+import type { RuntimeObservation, SDKRegistryEntry } from "@thirdwatch/core";
+import { createSpinner } from "../ui/spinner.js";
+import { printRuntimeSummary } from "../output/runtime-summary.js";
+import { loadRuntimeCatalog, checkRuntimeOutput, writeRuntimeReport } from "../runtime-output.js";
+import type { RuntimeOutputOpts } from "../runtime-output.js";
+
+interface AgentCommandOpts extends RuntimeOutputOpts, MeteringOpts {
+  duration?: string;
+  dns: boolean;
+  tls: boolean;
+  includePrivate?: boolean;
+  bpftrace?: string;
+  libc?: string;
+  libssl?: string;
+  kubernetes?: boolean;
+  nodeName?: string;
+  push?: string;
+  token?: string;
+  cluster?: string;
+  interval: string;
+  quiet?: boolean;
+}
+
+interface PushTarget {
+  endpoint: string;
+  token: string;
+  cluster?: string;
+  nodeName: string;
+}
+
+/** Send one window as per-workload usage reports; failures are logged, not fatal */
+function windowPusher(target: PushTarget, registry: SDKRegistryEntry[]) {
+  const matchVendor = createVendorMatcher(registry);
+  const matchIp = createIpRangeMatcher(registry);
+  let windowStart = new Date().toISOString();
+
+  return async (observations: RuntimeObservation[]) => {
+    const windowEnd = new Date().toISOString();
+    try {
+      const reports = buildUsageReports(observations, {
+        matchVendor,
+        matchIp,
+        windowStart,
+        windowEnd,
+        defaultService: `${NODE_NAMESPACE}/${target.nodeName}`,
+        ...(target.cluster ? { environment: target.cluster } : {}),
+      });
+      for (const report of reports) await sendUsageReport(target.endpoint, target.token, report);
+    } catch (err) {
+      console.error(`thirdwatch agent: ${err instanceof Error ? err.message : String(err)}`);
+    } finally {
+      windowStart = windowEnd;
+    }
+  };
+}
+
+export const agentCommand = withMeteringOptions(
+  new Command("agent")
+    .description(
+      "Observe outbound TCP/TLS connections and DNS lookups with eBPF (requires root and bpftrace).",
+    )
+    .option("-o, --output <file>", "Output file path (use - for stdout)", "./thirdwatch-runtime.json")
+    .option("-f, --format <format>", "Output format: json or yaml", "json")
+    .option("-d, --duration <seconds>", "Stop after this many seconds (default: run until Ctrl-C)")
+    .option("--no-dns", "Do not trace getaddrinfo() lookups")
+    .option("--no-tls", "Do not trace TLS SNI (OpenSSL)")
+    .option("--include-private", "Keep connections to private and loopback addresses")
+    .option("--bpftrace <path>", "bpftrace executable", "bpftrace")
+    .option("--libc <path>", "libc used for the DNS uprobe")
+    .option("--libssl <path>", "libssl used for the SNI uprobe")
+    .option("--kubernetes", "Attribute connections to pods (namespace/workload); run as a DaemonSet with hostPID")
+    .option("--node-name <name>", "Kubernetes node name (or set NODE_NAME env var)")
+    .option("--push <url>", "Push per-workload usage to a thirdwatch server instead of writing a report")
+    .option("--token <token>", "API token for --push (or set THIRDWATCH_TOKEN env var)")
+    .option("--cluster <name>", "Cluster name recorded with pushed usage")
+    .option("--interval <seconds>", "Seconds per usage window, for --push and egress alerts", "60")
+    .option("--catalog-version <version>", "Pin the vendor catalog version")
+    .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+    .option("--quiet", "Suppress all output except the report"),
+)
+  .action(async (opts: AgentCommandOpts) => {
+    const quiet = opts.quiet ?? false;
+    const outputPath = checkRuntimeOutput(opts);
+    if (outputPath === null) return;
+
+    let durationMs: number | undefined;
+    if (opts.duration !== undefined) {
+      const seconds = Number(opts.duration);
+      if (!Number.isFinite(seconds) || seconds <= 0) {
+        console.error(`Error: Invalid duration "${opts.duration}".`);
+        process.exitCode = 2;
+        return;
+      }
+      durationMs = seconds * 1000;
+    }
+
+    const intervalSeconds = Number(opts.interval);
+    if (!Number.isFinite(intervalSeconds) || intervalSeconds < 10) {
+      console.error(`Error: Invalid interval "${opts.interval}" (minimum 10 seconds).`);
+      process.exitCode = 2;
+      return;
+    }
+
+    const nodeName = opts.nodeName ?? process.env["NODE_NAME"] ?? hostname();
+    let push: PushTarget | undefined;
+    if (opts.push !== undefined) {
+      const token = opts.token ?? process.env["THIRDWATCH_TOKEN"];
+      if (!token) {
+        console.error("Error: API token required for --push. Use --token or set THIRDWATCH_TOKEN.");
+        process.exitCode = 2;
+        return;
+      }
+      push = { endpoint: opts.push, token, nodeName, ...(opts.cluster ? { cluster: opts.cluster } : {}) };
+    }
+
+    let pods: PodDirectory | undefined;
+    if (opts.kubernetes) {
+      try {
+        pods = new PodDirectory({ list: inClusterPodLister(nodeName) });
+        await pods.refresh();
+      } catch (err) {
+        console.error(`Error: ${err instanceof Error ? err.message : String(err)}`);
+        process.exitCode = 2;
+        return;
+      }
+    }
+
+    const controller = new AbortController();
+    const onSigint = () => controller.abort();
+    process.once("SIGINT", onSigint);
+
+    const s = createSpinner();
+    try {
+      const { registry, catalogVersion } = await loadRuntimeCatalog(opts);
+      const metering = await startMetering(opts, registry, { defaultService: nodeName, quiet });
+      if (metering === undefined) return;
+
+      // Pushing and metering both work in windows; without --push, windows are
+      // still collected for the final report
+      const collected: RuntimeObservation[] = [];
+      const pushWindow = push ? windowPusher(push, registry) : undefined;
+      const onWindow = async (window: RuntimeObservation[]) => {
+        if (pods) await pods.resolve(window).catch(() => {});
+        if (metering) {
+          for (const obs of window) metering.meter.record(obs);
+          await metering.closeWindow();
+        }
+        if (pushWindow) await pushWindow(window);
+        else collected.push(...window);
+      };
+      const windowed = push !== undefined || metering !== null;
+
+      if (!quiet && !push) {
+        s.start(durationMs ? `Observing egress for ${opts.duration}s…` : "Observing egress (Ctrl-C to stop)…");
+      }
+      if (!quiet && push) console.error(`thirdwatch agent pushing usage to ${push.endpoint} every ${opts.interval}s`);
+
+      const startMs = Date.now();
+      let events = 0;
+      let observations = await runEgressAgent({
+        dns: opts.dns,
+        tls: opts.tls,
+        includePrivate: opts.includePrivate === true,
+        signal: controller.signal,
+        onEvent: () => {
+          events++;
+        },
+        ...(opts.bpftrace ? { bpftrace: opts.bpftrace } : {}),
+        ...(opts.libc ? { libc: opts.libc } : {}),
+        ...(opts.libssl ? { libssl: opts.libssl } : {}),
+        ...(durationMs ? { durationMs } : {}),
+        ...(pods ? { clientFor: pods.clientFor.bind(pods) } : {}),
+        ...(windowed ? { windowMs: intervalSeconds * 1000, onWindow } : {}),
+      });
+      await metering?.stop();
+      if (push) {
+        process.exitCode = 0;
+        return;
+      }
+      if (windowed) observations = collected;
+      else if (pods) await pods.resolve(observations);
+
+      const tdm = buildRuntimeTDM(observations, registry, {
+        duration: Date.now() - startMs,
+        ...(catalogVersion ? { catalogVersion } : {}),
+      });
+      if (!quiet) s.succeed(`Captured ${observations.length} connections (${events} events)`);
+
+      await writeRuntimeReport(tdm, opts.format, outputPath);
+      if (!quiet && outputPath) {
+        printRuntimeSummary(tdm, "thirdwatch agent");
+        console.log(`\n✓ Runtime report written to ${outputPath}`);
+      }
+      process.exitCode = 0;
+    } catch (err) {
+      if (!quiet) s.fail("Agent failed");
+      console.error(`\n${err instanceof Error ? err.message : String(err)}`);
+      process.exitCode = 1;
+    } finally {
+      process.off("SIGINT", onSigint);
+    }
+  });
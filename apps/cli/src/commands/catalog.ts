@@ -0,0 +1,114 @@
+// apps/cli/src/commands/catalog.ts — `thirdwatch catalog` subcommands
+import { Command } from "commander";
+import { writeFile } from "node:fs/promises";
+import { dirname, relative, resolve } from "node:path";
+import { fileURLToPath } from "node:url";
+import pc from "picocolors";
+import {
+  updateCatalog,
+  importCatalogBundle,
+  currentCatalogVersion,
+  catalogCacheDir,
+  validateCatalogFiles,
+  buildEnrichmentDataset,
+} from "@thirdwatch/core";
+import { createSpinner } from "../ui/spinner.js";
+import { readTDM } from "../tdm-file.js";
+
+const __dirname = dirname(fileURLToPath(import.meta.url));
+
+interface UpdateCommandOpts {
+  version?: string;
+  url?: string;
+}
+
+const updateCommand = new Command("update")
+  .description("Download and verify the latest signed vendor catalog bundle.")
+  .option("--version <version>", "Install a specific catalog version instead of the latest")
+  .option("--url <url>", "Catalog base URL (or set THIRDWATCH_CATALOG_URL)")
+  .action(async (opts: UpdateCommandOpts) => {
+    const s = createSpinner();
+    s.start(opts.version ? `Fetching catalog ${opts.version}…` : "Fetching latest catalog…");
+    try {
+      const bundle = await updateCatalog({
+        ...(opts.version ? { version: opts.version, setCurrent: true } : {}),
+        ...(opts.url ? { baseUrl: opts.url } : {}),
+      });
+      s.succeed(`Catalog ${bundle.version} installed — ${bundle.entries.length} vendors`);
+      console.log(`  Cache: ${catalogCacheDir()}`);
+    } catch (err) {
+      s.fail("Catalog update failed");
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 1;
+    }
+  });
+
+const importCommand = new Command("import")
+  .description("Install a catalog bundle copied from a connected machine (expects <file>.sig alongside).")
+  .argument("<file>", "catalog.json from a catalog release or `thirdwatch catalog update` cache")
+  .option("--no-current", "Install without making it the bundle scans use by default")
+  .action(async (file: string, opts: { current: boolean }) => {
+    try {
+      const bundle = await importCatalogBundle(file, { setCurrent: opts.current });
+      console.log(pc.green(`✓ Catalog ${bundle.version} installed — ${bundle.entries.length} vendors`));
+      console.log(`  Cache: ${catalogCacheDir()}`);
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 2;
+    }
+  });
+
+const exportEnrichmentCommand = new Command("export-enrichment")
+  .description("Collect the host enrichment in TDMs from `scan --enrich` into a dataset for offline scans.")
+  .argument("<tdms...>", "TDM files scanned with --enrich")
+  .requiredOption("-o, --output <file>", "Dataset file to write")
+  .action(async (files: string[], opts: { output: string }) => {
+    try {
+      const dataset = buildEnrichmentDataset(await Promise.all(files.map((f) => readTDM(resolve(f)))));
+      await writeFile(resolve(opts.output), JSON.stringify(dataset, null, 2) + "\n", "utf8");
+      console.log(pc.green(`✓ ${Object.keys(dataset).length} hosts → ${opts.output}`));
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 2;
+    }
+  });
+
+const statusCommand = new Command("status")
+  .description("Show which catalog bundle scans will use.")
+  .action(async () => {
+    const current = await currentCatalogVersion();
+    console.log(current ? `Catalog ${current} (${catalogCacheDir()})` : "Built-in catalog (run `thirdwatch catalog update` to fetch the latest)");
+  });
+
+const validateCommand = new Command("validate")
+  .description("Check catalog entries against the schema and run their examples.")
+  .argument("[paths...]", "Entry files or directories (default: the built-in registries/sdks)")
+  .action(async (paths: string[]) => {
+    const targets = paths.length > 0
+      ? paths
+      : [resolve(__dirname, "../../../../registries/sdks")];
+
+    try {
+      const results = await validateCatalogFiles(targets);
+      let failed = 0;
+      for (const { file, errors } of results) {
+        if (errors.length === 0) continue;
+        failed++;
+        console.error(pc.red(`✗ ${relative(process.cwd(), file)}`));
+        for (const e of errors) console.error(`    ${e}`);
+      }
+      console.log(`\n${results.length - failed}/${results.length} catalog entries valid`);
+      if (failed > 0) process.exitCode = 1;
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 2;
+    }
+  });
+
+export const catalogCommand = new Command("catalog")
+  .description("Manage the vendor catalog used for detection.")
+  .addCommand(updateCommand)
+  .addCommand(importCommand)
+  .addCommand(exportEnrichmentCommand)
+  .addCommand(statusCommand)
+  .addCommand(validateCommand);
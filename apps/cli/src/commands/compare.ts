@@ -0,0 +1,130 @@
+// apps/cli/src/commands/compare.ts — `thirdwatch compare --from --to` dependency delta between two refs
+import { Command } from "commander";
+import { execFile } from "node:child_process";
+import { existsSync } from "node:fs";
+import { mkdtemp, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { dirname, join, resolve } from "node:path";
+import { fileURLToPath } from "node:url";
+import { promisify } from "node:util";
+import pc from "picocolors";
+import { compareTDMs, hasChanges, renderComparisonMarkdown, resolveCatalog, scan } from "@thirdwatch/core";
+import type { CatalogBundle, CompareSide, SDKRegistryEntry } from "@thirdwatch/core";
+import type { TDM } from "@thirdwatch/tdm";
+import { PythonPlugin } from "@thirdwatch/language-python";
+import { JavaScriptPlugin } from "@thirdwatch/language-javascript";
+import { GoPlugin } from "@thirdwatch/language-go";
+import { JavaPlugin } from "@thirdwatch/language-java";
+import { RustPlugin } from "@thirdwatch/language-rust";
+import { PhpPlugin } from "@thirdwatch/language-php";
+import { loadRuntimeCatalog } from "../runtime-output.js";
+import { readTDM } from "../tdm-file.js";
+
+const __dirname = dirname(fileURLToPath(import.meta.url));
+const execFileAsync = promisify(execFile);
+
+interface CompareCommandOpts {
+  from: string;
+  to: string;
+  format: string;
+  output?: string;
+  config?: string;
+  catalogVersion?: string;
+  catalogBundle?: string;
+}
+
+async function git(cwd: string, args: string[]): Promise<string> {
+  const { stdout } = await execFileAsync("git", ["-C", cwd, ...args], { maxBuffer: 16 * 1024 * 1024 });
+  return stdout.trim();
+}
+
+/**
+ * Scan the repository as of `ref` in a temporary worktree, so the working
+ * copy is left alone. A TDM file stands in for a scan that already exists.
+ */
+async function scanRef(
+  repo: string,
+  ref: string,
+  opts: CompareCommandOpts,
+  catalog: CatalogBundle | null,
+): Promise<{ tdm: TDM; side: CompareSide }> {
+  if (/\.(json|ya?ml)$/.test(ref) && existsSync(ref)) {
+    return { tdm: await readTDM(resolve(ref)), side: { ref } };
+  }
+  const commit = await git(repo, ["rev-parse", "--verify", "--quiet", `${ref}^{commit}`]).catch(() => "");
+  if (!commit) throw new Error(`Error: ${ref} is not a commit, tag, or branch in ${repo}.`);
+  // Scan the same subdirectory when run from inside the repository
+  const prefix = await git(repo, ["rev-parse", "--show-prefix"]);
+
+  const dir = await mkdtemp(join(tmpdir(), "thirdwatch-compare-"));
+  try {
+    await git(repo, ["worktree", "add", "--detach", "--quiet", dir, commit]);
+    const { tdm } = await scan({
+      root: join(dir, prefix),
+      plugins: [new PythonPlugin(), new JavaScriptPlugin(), new GoPlugin(), new JavaPlugin(), new RustPlugin(), new PhpPlugin()],
+      // Secret ages don't change what the release depends on
+      secretHistory: false,
+      registriesDir: resolve(__dirname, "../../../../registries"),
+      ...(catalog ? { catalog } : {}),
+      ...(opts.config ? { configFile: opts.config } : {}),
+    });
+    return { tdm, side: { ref, commit } };
+  } finally {
+    await git(repo, ["worktree", "remove", "--force", dir]).catch(() => {});
+    await rm(dir, { recursive: true, force: true });
+  }
+}
+
+export const compareCommand = new Command("compare")
+  .description(
+    "Scan two git refs and report the third-party dependencies added, removed, and changed between them, as release notes for change records.",
+  )
+  .argument("[path]", "Repository directory (default: current directory)", ".")
+  .requiredOption("--from <ref>", "Earlier tag, branch, or commit (or a TDM file from an earlier scan)")
+  .option("--to <ref>", "Later tag, branch, or commit (or a TDM file)", "HEAD")
+  .option("-f, --format <format>", "Output format: markdown or json", "markdown")
+  .option("-o, --output <file>", "Write to a file instead of stdout")
+  .option("--config <file>", "Path to .thirdwatch.yml for both scans (default: each ref's own)")
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .action(async (path: string, opts: CompareCommandOpts) => {
+    if (opts.format !== "markdown" && opts.format !== "json") {
+      console.error(`Error: Invalid format "${opts.format}". Use "markdown" or "json".`);
+      process.exitCode = 2;
+      return;
+    }
+
+    const repo = resolve(path);
+    let from: { tdm: TDM; side: CompareSide };
+    let to: { tdm: TDM; side: CompareSide };
+    let registry: SDKRegistryEntry[];
+    try {
+      // Both sides use the same catalog, so the delta reflects the code alone
+      const catalog = await resolveCatalog({
+        ...(opts.catalogBundle ? { bundlePath: opts.catalogBundle } : {}),
+        ...(opts.catalogVersion ? { version: opts.catalogVersion } : {}),
+      });
+      console.error(pc.dim(`Scanning ${opts.from}…`));
+      from = await scanRef(repo, opts.from, opts, catalog);
+      console.error(pc.dim(`Scanning ${opts.to}…`));
+      to = await scanRef(repo, opts.to, opts, catalog);
+      registry = catalog ? catalog.entries : (await loadRuntimeCatalog({})).registry;
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 2;
+      return;
+    }
+
+    const report = compareTDMs(from.tdm, to.tdm, registry, { from: from.side, to: to.side });
+    const output = opts.format === "json" ? JSON.stringify(report, null, 2) + "\n" : renderComparisonMarkdown(report);
+    if (opts.output) {
+      await writeFile(resolve(opts.output), output, "utf8");
+      console.error(
+        pc.green(
+          `✓ ${hasChanges(report) ? `${report.vendors.added.length} vendors added, ${report.vendors.removed.length} removed` : "No changes"} → ${opts.output}`,
+        ),
+      );
+    } else {
+      process.stdout.write(output);
+    }
+  });
@@ -0,0 +1,93 @@
+// apps/cli/src/commands/concentration.ts — `thirdwatch concentration` vendor criticality across services
+import { Command } from "commander";
+import { basename } from "node:path";
+import pc from "picocolors";
+import { computeConcentration, groupByService } from "@thirdwatch/core";
+import type { ConcentrationReport, CriticalityTier } from "@thirdwatch/core";
+import { loadRuntimeCatalog } from "../runtime-output.js";
+import { readTDM } from "../tdm-file.js";
+
+interface ConcentrationCommandOpts {
+  format: string;
+  critical: string[];
+  catalogVersion?: string;
+  catalogBundle?: string;
+}
+
+function collect(value: string, previous: string[]): string[] {
+  return [...previous, ...value.split(",").map((s) => s.trim()).filter(Boolean)];
+}
+
+const TIER_COLOR: Record<CriticalityTier, (s: string) => string> = {
+  high: pc.red,
+  medium: pc.yellow,
+  low: pc.gray,
+};
+
+function printConcentration(report: ConcentrationReport): void {
+  console.log("");
+  console.log(
+    pc.bold(`  Vendor criticality across ${report.total_services} services`) +
+      (report.critical_services.length > 0 ? pc.dim(` (${report.critical_services.length} critical)`) : ""),
+  );
+  console.log("");
+  for (const v of report.vendors) {
+    const used = `${v.services.length}/${report.total_services} services`;
+    const critical = v.critical_services.length > 0 ? `  ${v.critical_services.length} critical` : "";
+    const sole = v.sole_provider_in.length > 0 ? pc.dim(`  sole ${v.category ?? "provider"} in ${v.sole_provider_in.length}`) : "";
+    console.log(
+      `    ${TIER_COLOR[v.tier]("●")} ${String(v.score).padStart(3)}  ${v.display_name.padEnd(24)} ${used.padEnd(14)}${critical}${sole}`,
+    );
+  }
+
+  const concentrated = report.categories.filter((c) => c.vendors.length > 0 && c.hhi >= 5000);
+  if (concentrated.length > 0) {
+    console.log("");
+    console.log(pc.bold("  Concentrated categories (HHI ≥ 5000)"));
+    for (const c of concentrated) {
+      console.log(`    ${c.category.padEnd(16)} ${String(c.hhi).padStart(5)}  ${pc.dim(c.vendors.map((v) => `${v.vendor} ×${v.services}`).join(", "))}`);
+    }
+  }
+  if (report.shared_critical_vendors.length > 0) {
+    console.log("");
+    console.log(pc.yellow(`  Critical services share: ${report.shared_critical_vendors.join(", ")}`));
+  }
+}
+
+export const concentrationCommand = new Command("concentration")
+  .description(
+    "Rank vendors by how many services depend on them, critical-path overlap, and lock-in, for continuity planning.",
+  )
+  .argument("<tdm...>", "TDMs for each service; files with the same repository are one service")
+  .option("-f, --format <format>", "Output format: text or json", "text")
+  .option("--critical <services>", "Critical services, comma-separated or repeated", collect, [])
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .action(async (files: string[], opts: ConcentrationCommandOpts) => {
+    if (opts.format !== "text" && opts.format !== "json") {
+      console.error(`Error: Invalid format "${opts.format}". Use "text" or "json".`);
+      process.exitCode = 2;
+      return;
+    }
+
+    let report: ConcentrationReport;
+    try {
+      const { registry } = await loadRuntimeCatalog(opts);
+      const tdms = await Promise.all(files.map(readTDM));
+      const inventory = groupByService(tdms, (i) => basename(files[i]!).replace(/\.(json|ya?ml)$/, ""));
+      report = computeConcentration(inventory, registry, { critical: opts.critical });
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 2;
+      return;
+    }
+
+    const unknown = opts.critical.filter((s) => !report.critical_services.includes(s));
+    if (unknown.length > 0) console.error(pc.yellow(`Warning: no TDM for critical service(s) ${unknown.join(", ")}`));
+
+    if (opts.format === "json") {
+      process.stdout.write(JSON.stringify(report, null, 2) + "\n");
+    } else {
+      printConcentration(report);
+    }
+  });
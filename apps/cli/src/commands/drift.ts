@@ -0,0 +1,94 @@
+// apps/cli/src/commands/drift.ts — `thirdwatch drift` static-vs-runtime comparison
+import { Command } from "commander";
+import pc from "picocolors";
+import { computeDrift } from "@thirdwatch/core";
+import type { DriftReport, DriftVendor } from "@thirdwatch/core";
+import { readTDM } from "../tdm-file.js";
+
+interface DriftCommandOpts {
+  format: string;
+  failOn?: string;
+}
+
+const FAIL_ON = ["runtime-only", "static-only", "any"] as const;
+
+function printSdks(v: DriftVendor): void {
+  for (const sdk of v.sdks ?? []) {
+    const runtime = `${sdk.sdk_package} ${sdk.runtime_versions.join(", ") || "(version unknown)"}`;
+    const eco = sdk.ecosystem ? pc.dim(` (${sdk.ecosystem})`) : "";
+    const declared =
+      sdk.version_matches === false ? pc.yellow(` — manifests declare ${sdk.static_version}`) : "";
+    console.log(`        ${pc.dim("↳")} ${runtime}${eco}${declared}`);
+  }
+}
+
+function printDrift(report: DriftReport): void {
+  console.log("");
+  console.log(pc.bold(`  Runtime-only vendors (${report.runtime_only.length}) — called but not in code`));
+  for (const v of report.runtime_only) {
+    console.log(`    ${pc.yellow("●")} ${v.vendor.padEnd(20)} ${String(v.runtime_count).padStart(6)}×  ${pc.dim(v.hosts.join(", "))}`);
+    printSdks(v);
+  }
+  for (const h of report.unknown_runtime_hosts) {
+    console.log(`    ${pc.gray("●")} ${"(unclassified)".padEnd(20)} ${String(h.runtime_count).padStart(6)}×  ${pc.dim(h.host)}`);
+  }
+
+  console.log("");
+  console.log(pc.bold(`  Static-only vendors (${report.static_only.length}) — in code but never observed`));
+  for (const v of report.static_only) {
+    console.log(`    ${pc.red("●")} ${v.vendor.padEnd(20)} ${String(v.static_usages).padStart(4)} usages  ${pc.dim(v.files.slice(0, 3).join(", "))}`);
+  }
+
+  console.log("");
+  console.log(pc.bold(`  Confirmed (${report.confirmed.length})`));
+  for (const v of report.confirmed) {
+    console.log(`    ${pc.green("●")} ${v.vendor.padEnd(20)} ${String(v.runtime_count).padStart(6)}×  ${v.static_usages} usages`);
+    printSdks(v);
+  }
+}
+
+export const driftCommand = new Command("drift")
+  .description(
+    "Compare a static scan with runtime reports: vendors called but not in code, and in code but never called.",
+  )
+  .argument("<static>", "Static TDM from `thirdwatch scan`")
+  .argument("<runtime...>", "Runtime reports (agent, proxy, log ingestion)")
+  .option("-f, --format <format>", "Output format: text or json", "text")
+  .option("--fail-on <kind>", `Exit 1 when drift is found: ${FAIL_ON.join(", ")}`)
+  .action(async (staticFile: string, runtimeFiles: string[], opts: DriftCommandOpts) => {
+    if (opts.format !== "text" && opts.format !== "json") {
+      console.error(`Error: Invalid format "${opts.format}". Use "text" or "json".`);
+      process.exitCode = 2;
+      return;
+    }
+    if (opts.failOn && !(FAIL_ON as readonly string[]).includes(opts.failOn)) {
+      console.error(`Error: Invalid --fail-on "${opts.failOn}". Use ${FAIL_ON.join(", ")}.`);
+      process.exitCode = 2;
+      return;
+    }
+
+    let report: DriftReport;
+    try {
+      const staticTdm = await readTDM(staticFile);
+      const runtimeTdms = await Promise.all(runtimeFiles.map(readTDM));
+      report = computeDrift(staticTdm, runtimeTdms);
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 2;
+      return;
+    }
+
+    if (opts.format === "json") {
+      process.stdout.write(JSON.stringify(report, null, 2) + "\n");
+    } else {
+      printDrift(report);
+    }
+
+    const runtimeDrift = report.runtime_only.length + report.unknown_runtime_hosts.length > 0;
+    const staticDrift = report.static_only.length > 0;
+    const failed =
+      (opts.failOn === "runtime-only" && runtimeDrift) ||
+      (opts.failOn === "static-only" && staticDrift) ||
+      (opts.failOn === "any" && (runtimeDrift || staticDrift));
+    process.exitCode = failed ? 1 : 0;
+  });
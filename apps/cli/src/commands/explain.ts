@@ -0,0 +1,70 @@
+// apps/cli/src/commands/explain.ts — `thirdwatch explain` catalog details and review links for one vendor
+import { Command } from "commander";
+import pc from "picocolors";
+import { explainVendor } from "@thirdwatch/core";
+import type { VendorExplanation } from "@thirdwatch/core";
+import { loadRuntimeCatalog } from "../runtime-output.js";
+
+interface ExplainCommandOpts {
+  format: string;
+  catalogVersion?: string;
+  catalogBundle?: string;
+}
+
+function printExplanation(v: VendorExplanation): void {
+  const row = (label: string, value: string | undefined) =>
+    console.log(`    ${label.padEnd(16)} ${value ?? pc.gray("not in catalog")}`);
+
+  console.log("");
+  console.log(pc.bold(`  ${v.display_name}`) + pc.dim(` (${v.vendor}${v.category ? `, ${v.category}` : ""})`));
+  console.log("");
+  row("Homepage", v.homepage);
+  row("Docs", v.docs_url);
+  row("Changelog", v.changelog_url);
+  row("Status page", v.status_page_url);
+  row("SLA", v.sla ? `${v.sla.uptime}%${v.sla.plan ? ` (${v.sla.plan})` : ""} — ${v.sla.url}` : undefined);
+  console.log("");
+  row("Privacy policy", v.compliance.privacy_policy_url);
+  row("DPA", v.compliance.dpa_url);
+  row("Subprocessors", v.compliance.subprocessors_url);
+  row("Trust center", v.compliance.trust_center_url);
+  if (v.domains.length > 0) {
+    console.log("");
+    console.log(pc.dim(`    Hosts: ${v.domains.join(", ")}`));
+  }
+}
+
+export const explainCommand = new Command("explain")
+  .description("Show what the catalog knows about a vendor: category, status page, SLA, and its privacy policy, DPA, subprocessors, and trust center.")
+  .argument("<vendor>", "Vendor slug, display name, or a host or URL it serves")
+  .option("-f, --format <format>", "Output format: text or json", "text")
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .action(async (vendor: string, opts: ExplainCommandOpts) => {
+    if (opts.format !== "text" && opts.format !== "json") {
+      console.error(`Error: Invalid format "${opts.format}". Use "text" or "json".`);
+      process.exitCode = 2;
+      return;
+    }
+
+    let explanation: VendorExplanation | null;
+    try {
+      const { registry } = await loadRuntimeCatalog(opts);
+      explanation = explainVendor(vendor, registry);
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 2;
+      return;
+    }
+    if (!explanation) {
+      console.error(`Error: No catalog vendor matches "${vendor}".`);
+      process.exitCode = 1;
+      return;
+    }
+
+    if (opts.format === "json") {
+      process.stdout.write(JSON.stringify(explanation, null, 2) + "\n");
+    } else {
+      printExplanation(explanation);
+    }
+  });
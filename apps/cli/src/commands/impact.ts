@@ -0,0 +1,124 @@
+// apps/cli/src/commands/impact.ts — `thirdwatch impact --vendor` offboarding scope across the inventory
+import { Command } from "commander";
+import { stat } from "node:fs/promises";
+import { basename, relative, resolve } from "node:path";
+import pc from "picocolors";
+import { applyVendorAliases, computeImpact, groupByService, loadVendorAliases, resolveVendorSlug } from "@thirdwatch/core";
+import type { ImpactReport, MigrationScope } from "@thirdwatch/core";
+import type { TDM } from "@thirdwatch/tdm";
+import { loadRuntimeCatalog } from "../runtime-output.js";
+import { findTDMFiles, readTDM } from "../tdm-file.js";
+
+interface ImpactCommandOpts {
+  vendor: string;
+  format: string;
+  sites?: boolean;
+  aliases?: string;
+  catalogVersion?: string;
+  catalogBundle?: string;
+}
+
+const SCOPE_COLOR: Record<MigrationScope, (s: string) => string> = {
+  large: pc.red,
+  medium: pc.yellow,
+  small: pc.green,
+};
+
+function printImpact(report: ImpactReport, sites: boolean): void {
+  console.log("");
+  if (report.total_repositories === 0) {
+    console.log(pc.green(`  No repository depends on ${report.display_name}.`));
+    return;
+  }
+  console.log(
+    pc.bold(`  Offboarding ${report.display_name}`) +
+      (report.category ? pc.dim(` (${report.category})`) : "") +
+      `  ${SCOPE_COLOR[report.scope](`${report.scope} migration`)}`,
+  );
+  console.log(
+    pc.dim(
+      `  ${report.total_call_sites} call sites in ${report.total_files} files across ${report.total_repositories} repositories`,
+    ),
+  );
+  const kinds = Object.entries(report.kinds).map(([kind, n]) => `${kind} ×${n}`);
+  if (kinds.length > 0) console.log(pc.dim(`  By kind: ${kinds.join(", ")}`));
+  if (report.intents.length > 0) {
+    console.log(pc.dim(`  SDK usage: ${report.intents.map((i) => `${i.intent} ×${i.call_sites}`).join(", ")}`));
+  }
+  console.log("");
+
+  for (const repo of report.repositories) {
+    const packages = repo.sdk_packages.length > 0 ? pc.dim(`  ${repo.sdk_packages.join(", ")}`) : "";
+    console.log(`    ${repo.repository.padEnd(32)} ${String(repo.call_sites).padStart(4)} sites  ${String(repo.files.length).padStart(3)} files${packages}`);
+    if (!sites) continue;
+    for (const site of repo.sites) {
+      const usage = site.usage ? pc.dim(`  ${site.usage}`) : "";
+      console.log(pc.dim(`      ${site.file}:${site.line}`) + `  ${site.kind}${usage}`);
+    }
+  }
+
+  if (report.alternatives.length > 0) {
+    console.log("");
+    console.log(
+      pc.bold(`  Already in use for ${report.category}: `) +
+        report.alternatives.map((a) => `${a.vendor} (${a.repositories} repos)`).join(", "),
+    );
+  }
+}
+
+export const impactCommand = new Command("impact")
+  .description(
+    "List every repository, file, and call site depending on a vendor, with an estimate of the migration scope for offboarding.",
+  )
+  .argument("<paths...>", "TDM files, or directories of them searched recursively; files with the same repository are one service")
+  .requiredOption("--vendor <vendor>", "Vendor slug or display name, e.g. twilio")
+  .option("-f, --format <format>", "Output format: text or json", "text")
+  .option("--sites", "List each call site under its repository (text output)")
+  .option("--aliases <file>", "Vendor aliases: a .thirdwatch.yml with vendor_aliases, or a YAML mapping of slug to names")
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .action(async (paths: string[], opts: ImpactCommandOpts) => {
+    if (opts.format !== "text" && opts.format !== "json") {
+      console.error(`Error: Invalid format "${opts.format}". Use "text" or "json".`);
+      process.exitCode = 2;
+      return;
+    }
+
+    let report: ImpactReport;
+    try {
+      const tdms: TDM[] = [];
+      const names: string[] = [];
+      for (const path of paths) {
+        const root = resolve(path);
+        if (!(await stat(root)).isDirectory()) {
+          tdms.push(await readTDM(root));
+          names.push(basename(path).replace(/\.(json|ya?ml)$/, ""));
+          continue;
+        }
+        for (const file of await findTDMFiles(root)) {
+          try {
+            tdms.push(await readTDM(file));
+            names.push(relative(root, file).replace(/\.(json|ya?ml)$/, ""));
+          } catch {
+            console.error(pc.dim(`Skipping ${relative(root, file)}: not a TDM`));
+          }
+        }
+      }
+      if (tdms.length === 0) throw new Error(`Error: No TDM files found in ${paths.join(", ")}.`);
+
+      const catalog = await loadRuntimeCatalog(opts);
+      const registry = opts.aliases ? applyVendorAliases(catalog.registry, await loadVendorAliases(resolve(opts.aliases))) : catalog.registry;
+      const inventory = groupByService(tdms, (i) => names[i]!);
+      report = computeImpact(inventory, resolveVendorSlug(opts.vendor, registry), registry);
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 2;
+      return;
+    }
+
+    if (opts.format === "json") {
+      process.stdout.write(JSON.stringify(report, null, 2) + "\n");
+    } else {
+      printImpact(report, opts.sites === true);
+    }
+  });
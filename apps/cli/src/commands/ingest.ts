@@ -0,0 +1,314 @@
+// apps/cli/src/commands/ingest.ts — `thirdwatch ingest` reports from existing logs, traces, and SBOMs
+import { Command } from "commander";
+import { readFile } from "node:fs/promises";
+import { relative, resolve } from "node:path";
+import {
+  readLogLines,
+  parseDnsLogLine,
+  detectDnsLogFormat,
+  createFlowLogParser,
+  detectFlowLogFormat,
+  spanObservations,
+  startOtlpReceiver,
+  buildRuntimeTDM,
+  buildSbomTDM,
+  DNS_LOG_FORMATS,
+  FLOW_LOG_FORMATS,
+} from "@thirdwatch/core";
+import type { DnsLogFormat, FlowLogFormat, FlowLogParser, RuntimeObservation } from "@thirdwatch/core";
+import { createSpinner } from "../ui/spinner.js";
+import { printRuntimeSummary } from "../output/runtime-summary.js";
+import { printSummaryTable } from "../output/summary.js";
+import { loadRuntimeCatalog, checkRuntimeOutput, writeRuntimeReport } from "../runtime-output.js";
+import type { RuntimeOutputOpts } from "../runtime-output.js";
+
+interface DnsCommandOpts extends RuntimeOutputOpts {
+  logFormat: string;
+  quiet?: boolean;
+}
+
+const dnsCommand = new Command("dns")
+  .description("Build a runtime report from DNS query logs (CoreDNS, Route 53 Resolver, dnstap).")
+  .argument("<files...>", "Log files (.gz is decompressed)")
+  .option("--log-format <format>", `Log format: ${DNS_LOG_FORMATS.join(", ")}, or auto`, "auto")
+  .option("-o, --output <file>", "Output file path (use - for stdout)", "./thirdwatch-runtime.json")
+  .option("-f, --format <format>", "Output format: json or yaml", "json")
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .option("--quiet", "Suppress all output except the report")
+  .action(async (files: string[], opts: DnsCommandOpts) => {
+    const quiet = opts.quiet ?? false;
+    if (opts.logFormat !== "auto" && !(DNS_LOG_FORMATS as string[]).includes(opts.logFormat)) {
+      console.error(`Error: Invalid log format "${opts.logFormat}". Use ${DNS_LOG_FORMATS.join(", ")}, or auto.`);
+      process.exitCode = 2;
+      return;
+    }
+    const outputPath = checkRuntimeOutput(opts);
+    if (outputPath === null) return;
+
+    const s = createSpinner();
+    try {
+      const { registry, catalogVersion } = await loadRuntimeCatalog(opts);
+      if (!quiet) s.start(`Reading ${files.length} DNS log file${files.length === 1 ? "" : "s"}…`);
+
+      const startMs = Date.now();
+      const observations: RuntimeObservation[] = [];
+      let lines = 0;
+      for (const file of files) {
+        // Auto-detection is per file: fleets often mix resolver types
+        let format: DnsLogFormat | null = opts.logFormat === "auto" ? null : (opts.logFormat as DnsLogFormat);
+        await readLogLines(resolve(file), (line) => {
+          lines++;
+          format ??= detectDnsLogFormat(line);
+          if (!format) return;
+          const obs = parseDnsLogLine(line, format);
+          if (obs) observations.push(obs);
+        });
+      }
+
+      const tdm = buildRuntimeTDM(observations, registry, {
+        duration: Date.now() - startMs,
+        ...(catalogVersion ? { catalogVersion } : {}),
+      });
+      if (!quiet) s.succeed(`Parsed ${observations.length} lookups from ${lines} lines`);
+
+      await writeRuntimeReport(tdm, opts.format, outputPath);
+      if (!quiet && outputPath) {
+        printRuntimeSummary(tdm, "thirdwatch ingest dns");
+        console.log(`\n✓ Runtime report written to ${outputPath}`);
+      }
+      process.exitCode = 0;
+    } catch (err) {
+      if (!quiet) s.fail("Ingestion failed");
+      console.error(`\n${err instanceof Error ? err.message : String(err)}`);
+      process.exitCode = 1;
+    }
+  });
+
+interface FlowCommandOpts extends RuntimeOutputOpts {
+  logFormat: string;
+  includePrivate?: boolean;
+  quiet?: boolean;
+}
+
+const flowCommand = new Command("flow")
+  .description(
+    "Build a runtime report from flow, firewall, or egress-gateway logs (VPC Flow Logs, Suricata, iptables, Envoy).",
+  )
+  .argument("<files...>", "Log files (.gz is decompressed)")
+  .option("--log-format <format>", `Log format: ${FLOW_LOG_FORMATS.join(", ")}, or auto`, "auto")
+  .option("--include-private", "Keep flows to private and loopback destinations")
+  .option("-o, --output <file>", "Output file path (use - for stdout)", "./thirdwatch-runtime.json")
+  .option("-f, --format <format>", "Output format: json or yaml", "json")
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .option("--quiet", "Suppress all output except the report")
+  .action(async (files: string[], opts: FlowCommandOpts) => {
+    const quiet = opts.quiet ?? false;
+    if (opts.logFormat !== "auto" && !(FLOW_LOG_FORMATS as string[]).includes(opts.logFormat)) {
+      console.error(`Error: Invalid log format "${opts.logFormat}". Use ${FLOW_LOG_FORMATS.join(", ")}, or auto.`);
+      process.exitCode = 2;
+      return;
+    }
+    const outputPath = checkRuntimeOutput(opts);
+    if (outputPath === null) return;
+
+    const s = createSpinner();
+    try {
+      const { registry, catalogVersion } = await loadRuntimeCatalog(opts);
+      if (!quiet) s.start(`Reading ${files.length} flow log file${files.length === 1 ? "" : "s"}…`);
+
+      const startMs = Date.now();
+      const observations: RuntimeObservation[] = [];
+      let lines = 0;
+      for (const file of files) {
+        // Parsers keep per-file state (VPC header, Suricata flow IDs)
+        let parse: FlowLogParser | null = null;
+        const create = (format: FlowLogFormat) =>
+          createFlowLogParser(format, { includePrivate: opts.includePrivate === true });
+        if (opts.logFormat !== "auto") parse = create(opts.logFormat as FlowLogFormat);
+        await readLogLines(resolve(file), (line) => {
+          lines++;
+          if (!parse) {
+            const detected = detectFlowLogFormat(line);
+            if (!detected) return;
+            parse = create(detected);
+          }
+          const obs = parse(line);
+          if (obs) observations.push(obs);
+        });
+      }
+
+      const tdm = buildRuntimeTDM(observations, registry, {
+        duration: Date.now() - startMs,
+        ...(catalogVersion ? { catalogVersion } : {}),
+      });
+      if (!quiet) s.succeed(`Parsed ${observations.length} outbound flows from ${lines} lines`);
+
+      await writeRuntimeReport(tdm, opts.format, outputPath);
+      if (!quiet && outputPath) {
+        printRuntimeSummary(tdm, "thirdwatch ingest flow");
+        console.log(`\n✓ Runtime report written to ${outputPath}`);
+      }
+      process.exitCode = 0;
+    } catch (err) {
+      if (!quiet) s.fail("Ingestion failed");
+      console.error(`\n${err instanceof Error ? err.message : String(err)}`);
+      process.exitCode = 1;
+    }
+  });
+
+interface OtelCommandOpts extends RuntimeOutputOpts {
+  listen?: string;
+  host: string;
+  duration?: string;
+  quiet?: boolean;
+}
+
+/** Collect observations from OTLP/HTTP pushes until --duration or Ctrl-C */
+async function receiveOtlp(opts: OtelCommandOpts, quiet: boolean): Promise<RuntimeObservation[] | null> {
+  const port = Number(opts.listen);
+  if (!Number.isInteger(port) || port < 0 || port > 65535) {
+    console.error(`Error: Invalid port "${opts.listen}".`);
+    process.exitCode = 2;
+    return null;
+  }
+  const seconds = opts.duration !== undefined ? Number(opts.duration) : undefined;
+  if (seconds !== undefined && (!Number.isFinite(seconds) || seconds <= 0)) {
+    console.error(`Error: Invalid duration "${opts.duration}".`);
+    process.exitCode = 2;
+    return null;
+  }
+
+  const receiver = await startOtlpReceiver({ port, host: opts.host });
+  if (!quiet) {
+    console.error(`thirdwatch OTLP receiver listening on http://${opts.host}:${receiver.port}/v1/traces`);
+  }
+  await new Promise<void>((resolveFn) => {
+    const timer = seconds !== undefined ? setTimeout(resolveFn, seconds * 1000) : undefined;
+    process.once("SIGINT", () => {
+      if (timer) clearTimeout(timer);
+      resolveFn();
+    });
+  });
+  await receiver.close();
+  return receiver.observations;
+}
+
+const otelCommand = new Command("otel")
+  .description("Build a runtime report from OpenTelemetry client spans (OTLP/JSON files or an OTLP/HTTP receiver).")
+  .argument("[files...]", "OTLP/JSON files from the collector file exporter (.gz is decompressed)")
+  .option("--listen <port>", "Receive OTLP/HTTP JSON on this port instead of reading files (e.g. 4318)")
+  .option("--host <address>", "Receiver listen address", "127.0.0.1")
+  .option("-d, --duration <seconds>", "With --listen: stop after this many seconds (default: until Ctrl-C)")
+  .option("-o, --output <file>", "Output file path (use - for stdout)", "./thirdwatch-runtime.json")
+  .option("-f, --format <format>", "Output format: json or yaml", "json")
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .option("--quiet", "Suppress all output except the report")
+  .action(async (files: string[], opts: OtelCommandOpts) => {
+    const quiet = opts.quiet ?? false;
+    if ((files.length === 0) === (opts.listen === undefined)) {
+      console.error("Error: Pass OTLP files or --listen <port>, not both.");
+      process.exitCode = 2;
+      return;
+    }
+    const outputPath = checkRuntimeOutput(opts);
+    if (outputPath === null) return;
+
+    try {
+      const { registry, catalogVersion } = await loadRuntimeCatalog(opts);
+      const startMs = Date.now();
+      let observations: RuntimeObservation[] = [];
+      let invalid = 0;
+
+      if (opts.listen !== undefined) {
+        const received = await receiveOtlp(opts, quiet);
+        if (received === null) return;
+        observations = received;
+      } else {
+        for (const file of files) {
+          await readLogLines(resolve(file), (line) => {
+            let request: unknown;
+            try {
+              request = JSON.parse(line);
+            } catch {
+              invalid++;
+              return;
+            }
+            observations.push(...spanObservations(request));
+          });
+        }
+      }
+
+      const tdm = buildRuntimeTDM(observations, registry, {
+        duration: Date.now() - startMs,
+        ...(catalogVersion ? { catalogVersion } : {}),
+      });
+      if (!quiet && invalid > 0) {
+        console.error(`Skipped ${invalid} lines that were not OTLP/JSON (one ExportTraceServiceRequest per line expected)`);
+      }
+
+      await writeRuntimeReport(tdm, opts.format, outputPath);
+      if (!quiet && outputPath) {
+        printRuntimeSummary(tdm, "thirdwatch ingest otel");
+        console.log(`\n✓ Runtime report written to ${outputPath} (${observations.length} client spans)`);
+      }
+      process.exitCode = 0;
+    } catch (err) {
+      console.error(`\n${err instanceof Error ? err.message : String(err)}`);
+      process.exitCode = 1;
+    }
+  });
+
+interface SbomCommandOpts extends RuntimeOutputOpts {
+  service?: string;
+  quiet?: boolean;
+}
+
+const sbomCommand = new Command("sbom")
+  .description("Build a TDM from a CycloneDX or SPDX SBOM, for services you can't scan directly.")
+  .argument("<file>", "SBOM: CycloneDX JSON or XML, SPDX JSON or tag-value")
+  .option("--service <name>", "Service name for the TDM (default: the component the SBOM describes)")
+  .option("-o, --output <file>", "Output file path (use - for stdout)", "./thirdwatch.json")
+  .option("-f, --format <format>", "Output format: json or yaml", "json")
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .option("--quiet", "Suppress all output except the TDM")
+  .action(async (file: string, opts: SbomCommandOpts) => {
+    const quiet = opts.quiet ?? false;
+    const outputPath = checkRuntimeOutput(opts);
+    if (outputPath === null) return;
+
+    try {
+      const { registry, catalogVersion } = await loadRuntimeCatalog(opts);
+      const startMs = Date.now();
+      const text = await readFile(resolve(file), "utf-8");
+      const { tdm, sbom } = buildSbomTDM(text, registry, {
+        file: relative(process.cwd(), resolve(file)),
+        ...(opts.service ? { service: opts.service } : {}),
+        duration: Date.now() - startMs,
+        ...(catalogVersion ? { catalogVersion } : {}),
+      });
+      if (!quiet && sbom.skipped > 0) {
+        console.error(`Skipped ${sbom.skipped} component(s) without a package URL (purl); they can't be matched to the catalog`);
+      }
+
+      await writeRuntimeReport(tdm, opts.format, outputPath);
+      if (!quiet && outputPath) {
+        printSummaryTable(tdm, 1);
+        console.log(`\n✓ TDM written to ${outputPath} (${sbom.components.length} components from a ${sbom.format} SBOM)`);
+      }
+      process.exitCode = 0;
+    } catch (err) {
+      console.error(`\n${err instanceof Error ? err.message : String(err)}`);
+      process.exitCode = 1;
+    }
+  });
+
+export const ingestCommand = new Command("ingest")
+  .description("Build reports from logs, traces, and SBOMs you already collect.")
+  .addCommand(dnsCommand)
+  .addCommand(flowCommand)
+  .addCommand(otelCommand)
+  .addCommand(sbomCommand);
@@ -0,0 +1,96 @@
+// apps/cli/src/commands/outdated.ts — `thirdwatch outdated` vendor SDK versions against their latest releases
+import { Command } from "commander";
+import pc from "picocolors";
+import { buildCurrencyReport } from "@thirdwatch/core";
+import type { CurrencyPolicy, CurrencyReport } from "@thirdwatch/core";
+import { loadRuntimeCatalog } from "../runtime-output.js";
+import { readTDM } from "../tdm-file.js";
+
+interface OutdatedCommandOpts {
+  format: string;
+  maxBehind: string[];
+  catalogVersion?: string;
+  catalogBundle?: string;
+}
+
+function collect(value: string, previous: string[]): string[] {
+  return [...previous, value];
+}
+
+function printCurrency(report: CurrencyReport): void {
+  console.log("");
+  if (report.sdks.length === 0) {
+    console.log(pc.bold("  No vendor SDKs in the scanned manifests"));
+    return;
+  }
+  console.log(pc.bold(`  🔧 Vendor SDK versions (${report.sdks.length})`));
+  const violating = new Set(report.violations.map((v) => v.sdk));
+  for (const sdk of report.sdks) {
+    const behind =
+      sdk.majors_behind === null
+        ? pc.gray("latest unknown")
+        : sdk.majors_behind > 0
+          ? `${sdk.majors_behind} major${sdk.majors_behind === 1 ? "" : "s"} behind`
+          : sdk.minors_behind
+            ? `${sdk.minors_behind} minor${sdk.minors_behind === 1 ? "" : "s"} behind`
+            : "current";
+    const dot = violating.has(sdk) ? pc.red("●") : sdk.majors_behind ? pc.yellow("●") : pc.green("●");
+    console.log(
+      `    ${dot} ${sdk.package.padEnd(40)} ${sdk.current_version.padEnd(10)} → ${(sdk.latest_version ?? "?").padEnd(10)} ${behind}` +
+        pc.dim(`  ${sdk.provider}${sdk.category ? ` (${sdk.category})` : ""}`),
+    );
+  }
+  for (const { sdk, policy } of report.violations) {
+    console.error(
+      pc.red(`\n  ${sdk.package} is ${sdk.majors_behind} majors behind; policy "${policy.match}" allows ${policy.max_majors_behind}.`),
+    );
+  }
+}
+
+export const outdatedCommand = new Command("outdated")
+  .description("Compare each vendor SDK's pinned version with its latest release, and enforce how far behind SDKs may fall.")
+  .argument("<tdm>", "TDM from `thirdwatch scan`")
+  .option("-f, --format <format>", "Output format: text or json", "text")
+  .option(
+    "--max-behind <match=majors>",
+    "Exit 1 when SDKs of a provider, category, or * are more major versions behind, e.g. payments=2 (repeatable)",
+    collect,
+    [],
+  )
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .action(async (file: string, opts: OutdatedCommandOpts) => {
+    if (opts.format !== "text" && opts.format !== "json") {
+      console.error(`Error: Invalid format "${opts.format}". Use "text" or "json".`);
+      process.exitCode = 2;
+      return;
+    }
+    const policies: CurrencyPolicy[] = [];
+    for (const rule of opts.maxBehind) {
+      const [match, value] = rule.split("=");
+      const max = Number(value);
+      if (!match || value === undefined || !Number.isInteger(max) || max < 0) {
+        console.error(`Error: Invalid --max-behind "${rule}". Use <provider|category|*>=<majors>, e.g. payments=2.`);
+        process.exitCode = 2;
+        return;
+      }
+      policies.push({ match, max_majors_behind: max });
+    }
+
+    let report: CurrencyReport;
+    try {
+      const { registry } = await loadRuntimeCatalog(opts);
+      report = await buildCurrencyReport(await readTDM(file), registry, { policies });
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 2;
+      return;
+    }
+
+    if (opts.format === "json") {
+      process.stdout.write(JSON.stringify(report, null, 2) + "\n");
+    } else {
+      printCurrency(report);
+    }
+    process.exitCode = report.violations.length > 0 ? 1 : 0;
+  });
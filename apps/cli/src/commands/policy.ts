@@ -0,0 +1,80 @@
+// apps/cli/src/commands/policy.ts — `thirdwatch policy` enforcement artifacts from the approved-vendor list
+import { Command } from "commander";
+import { writeFile } from "node:fs/promises";
+import { resolve } from "node:path";
+import pc from "picocolors";
+import { buildOpaBundle, buildVendorPolicy, renderTerraformVars } from "@thirdwatch/core";
+import type { PolicyFormat } from "@thirdwatch/core";
+import { fetchApproved, readApproved } from "../approved-list.js";
+import { loadRuntimeCatalog } from "../runtime-output.js";
+import { DEFAULT_API_URL } from "./push.js";
+
+interface PolicyCommandOpts {
+  approved?: string;
+  token?: string;
+  apiUrl?: string;
+  format: string;
+  output?: string;
+  catalogVersion?: string;
+  catalogBundle?: string;
+}
+
+const FORMATS: PolicyFormat[] = ["opa", "terraform", "json"];
+
+export const policyCommand = new Command("policy")
+  .description(
+    "Generate egress and code-review policy from the approved-vendor list: an OPA bundle, Terraform variables, or JSON.",
+  )
+  .option("--approved <vendors>", "Approved vendor slugs, comma-separated, or a file with one per line (default: the list on Thirdwatch cloud)")
+  .option("--token <token>", "API token for fetching the approved list (or set THIRDWATCH_TOKEN env var)")
+  .option("--api-url <url>", "API base URL (or set THIRDWATCH_API_URL env var)")
+  .option("-f, --format <format>", "Output format: opa, terraform, or json", "opa")
+  .option("-o, --output <file>", "Write to a file (opa default: thirdwatch-policy.tar.gz; others: stdout)")
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .action(async (opts: PolicyCommandOpts) => {
+    if (!(FORMATS as string[]).includes(opts.format)) {
+      console.error(`Error: Invalid format "${opts.format}". Use one of: ${FORMATS.join(", ")}.`);
+      process.exitCode = 2;
+      return;
+    }
+    const token = opts.token ?? process.env["THIRDWATCH_TOKEN"];
+    if (!opts.approved && !token) {
+      console.error("Error: Pass --approved, or an API token (--token or THIRDWATCH_TOKEN) to use the approved list on Thirdwatch cloud.");
+      process.exitCode = 2;
+      return;
+    }
+
+    try {
+      const approved = opts.approved
+        ? await readApproved(opts.approved)
+        : await fetchApproved(opts.apiUrl ?? process.env["THIRDWATCH_API_URL"] ?? DEFAULT_API_URL, token!);
+      const { registry } = await loadRuntimeCatalog(opts);
+      const policy = buildVendorPolicy(approved, registry);
+      for (const slug of policy.unknown_vendors) {
+        console.error(pc.yellow(`⚠ ${slug} is not in the vendor catalog; no egress is allowed for it`));
+      }
+
+      const output =
+        opts.format === "opa"
+          ? buildOpaBundle(policy)
+          : opts.format === "terraform"
+            ? renderTerraformVars(policy)
+            : JSON.stringify(policy, null, 2) + "\n";
+      const file = opts.output ?? (opts.format === "opa" ? "thirdwatch-policy.tar.gz" : undefined);
+      if (file) {
+        await writeFile(resolve(file), output);
+        console.error(
+          pc.green(
+            `✓ ${policy.approved_vendors.length} approved vendors, ${policy.allowed_egress_domains.length} domains, ` +
+              `${policy.allowed_egress_cidrs.length} CIDR blocks → ${file}`,
+          ),
+        );
+      } else {
+        process.stdout.write(output);
+      }
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 1;
+    }
+  });
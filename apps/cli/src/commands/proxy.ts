@@ -0,0 +1,113 @@
+// apps/cli/src/commands/proxy.ts — `thirdwatch proxy` recording forward proxy
+import { Command } from "commander";
+import { hostname } from "node:os";
+import { startRecordingProxy, buildRuntimeTDM } from "@thirdwatch/core";
+import { withMeteringOptions, startMetering } from "../metering.js";
+import type { MeteringOpts } from "../metering.js";
+import { printRuntimeSummary } from "../output/runtime-summary.js";
+import { loadRuntimeCatalog, checkRuntimeOutput, writeRuntimeReport } from "../runtime-output.js";
+import type { RuntimeOutputOpts } from "../runtime-output.js";
+
+interface ProxyCommandOpts extends RuntimeOutputOpts, MeteringOpts {
+  port: string;
+  host: string;
+  duration?: string;
+  service?: string;
+  interval: string;
+  verbose?: boolean;
+  quiet?: boolean;
+}
+
+export const proxyCommand = withMeteringOptions(
+  new Command("proxy")
+    .description(
+      "Run an HTTP(S) forward proxy that records outbound hosts and paths (no bodies) as a runtime report.",
+    )
+    .option("-p, --port <port>", "Listen port", "8080")
+    .option("--host <address>", "Listen address (use 0.0.0.0 to accept remote clients)", "127.0.0.1")
+    .option("-o, --output <file>", "Output file path (use - for stdout)", "./thirdwatch-runtime.json")
+    .option("-f, --format <format>", "Output format: json or yaml", "json")
+    .option("-d, --duration <seconds>", "Stop after this many seconds (default: run until Ctrl-C)")
+    .option("--service <name>", "Service name for metering (default: hostname)")
+    .option("--interval <seconds>", "Seconds per metering window for egress alerts", "60")
+    .option("--catalog-version <version>", "Pin the vendor catalog version")
+    .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+    .option("--verbose", "Log each recorded request")
+    .option("--quiet", "Suppress all output except the report"),
+)
+  .action(async (opts: ProxyCommandOpts) => {
+    const quiet = opts.quiet ?? false;
+    const outputPath = checkRuntimeOutput(opts);
+    if (outputPath === null) return;
+
+    const port = Number(opts.port);
+    if (!Number.isInteger(port) || port < 0 || port > 65535) {
+      console.error(`Error: Invalid port "${opts.port}".`);
+      process.exitCode = 2;
+      return;
+    }
+    const seconds = opts.duration !== undefined ? Number(opts.duration) : undefined;
+    if (seconds !== undefined && (!Number.isFinite(seconds) || seconds <= 0)) {
+      console.error(`Error: Invalid duration "${opts.duration}".`);
+      process.exitCode = 2;
+      return;
+    }
+    const intervalSeconds = Number(opts.interval);
+    if (!Number.isFinite(intervalSeconds) || intervalSeconds < 10) {
+      console.error(`Error: Invalid interval "${opts.interval}" (minimum 10 seconds).`);
+      process.exitCode = 2;
+      return;
+    }
+
+    try {
+      const { registry, catalogVersion } = await loadRuntimeCatalog(opts);
+      const metering = await startMetering(opts, registry, {
+        defaultService: opts.service ?? hostname(),
+        quiet,
+      });
+      if (metering === undefined) return;
+
+      const startMs = Date.now();
+      const proxy = await startRecordingProxy({
+        port,
+        host: opts.host,
+        onObservation: (o) => {
+          metering?.meter.record(o);
+          if (opts.verbose) console.error(`  ${o.method ?? "CONNECT"} ${o.host ?? o.ip}:${o.port}${o.path ?? ""}`);
+        },
+        ...(metering ? { onTraffic: (o, bytes) => metering.meter.addBytes(o, bytes) } : {}),
+      });
+      const windowTimer = metering
+        ? setInterval(() => void metering.closeWindow(), intervalSeconds * 1000)
+        : undefined;
+      if (!quiet) {
+        console.error(`thirdwatch proxy listening on http://${opts.host}:${proxy.port}`);
+        console.error(`  export HTTP_PROXY=http://${opts.host}:${proxy.port} HTTPS_PROXY=http://${opts.host}:${proxy.port}`);
+      }
+
+      await new Promise<void>((resolveFn) => {
+        const timer = seconds !== undefined ? setTimeout(resolveFn, seconds * 1000) : undefined;
+        process.once("SIGINT", () => {
+          if (timer) clearTimeout(timer);
+          resolveFn();
+        });
+      });
+      await proxy.close();
+      if (windowTimer) clearInterval(windowTimer);
+      await metering?.stop();
+
+      const tdm = buildRuntimeTDM(proxy.observations, registry, {
+        duration: Date.now() - startMs,
+        ...(catalogVersion ? { catalogVersion } : {}),
+      });
+      await writeRuntimeReport(tdm, opts.format, outputPath);
+      if (!quiet && outputPath) {
+        printRuntimeSummary(tdm, "thirdwatch proxy");
+        console.log(`\n✓ Runtime report written to ${outputPath}`);
+      }
+      process.exitCode = 0;
+    } catch (err) {
+      console.error(`\n${err instanceof Error ? err.message : String(err)}`);
+      process.exitCode = 1;
+    }
+  });
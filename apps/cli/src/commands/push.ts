@@ -3,11 +3,38 @@ import { readFile } from "node:fs/promises";
 import { resolve } from "node:path";
 import { createSpinner } from "../ui/spinner.js";
 
-const DEFAULT_API_URL = "https://api.thirdwatch.dev";
+export const DEFAULT_API_URL = "https://api.thirdwatch.dev";
+
+export interface UploadResult {
+  tdmId: string;
+  dependenciesRegistered: number;
+  vendorChanges?: number;
+}
+
+/** POST a TDM to the cloud API, which stores it and fires change notifications */
+export async function uploadTDM(
+  tdm: unknown,
+  opts: { apiUrl: string; token: string; commit?: string },
+): Promise<UploadResult> {
+  const response = await fetch(`${opts.apiUrl}/api/v1/tdm`, {
+    method: "POST",
+    headers: {
+      "Content-Type": "application/json",
+      "x-api-key": opts.token,
+      ...(opts.commit ? { "x-thirdwatch-commit": opts.commit } : {}),
+    },
+    body: JSON.stringify(tdm),
+  });
+  if (!response.ok) {
+    throw new Error(`Upload failed (HTTP ${response.status})\n${await response.text()}`);
+  }
+  return (await response.json()) as UploadResult;
+}
 
 interface PushCommandOpts {
   token?: string;
   apiUrl?: string;
+  commit?: string;
 }
 
 export const pushCommand = new Command("push")
@@ -23,6 +50,10 @@ export const pushCommand = new Command("push")
     "--api-url <url>",
     "API base URL (or set THIRDWATCH_API_URL env var)",
   )
+  .option(
+    "--commit <sha>",
+    "Commit the TDM was scanned at, recorded in the vendor change feed (default: GITHUB_SHA or CI_COMMIT_SHA)",
+  )
   .action(async (file: string, opts: PushCommandOpts) => {
     const token =
       opts.token ?? process.env["THIRDWATCH_TOKEN"];
@@ -38,6 +69,8 @@ export const pushCommand = new Command("push")
       opts.apiUrl ??
       process.env["THIRDWATCH_API_URL"] ??
       DEFAULT_API_URL;
+    const commit =
+      opts.commit ?? process.env["GITHUB_SHA"] ?? process.env["CI_COMMIT_SHA"];
 
     const filePath = resolve(file);
     const s = createSpinner();
@@ -48,27 +81,7 @@ export const pushCommand = new Command("push")
       const tdm: unknown = JSON.parse(content);
 
       s.start(`Uploading TDM to ${apiUrl}…`);
-      const response = await fetch(`${apiUrl}/api/v1/tdm`, {
-        method: "POST",
-        headers: {
-          "Content-Type": "application/json",
-          "x-api-key": token,
-        },
-        body: JSON.stringify(tdm),
-      });
-
-      if (!response.ok) {
-        const body = await response.text();
-        s.fail(`Upload failed (HTTP ${response.status})`);
-        console.error(body);
-        process.exitCode = 1;
-        return;
-      }
-
-      const result = (await response.json()) as {
-        tdmId: string;
-        dependenciesRegistered: number;
-      };
+      const result = await uploadTDM(tdm, { apiUrl, token, ...(commit ? { commit } : {}) });
 
       s.succeed(
         `Uploaded — ${result.dependenciesRegistered} dependencies registered for monitoring`,
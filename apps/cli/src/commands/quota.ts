@@ -0,0 +1,131 @@
+// apps/cli/src/commands/quota.ts — `thirdwatch quota` rate-limit headroom from runtime traffic
+import { Command } from "commander";
+import pc from "picocolors";
+import { buildQuotaReport, DEFAULT_QUOTA_THRESHOLD } from "@thirdwatch/core";
+import type { QuotaReport } from "@thirdwatch/core";
+import { loadRuntimeCatalog } from "../runtime-output.js";
+import { readTDM } from "../tdm-file.js";
+
+interface QuotaCommandOpts {
+  format: string;
+  threshold?: string;
+  plan: string[];
+  assume: string[];
+  catalogVersion?: string;
+  catalogBundle?: string;
+}
+
+function collect(value: string, previous: string[]): string[] {
+  return [...previous, value];
+}
+
+/** "80%", "0.8" → 0.8 */
+function parseThreshold(value: string): number | null {
+  const n = value.endsWith("%") ? Number(value.slice(0, -1)) / 100 : Number(value);
+  return Number.isFinite(n) && n > 0 ? n : null;
+}
+
+/** "600/60" → 600 requests per 60 seconds; "100/1s", "1000/1m", "5000/1h" */
+function parseLimit(value: string): { requests: number; window_seconds: number } | null {
+  const m = value.match(/^(\d+)\/(\d+)([smh]?)$/);
+  if (!m) return null;
+  const requests = Number(m[1]);
+  const window = Number(m[2]) * (m[3] === "h" ? 3600 : m[3] === "m" ? 60 : 1);
+  return requests > 0 && window > 0 ? { requests, window_seconds: window } : null;
+}
+
+function windowLabel(seconds: number): string {
+  if (seconds === 1) return "s";
+  if (seconds % 3600 === 0) return seconds === 3600 ? "h" : `${seconds / 3600}h`;
+  if (seconds % 60 === 0) return seconds === 60 ? "min" : `${seconds / 60}min`;
+  return `${seconds}s`;
+}
+
+function printQuota(report: QuotaReport): void {
+  console.log("");
+  if (report.usages.length === 0) {
+    console.log(pc.bold("  No runtime traffic to vendors with a known rate limit"));
+  } else {
+    console.log(pc.bold(`  Rate-limit utilization`) + pc.dim(` (averaged over each observation span; flagged at ${Math.round(report.threshold * 100)}%)`));
+    console.log("");
+    for (const u of report.usages) {
+      const dot = u.status === "over" ? pc.red("●") : u.status === "near" ? pc.yellow("●") : pc.green("●");
+      const pct = `${Math.round(u.utilization * 100)}%`;
+      const used = `${u.requests_per_window}/${u.requests} per ${windowLabel(u.window_seconds)}`;
+      const notes = [u.plan, u.scope, u.source === "assumed" ? "assumed" : ""].filter(Boolean).join(", ");
+      console.log(`    ${dot} ${u.display_name.padEnd(18)} ${pct.padStart(5)}  ${used.padEnd(24)} ${pc.dim(notes)}`);
+    }
+  }
+  if (report.unknown.length > 0) {
+    console.log("");
+    console.log(pc.dim(`  No published limit for: ${report.unknown.join(", ")}. Add contracted limits with --assume.`));
+  }
+}
+
+export const quotaCommand = new Command("quota")
+  .description(
+    "Rate-limit utilization of vendor integrations, from runtime traffic and the vendors' published limits.",
+  )
+  .argument("<tdm...>", "Runtime TDMs (agent or proxy reports); pass every instance of a service together")
+  .option("-f, --format <format>", "Output format: text or json", "text")
+  .option("--threshold <ratio>", `Utilization that counts as near quota, e.g. 0.8 or 80% (default: ${DEFAULT_QUOTA_THRESHOLD})`)
+  .option("--plan <vendor=plan>", "Catalog plan a vendor's account is on, e.g. shopify=plus (repeatable)", collect, [])
+  .option("--assume <vendor=limit>", "Contracted limit as requests/window, e.g. stripe=500/1s (repeatable)", collect, [])
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .action(async (files: string[], opts: QuotaCommandOpts) => {
+    if (opts.format !== "text" && opts.format !== "json") {
+      console.error(`Error: Invalid format "${opts.format}". Use "text" or "json".`);
+      process.exitCode = 2;
+      return;
+    }
+    const threshold = opts.threshold !== undefined ? parseThreshold(opts.threshold) : undefined;
+    if (threshold === null) {
+      console.error(`Error: Invalid --threshold "${opts.threshold}". Use a ratio such as 0.8 or a percentage such as 80%.`);
+      process.exitCode = 2;
+      return;
+    }
+    const plans: Record<string, string> = {};
+    for (const pair of opts.plan) {
+      const [vendor, plan] = pair.split("=");
+      if (!vendor || !plan) {
+        console.error(`Error: Invalid --plan "${pair}". Use <vendor>=<plan>, e.g. shopify=plus.`);
+        process.exitCode = 2;
+        return;
+      }
+      plans[vendor] = plan;
+    }
+    const assume: Record<string, { requests: number; window_seconds: number }> = {};
+    for (const pair of opts.assume) {
+      const [vendor, value] = pair.split("=");
+      const limit = value !== undefined ? parseLimit(value) : null;
+      if (!vendor || limit === null) {
+        console.error(`Error: Invalid --assume "${pair}". Use <vendor>=<requests>/<window>, e.g. stripe=500/1s.`);
+        process.exitCode = 2;
+        return;
+      }
+      assume[vendor] = limit;
+    }
+
+    let report: QuotaReport;
+    try {
+      const { registry } = await loadRuntimeCatalog(opts);
+      const tdms = await Promise.all(files.map(readTDM));
+      report = buildQuotaReport(tdms, registry, { ...(threshold !== undefined ? { threshold } : {}), plans, assume });
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 2;
+      return;
+    }
+
+    if (opts.format === "json") {
+      process.stdout.write(JSON.stringify(report, null, 2) + "\n");
+    } else {
+      printQuota(report);
+    }
+
+    if (report.near_quota.length > 0 && opts.format === "text") {
+      console.error(pc.yellow(`\n  ${report.near_quota.length} integration(s) near or over quota: ${report.near_quota.join(", ")}`));
+    }
+    process.exitCode = report.near_quota.length > 0 ? 1 : 0;
+  });
@@ -0,0 +1,110 @@
+// apps/cli/src/commands/report.ts — `thirdwatch report --merge` organization rollup from scan results
+import { Command } from "commander";
+import { writeFile } from "node:fs/promises";
+import { relative, resolve } from "node:path";
+import pc from "picocolors";
+import { applyVendorAliases, buildOrgReport, groupByService, loadVendorAliases } from "@thirdwatch/core";
+import type { VendorAliases } from "@thirdwatch/core";
+import type { TDM } from "@thirdwatch/tdm";
+import { readApproved } from "../approved-list.js";
+import { loadRuntimeCatalog } from "../runtime-output.js";
+import { findTDMFiles, readTDM } from "../tdm-file.js";
+import { formatOrgReportCsv, formatOrgReportHtml } from "../output/org-report.js";
+
+interface ReportCommandOpts {
+  merge: string;
+  format: string;
+  output?: string;
+  approved?: string;
+  aliases?: string;
+  top: string;
+  catalogVersion?: string;
+  catalogBundle?: string;
+}
+
+const FORMATS = ["html", "csv", "json"];
+
+export const reportCommand = new Command("report")
+  .description(
+    "Roll up scan results from many repositories into an organization report (vendor × repo matrix, categories, unapproved vendors).",
+  )
+  .requiredOption("--merge <dir>", "Directory of TDM files from `thirdwatch scan`, searched recursively")
+  .option("-f, --format <format>", "Output format: html, csv, or json", "html")
+  .option("-o, --output <file>", "Write to a file instead of stdout")
+  .option("--approved <vendors>", "Approved vendor slugs, comma-separated, or a file with one per line")
+  .option("--top <n>", "Number of unapproved vendors to list", "10")
+  .option("--aliases <file>", "Vendor aliases: a .thirdwatch.yml with vendor_aliases, or a YAML mapping of slug to names")
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .action(async (opts: ReportCommandOpts) => {
+    if (!FORMATS.includes(opts.format)) {
+      console.error(`Error: Invalid format "${opts.format}". Use "html", "csv", or "json".`);
+      process.exitCode = 2;
+      return;
+    }
+    const top = Number(opts.top);
+    if (!Number.isInteger(top) || top < 1) {
+      console.error(`Error: --top must be a positive integer, got "${opts.top}".`);
+      process.exitCode = 2;
+      return;
+    }
+
+    const dir = resolve(opts.merge);
+    const tdms: TDM[] = [];
+    const names: string[] = [];
+    try {
+      for (const file of await findTDMFiles(dir)) {
+        try {
+          tdms.push(await readTDM(file));
+          names.push(relative(dir, file).replace(/\.(json|ya?ml)$/, ""));
+        } catch {
+          // Scan artifact directories often hold other JSON; only TDMs count
+          console.error(pc.dim(`Skipping ${relative(dir, file)}: not a TDM`));
+        }
+      }
+    } catch (err) {
+      console.error(`Error: Cannot read ${opts.merge}: ${err instanceof Error ? err.message : String(err)}`);
+      process.exitCode = 2;
+      return;
+    }
+    if (tdms.length === 0) {
+      console.error(`Error: No TDM files found in ${opts.merge}.`);
+      process.exitCode = 2;
+      return;
+    }
+
+    let aliases: VendorAliases;
+    try {
+      aliases = opts.aliases ? await loadVendorAliases(resolve(opts.aliases)) : {};
+    } catch (err) {
+      console.error(`Error: ${err instanceof Error ? err.message : String(err)}`);
+      process.exitCode = 2;
+      return;
+    }
+
+    try {
+      const registry = applyVendorAliases((await loadRuntimeCatalog(opts)).registry, aliases);
+      const inventory = groupByService(tdms, (i) => names[i]!);
+      const report = buildOrgReport(inventory, registry, {
+        ...(opts.approved ? { approved: await readApproved(opts.approved) } : {}),
+        top,
+      });
+      const output =
+        opts.format === "csv"
+          ? formatOrgReportCsv(report)
+          : opts.format === "json"
+            ? JSON.stringify(report, null, 2) + "\n"
+            : formatOrgReportHtml(report);
+      if (opts.output) {
+        await writeFile(resolve(opts.output), output, "utf8");
+        console.error(
+          pc.green(`✓ ${report.vendors.length} vendors across ${report.repositories.length} repositories → ${opts.output}`),
+        );
+      } else {
+        process.stdout.write(output);
+      }
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 1;
+    }
+  });
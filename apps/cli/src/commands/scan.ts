@@ -1,12 +1,25 @@
 // apps/cli/src/commands/scan.ts — `thirdwatch scan` command handler
 import { Command } from "commander";
-import { dirname, resolve, sep } from "node:path";
+import pc from "picocolors";
+import { dirname, relative, resolve, sep } from "node:path";
 import { fileURLToPath } from "node:url";
 import { writeFile } from "node:fs/promises";
 
 const __filename = fileURLToPath(import.meta.url);
 const __dirname = dirname(__filename);
-import { scan } from "@thirdwatch/core";
+import {
+  scan,
+  resolveCatalog,
+  diffFindings,
+  openScanTarget,
+  isArchivePath,
+  isImageReference,
+  isOffline,
+  loadEnrichmentDataset,
+  MAX_CONTEXT_LINES,
+  isAllowedLLMEndpoint,
+} from "@thirdwatch/core";
+import type { ScanTarget } from "@thirdwatch/core";
 import { PythonPlugin } from "@thirdwatch/language-python";
 import { JavaScriptPlugin } from "@thirdwatch/language-javascript";
 import { GoPlugin } from "@thirdwatch/language-go";
@@ -17,6 +30,8 @@ import { createSpinner } from "../ui/spinner.js";
 import { printSummaryTable } from "../output/summary.js";
 import { formatJson } from "../output/json.js";
 import { formatYaml } from "../output/yaml.js";
+import { formatHtml } from "../output/html.js";
+import { readTDM } from "../tdm-file.js";
 
 interface ScanCommandOpts {
   output: string;
@@ -25,6 +40,18 @@ interface ScanCommandOpts {
   ignore?: string[];
   config?: string;
   resolve: boolean;
+  secretHistory: boolean;
+  enrich?: boolean;
+  enrichDataset?: string;
+  llmClassify?: boolean;
+  llmEndpoint?: string;
+  llmModel?: string;
+  detectors?: boolean;
+  fallback: boolean;
+  contextLines?: string;
+  catalogVersion?: string;
+  catalogBundle?: string;
+  baseline?: string;
   verbose?: boolean;
   quiet?: boolean;
   color: boolean;
@@ -32,15 +59,27 @@ interface ScanCommandOpts {
 
 export const scanCommand = new Command("scan")
   .description(
-    "Scan a codebase and produce a Thirdwatch Dependency Manifest (TDM).",
+    "Scan a codebase, archive, or container image and produce a Thirdwatch Dependency Manifest (TDM).",
   )
-  .argument("[path]", "Path to scan (default: current directory)", ".")
+  .argument("[path]", "Directory, archive (.tar.gz, .zip, .jar), image tarball, or image reference such as app:1.4 (default: current directory)", ".")
   .option("-o, --output <file>", "Output file path (use - for stdout)", "./thirdwatch.json")
-  .option("-f, --format <format>", "Output format: json or yaml", "json")
+  .option("-f, --format <format>", "Output format: json, yaml, or html", "json")
   .option("--languages <langs...>", "Languages to scan (default: auto-detect)")
   .option("--ignore <patterns...>", "Glob patterns to ignore")
   .option("--config <file>", "Path to .thirdwatch.yml config file")
   .option("--no-resolve", "Skip environment variable resolution")
+  .option("--no-secret-history", "Skip git history lookups for hardcoded secrets")
+  .option("--enrich", "Look up RDAP, ASN, and TLS ownership for unknown API hosts")
+  .option("--enrich-dataset <file>", "Enrich unknown hosts from a dataset (`thirdwatch catalog export-enrichment`) before, or offline instead of, lookups")
+  .option("--llm-classify", "Ask the configured LLM to suggest vendors for unmatched packages and hosts")
+  .option("--llm-endpoint <url>", "OpenAI-compatible chat completions URL for --llm-classify; only this endpoint is sent THIRDWATCH_LLM_API_KEY (default: THIRDWATCH_LLM_ENDPOINT, else llm_classification in .thirdwatch.yml, without a key)")
+  .option("--llm-model <model>", "Model for --llm-endpoint (default: THIRDWATCH_LLM_MODEL)")
+  .option("--detectors", "Run custom detectors declared in .thirdwatch.yml (they execute commands from the config)")
+  .option("--no-fallback", "Skip the URL and hostname pass over files no analyzer supports")
+  .option("--context-lines <n>", "Source lines to include either side of each finding, secrets redacted (0-20; default: context_lines in .thirdwatch.yml, else 0)")
+  .option("--catalog-version <version>", "Pin the vendor catalog version for reproducible runs")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (offline; expects <file>.sig alongside)")
+  .option("--baseline <file>", "Compare findings by fingerprint against a previous TDM and list what is new")
   .option("--verbose", "Print detailed logs")
   .option("--quiet", "Suppress all output except the TDM")
   .option("--no-color", "Disable colored output")
@@ -48,11 +87,30 @@ export const scanCommand = new Command("scan")
     const quiet = opts.quiet ?? false;
     const verbose = opts.verbose ?? false;
     const format = opts.format;
-    const root = resolve(scanPath);
     const writeToStdout = opts.output === "-";
 
-    if (format !== "json" && format !== "yaml") {
-      console.error(`Error: Invalid format "${format}". Use "json" or "yaml".`);
+    if (format !== "json" && format !== "yaml" && format !== "html") {
+      console.error(`Error: Invalid format "${format}". Use "json", "yaml", or "html".`);
+      process.exitCode = 2;
+      return;
+    }
+    const contextLines = opts.contextLines === undefined ? undefined : Number(opts.contextLines);
+    if (contextLines !== undefined && (!Number.isInteger(contextLines) || contextLines < 0 || contextLines > MAX_CONTEXT_LINES)) {
+      console.error(`Error: --context-lines must be an integer from 0 to ${MAX_CONTEXT_LINES}, got "${opts.contextLines}".`);
+      process.exitCode = 2;
+      return;
+    }
+
+    // The endpoint, and with it the key, never comes from the scanned repository
+    const llmEndpoint = opts.llmEndpoint ?? process.env["THIRDWATCH_LLM_ENDPOINT"];
+    const llmModel = opts.llmModel ?? process.env["THIRDWATCH_LLM_MODEL"];
+    if (llmEndpoint !== undefined && !isAllowedLLMEndpoint(llmEndpoint)) {
+      console.error(`Error: LLM endpoint must use https (plain http is only allowed for localhost), got "${llmEndpoint}".`);
+      process.exitCode = 2;
+      return;
+    }
+    if (llmEndpoint !== undefined && !llmModel) {
+      console.error("Error: --llm-endpoint needs a model (--llm-model or THIRDWATCH_LLM_MODEL).");
       process.exitCode = 2;
       return;
     }
@@ -71,8 +129,13 @@ export const scanCommand = new Command("scan")
       }
     }
 
+    if (opts.enrich && !opts.enrichDataset && isOffline() && !quiet) {
+      console.error(pc.yellow("⚠  Offline mode: --enrich looks nothing up; pass --enrich-dataset to enrich from a dataset"));
+    }
+
     const s = createSpinner();
-    if (!quiet) s.start("Discovering files…");
+    const artifact = isArchivePath(scanPath) || isImageReference(scanPath);
+    if (!quiet) s.start(artifact ? `Extracting ${scanPath}…` : "Discovering files…");
 
     // Build plugin list — filter by --languages if provided
     const allPlugins = [new PythonPlugin(), new JavaScriptPlugin(), new GoPlugin(), new JavaPlugin(), new RustPlugin(), new PhpPlugin()];
@@ -87,18 +150,39 @@ export const scanCommand = new Command("scan")
       return;
     }
 
+    let target: ScanTarget | undefined;
     try {
       // Resolve registries directory (relative to CLI package in the monorepo)
       const registriesDir = resolve(__dirname, "../../../../registries");
 
+      target = await openScanTarget(scanPath);
       const scanOpts: Parameters<typeof scan>[0] = {
-        root,
+        root: target.root,
         plugins,
         resolveEnv: opts.resolve !== false,
+        // Extracted artifacts have no git history to date secrets from
+        secretHistory: opts.secretHistory !== false && !target.artifact,
+        enrichUnknown: opts.enrich === true || opts.enrichDataset !== undefined,
+        llmClassify: opts.llmClassify === true,
+        detectors: opts.detectors === true,
+        fallback: opts.fallback !== false,
         registriesDir,
       };
-      if (opts.ignore) scanOpts.ignore = opts.ignore;
+      if (contextLines !== undefined) scanOpts.contextLines = contextLines;
+      if (llmEndpoint !== undefined && llmModel) {
+        const apiKey = process.env["THIRDWATCH_LLM_API_KEY"];
+        scanOpts.llm = { endpoint: llmEndpoint, model: llmModel, ...(apiKey ? { apiKey } : {}) };
+      }
+      const catalog = await resolveCatalog({
+        ...(opts.catalogBundle ? { bundlePath: opts.catalogBundle } : {}),
+        ...(opts.catalogVersion ? { version: opts.catalogVersion } : {}),
+      });
+      if (catalog) scanOpts.catalog = catalog;
+      if (target.artifact) scanOpts.artifact = target.artifact;
+      const ignore = [...target.ignore, ...(opts.ignore ?? [])];
+      if (ignore.length > 0) scanOpts.ignore = ignore;
       if (opts.config) scanOpts.configFile = opts.config;
+      if (opts.enrichDataset) scanOpts.enrichmentDataset = await loadEnrichmentDataset(resolve(opts.enrichDataset));
 
       const result = await scan(scanOpts);
 
@@ -107,15 +191,20 @@ export const scanCommand = new Command("scan")
 
       if (!quiet) s.succeed(`Scan complete — ${depCount} dependencies found`);
 
-      if (verbose && result.errors.length > 0) {
-        console.error(`\n⚠  ${result.errors.length} file(s) had errors:`);
-        for (const e of result.errors) {
-          console.error(`   ${e.filePath}: ${e.error}`);
+      if (result.errors.length > 0) {
+        if (verbose) {
+          console.error(`\n⚠  ${result.errors.length} error(s); these results are partial:`);
+          for (const e of result.errors) {
+            const where = e.filePath ? relative(target.root, e.filePath) || e.filePath : "(scan)";
+            console.error(`   ${where} [${e.stage}${e.detector ? `: ${e.detector}` : ""}]: ${e.error}`);
+          }
+        } else if (!quiet) {
+          console.error(`⚠  ${result.errors.length} error(s); these results are partial (listed under "errors" in the report, or run with --verbose)`);
         }
       }
 
       // Format output
-      const output = format === "yaml" ? formatYaml(tdm) : formatJson(tdm);
+      const output = format === "yaml" ? formatYaml(tdm) : format === "html" ? formatHtml(tdm) : formatJson(tdm);
 
       if (writeToStdout) {
         process.stdout.write(output);
@@ -124,6 +213,13 @@ export const scanCommand = new Command("scan")
 
         if (!quiet) {
           printSummaryTable(tdm, result.filesScanned);
+          if (opts.baseline) {
+            const { added, removed } = diffFindings(tdm, await readTDM(opts.baseline));
+            console.log("");
+            console.log(pc.bold(`  Since baseline: ${added.length} new, ${removed.length} resolved`));
+            for (const f of added) console.log(`    ${pc.yellow("+")} ${f.finding}  ${pc.dim(`${f.file}:${f.line}`)}`);
+            for (const f of removed) console.log(`    ${pc.green("-")} ${f.finding}  ${pc.dim(`${f.file}:${f.line}`)}`);
+          }
           console.log(`\n✓ TDM written to ${outputPath}`);
         } else {
           process.stdout.write(output);
@@ -138,5 +234,7 @@ export const scanCommand = new Command("scan")
         `\n${err instanceof Error ? err.message : String(err)}`,
       );
       process.exitCode = 1;
+    } finally {
+      await target?.cleanup();
     }
   });
@@ -0,0 +1,179 @@
+// apps/cli/src/commands/server.ts — `thirdwatch server --schedule` periodic re-clone, rescan, and upload
+import { Command } from "commander";
+import pc from "picocolors";
+import { mkdtemp, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { dirname, join, resolve } from "node:path";
+import { fileURLToPath } from "node:url";
+import {
+  scan,
+  resolveCatalog,
+  parseCron,
+  nextRun,
+  loadServerConfig,
+  repositoryName,
+  cloneRepository,
+} from "@thirdwatch/core";
+import type { CronSchedule, ScheduledRepository } from "@thirdwatch/core";
+import { PythonPlugin } from "@thirdwatch/language-python";
+import { JavaScriptPlugin } from "@thirdwatch/language-javascript";
+import { GoPlugin } from "@thirdwatch/language-go";
+import { JavaPlugin } from "@thirdwatch/language-java";
+import { RustPlugin } from "@thirdwatch/language-rust";
+import { PhpPlugin } from "@thirdwatch/language-php";
+import { DEFAULT_API_URL, uploadTDM } from "./push.js";
+
+const __dirname = dirname(fileURLToPath(import.meta.url));
+
+// setTimeout's ceiling; longer waits are chained
+const MAX_TIMER_MS = 2 ** 31 - 1;
+
+interface ServerCommandOpts {
+  repos: string;
+  schedule?: string;
+  once?: boolean;
+  token?: string;
+  apiUrl?: string;
+  workDir?: string;
+  catalogVersion?: string;
+  catalogBundle?: string;
+}
+
+interface RunContext {
+  apiUrl: string;
+  token: string;
+  workDir: string;
+  catalogVersion?: string;
+  catalogBundle?: string;
+}
+
+function log(message: string): void {
+  console.log(`${pc.dim(new Date().toISOString())} ${message}`);
+}
+
+/** Clone, scan, and upload one repository; the clone is removed afterwards */
+async function rescan(repo: ScheduledRepository, ctx: RunContext): Promise<void> {
+  const name = repositoryName(repo);
+  const dir = await mkdtemp(join(ctx.workDir, "thirdwatch-server-"));
+  try {
+    const commit = await cloneRepository(repo, dir);
+    const catalog = await resolveCatalog({
+      ...(ctx.catalogBundle ? { bundlePath: ctx.catalogBundle } : {}),
+      ...(ctx.catalogVersion ? { version: ctx.catalogVersion } : {}),
+    });
+    const { tdm } = await scan({
+      root: dir,
+      plugins: [new PythonPlugin(), new JavaScriptPlugin(), new GoPlugin(), new JavaPlugin(), new RustPlugin(), new PhpPlugin()],
+      // A shallow clone has no history to date secrets from
+      secretHistory: false,
+      registriesDir: resolve(__dirname, "../../../../registries"),
+      ...(catalog ? { catalog } : {}),
+    });
+    tdm.metadata.repository = name;
+    const result = await uploadTDM(tdm, { apiUrl: ctx.apiUrl, token: ctx.token, commit });
+    log(
+      `${pc.green("✓")} ${name} @ ${commit.slice(0, 7)}: ${tdm.metadata.total_dependencies_found} dependencies, ` +
+        `${result.vendorChanges ?? 0} vendor changes`,
+    );
+  } finally {
+    await rm(dir, { recursive: true, force: true });
+  }
+}
+
+/** One pass over every repository; a failing repository doesn't stop the rest */
+async function runOnce(repos: ScheduledRepository[], ctx: RunContext): Promise<number> {
+  let failures = 0;
+  for (const repo of repos) {
+    try {
+      await rescan(repo, ctx);
+    } catch (err) {
+      failures++;
+      log(`${pc.red("✗")} ${repositoryName(repo)}: ${err instanceof Error ? err.message : String(err)}`);
+    }
+  }
+  return failures;
+}
+
+export const serverCommand = new Command("server")
+  .description(
+    "Re-clone and rescan repositories on a cron schedule, uploading each TDM so inventory and change notifications stay current.",
+  )
+  .option("--repos <file>", "YAML list of repositories to scan", "./thirdwatch-server.yml")
+  .option("--schedule <cron>", 'Cron expression in UTC, e.g. "0 4 * * *" or @daily (default: the file\'s schedule)')
+  .option("--once", "Scan every repository once and exit")
+  .option("--token <token>", "API token (or set THIRDWATCH_TOKEN env var)")
+  .option("--api-url <url>", "API base URL (or set THIRDWATCH_API_URL env var)")
+  .option("--work-dir <dir>", "Directory for temporary clones (default: the system temp directory)")
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .action(async (opts: ServerCommandOpts) => {
+    const token = opts.token ?? process.env["THIRDWATCH_TOKEN"];
+    if (!token) {
+      console.error("Error: API token required. Use --token or set THIRDWATCH_TOKEN.");
+      process.exitCode = 2;
+      return;
+    }
+
+    let repos: ScheduledRepository[];
+    let schedule: CronSchedule | null = null;
+    try {
+      const config = await loadServerConfig(resolve(opts.repos));
+      repos = config.repositories;
+      const expression = opts.schedule ?? config.schedule;
+      if (expression) schedule = parseCron(expression);
+    } catch (err) {
+      console.error(`Error: ${err instanceof Error ? err.message : String(err)}`);
+      process.exitCode = 2;
+      return;
+    }
+    if (!schedule && !opts.once) {
+      console.error("Error: No schedule. Pass --schedule, set schedule in the repository list, or use --once.");
+      process.exitCode = 2;
+      return;
+    }
+
+    const ctx: RunContext = {
+      apiUrl: opts.apiUrl ?? process.env["THIRDWATCH_API_URL"] ?? DEFAULT_API_URL,
+      token,
+      workDir: opts.workDir ? resolve(opts.workDir) : tmpdir(),
+      ...(opts.catalogVersion ? { catalogVersion: opts.catalogVersion } : {}),
+      ...(opts.catalogBundle ? { catalogBundle: opts.catalogBundle } : {}),
+    };
+
+    if (opts.once || !schedule) {
+      const failures = await runOnce(repos, ctx);
+      process.exitCode = failures > 0 ? 1 : 0;
+      return;
+    }
+
+    const cron = schedule;
+    let timer: ReturnType<typeof setTimeout> | undefined;
+    let stopping = false;
+    const stop = () => {
+      stopping = true;
+      if (timer) clearTimeout(timer);
+      log("Stopping");
+    };
+    process.once("SIGINT", stop);
+    process.once("SIGTERM", stop);
+
+    // Runs never overlap: the next one is scheduled after this one finishes
+    const scheduleNext = () => {
+      if (stopping) return;
+      const at = nextRun(cron, new Date());
+      log(`Next run at ${at.toISOString()} (${repos.length} repositories)`);
+      const wait = () => {
+        const remaining = at.getTime() - Date.now();
+        if (remaining > MAX_TIMER_MS) {
+          timer = setTimeout(wait, MAX_TIMER_MS);
+          return;
+        }
+        timer = setTimeout(() => {
+          void runOnce(repos, ctx).then(scheduleNext);
+        }, Math.max(0, remaining));
+      };
+      wait();
+    };
+    log(`thirdwatch server started — schedule "${cron.expression}" (UTC)`);
+    scheduleNext();
+  });
@@ -0,0 +1,116 @@
+// apps/cli/src/commands/sla.ts — `thirdwatch sla` composite availability from vendor SLAs
+import { Command } from "commander";
+import pc from "picocolors";
+import { computeSlaReport } from "@thirdwatch/core";
+import type { SlaReport } from "@thirdwatch/core";
+import { loadRuntimeCatalog } from "../runtime-output.js";
+import { readTDM } from "../tdm-file.js";
+
+interface SlaCommandOpts {
+  format: string;
+  service?: string;
+  assume: string[];
+  target?: string;
+  catalogVersion?: string;
+  catalogBundle?: string;
+}
+
+function collect(value: string, previous: string[]): string[] {
+  return [...previous, value];
+}
+
+function parsePercent(value: string): number | null {
+  const n = Number(value.replace(/%$/, ""));
+  return Number.isFinite(n) && n > 0 && n <= 100 ? n : null;
+}
+
+const pct = (n: number) => `${n}%`;
+
+function printSla(report: SlaReport): void {
+  console.log("");
+  if (report.composite_uptime === null) {
+    console.log(pc.bold(`  ${report.service} — no dependency publishes an SLA`));
+  } else {
+    console.log(
+      pc.bold(`  ${report.service} — availability ceiling ${pct(report.composite_uptime)}`) +
+        pc.dim(` (${report.monthly_downtime_minutes} min/month of allowed vendor downtime)`),
+    );
+    if (report.composite_with_failover !== report.composite_uptime) {
+      console.log(pc.dim(`  ${pct(report.composite_with_failover!)} if same-category vendors fail over to each other`));
+    }
+  }
+  console.log("");
+  for (const dep of report.dependencies) {
+    const name = dep.scope ? `${dep.display_name} (${dep.scope})` : dep.display_name;
+    const sla = dep.uptime === null ? pc.gray("no published SLA") : `${pct(dep.uptime).padEnd(8)} ${String(dep.monthly_downtime_minutes).padStart(6)} min/mo`;
+    const notes = [
+      dep.plan ? pc.dim(dep.plan) : "",
+      dep.source === "assumed" ? pc.dim("assumed") : "",
+      dep.single_point_of_failure ? pc.red("single point of failure") : pc.dim(`alternatives: ${dep.alternatives.join(", ")}`),
+    ].filter(Boolean);
+    console.log(`    ${dep.uptime === null ? pc.gray("●") : pc.green("●")} ${name.padEnd(28)} ${sla}  ${notes.join("  ")}`);
+  }
+  if (report.unknown.length > 0) {
+    console.log("");
+    console.log(pc.yellow(`  ${report.unknown.length} vendor(s) publish no SLA, so the real ceiling is lower. Add contracted numbers with --assume.`));
+  }
+}
+
+export const slaCommand = new Command("sla")
+  .description(
+    "Composite availability ceiling for a service from its vendors' published SLAs, with single points of failure.",
+  )
+  .argument("<tdm...>", "TDMs for one service (a static scan, runtime reports, or both)")
+  .option("-f, --format <format>", "Output format: text or json", "text")
+  .option("--service <name>", "Service name for the report (default: the TDM's repository)")
+  .option("--assume <vendor=percent>", "Contracted uptime for a vendor, e.g. stripe=99.99 (repeatable)", collect, [])
+  .option("--target <percent>", "Exit 1 when the availability ceiling is below this, e.g. 99.9")
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .action(async (files: string[], opts: SlaCommandOpts) => {
+    if (opts.format !== "text" && opts.format !== "json") {
+      console.error(`Error: Invalid format "${opts.format}". Use "text" or "json".`);
+      process.exitCode = 2;
+      return;
+    }
+    const target = opts.target !== undefined ? parsePercent(opts.target) : undefined;
+    if (target === null) {
+      console.error(`Error: Invalid --target "${opts.target}". Use a percentage such as 99.9.`);
+      process.exitCode = 2;
+      return;
+    }
+    const assume: Record<string, number> = {};
+    for (const pair of opts.assume) {
+      const [vendor, value] = pair.split("=");
+      const uptime = value !== undefined ? parsePercent(value) : null;
+      if (!vendor || uptime === null) {
+        console.error(`Error: Invalid --assume "${pair}". Use <vendor>=<percent>, e.g. stripe=99.99.`);
+        process.exitCode = 2;
+        return;
+      }
+      assume[vendor] = uptime;
+    }
+
+    let report: SlaReport;
+    try {
+      const { registry } = await loadRuntimeCatalog(opts);
+      const tdms = await Promise.all(files.map(readTDM));
+      report = computeSlaReport(tdms, registry, { ...(opts.service ? { service: opts.service } : {}), assume });
+    } catch (err) {
+      console.error(err instanceof Error ? err.message : String(err));
+      process.exitCode = 2;
+      return;
+    }
+
+    if (opts.format === "json") {
+      process.stdout.write(JSON.stringify(report, null, 2) + "\n");
+    } else {
+      printSla(report);
+    }
+
+    const belowTarget = target !== undefined && report.composite_uptime !== null && report.composite_uptime < target;
+    if (belowTarget && opts.format === "text") {
+      console.error(pc.red(`\n  Ceiling ${pct(report.composite_uptime!)} is below the ${pct(target!)} target.`));
+    }
+    process.exitCode = belowTarget ? 1 : 0;
+  });
@@ -0,0 +1,59 @@
+// apps/cli/src/commands/snapshot.ts — `thirdwatch snapshot` one-shot connection inventory
+import { Command } from "commander";
+import { takeSnapshot, buildRuntimeTDM } from "@thirdwatch/core";
+import { createSpinner } from "../ui/spinner.js";
+import { printRuntimeSummary } from "../output/runtime-summary.js";
+import { loadRuntimeCatalog, checkRuntimeOutput, writeRuntimeReport } from "../runtime-output.js";
+import type { RuntimeOutputOpts } from "../runtime-output.js";
+
+interface SnapshotCommandOpts extends RuntimeOutputOpts {
+  conntrack: boolean;
+  reverseDns: boolean;
+  includePrivate?: boolean;
+  quiet?: boolean;
+}
+
+export const snapshotCommand = new Command("snapshot")
+  .description("List the external services this host is connected to right now (socket tables, conntrack, lsof).")
+  .option("-o, --output <file>", "Output file path (use - for stdout)", "./thirdwatch-runtime.json")
+  .option("-f, --format <format>", "Output format: json or yaml", "json")
+  .option("--no-conntrack", "Do not read the conntrack table (Linux)")
+  .option("--no-reverse-dns", "Do not name addresses with PTR lookups")
+  .option("--include-private", "Keep connections to private and loopback addresses")
+  .option("--catalog-version <version>", "Pin the vendor catalog version")
+  .option("--catalog-bundle <file>", "Use a vendored catalog bundle (expects <file>.sig alongside)")
+  .option("--quiet", "Suppress all output except the report")
+  .action(async (opts: SnapshotCommandOpts) => {
+    const quiet = opts.quiet ?? false;
+    const outputPath = checkRuntimeOutput(opts);
+    if (outputPath === null) return;
+
+    const s = createSpinner();
+    try {
+      const { registry, catalogVersion } = await loadRuntimeCatalog(opts);
+      if (!quiet) s.start("Reading connection tables…");
+
+      const startMs = Date.now();
+      const { observations, sources } = await takeSnapshot({
+        conntrack: opts.conntrack,
+        reverseDns: opts.reverseDns,
+        includePrivate: opts.includePrivate === true,
+      });
+      const tdm = buildRuntimeTDM(observations, registry, {
+        duration: Date.now() - startMs,
+        ...(catalogVersion ? { catalogVersion } : {}),
+      });
+      if (!quiet) s.succeed(`Found ${observations.length} open outbound connections (${sources.join(", ")})`);
+
+      await writeRuntimeReport(tdm, opts.format, outputPath);
+      if (!quiet && outputPath) {
+        printRuntimeSummary(tdm, "thirdwatch snapshot");
+        console.log(`\n✓ Runtime report written to ${outputPath}`);
+      }
+      process.exitCode = 0;
+    } catch (err) {
+      if (!quiet) s.fail("Snapshot failed");
+      console.error(`\n${err instanceof Error ? err.message : String(err)}`);
+      process.exitCode = 1;
+    }
+  });
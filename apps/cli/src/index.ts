@@ -5,6 +5,22 @@ import { fileURLToPath } from "node:url";
 import { dirname, join } from "node:path";
 import { scanCommand } from "./commands/scan.js";
 import { pushCommand } from "./commands/push.js";
+import { catalogCommand } from "./commands/catalog.js";
+import { agentCommand } from "./commands/agent.js";
+import { proxyCommand } from "./commands/proxy.js";
+import { driftCommand } from "./commands/drift.js";
+import { ingestCommand } from "./commands/ingest.js";
+import { snapshotCommand } from "./commands/snapshot.js";
+import { slaCommand } from "./commands/sla.js";
+import { quotaCommand } from "./commands/quota.js";
+import { concentrationCommand } from "./commands/concentration.js";
+import { reportCommand } from "./commands/report.js";
+import { explainCommand } from "./commands/explain.js";
+import { outdatedCommand } from "./commands/outdated.js";
+import { impactCommand } from "./commands/impact.js";
+import { serverCommand } from "./commands/server.js";
+import { policyCommand } from "./commands/policy.js";
+import { compareCommand } from "./commands/compare.js";
 import { checkForUpdates } from "./update-check.js";
 
 const __dirname = dirname(fileURLToPath(import.meta.url));
@@ -25,12 +41,33 @@ program
   .description(
     "Know before you break — map every external dependency in your codebase.",
   )
-  .version(version, "-v, --version");
+  .version(version, "-v, --version")
+  .option("--offline", "Air-gapped mode: never contact public services (same as THIRDWATCH_OFFLINE=1)")
+  .hook("preAction", (cmd) => {
+    // Through the environment, so core modules and child processes see it too
+    if (cmd.opts<{ offline?: boolean }>().offline) process.env["THIRDWATCH_OFFLINE"] = "1";
+  });
 
 program.addCommand(scanCommand);
 program.addCommand(pushCommand);
-
-// Non-blocking update check (fire and forget)
-void checkForUpdates(version);
+program.addCommand(catalogCommand);
+program.addCommand(agentCommand);
+program.addCommand(proxyCommand);
+program.addCommand(driftCommand);
+program.addCommand(ingestCommand);
+program.addCommand(snapshotCommand);
+program.addCommand(slaCommand);
+program.addCommand(quotaCommand);
+program.addCommand(concentrationCommand);
+program.addCommand(reportCommand);
+program.addCommand(explainCommand);
+program.addCommand(outdatedCommand);
+program.addCommand(impactCommand);
+program.addCommand(serverCommand);
+program.addCommand(policyCommand);
+program.addCommand(compareCommand);
 
 program.parse();
+
+// Non-blocking update check (fire and forget), after --offline is applied
+void checkForUpdates(version);
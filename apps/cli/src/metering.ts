@@ -0,0 +1,115 @@
+// apps/cli/src/metering.ts — Egress metering and alerts for long-running runtime commands (agent, proxy)
+import { Command } from "commander";
+import { readFile, rename, writeFile } from "node:fs/promises";
+import { resolve } from "node:path";
+import {
+  EgressMeter,
+  sendAlertWebhook,
+  startMetricsServer,
+  createVendorMatcher,
+  createIpRangeMatcher,
+} from "@thirdwatch/core";
+import type { EgressAlert, MeterState, MetricsServer, SDKRegistryEntry } from "@thirdwatch/core";
+
+export interface MeteringOpts {
+  alertWebhook?: string;
+  alertSecret?: string;
+  metricsPort?: string;
+  metricsHost: string;
+  baseline?: string;
+  learningWindows: string;
+}
+
+/** Add the metering options shared by `agent` and `proxy` */
+export function withMeteringOptions(command: Command): Command {
+  return command
+    .option("--alert-webhook <url>", "POST egress alerts (new vendor, volume spike) to this URL")
+    .option("--alert-secret <secret>", "HMAC secret for alert payloads (or set THIRDWATCH_ALERT_SECRET)")
+    .option("--metrics-port <port>", "Serve Prometheus metrics on /metrics at this port")
+    .option("--metrics-host <address>", "Metrics listen address", "127.0.0.1")
+    .option("--baseline <file>", "Load and save the metering baseline here, so restarts don't re-learn")
+    .option("--learning-windows <n>", "Windows to learn before new-vendor alerts fire", "60");
+}
+
+export interface Metering {
+  meter: EgressMeter;
+  /** Close the window: deliver alerts and save the baseline */
+  closeWindow(): Promise<void>;
+  stop(): Promise<void>;
+}
+
+/** Print an alert as one line on stderr */
+function logAlert(alert: EgressAlert): void {
+  console.error(`thirdwatch alert [${alert.kind}] ${alert.message}`);
+}
+
+/**
+ * Start metering when alerts or metrics were requested; returns null when
+ * they weren't and undefined after printing a usage error.
+ */
+export async function startMetering(
+  opts: MeteringOpts,
+  registry: SDKRegistryEntry[],
+  context: { defaultService: string; quiet: boolean },
+): Promise<Metering | null | undefined> {
+  if (opts.alertWebhook === undefined && opts.metricsPort === undefined) return null;
+
+  const learningWindows = Number(opts.learningWindows);
+  if (!Number.isInteger(learningWindows) || learningWindows < 0) {
+    console.error(`Error: Invalid --learning-windows "${opts.learningWindows}".`);
+    process.exitCode = 2;
+    return undefined;
+  }
+  const metricsPort = opts.metricsPort !== undefined ? Number(opts.metricsPort) : undefined;
+  if (metricsPort !== undefined && (!Number.isInteger(metricsPort) || metricsPort < 0 || metricsPort > 65535)) {
+    console.error(`Error: Invalid --metrics-port "${opts.metricsPort}".`);
+    process.exitCode = 2;
+    return undefined;
+  }
+
+  const baselinePath = opts.baseline ? resolve(opts.baseline) : undefined;
+  let state: MeterState | undefined;
+  if (baselinePath) {
+    const text = await readFile(baselinePath, "utf8").catch(() => null);
+    if (text !== null) state = JSON.parse(text) as MeterState;
+  }
+
+  const meter = new EgressMeter({
+    matchVendor: createVendorMatcher(registry),
+    matchIp: createIpRangeMatcher(registry),
+    defaultService: context.defaultService,
+    learningWindows,
+    ...(state ? { state } : {}),
+  });
+
+  let metrics: MetricsServer | undefined;
+  if (metricsPort !== undefined) {
+    metrics = await startMetricsServer({ port: metricsPort, host: opts.metricsHost, render: () => meter.metrics() });
+    if (!context.quiet) console.error(`thirdwatch metrics on http://${opts.metricsHost}:${metrics.port}/metrics`);
+  }
+
+  const secret = opts.alertSecret ?? process.env["THIRDWATCH_ALERT_SECRET"];
+  const closeWindow = async () => {
+    for (const alert of meter.closeWindow()) {
+      if (!context.quiet) logAlert(alert);
+      if (opts.alertWebhook) {
+        await sendAlertWebhook({ url: opts.alertWebhook, ...(secret ? { secret } : {}) }, alert).catch((err: unknown) =>
+          console.error(`thirdwatch: ${err instanceof Error ? err.message : String(err)}`),
+        );
+      }
+    }
+    if (baselinePath) {
+      // Write-then-rename so a crash mid-write never leaves a truncated baseline
+      await writeFile(`${baselinePath}.tmp`, JSON.stringify(meter.snapshot()), "utf8");
+      await rename(`${baselinePath}.tmp`, baselinePath);
+    }
+  };
+
+  return {
+    meter,
+    closeWindow,
+    stop: async () => {
+      await metrics?.close();
+    },
+  };
+}
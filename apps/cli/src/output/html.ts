@@ -0,0 +1,113 @@
+// apps/cli/src/output/html.ts — HTML rendering of a scan, with evidence snippets per location
+import { redactSecrets } from "@thirdwatch/core";
+import type { TDM, TDMLocation } from "@thirdwatch/tdm";
+
+function esc(value: string | number): string {
+  return String(value).replace(/[&<>"']/g, (c) => `&#${c.charCodeAt(0)};`);
+}
+
+const STYLE = `
+body { font: 14px/1.4 -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 2rem; color: #1f2328; }
+h1 { font-size: 1.5rem; margin-bottom: 0.25rem; }
+h2 { font-size: 1.1rem; margin-top: 2rem; }
+h3 { font-size: 1rem; margin: 1.25rem 0 0.25rem; }
+.meta, .loc { color: #656d76; }
+.tag { display: inline-block; margin-left: 0.5rem; padding: 0 6px; border-radius: 8px; background: #eaeef2; font-size: 12px; }
+.tag.secret { background: #ffebe9; color: #cf222e; }
+table { border-collapse: collapse; margin-top: 0.5rem; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; }
+th { background: #f6f8fa; }
+pre { margin: 0.25rem 0 0.75rem; padding: 0.5rem 0; background: #f6f8fa; border: 1px solid #d0d7de; overflow-x: auto; font-size: 12px; }
+pre span { display: block; padding: 0 0.75rem; white-space: pre; }
+pre span i { display: inline-block; width: 3.5em; color: #8c959f; font-style: normal; user-select: none; }
+pre span.hit { background: #fff8c5; }
+`;
+
+/** A location's source: its snippet with the finding's line marked, or the one-line context */
+function renderLocation(loc: TDMLocation): string {
+  const tags = [
+    loc.secret ? `<span class="tag secret">secret: ${esc(loc.secret.kind)}</span>` : "",
+    loc.environment ? `<span class="tag">${esc(loc.environment)}</span>` : "",
+    loc.service ? `<span class="tag">${esc(loc.service)}</span>` : "",
+  ].join("");
+  const out = [`<div class="loc">${esc(loc.file)}:${loc.line}${tags}</div>`];
+  if (loc.snippet) {
+    const lines = loc.snippet.lines.map((text, i) => {
+      const n = loc.snippet!.start_line + i;
+      return `<span${n === loc.line ? ' class="hit"' : ""}><i>${n}</i>${esc(text)}</span>`;
+    });
+    out.push(`<pre>${lines.join("")}</pre>`);
+  } else if (loc.context) {
+    out.push(`<pre><span class="hit"><i>${loc.line}</i>${esc(loc.context)}</span></pre>`);
+  }
+  return out.join("\n");
+}
+
+interface Finding {
+  title: string;
+  provider?: string | undefined;
+  confidence: string;
+  rule_id?: string | undefined;
+  locations: TDMLocation[];
+}
+
+function renderSection(heading: string, findings: Finding[]): string[] {
+  if (findings.length === 0) return [];
+  const out = [`<h2>${esc(heading)} (${findings.length})</h2>`];
+  for (const f of findings) {
+    const tags = [f.provider, f.confidence, f.rule_id].filter(Boolean).map((t) => `<span class="tag">${esc(t!)}</span>`).join("");
+    // Connection strings can carry their password
+    out.push(`<h3>${esc(redactSecrets(f.title))}${tags}</h3>`);
+    for (const loc of f.locations) out.push(renderLocation(loc));
+  }
+  return out;
+}
+
+/**
+ * Self-contained HTML page of a scan: every API, SDK, infrastructure, and
+ * webhook finding with its locations and, when the scan used
+ * `--context-lines`, the redacted source around each one.
+ */
+export function formatHtml(tdm: TDM): string {
+  const { metadata } = tdm;
+  const out: string[] = [
+    "<!doctype html>",
+    '<html lang="en"><head><meta charset="utf-8">',
+    "<title>Thirdwatch scan</title>",
+    `<style>${STYLE}</style></head><body>`,
+    `<h1>Third-party dependencies${metadata.repository ? ` in ${esc(metadata.repository)}` : ""}</h1>`,
+    `<p class="meta">${metadata.total_dependencies_found} dependencies · ${esc(metadata.languages_detected.join(", "))} · scanned ${esc(metadata.scan_timestamp)}</p>`,
+  ];
+
+  out.push(
+    ...renderSection(
+      "External APIs",
+      tdm.apis.map((a) => ({ title: `${a.method ?? ""} ${a.url}`.trim(), provider: a.provider ?? undefined, confidence: a.confidence, rule_id: a.rule_id, locations: a.locations })),
+    ),
+    ...renderSection(
+      "SDKs",
+      tdm.sdks.map((s) => ({ title: s.sdk_package, provider: s.provider, confidence: s.confidence, rule_id: s.rule_id, locations: s.locations })),
+    ),
+    ...renderSection(
+      "Infrastructure",
+      tdm.infrastructure.map((i) => ({ title: `${i.type}: ${i.connection_ref}`, provider: i.provider, confidence: i.confidence, rule_id: i.rule_id, locations: i.locations })),
+    ),
+    ...renderSection(
+      "Webhooks",
+      tdm.webhooks.map((w) => ({ title: `${w.direction}: ${w.target_url}`, provider: w.provider, confidence: w.confidence, rule_id: w.rule_id, locations: w.locations })),
+    ),
+  );
+
+  if (tdm.packages.length > 0) {
+    out.push(`<h2>Packages (${tdm.packages.length})</h2>`);
+    out.push("<table><tr><th>Package</th><th>Ecosystem</th><th>Version</th><th>Manifest</th></tr>");
+    for (const p of tdm.packages) {
+      out.push(
+        `<tr><td>${esc(p.name)}</td><td>${esc(p.ecosystem)}</td><td>${esc(p.current_version)}</td><td>${esc(p.manifest_file)}</td></tr>`,
+      );
+    }
+    out.push("</table>");
+  }
+  out.push("</body></html>");
+  return out.join("\n") + "\n";
+}
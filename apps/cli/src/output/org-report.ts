@@ -0,0 +1,115 @@
+// apps/cli/src/output/org-report.ts — CSV and HTML renderings of `thirdwatch report --merge`
+import { COMPLIANCE_URL_KEYS, formatCsv } from "@thirdwatch/core";
+import type { OrgReport } from "@thirdwatch/core";
+
+/** Vendors × repositories matrix, one row per vendor, usage counts in the cells */
+export function formatOrgReportCsv(report: OrgReport): string {
+  const approval = report.vendors.some((v) => v.approved !== undefined);
+  const header = ["vendor", "display_name", "category", "data_classification", ...(approval ? ["approved"] : []), "repositories", "total_usages", ...report.repositories];
+  const rows = report.vendors.map((v) => [
+    v.vendor,
+    v.display_name,
+    v.category ?? "",
+    (v.data_classification ?? []).join(" "),
+    ...(approval ? [v.approved ? "yes" : "no"] : []),
+    v.repositories,
+    v.total_usages,
+    ...report.repositories.map((repo) => v.usages[repo] ?? 0),
+  ]);
+  return formatCsv([header, ...rows]);
+}
+
+function esc(value: string | number): string {
+  return String(value).replace(/[&<>"']/g, (c) => `&#${c.charCodeAt(0)};`);
+}
+
+const STYLE = `
+body { font: 14px/1.4 -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 2rem; color: #1f2328; }
+h1 { font-size: 1.5rem; margin-bottom: 0.25rem; }
+h2 { font-size: 1.1rem; margin-top: 2rem; }
+.meta { color: #656d76; }
+table { border-collapse: collapse; margin-top: 0.5rem; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; }
+th { background: #f6f8fa; }
+td.n { text-align: right; font-variant-numeric: tabular-nums; }
+td.off { color: #d0d7de; text-align: center; }
+tr.unapproved td:first-child { color: #cf222e; font-weight: 600; }
+.matrix th.repo { writing-mode: vertical-rl; transform: rotate(180deg); white-space: nowrap; }
+`;
+
+/** Self-contained HTML page: no scripts or external assets, so it can be attached or archived */
+export function formatOrgReportHtml(report: OrgReport): string {
+  const approval = report.vendors.some((v) => v.approved !== undefined);
+  const out: string[] = [
+    "<!doctype html>",
+    '<html lang="en"><head><meta charset="utf-8">',
+    "<title>Thirdwatch organization report</title>",
+    `<style>${STYLE}</style></head><body>`,
+    "<h1>Third-party dependencies across the organization</h1>",
+    `<p class="meta">${report.vendors.length} vendors in ${report.repositories.length} repositories · generated ${esc(report.generated_at)}</p>`,
+  ];
+
+  if (approval) {
+    out.push("<h2>Top unapproved vendors</h2>");
+    if (report.top_unapproved.length === 0) {
+      out.push("<p>Every vendor in use is approved.</p>");
+    } else {
+      out.push("<table><tr><th>Vendor</th><th>Category</th><th>Repositories</th><th>Usages</th></tr>");
+      for (const v of report.top_unapproved) {
+        out.push(
+          `<tr class="unapproved"><td>${esc(v.display_name)}</td><td>${esc(v.category ?? "")}</td>` +
+            `<td class="n">${v.repositories}</td><td class="n">${v.total_usages}</td></tr>`,
+        );
+      }
+      out.push("</table>");
+    }
+  }
+
+  out.push("<h2>Categories</h2>");
+  out.push("<table><tr><th>Category</th><th>Repositories</th><th>Usages</th><th>Vendors</th></tr>");
+  for (const c of report.categories) {
+    out.push(
+      `<tr><td>${esc(c.category)}</td><td class="n">${c.repositories}</td><td class="n">${c.total_usages}</td>` +
+        `<td>${esc(c.vendors.join(", "))}</td></tr>`,
+    );
+  }
+  out.push("</table>");
+
+  out.push("<h2>Vendors × repositories</h2>");
+  out.push(
+    '<table class="matrix"><tr><th>Vendor</th><th>Category</th><th>Data</th><th>Repos</th>' +
+      report.repositories.map((r) => `<th class="repo">${esc(r)}</th>`).join("") +
+      "</tr>",
+  );
+  for (const v of report.vendors) {
+    const cells = report.repositories
+      .map((repo) => {
+        const n = v.usages[repo];
+        return n === undefined ? '<td class="off">·</td>' : `<td class="n">${n}</td>`;
+      })
+      .join("");
+    out.push(
+      `<tr${v.approved === false ? ' class="unapproved"' : ""}><td>${esc(v.display_name)}</td>` +
+        `<td>${esc(v.category ?? "")}</td><td>${esc((v.data_classification ?? []).join(", "))}</td>` +
+        `<td class="n">${v.repositories}</td>${cells}</tr>`,
+    );
+  }
+  out.push("</table>");
+
+  // Links reviewers would otherwise look up per vendor for DPIA and subprocessor reviews
+  const documented = report.vendors.filter((v) => v.compliance);
+  if (documented.length > 0) {
+    out.push("<h2>Vendor privacy and compliance documents</h2>");
+    out.push("<table><tr><th>Vendor</th><th>Privacy policy</th><th>DPA</th><th>Subprocessors</th><th>Trust center</th></tr>");
+    for (const v of documented) {
+      const cells = COMPLIANCE_URL_KEYS.map((key) => {
+        const url = v.compliance![key];
+        return url ? `<td><a href="${esc(url)}">${esc(new URL(url).hostname)}</a></td>` : '<td class="off">·</td>';
+      }).join("");
+      out.push(`<tr><td>${esc(v.display_name)}</td>${cells}</tr>`);
+    }
+    out.push("</table>");
+  }
+  out.push("</body></html>");
+  return out.join("\n") + "\n";
+}
@@ -0,0 +1,30 @@
+// apps/cli/src/output/runtime-summary.ts — Terminal summary for runtime reports
+import type { TDM } from "@thirdwatch/tdm";
+import pc from "picocolors";
+
+function pad(str: string, len: number): string {
+  return str.length >= len ? str : str + " ".repeat(len - str.length);
+}
+
+export function printRuntimeSummary(tdm: TDM, title: string): void {
+  const apis = [...tdm.apis].sort((a, b) => (b.runtime?.count ?? 0) - (a.runtime?.count ?? 0));
+  const vendors = new Set(apis.map((a) => a.provider).filter(Boolean));
+  const durationSec = (tdm.metadata.scan_duration_ms / 1000).toFixed(1);
+
+  console.log("");
+  console.log(
+    pc.bold(`  ${title} — ${apis.length} destinations, ${vendors.size} known vendors in ${durationSec}s`),
+  );
+  if (apis.length === 0) return;
+
+  console.log("");
+  for (const api of apis) {
+    const vendor = api.provider ? pc.green(pad(api.provider, 16)) : pc.yellow(pad("unknown", 16));
+    const count = `${api.runtime?.count ?? api.usage_count}×`;
+    const procs = api.runtime?.processes?.length ? pc.dim(`  ${api.runtime.processes.join(", ")}`) : "";
+    console.log(`    ${vendor} ${pad(api.url, 48)} ${count}${procs}`);
+    for (const sdk of api.runtime?.sdks ?? []) {
+      console.log(pc.dim(`      ↳ ${sdk.sdk_package}${sdk.version ? ` ${sdk.version}` : ""}${sdk.ecosystem ? ` (${sdk.ecosystem})` : ""}  ${sdk.count}×`));
+    }
+  }
+}
@@ -1,6 +1,7 @@
 // apps/cli/src/output/summary.ts — Human-readable summary table for terminal
-import type { TDM, Confidence } from "@thirdwatch/tdm";
+import type { TDM, Confidence, Severity } from "@thirdwatch/tdm";
 import pc from "picocolors";
+import { vendorsByEnvironment, vendorsByService } from "@thirdwatch/core";
 
 function confidenceDot(confidence: Confidence): string {
   switch (confidence) {
@@ -17,6 +18,19 @@ function confidenceDot(confidence: Confidence): string {
   }
 }
 
+/** Rule ID suffix, colored when `rule_settings` raised the severity */
+function ruleTag(entry: { rule_id?: string; severity?: Severity }): string {
+  if (!entry.rule_id) return "";
+  if (entry.severity === "error") return `  ${pc.red(`${entry.rule_id} (error)`)}`;
+  if (entry.severity === "warning") return `  ${pc.yellow(`${entry.rule_id} (warning)`)}`;
+  return `  ${pc.dim(entry.rule_id)}`;
+}
+
+/** Classification labels, e.g. "  [pci, pii]" */
+function dataTag(entry: { data_classification?: string[] }): string {
+  return entry.data_classification ? `  ${pc.magenta(`[${entry.data_classification.join(", ")}]`)}` : "";
+}
+
 function pad(str: string, len: number): string {
   return str.length >= len ? str : str + " ".repeat(len - str.length);
 }
@@ -26,7 +40,11 @@ function padStart(str: string, len: number): string {
 }
 
 export function printSummaryTable(tdm: TDM, filesScanned: number): void {
-  const { metadata, packages, apis, sdks, infrastructure, webhooks } = tdm;
+  const { metadata, packages, sdks } = tdm;
+  // First-party entries are listed in their own section below
+  const apis = tdm.apis.filter((a) => !a.first_party);
+  const infrastructure = tdm.infrastructure.filter((i) => !i.first_party);
+  const webhooks = tdm.webhooks.filter((w) => !w.first_party);
   const durationSec = (metadata.scan_duration_ms / 1000).toFixed(1);
   const langs = metadata.languages_detected.join(", ");
 
@@ -61,8 +79,12 @@ export function printSummaryTable(tdm: TDM, filesScanned: number): void {
       const url = pad(api.url, 48);
       const calls = padStart(`${api.usage_count} calls`, 9);
       console.log(
-        `    ${confidenceDot(api.confidence)} ${pad(api.confidence, 8)} ${method} ${url} ${calls}`,
+        `    ${confidenceDot(api.confidence)} ${pad(api.confidence, 8)} ${method} ${url} ${calls}${ruleTag(api)}${dataTag(api)}`,
       );
+      const owner = api.enrichment?.registrant ?? api.enrichment?.cert_subject_org ?? api.enrichment?.as_name;
+      if (owner) console.log(pc.dim(`        ↳ operated by ${owner}`));
+      if (api.gateway_bypass) console.log(pc.yellow(`        ↳ calls ${api.provider} directly, bypassing the ${api.gateway_bypass} gateway`));
+      if (api.suggestion) console.log(pc.dim(`        ↳ ${api.suggestion.vendor ?? "vendor"} suggested by ${api.suggestion.model} — confirm before relying on it`));
     }
   }
 
@@ -71,17 +93,24 @@ export function printSummaryTable(tdm: TDM, filesScanned: number): void {
     console.log("");
     console.log(pc.bold(`  🔧 SDKs (${sdks.length})`));
     for (const sdk of sdks) {
-      const services =
-        sdk.services_used && sdk.services_used.length > 0
-          ? sdk.services_used.join(", ")
-          : "";
+      const services = [
+        sdk.services_used && sdk.services_used.length > 0 ? sdk.services_used.join(", ") : "",
+        sdk.regions && sdk.regions.length > 0 ? `@ ${sdk.regions.join(", ")}` : "",
+        sdk.data_exported && sdk.data_exported.length > 0 ? `sends ${sdk.data_exported.join(", ")}` : "",
+        sdk.intents && sdk.intents.length > 0 ? `→ ${sdk.intents.join(", ")}` : "",
+      ]
+        .filter(Boolean)
+        .join(" ");
       const loc =
         sdk.locations.length > 0
           ? `${sdk.locations[0]!.file}:${sdk.locations[0]!.line}`
           : "";
       console.log(
-        `    ${confidenceDot(sdk.confidence)} ${pad(sdk.confidence, 8)} ${pad(sdk.provider, 10)} (${sdk.sdk_package})  ${services || loc}  ${padStart(`${sdk.usage_count} usages`, 10)}`,
+        `    ${confidenceDot(sdk.confidence)} ${pad(sdk.confidence, 8)} ${pad(sdk.provider, 10)} (${sdk.sdk_package})  ${services || loc}  ${padStart(`${sdk.usage_count} usages`, 10)}${ruleTag(sdk)}${dataTag(sdk)}`,
       );
+      if (sdk.unused) console.log(pc.yellow("        ↳ constructed but never called — remove it and its credentials"));
+      if (sdk.gateway_bypass) console.log(pc.yellow(`        ↳ not routed through the ${sdk.gateway_bypass} gateway`));
+      if (sdk.suggestion) console.log(pc.dim(`        ↳ suggested by ${sdk.suggestion.model} — confirm before relying on it`));
     }
   }
 
@@ -92,8 +121,9 @@ export function printSummaryTable(tdm: TDM, filesScanned: number): void {
     for (const infra of infrastructure) {
       const host = infra.resolved_host ?? infra.connection_ref;
       console.log(
-        `    ${confidenceDot(infra.confidence)} ${pad(infra.confidence, 8)} ${pad(infra.type, 14)} ${host}`,
+        `    ${confidenceDot(infra.confidence)} ${pad(infra.confidence, 8)} ${pad(infra.type, 14)} ${host}${ruleTag(infra)}${dataTag(infra)}`,
       );
+      if (infra.unused) console.log(pc.yellow("        ↳ opened but never used"));
     }
   }
 
@@ -104,8 +134,70 @@ export function printSummaryTable(tdm: TDM, filesScanned: number): void {
     for (const wh of webhooks) {
       const dir = wh.direction === "outbound_registration" ? "outbound" : "inbound";
       console.log(
-        `    ${confidenceDot(wh.confidence)} ${pad(wh.confidence, 8)} ${pad(dir, 10)} ${wh.target_url}`,
+        `    ${confidenceDot(wh.confidence)} ${pad(wh.confidence, 8)} ${pad(dir, 10)} ${wh.target_url}${ruleTag(wh)}${dataTag(wh)}`,
+      );
+    }
+  }
+
+  // Internal (first-party) dependencies
+  const internal = [
+    ...tdm.apis.filter((a) => a.first_party).map((a) => `api      ${a.url}`),
+    ...tdm.infrastructure
+      .filter((i) => i.first_party)
+      .map((i) => `${pad(i.type, 8)} ${i.resolved_host ?? i.connection_ref}`),
+    ...tdm.webhooks.filter((w) => w.first_party).map((w) => `webhook  ${w.target_url}`),
+  ];
+  if (internal.length > 0) {
+    console.log("");
+    console.log(pc.bold(`  🏠 Internal dependencies (${internal.length})`));
+    for (const line of internal) {
+      console.log(`    ${pc.gray("●")} ${line}`);
+    }
+  }
+
+  // Vendors per environment, once any config overlay or test key names one
+  const environments = vendorsByEnvironment(tdm);
+  if (environments.some((e) => e.environment !== "all")) {
+    console.log("");
+    console.log(pc.bold("  🌍 Vendors by environment"));
+    for (const { environment, vendors } of environments) {
+      console.log(`    ${pad(environment, 12)} ${vendors.join(", ")}`);
+    }
+  }
+
+  // Vendors per logical service, once `services` maps any directory
+  const services = vendorsByService(tdm);
+  if (services.some((s) => s.service !== "(unmapped)")) {
+    console.log("");
+    console.log(pc.bold("  🧩 Vendors by service"));
+    const width = Math.max(12, ...services.map((s) => s.service.length + 1));
+    for (const { service, vendors } of services) {
+      console.log(`    ${pad(service, width)} ${vendors.join(", ")}`);
+    }
+  }
+
+  // Hardcoded secrets — oldest first, since those are the most urgent to rotate
+  const secrets = [
+    ...tdm.packages,
+    ...tdm.apis,
+    ...tdm.sdks,
+    ...tdm.infrastructure,
+    ...tdm.webhooks,
+  ].flatMap((e) => e.locations.filter((l) => l.secret));
+  if (secrets.length > 0) {
+    secrets.sort((a, b) => (b.secret!.age_days ?? -1) - (a.secret!.age_days ?? -1));
+    console.log("");
+    console.log(pc.bold(`  🔑 Hardcoded secrets (${secrets.length})`));
+    for (const loc of secrets) {
+      const age =
+        loc.secret!.age_days !== undefined
+          ? `live ${loc.secret!.age_days}d since ${loc.secret!.introduced_commit!.slice(0, 8)}`
+          : "not in git history";
+      const privilege = loc.secret!.privilege ? pc.dim(` ${loc.secret!.privilege} ${loc.secret!.key_type!.replace(/_/g, " ")}`) : "";
+      console.log(
+        `    ${pc.red("●")} ${pad(loc.secret!.kind, 28)} ${pad(`${loc.file}:${loc.line}`, 32)} ${age}${privilege}`,
       );
+      if (loc.secret!.overly_broad) console.log(`      ${pc.yellow("↳")} ${loc.secret!.overly_broad}`);
     }
   }
 
@@ -117,6 +209,7 @@ export function printSummaryTable(tdm: TDM, filesScanned: number): void {
   if (infrastructure.length > 0)
     sections.push(`${infrastructure.length} infrastructure`);
   if (webhooks.length > 0) sections.push(`${webhooks.length} webhooks`);
+  if (internal.length > 0) sections.push(`${internal.length} internal`);
 
   console.log("");
   console.log(
@@ -0,0 +1,57 @@
+// apps/cli/src/runtime-output.ts — Shared plumbing for runtime commands (agent, proxy, …)
+import { dirname, resolve, sep } from "node:path";
+import { fileURLToPath } from "node:url";
+import { writeFile } from "node:fs/promises";
+import type { TDM } from "@thirdwatch/tdm";
+import { loadSDKRegistry, resolveCatalog } from "@thirdwatch/core";
+import type { SDKRegistryEntry } from "@thirdwatch/core";
+import { formatJson } from "./output/json.js";
+import { formatYaml } from "./output/yaml.js";
+
+const __dirname = dirname(fileURLToPath(import.meta.url));
+
+export interface RuntimeOutputOpts {
+  output: string;
+  format: string;
+  catalogVersion?: string;
+  catalogBundle?: string;
+}
+
+/** Catalog used to classify destinations: remote bundle if selected, else built-in */
+export async function loadRuntimeCatalog(
+  opts: Pick<RuntimeOutputOpts, "catalogVersion" | "catalogBundle">,
+): Promise<{ registry: SDKRegistryEntry[]; catalogVersion?: string }> {
+  const catalog = await resolveCatalog({
+    ...(opts.catalogBundle ? { bundlePath: opts.catalogBundle } : {}),
+    ...(opts.catalogVersion ? { version: opts.catalogVersion } : {}),
+  });
+  if (catalog) return { registry: catalog.entries, catalogVersion: catalog.version };
+  return { registry: await loadSDKRegistry(resolve(__dirname, "../../../registries")) };
+}
+
+/**
+ * Validate --format / --output before doing any work. Returns the absolute
+ * output path ("" for stdout), or null after printing an error.
+ */
+export function checkRuntimeOutput(opts: RuntimeOutputOpts): string | null {
+  if (opts.format !== "json" && opts.format !== "yaml") {
+    console.error(`Error: Invalid format "${opts.format}". Use "json" or "yaml".`);
+    process.exitCode = 2;
+    return null;
+  }
+  if (opts.output === "-") return "";
+  const outputPath = resolve(opts.output);
+  const basePath = resolve(process.cwd());
+  if (!outputPath.startsWith(basePath + sep) && outputPath !== basePath) {
+    console.error("Error: Output path must be within the current working directory.");
+    process.exitCode = 2;
+    return null;
+  }
+  return outputPath;
+}
+
+export async function writeRuntimeReport(tdm: TDM, format: string, outputPath: string): Promise<void> {
+  const output = format === "yaml" ? formatYaml(tdm) : formatJson(tdm);
+  if (outputPath) await writeFile(outputPath, output, "utf8");
+  else process.stdout.write(output);
+}
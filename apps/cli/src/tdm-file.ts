@@ -0,0 +1,28 @@
+// apps/cli/src/tdm-file.ts — Read a TDM written by scan or a runtime command
+import { readdir, readFile } from "node:fs/promises";
+import { extname, join, resolve } from "node:path";
+import yaml from "js-yaml";
+import { parseTDM } from "@thirdwatch/tdm";
+import type { TDM } from "@thirdwatch/tdm";
+
+/** Parse and validate a JSON or YAML TDM file */
+export async function readTDM(file: string): Promise<TDM> {
+  const content = await readFile(resolve(file), "utf8");
+  const ext = extname(file);
+  const raw: unknown = ext === ".yml" || ext === ".yaml" ? yaml.load(content) : JSON.parse(content);
+  return parseTDM(raw);
+}
+
+/** JSON and YAML files under `dir`, sorted; skips node_modules and dot-directories */
+export async function findTDMFiles(dir: string): Promise<string[]> {
+  const files: string[] = [];
+  for (const entry of await readdir(dir, { withFileTypes: true })) {
+    const path = join(dir, entry.name);
+    if (entry.isDirectory()) {
+      if (entry.name !== "node_modules" && !entry.name.startsWith(".")) files.push(...(await findTDMFiles(path)));
+    } else if (/\.(json|ya?ml)$/.test(entry.name)) {
+      files.push(path);
+    }
+  }
+  return files.sort();
+}
@@ -1,14 +1,16 @@
 // apps/cli/src/update-check.ts — Non-blocking version check on startup
 import pc from "picocolors";
+import { isOffline } from "@thirdwatch/core";
 
 export async function checkForUpdates(
   currentVersion: string,
 ): Promise<void> {
-  // Skip in non-TTY (piping), CI, or when explicitly suppressed
+  // Skip in non-TTY (piping), CI, offline mode, or when explicitly suppressed
   if (
     !process.stderr.isTTY ||
     process.env["CI"] ||
-    process.env["NO_UPDATE_NOTIFICATION"]
+    process.env["NO_UPDATE_NOTIFICATION"] ||
+    isOffline()
   ) {
     return;
   }
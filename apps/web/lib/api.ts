@@ -68,6 +68,59 @@ export const api = {
       "/api/v1/notifications/routing",
       { token },
     ),
+  getOidcConfig: () =>
+    apiFetch<{
+      issuer: string;
+      authorization_endpoint: string;
+      client_id: string;
+      scopes: string;
+    }>("/api/v1/auth/oidc/config"),
+  loginWithOidc: (body: {
+    code: string;
+    codeVerifier: string;
+    redirectUri: string;
+    nonce: string;
+  }) =>
+    apiFetch<{
+      token: string;
+      org: { id: string; name: string; plan: string };
+      user: { id: string; email: string | null; name: string | null; role: string };
+    }>("/api/v1/auth/oidc", { method: "POST", body }),
+  getUsers: (token: string) =>
+    apiFetch<{ users: Record<string, unknown>[] }>("/api/v1/org/users", {
+      token,
+    }),
+  updateUserRole: (token: string, id: string, role: string) =>
+    apiFetch<Record<string, unknown>>(`/api/v1/org/users/${id}`, {
+      token,
+      method: "PATCH",
+      body: { role },
+    }),
+  getVendorApprovals: (token: string) =>
+    apiFetch<{ approvals: Record<string, unknown>[] }>(
+      "/api/v1/vendors/approvals",
+      { token },
+    ),
+  setVendorApproval: (
+    token: string,
+    vendor: string,
+    status: "approved" | "denied",
+    note?: string,
+  ) =>
+    apiFetch<Record<string, unknown>>(
+      `/api/v1/vendors/${encodeURIComponent(vendor)}/approval`,
+      { token, method: "PUT", body: { status, note } },
+    ),
+  getSuppressions: (token: string) =>
+    apiFetch<{ suppressions: Record<string, unknown>[] }>(
+      "/api/v1/suppressions",
+      { token },
+    ),
+  getAuditLog: (token: string, params?: string) =>
+    apiFetch<{ entries: Record<string, unknown>[]; next_cursor: number }>(
+      `/api/v1/audit-log${params ? `?${params}` : ""}`,
+      { token },
+    ),
   createCheckout: (token: string, plan: string) =>
     apiFetch<{ url: string }>("/api/v1/billing/checkout", {
       token,
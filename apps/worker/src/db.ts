@@ -102,6 +102,7 @@ export const workerDb = {
       humanSummary: "human_summary",
       notified: "notified",
       notifiedAt: "notified_at",
+      suppressedBy: "suppressed_by",
     };
     const sets: string[] = [];
     const values: unknown[] = [];
@@ -185,6 +186,22 @@ export const workerDb = {
     return result.rows;
   },
 
+  async getActiveSuppressions(orgId: string) {
+    const result = await pool.query(
+      `SELECT id, repository, dependency, change_category, min_priority FROM suppressions
+       WHERE org_id = $1 AND (expires_at IS NULL OR expires_at > now())
+       ORDER BY created_at`,
+      [orgId],
+    );
+    return result.rows as Array<{
+      id: string;
+      repository: string;
+      dependency: string | null;
+      change_category: string | null;
+      min_priority: string | null;
+    }>;
+  },
+
   async insertNotificationLog(data: {
     orgId: string;
     changeEventId: string;
@@ -213,4 +230,74 @@ export const workerDb = {
     );
     return result.rows[0] ?? null;
   },
+
+  async getTeamsDueForDigest() {
+    const result = await pool.query(
+      `SELECT * FROM teams
+       WHERE digest_schedule <> 'off'
+         AND (last_digest_at IS NULL
+              OR last_digest_at <= now() - CASE digest_schedule WHEN 'daily' THEN interval '1 day' ELSE interval '7 days' END)`,
+    );
+    return result.rows;
+  },
+
+  /** Vendors currently in each repository: the latest feed event per pair is an addition */
+  async getVendorFootprint(orgId: string) {
+    const result = await pool.query(
+      `SELECT repository, vendor FROM (
+         SELECT DISTINCT ON (repository, vendor) repository, vendor, change
+         FROM vendor_events WHERE org_id = $1
+         ORDER BY repository, vendor, seq DESC
+       ) latest
+       WHERE change = 'added'`,
+      [orgId],
+    );
+    return result.rows as Array<{ repository: string; vendor: string }>;
+  },
+
+  async getVendorEventsBetween(orgId: string, since: Date, until: Date) {
+    const result = await pool.query(
+      `SELECT repository, vendor, change, commit_sha FROM vendor_events
+       WHERE org_id = $1 AND occurred_at > $2 AND occurred_at <= $3
+       ORDER BY seq`,
+      [orgId, since, until],
+    );
+    return result.rows as Array<{ repository: string; vendor: string; change: "added" | "removed"; commit_sha: string | null }>;
+  },
+
+  async getRecentHighPriorityChanges(orgId: string, since: Date) {
+    const result = await pool.query(
+      `SELECT ce.title, ce.url, ce.priority, wd.identifier, wd.repositories
+       FROM change_events ce
+       JOIN watched_dependencies wd ON wd.id = ce.dependency_id
+       WHERE ce.org_id = $1 AND ce.priority IN ('P0', 'P1') AND ce.detected_at > $2
+       ORDER BY ce.priority, ce.detected_at DESC`,
+      [orgId, since],
+    );
+    return result.rows as Array<{
+      title: string;
+      url: string | null;
+      priority: string;
+      identifier: string;
+      repositories: string[] | null;
+    }>;
+  },
+
+  async insertTeamDigest(data: {
+    teamId: string;
+    periodStart: Date;
+    periodEnd: Date;
+    digest: unknown;
+    deliveredTo: string[];
+  }) {
+    await pool.query(
+      `INSERT INTO team_digests (team_id, period_start, period_end, digest, delivered_to)
+       VALUES ($1, $2, $3, $4, $5)`,
+      [data.teamId, data.periodStart, data.periodEnd, JSON.stringify(data.digest), data.deliveredTo],
+    );
+    await pool.query(
+      `UPDATE teams SET last_digest_at = $2 WHERE id = $1`,
+      [data.teamId, data.periodEnd],
+    );
+  },
 };
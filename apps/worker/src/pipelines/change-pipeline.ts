@@ -1,3 +1,4 @@
+import { matchesRepository } from "@thirdwatch/notifier";
 import { workerDb } from "../db.js";
 
 interface WatchedDep {
@@ -126,6 +127,33 @@ function computePriority(
   return { priority: "P4", score };
 }
 
+const PRIORITY_RANK: Record<string, number> = { P0: 0, P1: 1, P2: 2, P3: 3, P4: 4 };
+
+type Suppression = Awaited<ReturnType<typeof workerDb.getActiveSuppressions>>[number];
+
+/**
+ * Whether a suppression created through the API covers this change. As with
+ * .thirdwatch.yml suppression rules, every matcher set must match and
+ * min_priority suppresses changes of lower priority than it.
+ */
+function matchesSuppression(
+  suppression: Suppression,
+  change: { identifier: string; category: string; priority: string; repositories: string[] },
+): boolean {
+  if (suppression.dependency && !matchesRepository(change.identifier, [suppression.dependency])) return false;
+  if (suppression.change_category && suppression.change_category !== change.category) return false;
+  if (
+    suppression.min_priority &&
+    !((PRIORITY_RANK[change.priority] ?? 4) > (PRIORITY_RANK[suppression.min_priority] ?? 4))
+  ) {
+    return false;
+  }
+  if (suppression.repository !== "*" && !change.repositories.some((r) => matchesRepository(r, [suppression.repository]))) {
+    return false;
+  }
+  return true;
+}
+
 export async function processNewVersion(
   dependency: WatchedDep,
   newVersion: string,
@@ -244,7 +272,19 @@ export async function processNewVersion(
 
   // 4. Notify — currently log-only; actual delivery via @thirdwatch/notifier
   //    (Slack, GitHub, Jira, webhook) is deferred to a follow-up.
-  const routes = await workerDb.getRoutingRules(dependency.org_id);
+  //    Suppressed changes are recorded but not routed.
+  const suppression = (await workerDb.getActiveSuppressions(dependency.org_id)).find((s) =>
+    matchesSuppression(s, {
+      identifier: dependency.identifier,
+      category: classification.category,
+      priority,
+      repositories: repos,
+    }),
+  );
+  if (suppression) {
+    await workerDb.updateChangeEvent(changeEvent.id, { suppressedBy: suppression.id });
+  }
+  const routes = suppression ? [] : await workerDb.getRoutingRules(dependency.org_id);
   for (const route of routes) {
     const priorities = (route.priority as string[] | null) ?? [];
     if (priorities.length > 0 && !priorities.includes(priority)) continue;
@@ -264,10 +304,12 @@ export async function processNewVersion(
     });
   }
 
-  await workerDb.updateChangeEvent(changeEvent.id, {
-    notified: true,
-    notifiedAt: new Date(),
-  });
+  if (!suppression) {
+    await workerDb.updateChangeEvent(changeEvent.id, {
+      notified: true,
+      notifiedAt: new Date(),
+    });
+  }
 
   // 5. Update dependency's last seen version
   await workerDb.updateWatchedDependency(dependency.id, {
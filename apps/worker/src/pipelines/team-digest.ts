@@ -0,0 +1,119 @@
+import {
+  buildTeamDigest,
+  digestSubject,
+  matchesRepository,
+  renderDigestText,
+  sendMail,
+  sendSlackDigest,
+} from "@thirdwatch/notifier";
+import { workerDb } from "../db.js";
+
+const SMTP_URL = process.env["SMTP_URL"];
+const DIGEST_FROM = process.env["DIGEST_FROM"] ?? "Thirdwatch <digest@thirdwatch.local>";
+
+/** Open changes older than this are left out of digests */
+const OPEN_CHANGE_WINDOW_MS = 30 * 24 * 60 * 60 * 1000;
+const SCHEDULE_MS: Record<string, number> = {
+  daily: 24 * 60 * 60 * 1000,
+  weekly: 7 * 24 * 60 * 60 * 1000,
+};
+
+interface TeamRow {
+  id: string;
+  org_id: string;
+  name: string;
+  repositories: string[];
+  denied_vendors: string[];
+  digest_schedule: string;
+  digest_emails: string[];
+  slack_webhook_url: string | null;
+  last_digest_at: string | null;
+}
+
+export async function sendTeamDigest(team: TeamRow, now = new Date()): Promise<void> {
+  const periodEnd = now;
+  const periodStart = team.last_digest_at
+    ? new Date(team.last_digest_at)
+    : new Date(now.getTime() - (SCHEDULE_MS[team.digest_schedule] ?? SCHEDULE_MS["weekly"]!));
+  const owns = (repository: string) => matchesRepository(repository, team.repositories);
+
+  const footprint = new Map<string, string[]>();
+  for (const row of await workerDb.getVendorFootprint(team.org_id)) {
+    if (!owns(row.repository)) continue;
+    footprint.set(row.repository, [...(footprint.get(row.repository) ?? []), row.vendor]);
+  }
+  const events = await workerDb.getVendorEventsBetween(team.org_id, periodStart, periodEnd);
+  const changes = await workerDb.getRecentHighPriorityChanges(
+    team.org_id,
+    new Date(now.getTime() - OPEN_CHANGE_WINDOW_MS),
+  );
+
+  const digest = buildTeamDigest({
+    team: team.name,
+    periodStart: periodStart.toISOString(),
+    periodEnd: periodEnd.toISOString(),
+    footprint: [...footprint].map(([repository, vendors]) => ({ repository, vendors })),
+    vendorChanges: events
+      .filter((e) => owns(e.repository))
+      .map((e) => ({ repository: e.repository, vendor: e.vendor, change: e.change, commit: e.commit_sha })),
+    openChanges: changes
+      .map((c) => ({
+        dependency: c.identifier,
+        priority: c.priority,
+        title: c.title,
+        url: c.url ?? undefined,
+        repositories: (c.repositories ?? []).filter(owns),
+      }))
+      .filter((c) => c.repositories.length > 0),
+    deniedVendors: team.denied_vendors,
+  });
+
+  const deliveredTo: string[] = [];
+  if (team.slack_webhook_url) {
+    try {
+      await sendSlackDigest(team.slack_webhook_url, digest);
+      deliveredTo.push("slack");
+    } catch (err) {
+      console.error(`[digest] Slack delivery failed for team ${team.name}:`, err);
+    }
+  }
+  if (team.digest_emails.length > 0) {
+    if (!SMTP_URL) {
+      console.warn(`[digest] SMTP_URL is not set; skipping email for team ${team.name}`);
+    } else {
+      try {
+        await sendMail(
+          { url: SMTP_URL, from: DIGEST_FROM },
+          { to: team.digest_emails, subject: digestSubject(digest), text: renderDigestText(digest) },
+        );
+        deliveredTo.push(...team.digest_emails);
+      } catch (err) {
+        console.error(`[digest] Email delivery failed for team ${team.name}:`, err);
+      }
+    }
+  }
+
+  // Stored even when delivery failed, so it can be read from the API
+  await workerDb.insertTeamDigest({ teamId: team.id, periodStart, periodEnd, digest, deliveredTo });
+  console.log(`[digest] ${team.name}: ${digestSubject(digest)} → ${deliveredTo.join(", ") || "stored only"}`);
+}
+
+let running = false;
+
+export async function scheduleDigests(): Promise<void> {
+  // A slow SMTP server must not let the next tick send the same digests again
+  if (running) return;
+  running = true;
+  try {
+    const teams = (await workerDb.getTeamsDueForDigest()) as TeamRow[];
+    for (const team of teams) {
+      try {
+        await sendTeamDigest(team);
+      } catch (err) {
+        console.error(`[digest] Failed to build digest for team ${team.name}:`, err);
+      }
+    }
+  } finally {
+    running = false;
+  }
+}
@@ -1,5 +1,6 @@
 import { Queue } from "bullmq";
 import { workerDb } from "./db.js";
+import { scheduleDigests } from "./pipelines/team-digest.js";
 
 const REDIS_URL = process.env["REDIS_URL"] ?? "redis://localhost:6379";
 
@@ -58,6 +59,9 @@ export function startScheduler(): ReturnType<typeof setInterval> {
     scheduleChecks().catch((err: unknown) => {
       console.error("[scheduler] Error scheduling checks:", err);
     });
+    scheduleDigests().catch((err: unknown) => {
+      console.error("[scheduler] Error sending team digests:", err);
+    });
   }, 5 * 60 * 1000);
 
   scheduleChecks().catch((err: unknown) => {
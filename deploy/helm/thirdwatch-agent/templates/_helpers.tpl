@@ -0,0 +1,19 @@
+{{- define "thirdwatch-agent.name" -}}
+{{- .Release.Name | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{- define "thirdwatch-agent.labels" -}}
+app.kubernetes.io/name: thirdwatch-agent
+app.kubernetes.io/instance: {{ .Release.Name }}
+app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
+helm.sh/chart: {{ .Chart.Name }}-{{ .Chart.Version }}
+{{- end -}}
+
+{{- define "thirdwatch-agent.selectorLabels" -}}
+app.kubernetes.io/name: thirdwatch-agent
+app.kubernetes.io/instance: {{ .Release.Name }}
+{{- end -}}
+
+{{- define "thirdwatch-agent.secretName" -}}
+{{- default (include "thirdwatch-agent.name" .) .Values.server.existingSecret -}}
+{{- end -}}
@@ -0,0 +1,3 @@
+#!/bin/sh
+set -e
+geoipupdate -f /etc/GeoIP.conf -d /var/lib/GeoIP && systemctl reload edge
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"context"
+	"os"
+
+	"github.com/auth0/go-auth0/management"
+	"github.com/coreos/go-oidc/v3/oidc"
+	jwtverifier "github.com/okta/okta-jwt-verifier-golang"
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+func Setup(ctx context.Context) error {
+	// Google accounts via generic OIDC discovery
+	provider, err := oidc.NewProvider(ctx, "https://accounts.google.com")
+	if err != nil {
+		return err
+	}
+	_ = provider
+
+	// Auth0 Management API
+	mgmt, err := management.New("acme.us.auth0.com", management.WithClientCredentials(ctx, os.Getenv("AUTH0_CLIENT_ID"), os.Getenv("AUTH0_CLIENT_SECRET")))
+	if err != nil {
+		return err
+	}
+	_ = mgmt
+
+	// Okta
+	_, client, err := okta.NewClient(ctx, okta.WithOrgUrl("https://acme.okta.com"), okta.WithToken(os.Getenv("OKTA_API_TOKEN")))
+	if err != nil {
+		return err
+	}
+	_ = client
+	verifier := jwtverifier.JwtVerifier{
+		Issuer: "https://acme.okta.com/oauth2/default",
+	}
+	_ = verifier
+	return nil
+}
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"os"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/oauth2/endpoints"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/microsoft"
+)
+
+// GitHubLogin is the "Sign in with GitHub" flow.
+var GitHubLogin = &oauth2.Config{
+	ClientID:     os.Getenv("GITHUB_CLIENT_ID"),
+	ClientSecret: os.Getenv("GITHUB_CLIENT_SECRET"),
+	Endpoint:     github.Endpoint,
+	Scopes:       []string{"read:user", "user:email"},
+}
+
+// WorkforceLogin signs employees in through the company's Entra ID tenant.
+var WorkforceLogin = &oauth2.Config{
+	ClientID: os.Getenv("ENTRA_CLIENT_ID"),
+	Endpoint: microsoft.AzureADEndpoint("acme.onmicrosoft.com"),
+}
+
+// SlackInstall is the "Add to Slack" flow.
+var SlackInstall = &oauth2.Config{ClientID: os.Getenv("SLACK_CLIENT_ID"), Endpoint: endpoints.Slack}
+
+// PartnerAPI authenticates to the logistics partner with client credentials.
+var PartnerAPI = &clientcredentials.Config{
+	ClientID:     os.Getenv("PARTNER_CLIENT_ID"),
+	ClientSecret: os.Getenv("PARTNER_CLIENT_SECRET"),
+	TokenURL:     "https://auth.logistics-partner.com/oauth2/token",
+}
@@ -0,0 +1,48 @@
+package baas
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+
+	firebase "firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/messaging"
+	"github.com/supabase-community/supabase-go"
+)
+
+func NewFirebase(ctx context.Context) (*firebase.App, error) {
+	return firebase.NewApp(ctx, &firebase.Config{
+		DatabaseURL: "https://acme-default-rtdb.firebaseio.com",
+		ProjectID:   "acme",
+	})
+}
+
+func NotifyOrder(ctx context.Context, app *firebase.App, token string) error {
+	client, err := app.Messaging(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.Send(ctx, &messaging.Message{Token: token})
+	return err
+}
+
+func SaveOrder(ctx context.Context, app *firebase.App, id string) error {
+	db, err := app.Database(ctx)
+	if err != nil {
+		return err
+	}
+	return db.NewRef("orders/"+id).Set(ctx, map[string]bool{"paid": true})
+}
+
+func LegacyPush(body []byte) (*http.Response, error) {
+	req, err := http.NewRequest("POST", "https://fcm.googleapis.com/fcm/send", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
+
+func NewSupabase() (*supabase.Client, error) {
+	return supabase.NewClient("https://xyzcompany.supabase.co", os.Getenv("SUPABASE_SERVICE_ROLE_KEY"), nil)
+}
@@ -0,0 +1,38 @@
+package banking
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/plaid/plaid-go/v20/plaid"
+)
+
+// NewPlaid reads linked account balances from Plaid production.
+func NewPlaid() *plaid.APIClient {
+	cfg := plaid.NewConfiguration()
+	cfg.AddDefaultHeader("PLAID-CLIENT-ID", os.Getenv("PLAID_CLIENT_ID"))
+	cfg.AddDefaultHeader("PLAID-SECRET", os.Getenv("PLAID_SECRET"))
+	cfg.UseEnvironment(plaid.Production)
+	return plaid.NewAPIClient(cfg)
+}
+
+// TrueLayerAccounts lists the accounts a UK customer connected through TrueLayer.
+func TrueLayerAccounts(ctx context.Context, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.truelayer.com/data/v1/accounts", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return http.DefaultClient.Do(req)
+}
+
+// YodleeTransactions pulls aggregated transactions from Yodlee.
+func YodleeTransactions(ctx context.Context, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://production.api.yodlee.com/ysl/transactions", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return http.DefaultClient.Do(req)
+}
@@ -0,0 +1,37 @@
+package billing
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/chargebee/chargebee-go/v3"
+	"github.com/recurly/recurly-client-go/v4"
+	"github.com/taxjar/taxjar-go"
+)
+
+// ConfigureChargebee points the Chargebee SDK at the production site.
+func ConfigureChargebee() {
+	chargebee.Configure(os.Getenv("CHARGEBEE_API_KEY"), "acme")
+}
+
+// NewRecurly manages legacy plans still billed through Recurly.
+func NewRecurly() (*recurly.Client, error) {
+	return recurly.NewClient(os.Getenv("RECURLY_API_KEY"))
+}
+
+// NewTaxJar calculates US sales tax at checkout.
+func NewTaxJar() taxjar.Config {
+	return taxjar.NewClient(taxjar.Config{APIKey: os.Getenv("TAXJAR_API_KEY")})
+}
+
+// CommitAvalaraTransaction records an invoice with AvaTax for filing.
+func CommitAvalaraTransaction(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://rest.avatax.com/api/v2/transactions/create", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(os.Getenv("AVALARA_ACCOUNT_ID"), os.Getenv("AVALARA_LICENSE_KEY"))
+	return http.DefaultClient.Do(req)
+}
@@ -0,0 +1,39 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+
+	recaptcha "cloud.google.com/go/recaptchaenterprise/v2/apiv1"
+)
+
+// NewAssessor scores signups with reCAPTCHA Enterprise.
+func NewAssessor(ctx context.Context) (*recaptcha.Client, error) {
+	return recaptcha.NewClient(ctx)
+}
+
+// VerifyRecaptcha checks a classic reCAPTCHA v3 token from the signup form.
+func VerifyRecaptcha(token string) (*http.Response, error) {
+	return http.PostForm("https://www.google.com/recaptcha/api/siteverify", url.Values{
+		"secret":   {os.Getenv("RECAPTCHA_SECRET_KEY")},
+		"response": {token},
+	})
+}
+
+// VerifyHCaptcha checks the contact form's hCaptcha token.
+func VerifyHCaptcha(token string) (*http.Response, error) {
+	return http.PostForm("https://api.hcaptcha.com/siteverify", url.Values{
+		"secret":   {os.Getenv("HCAPTCHA_SECRET")},
+		"response": {token},
+	})
+}
+
+// VerifyTurnstile checks the login page's Cloudflare Turnstile token.
+func VerifyTurnstile(token string) (*http.Response, error) {
+	return http.PostForm("https://challenges.cloudflare.com/turnstile/v0/siteverify", url.Values{
+		"secret":   {os.Getenv("TURNSTILE_SECRET_KEY")},
+		"response": {token},
+	})
+}
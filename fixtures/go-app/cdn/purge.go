@@ -0,0 +1,47 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/edgegrid"
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/fastly/go-fastly/v9/fastly"
+)
+
+// NewCloudflare manages DNS records and page rules for the marketing site.
+func NewCloudflare() (*cloudflare.API, error) {
+	return cloudflare.NewWithAPIToken(os.Getenv("CLOUDFLARE_API_TOKEN"))
+}
+
+// NewFastly purges product images after a catalog import.
+func NewFastly() (*fastly.Client, error) {
+	return fastly.NewClient(os.Getenv("FASTLY_API_TOKEN"))
+}
+
+// NewAkamaiSigner signs Property Manager requests with ~/.edgerc credentials.
+func NewAkamaiSigner() (*edgegrid.Config, error) {
+	return edgegrid.New(edgegrid.WithEnv(true))
+}
+
+// PurgeZone clears Cloudflare's cache for the storefront zone.
+func PurgeZone(ctx context.Context) (*http.Response, error) {
+	body := bytes.NewReader([]byte(`{"purge_everything":true}`))
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.cloudflare.com/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/purge_cache", body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("CLOUDFLARE_API_TOKEN"))
+	return http.DefaultClient.Do(req)
+}
+
+// InvalidateURLs asks Akamai Fast Purge to drop cached pages.
+func InvalidateURLs(ctx context.Context, client *http.Client, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://akab-hqbvzjbjtcbss3xv.purge.akamaiapis.net/ccu/v3/invalidate/url/production", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
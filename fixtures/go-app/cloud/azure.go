@@ -0,0 +1,37 @@
+package cloud
+
+import (
+	"context"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/ai/azopenai"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+)
+
+func NewAzureClients(ctx context.Context) error {
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return err
+	}
+
+	// Blob Storage
+	blobs, _ := azblob.NewClient("https://acmeuploads.blob.core.windows.net/", cred, nil)
+	_ = blobs
+
+	// Cosmos DB
+	cosmos, _ := azcosmos.NewClient("https://acme-orders.documents.azure.com:443/", cred, nil)
+	_ = cosmos
+
+	// Service Bus
+	bus, _ := azservicebus.NewClient("acme.servicebus.windows.net", cred, nil)
+	_ = bus
+
+	// Azure OpenAI
+	llm, _ := azopenai.NewClient(os.Getenv("AZURE_OPENAI_ENDPOINT"), cred, nil)
+	_ = llm
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package cloud
+
+import (
+	"context"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/pubsub"
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"cloud.google.com/go/spanner"
+	"cloud.google.com/go/storage"
+	"cloud.google.com/go/vertexai/genai"
+	"google.golang.org/api/option"
+)
+
+func NewGCPClients(ctx context.Context, project string) error {
+	// Cloud Storage
+	gcs, err := storage.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer gcs.Close()
+
+	// BigQuery
+	bq, _ := bigquery.NewClient(ctx, project)
+	_ = bq
+
+	// Pub/Sub
+	ps, _ := pubsub.NewClient(ctx, project)
+	_ = ps
+
+	// Firestore
+	fs, _ := firestore.NewClient(ctx, project)
+	_ = fs
+
+	// Spanner
+	db, _ := spanner.NewClient(ctx, "projects/"+project+"/instances/main/databases/orders")
+	_ = db
+
+	// Vertex AI
+	llm, _ := genai.NewClient(ctx, project, "us-central1",
+		option.WithEndpoint("https://us-central1-aiplatform.googleapis.com"))
+	_ = llm
+
+	// Secret Manager (no dedicated entry)
+	secrets, _ := secretmanager.NewClient(ctx)
+	_ = secrets
+
+	return nil
+}
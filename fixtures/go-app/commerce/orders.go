@@ -0,0 +1,46 @@
+package commerce
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+
+	goshopify "github.com/bold-commerce/go-shopify/v4"
+)
+
+// NewShopify syncs orders from the Acme storefront.
+func NewShopify() (*goshopify.Client, error) {
+	app := goshopify.App{ApiKey: os.Getenv("SHOPIFY_API_KEY"), ApiSecret: os.Getenv("SHOPIFY_API_SECRET")}
+	return goshopify.NewClient(app, "acme-store", os.Getenv("SHOPIFY_ACCESS_TOKEN"))
+}
+
+// FulfillShopifyOrder posts a fulfillment through the Admin GraphQL API.
+func FulfillShopifyOrder(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://acme-store.myshopify.com/admin/api/2024-07/graphql.json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Shopify-Access-Token", os.Getenv("SHOPIFY_ACCESS_TOKEN"))
+	return http.DefaultClient.Do(req)
+}
+
+// WooCommerceOrders lists orders from the wholesale WordPress store.
+func WooCommerceOrders(ctx context.Context) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://wholesale.acme.example/wp-json/wc/v3/orders", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(os.Getenv("WC_CONSUMER_KEY"), os.Getenv("WC_CONSUMER_SECRET"))
+	return http.DefaultClient.Do(req)
+}
+
+// EbayOrders pulls marketplace orders awaiting fulfillment.
+func EbayOrders(ctx context.Context, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.ebay.com/sell/fulfillment/v1/order", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return http.DefaultClient.Do(req)
+}
@@ -0,0 +1,37 @@
+package documents
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/jfcote87/esign"
+	"github.com/jfcote87/esign/v2.1/envelopes"
+	"github.com/jfcote87/esign/v2.1/model"
+)
+
+// SendContract emails an offer letter for signature through DocuSign.
+func SendContract(ctx context.Context, cred esign.Credential, env *model.EnvelopeDefinition) (*model.EnvelopeSummary, error) {
+	return envelopes.New(cred).Create(env).Do(ctx)
+}
+
+// SendNDA asks a contractor to sign the NDA through Dropbox Sign.
+func SendNDA(ctx context.Context, form []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.hellosign.com/v3/signature_request/send", bytes.NewReader(form))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(os.Getenv("HELLOSIGN_API_KEY"), "")
+	return http.DefaultClient.Do(req)
+}
+
+// CreateQuote drafts a sales quote from a PandaDoc template.
+func CreateQuote(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.pandadoc.com/public/v1/documents", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "API-Key "+os.Getenv("PANDADOC_API_KEY"))
+	return http.DefaultClient.Do(req)
+}
@@ -0,0 +1,31 @@
+package errors
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/bugsnag/bugsnag-go/v2"
+	"github.com/getsentry/sentry-go"
+	sentryhttp "github.com/getsentry/sentry-go/http"
+	"github.com/rollbar/rollbar-go"
+)
+
+func Setup(mux *http.ServeMux) http.Handler {
+	// Sentry, with request bodies and user IPs attached to events
+	sentry.Init(sentry.ClientOptions{
+		Dsn:            "https://0123456789abcdef0123456789abcdef@o42.ingest.sentry.io/4504",
+		SendDefaultPII: true,
+	})
+	handler := sentryhttp.New(sentryhttp.Options{}).Handle(mux)
+
+	// Bugsnag
+	bugsnag.Configure(bugsnag.Configuration{
+		APIKey:       os.Getenv("BUGSNAG_API_KEY"),
+		ReleaseStage: "production",
+	})
+
+	// Rollbar
+	rollbar.SetToken(os.Getenv("ROLLBAR_ACCESS_TOKEN"))
+	rollbar.SetEnvironment("production")
+	return handler
+}
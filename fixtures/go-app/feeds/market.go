@@ -0,0 +1,28 @@
+package feeds
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Quotes streams prices from the exchange's FIX gateway, which has no DNS name.
+func Quotes() (net.Conn, error) {
+	return net.Dial("tcp", "34.120.18.9:9878")
+}
+
+// Settlement connects to the clearing house's TLS drop.
+func Settlement(cfg *tls.Config) (*tls.Conn, error) {
+	return tls.Dial("tcp", "drop.clearing-example.net:7001", cfg)
+}
+
+// Metrics sends StatsD packets to the sidecar on the node.
+func Metrics() (net.Conn, error) {
+	return net.DialTimeout("udp", "10.0.4.12:8125", 2*time.Second)
+}
+
+// Snapshot pulls the end-of-day file from the vendor's bare-IP server.
+func Snapshot() (*http.Response, error) {
+	return http.Get("http://52.14.20.7:8080/eod/latest.csv")
+}
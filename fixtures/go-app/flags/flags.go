@@ -0,0 +1,28 @@
+package flags
+
+import (
+	"os"
+	"time"
+
+	"github.com/Flagsmith/flagsmith-go-client/v4"
+	"github.com/Unleash/unleash-client-go/v4"
+	ld "github.com/launchdarkly/go-server-sdk/v7"
+)
+
+// NewLaunchDarkly streams flag rules from LaunchDarkly.
+func NewLaunchDarkly() (*ld.LDClient, error) {
+	return ld.MakeClient(os.Getenv("LAUNCHDARKLY_SDK_KEY"), 5*time.Second)
+}
+
+// StartUnleash polls the self-hosted Unleash instance.
+func StartUnleash() error {
+	return unleash.Initialize(
+		unleash.WithAppName("checkout"),
+		unleash.WithUrl("https://unleash.platform.internal/api/"),
+	)
+}
+
+// NewFlagsmith evaluates flags through the Flagsmith Edge API.
+func NewFlagsmith() *flagsmith.Client {
+	return flagsmith.NewClient(os.Getenv("FLAGSMITH_SERVER_SIDE_ENVIRONMENT_KEY"))
+}
@@ -0,0 +1,31 @@
+package fx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/mattevans/dinero"
+)
+
+// NewRates caches Open Exchange Rates quotes for checkout pricing.
+func NewRates() *dinero.Client {
+	return dinero.NewClient(os.Getenv("OPENEXCHANGERATES_APP_ID"), "USD", 20*time.Minute)
+}
+
+// FixerLatest fetches the rates invoices are converted at.
+func FixerLatest(ctx context.Context) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://data.fixer.io/api/latest", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = url.Values{"access_key": {os.Getenv("FIXER_ACCESS_KEY")}, "base": {"EUR"}}.Encode()
+	return http.DefaultClient.Do(req)
+}
+
+// ECBReference downloads the euro reference rates used when Fixer is down.
+func ECBReference() (*http.Response, error) {
+	return http.Get("https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml")
+}
@@ -0,0 +1,27 @@
+package geo
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"googlemaps.github.io/maps"
+)
+
+// NewGoogleMaps geocodes delivery addresses.
+func NewGoogleMaps() (*maps.Client, error) {
+	return maps.NewClient(maps.WithAPIKey(os.Getenv("GOOGLE_MAPS_API_KEY")))
+}
+
+// MapboxForward geocodes through Mapbox for the courier app.
+func MapboxForward(ctx context.Context, query string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.mapbox.com/search/geocode/v6/forward", nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Set("q", query)
+	q.Set("access_token", os.Getenv("MAPBOX_ACCESS_TOKEN"))
+	req.URL.RawQuery = q.Encode()
+	return http.DefaultClient.Do(req)
+}
@@ -0,0 +1,24 @@
+package geo
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/ipinfo/go/v2/ipinfo"
+	"github.com/oschwald/geoip2-golang"
+)
+
+// NewIPinfo enriches signups with ASN and privacy flags.
+func NewIPinfo() *ipinfo.Client {
+	return ipinfo.NewClient(nil, nil, os.Getenv("IPINFO_TOKEN"))
+}
+
+// OpenCityDB reads the GeoLite2 database baked into the image; lookups stay local.
+func OpenCityDB() (*geoip2.Reader, error) {
+	return geoip2.Open("/data/GeoLite2-City.mmdb")
+}
+
+// IPStackCheck looks up the caller's own address with ipstack.
+func IPStackCheck() (*http.Response, error) {
+	return http.Get("https://api.ipstack.com/check")
+}
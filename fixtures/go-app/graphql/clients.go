@@ -0,0 +1,29 @@
+package graphql
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	genql "github.com/Khan/genqlient/graphql"
+	"github.com/machinebox/graphql"
+	shurcool "github.com/shurcooL/graphql"
+)
+
+// Repos queries GitHub's GraphQL API through genqlient's generated operations.
+func Repos(hc *http.Client) genql.Client {
+	return genql.NewClient("https://api.github.com/graphql", hc)
+}
+
+// Products reads the storefront catalog.
+func Products(ctx context.Context, out any) error {
+	client := graphql.NewClient("https://acme.myshopify.com/api/2024-07/graphql.json")
+	req := graphql.NewRequest(`{ products(first: 10) { edges { node { title } } } }`)
+	req.Header.Set("X-Shopify-Storefront-Access-Token", os.Getenv("SHOPIFY_STOREFRONT_TOKEN"))
+	return client.Run(ctx, req, out)
+}
+
+// Content reads CMS entries from an endpoint configured per environment.
+func Content(hc *http.Client) *shurcool.Client {
+	return shurcool.NewClient(os.Getenv("CMS_GRAPHQL_URL"), hc)
+}
@@ -0,0 +1,26 @@
+package growth
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+
+	pdl "github.com/peopledatalabs/peopledatalabs-go/v5"
+)
+
+// NewEnricher fills in job titles and companies for new signups.
+func NewEnricher() *pdl.Client {
+	return pdl.New(os.Getenv("PDL_API_KEY"))
+}
+
+// FindEmail looks up a sales prospect's address by name and domain.
+func FindEmail(ctx context.Context, domain, first, last string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.hunter.io/v2/email-finder", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = url.Values{"domain": {domain}, "first_name": {first}, "last_name": {last}}.Encode()
+	req.Header.Set("X-API-KEY", os.Getenv("HUNTER_API_KEY"))
+	return http.DefaultClient.Do(req)
+}
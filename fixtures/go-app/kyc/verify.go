@@ -0,0 +1,38 @@
+package kyc
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+)
+
+// UploadOnfidoDocument sends a passport scan to Onfido for verification.
+func UploadOnfidoDocument(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.eu.onfido.com/v3.6/documents", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Token token="+os.Getenv("ONFIDO_API_TOKEN"))
+	return http.DefaultClient.Do(req)
+}
+
+// CreatePersonaInquiry starts a hosted Persona verification flow.
+func CreatePersonaInquiry(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://withpersona.com/api/v1/inquiries", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("PERSONA_API_KEY"))
+	return http.DefaultClient.Do(req)
+}
+
+// CreateJumioAccount opens a Jumio transaction for an ID and selfie check.
+func CreateJumioAccount(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://account.amer-1.jumio.ai/api/v1/accounts", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(os.Getenv("JUMIO_API_TOKEN"), os.Getenv("JUMIO_API_SECRET"))
+	return http.DefaultClient.Do(req)
+}
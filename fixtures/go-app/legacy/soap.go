@@ -0,0 +1,19 @@
+package legacy
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/hooklift/gowsdl/soap"
+	"github.com/tiaguinho/gosoap"
+)
+
+// NewFreight talks to the carrier's freight booking service.
+func NewFreight() *soap.Client {
+	return soap.NewClient("https://ws.freight-carrier.example/BookingService.svc", soap.WithBasicAuth("acme", os.Getenv("FREIGHT_PASSWORD")))
+}
+
+// NewVAT validates EU VAT numbers against VIES.
+func NewVAT(hc *http.Client) (*gosoap.Client, error) {
+	return gosoap.SoapClient("https://ec.europa.eu/taxation_customs/vies/services/checkVatService?wsdl", hc)
+}
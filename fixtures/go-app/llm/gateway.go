@@ -0,0 +1,12 @@
+package llm
+
+import (
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// NewLocalClient talks to the vLLM server running next to the worker.
+func NewLocalClient() *openai.Client {
+	cfg := openai.DefaultConfig("unused")
+	cfg.BaseURL = "http://localhost:8000/v1"
+	return openai.NewClientWithConfig(cfg)
+}
@@ -0,0 +1,38 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	cohereclient "github.com/cohere-ai/cohere-go/v2/client"
+	"github.com/gage-technologies/mistral-go"
+	"google.golang.org/genai"
+)
+
+func Clients(ctx context.Context) error {
+	claude := anthropic.NewClient(option.WithAPIKey(os.Getenv("ANTHROPIC_API_KEY")))
+	_ = claude
+
+	co := cohereclient.NewClient(cohereclient.WithToken(os.Getenv("COHERE_API_KEY")))
+	_ = co
+
+	mc := mistral.NewMistralClientDefault(os.Getenv("MISTRAL_API_KEY"))
+	_ = mc
+
+	gemini, err := genai.NewClient(ctx, &genai.ClientConfig{APIKey: os.Getenv("GEMINI_API_KEY")})
+	_ = gemini
+	return err
+}
+
+func Embed(body []byte) (*http.Response, error) {
+	return http.Post("https://api.cohere.com/v2/embed", "application/json", bytes.NewReader(body))
+}
+
+func Generate(body []byte) (*http.Response, error) {
+	url := "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent"
+	return http.Post(url, "application/json", bytes.NewReader(body))
+}
@@ -32,7 +32,7 @@ func main() {
 	_ = req
 
 	// AWS S3
-	cfg, _ := config.LoadDefaultConfig(ctx)
+	cfg, _ := config.LoadDefaultConfig(ctx, config.WithRegion("us-west-2"))
 	s3Client := s3.NewFromConfig(cfg)
 	_ = s3Client
 
@@ -0,0 +1,47 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/imgix/imgix-go/v2"
+	muxgo "github.com/muxinc/mux-go/v5"
+	transloadit "github.com/transloadit/go-sdk"
+)
+
+// NewCloudinary stores profile photos.
+func NewCloudinary() (*cloudinary.Cloudinary, error) {
+	return cloudinary.NewFromURL(os.Getenv("CLOUDINARY_URL"))
+}
+
+// AvatarURLs renders thumbnails through imgix.
+func AvatarURLs() imgix.URLBuilder {
+	return imgix.NewURLBuilder("acme.imgix.net", imgix.WithToken(os.Getenv("IMGIX_SECURE_TOKEN")))
+}
+
+// NewMux hosts uploaded course videos.
+func NewMux() *muxgo.APIClient {
+	return muxgo.NewAPIClient(muxgo.NewConfiguration(
+		muxgo.WithBasicAuth(os.Getenv("MUX_TOKEN_ID"), os.Getenv("MUX_TOKEN_SECRET")),
+	))
+}
+
+// NewTranscoder encodes attachments with Transloadit.
+func NewTranscoder() transloadit.Client {
+	options := transloadit.DefaultConfig
+	options.AuthKey = os.Getenv("TRANSLOADIT_KEY")
+	options.AuthSecret = os.Getenv("TRANSLOADIT_SECRET")
+	return transloadit.NewClient(options)
+}
+
+// UploadUnsigned posts a browser-signed upload straight to Cloudinary.
+func UploadUnsigned(ctx context.Context, form []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.cloudinary.com/v1_1/acme/image/upload", bytes.NewReader(form))
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
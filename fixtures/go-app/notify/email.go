@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"context"
+	"net/smtp"
+	"os"
+
+	"github.com/mailgun/mailgun-go/v4"
+	"github.com/mrz1836/postmark"
+	"github.com/sendgrid/sendgrid-go"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	gomail "gopkg.in/mail.v2"
+)
+
+func SendWelcome(ctx context.Context, to string) error {
+	// SendGrid Web API
+	sg := sendgrid.NewSendClient(os.Getenv("SENDGRID_API_KEY"))
+	msg := mail.NewSingleEmail(mail.NewEmail("Acme", "hello@acme.io"), "Welcome", mail.NewEmail("", to), "Hi", "")
+	if _, err := sg.Send(msg); err != nil {
+		return err
+	}
+
+	// Mailgun
+	mg := mailgun.NewMailgun("mg.acme.io", os.Getenv("MAILGUN_API_KEY"))
+	_, _, err := mg.Send(ctx, mg.NewMessage("hello@acme.io", "Welcome", "Hi", to))
+	if err != nil {
+		return err
+	}
+
+	// Postmark
+	pm := postmark.NewClient(os.Getenv("POSTMARK_SERVER_TOKEN"), "")
+	_ = pm
+	return nil
+}
+
+// Relay through SMTP instead of the providers' HTTP APIs
+func SendDigest(to string, body []byte) error {
+	auth := smtp.PlainAuth("", "apikey", os.Getenv("SENDGRID_API_KEY"), "smtp.sendgrid.net")
+	if err := smtp.SendMail("smtp.sendgrid.net:587", auth, "digest@acme.io", []string{to}, body); err != nil {
+		return err
+	}
+
+	d := gomail.NewDialer("email-smtp.eu-west-1.amazonaws.com", 587, os.Getenv("SES_SMTP_USER"), os.Getenv("SES_SMTP_PASSWORD"))
+	m := gomail.NewMessage()
+	m.SetHeader("To", to)
+	return d.DialAndSend(m)
+}
@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/twilio/twilio-go"
+	openapi "github.com/twilio/twilio-go/rest/api/v2010"
+)
+
+func SendTwilio(to, body string) error {
+	client := twilio.NewRestClient()
+	params := &openapi.CreateMessageParams{}
+	params.SetTo(to)
+	params.SetBody(body)
+	_, err := client.Api.CreateMessage(params)
+	return err
+}
+
+// SendNexmo posts to the Vonage SMS API directly, without the SDK.
+func SendNexmo(to, body string) error {
+	_, err := http.PostForm("https://rest.nexmo.com/sms/json", url.Values{
+		"api_key":    {os.Getenv("VONAGE_API_KEY")},
+		"api_secret": {os.Getenv("VONAGE_API_SECRET")},
+		"to":         {to},
+		"text":       {body},
+	})
+	return err
+}
+
+// CallStatus reads a Twilio call resource with net/http.
+func CallStatus(sid string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", "https://api.twilio.com/2010-04-01/Accounts/AC123/Calls.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
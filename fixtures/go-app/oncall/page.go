@@ -0,0 +1,36 @@
+package oncall
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/alert"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
+)
+
+// Trigger pages the payments on-call through the PagerDuty Events API.
+func Trigger(ctx context.Context, summary string) (*pagerduty.V2EventResponse, error) {
+	return pagerduty.ManageEventWithContext(ctx, pagerduty.V2Event{
+		RoutingKey: os.Getenv("PAGERDUTY_ROUTING_KEY"),
+		Action:     "trigger",
+		Payload:    &pagerduty.V2Payload{Summary: summary, Source: "payments", Severity: "critical"},
+	})
+}
+
+// NewAlerts opens alerts for the platform team, which is still on Opsgenie.
+func NewAlerts() (*alert.Client, error) {
+	return alert.NewClient(&client.Config{ApiKey: os.Getenv("OPSGENIE_API_KEY")})
+}
+
+// Declare opens an incident.io incident when a payout batch fails.
+func Declare(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.incident.io/v2/incidents", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("INCIDENT_IO_API_KEY"))
+	return http.DefaultClient.Do(req)
+}
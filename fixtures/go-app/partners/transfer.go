@@ -0,0 +1,36 @@
+package partners
+
+import (
+	"net/smtp"
+	"os"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// SendRemittance emails the daily remittance advice through the bank's relay.
+func SendRemittance(body []byte) error {
+	auth := smtp.PlainAuth("", "ops", os.Getenv("BANK_SMTP_PASSWORD"), "mail.acmebank-partner.com")
+	return smtp.SendMail("mail.acmebank-partner.com:587", auth, "ops@acme.io", []string{"remit@acmebank-partner.com"}, body)
+}
+
+// UploadCatalog drops the product feed on the retailer's FTP server.
+func UploadCatalog() (*ftp.ServerConn, error) {
+	return ftp.Dial("ftp.retailer-feeds.com:21", ftp.DialWithTimeout(10*time.Second))
+}
+
+// FetchStatements downloads settlement files from the processor's SFTP drop.
+func FetchStatements(cfg *ssh.ClientConfig) (*sftp.Client, error) {
+	conn, err := ssh.Dial("tcp", "sftp.processor-example.com:22", cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sftp.NewClient(conn)
+}
+
+// Archive copies files to the on-prem archive, which is not a third party.
+func Archive() (*ftp.ServerConn, error) {
+	return ftp.Dial("10.20.0.15:21")
+}
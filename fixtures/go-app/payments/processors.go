@@ -0,0 +1,44 @@
+package payments
+
+import (
+	"os"
+
+	"github.com/adyen/adyen-go-api-library/v9/src/adyen"
+	"github.com/adyen/adyen-go-api-library/v9/src/common"
+	"github.com/braintree-go/braintree-go"
+	"github.com/plutov/paypal/v4"
+	square "github.com/square/square-go-sdk"
+	"github.com/square/square-go-sdk/client"
+	"github.com/square/square-go-sdk/option"
+)
+
+// NewPayPal captures orders against PayPal's live REST API.
+func NewPayPal() (*paypal.Client, error) {
+	return paypal.NewClient(os.Getenv("PAYPAL_CLIENT_ID"), os.Getenv("PAYPAL_CLIENT_SECRET"), paypal.APIBaseLive)
+}
+
+// NewBraintree charges vaulted cards in the Braintree sandbox.
+func NewBraintree() *braintree.Braintree {
+	return braintree.New(
+		braintree.Sandbox,
+		os.Getenv("BRAINTREE_MERCHANT_ID"),
+		os.Getenv("BRAINTREE_PUBLIC_KEY"),
+		os.Getenv("BRAINTREE_PRIVATE_KEY"),
+	)
+}
+
+// NewAdyen authorises payments in Adyen's test environment.
+func NewAdyen() *adyen.APIClient {
+	return adyen.NewClient(&common.Config{
+		ApiKey:      os.Getenv("ADYEN_API_KEY"),
+		Environment: common.TestEnv,
+	})
+}
+
+// NewSquare takes in-person payments through Square.
+func NewSquare() *client.Client {
+	return client.NewClient(
+		option.WithToken(os.Getenv("SQUARE_ACCESS_TOKEN")),
+		option.WithBaseURL(square.Environments.Production),
+	)
+}
@@ -0,0 +1,19 @@
+package platform
+
+import (
+	consulapi "github.com/hashicorp/consul/api"
+	tfe "github.com/hashicorp/go-tfe"
+)
+
+// NewDiscovery registers services with the local Consul agent.
+func NewDiscovery() (*consulapi.Client, error) {
+	return consulapi.NewClient(&consulapi.Config{
+		Address: "consul.service.internal:8500",
+		Scheme:  "https",
+	})
+}
+
+// NewWorkspaces manages HCP Terraform workspaces.
+func NewWorkspaces(token string) (*tfe.Client, error) {
+	return tfe.NewClient(&tfe.Config{Token: token})
+}
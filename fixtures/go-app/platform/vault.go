@@ -0,0 +1,26 @@
+package platform
+
+import (
+	"context"
+	"os"
+
+	"github.com/hashicorp/vault-client-go"
+	"github.com/hashicorp/vault/api"
+)
+
+// NewSecretsClient reads application secrets from the HCP Vault cluster.
+func NewSecretsClient() (*api.Client, error) {
+	config := api.DefaultConfig()
+	config.Address = "https://vault-cluster-public-vault-abc123.def456.z1.hashicorp.cloud:8200"
+	return api.NewClient(config)
+}
+
+// NewPKIClient issues certificates from the cluster named in PKI_VAULT_ADDR.
+func NewPKIClient(ctx context.Context) (*vault.Client, error) {
+	return vault.New(vault.WithAddress(os.Getenv("PKI_VAULT_ADDR")))
+}
+
+// NewDefaultClient uses whatever VAULT_ADDR points at.
+func NewDefaultClient() (*api.Client, error) {
+	return api.NewClient(nil)
+}
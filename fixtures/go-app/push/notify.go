@@ -0,0 +1,38 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+
+	onesignal "github.com/OneSignal/onesignal-go-api"
+	expo "github.com/oliveroneill/exponent-server-sdk-golang/sdk"
+	"github.com/sideshow/apns2"
+	"github.com/sideshow/apns2/token"
+)
+
+// NewAPNs sends iOS notifications through Apple's sandbox gateway.
+func NewAPNs(authKey *token.Token) *apns2.Client {
+	return apns2.NewTokenClient(authKey).Development()
+}
+
+// NewOneSignal broadcasts marketing campaigns.
+func NewOneSignal() *onesignal.APIClient {
+	return onesignal.NewAPIClient(onesignal.NewConfiguration())
+}
+
+// NewExpo relays notifications for the React Native app.
+func NewExpo() *expo.PushClient {
+	return expo.NewPushClient(nil)
+}
+
+// SendFCM posts an Android notification with the HTTP v1 API.
+func SendFCM(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://fcm.googleapis.com/v1/projects/acme-mobile/messages:send", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("FCM_ACCESS_TOKEN"))
+	return http.DefaultClient.Do(req)
+}
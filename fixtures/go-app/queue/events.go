@@ -0,0 +1,23 @@
+package queue
+
+import (
+	"github.com/IBM/sarama"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// NewOrderProducer publishes order events to the MSK cluster.
+func NewOrderProducer() (sarama.SyncProducer, error) {
+	brokers := []string{"b-1.orders.abc123.c2.kafka.us-east-1.amazonaws.com:9096", "b-2.orders.abc123.c2.kafka.us-east-1.amazonaws.com:9096"}
+	cfg := sarama.NewConfig()
+	cfg.Net.TLS.Enable = true
+	return sarama.NewSyncProducer(brokers, cfg)
+}
+
+// NewAuditConsumer reads audit events from Aiven.
+func NewAuditConsumer() (*kafka.Consumer, error) {
+	return kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": "kafka-audit-acme.aivencloud.com:12692",
+		"group.id":          "audit",
+		"security.protocol": "SSL",
+	})
+}
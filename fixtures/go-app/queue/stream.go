@@ -0,0 +1,23 @@
+package queue
+
+import (
+	"os"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ClickWriter streams click events to Confluent Cloud.
+var ClickWriter = &kafka.Writer{
+	Addr:  kafka.TCP("pkc-abc12.us-east-1.aws.confluent.cloud:9092"),
+	Topic: "clicks",
+}
+
+// NewJobReader reads jobs from the cluster named in KAFKA_BROKERS.
+func NewJobReader() *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers: strings.Split(os.Getenv("KAFKA_BROKERS"), ","),
+		Topic:   "jobs",
+		GroupID: "worker",
+	})
+}
@@ -0,0 +1,30 @@
+package realtime
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	nhooyr "nhooyr.io/websocket"
+)
+
+// Trades subscribes to the exchange's public trade stream.
+func Trades() (*websocket.Conn, *http.Response, error) {
+	return websocket.DefaultDialer.Dial("wss://stream.binance.com:9443/ws/btcusdt@trade", nil)
+}
+
+// Presence joins the realtime channel the dashboards render from.
+func Presence(ctx context.Context, header http.Header) (*websocket.Conn, *http.Response, error) {
+	dialer := websocket.Dialer{EnableCompression: true}
+	return dialer.DialContext(ctx, "wss://realtime.ably.io/?format=json", header)
+}
+
+// Quotes streams market data over nhooyr.io/websocket.
+func Quotes(ctx context.Context) (*nhooyr.Conn, *http.Response, error) {
+	return nhooyr.Dial(ctx, "wss://ws.finnhub.io", nil)
+}
+
+// Local connects to the sidecar's debug socket.
+func Local(ctx context.Context) (*nhooyr.Conn, *http.Response, error) {
+	return nhooyr.Dial(ctx, "ws://127.0.0.1:9229/debug", nil)
+}
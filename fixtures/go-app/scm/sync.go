@@ -0,0 +1,38 @@
+package scm
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/ktrysmt/go-bitbucket"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func Mirror(ctx context.Context) error {
+	gh := github.NewClient(nil).WithAuthToken(os.Getenv("GITHUB_TOKEN"))
+	if _, _, err := gh.Repositories.Get(ctx, "acme", "checkout"); err != nil {
+		return err
+	}
+
+	gl, err := gitlab.NewClient(os.Getenv("GITLAB_TOKEN"))
+	if err != nil {
+		return err
+	}
+	_ = gl
+
+	bb := bitbucket.NewOAuthbearerToken(os.Getenv("BITBUCKET_ACCESS_TOKEN"))
+	_ = bb
+
+	// GraphQL without a client library
+	query := bytes.NewBufferString(`{"query":"{ viewer { login } }"}`)
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.github.com/graphql", query)
+	if err != nil {
+		return err
+	}
+	_, err = http.DefaultClient.Do(req)
+	_, _ = http.Get("https://gitlab.com/api/v4/projects/42/merge_requests")
+	return err
+}
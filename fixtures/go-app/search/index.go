@@ -0,0 +1,42 @@
+package search
+
+import (
+	"os"
+
+	"github.com/algolia/algoliasearch-client-go/v4/algolia/search"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/meilisearch/meilisearch-go"
+	"github.com/opensearch-project/opensearch-go/v4"
+	"github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+	"github.com/typesense/typesense-go/v2/typesense"
+)
+
+// NewProducts connects to the Elastic Cloud deployment.
+func NewProducts() (*elasticsearch.TypedClient, error) {
+	return elasticsearch.NewTypedClient(elasticsearch.Config{
+		CloudID: "acme-search:dXMtY2VudHJhbDEuZ2NwLmNsb3VkLmVzLmlvOjQ0MyRhMWIyYzNkNGU1ZjYkMGY5ZThkN2M2YjVh",
+		APIKey:  os.Getenv("ELASTIC_API_KEY"),
+	})
+}
+
+// NewLogs connects to the self-hosted logging cluster.
+func NewLogs() (*elasticsearch.Client, error) {
+	return elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{"http://logs-es.internal:9200"},
+	})
+}
+
+// NewAudit connects to the AWS OpenSearch domain.
+func NewAudit() (*opensearchapi.Client, error) {
+	return opensearchapi.NewClient(opensearchapi.Config{
+		Client: opensearch.Config{
+			Addresses: []string{"https://search-audit-abc123.us-east-1.es.amazonaws.com"},
+		},
+	})
+}
+
+func NewHosted() {
+	_, _ = search.NewClient(os.Getenv("ALGOLIA_APP_ID"), os.Getenv("ALGOLIA_API_KEY"))
+	_ = meilisearch.New("https://ms-abc123.lon.meilisearch.io", meilisearch.WithAPIKey(os.Getenv("MEILI_KEY")))
+	_ = typesense.NewClient(typesense.WithServer("https://xyz123-1.a1.typesense.net"))
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/stripe/stripe-go/v78/webhook"
+)
+
+// Routes registers the callbacks vendors deliver events to.
+func Routes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /webhooks/stripe", stripeEvents)
+	mux.HandleFunc("POST /webhooks/shopify/orders", shopifyOrders)
+	mux.HandleFunc("POST /hooks/github", githubPush)
+	mux.HandleFunc("GET /healthz", health)
+}
+
+func stripeEvents(w http.ResponseWriter, r *http.Request) {
+	payload, _ := io.ReadAll(r.Body)
+	event, err := webhook.ConstructEvent(payload, r.Header.Get("Stripe-Signature"), os.Getenv("STRIPE_WEBHOOK_SECRET"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	_ = event
+}
+
+func shopifyOrders(w http.ResponseWriter, r *http.Request) {
+	if !validHMAC(r.Header.Get("X-Shopify-Hmac-Sha256")) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+}
+
+func githubPush(w http.ResponseWriter, r *http.Request) {}
+
+func health(w http.ResponseWriter, r *http.Request) {}
+
+func validHMAC(string) bool { return true }
@@ -0,0 +1,39 @@
+package shipping
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/EasyPost/easypost-go/v4"
+	"github.com/coldbrewcloud/go-shippo/client"
+)
+
+// NewEasyPost buys domestic labels through EasyPost.
+func NewEasyPost() *easypost.Client {
+	return easypost.New(os.Getenv("EASYPOST_API_KEY"))
+}
+
+// NewShippo rates international parcels through Shippo.
+func NewShippo() *client.Client {
+	return client.NewClient(os.Getenv("SHIPPO_API_TOKEN"))
+}
+
+// FedExRates quotes freight directly against the FedEx REST API.
+func FedExRates(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://apis.fedex.com/rate/v1/rates/quotes", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
+
+// UPSShip creates a UPS shipment and label.
+func UPSShip(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://onlinetools.ups.com/api/shipments/v2409/ship", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
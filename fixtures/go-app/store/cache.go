@@ -0,0 +1,33 @@
+package store
+
+import (
+	"os"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRateLimiter connects to the rate-limit counters on Upstash.
+func NewRateLimiter() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     "eu1-pleasant-moth-31337.upstash.io:6379",
+		Password: os.Getenv("UPSTASH_REDIS_PASSWORD"),
+	})
+}
+
+// NewSessionStore connects to the ElastiCache cluster holding sessions.
+func NewSessionStore() *redis.ClusterClient {
+	return redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs: []string{"clustercfg.sessions.abc123.use1.cache.amazonaws.com:6379"},
+	})
+}
+
+// NewFeedCache connects to the feed cache on Redis Cloud.
+func NewFeedCache() *redis.Client {
+	opts := &redis.Options{Addr: "redis-12345.c1.us-east-1-2.ec2.redns.redis-cloud.com:12345"}
+	return redis.NewClient(opts)
+}
+
+// NewLocalCache connects to the cache sidecar.
+func NewLocalCache() *redis.Client {
+	return redis.NewClient(&redis.Options{Addr: os.Getenv("CACHE_ADDR")})
+}
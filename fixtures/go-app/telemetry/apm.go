@@ -0,0 +1,50 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/honeycombio/libhoney-go"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+func Start(ctx context.Context) error {
+	// Datadog APM and DogStatsD, through the local agent
+	tracer.Start(tracer.WithService("checkout"), tracer.WithEnv(os.Getenv("DD_ENV")))
+	defer tracer.Stop()
+	metrics, err := statsd.New("127.0.0.1:8125")
+	if err != nil {
+		return err
+	}
+	_ = metrics
+
+	// New Relic
+	app, err := newrelic.NewApplication(
+		newrelic.ConfigAppName("checkout"),
+		newrelic.ConfigLicense(os.Getenv("NEW_RELIC_LICENSE_KEY")),
+	)
+	if err != nil {
+		return err
+	}
+	_ = app
+
+	// Honeycomb events, EU region
+	if err := libhoney.Init(libhoney.Config{
+		APIKey:  os.Getenv("HONEYCOMB_API_KEY"),
+		APIHost: "https://api.eu1.honeycomb.io",
+		Dataset: "checkout",
+	}); err != nil {
+		return err
+	}
+
+	// OpenTelemetry traces straight to Honeycomb
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint("api.honeycomb.io:443"))
+	if err != nil {
+		return err
+	}
+	_ = exporter
+	return nil
+}
@@ -0,0 +1,34 @@
+package tracking
+
+import (
+	"os"
+
+	"github.com/amplitude/analytics-go/amplitude"
+	"github.com/mixpanel/mixpanel-go"
+	"github.com/segmentio/analytics-go/v3"
+)
+
+// NewSegment batches track and identify calls to Segment's EU region.
+func NewSegment() (analytics.Client, error) {
+	return analytics.NewWithConfig(os.Getenv("SEGMENT_WRITE_KEY"), analytics.Config{
+		Endpoint: "https://events.eu1.segmentapis.com",
+	})
+}
+
+// IdentifyCustomer records the customer's profile traits in Segment.
+func IdentifyCustomer(client analytics.Client, id, email string) error {
+	return client.Enqueue(analytics.Identify{
+		UserId: id,
+		Traits: analytics.NewTraits().SetEmail(email),
+	})
+}
+
+// NewMixpanel sends product events to Mixpanel.
+func NewMixpanel() *mixpanel.ApiClient {
+	return mixpanel.NewApiClient(os.Getenv("MIXPANEL_TOKEN"))
+}
+
+// NewAmplitude sends events to Amplitude.
+func NewAmplitude() amplitude.Client {
+	return amplitude.NewClient(amplitude.NewConfig(os.Getenv("AMPLITUDE_API_KEY")))
+}
@@ -0,0 +1,39 @@
+package workflow
+
+import (
+	"context"
+	"os"
+
+	executions "cloud.google.com/go/workflows/executions/apiv1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	"go.temporal.io/sdk/client"
+)
+
+// DialOrders connects to the orders namespace on Temporal Cloud.
+func DialOrders() (client.Client, error) {
+	return client.Dial(client.Options{
+		HostPort:  "orders-prod.a1b2c.tmprl.cloud:7233",
+		Namespace: "orders-prod.a1b2c",
+	})
+}
+
+// DialBatch connects to the self-hosted cluster named in TEMPORAL_ADDRESS.
+func DialBatch() (client.Client, error) {
+	return client.NewLazyClient(client.Options{HostPort: os.Getenv("TEMPORAL_ADDRESS")})
+}
+
+// DialLocal connects to the development server.
+func DialLocal() (client.Client, error) {
+	return client.Dial(client.Options{})
+}
+
+// StartFulfillment starts the fulfillment state machine.
+func StartFulfillment(cfg aws.Config) *sfn.Client {
+	return sfn.NewFromConfig(cfg)
+}
+
+// NewExecutions starts Google Workflows runs for invoicing.
+func NewExecutions(ctx context.Context) (*executions.Client, error) {
+	return executions.NewClient(ctx)
+}
@@ -0,0 +1,31 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+// NewCalendar books interview slots on the recruiter's calendar.
+func NewCalendar(ctx context.Context, ts oauth2.TokenSource) (*calendar.Service, error) {
+	return calendar.NewService(ctx, option.WithTokenSource(ts))
+}
+
+// NewDrive exports signed offer letters to the shared drive.
+func NewDrive(ctx context.Context, ts oauth2.TokenSource) (*drive.Service, error) {
+	return drive.NewService(ctx, option.WithTokenSource(ts))
+}
+
+// ScheduleInterview creates the video call for an interview.
+func ScheduleInterview(ctx context.Context, client *http.Client, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.zoom.us/v2/users/me/meetings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
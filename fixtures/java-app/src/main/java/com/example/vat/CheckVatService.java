@@ -0,0 +1,24 @@
+package com.example.vat;
+
+import java.net.MalformedURLException;
+import java.net.URL;
+import javax.xml.namespace.QName;
+import javax.xml.ws.Service;
+import javax.xml.ws.WebServiceClient;
+
+// Generated by wsimport from the VIES WSDL
+@WebServiceClient(name = "checkVatService",
+                  targetNamespace = "urn:ec.europa.eu:taxud:vies:services:checkVat",
+                  wsdlLocation = "https://ec.europa.eu/taxation_customs/vies/services/checkVatService?wsdl")
+public class CheckVatService extends Service {
+
+    private static final QName SERVICE = new QName("urn:ec.europa.eu:taxud:vies:services:checkVat", "checkVatService");
+
+    public CheckVatService(URL wsdlLocation) {
+        super(wsdlLocation, SERVICE);
+    }
+
+    public static CheckVatService freight() throws MalformedURLException {
+        return new CheckVatService(new URL("https://ws.freight-carrier.example/BookingService.svc?wsdl"));
+    }
+}
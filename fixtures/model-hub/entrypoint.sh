@@ -0,0 +1,9 @@
+#!/bin/sh
+set -e
+
+# Pull the adapter at start-up; the base model is baked into the image
+hf download acme/support-lora --revision "${ADAPTER_REVISION:-main}" --local-dir /models/adapter
+# hf download acme/old-adapter
+wget -q https://huggingface.co/datasets/acme/eval-set/resolve/main/eval.jsonl -O /data/eval.jsonl
+
+exec python -m ai.serve
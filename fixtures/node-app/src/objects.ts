@@ -0,0 +1,11 @@
+// S3-compatible object storage through the AWS SDK — fixture for Thirdwatch scanner tests
+import { S3Client } from "@aws-sdk/client-s3";
+
+// Cloudflare R2
+export const r2 = new S3Client({
+  region: "auto",
+  endpoint: "https://8d5c1a2f9e0b4c7d.r2.cloudflarestorage.com",
+});
+
+// DigitalOcean Spaces, endpoint from the environment
+export const spaces = new S3Client({ endpoint: process.env.SPACES_ENDPOINT, forcePathStyle: false });
@@ -0,0 +1,13 @@
+"""OpenAI-compatible gateways — fixture for Thirdwatch scanner tests."""
+
+import os
+
+from openai import AzureOpenAI, OpenAI
+
+groq = OpenAI(
+    base_url="https://api.groq.com/openai/v1",
+    api_key=os.environ["GROQ_API_KEY"],
+)
+gateway = OpenAI(base_url=os.environ["LLM_GATEWAY_URL"])
+azure = AzureOpenAI(azure_endpoint="https://acme.openai.azure.com", api_version="2024-06-01")
+default = OpenAI()
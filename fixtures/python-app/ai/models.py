@@ -0,0 +1,30 @@
+"""Hugging Face and Replicate inference — fixture for Thirdwatch scanner tests."""
+
+import os
+
+import replicate
+from huggingface_hub import InferenceClient, hf_hub_download
+from sentence_transformers import SentenceTransformer
+from transformers import AutoModelForSequenceClassification, AutoTokenizer, pipeline
+
+MODEL_ID = "distilbert/distilbert-base-uncased-finetuned-sst-2-english"
+
+tokenizer = AutoTokenizer.from_pretrained("distilbert/distilbert-base-uncased-finetuned-sst-2-english")
+model = AutoModelForSequenceClassification.from_pretrained("./models/sst2")
+ner = pipeline("ner", model="dslim/bert-base-NER")
+embedder = SentenceTransformer("sentence-transformers/all-MiniLM-L6-v2")
+weights = hf_hub_download(repo_id="stabilityai/sdxl-turbo", filename="sd_xl_turbo_1.0.safetensors")
+
+hf = InferenceClient(token=os.environ["HF_TOKEN"])
+
+
+def summarize(text: str) -> str:
+    return hf.summarization(text, model="facebook/bart-large-cnn").summary_text
+
+
+def upscale(image_url: str) -> str:
+    output = replicate.run(
+        "nightmareai/real-esrgan:42fed1c4974146d4d2414e2be2c5277c7fcf05fcc3a73abf41610695738c1d7b",
+        input={"image": image_url},
+    )
+    return str(output)
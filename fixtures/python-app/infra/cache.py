@@ -0,0 +1,6 @@
+import os
+
+import redis
+
+rate_limits = redis.from_url(os.environ["UPSTASH_REDIS_URL"])
+sessions = redis.Redis(host="master.sessions.abc123.use1.cache.amazonaws.com", port=6379, ssl=True)
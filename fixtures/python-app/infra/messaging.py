@@ -0,0 +1,14 @@
+import os
+
+import nats
+import pika
+
+
+def publish_order(body):
+    conn = pika.BlockingConnection(pika.URLParameters(os.environ["CLOUDAMQP_URL"]))
+    conn.channel().basic_publish(exchange="", routing_key="orders", body=body)
+
+
+async def subscribe_events():
+    nc = await nats.connect("tls://connect.ngs.global", user_credentials="edge.creds")
+    await nc.subscribe("events.>")
@@ -0,0 +1,15 @@
+"""S3-compatible object storage — fixture for Thirdwatch scanner tests."""
+
+import os
+import boto3
+
+# Cloudflare R2 through the S3 API
+r2 = boto3.client("s3",
+                  endpoint_url="https://8d5c1a2f9e0b4c7d.r2.cloudflarestorage.com",
+                  region_name="auto")
+
+# Backups go to whichever S3-compatible store the deployment configures
+backups = boto3.client("s3", endpoint_url=os.environ["BACKUP_S3_ENDPOINT"])
+
+# Plain AWS S3
+exports = boto3.client("s3", region_name="eu-west-1")
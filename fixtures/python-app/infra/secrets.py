@@ -0,0 +1,7 @@
+import os
+
+import consul
+import hvac
+
+vault = hvac.Client(url="https://vault.platform.internal:8200", token=os.environ["VAULT_TOKEN"])
+discovery = consul.Consul(host="consul.service.internal", port=8500)
@@ -0,0 +1,14 @@
+"""EU VAT number checks for B2B invoices."""
+
+import zeep
+from zeep.transports import Transport
+
+vies = zeep.Client("https://ec.europa.eu/taxation_customs/vies/services/checkVatService?wsdl")
+freight = zeep.Client(
+    wsdl="https://ws.freight-carrier.example/BookingService.svc?wsdl",
+    transport=Transport(timeout=10),
+)
+
+
+def check_vat(country_code: str, number: str) -> bool:
+    return vies.service.checkVat(countryCode=country_code, vatNumber=number).valid
@@ -0,0 +1,21 @@
+import os
+
+import boto3
+import redshift_connector
+import snowflake.connector
+from google.cloud import bigquery
+
+
+def snowflake_conn():
+    return snowflake.connector.connect(account="acme-analytics", user="etl", password=os.environ["SNOWFLAKE_PASSWORD"])
+
+
+def redshift_conn():
+    return redshift_connector.connect(host="analytics.abc123xyz.us-east-1.redshift.amazonaws.com", database="dev")
+
+
+def run_report(sql):
+    client = bigquery.Client(project="acme-analytics")
+    client.query(sql).result()
+    data = boto3.client("redshift-data")
+    data.execute_statement(WorkgroupName="analytics", Database="dev", Sql=sql)
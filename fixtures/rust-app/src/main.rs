@@ -19,7 +19,7 @@ async fn main() -> Result<(), Box<dyn std::error::Error>> {
         .send().await?;
 
     // AWS SDK
-    let config = aws_config::load_defaults(BehaviorVersion::latest()).await;
+    let config = aws_config::defaults(BehaviorVersion::latest()).region(Region::new("eu-west-1")).load().await;
     let s3 = aws_sdk_s3::Client::new(&config);
 
     // Stripe SDK
@@ -0,0 +1,18 @@
+terraform {
+  # backend "remote" { organization = "old-org" }
+  backend "remote" {
+    hostname     = "tfe.acme-corp.com"
+    organization = "platform"
+
+    workspaces {
+      name = "network"
+    }
+  }
+}
+
+terraform {
+  backend "s3" {
+    bucket = "acme-tf-state"
+    key    = "dns/terraform.tfstate"
+  }
+}
@@ -0,0 +1,15 @@
+terraform {
+  required_version = ">= 1.6"
+
+  cloud {
+    organization = "acme"
+
+    workspaces {
+      tags = ["app:payments"]
+    }
+  }
+}
+
+resource "aws_s3_bucket" "receipts" {
+  bucket = "acme-receipts"
+}
@@ -0,0 +1,25 @@
+-- 006_runtime_usage.sql — Live third-party call stats from instrumentation libraries
+
+CREATE TABLE IF NOT EXISTS runtime_usage (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  org_id UUID REFERENCES organizations(id),
+  service TEXT NOT NULL,
+  environment TEXT,
+  kind TEXT NOT NULL DEFAULT 'http',
+  host TEXT NOT NULL,
+  method TEXT,
+  vendor TEXT,
+  window_start TIMESTAMPTZ NOT NULL,
+  window_end TIMESTAMPTZ NOT NULL,
+  call_count INTEGER NOT NULL,
+  error_count INTEGER NOT NULL DEFAULT 0,
+  latency_ms_total DOUBLE PRECISION NOT NULL DEFAULT 0,
+  latency_ms_max DOUBLE PRECISION NOT NULL DEFAULT 0,
+  received_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_runtime_usage_service
+  ON runtime_usage (org_id, service, window_end DESC);
+
+CREATE INDEX IF NOT EXISTS idx_runtime_usage_vendor
+  ON runtime_usage (org_id, vendor, window_end DESC);
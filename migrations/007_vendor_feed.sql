@@ -0,0 +1,40 @@
+-- 007_vendor_feed.sql — Append-only feed of vendors appearing in and leaving repositories
+
+CREATE TABLE IF NOT EXISTS vendor_events (
+  -- Monotonic cursor for GET /api/v1/vendor-feed?after=
+  seq BIGSERIAL PRIMARY KEY,
+  org_id UUID REFERENCES organizations(id),
+  repository TEXT NOT NULL,
+  vendor TEXT NOT NULL,
+  change TEXT NOT NULL CHECK (change IN ('added', 'removed')),
+  commit_sha TEXT,
+  -- No foreign key: pruning old uploads must not rewrite the feed
+  tdm_upload_id UUID,
+  occurred_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_events_org
+  ON vendor_events (org_id, seq);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_events_vendor
+  ON vendor_events (org_id, vendor, seq);
+
+-- Events are history: rows may be deleted with their organization, never rewritten
+CREATE OR REPLACE FUNCTION vendor_events_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'vendor_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS vendor_events_no_update ON vendor_events;
+CREATE TRIGGER vendor_events_no_update
+  BEFORE UPDATE ON vendor_events
+  FOR EACH ROW EXECUTE FUNCTION vendor_events_append_only();
+
+CREATE TABLE IF NOT EXISTS vendor_feed_subscriptions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  org_id UUID REFERENCES organizations(id),
+  url TEXT NOT NULL,
+  secret TEXT,
+  created_at TIMESTAMPTZ DEFAULT now()
+);
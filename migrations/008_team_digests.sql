@@ -0,0 +1,30 @@
+-- 008_team_digests.sql — Repository ownership by team, and scheduled third-party digests
+
+CREATE TABLE IF NOT EXISTS teams (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  org_id UUID REFERENCES organizations(id),
+  name TEXT NOT NULL,
+  -- Repository patterns the team owns; "*" is a wildcard
+  repositories TEXT[] NOT NULL DEFAULT '{}',
+  -- Vendors the team may not use, reported as violations in its digest
+  denied_vendors TEXT[] NOT NULL DEFAULT '{}',
+  digest_schedule TEXT NOT NULL DEFAULT 'weekly' CHECK (digest_schedule IN ('daily', 'weekly', 'off')),
+  digest_emails TEXT[] NOT NULL DEFAULT '{}',
+  slack_webhook_url TEXT,
+  last_digest_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT now(),
+  UNIQUE (org_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS team_digests (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  team_id UUID REFERENCES teams(id) ON DELETE CASCADE,
+  period_start TIMESTAMPTZ NOT NULL,
+  period_end TIMESTAMPTZ NOT NULL,
+  digest JSONB NOT NULL,
+  delivered_to TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_digests_team
+  ON team_digests (team_id, period_end DESC);
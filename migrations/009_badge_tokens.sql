@@ -0,0 +1,15 @@
+-- 009_badge_tokens.sql — Read-only tokens for repository badges and summaries
+
+-- Kept apart from api_keys so a token pasted into a README can never
+-- authenticate anything but GET /api/v1/badges/*
+CREATE TABLE IF NOT EXISTS badge_tokens (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  org_id UUID REFERENCES organizations(id),
+  token_hash TEXT NOT NULL UNIQUE,
+  token_prefix TEXT NOT NULL,
+  name TEXT,
+  -- Repository patterns the token may read; "*" is a wildcard
+  repositories TEXT[] NOT NULL DEFAULT '{*}',
+  last_used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT now()
+);
@@ -0,0 +1,55 @@
+-- 010_rbac_oidc.sql — OIDC sign-in, viewer/approver/admin roles, vendor approvals, and server-side suppressions
+
+-- Users may sign in with GitHub or an OIDC provider
+ALTER TABLE users ALTER COLUMN github_login DROP NOT NULL;
+ALTER TABLE users ALTER COLUMN github_id DROP NOT NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS oidc_issuer TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS oidc_subject TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS display_name TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_oidc ON users (oidc_issuer, oidc_subject) WHERE oidc_subject IS NOT NULL;
+
+-- "member" predates roles; members keep read access only
+UPDATE users SET role = 'viewer' WHERE role NOT IN ('viewer', 'approver', 'admin');
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'viewer';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('viewer', 'approver', 'admin'));
+
+-- A key acts as the user who created it, with at most that user's role.
+-- Keys created before roles existed keep the full access they had.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'admin';
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS api_keys_role_check;
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_role_check CHECK (role IN ('viewer', 'approver', 'admin'));
+
+CREATE TABLE IF NOT EXISTS vendor_approvals (
+  org_id UUID REFERENCES organizations(id),
+  vendor TEXT NOT NULL,
+  status TEXT NOT NULL CHECK (status IN ('approved', 'denied')),
+  note TEXT,
+  -- Who decided: the user, and a name that survives the user's deletion
+  decided_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  decided_by_name TEXT NOT NULL,
+  decided_at TIMESTAMPTZ DEFAULT now(),
+  PRIMARY KEY (org_id, vendor)
+);
+
+CREATE TABLE IF NOT EXISTS suppressions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  org_id UUID REFERENCES organizations(id),
+  -- Repository pattern; "*" is a wildcard
+  repository TEXT NOT NULL DEFAULT '*',
+  -- Matchers as in .thirdwatch.yml suppression rules; all given must match
+  dependency TEXT,
+  change_category TEXT,
+  min_priority TEXT CHECK (min_priority IN ('P0', 'P1', 'P2', 'P3', 'P4')),
+  reason TEXT NOT NULL,
+  expires_at TIMESTAMPTZ,
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_by_name TEXT NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_suppressions_org ON suppressions (org_id);
+
+ALTER TABLE change_events ADD COLUMN IF NOT EXISTS suppressed_by UUID REFERENCES suppressions(id) ON DELETE SET NULL;
@@ -0,0 +1,46 @@
+-- 011_audit_log.sql — Immutable record of approvals, suppressions, and policy changes
+
+CREATE TABLE IF NOT EXISTS audit_log (
+  -- Monotonic cursor for GET /api/v1/audit-log?after=
+  seq BIGSERIAL PRIMARY KEY,
+  org_id UUID REFERENCES organizations(id),
+  -- No foreign key: the entry outlives the user, so the name is kept too
+  actor_id UUID,
+  actor_name TEXT NOT NULL,
+  actor_role TEXT NOT NULL,
+  -- e.g. vendor.approved, suppression.created, team.updated
+  action TEXT NOT NULL,
+  -- What was acted on: a vendor slug, or the id of a suppression, team, user, or key
+  target TEXT NOT NULL,
+  details JSONB NOT NULL DEFAULT '{}',
+  occurred_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_org
+  ON audit_log (org_id, seq);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_target
+  ON audit_log (org_id, target, seq);
+
+-- Entries are never rewritten or removed, except with their organization:
+-- db.deleteOrg sets thirdwatch.deleting_org for the length of its transaction
+CREATE OR REPLACE FUNCTION audit_log_immutable() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    IF OLD.org_id::text = current_setting('thirdwatch.deleting_org', true) THEN
+      RETURN OLD;
+    END IF;
+  END IF;
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_no_update ON audit_log;
+CREATE TRIGGER audit_log_no_update
+  BEFORE UPDATE OR DELETE ON audit_log
+  FOR EACH ROW EXECUTE FUNCTION audit_log_immutable();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate
+  BEFORE TRUNCATE ON audit_log
+  FOR EACH STATEMENT EXECUTE FUNCTION audit_log_immutable();
@@ -0,0 +1,16 @@
+-- 012_inventory_publishers.sql — Confluence pages and Notion databases kept in sync with the vendor inventory
+
+CREATE TABLE IF NOT EXISTS inventory_publishers (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  org_id UUID REFERENCES organizations(id),
+  type TEXT NOT NULL CHECK (type IN ('confluence', 'notion')),
+  name TEXT,
+  -- Target and credentials; never returned by the API
+  config JSONB NOT NULL,
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  last_published_at TIMESTAMPTZ,
+  last_error TEXT,
+  created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_publishers_org ON inventory_publishers (org_id);
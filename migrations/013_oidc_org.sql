@@ -0,0 +1,15 @@
+-- 013_oidc_org.sql — One organization per OIDC_ORG name, however many users sign in at once
+
+-- The name an organization is known by to OIDC sign-in; unique, so
+-- concurrent first sign-ins converge on one organization
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS oidc_name TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizations_oidc_name ON organizations (oidc_name) WHERE oidc_name IS NOT NULL;
+
+-- Sign-in used the oldest organization with the name; it keeps the users
+UPDATE organizations SET oidc_name = name
+WHERE id IN (
+  SELECT DISTINCT ON (name) id FROM organizations
+  WHERE github_org IS NULL
+  ORDER BY name, created_at
+)
+AND NOT EXISTS (SELECT 1 FROM organizations WHERE oidc_name IS NOT NULL);
@@ -131,4 +131,24 @@ describe("shouldSuppress", () => {
     const p4Breaking = makeAssessment({ priority: "P4", changeCategory: "breaking" });
     expect(shouldSuppress(p4Breaking, rules).suppressed).toBe(false);
   });
+
+  it("suppresses by fingerprint only when every affected location is listed", () => {
+    const rules: SuppressionRule[] = [{ fingerprints: ["3f2a9c1d0b7e4a65"], reason: "Accepted" }];
+    const location = (fingerprint?: string) => ({
+      file: "src/billing/charge.py",
+      line: 42,
+      context: "",
+      usageType: "method_call",
+      ...(fingerprint ? { fingerprint } : {}),
+    });
+
+    const listed = makeAssessment({ affectedLocations: [location("3f2a9c1d0b7e4a65")] });
+    expect(shouldSuppress(listed, rules).suppressed).toBe(true);
+
+    const partly = makeAssessment({ affectedLocations: [location("3f2a9c1d0b7e4a65"), location("88c0de21a9f3b410")] });
+    expect(shouldSuppress(partly, rules).suppressed).toBe(false);
+
+    // Locations from scans without fingerprints never match
+    expect(shouldSuppress(makeAssessment({ affectedLocations: [location()] }), rules).suppressed).toBe(false);
+  });
 });
@@ -18,6 +18,7 @@ export function mapAffectedCode(
       line: loc.line,
       context: loc.context ?? "",
       usageType: loc.usage ?? "unknown",
+      ...(loc.fingerprint ? { fingerprint: loc.fingerprint } : {}),
     })),
   );
 }
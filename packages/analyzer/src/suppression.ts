@@ -65,6 +65,14 @@ function matchesRule(
     if (!allMatch) return false;
   }
 
+  // Fingerprints: suppress only if ALL affected locations are listed, so the
+  // rule follows those findings through file moves and line shifts
+  if (rule.fingerprints !== undefined) {
+    if (assessment.affectedLocations.length === 0) return false;
+    const listed = new Set(rule.fingerprints);
+    if (!assessment.affectedLocations.every((loc) => loc.fingerprint && listed.has(loc.fingerprint))) return false;
+  }
+
   return true;
 }
 
@@ -34,6 +34,8 @@ export interface AffectedLocation {
   context: string;
   /** e.g. "method_call", "import", "instantiation" */
   usageType: string;
+  /** The TDM location's stable fingerprint, when the scan recorded one */
+  fingerprint?: string | undefined;
 }
 
 // ---------------------------------------------------------------------------
@@ -78,6 +80,8 @@ export interface SuppressionRule {
   min_priority?: Priority | undefined;
   /** Glob — suppress if ALL affected files match */
   file_path?: string | undefined;
+  /** Finding fingerprints — suppress if ALL affected locations are among them */
+  fingerprints?: string[] | undefined;
 }
 
 // ---------------------------------------------------------------------------
@@ -0,0 +1,85 @@
+import { describe, it, expect } from "vitest";
+import { buildAgentScript, parseAgentLine, AgentCorrelator } from "../agent.js";
+
+describe("buildAgentScript", () => {
+  it("includes only the requested probes", () => {
+    const full = buildAgentScript();
+    expect(full).toContain("kprobe:tcp_connect");
+    expect(full).toContain("uprobe:libc:getaddrinfo");
+    expect(full).toContain("uprobe:libssl:SSL_ctrl /arg1 == 55/");
+
+    const minimal = buildAgentScript({ dns: false, tls: false });
+    expect(minimal).toContain("kprobe:tcp_connect");
+    expect(minimal).not.toContain("uprobe");
+
+    expect(buildAgentScript({ libc: "/lib/x86_64-linux-gnu/libc.so.6" })).toContain(
+      "uprobe:/lib/x86_64-linux-gnu/libc.so.6:getaddrinfo",
+    );
+  });
+});
+
+describe("parseAgentLine", () => {
+  it("parses tcp, dns, and sni events", () => {
+    expect(parseAgentLine("tcp\t42\tnode\t52.1.2.3\t443")).toEqual({
+      kind: "tcp", pid: 42, comm: "node", ip: "52.1.2.3", port: 443,
+    });
+    expect(parseAgentLine("dns\t42\tnode\tAPI.Stripe.com.")).toEqual({
+      kind: "dns", pid: 42, comm: "node", name: "api.stripe.com",
+    });
+    expect(parseAgentLine("sni\t7\tcurl\tapi.openai.com")).toEqual({
+      kind: "sni", pid: 7, comm: "curl", name: "api.openai.com",
+    });
+  });
+
+  it("rejects malformed lines", () => {
+    expect(parseAgentLine("Attaching 3 probes...")).toBeNull();
+    expect(parseAgentLine("tcp\t42\tnode\t52.1.2.3\t0")).toBeNull();
+    expect(parseAgentLine("dns\t42\tnode\tnot a host")).toBeNull();
+    expect(parseAgentLine("dns\t42\tnode\tlocalhost")).toBeNull();
+  });
+});
+
+describe("AgentCorrelator", () => {
+  const now = () => "2026-10-14T10:00:00.000Z";
+
+  it("names connections from resolved DNS lookups, including ones that raced the lookup", async () => {
+    const c = new AgentCorrelator({ now, resolve: async () => ["52.1.2.3"] });
+    c.handle({ kind: "tcp", pid: 1, comm: "node", ip: "52.1.2.3", port: 443 });
+    c.handle({ kind: "dns", pid: 1, comm: "node", name: "api.stripe.com" });
+    await c.settle();
+    c.handle({ kind: "tcp", pid: 1, comm: "node", ip: "52.1.2.3", port: 443 });
+
+    expect(c.observations.map((o) => o.host)).toEqual(["api.stripe.com", "api.stripe.com"]);
+  });
+
+  it("names the process's last unnamed connection from SNI", () => {
+    const c = new AgentCorrelator({ now, resolve: async () => [] });
+    c.handle({ kind: "tcp", pid: 9, comm: "app", ip: "104.18.1.1", port: 443 });
+    c.handle({ kind: "sni", pid: 9, comm: "app", name: "api.openai.com" });
+    c.handle({ kind: "tcp", pid: 10, comm: "other", ip: "104.18.1.1", port: 443 });
+
+    expect(c.observations).toEqual([
+      { source: "agent", ip: "104.18.1.1", port: 443, process: "app", timestamp: now(), host: "api.openai.com" },
+      { source: "agent", ip: "104.18.1.1", port: 443, process: "other", timestamp: now(), host: "api.openai.com" },
+    ]);
+  });
+
+  it("drops private destinations unless asked to keep them", () => {
+    const c = new AgentCorrelator({ now });
+    c.handle({ kind: "tcp", pid: 1, comm: "app", ip: "10.0.0.5", port: 5432 });
+    expect(c.observations).toEqual([]);
+
+    const keep = new AgentCorrelator({ now, includePrivate: true });
+    keep.handle({ kind: "tcp", pid: 1, comm: "app", ip: "10.0.0.5", port: 5432 });
+    expect(keep.observations).toHaveLength(1);
+  });
+
+  it("labels connections with the workload and hands over windows", () => {
+    const c = new AgentCorrelator({ now, clientFor: (pid) => (pid === 1 ? "shop/checkout" : undefined) });
+    c.handle({ kind: "tcp", pid: 1, comm: "node", ip: "52.1.2.3", port: 443 });
+    c.handle({ kind: "tcp", pid: 2, comm: "sshd", ip: "52.1.2.4", port: 443 });
+
+    expect(c.take().map((o) => o.client)).toEqual(["shop/checkout", undefined]);
+    expect(c.observations).toEqual([]);
+  });
+});
@@ -0,0 +1,107 @@
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+import { mkdtempSync, rmSync, writeFileSync } from "node:fs";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import type { DependencyEntry } from "../plugin.js";
+import type { SDKRegistryEntry } from "../registry.js";
+import { createVendorMatcher } from "../runtime.js";
+import {
+  applyVendorAliases,
+  canonicalizeProviders,
+  isHostAlias,
+  loadVendorAliases,
+  normalizeVendorName,
+  vendorNameIndex,
+} from "../aliases.js";
+
+const registry: SDKRegistryEntry[] = [
+  {
+    provider: "aws-ses",
+    display_name: "AWS SES",
+    aliases: ["Amazon SES", "Amazon Simple Email Service"],
+    patterns: {},
+    domains: ["email-smtp.*.amazonaws.com"],
+  },
+  { provider: "aws", display_name: "Amazon Web Services", patterns: {}, domains: ["amazonaws.com"] },
+  // Claims another entry's slug as an alias; the slug still wins
+  { provider: "sendgrid", display_name: "SendGrid", aliases: ["aws"], patterns: {} },
+];
+
+describe("normalizeVendorName", () => {
+  it("folds case, spaces, and punctuation", () => {
+    expect(normalizeVendorName("Amazon SES")).toBe("amazon-ses");
+    expect(normalizeVendorName(" aws_ses ")).toBe("aws-ses");
+    expect(normalizeVendorName("Pub/Sub")).toBe("pub-sub");
+  });
+
+  it("tells hostname patterns apart from names", () => {
+    expect(isHostAlias("email-smtp.*.amazonaws.com")).toBe(true);
+    expect(isHostAlias("mail-relay.corp.acme.io")).toBe(true);
+    expect(isHostAlias("Amazon SES")).toBe(false);
+    expect(isHostAlias("mailer")).toBe(false);
+  });
+});
+
+describe("vendor name resolution", () => {
+  it("resolves slugs, display names, aliases, and hosts to one vendor", () => {
+    const match = createVendorMatcher(registry);
+    expect(match("aws-ses")).toBe("aws-ses");
+    expect(match("AWS SES")).toBe("aws-ses");
+    expect(match("Amazon SES")).toBe("aws-ses");
+    expect(match("amazon_simple_email_service")).toBe("aws-ses");
+    expect(match("email-smtp.ap-south-1.amazonaws.com")).toBe("aws-ses");
+    expect(match("s3.us-east-1.amazonaws.com")).toBe("aws");
+    expect(match("postmark")).toBeNull();
+  });
+
+  it("never lets an alias take over another entry's slug", () => {
+    expect(vendorNameIndex(registry).get("aws")).toBe("aws");
+  });
+});
+
+describe("applyVendorAliases", () => {
+  it("adds an organization's names and hosts, and vendors the catalog doesn't know", () => {
+    const merged = applyVendorAliases(registry, {
+      "aws-ses": ["mailer", "mail-relay.corp.acme.io"],
+      "Acme Billing": ["billing-gw"],
+    });
+    const match = createVendorMatcher(merged);
+    expect(match("mailer")).toBe("aws-ses");
+    expect(match("mail-relay.corp.acme.io")).toBe("aws-ses");
+    expect(match("billing-gw")).toBe("acme-billing");
+    expect(match("Acme Billing")).toBe("acme-billing");
+    // The catalog itself is untouched
+    expect(registry[0]!.aliases).toEqual(["Amazon SES", "Amazon Simple Email Service"]);
+  });
+
+  it("rewrites findings' providers to the canonical slug", () => {
+    const match = createVendorMatcher(applyVendorAliases(registry, { "aws-ses": ["mailer"] }));
+    const entries: DependencyEntry[] = [
+      { kind: "sdk", provider: "mailer", sdk_package: "@acme/mailer", locations: [], usage_count: 0, confidence: "high" },
+      { kind: "api", url: "https://api.acme.io", provider: "acme-internal", locations: [], usage_count: 0, confidence: "low" },
+    ];
+    canonicalizeProviders(entries, match);
+    expect(entries.map((e) => (e.kind === "package" ? null : e.provider))).toEqual(["aws-ses", "acme-internal"]);
+  });
+});
+
+describe("loadVendorAliases", () => {
+  let dir: string;
+
+  beforeAll(() => {
+    dir = mkdtempSync(join(tmpdir(), "tw-aliases-"));
+    writeFileSync(join(dir, ".thirdwatch.yml"), 'version: "1"\nvendor_aliases:\n  aws-ses: [mailer]\n');
+    writeFileSync(join(dir, "aliases.yml"), "stripe:\n  - payments-gw\n");
+    writeFileSync(join(dir, "bad.yml"), "stripe: payments-gw\n");
+  });
+
+  afterAll(() => {
+    rmSync(dir, { recursive: true, force: true });
+  });
+
+  it("reads a config's vendor_aliases or a bare mapping", async () => {
+    expect(await loadVendorAliases(join(dir, ".thirdwatch.yml"))).toEqual({ "aws-ses": ["mailer"] });
+    expect(await loadVendorAliases(join(dir, "aliases.yml"))).toEqual({ stripe: ["payments-gw"] });
+    await expect(loadVendorAliases(join(dir, "bad.yml"))).rejects.toThrow(/must be a list of names/);
+  });
+});
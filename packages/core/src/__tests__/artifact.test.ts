@@ -0,0 +1,301 @@
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+import { createHash } from "node:crypto";
+import { existsSync } from "node:fs";
+import { mkdtemp, readFile, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { crc32, deflateRawSync, gzipSync } from "node:zlib";
+import { openScanTarget, isImageReference, IMAGE_ENV_FILE, IMAGE_IGNORE } from "../artifact.js";
+
+interface Entry {
+  name: string;
+  data?: string | Buffer;
+  /** tar typeflag: "0" file, "2" symlink, "5" directory */
+  type?: string;
+  linkname?: string;
+}
+
+function tarHeader(name: string, size: number, type: string, linkname = ""): Buffer {
+  const header = Buffer.alloc(512);
+  header.write(name.slice(0, 100), 0);
+  header.write("0000644\0", 100);
+  header.write("0000000\0", 108);
+  header.write("0000000\0", 116);
+  header.write(size.toString(8).padStart(11, "0") + "\0", 124);
+  header.write("00000000000\0", 136);
+  header.write(type, 156);
+  header.write(linkname, 157);
+  header.write("ustar\x0000", 257);
+  header.fill(" ", 148, 156);
+  const sum = header.reduce((a, b) => a + b, 0);
+  header.write(sum.toString(8).padStart(6, "0") + "\0 ", 148);
+  return header;
+}
+
+function tarball(entries: Entry[]): Buffer {
+  const blocks: Buffer[] = [];
+  const pad = (b: Buffer) => Buffer.concat([b, Buffer.alloc((512 - (b.length % 512)) % 512)]);
+  for (const e of entries) {
+    if (e.name.length > 100) {
+      // GNU long name
+      const name = Buffer.from(e.name + "\0");
+      blocks.push(tarHeader("././@LongLink", name.length, "L"), pad(name));
+    }
+    const data = Buffer.from(e.data ?? "");
+    blocks.push(tarHeader(e.name, data.length, e.type ?? "0", e.linkname), pad(data));
+  }
+  return Buffer.concat([...blocks, Buffer.alloc(1024)]);
+}
+
+function zipArchive(entries: Entry[]): Buffer {
+  const local: Buffer[] = [];
+  const central: Buffer[] = [];
+  let offset = 0;
+  for (const e of entries) {
+    const data = Buffer.from(e.data ?? "");
+    const compressed = deflateRawSync(data);
+    const name = Buffer.from(e.name);
+    const header = Buffer.alloc(30);
+    header.writeUInt32LE(0x04034b50, 0);
+    header.writeUInt16LE(20, 4);
+    header.writeUInt16LE(8, 8);
+    header.writeUInt32LE(crc32(data), 14);
+    header.writeUInt32LE(compressed.length, 18);
+    header.writeUInt32LE(data.length, 22);
+    header.writeUInt16LE(name.length, 26);
+    local.push(header, name, compressed);
+
+    const record = Buffer.alloc(46);
+    record.writeUInt32LE(0x02014b50, 0);
+    record.writeUInt16LE(20, 6);
+    record.writeUInt16LE(8, 10);
+    record.writeUInt32LE(crc32(data), 16);
+    record.writeUInt32LE(compressed.length, 20);
+    record.writeUInt32LE(data.length, 24);
+    record.writeUInt16LE(name.length, 28);
+    record.writeUInt32LE(((e.type === "2" ? 0o120777 : 0o100644) << 16) >>> 0, 38);
+    record.writeUInt32LE(offset, 42);
+    central.push(record, name);
+    offset += header.length + name.length + compressed.length;
+  }
+  const directory = Buffer.concat(central);
+  const end = Buffer.alloc(22);
+  end.writeUInt32LE(0x06054b50, 0);
+  end.writeUInt16LE(entries.length, 8);
+  end.writeUInt16LE(entries.length, 10);
+  end.writeUInt32LE(directory.length, 12);
+  end.writeUInt32LE(offset, 16);
+  return Buffer.concat([...local, directory, end]);
+}
+
+const APP_FILES: Entry[] = [
+  { name: "app/", type: "5" },
+  { name: "app/main.py", data: 'import stripe\nstripe.Charge.create(amount=100)\n' },
+  { name: "app/config/prod.yaml", data: "openai:\n  base_url: https://api.openai.com/v1\n" },
+  { name: `app/${"nested/".repeat(16)}deep.py`, data: "import requests\n" },
+  { name: "../escape.py", data: "print('outside')\n" },
+  { name: "app/passwd", type: "2", linkname: "/etc/passwd" },
+];
+
+let work: string;
+
+beforeAll(async () => {
+  work = await mkdtemp(join(tmpdir(), "thirdwatch-artifact-test-"));
+});
+
+afterAll(async () => {
+  await rm(work, { recursive: true, force: true });
+});
+
+describe("openScanTarget", () => {
+  it("extracts a tar.gz, dropping symlinks and paths that escape the root", async () => {
+    const file = join(work, "release.tar.gz");
+    const bytes = gzipSync(tarball(APP_FILES));
+    await writeFile(file, bytes);
+    const target = await openScanTarget(file);
+    try {
+      expect(await readFile(join(target.root, "app/main.py"), "utf-8")).toContain("stripe.Charge.create");
+      expect(existsSync(join(target.root, `app/${"nested/".repeat(16)}deep.py`))).toBe(true);
+      expect(existsSync(join(target.root, "app/passwd"))).toBe(false);
+      expect(existsSync(join(target.root, "..", "escape.py"))).toBe(false);
+      expect(target.artifact).toEqual({
+        type: "archive",
+        reference: "release.tar.gz",
+        digest: `sha256:${createHash("sha256").update(bytes).digest("hex")}`,
+      });
+      expect(target.ignore).toEqual([]);
+    } finally {
+      await target.cleanup();
+    }
+    expect(existsSync(target.root)).toBe(false);
+  });
+
+  it("extracts zip archives such as jars and wheels", async () => {
+    const file = join(work, "service.jar");
+    await writeFile(file, zipArchive(APP_FILES.filter((e) => e.type !== "5")));
+    const target = await openScanTarget(file);
+    try {
+      expect(await readFile(join(target.root, "app/config/prod.yaml"), "utf-8")).toContain("api.openai.com");
+      expect(existsSync(join(target.root, "app/passwd"))).toBe(false);
+      expect(existsSync(join(target.root, "..", "escape.py"))).toBe(false);
+    } finally {
+      await target.cleanup();
+    }
+  });
+
+  it("applies a docker save tarball's layers with their whiteouts", async () => {
+    const layer1 = tarball([
+      { name: "usr/lib/python3/http.py", data: "# runtime\n" },
+      { name: "app/main.py", data: "import stripe\n" },
+      { name: "app/legacy.py", data: "import braintree\n" },
+      { name: "app/plugins/old.py", data: "import twilio\n" },
+    ]);
+    const layer2 = gzipSync(
+      tarball([
+        { name: "app/.wh.legacy.py" },
+        { name: "app/plugins/new.py", data: "import sendgrid\n" },
+        { name: "app/plugins/.wh..wh..opq" },
+      ]),
+    );
+    const config = JSON.stringify({ config: { Env: ["PATH=/usr/local/bin:/usr/bin", "PAYMENTS_API=https://api.stripe.com/v1"] } });
+    const id = createHash("sha256").update(config).digest("hex");
+    const file = join(work, "checkout.tar");
+    await writeFile(
+      file,
+      tarball([
+        { name: "layer1/layer.tar", data: layer1 },
+        { name: "layer2/layer.tar", data: layer2 },
+        { name: `${id}.json`, data: config },
+        { name: "manifest.json", data: JSON.stringify([{ Config: `${id}.json`, RepoTags: ["checkout:1.4"], Layers: ["layer1/layer.tar", "layer2/layer.tar"] }]) },
+      ]),
+    );
+
+    const target = await openScanTarget(file);
+    try {
+      expect(existsSync(join(target.root, "app/main.py"))).toBe(true);
+      expect(existsSync(join(target.root, "app/legacy.py"))).toBe(false);
+      expect(existsSync(join(target.root, "app/plugins/old.py"))).toBe(false);
+      expect(existsSync(join(target.root, "app/plugins/new.py"))).toBe(true);
+      expect(await readFile(join(target.root, IMAGE_ENV_FILE), "utf-8")).toContain("PAYMENTS_API=https://api.stripe.com/v1");
+      expect(target.artifact).toEqual({ type: "image", reference: "checkout.tar", digest: `sha256:${id}` });
+      expect(target.ignore).toBe(IMAGE_IGNORE);
+    } finally {
+      await target.cleanup();
+    }
+  });
+
+  it("ignores whiteouts that name their own directory or its parent", async () => {
+    const layer1 = tarball([
+      { name: "app/main.py", data: "import stripe\n" },
+      { name: "app/plugins/billing.py", data: "import braintree\n" },
+    ]);
+    const layer2 = tarball([{ name: "app/plugins/.wh.." }, { name: "app/plugins/.wh..." }, { name: ".wh..." }]);
+    const file = join(work, "whiteouts.tar");
+    await writeFile(
+      file,
+      tarball([
+        { name: "layer1/layer.tar", data: layer1 },
+        { name: "layer2/layer.tar", data: layer2 },
+        { name: "config.json", data: "{}" },
+        { name: "manifest.json", data: JSON.stringify([{ Config: "config.json", Layers: ["layer1/layer.tar", "layer2/layer.tar"] }]) },
+      ]),
+    );
+
+    const target = await openScanTarget(file);
+    try {
+      expect(existsSync(join(target.root, "app/main.py"))).toBe(true);
+      expect(existsSync(join(target.root, "app/plugins/billing.py"))).toBe(true);
+    } finally {
+      await target.cleanup();
+    }
+  });
+
+  it("rejects docker save manifests that point outside the archive", async () => {
+    for (const manifest of [
+      { Config: "config.json", Layers: ["../../../etc/passwd"] },
+      { Config: "../config.json", Layers: [] },
+    ]) {
+      const file = join(work, "escape-image.tar");
+      await writeFile(file, tarball([{ name: "config.json", data: "{}" }, { name: "manifest.json", data: JSON.stringify([manifest]) }]));
+      await expect(openScanTarget(file)).rejects.toThrow(/outside the archive/);
+    }
+  });
+
+  it("rejects OCI digests that aren't algorithm:hex", async () => {
+    for (const digest of ["sha256:../../../../etc/passwd", "../index.json", "sha256:ABCDEF"]) {
+      const file = join(work, "bad-digest.tar");
+      await writeFile(
+        file,
+        tarball([
+          { name: "oci-layout", data: '{"imageLayoutVersion":"1.0.0"}' },
+          { name: "index.json", data: JSON.stringify({ manifests: [{ digest }] }) },
+        ]),
+      );
+      await expect(openScanTarget(file)).rejects.toThrow(/invalid digest/);
+    }
+  });
+
+  it("reads OCI image layouts, choosing linux/amd64 from a multi-platform index", async () => {
+    const blob = (content: string | Buffer) => {
+      const digest = `sha256:${createHash("sha256").update(content).digest("hex")}`;
+      return { digest, entry: { name: `blobs/sha256/${digest.slice(7)}`, data: content } };
+    };
+    const layer = blob(gzipSync(tarball([{ name: "srv/app.js", data: 'fetch("https://api.stripe.com/v1/charges")\n' }])));
+    const config = blob(JSON.stringify({ config: {} }));
+    const manifest = blob(
+      JSON.stringify({
+        mediaType: "application/vnd.oci.image.manifest.v1+json",
+        config: { digest: config.digest },
+        layers: [{ digest: layer.digest }],
+      }),
+    );
+    const index = blob(
+      JSON.stringify({
+        mediaType: "application/vnd.oci.image.index.v1+json",
+        manifests: [
+          { digest: "sha256:" + "0".repeat(64), platform: { os: "linux", architecture: "arm64" } },
+          { digest: manifest.digest, platform: { os: "linux", architecture: "amd64" } },
+        ],
+      }),
+    );
+    const file = join(work, "oci.tar");
+    await writeFile(
+      file,
+      tarball([
+        { name: "oci-layout", data: '{"imageLayoutVersion":"1.0.0"}' },
+        { name: "index.json", data: JSON.stringify({ manifests: [{ mediaType: "application/vnd.oci.image.index.v1+json", digest: index.digest }] }) },
+        index.entry,
+        manifest.entry,
+        config.entry,
+        layer.entry,
+      ]),
+    );
+
+    const target = await openScanTarget(file);
+    try {
+      expect(await readFile(join(target.root, "srv/app.js"), "utf-8")).toContain("api.stripe.com");
+      expect(existsSync(join(target.root, IMAGE_ENV_FILE))).toBe(false);
+      expect(target.artifact).toMatchObject({ type: "image", digest: config.digest });
+    } finally {
+      await target.cleanup();
+    }
+  });
+
+  it("passes directories through untouched", async () => {
+    const target = await openScanTarget(work);
+    expect(target.root).toBe(work);
+    expect(target.artifact).toBeUndefined();
+    expect(existsSync(work)).toBe(true);
+  });
+});
+
+describe("isImageReference", () => {
+  it("needs a tag or digest, so a missing directory isn't pulled", () => {
+    expect(isImageReference("nginx:1.27")).toBe(true);
+    expect(isImageReference("ghcr.io/acme/checkout:1.4")).toBe(true);
+    expect(isImageReference("localhost:5000/team/app:latest")).toBe(true);
+    expect(isImageReference(`acme/app@sha256:${"a".repeat(64)}`)).toBe(true);
+    expect(isImageReference("src")).toBe(false);
+    expect(isImageReference("./build/output")).toBe(false);
+  });
+});
@@ -0,0 +1,75 @@
+import { describe, it, expect } from "vitest";
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "../registry.js";
+import { badgeFor, renderBadge, summarizeRepository } from "../badge.js";
+
+const registry: SDKRegistryEntry[] = [
+  { provider: "stripe", display_name: "Stripe", category: "payments", patterns: {} },
+  { provider: "openai", display_name: "OpenAI", category: "ai", patterns: {}, domains: ["openai.com"] },
+  { provider: "pusher", display_name: "Pusher", category: "communication", patterns: {} },
+];
+
+function tdm(severity?: "error" | "warning"): TDM {
+  return {
+    version: "1.0",
+    metadata: {
+      scan_timestamp: "2026-10-14T10:00:00.000Z",
+      scanner_version: "0.1.0",
+      repository: "github.com/acme/checkout",
+      languages_detected: ["python"],
+      total_dependencies_found: 4,
+      scan_duration_ms: 0,
+    },
+    packages: [],
+    apis: [
+      {
+        url: "https://api.openai.com/v1/chat/completions",
+        method: "POST",
+        ...(severity ? { severity } : {}),
+        locations: [{ file: "chat.py", line: 3 }],
+        usage_count: 1,
+        confidence: "high",
+      },
+    ],
+    sdks: [
+      { provider: "stripe", sdk_package: "stripe", locations: [{ file: "pay.py", line: 1 }], usage_count: 1, confidence: "high" },
+      { provider: "pusher", sdk_package: "pusher", locations: [{ file: "live.py", line: 1 }], usage_count: 1, confidence: "high" },
+    ],
+    infrastructure: [],
+    webhooks: [],
+  };
+}
+
+describe("summarizeRepository", () => {
+  it("counts vendors and derives the policy status", () => {
+    expect(summarizeRepository("checkout", tdm(), registry)).toEqual({
+      repository: "checkout",
+      scanned_at: "2026-10-14T10:00:00.000Z",
+      vendors: 3,
+      dependencies: 4,
+      policy: "passing",
+      denied_vendors: [],
+      findings: { error: 0, warning: 0 },
+    });
+    expect(summarizeRepository("checkout", tdm("warning"), registry).policy).toBe("warning");
+    expect(summarizeRepository("checkout", tdm("error"), registry).policy).toBe("failing");
+    expect(summarizeRepository("checkout", tdm(), registry, { deniedVendors: ["pusher", "twilio"] })).toMatchObject({
+      policy: "failing",
+      denied_vendors: ["pusher"],
+    });
+  });
+});
+
+describe("renderBadge", () => {
+  it("renders both metrics and an unscanned badge", () => {
+    const summary = summarizeRepository("checkout", tdm("error"), registry);
+    expect(badgeFor(summary, "policy")).toEqual({ label: "vendor policy", message: "failing", color: "#e05d44" });
+    expect(badgeFor(summary, "vendors")).toEqual({ label: "vendors", message: "3", color: "#007ec6" });
+    expect(badgeFor(null, "vendors").message).toBe("not scanned");
+
+    const svg = renderBadge(badgeFor(summary, "policy"));
+    expect(svg).toMatch(/^<svg xmlns="http:\/\/www.w3.org\/2000\/svg" width="\d+" height="20"/);
+    expect(svg).toContain('aria-label="vendor policy: failing"');
+    expect(renderBadge({ label: "a<b", message: "\"&\"", color: "#555" })).toContain("a&lt;b: &quot;&amp;&quot;");
+  });
+});
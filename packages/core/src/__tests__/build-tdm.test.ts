@@ -129,7 +129,7 @@ describe("buildTDM", () => {
     expect(tdm.apis[0]!.usage_count).toBe(2);
   });
 
-  it("deduplicates SDKs and merges services_used and api_methods", () => {
+  it("deduplicates SDKs and merges services_used, api_methods, and intents", () => {
     const entries: DependencyEntry[] = [
       {
         kind: "sdk",
@@ -137,6 +137,7 @@ describe("buildTDM", () => {
         sdk_package: "boto3",
         services_used: ["s3"],
         api_methods: ["s3.upload_file"],
+        intents: ["data_write"],
         locations: [{ file: "a.py", line: 1 }],
         usage_count: 1,
         confidence: "high",
@@ -147,6 +148,7 @@ describe("buildTDM", () => {
         sdk_package: "boto3",
         services_used: ["sqs"],
         api_methods: ["sqs.send_message"],
+        intents: ["data_read", "data_write"],
         locations: [{ file: "b.py", line: 5 }],
         usage_count: 1,
         confidence: "high",
@@ -166,6 +168,49 @@ describe("buildTDM", () => {
     expect(tdm.sdks[0]!.api_methods).toEqual(
       expect.arrayContaining(["s3.upload_file", "sqs.send_message"]),
     );
+    expect(tdm.sdks[0]!.intents).toEqual(["data_write", "data_read"]);
+  });
+
+  it("merges the regions of deduplicated SDKs", () => {
+    const sdk = (file: string, regions: string[]): DependencyEntry => ({
+      kind: "sdk",
+      provider: "aws-s3",
+      sdk_package: "boto3",
+      services_used: ["s3"],
+      regions,
+      locations: [{ file, line: 1 }],
+      usage_count: 1,
+      confidence: "high",
+    });
+
+    const tdm = buildTDM([sdk("a.py", ["us-east-1"]), sdk("b.py", ["eu-west-1", "us-east-1"])], {
+      root: "/tmp/test",
+      plugins: [mockPlugin],
+      duration: 50,
+    });
+
+    expect(tdm.sdks).toHaveLength(1);
+    expect(tdm.sdks[0]!.regions).toEqual(["us-east-1", "eu-west-1"]);
+  });
+
+  it("merges the exported data of deduplicated SDKs", () => {
+    const sdk = (file: string, data_exported: string[]): DependencyEntry => ({
+      kind: "sdk",
+      provider: "sentry",
+      sdk_package: "sentry-go",
+      data_exported,
+      locations: [{ file, line: 1 }],
+      usage_count: 1,
+      confidence: "high",
+    });
+
+    const tdm = buildTDM([sdk("a.go", ["stack_traces"]), sdk("b.go", ["stack_traces", "request_payloads"])], {
+      root: "/tmp/test",
+      plugins: [mockPlugin],
+      duration: 50,
+    });
+
+    expect(tdm.sdks[0]!.data_exported).toEqual(["stack_traces", "request_payloads"]);
   });
 
   it("does not include repository in metadata when not provided", () => {
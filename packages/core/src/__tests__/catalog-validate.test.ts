@@ -0,0 +1,121 @@
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+import { mkdtempSync, rmSync, writeFileSync } from "node:fs";
+import { tmpdir } from "node:os";
+import { join, resolve } from "node:path";
+import type { SDKRegistryEntry } from "../registry.js";
+import {
+  validateCatalogEntry,
+  checkCatalogExamples,
+  validateCatalogFiles,
+} from "../catalog-validate.js";
+
+const registriesDir = resolve(__dirname, "../../../../registries/sdks");
+
+describe("validateCatalogEntry", () => {
+  it("accepts a complete entry", () => {
+    expect(
+      validateCatalogEntry({
+        provider: "acme",
+        display_name: "Acme",
+        category: "payments",
+        docs_url: "https://docs.acme.io",
+        patterns: { npm: [{ package: "acme", import_patterns: ["acme"] }] },
+        domains: ["api.acme.io", "*.acme-cdn.net"],
+        ip_ranges: ["192.0.2.0/24", "2001:db8::/32", "198.51.100.7"],
+        examples: [{ ecosystem: "npm", code: `import acme from "acme"` }],
+      }),
+    ).toEqual([]);
+  });
+
+  it("reports schema violations", () => {
+    const errors = validateCatalogEntry({
+      provider: "Acme Inc",
+      display_name: "Acme",
+      category: "fintech",
+      patterns: { cobol: [] },
+      aliases: ["api.acme.io", "--"],
+      domains: ["https://api.acme.io"],
+      ip_ranges: ["192.0.2.0/33"],
+      sla: { uptime: 999.5, url: "acme.io/sla" },
+      user_agents: [{ pattern: "acme-python/(", ecosystem: "pip" }],
+      examples: [{ code: "import acme" }],
+      extra: true,
+    });
+    expect(errors).toEqual(
+      expect.arrayContaining([
+        "unknown top-level key 'extra'",
+        expect.stringMatching(/^'provider' must match/),
+        expect.stringMatching(/^'category' must be one of/),
+        expect.stringMatching(/^unknown ecosystem 'cobol'/),
+        "alias 'api.acme.io' is a hostname; list it under 'domains'",
+        "alias '--' has no letters or digits",
+        expect.stringMatching(/^invalid domain 'https:\/\/api\.acme\.io'/),
+        expect.stringMatching(/^invalid ip range '192\.0\.2\.0\/33'/),
+        expect.stringMatching(/^sla\.uptime must be a percentage/),
+        "sla.url must be an http(s) URL",
+        "user_agents[0].pattern is not a valid regex: acme-python/(",
+        "user_agents[0].sdk_package must be a string",
+        expect.stringMatching(/^user_agents\[0\]\.ecosystem must be one of/),
+        "examples[0] with 'code' needs a valid 'ecosystem'",
+      ]),
+    );
+  });
+});
+
+describe("checkCatalogExamples", () => {
+  const entry: SDKRegistryEntry = {
+    provider: "acme",
+    display_name: "Acme",
+    patterns: { pypi: [{ package: "acme-sdk", import_patterns: ["import acme"] }] },
+    known_api_base_urls: ["https://api.acme.io"],
+    domains: ["acme-cdn.net"],
+    examples: [
+      { ecosystem: "pypi", code: "import acme" },
+      { url: "https://api.acme.io/v1/orders" },
+      { url: "https://eu.acme-cdn.net/asset.js" },
+      { ecosystem: "pypi", code: "from other import thing" },
+      { url: "https://api.notacme.io/v1" },
+    ],
+  };
+
+  it("fails fixtures the entry does not detect", () => {
+    expect(checkCatalogExamples(entry)).toEqual([
+      `examples[3]: no pypi import pattern matches "from other import thing"`,
+      "examples[4]: https://api.notacme.io/v1 matches no known_api_base_urls or domains",
+    ]);
+  });
+});
+
+describe("validateCatalogFiles", () => {
+  let dir: string;
+
+  beforeAll(() => {
+    dir = mkdtempSync(join(tmpdir(), "tw-catalog-validate-"));
+    writeFileSync(join(dir, "acme.yml"), `provider: acme\ndisplay_name: Acme\npatterns:\n  npm:\n    - package: acme\n`);
+    writeFileSync(join(dir, "wrong-name.yml"), `provider: acme\ndisplay_name: Acme 2\npatterns: {}\n`);
+    writeFileSync(join(dir, "broken.yml"), `provider: [unclosed\n`);
+    writeFileSync(join(dir, "acme-pay.yml"), `provider: acme-pay\ndisplay_name: Acme Pay\naliases: ["ACME"]\npatterns: {}\n`);
+  });
+
+  afterAll(() => {
+    rmSync(dir, { recursive: true, force: true });
+  });
+
+  it("flags file name mismatches, duplicate providers, clashing aliases, and YAML errors", async () => {
+    const results = await validateCatalogFiles([dir]);
+    const byFile = Object.fromEntries(results.map((r) => [r.file.slice(dir.length + 1), r.errors]));
+    expect(byFile["acme.yml"]).toEqual([]);
+    expect(byFile["acme-pay.yml"]).toEqual(["'ACME' already names acme"]);
+    expect(byFile["wrong-name.yml"]).toEqual([
+      "file name should be acme.yml",
+      "provider 'acme' is already defined in acme.yml",
+    ]);
+    expect(byFile["broken.yml"]![0]).toMatch(/^YAML parse error/);
+  });
+
+  it("passes for every built-in catalog entry, including their examples", async () => {
+    const results = await validateCatalogFiles([registriesDir]);
+    expect(results.length).toBeGreaterThanOrEqual(51);
+    expect(results.filter((r) => r.errors.length > 0)).toEqual([]);
+  });
+});
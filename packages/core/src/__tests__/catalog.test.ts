@@ -0,0 +1,125 @@
+import { describe, it, expect, beforeAll, afterAll, beforeEach } from "vitest";
+import { generateKeyPairSync, sign } from "node:crypto";
+import { mkdtempSync, mkdirSync, rmSync, writeFileSync } from "node:fs";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import {
+  verifyCatalogSignature,
+  parseCatalogBundle,
+  loadCatalogBundle,
+  importCatalogBundle,
+  resolveCatalog,
+} from "../catalog.js";
+
+const { privateKey, publicKey } = generateKeyPairSync("ed25519");
+const publicPem = publicKey.export({ type: "spki", format: "pem" }).toString();
+
+const bundleData = Buffer.from(
+  JSON.stringify({
+    version: "2026.10.1",
+    created_at: "2026-10-01T00:00:00Z",
+    entries: [
+      { provider: "acme", display_name: "Acme", patterns: { npm: [{ package: "acme-sdk" }] } },
+      { provider: "broken" },
+    ],
+  }),
+);
+const signature = sign(null, bundleData, privateKey).toString("base64");
+
+describe("verifyCatalogSignature", () => {
+  it("accepts bundles signed by a trusted key", () => {
+    expect(verifyCatalogSignature(bundleData, signature, [publicPem])).toBe(true);
+  });
+
+  it("rejects tampered bundles and missing signatures", () => {
+    const tampered = Buffer.from(bundleData.toString().replace("acme-sdk", "evil-sdk"));
+    expect(verifyCatalogSignature(tampered, signature, [publicPem])).toBe(false);
+    expect(verifyCatalogSignature(bundleData, "", [publicPem])).toBe(false);
+  });
+});
+
+describe("parseCatalogBundle", () => {
+  it("keeps valid entries only", () => {
+    const bundle = parseCatalogBundle(bundleData);
+    expect(bundle.version).toBe("2026.10.1");
+    expect(bundle.entries.map((e) => e.provider)).toEqual(["acme"]);
+  });
+
+  it("rejects bundles without a version", () => {
+    expect(() => parseCatalogBundle(`{"entries": []}`)).toThrow(/no valid version/);
+  });
+});
+
+describe("bundle resolution", () => {
+  let home: string;
+
+  beforeAll(() => {
+    home = mkdtempSync(join(tmpdir(), "tw-catalog-"));
+    process.env["THIRDWATCH_HOME"] = home;
+    process.env["THIRDWATCH_CATALOG_PUBLIC_KEY"] = publicPem;
+  });
+
+  afterAll(() => {
+    delete process.env["THIRDWATCH_HOME"];
+    delete process.env["THIRDWATCH_CATALOG_PUBLIC_KEY"];
+    rmSync(home, { recursive: true, force: true });
+  });
+
+  beforeEach(() => {
+    rmSync(join(home, "catalog"), { recursive: true, force: true });
+  });
+
+  it("loads a vendored bundle with its signature", async () => {
+    const file = join(home, "vendored.json");
+    writeFileSync(file, bundleData);
+    writeFileSync(`${file}.sig`, signature);
+    const bundle = await loadCatalogBundle(file);
+    expect(bundle.version).toBe("2026.10.1");
+  });
+
+  it("refuses a vendored bundle without a signature", async () => {
+    const file = join(home, "unsigned.json");
+    writeFileSync(file, bundleData);
+    await expect(loadCatalogBundle(file)).rejects.toThrow(/missing or invalid signature/);
+  });
+
+  it("uses the cached current bundle, or falls back to the built-in catalog", async () => {
+    expect(await resolveCatalog()).toBeNull();
+
+    const dir = join(home, "catalog", "2026.10.1");
+    mkdirSync(dir, { recursive: true });
+    writeFileSync(join(dir, "catalog.json"), bundleData);
+    writeFileSync(join(dir, "catalog.json.sig"), signature);
+    writeFileSync(join(home, "catalog", "current"), "2026.10.1\n");
+
+    const bundle = await resolveCatalog();
+    expect(bundle?.version).toBe("2026.10.1");
+  });
+
+  it("prefers a pinned version already in the cache", async () => {
+    const dir = join(home, "catalog", "2026.10.1");
+    mkdirSync(dir, { recursive: true });
+    writeFileSync(join(dir, "catalog.json"), bundleData);
+    writeFileSync(join(dir, "catalog.json.sig"), signature);
+
+    const bundle = await resolveCatalog({ version: "2026.10.1" });
+    expect(bundle?.entries).toHaveLength(1);
+  });
+
+  it("imports a copied bundle into the cache as current", async () => {
+    const file = join(home, "copied.json");
+    writeFileSync(file, bundleData);
+    writeFileSync(`${file}.sig`, signature);
+    await importCatalogBundle(file);
+    expect((await resolveCatalog())?.version).toBe("2026.10.1");
+  });
+
+  it("downloads nothing in offline mode", async () => {
+    process.env["THIRDWATCH_OFFLINE"] = "1";
+    try {
+      await expect(resolveCatalog({ version: "2026.11.0" })).rejects.toThrow(/offline mode/);
+    } finally {
+      delete process.env["THIRDWATCH_OFFLINE"];
+    }
+  });
+});
@@ -0,0 +1,243 @@
+import { describe, it, expect } from "vitest";
+import { applyCatalogCategories } from "../categories.js";
+import type { DependencyEntry } from "../plugin.js";
+import type { SDKRegistryEntry } from "../registry.js";
+
+const registry: SDKRegistryEntry[] = [
+  {
+    provider: "sendgrid",
+    display_name: "SendGrid",
+    category: "email",
+    patterns: {},
+    known_api_base_urls: ["https://api.sendgrid.com"],
+    domains: ["sendgrid.net"],
+  },
+  {
+    provider: "sentry",
+    display_name: "Sentry",
+    category: "error-tracking",
+    patterns: {},
+  },
+  {
+    provider: "aws-redshift",
+    display_name: "Amazon Redshift",
+    category: "data-warehouse",
+    patterns: {},
+    domains: ["redshift.amazonaws.com"],
+  },
+  {
+    provider: "snowflake",
+    display_name: "Snowflake",
+    category: "data-warehouse",
+    patterns: {},
+    domains: ["snowflakecomputing.com"],
+  },
+  {
+    provider: "stripe",
+    display_name: "Stripe",
+    category: "payments",
+    patterns: {},
+    known_api_base_urls: ["https://api.stripe.com"],
+  },
+];
+
+const loc = [{ file: "main.go", line: 1 }];
+
+describe("applyCatalogCategories", () => {
+  it("sets categories on SDKs by provider and on API calls by host", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "sdk", provider: "sendgrid", sdk_package: "sendgrid-go", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "smtp://smtp.sendgrid.net:587", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://api.stripe.com/v1/charges", method: "POST", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://api.example.com/v1", locations: loc, usage_count: 1, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, registry);
+    expect(entries.map((e) => ("category" in e ? e.category : undefined))).toEqual([
+      "email",
+      "email",
+      "payments",
+      undefined,
+    ]);
+  });
+
+  it("attributes self-hosted WooCommerce stores by their REST path", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "api", url: "https://shop.example.com/wp-json/wc/v3/orders", method: "GET", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://blog.example.com/wp-json/wp/v2/posts", method: "GET", locations: loc, usage_count: 1, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, [
+      { provider: "woocommerce", display_name: "WooCommerce", category: "commerce", patterns: {} },
+    ]);
+    expect(entries.map((e) => ("category" in e ? e.category : undefined))).toEqual(["commerce", undefined]);
+  });
+
+  it("attributes reCAPTCHA's siteverify on www.google.com by path", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "api", url: "https://www.google.com/recaptcha/api/siteverify", method: "POST", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://www.google.com/search", method: "GET", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://api.hcaptcha.com/siteverify", method: "POST", locations: loc, usage_count: 1, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, [
+      { provider: "recaptcha", display_name: "Google reCAPTCHA", category: "bot-mitigation", patterns: {}, domains: ["recaptcha.net"] },
+      { provider: "hcaptcha", display_name: "hCaptcha", category: "bot-mitigation", patterns: {}, domains: ["hcaptcha.com"] },
+    ]);
+    expect(entries.map((e) => ("category" in e ? e.category : undefined))).toEqual(["bot-mitigation", undefined, "bot-mitigation"]);
+  });
+
+  it("prefers a Workspace API path over the shared googleapis.com host", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "api", url: "https://www.googleapis.com/calendar/v3/calendars/primary/events", method: "POST", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://www.googleapis.com/upload/drive/v3/files", method: "POST", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://www.googleapis.com/storage/v1/b/acme/o", method: "GET", locations: loc, usage_count: 1, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, [
+      { provider: "gcp", display_name: "Google Cloud", category: "cloud", patterns: {}, domains: ["googleapis.com"] },
+      { provider: "google-workspace", display_name: "Google Workspace APIs", category: "productivity", patterns: {} },
+    ]);
+    expect(entries.map((e) => ("category" in e ? e.category : undefined))).toEqual(["productivity", "productivity", "cloud"]);
+  });
+
+  it("attributes Fixer on the APILayer marketplace by path", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "api", url: "https://api.apilayer.com/fixer/latest", method: "GET", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml", method: "GET", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://api.apilayer.com/exchangerates_data/latest", method: "GET", locations: loc, usage_count: 1, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, [
+      { provider: "fixer", display_name: "Fixer", category: "exchange-rates", patterns: {}, domains: ["fixer.io"] },
+      { provider: "ecb", display_name: "European Central Bank reference rates", category: "exchange-rates", patterns: {}, domains: ["ecb.europa.eu"] },
+    ]);
+    expect(entries.map((e) => ("category" in e ? e.category : undefined))).toEqual(["exchange-rates", "exchange-rates", undefined]);
+  });
+
+  it("names the sender of inbound webhook routes by path", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "webhook", direction: "inbound_callback", target_url: "/hooks/github", locations: loc, confidence: "medium" },
+      { kind: "webhook", direction: "inbound_callback", target_url: "/webhooks/orders", locations: loc, confidence: "medium" },
+      { kind: "webhook", direction: "inbound_callback", target_url: "/webhooks/stripe", provider: "stripe", locations: loc, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, [
+      ...registry,
+      { provider: "github", display_name: "GitHub", category: "devtools", patterns: {} },
+    ]);
+    expect(entries.map((e) => (e.kind === "webhook" ? e.provider : null))).toEqual(["github", undefined, "stripe"]);
+  });
+
+  it("sets categories on infrastructure by host, then by driver type", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "infrastructure", type: "redshift", connection_ref: "postgres://<redacted>@analytics.abc123.us-east-1.redshift.amazonaws.com:5439/dev", locations: loc, confidence: "high" },
+      { kind: "infrastructure", type: "snowflake", connection_ref: "SNOWFLAKE_DSN", locations: loc, confidence: "high" },
+      { kind: "infrastructure", type: "postgresql", connection_ref: "DATABASE_URL", resolved_host: "acme.snowflakecomputing.com", locations: loc, confidence: "high" },
+      { kind: "infrastructure", type: "postgresql", connection_ref: "DATABASE_URL", locations: loc, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, registry);
+    expect(entries.map((e) => ("category" in e ? e.category : undefined))).toEqual([
+      "data-warehouse",
+      "data-warehouse",
+      "data-warehouse",
+      undefined,
+    ]);
+  });
+
+  it("attributes hosted infrastructure to its vendor, leaving self-hosted clusters alone", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "infrastructure", type: "mongodb", connection_ref: "mongodb+srv://<redacted>@cluster0.abcde.mongodb.net/shop", locations: loc, confidence: "high" },
+      { kind: "infrastructure", type: "mongodb", connection_ref: "mongodb://<redacted>@mongo.internal:27017/shop", locations: loc, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, [
+      { provider: "mongodb", display_name: "MongoDB Atlas", category: "database", patterns: {}, domains: ["mongodb.net"] },
+    ]);
+    expect(entries.map((e) => (e.kind === "infrastructure" ? [e.provider, e.category] : []))).toEqual([
+      ["mongodb", "database"],
+      [undefined, undefined],
+    ]);
+  });
+
+  it("keeps categories set by custom rules", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "sdk", provider: "sendgrid", category: "messaging", sdk_package: "sendgrid-go", locations: loc, usage_count: 1, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, registry);
+    expect(entries[0]!.kind === "sdk" && entries[0]!.category).toBe("messaging");
+  });
+
+  it("marks error-tracking SDKs as exporting stack traces", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "sdk", provider: "sentry", sdk_package: "sentry-go", data_exported: ["user_data"], locations: loc, usage_count: 1, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, registry);
+    expect(entries[0]!.kind === "sdk" && entries[0]!.data_exported).toEqual(["stack_traces", "user_data"]);
+  });
+
+  it("marks feature-flag SDKs as exporting the evaluation context", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "sdk", provider: "launchdarkly", sdk_package: "go-server-sdk", locations: loc, usage_count: 1, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, [
+      { provider: "launchdarkly", display_name: "LaunchDarkly", category: "feature-flags", patterns: {} },
+    ]);
+    expect(entries[0]!.kind === "sdk" && [entries[0]!.category, entries[0]!.data_exported]).toEqual([
+      "feature-flags",
+      ["user_context"],
+    ]);
+  });
+
+  it("treats identity verification as high-sensitivity egress", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "sdk", provider: "onfido", sdk_package: "@onfido/api", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://withpersona.com/api/v1/inquiries", method: "POST", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://withpersona.com/api/v1/accounts", severity: "error", locations: loc, usage_count: 1, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, [
+      { provider: "onfido", display_name: "Onfido", category: "identity-verification", patterns: {} },
+      { provider: "persona", display_name: "Persona", category: "identity-verification", patterns: {}, domains: ["withpersona.com"] },
+    ]);
+    expect(entries.map((e) => [e.kind === "sdk" ? e.data_exported : undefined, e.kind !== "package" && e.severity])).toEqual([
+      [["identity_documents", "biometrics"], "warning"],
+      [undefined, "warning"],
+      [undefined, "error"],
+    ]);
+  });
+
+  it("treats enrichment providers as high-sensitivity egress of contact data", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "sdk", provider: "peopledatalabs", sdk_package: "peopledatalabs-go", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://person.clearbit.com/v2/combined/find", method: "GET", locations: loc, usage_count: 1, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, [
+      { provider: "peopledatalabs", display_name: "People Data Labs", category: "enrichment", patterns: {} },
+      { provider: "clearbit", display_name: "Clearbit", category: "enrichment", patterns: {}, domains: ["clearbit.com"] },
+    ]);
+    expect(entries.map((e) => [e.kind === "sdk" ? e.data_exported : undefined, e.kind !== "package" && e.severity])).toEqual([
+      [["email_addresses", "company_data"], "warning"],
+      [undefined, "warning"],
+    ]);
+  });
+
+  it("marks analytics SDKs as exporting behavioral events and user identifiers", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "sdk", provider: "segment", sdk_package: "segmentio/analytics-go", data_exported: ["user_traits"], locations: loc, usage_count: 1, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, [
+      { provider: "segment", display_name: "Segment", category: "analytics", patterns: {} },
+    ]);
+    expect(entries[0]!.kind === "sdk" && entries[0]!.data_exported).toEqual([
+      "behavioral_events",
+      "user_identifiers",
+      "user_traits",
+    ]);
+  });
+
+  it("marks media-processing SDKs as receiving user uploads", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "sdk", provider: "cloudinary", sdk_package: "cloudinary-go", locations: loc, usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://api.mux.com/video/v1/uploads", method: "POST", locations: loc, usage_count: 1, confidence: "high" },
+    ];
+    applyCatalogCategories(entries, [
+      { provider: "cloudinary", display_name: "Cloudinary", category: "media", patterns: {} },
+      { provider: "mux", display_name: "Mux", category: "media", patterns: {}, domains: ["mux.com"] },
+    ]);
+    expect(entries[0]!.kind === "sdk" && entries[0]!.data_exported).toEqual(["user_content"]);
+    expect(entries[1]!.kind === "api" && entries[1]!.category).toBe("media");
+  });
+});
@@ -0,0 +1,133 @@
+import { describe, it, expect } from "vitest";
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "../registry.js";
+import { compareTDMs, hasChanges, renderComparisonMarkdown } from "../compare.js";
+
+function tdm(parts: Partial<Pick<TDM, "packages" | "sdks" | "apis" | "infrastructure" | "webhooks">>): TDM {
+  return {
+    version: "1.0",
+    metadata: {
+      scan_timestamp: "2026-10-14T10:00:00.000Z",
+      scanner_version: "0.1.0",
+      repository: "github.com/acme/checkout",
+      languages_detected: [],
+      total_dependencies_found: 0,
+      scan_duration_ms: 0,
+    },
+    packages: parts.packages ?? [],
+    apis: parts.apis ?? [],
+    sdks: parts.sdks ?? [],
+    infrastructure: parts.infrastructure ?? [],
+    webhooks: parts.webhooks ?? [],
+  };
+}
+
+const registry: SDKRegistryEntry[] = [
+  { provider: "stripe", display_name: "Stripe", category: "payments", patterns: {} },
+  { provider: "twilio", display_name: "Twilio", category: "communication", patterns: {}, domains: ["twilio.com"] },
+  { provider: "openai", display_name: "OpenAI", category: "ai", patterns: {} },
+];
+
+const loc = (file: string, line = 1) => ({ file, line });
+const sides = { from: { ref: "v1.4.0", commit: "1111111aaaa" }, to: { ref: "v1.5.0", commit: "2222222bbbb" } };
+
+const before = tdm({
+  packages: [
+    { name: "stripe", ecosystem: "npm", current_version: "14.0.0", manifest_file: "package.json", locations: [], usage_count: 0, confidence: "high" },
+    { name: "twilio", ecosystem: "npm", current_version: "4.0.0", manifest_file: "package.json", locations: [], usage_count: 0, confidence: "high" },
+  ],
+  sdks: [
+    { provider: "stripe", sdk_package: "stripe", api_methods: ["charges.create"], locations: [loc("pay.ts")], usage_count: 1, confidence: "high" },
+    { provider: "twilio", sdk_package: "twilio", locations: [loc("sms.ts")], usage_count: 1, confidence: "high" },
+  ],
+  apis: [
+    { url: "https://api.stripe.com/v1/charges", method: "POST", provider: "stripe", locations: [loc("pay.ts")], usage_count: 1, confidence: "high" },
+    { url: "https://verify.twilio.com/v2/Services", method: "POST", locations: [loc("otp.ts")], usage_count: 1, confidence: "medium" },
+    { url: "https://api.internal/v1/users", first_party: true, locations: [loc("users.ts")], usage_count: 1, confidence: "medium" },
+  ],
+});
+
+const after = tdm({
+  packages: [
+    { name: "stripe", ecosystem: "npm", current_version: "15.2.0", manifest_file: "package.json", locations: [], usage_count: 0, confidence: "high" },
+    { name: "openai", ecosystem: "npm", current_version: "4.56.0", manifest_file: "package.json", locations: [], usage_count: 0, confidence: "high" },
+  ],
+  sdks: [
+    {
+      provider: "stripe",
+      sdk_package: "stripe",
+      api_methods: ["paymentIntents.create"],
+      locations: [loc("pay.ts"), loc("refund.ts")],
+      usage_count: 2,
+      confidence: "high",
+    },
+    { provider: "openai", sdk_package: "openai", locations: [loc("chat.ts")], usage_count: 1, confidence: "high" },
+  ],
+  apis: [
+    { url: "https://api.stripe.com/v1/charges", method: "POST", provider: "stripe", locations: [loc("pay.ts"), loc("refund.ts")], usage_count: 2, confidence: "high" },
+    { url: "https://api.internal/v2/users", first_party: true, locations: [loc("users.ts")], usage_count: 1, confidence: "medium" },
+  ],
+  infrastructure: [{ type: "redis", connection_ref: "REDIS_URL", locations: [loc("cache.ts")], confidence: "high" }],
+  webhooks: [
+    { direction: "inbound_callback", target_url: "/stripe/webhook", provider: "stripe", locations: [loc("hooks.ts")], confidence: "medium" },
+  ],
+});
+
+describe("compareTDMs", () => {
+  it("reports vendors, packages, SDKs, and endpoints added, removed, and changed", () => {
+    const report = compareTDMs(before, after, registry, sides);
+
+    expect(report.repository).toBe("github.com/acme/checkout");
+    expect(report.vendors.added).toEqual([{ vendor: "openai", display_name: "OpenAI", category: "ai", usages: 1 }]);
+    // The Twilio SDK and the verify.twilio.com call both count
+    expect(report.vendors.removed).toEqual([{ vendor: "twilio", display_name: "Twilio", category: "communication", usages: 2 }]);
+
+    expect(report.packages.added).toEqual([{ ecosystem: "npm", name: "openai", version: "4.56.0" }]);
+    expect(report.packages.removed).toEqual([{ ecosystem: "npm", name: "twilio", version: "4.0.0" }]);
+    expect(report.packages.changed).toEqual([{ ecosystem: "npm", name: "stripe", from_version: "14.0.0", to_version: "15.2.0" }]);
+
+    expect(report.sdks.changed).toEqual([
+      {
+        provider: "stripe",
+        sdk_package: "stripe",
+        from_usages: 1,
+        to_usages: 2,
+        methods_added: ["paymentIntents.create"],
+        methods_removed: ["charges.create"],
+      },
+    ]);
+
+    // First-party endpoints are not third-party changes
+    expect(report.endpoints.added).toEqual([]);
+    expect(report.endpoints.removed).toEqual([{ method: "POST", url: "https://verify.twilio.com/v2/Services", usages: 1 }]);
+    expect(report.endpoints.changed).toEqual([
+      { method: "POST", url: "https://api.stripe.com/v1/charges", provider: "stripe", from_usages: 1, to_usages: 2 },
+    ]);
+    expect(report.infrastructure.added).toEqual([{ type: "redis", target: "REDIS_URL" }]);
+    expect(report.webhooks.added).toEqual([{ type: "inbound_callback", target: "/stripe/webhook", provider: "stripe" }]);
+  });
+
+  it("finds no changes between identical scans", () => {
+    expect(hasChanges(compareTDMs(before, before, registry, sides))).toBe(false);
+  });
+});
+
+describe("renderComparisonMarkdown", () => {
+  it("renders release-notes sections for what changed", () => {
+    const markdown = renderComparisonMarkdown(compareTDMs(before, after, registry, sides));
+    expect(markdown).toContain("## Third-party dependency changes: v1.4.0 (1111111) → v1.5.0 (2222222)");
+    expect(markdown).toContain("### New vendors\n\n- **OpenAI** (ai) — 1 usage\n");
+    expect(markdown).toContain("### Removed vendors\n\n- **Twilio** (communication)\n");
+    expect(markdown).toContain("- stripe 14.0.0 → 15.2.0 (npm)");
+    expect(markdown).toContain(
+      "- stripe (stripe) — 1 → 2 call sites; now calls `paymentIntents.create`; no longer calls `charges.create`",
+    );
+    expect(markdown).toContain("### Changed endpoints\n\n- `POST https://api.stripe.com/v1/charges` (stripe) — 1 → 2 call sites");
+    expect(markdown).not.toContain("### New endpoints");
+    expect(markdown).toContain("- inbound callback `/stripe/webhook` (stripe)");
+  });
+
+  it("says so when nothing changed", () => {
+    expect(renderComparisonMarkdown(compareTDMs(before, before, registry, sides))).toContain("No third-party dependency changes.");
+  });
+});
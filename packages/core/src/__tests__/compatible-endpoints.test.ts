@@ -0,0 +1,110 @@
+import { describe, it, expect } from "vitest";
+import type { DependencyEntry } from "../plugin.js";
+import type { SDKRegistryEntry } from "../registry.js";
+import { createCompatibleEndpointResolver } from "../compatible-endpoints.js";
+
+const registry: SDKRegistryEntry[] = [
+  { provider: "openai", display_name: "OpenAI", category: "ai", patterns: {}, domains: ["api.openai.com"] },
+  { provider: "azure-openai", display_name: "Azure OpenAI", category: "ai", patterns: {}, domains: ["*.openai.azure.com"] },
+  { provider: "groq", display_name: "Groq", category: "ai", patterns: {}, domains: ["api.groq.com"] },
+  { provider: "aws", display_name: "AWS", category: "cloud", patterns: {}, domains: ["amazonaws.com"] },
+  { provider: "aws-s3", display_name: "AWS S3", category: "storage", patterns: {}, domains: ["s3.amazonaws.com"] },
+  { provider: "cloudflare-r2", display_name: "Cloudflare R2", category: "storage", patterns: {}, domains: ["r2.cloudflarestorage.com"] },
+  { provider: "backblaze-b2", display_name: "Backblaze B2", category: "storage", patterns: {}, domains: ["backblazeb2.com"] },
+];
+
+function client(baseUrl?: string): DependencyEntry {
+  return {
+    kind: "sdk",
+    provider: "openai",
+    sdk_package: "openai",
+    ...(baseUrl ? { base_url: baseUrl } : {}),
+    locations: [{ file: "llm.py", line: 1 }],
+    usage_count: 1,
+    confidence: "high",
+  };
+}
+
+function s3(baseUrl?: string): DependencyEntry {
+  return {
+    kind: "sdk",
+    provider: "aws-s3",
+    sdk_package: "boto3",
+    ...(baseUrl ? { base_url: baseUrl } : {}),
+    locations: [{ file: "storage.py", line: 1 }],
+    usage_count: 1,
+    confidence: "high",
+  };
+}
+
+function resolveAll(entries: DependencyEntry[], env: Record<string, string> = {}) {
+  createCompatibleEndpointResolver(registry, env)(entries);
+  return entries.map((e) => (e.kind === "sdk" ? [e.provider, e.base_url, e.category] : []));
+}
+
+describe("createCompatibleEndpointResolver", () => {
+  it("reports the catalog vendor behind a custom base URL", () => {
+    expect(
+      resolveAll([client("https://acme.openai.azure.com"), client("https://api.groq.com/openai/v1")]),
+    ).toEqual([
+      ["azure-openai", "https://acme.openai.azure.com", undefined],
+      ["groq", "https://api.groq.com/openai/v1", undefined],
+    ]);
+  });
+
+  it("separates self-hosted model servers from unknown hosted gateways", () => {
+    expect(
+      resolveAll([
+        client("http://localhost:8000/v1"),
+        client("http://vllm.ml.svc.cluster.local:8000/v1"),
+        client("http://10.0.3.7:11434/v1"),
+        client("https://llm-gateway.example.com/v1"),
+      ]),
+    ).toEqual([
+      ["self-hosted", "http://localhost:8000/v1", "ai"],
+      ["self-hosted", "http://vllm.ml.svc.cluster.local:8000/v1", "ai"],
+      ["self-hosted", "http://10.0.3.7:11434/v1", "ai"],
+      ["openai-compatible", "https://llm-gateway.example.com/v1", "ai"],
+    ]);
+  });
+
+  it("resolves env lookups and falls back to OPENAI_BASE_URL", () => {
+    const env = { LLM_GATEWAY_URL: "https://api.groq.com/openai/v1", OPENAI_BASE_URL: "http://ollama:11434/v1" };
+    expect(resolveAll([client("${LLM_GATEWAY_URL}"), client()], env)).toEqual([
+      ["groq", "https://api.groq.com/openai/v1", undefined],
+      ["self-hosted", "http://ollama:11434/v1", "ai"],
+    ]);
+  });
+
+  it("leaves clients of the default endpoint or an unresolved URL alone", () => {
+    expect(resolveAll([client("https://api.openai.com/v1"), client("${UNSET_URL}"), client()])).toEqual([
+      ["openai", "https://api.openai.com/v1", undefined],
+      ["openai", "${UNSET_URL}", undefined],
+      ["openai", undefined, undefined],
+    ]);
+  });
+
+  it("reports the storage provider behind an S3 client's endpoint", () => {
+    expect(
+      resolveAll([
+        s3("https://8d5c1a2f9e0b4c7d.r2.cloudflarestorage.com"),
+        s3("https://s3.us-west-004.backblazeb2.com"),
+        s3("http://minio:9000"),
+        s3("https://objects.example.com"),
+      ]),
+    ).toEqual([
+      ["cloudflare-r2", "https://8d5c1a2f9e0b4c7d.r2.cloudflarestorage.com", undefined],
+      ["backblaze-b2", "https://s3.us-west-004.backblazeb2.com", undefined],
+      ["self-hosted", "http://minio:9000", "storage"],
+      ["s3-compatible", "https://objects.example.com", "storage"],
+    ]);
+  });
+
+  it("keeps S3 clients on other AWS endpoints and reads AWS_ENDPOINT_URL_S3", () => {
+    const env = { AWS_ENDPOINT_URL: "https://s3.eu-central-1.wasabisys.com", AWS_ENDPOINT_URL_S3: "http://localhost:9000" };
+    expect(resolveAll([s3("https://s3-fips.us-east-1.amazonaws.com"), s3()], env)).toEqual([
+      ["aws-s3", "https://s3-fips.us-east-1.amazonaws.com", undefined],
+      ["self-hosted", "http://localhost:9000", "storage"],
+    ]);
+  });
+});
@@ -0,0 +1,97 @@
+import { describe, it, expect } from "vitest";
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "../registry.js";
+import { computeConcentration, groupByService } from "../concentration.js";
+
+function tdm(repository: string | undefined, providers: string[]): TDM {
+  return {
+    version: "1.0",
+    metadata: {
+      scan_timestamp: "2026-10-14T10:00:00.000Z",
+      scanner_version: "0.1.0",
+      ...(repository ? { repository } : {}),
+      languages_detected: [],
+      total_dependencies_found: 0,
+      scan_duration_ms: 0,
+    },
+    packages: [],
+    apis: [],
+    sdks: providers.map((provider) => ({
+      provider,
+      sdk_package: provider,
+      locations: [{ file: `${provider}.ts`, line: 1 }],
+      usage_count: 1,
+      confidence: "high" as const,
+    })),
+    infrastructure: [],
+    webhooks: [],
+  };
+}
+
+const registry: SDKRegistryEntry[] = [
+  { provider: "stripe", display_name: "Stripe", category: "payments", patterns: {} },
+  { provider: "adyen", display_name: "Adyen", category: "payments", patterns: {} },
+  { provider: "sentry", display_name: "Sentry", category: "error-tracking", patterns: {} },
+  { provider: "openai", display_name: "OpenAI", category: "ai", patterns: {}, sla: { uptime: 99.9, url: "https://example.com/sla" } },
+];
+
+describe("computeConcentration", () => {
+  const inventory = groupByService(
+    [
+      tdm("checkout", ["stripe", "sentry"]),
+      tdm("billing", ["stripe", "adyen", "sentry"]),
+      tdm("search", ["openai", "sentry"]),
+      tdm("checkout", ["openai"]),
+    ],
+    (i) => `file-${i}`,
+  );
+
+  it("ranks vendors by reach, critical reach, and lock-in", () => {
+    const report = computeConcentration(inventory, registry, { critical: ["checkout", "billing", "unknown"] });
+
+    expect(report.total_services).toBe(3);
+    expect(report.critical_services).toEqual(["checkout", "billing"]);
+    expect(report.vendors.map((v) => [v.vendor, v.score, v.tier])).toEqual([
+      // 0.5×1 + 0.3×1 + 0.2×1
+      ["sentry", 100, "high"],
+      // 0.5×(2/3) + 0.3×1 + 0.2×0.5
+      ["stripe", 73, "high"],
+      ["openai", 68, "high"],
+      ["adyen", 32, "medium"],
+    ]);
+    expect(report.vendors.find((v) => v.vendor === "stripe")).toMatchObject({
+      services: ["checkout", "billing"],
+      sole_provider_in: ["checkout"],
+    });
+    expect(report.vendors.find((v) => v.vendor === "openai")?.uptime).toBe(99.9);
+    expect(report.shared_critical_vendors).toEqual(["sentry", "stripe"]);
+  });
+
+  it("computes per-category HHI over services", () => {
+    const report = computeConcentration(inventory, registry);
+    const payments = report.categories.find((c) => c.category === "payments");
+    // Stripe in 2 of 3 payment slots, Adyen in 1: (66.7)² + (33.3)²
+    expect(payments).toEqual({ category: "payments", vendors: [{ vendor: "stripe", services: 2 }, { vendor: "adyen", services: 1 }], hhi: 5556 });
+    expect(report.categories[0]).toMatchObject({ category: "ai", hhi: 10000 });
+  });
+
+  it("splits a monorepo scan into its mapped services", () => {
+    const monorepo = tdm("acme/platform", ["stripe", "sentry"]);
+    monorepo.sdks[0]!.locations = [{ file: "services/payments/stripe.ts", line: 1, service: "payments-api" }];
+    monorepo.sdks[1]!.locations = [
+      { file: "services/payments/app.ts", line: 1, service: "payments-api" },
+      { file: "tools/report.ts", line: 1 },
+    ];
+    monorepo.sdks[1]!.usage_count = 2;
+
+    const services = groupByService([monorepo], (i) => `file-${i}`);
+    expect(services.map((s) => [s.service, s.tdms[0]!.sdks.map((d) => [d.provider, d.usage_count])])).toEqual([
+      ["payments-api", [["stripe", 1], ["sentry", 1]]],
+      ["acme/platform", [["sentry", 1]]],
+    ]);
+  });
+
+  it("names TDMs without a repository by their position", () => {
+    expect(groupByService([tdm(undefined, [])], (i) => `file-${i}`)[0]?.service).toBe("file-0");
+  });
+});
@@ -0,0 +1,42 @@
+import { describe, it, expect } from "vitest";
+import { csvCell, formatCsv } from "../csv.js";
+
+describe("csvCell", () => {
+  it("leaves plain fields alone", () => {
+    expect(csvCell("stripe")).toBe("stripe");
+    expect(csvCell(42)).toBe("42");
+    expect(csvCell("")).toBe("");
+  });
+
+  it("quotes fields with commas, quotes, or line breaks", () => {
+    expect(csvCell("a,b")).toBe('"a,b"');
+    expect(csvCell('say "hi"')).toBe('"say ""hi"""');
+    expect(csvCell("two\nlines")).toBe('"two\nlines"');
+    expect(csvCell("crlf\r\nend")).toBe('"crlf\r\nend"');
+  });
+
+  it("defuses spreadsheet formulas", () => {
+    expect(csvCell("=HYPERLINK(\"http://evil\")")).toBe('"\'=HYPERLINK(""http://evil"")"');
+    expect(csvCell("+1")).toBe("'+1");
+    expect(csvCell("-2+3")).toBe("'-2+3");
+    expect(csvCell("@SUM(A1)")).toBe("'@SUM(A1)");
+    expect(csvCell("\tcmd")).toBe("'\tcmd");
+    expect(csvCell("a=b")).toBe("a=b");
+  });
+});
+
+describe("formatCsv", () => {
+  it("joins rows with CRLF and ends with one", () => {
+    const csv = formatCsv([
+      ["seq", "action", "target", "details"],
+      [1, "suppression.created", "3f2a", JSON.stringify({ reason: "=cmd|' /C calc'!A0", repository: "*" })],
+    ]);
+    expect(csv).toBe(
+      'seq,action,target,details\r\n1,suppression.created,3f2a,"{""reason"":""=cmd|\' /C calc\'!A0"",""repository"":""*""}"\r\n',
+    );
+  });
+
+  it("defuses a formula in a field that also needs quoting", () => {
+    expect(formatCsv([["=1,2"]])).toBe(`"'=1,2"\r\n`);
+  });
+});
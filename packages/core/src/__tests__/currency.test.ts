@@ -0,0 +1,114 @@
+import { describe, it, expect } from "vitest";
+import type { TDM, TDMPackage } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "../registry.js";
+import { buildCurrencyReport, fetchLatestVersion, versionsBehind } from "../currency.js";
+import type { FetchFn } from "../currency.js";
+
+const registry: SDKRegistryEntry[] = [
+  { provider: "stripe", display_name: "Stripe", category: "payments", patterns: { go: [{ package: "github.com/stripe/stripe-go" }], npm: [{ package: "stripe" }] } },
+  { provider: "openai", display_name: "OpenAI", category: "ai", patterns: { pypi: [{ package: "openai" }] } },
+];
+
+/** Serves canned JSON bodies by URL; anything else is a 404 */
+function fakeFetch(routes: Record<string, unknown>): FetchFn & { calls: string[] } {
+  const calls: string[] = [];
+  const fn = async (url: string) => {
+    calls.push(url);
+    return url in routes
+      ? new Response(JSON.stringify(routes[url]), { status: 200 })
+      : new Response("not found", { status: 404 });
+  };
+  return Object.assign(fn, { calls });
+}
+
+function pkg(name: string, ecosystem: string, current_version: string): TDMPackage {
+  return { name, ecosystem, current_version, manifest_file: "manifest", locations: [], usage_count: 0, confidence: "high" };
+}
+
+function tdm(packages: TDMPackage[]): TDM {
+  return {
+    version: "1.0",
+    metadata: { scan_timestamp: "2026-10-14T10:00:00.000Z", scanner_version: "0.1.0", languages_detected: [], total_dependencies_found: 0, scan_duration_ms: 0 },
+    packages,
+    apis: [],
+    sdks: [],
+    infrastructure: [],
+    webhooks: [],
+  };
+}
+
+describe("versionsBehind", () => {
+  it("counts majors, and minors only within the same major", () => {
+    expect(versionsBehind("v78.3.0", "v81.0.1")).toEqual({ majors: 3, minors: 0 });
+    expect(versionsBehind("^1.2.0", "1.9.4")).toEqual({ majors: 0, minors: 7 });
+    expect(versionsBehind("unknown", "1.0.0")).toBeNull();
+  });
+});
+
+describe("fetchLatestVersion", () => {
+  it("probes newer Go major versions, which live at a different module path", async () => {
+    const fetchFn = fakeFetch({
+      "https://proxy.golang.org/github.com/stripe/stripe-go/v78/@latest": { Version: "v78.12.0" },
+      "https://proxy.golang.org/github.com/stripe/stripe-go/v79/@latest": { Version: "v79.4.0" },
+      "https://proxy.golang.org/github.com/stripe/stripe-go/v80/@latest": { Version: "v80.1.0" },
+    });
+    expect(await fetchLatestVersion("go", "github.com/stripe/stripe-go/v78", "v78.0.0", fetchFn)).toBe("v80.1.0");
+    expect(fetchFn.calls.at(-1)).toBe("https://proxy.golang.org/github.com/stripe/stripe-go/v81/@latest");
+  });
+
+  it("escapes capital letters in Go module paths", async () => {
+    const fetchFn = fakeFetch({ "https://proxy.golang.org/github.com/!azure/azure-sdk-for-go/@latest": { Version: "v68.0.0" } });
+    expect(await fetchLatestVersion("go", "github.com/Azure/azure-sdk-for-go", "v60.0.0", fetchFn)).toBe("v68.0.0");
+  });
+
+  it("returns null when the registry is unreachable", async () => {
+    const fetchFn: FetchFn = async () => {
+      throw new Error("ECONNREFUSED");
+    };
+    expect(await fetchLatestVersion("npm", "stripe", "14.0.0", fetchFn)).toBeNull();
+  });
+});
+
+describe("buildCurrencyReport", () => {
+  const fetchFn = fakeFetch({
+    "https://proxy.golang.org/github.com/stripe/stripe-go/v76/@latest": { Version: "v76.25.0" },
+    "https://proxy.golang.org/github.com/stripe/stripe-go/v77/@latest": { Version: "v77.0.0" },
+    "https://proxy.golang.org/github.com/stripe/stripe-go/v78/@latest": { Version: "v78.1.0" },
+    "https://proxy.golang.org/github.com/stripe/stripe-go/v79/@latest": { Version: "v79.2.0" },
+    "https://pypi.org/pypi/openai/json": { info: { version: "1.51.0" } },
+  });
+  const scan = tdm([
+    pkg("github.com/stripe/stripe-go/v76", "go", "v76.3.0"),
+    pkg("openai", "pypi", "1.40.0"),
+    pkg("github.com/jackc/pgx/v5", "go", "v5.5.0"),
+  ]);
+
+  it("reports how far each vendor SDK is behind, skipping non-vendor packages", async () => {
+    const report = await buildCurrencyReport(scan, registry, { fetchFn });
+    expect(report.sdks.map((s) => [s.package, s.latest_version, s.majors_behind, s.minors_behind])).toEqual([
+      ["github.com/stripe/stripe-go/v76", "v79.2.0", 3, 0],
+      ["openai", "1.51.0", 0, 11],
+    ]);
+    expect(report.violations).toEqual([]);
+  });
+
+  it("applies the most specific policy: provider, then category, then *", async () => {
+    const report = await buildCurrencyReport(scan, registry, {
+      fetchFn,
+      policies: [
+        { match: "*", max_majors_behind: 5 },
+        { match: "payments", max_majors_behind: 2 },
+      ],
+    });
+    expect(report.violations.map((v) => [v.sdk.provider, v.policy.match])).toEqual([["stripe", "payments"]]);
+
+    const lenient = await buildCurrencyReport(scan, registry, {
+      fetchFn,
+      policies: [
+        { match: "payments", max_majors_behind: 2 },
+        { match: "stripe", max_majors_behind: 3 },
+      ],
+    });
+    expect(lenient.violations).toEqual([]);
+  });
+});
@@ -0,0 +1,73 @@
+import { describe, it, expect } from "vitest";
+import { compileRules, applyCustomRules } from "../custom-rules.js";
+import type { CustomRule } from "../custom-rules.js";
+
+const acme: CustomRule = {
+  id: "acme-billing",
+  vendor: "acme-billing",
+  category: "payments",
+  match: {
+    import: "github.com/acme/billing-go",
+    domain: "^api\\.acme\\.io$",
+    config_key: "^ACME_(API|SECRET)_KEY$",
+    code: "acme\\.NewClient\\(",
+  },
+};
+
+describe("applyCustomRules", () => {
+  const rules = compileRules([acme]);
+
+  it("matches import paths, including subpackages", () => {
+    const entries = applyCustomRules(
+      rules,
+      `import (\n\t"github.com/acme/billing-go/invoices"\n)\n`,
+      "main.go",
+    );
+    expect(entries).toEqual([
+      {
+        kind: "sdk",
+        provider: "acme-billing",
+        sdk_package: "github.com/acme/billing-go",
+        rule_id: "acme-billing",
+        category: "payments",
+        locations: [{ file: "main.go", line: 2, context: `"github.com/acme/billing-go/invoices"` }],
+        usage_count: 1,
+        confidence: "high",
+      },
+    ]);
+  });
+
+  it("creates API findings for matching domains", () => {
+    const entries = applyCustomRules(rules, `resp, _ := http.Get("https://api.acme.io/v1/invoices")`, "main.go");
+    expect(entries).toHaveLength(1);
+    expect(entries[0]).toMatchObject({
+      kind: "api",
+      url: "https://api.acme.io/v1/invoices",
+      provider: "acme-billing",
+      category: "payments",
+    });
+  });
+
+  it("matches config keys in code and config files", () => {
+    expect(applyCustomRules(rules, `key := os.Getenv("ACME_API_KEY")`, "main.go")).toHaveLength(1);
+    expect(applyCustomRules(rules, `ACME_SECRET_KEY: abc123`, "deploy/values.yaml")).toHaveLength(1);
+    expect(applyCustomRules(rules, `ACME_REGION: us-east-1`, "deploy/values.yaml")).toEqual([]);
+  });
+
+  it("matches code patterns", () => {
+    const entries = applyCustomRules(rules, `client := acme.NewClient(cfg)`, "main.go");
+    expect(entries[0]).toMatchObject({ kind: "sdk", provider: "acme-billing" });
+  });
+
+  it("ignores unrelated lines and lookalike domains", () => {
+    expect(applyCustomRules(rules, `url := "https://api.acme.io.evil.com/x"\nfmt.Println("hi")`, "main.go")).toEqual([]);
+  });
+});
+
+describe("compileRules", () => {
+  it("rejects invalid regular expressions", () => {
+    expect(() =>
+      compileRules([{ id: "broken", vendor: "x", match: { code: "(" } }]),
+    ).toThrow(/Rule broken: invalid code pattern/);
+  });
+});
@@ -0,0 +1,76 @@
+import { describe, it, expect } from "vitest";
+import type { DependencyEntry } from "../plugin.js";
+import { labelDataFlows } from "../data-flow.js";
+
+const at = (file: string, line = 1, fingerprint?: string) => [{ file, line, ...(fingerprint ? { fingerprint } : {}) }];
+
+function labels(entries: DependencyEntry[]) {
+  return entries.map((e) => (e.kind === "package" ? null : [e.data_flow ?? null, e.data_classification ?? null]));
+}
+
+describe("labelDataFlows", () => {
+  it("infers direction from methods, intents, and exported data, and pci/pii from the catalog", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "api", url: "https://status.example.com/api", method: "GET", locations: at("a.ts"), usage_count: 1, confidence: "high" },
+      { kind: "api", provider: "stripe", category: "payments", url: "https://api.stripe.com/v1/charges", method: "POST", locations: at("a.ts"), usage_count: 1, confidence: "high" },
+      { kind: "sdk", provider: "aws-s3", sdk_package: "boto3", intents: ["data_read", "data_write"], locations: at("s3.py"), usage_count: 1, confidence: "high" },
+      { kind: "sdk", provider: "openai", sdk_package: "openai", intents: ["inference"], locations: at("llm.py"), usage_count: 1, confidence: "high" },
+      { kind: "sdk", provider: "segment", category: "analytics", sdk_package: "analytics", data_exported: ["behavioral_events", "user_identifiers"], locations: at("track.js"), usage_count: 1, confidence: "high" },
+      { kind: "webhook", direction: "inbound_callback", target_url: "/hooks/stripe", provider: "stripe", locations: at("hooks.ts"), confidence: "high" },
+      { kind: "infrastructure", type: "postgresql", connection_ref: "DATABASE_URL", locations: at("db.ts"), confidence: "high" },
+      { kind: "package", name: "stripe", ecosystem: "npm", current_version: "14.0.0", manifest_file: "package.json", locations: at("package.json"), usage_count: 1, confidence: "high" },
+    ];
+    labelDataFlows(entries);
+    expect(labels(entries)).toEqual([
+      ["receive", null],
+      ["send", ["pci"]],
+      ["bidirectional", null],
+      ["bidirectional", null],
+      ["send", ["pii"]],
+      ["receive", null],
+      [null, null],
+      null,
+    ]);
+  });
+
+  it("applies annotations by provider, rule, file, and fingerprint, later ones winning", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "sdk", provider: "stripe", category: "payments", sdk_package: "stripe", rule_id: "TW-STRIPE-SDK-PYTHON", locations: at("billing/charge.py", 3), usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://status.example.com/api", method: "GET", rule_id: "TW-HTTP-URL", locations: at("services/status/poll.ts"), usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://hooks.example.com/notify", method: "POST", rule_id: "TW-HTTP-URL", locations: at("jobs/notify.ts", 8, "3f9a0c2e1b7d4a58"), usage_count: 1, confidence: "high" },
+    ];
+    labelDataFlows(entries, [
+      { provider: "stripe", classification: ["pci", "pii"] },
+      { rule: "TW-*-SDK-PYTHON", file: "billing/**", data_flow: "bidirectional" },
+      { rule: "TW-HTTP-URL", file: "services/status/**", classification: ["public"] },
+      { fingerprints: ["3f9a0c2e1b7d4a58"], classification: ["internal"] },
+      { fingerprints: ["3f9a0c2e1b7d4a58"], classification: ["customer-contracts"] },
+      { provider: "twilio", classification: ["pii"] },
+    ]);
+    expect(labels(entries)).toEqual([
+      ["bidirectional", ["pci", "pii"]],
+      ["receive", ["public"]],
+      ["send", ["customer-contracts"]],
+    ]);
+  });
+
+  it("needs every matcher of an annotation to match", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "sdk", provider: "stripe", sdk_package: "stripe", rule_id: "TW-STRIPE-SDK-GO", locations: at("pay.go"), usage_count: 1, confidence: "high" },
+    ];
+    labelDataFlows(entries, [{ provider: "stripe", file: "billing/**", classification: ["pci"] }]);
+    expect(labels(entries)).toEqual([[null, null]]);
+  });
+
+  it("matches annotations by logical service", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "api", url: "https://api.adyen.com/v71/payments", method: "POST", locations: [{ file: "services/payments/pay.ts", line: 4, service: "payments-api" }], usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://api.adyen.com/v71/payments", method: "GET", locations: at("web/pay.ts"), usage_count: 1, confidence: "high" },
+    ];
+    labelDataFlows(entries, [{ service: "payments-*", classification: ["pci"] }]);
+    expect(labels(entries)).toEqual([
+      ["send", ["pci"]],
+      ["receive", null],
+    ]);
+  });
+});
@@ -0,0 +1,108 @@
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+import { mkdtempSync, rmSync, writeFileSync } from "node:fs";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { startExecDetector, sanitizeDetectorEntries } from "../detectors.js";
+
+const DETECTOR_SCRIPT = `
+const { createInterface } = require("node:readline");
+createInterface({ input: process.stdin }).on("line", (line) => {
+  const msg = JSON.parse(line);
+  if (msg.type === "handshake") return reply({ protocol: 1 });
+  if (msg.file.endsWith("bad.go")) return reply({ id: msg.id, error: "cannot parse" });
+  const entries = [];
+  msg.source.split("\\n").forEach((text, i) => {
+    if (text.includes("partner.acme.io")) {
+      entries.push({
+        kind: "api",
+        url: "https://partner.acme.io",
+        locations: [{ file: msg.file, line: i + 1 }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+  });
+  reply({ id: msg.id, entries });
+});
+function reply(obj) { process.stdout.write(JSON.stringify(obj) + "\\n"); }
+`;
+
+describe("startExecDetector", () => {
+  let dir: string;
+  let script: string;
+
+  beforeAll(() => {
+    dir = mkdtempSync(join(tmpdir(), "tw-detector-"));
+    script = join(dir, "detector.cjs");
+    writeFileSync(script, DETECTOR_SCRIPT);
+  });
+
+  afterAll(() => {
+    rmSync(dir, { recursive: true, force: true });
+  });
+
+  it("exchanges analyze requests over the exec protocol", async () => {
+    const detector = await startExecDetector(
+      { name: "acme", command: process.execPath, args: [script], extensions: [".go"] },
+      dir,
+    );
+    try {
+      const entries = await detector.analyze({
+        filePath: join(dir, "client.go"),
+        source: `package main\nconst base = "https://partner.acme.io/v1"\n`,
+        scanRoot: dir,
+        resolvedEnv: {},
+      });
+      expect(entries).toEqual([
+        {
+          kind: "api",
+          url: "https://partner.acme.io",
+          locations: [{ file: "client.go", line: 2 }],
+          usage_count: 1,
+          confidence: "high",
+        },
+      ]);
+    } finally {
+      await detector.close();
+    }
+  });
+
+  it("surfaces per-file detector errors", async () => {
+    const detector = await startExecDetector(
+      { name: "acme", command: process.execPath, args: [script], extensions: [".go"] },
+      dir,
+    );
+    try {
+      await expect(
+        detector.analyze({ filePath: join(dir, "bad.go"), source: "", scanRoot: dir, resolvedEnv: {} }),
+      ).rejects.toThrow("Detector acme: cannot parse");
+    } finally {
+      await detector.close();
+    }
+  });
+
+  it("rejects detectors that do not complete the handshake", async () => {
+    const silent = join(dir, "silent.cjs");
+    writeFileSync(silent, `process.stdin.resume(); process.stdin.on("end", () => process.exit(0));\nprocess.stdout.write("{\\"protocol\\":99}\\n");`);
+    await expect(
+      startExecDetector({ name: "old", command: process.execPath, args: [silent], extensions: [".go"] }, dir),
+    ).rejects.toThrow("unsupported protocol 99");
+  });
+});
+
+describe("sanitizeDetectorEntries", () => {
+  it("drops malformed entries and locations in other files", () => {
+    const entries = sanitizeDetectorEntries(
+      [
+        { kind: "api", url: "https://a.example", locations: [{ file: "other.go", line: 1 }], usage_count: 1 },
+        { kind: "bogus", locations: [{ file: "main.go", line: 1 }] },
+        "not an object",
+        { kind: "sdk", provider: "acme", sdk_package: "acme-go", locations: [{ file: "main.go", line: 3 }], usage_count: 1 },
+      ],
+      "main.go",
+    );
+    expect(entries).toEqual([
+      { kind: "sdk", provider: "acme", sdk_package: "acme-go", locations: [{ file: "main.go", line: 3 }], usage_count: 1, confidence: "medium" },
+    ]);
+  });
+});
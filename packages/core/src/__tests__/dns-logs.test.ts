@@ -0,0 +1,85 @@
+import { describe, it, expect } from "vitest";
+import { mkdtemp, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { gzipSync } from "node:zlib";
+import { parseDnsLogLine, detectDnsLogFormat, normalizeQueryName } from "../dns-logs.js";
+import { readLogLines } from "../runtime.js";
+
+const now = () => "2026-10-14T12:00:00.000Z";
+
+describe("parseDnsLogLine", () => {
+  it("parses CoreDNS log plugin lines", () => {
+    const line = `[INFO] 10.244.0.5:50553 - 27759 "A IN api.stripe.com. udp 32 false 512" NOERROR qr,rd,ra 106 0.000123s`;
+    expect(parseDnsLogLine(line, "coredns", now)).toEqual({
+      source: "dns", host: "api.stripe.com", client: "10.244.0.5", timestamp: now(),
+    });
+    const stamped = `2026-10-14T09:30:00.000Z [INFO] 10.244.0.5:1 - 2 "AAAA IN api.openai.com. udp 32 false 512" NOERROR qr 1 0.1s`;
+    expect(parseDnsLogLine(stamped, "coredns", now)?.timestamp).toBe("2026-10-14T09:30:00.000Z");
+  });
+
+  it("skips failed lookups, internal names, and non-connect record types", () => {
+    expect(parseDnsLogLine(`[INFO] 10.0.0.1:1 - 1 "A IN nope.example.com. udp 32 false 512" NXDOMAIN qr 1 0.1s`, "coredns", now)).toBeNull();
+    expect(parseDnsLogLine(`[INFO] 10.0.0.1:1 - 1 "A IN api.stripe.com.default.svc.cluster.local. udp 32 false 512" NOERROR qr 1 0.1s`, "coredns", now)).toBeNull();
+    expect(parseDnsLogLine(`[INFO] 10.0.0.1:1 - 1 "TXT IN example.com. udp 32 false 512" NOERROR qr 1 0.1s`, "coredns", now)).toBeNull();
+  });
+
+  it("parses Route 53 Resolver query logs", () => {
+    const line = JSON.stringify({
+      version: "1.100000",
+      query_timestamp: "2026-10-14T10:00:00Z",
+      query_name: "hooks.slack.com.",
+      query_type: "A",
+      query_class: "IN",
+      rcode: "NOERROR",
+      srcaddr: "10.0.1.5",
+      srcids: { instance: "i-0abc123" },
+    });
+    expect(parseDnsLogLine(line, "route53", now)).toEqual({
+      source: "dns", host: "hooks.slack.com", client: "i-0abc123", timestamp: "2026-10-14T10:00:00.000Z",
+    });
+    expect(parseDnsLogLine("not json", "route53", now)).toBeNull();
+  });
+
+  it("parses dnstap text output", () => {
+    const line = `10:15:30.123456 CQ 10.0.0.9 UDP 43b "api.twilio.com." IN A`;
+    expect(parseDnsLogLine(line, "dnstap", now)).toEqual({
+      source: "dns", host: "api.twilio.com", client: "10.0.0.9", timestamp: "2026-10-14T10:15:30.123Z",
+    });
+    // Responses (CR) are not counted separately from their queries
+    expect(parseDnsLogLine(`10:15:30.2 CR 10.0.0.9 UDP 59b "api.twilio.com." IN A`, "dnstap", now)).toBeNull();
+  });
+});
+
+describe("detectDnsLogFormat", () => {
+  it("recognizes each format from a sample line", () => {
+    expect(detectDnsLogFormat(`{"query_name":"a.b.com.","query_type":"A"}`)).toBe("route53");
+    expect(detectDnsLogFormat(`[INFO] 10.0.0.1:1 - 1 "A IN a.b.com. udp 32 false 512" NOERROR qr 1 0.1s`)).toBe("coredns");
+    expect(detectDnsLogFormat(`10:15:30.1 CQ 10.0.0.9 UDP 43b "a.b.com." IN A`)).toBe("dnstap");
+    expect(detectDnsLogFormat("hello")).toBeNull();
+  });
+});
+
+describe("normalizeQueryName", () => {
+  it("lowercases and strips the root dot", () => {
+    expect(normalizeQueryName("API.Stripe.COM.")).toBe("api.stripe.com");
+    expect(normalizeQueryName("localhost")).toBeNull();
+    expect(normalizeQueryName("5.0.0.10.in-addr.arpa.")).toBeNull();
+  });
+});
+
+describe("readLogLines", () => {
+  it("reads plain and gzipped files", async () => {
+    const dir = await mkdtemp(join(tmpdir(), "tw-dns-"));
+    await writeFile(join(dir, "q.log"), "a\n\nb\n");
+    await writeFile(join(dir, "q.log.gz"), gzipSync("c\nd\n"));
+
+    const plain: string[] = [];
+    await readLogLines(join(dir, "q.log"), (l) => plain.push(l));
+    const gz: string[] = [];
+    await readLogLines(join(dir, "q.log.gz"), (l) => gz.push(l));
+
+    expect(plain).toEqual(["a", "b"]);
+    expect(gz).toEqual(["c", "d"]);
+  });
+});
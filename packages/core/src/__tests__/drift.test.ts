@@ -0,0 +1,108 @@
+import { describe, it, expect } from "vitest";
+import type { TDM } from "@thirdwatch/tdm";
+import { computeDrift } from "../drift.js";
+
+function tdm(partial: Partial<TDM>): TDM {
+  return {
+    version: "1.0",
+    metadata: {
+      scan_timestamp: "2026-10-14T10:00:00.000Z",
+      scanner_version: "0.1.0",
+      languages_detected: [],
+      total_dependencies_found: 0,
+      scan_duration_ms: 0,
+    },
+    packages: [],
+    apis: [],
+    sdks: [],
+    infrastructure: [],
+    webhooks: [],
+    ...partial,
+  };
+}
+
+const runtime = (url: string, provider: string | null, count: number) => ({
+  url,
+  provider,
+  runtime: { source: "agent", count, first_seen: "2026-10-14T10:00:00.000Z", last_seen: "2026-10-14T10:05:00.000Z" },
+  locations: [{ file: "runtime:agent", line: 1 }],
+  usage_count: count,
+  confidence: "high" as const,
+});
+
+describe("computeDrift", () => {
+  const staticTdm = tdm({
+    sdks: [
+      { provider: "stripe", sdk_package: "stripe", locations: [{ file: "pay.ts", line: 3 }], usage_count: 1, confidence: "high" },
+      { provider: "twilio", sdk_package: "twilio", locations: [{ file: "sms.ts", line: 1 }, { file: "sms.ts", line: 9 }], usage_count: 2, confidence: "high" },
+    ],
+    apis: [
+      { url: "https://api.partner.io/v1", locations: [{ file: "partner.ts", line: 4 }], usage_count: 1, confidence: "high" },
+      { url: "https://billing.internal/x", first_party: true, provider: "acme", locations: [{ file: "b.ts", line: 1 }], usage_count: 1, confidence: "high" },
+    ],
+  });
+
+  it("splits vendors into runtime-only, static-only, and confirmed", () => {
+    const report = computeDrift(staticTdm, [
+      tdm({ apis: [runtime("https://api.stripe.com", "stripe", 40), runtime("https://o123.ingest.sentry.io", "sentry", 7)] }),
+      tdm({ apis: [runtime("https://api.stripe.com/v1/charges", "stripe", 2)] }),
+    ]);
+
+    expect(report.confirmed).toEqual([
+      { vendor: "stripe", static_usages: 1, runtime_count: 42, hosts: ["api.stripe.com"], files: ["pay.ts"] },
+    ]);
+    expect(report.runtime_only).toEqual([
+      { vendor: "sentry", static_usages: 0, runtime_count: 7, hosts: ["o123.ingest.sentry.io"], files: [] },
+    ]);
+    expect(report.static_only).toEqual([
+      { vendor: "twilio", static_usages: 2, runtime_count: 0, hosts: [], files: ["sms.ts"] },
+    ]);
+  });
+
+  it("compares unclassified hosts by hostname and ignores first-party entries", () => {
+    const report = computeDrift(staticTdm, [
+      tdm({
+        apis: [
+          runtime("https://api.partner.io", null, 3),
+          runtime("https://52.1.2.3", null, 5),
+          { ...runtime("https://billing.internal", "acme", 9), first_party: true },
+        ],
+      }),
+    ]);
+    expect(report.unknown_runtime_hosts).toEqual([{ host: "52.1.2.3", runtime_count: 5 }]);
+    expect(report.runtime_only).toEqual([]);
+    expect(report.static_only.map((v) => v.vendor)).toEqual(["twilio", "stripe"]);
+  });
+
+  it("lines up runtime SDK versions with the versions manifests declare", () => {
+    const withPackages = tdm({
+      ...staticTdm,
+      packages: [
+        { name: "stripe", ecosystem: "pypi", current_version: "==8.0.0", manifest_file: "requirements.txt", locations: [], usage_count: 1, confidence: "high" },
+        { name: "github.com/stripe/stripe-go/v76", ecosystem: "go", current_version: "v76.2.0", manifest_file: "go.mod", locations: [], usage_count: 1, confidence: "high" },
+      ],
+    });
+    const api = runtime("https://api.stripe.com", "stripe", 12);
+    const report = computeDrift(withPackages, [
+      tdm({
+        apis: [
+          {
+            ...api,
+            runtime: {
+              ...api.runtime,
+              sdks: [
+                { sdk_package: "stripe", ecosystem: "pypi", version: "7.1.0", count: 10 },
+                { sdk_package: "github.com/stripe/stripe-go", ecosystem: "go", version: "76.2.0", count: 2 },
+              ],
+            },
+          },
+        ],
+      }),
+    ]);
+
+    expect(report.confirmed[0]!.sdks).toEqual([
+      { sdk_package: "github.com/stripe/stripe-go", ecosystem: "go", runtime_versions: ["76.2.0"], static_version: "v76.2.0", version_matches: true },
+      { sdk_package: "stripe", ecosystem: "pypi", runtime_versions: ["7.1.0"], static_version: "==8.0.0", version_matches: false },
+    ]);
+  });
+});
@@ -0,0 +1,89 @@
+import { describe, it, expect } from "vitest";
+import type { TDM, TDMApi } from "@thirdwatch/tdm";
+import {
+  registrableDomain,
+  parseRdapDomain,
+  parseCymruTxt,
+  catalogHosts,
+  enrichUnknownApis,
+  buildEnrichmentDataset,
+} from "../enrich.js";
+
+describe("registrableDomain", () => {
+  it("strips subdomains down to the registered domain", () => {
+    expect(registrableDomain("api.eu.acme.io")).toBe("acme.io");
+    expect(registrableDomain("acme.io")).toBe("acme.io");
+  });
+
+  it("keeps two-level public suffixes intact", () => {
+    expect(registrableDomain("api.acme.co.uk")).toBe("acme.co.uk");
+  });
+});
+
+describe("parseRdapDomain", () => {
+  it("extracts registration date, registrant, and registrar", () => {
+    const result = parseRdapDomain({
+      events: [
+        { eventAction: "last changed", eventDate: "2025-06-01T00:00:00Z" },
+        { eventAction: "registration", eventDate: "2012-03-14T10:00:00Z" },
+      ],
+      entities: [
+        { roles: ["registrar"], vcardArray: ["vcard", [["fn", {}, "text", "MarkMonitor Inc."]]] },
+        { roles: ["registrant"], vcardArray: ["vcard", [["org", {}, "text", "Acme Corp"]]] },
+      ],
+    });
+    expect(result).toEqual({
+      registered_at: "2012-03-14T10:00:00Z",
+      registrar: "MarkMonitor Inc.",
+      registrant: "Acme Corp",
+    });
+  });
+
+  it("skips privacy-redacted registrants", () => {
+    const result = parseRdapDomain({
+      entities: [
+        { roles: ["registrant"], vcardArray: ["vcard", [["fn", {}, "text", "REDACTED FOR PRIVACY"]]] },
+      ],
+    });
+    expect(result.registrant).toBeUndefined();
+  });
+});
+
+describe("parseCymruTxt", () => {
+  it("parses origin and AS records", () => {
+    expect(parseCymruTxt("16509 | 52.94.0.0/22 | US | arin | 2015-09-02", "origin")).toEqual({ asn: 16509 });
+    expect(parseCymruTxt("16509 | US | arin | 2000-05-04 | AMAZON-02, US", "as")).toEqual({ as_name: "AMAZON-02, US" });
+  });
+
+  it("returns nothing for malformed records", () => {
+    expect(parseCymruTxt("", "origin")).toEqual({});
+    expect(parseCymruTxt("16509 | US", "as")).toEqual({});
+  });
+});
+
+describe("enrichUnknownApis", () => {
+  it("skips catalog hosts, known providers, first-party, and localhost without network calls", async () => {
+    const apis: TDMApi[] = [
+      { url: "https://api.stripe.com/v1/charges", locations: [{ file: "a.go", line: 1 }], usage_count: 1, confidence: "high" },
+      { url: "https://x.example", provider: "x", locations: [{ file: "a.go", line: 2 }], usage_count: 1, confidence: "high" },
+      { url: "https://svc.internal", first_party: true, locations: [{ file: "a.go", line: 3 }], usage_count: 1, confidence: "high" },
+      { url: "http://localhost:8080/health", locations: [{ file: "a.go", line: 4 }], usage_count: 1, confidence: "high" },
+    ];
+    await enrichUnknownApis(apis, catalogHosts(["https://api.stripe.com"]));
+    expect(apis.every((a) => a.enrichment === undefined)).toBe(true);
+  });
+
+  it("uses a dataset offline and looks nothing else up", async () => {
+    const apis: TDMApi[] = [
+      { url: "https://hooks.acme-partner.io/notify", locations: [{ file: "a.go", line: 1 }], usage_count: 1, confidence: "low" },
+      { url: "https://api.unlisted.dev/v1", locations: [{ file: "a.go", line: 2 }], usage_count: 1, confidence: "low" },
+    ];
+    const dataset = buildEnrichmentDataset([
+      { apis: [{ ...apis[0]!, url: "https://acme-partner.io", enrichment: { registrant: "Acme Partner Ltd", asn: 16509 } }] } as TDM,
+    ]);
+    expect(dataset).toEqual({ "acme-partner.io": { registrant: "Acme Partner Ltd", asn: 16509 } });
+
+    await enrichUnknownApis(apis, [], { dataset, offline: true });
+    expect(apis.map((a) => a.enrichment)).toEqual([{ registrant: "Acme Partner Ltd", asn: 16509 }, undefined]);
+  });
+});
@@ -0,0 +1,101 @@
+import { describe, it, expect } from "vitest";
+import { readFile } from "node:fs/promises";
+import { resolve } from "node:path";
+import { environmentOf, annotateEnvironments, vendorsByEnvironment } from "../environments.js";
+import { detectConfigEndpoints } from "../config-endpoints.js";
+import { buildTDM } from "../build-tdm.js";
+import { loadSDKRegistry } from "../registry.js";
+import { createVendorMatcher } from "../runtime.js";
+import type { DependencyEntry } from "../plugin.js";
+
+const fixturesRoot = resolve(__dirname, "../../../../fixtures/environments");
+const registriesDir = resolve(__dirname, "../../../../registries");
+
+const FILES = [
+  "config/default.yaml",
+  "config/dev.yaml",
+  "config/prod.yaml",
+  "k8s/base/kustomization.yaml",
+  "k8s/overlays/staging/kustomization.yaml",
+];
+
+async function scanConfigs(): Promise<DependencyEntry[]> {
+  const matchVendor = createVendorMatcher(await loadSDKRegistry(registriesDir));
+  const entries: DependencyEntry[] = [];
+  for (const file of FILES) {
+    const source = await readFile(resolve(fixturesRoot, file), "utf-8");
+    const found = detectConfigEndpoints(source, file, matchVendor);
+    annotateEnvironments(found, source, file);
+    entries.push(...found);
+  }
+  return entries;
+}
+
+describe("environmentOf", () => {
+  it("reads the environment from config file names", () => {
+    expect(environmentOf("config/prod.yaml")).toBe("production");
+    expect(environmentOf("src/main/resources/application-dev.yml")).toBe("development");
+    expect(environmentOf("appsettings.Production.json")).toBe("production");
+    expect(environmentOf("charts/api/values-staging.yaml")).toBe("staging");
+    expect(environmentOf(".env.test")).toBe("test");
+  });
+
+  it("reads the environment from overlay directories", () => {
+    expect(environmentOf("k8s/overlays/prod/kustomization.yaml")).toBe("production");
+    expect(environmentOf("infra/environments/staging/main.tfvars")).toBe("staging");
+    expect(environmentOf("deploy/uat/values.yaml")).toBe("staging");
+  });
+
+  it("leaves shared files and source code untagged", () => {
+    expect(environmentOf("config/default.yaml")).toBeNull();
+    expect(environmentOf("k8s/base/kustomization.yaml")).toBeNull();
+    // Source files named after an environment aren't overlays
+    expect(environmentOf("src/prod.py")).toBeNull();
+    expect(environmentOf("tests/test_payments.py")).toBeNull();
+    expect(environmentOf("src/live/stream.ts")).toBeNull();
+  });
+});
+
+describe("annotateEnvironments", () => {
+  it("tags config endpoints with the overlay they come from", async () => {
+    const entries = await scanConfigs();
+    expect(
+      entries.map((e) => (e.kind === "api" ? [e.url, e.locations[0]!.file, e.locations[0]!.environment] : null)),
+    ).toEqual([
+      ["https://api.openai.com/v1", "config/default.yaml", undefined],
+      ["https://api.stripe.com", "config/dev.yaml", "development"],
+      ["https://api-m.sandbox.paypal.com", "config/dev.yaml", "development"],
+      ["https://api.stripe.com", "config/prod.yaml", "production"],
+      ["https://api-m.paypal.com", "config/prod.yaml", "production"],
+      ["https://api.stripe.com", "k8s/overlays/staging/kustomization.yaml", "staging"],
+      ["https://api.sendgrid.com/v3", "k8s/overlays/staging/kustomization.yaml", "staging"],
+    ]);
+  });
+
+  it("tags test-mode keys and sandbox hosts in files that name no environment", () => {
+    const source = [
+      `stripe.api_key = "sk_test_4eC39HqLyjWDarjtT1zdp7dc"`,
+      `PAYPAL = "https://api-m.sandbox.paypal.com/v2/checkout/orders"`,
+      `LIVE = "https://api-m.paypal.com/v2/checkout/orders"`,
+    ].join("\n");
+    const entries: DependencyEntry[] = [
+      { kind: "sdk", provider: "stripe", sdk_package: "stripe", locations: [{ file: "app.py", line: 1 }], usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://api-m.sandbox.paypal.com/v2/checkout/orders", locations: [{ file: "app.py", line: 2 }], usage_count: 1, confidence: "high" },
+      { kind: "api", url: "https://api-m.paypal.com/v2/checkout/orders", locations: [{ file: "app.py", line: 3 }], usage_count: 1, confidence: "high" },
+    ];
+    annotateEnvironments(entries, source, "app.py");
+    expect(entries.map((e) => e.locations[0]!.environment)).toEqual(["test", "test", undefined]);
+  });
+});
+
+describe("vendorsByEnvironment", () => {
+  it("separates sandbox-only vendors from production dependencies", async () => {
+    const tdm = buildTDM(await scanConfigs(), { root: fixturesRoot, plugins: [], duration: 1 });
+    expect(vendorsByEnvironment(tdm)).toEqual([
+      { environment: "all", vendors: ["openai"] },
+      { environment: "development", vendors: ["paypal", "stripe"] },
+      { environment: "staging", vendors: ["sendgrid", "stripe"] },
+      { environment: "production", vendors: ["paypal", "stripe"] },
+    ]);
+  });
+});
@@ -0,0 +1,37 @@
+import { describe, it, expect } from "vitest";
+import { resolve } from "node:path";
+import { explainVendor } from "../explain.js";
+import { loadSDKRegistry } from "../registry.js";
+
+const registriesDir = resolve(__dirname, "../../../../registries");
+
+describe("explainVendor", () => {
+  it("returns the catalog's links for a vendor named by slug", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const stripe = explainVendor("stripe", registry);
+
+    expect(stripe).toMatchObject({
+      vendor: "stripe",
+      display_name: "Stripe",
+      category: "payments",
+      status_page_url: "https://status.stripe.com",
+      compliance: {
+        privacy_policy_url: "https://stripe.com/privacy",
+        dpa_url: "https://stripe.com/legal/dpa",
+        subprocessors_url: "https://stripe.com/legal/service-providers",
+        trust_center_url: "https://docs.stripe.com/security",
+      },
+    });
+    expect(stripe!.domains).toContain("api.stripe.com");
+  });
+
+  it("finds vendors by display name, host, or URL", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+
+    expect(explainVendor("Google Analytics", registry)?.vendor).toBe("google-analytics");
+    expect(explainVendor("widget.intercom.io", registry)?.vendor).toBe("intercom");
+    expect(explainVendor("https://js.stripe.com/v3/", registry)?.vendor).toBe("stripe");
+    expect(explainVendor("ecb", registry)?.compliance).toEqual({});
+    expect(explainVendor("acme-internal", registry)).toBeNull();
+  });
+});
@@ -0,0 +1,106 @@
+import { describe, it, expect } from "vitest";
+import type { SDKRegistryEntry } from "../registry.js";
+import { createVendorMatcher } from "../runtime.js";
+import { detectFallbackEndpoints, isFallbackCandidate, looksBinary, shannonEntropy } from "../fallback.js";
+
+const registry: SDKRegistryEntry[] = [
+  { provider: "stripe", display_name: "Stripe", category: "payments", patterns: {}, known_api_base_urls: ["https://api.stripe.com"] },
+  { provider: "openai", display_name: "OpenAI", category: "ai", patterns: {}, known_api_base_urls: ["https://api.openai.com"] },
+  { provider: "upstash", display_name: "Upstash", category: "database", patterns: {}, domains: ["upstash.io"] },
+];
+const matchVendor = createVendorMatcher(registry);
+
+describe("isFallbackCandidate", () => {
+  it("skips binaries, media, docs, license files, and languages with their own analyzer", () => {
+    expect(isFallbackCandidate("lib/payments.lua")).toBe(true);
+    expect(isFallbackCandidate("lib/app/client.ex")).toBe(true);
+    expect(isFallbackCandidate("Makefile")).toBe(true);
+    expect(isFallbackCandidate("assets/logo.PNG")).toBe(false);
+    expect(isFallbackCandidate("docs/setup.md")).toBe(false);
+    expect(isFallbackCandidate("mix.lock")).toBe(false);
+    expect(isFallbackCandidate("LICENSE")).toBe(false);
+    expect(isFallbackCandidate("CHANGELOG.rst")).toBe(false);
+    expect(isFallbackCandidate("src/app.py")).toBe(false);
+  });
+
+  it("tells binary contents apart from text", () => {
+    expect(looksBinary("local x = 1\n")).toBe(false);
+    expect(looksBinary("\x7fELF\0\0\0")).toBe(true);
+  });
+});
+
+describe("shannonEntropy", () => {
+  it("is low for repetitive text and high for random-looking text", () => {
+    expect(shannonEntropy("aaaaaaaa")).toBe(0);
+    expect(shannonEntropy("api.stripe.com")).toBeLessThan(3.5);
+    expect(shannonEntropy("Q2hlY2tvdXRTZXNzaW9uLmNyZWF0ZQ9xK3mZpL7wTb")).toBeGreaterThan(4.5);
+  });
+});
+
+describe("detectFallbackEndpoints", () => {
+  it("finds vendor URLs in a Lua file", () => {
+    const source = [
+      "local http = require('socket.http')",
+      "-- see https://api.openai.com for the docs",
+      'local body = http.request("https://api.stripe.com/v1/charges")',
+      'local hook = "https://hooks.acme-partner.io/notify"',
+    ].join("\n");
+    const entries = detectFallbackEndpoints(source, "lib/pay.lua", matchVendor);
+
+    expect(entries).toEqual([
+      {
+        kind: "api",
+        url: "https://api.stripe.com/v1/charges",
+        provider: "stripe",
+        locations: [{ file: "lib/pay.lua", line: 3, context: 'local body = http.request("https://api.stripe.com/v1/charges")', usage: "fallback" }],
+        usage_count: 1,
+        confidence: "medium",
+      },
+      expect.objectContaining({ kind: "api", url: "https://hooks.acme-partner.io/notify", confidence: "low" }),
+    ]);
+    expect(entries[1]).not.toHaveProperty("provider");
+  });
+
+  it("finds connection strings and catalog hosts in an Elixir config", () => {
+    const source = [
+      "config :app, Cache,",
+      '  url: "redis://default:pw@eu1-cache.upstash.io:6379"',
+      "config :app, OpenAI,",
+      '  host: "api.openai.com"',
+      '  module: "Elixir.App.Web.Endpoint"',
+    ].join("\n");
+    const entries = detectFallbackEndpoints(source, "config/prod.exs", matchVendor);
+
+    expect(entries).toEqual([
+      expect.objectContaining({
+        kind: "infrastructure",
+        type: "redis",
+        connection_ref: "redis://default:pw@eu1-cache.upstash.io:6379",
+        confidence: "medium",
+      }),
+      expect.objectContaining({ kind: "api", url: "https://api.openai.com", provider: "openai", confidence: "low" }),
+    ]);
+  });
+
+  it("ignores local, example, namespace, and documentation hosts", () => {
+    const source = [
+      'target = "http://localhost:8080/health"',
+      'peer = "http://10.0.4.12:9000"',
+      'sample = "https://api.example.com/v1"',
+      '<svg xmlns="http://www.w3.org/2000/svg">',
+      'homepage = "https://api.stripe.com/docs"',
+      'db = "postgres://app@db.internal:5432/app"',
+    ].join("\n");
+    expect(detectFallbackEndpoints(source, "conf/app.conf", matchVendor)).toEqual([]);
+  });
+
+  it("ignores catalog hosts inside high-entropy blobs", () => {
+    const blob = "dGhpcyBpcyBub3QgYSBob3N0+api.stripe.comQk9NYiZx/8Kq2LwPzR7vNfJ";
+    expect(detectFallbackEndpoints(`payload = "${blob}"`, "data.cfg", matchVendor)).toEqual([]);
+  });
+
+  it("reports a URL once per line", () => {
+    const source = 'curl https://api.stripe.com/v1/charges https://api.stripe.com/v1/charges';
+    expect(detectFallbackEndpoints(source, "Makefile", matchVendor)).toHaveLength(1);
+  });
+});
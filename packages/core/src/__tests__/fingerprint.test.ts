@@ -0,0 +1,117 @@
+import { describe, it, expect } from "vitest";
+import { fingerprintFindings, disambiguateFingerprints, enclosingSymbol, diffFindings } from "../fingerprint.js";
+import { buildTDM } from "../build-tdm.js";
+import type { DependencyEntry } from "../plugin.js";
+
+const BILLING = `import stripe
+
+class Billing:
+    def charge(self, amount):
+        return stripe.PaymentIntent.create(amount=amount)
+
+    def refund(self, charge_id):
+        return stripe.Refund.create(charge=charge_id)
+`;
+
+function stripeCalls(file: string, source: string): DependencyEntry[] {
+  const lines = source.split("\n");
+  const entries: DependencyEntry[] = [
+    {
+      kind: "sdk",
+      provider: "stripe",
+      sdk_package: "stripe",
+      locations: lines.flatMap((text, i) => (text.includes("stripe.") ? [{ file, line: i + 1 }] : [])),
+      usage_count: 2,
+      confidence: "high",
+    },
+  ];
+  fingerprintFindings(entries, source, file);
+  return entries;
+}
+
+const fingerprints = (entries: DependencyEntry[]) => entries.flatMap((e) => e.locations.map((l) => l.fingerprint));
+
+describe("enclosingSymbol", () => {
+  it("names the enclosing class and function", () => {
+    const lines = BILLING.split("\n");
+    expect(enclosingSymbol(lines, 5)).toBe("Billing.charge");
+    expect(enclosingSymbol(lines, 8)).toBe("Billing.refund");
+    expect(enclosingSymbol(lines, 1)).toBe("");
+  });
+
+  it("handles brace languages", () => {
+    const lines = ["func (s *Server) Charge(ctx context.Context) error {", "\t_, err := charge.New(params)", "\treturn err", "}"];
+    expect(enclosingSymbol(lines, 2)).toBe("Charge");
+  });
+});
+
+describe("fingerprintFindings", () => {
+  it("survives file moves and unrelated line shifts", () => {
+    const before = fingerprints(stripeCalls("payments/billing.py", BILLING));
+    const moved = fingerprints(
+      stripeCalls("services/billing/billing.py", `"""Billing."""\nimport logging\n\n${BILLING.replace("import stripe", "import stripe\nlog = logging.getLogger()")}`),
+    );
+    expect(before).toHaveLength(2);
+    expect(moved).toEqual(before);
+  });
+
+  it("changes when the call itself or its function changes", () => {
+    const before = fingerprints(stripeCalls("billing.py", BILLING));
+    const edited = fingerprints(stripeCalls("billing.py", BILLING.replace("amount=amount)", "amount=amount, currency=\"usd\")")));
+    const renamed = fingerprints(stripeCalls("billing.py", BILLING.replace("def refund", "def issue_refund")));
+    expect(edited[0]).not.toBe(before[0]);
+    expect(edited[1]).toBe(before[1]);
+    expect(renamed[0]).toBe(before[0]);
+    expect(renamed[1]).not.toBe(before[1]);
+  });
+
+  it("tells identical lines in one function apart and ignores package versions", () => {
+    const source = `def sync():\n    client.put_object(Bucket=b)\n    client.put_object(Bucket=b)\n`;
+    const [first, second] = fingerprints(stripeCalls("sync.py", source.replace(/client/g, "stripe.client")));
+    expect(second).toBe(`${first}-2`);
+
+    const pkg = (version: string): DependencyEntry[] => [
+      { kind: "package", name: "stripe", ecosystem: "pypi", current_version: version, manifest_file: "requirements.txt", locations: [{ file: "requirements.txt", line: 3, context: `stripe==${version}` }], usage_count: 1, confidence: "high" },
+    ];
+    const old = pkg("7.0.0");
+    const bumped = pkg("8.1.0");
+    fingerprintFindings(old);
+    fingerprintFindings(bumped);
+    expect(fingerprints(bumped)).toEqual(fingerprints(old));
+  });
+});
+
+describe("disambiguateFingerprints", () => {
+  it("tells the same call in same-named files in different directories apart", () => {
+    const entries = [...stripeCalls("web/billing.py", BILLING), ...stripeCalls("api/billing.py", BILLING)];
+    const [apiCharge, apiRefund] = fingerprints(entries.slice(1));
+    expect(fingerprints(entries.slice(0, 1))).toEqual([apiCharge, apiRefund]);
+
+    disambiguateFingerprints(entries);
+    const all = fingerprints(entries);
+    expect(new Set(all).size).toBe(4);
+    expect(fingerprints(entries.slice(1))).toEqual([apiCharge, apiRefund]);
+  });
+
+  it("leaves fingerprints alone when only one file has them", () => {
+    const entries = stripeCalls("payments/billing.py", BILLING);
+    const before = fingerprints(entries);
+    disambiguateFingerprints(entries);
+    expect(fingerprints(entries)).toEqual(before);
+  });
+});
+
+describe("diffFindings", () => {
+  it("lists findings new and resolved since the baseline", () => {
+    const context = { root: "/repo", plugins: [], duration: 1 };
+    const baseline = buildTDM(stripeCalls("billing.py", BILLING), context);
+    const current = buildTDM(
+      stripeCalls("billing.py", `${BILLING.replace(/\n    def refund[\s\S]*$/, "\n")}\n    def payout(self):\n        return stripe.Payout.create()\n`),
+      context,
+    );
+    const { added, removed } = diffFindings(current, baseline);
+    expect(added.map((f) => [f.finding, f.line])).toEqual([["sdk:stripe/stripe", 9]]);
+    expect(removed.map((f) => [f.finding, f.line])).toEqual([["sdk:stripe/stripe", 8]]);
+    expect(added[0]!.fingerprint).not.toBe(removed[0]!.fingerprint);
+  });
+});
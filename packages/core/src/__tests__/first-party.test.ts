@@ -0,0 +1,68 @@
+import { describe, it, expect } from "vitest";
+import type { DependencyEntry } from "../plugin.js";
+import { classifyFirstParty, extractHost, matchesDomain } from "../first-party.js";
+
+describe("extractHost", () => {
+  it("extracts hosts from URLs and connection strings", () => {
+    expect(extractHost("https://api.example.com/health")).toBe("api.example.com");
+    expect(extractHost("postgresql://<redacted>@db.internal:5432/mydb")).toBe("db.internal");
+    expect(extractHost("localhost:6379")).toBe("localhost");
+  });
+
+  it("returns null for env var names and templates", () => {
+    expect(extractHost("DATABASE_URL")).toBeNull();
+    expect(extractHost("${API_BASE}/v1/users")).toBeNull();
+  });
+});
+
+describe("matchesDomain", () => {
+  it("supports exact, wildcard, and suffix patterns", () => {
+    expect(matchesDomain("api.mycompany.com", "api.mycompany.com")).toBe(true);
+    expect(matchesDomain("db.internal", "*.internal")).toBe(true);
+    expect(matchesDomain("internal", "*.internal")).toBe(false);
+    expect(matchesDomain("eu.api.mycompany.com", "mycompany.com")).toBe(true);
+    expect(matchesDomain("notmycompany.com", "mycompany.com")).toBe(false);
+  });
+
+  it("matches one label per interior wildcard", () => {
+    expect(matchesDomain("b-1.orders.abc123.c2.kafka.us-east-1.amazonaws.com", "kafka.*.amazonaws.com")).toBe(true);
+    expect(matchesDomain("kafka.eu-west-1.amazonaws.com", "kafka.*.amazonaws.com")).toBe(true);
+    expect(matchesDomain("kafka.eu-west-1.amazonaws.com", "*.kafka.*.amazonaws.com")).toBe(false);
+    expect(matchesDomain("kafka.a.b.amazonaws.com", "kafka.*.amazonaws.com")).toBe(false);
+    expect(matchesDomain("kafka-us-east-1.amazonaws.com", "kafka.*.amazonaws.com")).toBe(false);
+  });
+});
+
+describe("classifyFirstParty", () => {
+  const entries = (): DependencyEntry[] => [
+    {
+      kind: "api",
+      url: "https://api.stripe.com/v1/charges",
+      locations: [{ file: "main.go", line: 1 }],
+      usage_count: 1,
+      confidence: "high",
+    },
+    {
+      kind: "infrastructure",
+      type: "postgresql",
+      connection_ref: "postgresql://<redacted>@db.internal:5432/mydb",
+      locations: [{ file: "server/handler.go", line: 21 }],
+      confidence: "high",
+    },
+  ];
+
+  it("flags internal hosts in separate mode", () => {
+    const result = classifyFirstParty(entries(), ["*.internal"]);
+    expect(result).toHaveLength(2);
+    const infra = result.find((e) => e.kind === "infrastructure");
+    expect(infra && infra.kind === "infrastructure" && infra.first_party).toBe(true);
+    const api = result.find((e) => e.kind === "api");
+    expect(api && api.kind === "api" && api.first_party).toBeUndefined();
+  });
+
+  it("drops internal hosts in hide mode", () => {
+    const result = classifyFirstParty(entries(), ["*.internal"], "hide");
+    expect(result).toHaveLength(1);
+    expect(result[0]!.kind).toBe("api");
+  });
+});
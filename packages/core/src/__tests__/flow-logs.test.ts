@@ -0,0 +1,108 @@
+import { describe, it, expect } from "vitest";
+import { createFlowLogParser, detectFlowLogFormat } from "../flow-logs.js";
+
+const now = () => "2026-10-14T12:00:00.000Z";
+
+describe("createFlowLogParser — vpc", () => {
+  it("parses default-format records and drops rejected, reply, and ICMP flows", () => {
+    const parse = createFlowLogParser("vpc", { now });
+    expect(
+      parse("2 123456789012 eni-0a1b2c3d 10.0.1.5 52.1.2.3 49152 443 6 20 4249 1791972000 1791972060 ACCEPT OK"),
+    ).toEqual({ source: "flow", ip: "52.1.2.3", port: 443, client: "10.0.1.5", timestamp: "2026-10-14T10:00:00.000Z" });
+    expect(parse("2 123456789012 eni-0a1b2c3d 10.0.1.5 52.1.2.3 49152 443 6 1 60 1791972000 1791972060 REJECT OK")).toBeNull();
+    expect(parse("2 123456789012 eni-0a1b2c3d 52.1.2.3 10.0.1.5 443 49152 6 20 4249 1791972000 1791972060 ACCEPT OK")).toBeNull();
+    expect(parse("2 123456789012 eni-0a1b2c3d 10.0.1.5 52.1.2.3 0 0 1 1 84 1791972000 1791972060 ACCEPT OK")).toBeNull();
+    expect(parse("2 123456789012 eni-0a1b2c3d - - - - - - - 1791972000 1791972060 - NODATA")).toBeNull();
+  });
+
+  it("follows a custom-format header line", () => {
+    const parse = createFlowLogParser("vpc", { now });
+    expect(parse("instance-id srcaddr dstaddr pkt-dstaddr dstport protocol start action flow-direction")).toBeNull();
+    expect(parse("i-0abc 10.0.1.5 10.0.9.9 104.18.1.1 443 6 1791972000 ACCEPT egress")).toEqual({
+      source: "flow", ip: "104.18.1.1", port: 443, client: "i-0abc", timestamp: "2026-10-14T10:00:00.000Z",
+    });
+    expect(parse("i-0abc 10.0.1.5 10.0.9.9 104.18.1.1 443 6 1791972000 ACCEPT ingress")).toBeNull();
+  });
+});
+
+describe("createFlowLogParser — gcp", () => {
+  it("parses Cloud Logging exports and names GKE pods", () => {
+    const parse = createFlowLogParser("gcp", { now });
+    const line = JSON.stringify({
+      jsonPayload: {
+        connection: { src_ip: "10.8.0.4", dest_ip: "142.250.1.1", dest_port: 443, protocol: 6 },
+        reporter: "SRC",
+        src_gke_details: { pod: { pod_name: "checkout-7d9f" } },
+        start_time: "2026-10-14T09:00:00.5Z",
+      },
+    });
+    expect(parse(line)).toEqual({
+      source: "flow", ip: "142.250.1.1", port: 443, client: "checkout-7d9f", timestamp: "2026-10-14T09:00:00.500Z",
+    });
+    expect(parse(line.replace('"SRC"', '"DEST"'))).toBeNull();
+  });
+});
+
+describe("createFlowLogParser — suricata", () => {
+  it("uses SNI, counts each flow once, and unwraps AWS Network Firewall records", () => {
+    const parse = createFlowLogParser("suricata", { now });
+    const tls = JSON.stringify({
+      firewall_name: "egress",
+      event: {
+        timestamp: "2026-10-14T10:00:00.000000+0000",
+        flow_id: 42, event_type: "tls",
+        src_ip: "10.0.1.5", dest_ip: "52.1.2.3", dest_port: 443,
+        tls: { sni: "api.stripe.com" },
+      },
+    });
+    const flow = JSON.stringify({ flow_id: 42, event_type: "netflow", src_ip: "10.0.1.5", dest_ip: "52.1.2.3", dest_port: 443 });
+    expect(parse(tls)).toEqual({
+      source: "firewall", host: "api.stripe.com", ip: "52.1.2.3", port: 443, client: "10.0.1.5", timestamp: "2026-10-14T10:00:00.000Z",
+    });
+    expect(parse(flow)).toBeNull();
+    expect(parse(JSON.stringify({ event_type: "alert", dest_ip: "52.9.9.9", alert: { action: "blocked" } }))).toBeNull();
+  });
+});
+
+describe("createFlowLogParser — iptables", () => {
+  it("parses LOG target lines and keeps only opening SYNs", () => {
+    const parse = createFlowLogParser("iptables", { now });
+    const syn = "Oct 14 10:00:00 node1 kernel: [1234.5] EGRESS IN= OUT=eth0 SRC=10.0.0.5 DST=104.18.1.1 LEN=60 TOS=0x00 PREC=0x00 TTL=64 ID=1 DF PROTO=TCP SPT=51234 DPT=443 WINDOW=64240 RES=0x00 SYN URGP=0";
+    expect(parse(syn)).toEqual({
+      source: "firewall", ip: "104.18.1.1", port: 443, client: "10.0.0.5", timestamp: "2026-10-14T10:00:00.000Z",
+    });
+    expect(parse(syn.replace("SYN URGP", "ACK PSH URGP"))).toBeNull();
+    expect(parse(syn.replace("DST=104.18.1.1", "DST=10.0.0.9"))).toBeNull();
+  });
+});
+
+describe("createFlowLogParser — envoy", () => {
+  it("parses default and Istio access log formats", () => {
+    const parse = createFlowLogParser("envoy", { now });
+    const http = `[2026-10-14T10:00:00.000Z] "POST /v1/charges/ch_3NkLmN2pQrStUvWxYz HTTP/1.1" 200 - 120 512 80 78 "-" "node" "req-1" "api.stripe.com" "52.1.2.3:443"`;
+    expect(parse(http)).toEqual({
+      source: "gateway", host: "api.stripe.com", ip: "52.1.2.3", port: 443, method: "POST",
+      path: "/v1/charges/{id}", user_agent: "node", timestamp: "2026-10-14T10:00:00.000Z",
+    });
+
+    const passthrough = `[2026-10-14T10:00:01.000Z] "- - -" 0 - - - "-" 1200 5400 300 - "-" "-" "-" "-" "104.18.1.1:443" outbound|443||api.openai.com 10.8.0.9:40000 10.8.0.9:443 10.8.1.4:51200 api.openai.com -`;
+    expect(parse(passthrough)).toEqual({
+      source: "gateway", host: "api.openai.com", ip: "104.18.1.1", port: 443, client: "10.8.1.4", timestamp: "2026-10-14T10:00:01.000Z",
+    });
+
+    const internal = `[2026-10-14T10:00:00.000Z] "GET / HTTP/1.1" 200 - 0 1 1 1 "-" "-" "-" "payments.default.svc.cluster.local" "10.8.2.2:8080"`;
+    expect(parse(internal)).toBeNull();
+  });
+});
+
+describe("detectFlowLogFormat", () => {
+  it("recognizes each format from a sample line", () => {
+    expect(detectFlowLogFormat("version account-id interface-id srcaddr dstaddr srcport dstport protocol packets bytes start end action log-status")).toBe("vpc");
+    expect(detectFlowLogFormat("2 123456789012 eni-0a1b2c3d 10.0.1.5 52.1.2.3 49152 443 6 20 4249 1 2 ACCEPT OK")).toBe("vpc");
+    expect(detectFlowLogFormat(`{"jsonPayload":{"connection":{"dest_ip":"1.2.3.4"}}}`)).toBe("gcp");
+    expect(detectFlowLogFormat(`{"event":{"event_type":"netflow"}}`)).toBe("suricata");
+    expect(detectFlowLogFormat("kernel: IN= OUT=eth0 SRC=10.0.0.5 DST=1.2.3.4 PROTO=TCP")).toBe("iptables");
+    expect(detectFlowLogFormat(`[2026-10-14T10:00:00.000Z] "GET / HTTP/1.1" 200`)).toBe("envoy");
+    expect(detectFlowLogFormat("hello")).toBeNull();
+  });
+});
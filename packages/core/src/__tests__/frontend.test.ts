@@ -0,0 +1,54 @@
+import { describe, it, expect } from "vitest";
+import { readFile } from "node:fs/promises";
+import { resolve } from "node:path";
+import { detectFrontendAssets, isFrontendAsset } from "../frontend.js";
+import { applyCatalogCategories } from "../categories.js";
+import { loadSDKRegistry } from "../registry.js";
+
+const fixturesRoot = resolve(__dirname, "../../../../fixtures/frontend");
+const registriesDir = resolve(__dirname, "../../../../registries");
+
+async function assets(file: string): Promise<Array<[string, number, string]>> {
+  const source = await readFile(resolve(fixturesRoot, file), "utf-8");
+  return detectFrontendAssets(source, file).flatMap((e) =>
+    e.kind === "api" ? [[e.url, e.locations[0]!.line, e.locations[0]!.usage!] as [string, number, string]] : [],
+  );
+}
+
+describe("detectFrontendAssets", () => {
+  it("finds external scripts, stylesheets, iframes, and loader snippets in HTML", async () => {
+    expect(isFrontendAsset("templates/base.html")).toBe(true);
+    expect(isFrontendAsset("src/App.vue")).toBe(true);
+    expect(isFrontendAsset("src/app.ts")).toBe(false);
+    expect(await assets("index.html")).toEqual([
+      ["https://fonts.gstatic.com", 5, "stylesheet"],
+      ["https://fonts.googleapis.com/css2", 6, "stylesheet"],
+      ["https://www.googletagmanager.com/gtag/js", 8, "script_tag"],
+      ["https://js.stripe.com/v3/", 9, "script_tag"],
+      ["https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ", 14, "iframe"],
+      ["https://widget.intercom.io/widget/", 18, "script_tag"],
+    ]);
+  });
+
+  it("finds imported stylesheets and web fonts in CSS", async () => {
+    expect(await assets("app.css")).toEqual([
+      ["https://fonts.googleapis.com/css2", 1, "stylesheet"],
+      ["https://cdn.brand-assets.example/fonts/brand.woff2", 5, "font"],
+    ]);
+  });
+
+  it("attributes browser-side vendors through the catalog", async () => {
+    const source = await readFile(resolve(fixturesRoot, "index.html"), "utf-8");
+    const entries = detectFrontendAssets(source, "index.html");
+    applyCatalogCategories(entries, await loadSDKRegistry(registriesDir));
+    const categories = entries.map((e) => (e.kind === "api" ? [e.url, e.category ?? null] : []));
+    expect(categories).toEqual([
+      ["https://fonts.gstatic.com", "cdn"],
+      ["https://fonts.googleapis.com/css2", "cdn"],
+      ["https://www.googletagmanager.com/gtag/js", "analytics"],
+      ["https://js.stripe.com/v3/", "payments"],
+      ["https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ", null],
+      ["https://widget.intercom.io/widget/", "support"],
+    ]);
+  });
+});
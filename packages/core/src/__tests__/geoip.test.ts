@@ -0,0 +1,46 @@
+import { describe, it, expect } from "vitest";
+import { readFile } from "node:fs/promises";
+import { resolve } from "node:path";
+import { detectGeoipDownloads, isGeoipConfig } from "../geoip.js";
+
+const fixturesRoot = resolve(__dirname, "../../../../fixtures/geoip");
+
+async function downloads(file: string): Promise<Array<[string, number]>> {
+  const source = await readFile(resolve(fixturesRoot, file), "utf-8");
+  return detectGeoipDownloads(source, file).flatMap((e) =>
+    e.kind === "api" ? [[e.url, e.locations[0]!.line] as [string, number]] : [],
+  );
+}
+
+describe("detectGeoipDownloads", () => {
+  it("finds MaxMind and IPinfo database downloads in a Dockerfile", async () => {
+    expect(await downloads("Dockerfile")).toEqual([
+      ["https://download.maxmind.com/geoip/databases/GeoLite2-City/download", 9],
+      ["https://download.maxmind.com/geoip/databases/GeoLite2-ASN/download", 10],
+      ["https://ipinfo.io/data/free/country_asn.mmdb", 11],
+    ]);
+  });
+
+  it("reports each edition in GeoIP.conf and geoipupdate runs in scripts", async () => {
+    expect(isGeoipConfig("deploy/GeoIP.conf")).toBe(true);
+    expect(await downloads("GeoIP.conf")).toEqual([
+      ["https://download.maxmind.com/geoip/databases/GeoIP2-City/download", 4],
+      ["https://download.maxmind.com/geoip/databases/GeoIP2-Anonymous-IP/download", 4],
+    ]);
+    expect(await downloads("refresh.sh")).toEqual([["https://updates.maxmind.com", 3]]);
+  });
+
+  it("redacts license keys and tokens from the reported context", () => {
+    const [entry] = detectGeoipDownloads(
+      'RUN wget "https://download.maxmind.com/app/geoip_download?edition_id=GeoLite2-Country&license_key=abc123XYZ&suffix=tar.gz"',
+      "Dockerfile",
+    );
+    expect(entry).toMatchObject({
+      kind: "api",
+      method: "GET",
+      locations: [{ line: 1, usage: "geoip_database_download" }],
+    });
+    expect(entry!.locations[0]!.context).toContain("license_key=[REDACTED]&suffix");
+    expect(entry!.locations[0]!.context).not.toContain("abc123XYZ");
+  });
+});
@@ -0,0 +1,134 @@
+import { describe, it, expect } from "vitest";
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "../registry.js";
+import { groupByService } from "../concentration.js";
+import { computeImpact, resolveVendorSlug } from "../impact.js";
+
+function tdm(repository: string, parts: Partial<Pick<TDM, "sdks" | "apis" | "webhooks">>): TDM {
+  return {
+    version: "1.0",
+    metadata: {
+      scan_timestamp: "2026-10-14T10:00:00.000Z",
+      scanner_version: "0.1.0",
+      repository,
+      languages_detected: [],
+      total_dependencies_found: 0,
+      scan_duration_ms: 0,
+    },
+    packages: [],
+    apis: parts.apis ?? [],
+    sdks: parts.sdks ?? [],
+    infrastructure: [],
+    webhooks: parts.webhooks ?? [],
+  };
+}
+
+const registry: SDKRegistryEntry[] = [
+  { provider: "twilio", display_name: "Twilio", category: "communication", patterns: {}, domains: ["twilio.com"] },
+  { provider: "sendgrid", display_name: "SendGrid", category: "communication", patterns: {} },
+  { provider: "stripe", display_name: "Stripe", category: "payments", patterns: {} },
+];
+
+const inventory = groupByService(
+  [
+    tdm("notifications", {
+      sdks: [
+        {
+          provider: "twilio",
+          sdk_package: "twilio",
+          intents: ["message_send"],
+          locations: [
+            { file: "sms.py", line: 4, usage: "import" },
+            { file: "sms.py", line: 12, usage: "method_call:client.messages.create" },
+            { file: "voice.py", line: 8, usage: "method_call:client.calls.create" },
+          ],
+          usage_count: 3,
+          confidence: "high",
+        },
+      ],
+      webhooks: [
+        {
+          direction: "inbound_callback",
+          target_url: "/twilio/status",
+          provider: "twilio",
+          locations: [{ file: "routes.py", line: 30 }],
+          confidence: "medium",
+        },
+      ],
+    }),
+    tdm("auth", {
+      apis: [
+        {
+          url: "https://verify.twilio.com/v2/Services",
+          method: "POST",
+          locations: [{ file: "otp.ts", line: 7 }],
+          usage_count: 1,
+          confidence: "medium",
+        },
+      ],
+    }),
+    // Runtime report for the same service: endpoint only, no call site
+    tdm("auth", {
+      apis: [
+        {
+          url: "https://api.twilio.com",
+          locations: [],
+          usage_count: 0,
+          confidence: "high",
+          runtime: { source: "agent", count: 40, first_seen: "2026-10-01T00:00:00Z", last_seen: "2026-10-14T00:00:00Z" },
+        },
+      ],
+    }),
+    tdm("marketing", {
+      sdks: [{ provider: "sendgrid", sdk_package: "@sendgrid/mail", locations: [{ file: "mail.ts", line: 1 }], usage_count: 1, confidence: "high" }],
+    }),
+    tdm("checkout", {
+      sdks: [{ provider: "stripe", sdk_package: "stripe", locations: [{ file: "pay.ts", line: 1 }], usage_count: 1, confidence: "high" }],
+    }),
+  ],
+  (i) => `file-${i}`,
+);
+
+describe("computeImpact", () => {
+  it("lists repositories, files, and call sites depending on the vendor", () => {
+    const report = computeImpact(inventory, "twilio", registry);
+
+    expect(report).toMatchObject({
+      vendor: "twilio",
+      display_name: "Twilio",
+      category: "communication",
+      total_repositories: 2,
+      total_files: 4,
+      total_call_sites: 5,
+      kinds: { sdk: 3, webhook: 1, api: 1 },
+      intents: [{ intent: "message_send", call_sites: 3 }],
+      alternatives: [{ vendor: "sendgrid", repositories: 1 }],
+      scope: "small",
+    });
+    expect(report.repositories.map((r) => [r.repository, r.call_sites, r.files])).toEqual([
+      ["notifications", 4, ["routes.py", "sms.py", "voice.py"]],
+      ["auth", 1, ["otp.ts"]],
+    ]);
+    expect(report.repositories[0]!.sdk_packages).toEqual(["twilio"]);
+    expect(report.repositories[1]!.endpoints).toEqual(["https://api.twilio.com", "https://verify.twilio.com/v2/Services"]);
+    expect(report.repositories[0]!.sites[2]).toEqual({
+      file: "sms.py",
+      line: 12,
+      kind: "sdk",
+      usage: "method_call:client.messages.create",
+    });
+  });
+
+  it("reports nothing for a vendor no repository uses", () => {
+    const report = computeImpact(inventory, "adyen", registry);
+    expect(report).toMatchObject({ total_repositories: 0, total_call_sites: 0, repositories: [], scope: "small" });
+  });
+});
+
+describe("resolveVendorSlug", () => {
+  it("accepts slugs and display names", () => {
+    expect(resolveVendorSlug("SendGrid", registry)).toBe("sendgrid");
+    expect(resolveVendorSlug("twilio", registry)).toBe("twilio");
+    expect(resolveVendorSlug("Acme-Internal", registry)).toBe("acme-internal");
+  });
+});
@@ -0,0 +1,72 @@
+import { describe, it, expect } from "vitest";
+import type { DependencyEntry } from "../plugin.js";
+import { classifyUsageIntents } from "../intent.js";
+
+const categories: Record<string, string> = {
+  "aws-s3": "storage",
+  stripe: "payments",
+  openai: "ai",
+  anthropic: "ai",
+  twilio: "messaging",
+  sentry: "error-tracking",
+};
+
+function sdk(provider: string, extra: Partial<DependencyEntry & { kind: "sdk" }> = {}): DependencyEntry & { kind: "sdk" } {
+  return {
+    kind: "sdk",
+    provider,
+    sdk_package: provider,
+    locations: [{ file: "app.py", line: 1 }],
+    usage_count: 1,
+    confidence: "high",
+    ...extra,
+  };
+}
+
+function intents(source: string, ...entries: Array<DependencyEntry & { kind: "sdk" }>): Array<string[] | undefined> {
+  classifyUsageIntents(entries, source, (p) => categories[p]);
+  return entries.map((e) => e.intents);
+}
+
+describe("classifyUsageIntents", () => {
+  it("tells read-only object storage use from writes and deletes", () => {
+    expect(intents('obj = s3.get_object(Bucket="reports", Key=key)\n', sdk("aws-s3"))).toEqual([["data_read"]]);
+    expect(
+      intents(
+        [
+          "out, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: bucket})",
+          "_, err = client.PutObject(ctx, &s3.PutObjectInput{Bucket: bucket, Body: body})",
+          "// client.DeleteObject(ctx, input)",
+        ].join("\n"),
+        sdk("aws-s3"),
+      ),
+    ).toEqual([["data_read", "data_write"]]);
+  });
+
+  it("uses the AWS service when the entry is the umbrella SDK", () => {
+    expect(intents("s3.upload_file(path, bucket, key)\n", sdk("aws", { services_used: ["s3"] }))).toEqual([["data_write"]]);
+  });
+
+  it("reads the same method name by the vendor's category", () => {
+    const source = "client.messages.create(model=MODEL, messages=history)\n";
+    expect(intents(source, sdk("anthropic"), sdk("twilio"))).toEqual([["inference"], ["message_send"]]);
+  });
+
+  it("classifies payments, inference, embeddings, and telemetry", () => {
+    const source = [
+      "intent = stripe.PaymentIntent.create(amount=total, currency='usd')",
+      "stripe.Refund.create(payment_intent=intent.id)",
+      "vectors = openai.embeddings.create(model='text-embedding-3-small', input=docs)",
+      "sentry_sdk.capture_exception(err)",
+    ].join("\n");
+    expect(intents(source, sdk("stripe"), sdk("openai"), sdk("sentry"))).toEqual([
+      ["payment", "refund"],
+      ["embedding"],
+      ["telemetry_export"],
+    ]);
+  });
+
+  it("leaves SDKs with no recognised calls unclassified", () => {
+    expect(intents("s3 = boto3.client('s3')\n", sdk("aws-s3"), sdk("acme-internal"))).toEqual([undefined, undefined]);
+  });
+});
@@ -0,0 +1,51 @@
+import { describe, it, expect } from "vitest";
+import type { SDKRegistryEntry } from "../registry.js";
+import { parseIp, parseCidr, createIpRangeMatcher } from "../ip-ranges.js";
+
+const entry = (provider: string, ip_ranges: string[]): SDKRegistryEntry => ({
+  provider,
+  display_name: provider,
+  patterns: {},
+  ip_ranges,
+});
+
+describe("parseIp / parseCidr", () => {
+  it("parses IPv4 and IPv6 addresses", () => {
+    expect(parseIp("10.0.0.1")).toEqual({ version: 4, value: 0x0a000001n });
+    expect(parseIp("[2001:db8::1]")).toEqual({ version: 6, value: 0x20010db8000000000000000000000001n });
+    expect(parseIp("::ffff:10.0.0.1")?.value).toBe(0xffff0a000001n);
+    expect(parseIp("256.0.0.1")).toBeNull();
+    expect(parseIp("1::2::3")).toBeNull();
+    expect(parseIp("api.stripe.com")).toBeNull();
+  });
+
+  it("masks host bits and rejects bad prefix lengths", () => {
+    expect(parseCidr("192.0.2.77/24")).toEqual({ version: 4, value: 0xc0000200n, bits: 24 });
+    expect(parseCidr("198.51.100.7")?.bits).toBe(32);
+    expect(parseCidr("2001:db8::/32")?.bits).toBe(32);
+    expect(parseCidr("192.0.2.0/33")).toBeNull();
+    expect(parseCidr("192.0.2.0/24/1")).toBeNull();
+  });
+});
+
+describe("createIpRangeMatcher", () => {
+  const match = createIpRangeMatcher([
+    entry("aws", ["52.0.0.0/10", "2600:1f00::/24"]),
+    entry("stripe", ["52.1.2.3/32"]),
+    entry("cloudflare", ["104.16.0.0/13"]),
+    entry("broken", ["not-a-range"]),
+  ]);
+
+  it("prefers the most specific prefix", () => {
+    expect(match("52.1.2.3")).toBe("stripe");
+    expect(match("52.1.2.4")).toBe("aws");
+    expect(match("104.18.1.1")).toBe("cloudflare");
+    expect(match("2600:1f18::10")).toBe("aws");
+  });
+
+  it("matches IPv4-mapped addresses and returns null for misses", () => {
+    expect(match("::ffff:52.1.2.3")).toBe("stripe");
+    expect(match("8.8.8.8")).toBeNull();
+    expect(match("garbage")).toBeNull();
+  });
+});
@@ -0,0 +1,63 @@
+import { describe, it, expect } from "vitest";
+import { mkdtempSync, mkdirSync, writeFileSync } from "node:fs";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { podUidFromCgroup, workloadOf, PodDirectory } from "../k8s.js";
+import type { KubePod } from "../k8s.js";
+
+const UID = "8a3c1f2e-4b5d-4e6f-9a0b-1c2d3e4f5a6b";
+
+function pod(uid: string, name: string, namespace: string, extra: Partial<KubePod["metadata"]> = {}): KubePod {
+  return { metadata: { uid, name, namespace, ...extra } };
+}
+
+describe("podUidFromCgroup", () => {
+  it("reads the pod UID from cgroupfs and systemd layouts", () => {
+    expect(podUidFromCgroup(`12:pids:/kubepods/burstable/pod${UID}/0123abcd\n`)).toBe(UID);
+    expect(
+      podUidFromCgroup(
+        `0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod${UID.replace(/-/g, "_")}.slice/cri-containerd-0123.scope\n`,
+      ),
+    ).toBe(UID);
+    expect(podUidFromCgroup("0::/system.slice/sshd.service\n")).toBeNull();
+  });
+});
+
+describe("workloadOf", () => {
+  it("names the owning controller, stripping Deployment ReplicaSet hashes", () => {
+    expect(
+      workloadOf(pod(UID, "checkout-7d9f8-x2k4q", "shop", {
+        labels: { "pod-template-hash": "7d9f8" },
+        ownerReferences: [{ kind: "ReplicaSet", name: "checkout-7d9f8", controller: true }],
+      })),
+    ).toBe("checkout");
+    expect(
+      workloadOf(pod(UID, "db-0", "shop", { ownerReferences: [{ kind: "StatefulSet", name: "db", controller: true }] })),
+    ).toBe("db");
+    expect(workloadOf(pod(UID, "debug", "default"))).toBe("debug");
+  });
+});
+
+describe("PodDirectory", () => {
+  it("labels pids by workload, resolving provisional labels after a refresh", async () => {
+    const proc = mkdtempSync(join(tmpdir(), "tw-proc-"));
+    mkdirSync(join(proc, "100"));
+    writeFileSync(join(proc, "100", "cgroup"), `0::/kubepods/pod${UID}/abc\n`);
+    mkdirSync(join(proc, "200"));
+    writeFileSync(join(proc, "200", "cgroup"), "0::/system.slice/kubelet.service\n");
+
+    let pods: KubePod[] = [];
+    const dir = new PodDirectory({ list: async () => pods, procRoot: proc, refreshIntervalMs: 0 });
+    await dir.refresh();
+
+    const obs = [{ client: dir.clientFor(100) ?? "" }];
+    expect(obs[0]!.client).toBe(`uid:${UID}`);
+    expect(dir.clientFor(200)).toBeUndefined();
+    expect(dir.clientFor(300)).toBeUndefined();
+
+    pods = [pod(UID, "api-0", "shop", { ownerReferences: [{ kind: "StatefulSet", name: "api" }] })];
+    await dir.resolve(obs);
+    expect(obs[0]!.client).toBe("shop/api");
+    expect(dir.clientFor(100)).toBe("shop/api");
+  });
+});
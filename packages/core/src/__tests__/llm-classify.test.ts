@@ -0,0 +1,112 @@
+import { describe, it, expect } from "vitest";
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "../registry.js";
+import {
+  collectUnmatched,
+  parseSuggestions,
+  applySuggestions,
+  vendorSlug,
+  isAllowedLLMEndpoint,
+} from "../llm-classify.js";
+
+function makeTdm(): TDM {
+  return {
+    version: "1.0",
+    metadata: {
+      scan_timestamp: "2026-01-01T00:00:00.000Z",
+      scanner_version: "0.1.0",
+      languages_detected: ["go"],
+      total_dependencies_found: 5,
+      scan_duration_ms: 10,
+    },
+    packages: [
+      { name: "github.com/stripe/stripe-go/v76", ecosystem: "go", current_version: "v76.0.0", manifest_file: "go.mod", locations: [{ file: "go.mod", line: 3 }], usage_count: 1, confidence: "high" },
+      { name: "github.com/acmepay/acme-go", ecosystem: "go", current_version: "v1.2.0", manifest_file: "go.mod", locations: [{ file: "go.mod", line: 4, context: "secret source" }], usage_count: 1, confidence: "high" },
+    ],
+    apis: [
+      { url: "https://api.stripe.com/v1/charges", provider: "stripe", locations: [{ file: "main.go", line: 10 }], usage_count: 1, confidence: "high" },
+      { url: "https://ingest.acmelogs.io/v2/events?token=abc", locations: [{ file: "main.go", line: 20 }], usage_count: 1, confidence: "medium" },
+      { url: "http://localhost:8080/health", locations: [{ file: "main.go", line: 30 }], usage_count: 1, confidence: "medium" },
+    ],
+    sdks: [],
+    infrastructure: [],
+    webhooks: [],
+  };
+}
+
+const registry: SDKRegistryEntry[] = [
+  { provider: "stripe", display_name: "Stripe", patterns: { go: [{ package: "github.com/stripe/stripe-go/v76" }] } },
+];
+
+describe("collectUnmatched", () => {
+  it("sends only unmatched package names and bare hostnames", () => {
+    const items = collectUnmatched(makeTdm(), registry);
+    expect(items).toEqual([
+      { id: "package:go:github.com/acmepay/acme-go", kind: "package", value: "github.com/acmepay/acme-go", ecosystem: "go" },
+      { id: "endpoint:ingest.acmelogs.io", kind: "endpoint", value: "ingest.acmelogs.io" },
+    ]);
+    expect(JSON.stringify(items)).not.toContain("token=abc");
+    expect(JSON.stringify(items)).not.toContain("secret source");
+  });
+});
+
+describe("parseSuggestions", () => {
+  it("keeps only ids that were asked about", () => {
+    const text = JSON.stringify({
+      classifications: [
+        { id: "endpoint:ingest.acmelogs.io", vendor: "Acme Logs", category: "observability", reasoning: "Log ingestion host." },
+        { id: "endpoint:evil.example", vendor: "evil" },
+      ],
+    });
+    expect(parseSuggestions(text, new Set(["endpoint:ingest.acmelogs.io"]))).toEqual([
+      { id: "endpoint:ingest.acmelogs.io", vendor: "acme-logs", category: "observability", reasoning: "Log ingestion host." },
+    ]);
+  });
+
+  it("returns nothing for malformed responses", () => {
+    expect(parseSuggestions("not json", new Set())).toEqual([]);
+    expect(parseSuggestions(`{"classifications": "nope"}`, new Set())).toEqual([]);
+  });
+});
+
+describe("applySuggestions", () => {
+  it("emits low-confidence findings marked for confirmation", () => {
+    const tdm = makeTdm();
+    const applied = applySuggestions(
+      tdm,
+      [
+        { id: "package:go:github.com/acmepay/acme-go", vendor: "acmepay", category: "payments" },
+        { id: "endpoint:ingest.acmelogs.io", vendor: "acme-logs" },
+      ],
+      "gpt-4o-mini",
+    );
+
+    expect(applied).toBe(2);
+    expect(tdm.sdks).toHaveLength(1);
+    expect(tdm.sdks[0]).toMatchObject({
+      provider: "acmepay",
+      sdk_package: "github.com/acmepay/acme-go",
+      confidence: "low",
+      suggestion: { source: "llm", model: "gpt-4o-mini", vendor: "acmepay", category: "payments" },
+    });
+    expect(tdm.apis[1]).toMatchObject({ confidence: "low", suggestion: { vendor: "acme-logs" } });
+    expect(tdm.apis[1]!.provider).toBeUndefined();
+    expect(tdm.metadata.total_dependencies_found).toBe(6);
+  });
+});
+
+describe("vendorSlug", () => {
+  it("normalizes vendor names", () => {
+    expect(vendorSlug("  Acme Cloud, Inc. ")).toBe("acme-cloud-inc");
+  });
+});
+
+describe("isAllowedLLMEndpoint", () => {
+  it("requires https except on localhost", () => {
+    expect(isAllowedLLMEndpoint("https://llm.internal.acme.io/v1/chat/completions")).toBe(true);
+    expect(isAllowedLLMEndpoint("http://localhost:11434/v1/chat/completions")).toBe(true);
+    expect(isAllowedLLMEndpoint("http://127.0.0.1/v1")).toBe(true);
+    expect(isAllowedLLMEndpoint("http://llm.internal.acme.io/v1")).toBe(false);
+    expect(isAllowedLLMEndpoint("http://localhost.attacker.example/v1")).toBe(false);
+  });
+});
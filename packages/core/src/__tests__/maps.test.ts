@@ -0,0 +1,43 @@
+import { describe, it, expect } from "vitest";
+import { readFile } from "node:fs/promises";
+import { resolve } from "node:path";
+import { detectMapsKeys } from "../maps.js";
+import { annotateSecrets } from "../secrets.js";
+
+const fixturesRoot = resolve(__dirname, "../../../../fixtures/maps-config");
+
+async function keys(file: string): Promise<[string, number][]> {
+  const source = await readFile(resolve(fixturesRoot, file), "utf-8");
+  return detectMapsKeys(source, file).flatMap((e) => (e.kind === "api" ? [[e.url, e.locations[0]!.line]] : []));
+}
+
+describe("detectMapsKeys", () => {
+  it("finds namespaced keys in YAML, skipping comments", async () => {
+    expect(await keys("application.yml")).toEqual([
+      ["https://maps.googleapis.com", 2],
+      ["https://api.mapbox.com", 3],
+    ]);
+  });
+
+  it("finds camelCase and env-style keys in JSON", async () => {
+    expect(await keys("app.json")).toEqual([
+      ["https://geocode.search.hereapi.com", 5],
+      ["https://maps.googleapis.com", 6],
+    ]);
+  });
+
+  it("reports keys in the maps category, with literal keys flagged as secrets", () => {
+    const source = 'MAPBOX_ACCESS_TOKEN: "pk.eyJ1IjoiYWNtZSIsImEiOiJjbHgxYTJiM2MwMDAwIn0.abcdefghijklmnopqrstuv"\n';
+    const entries = detectMapsKeys(source, "config.yml");
+    annotateSecrets(entries, source, "config.yml");
+    expect(entries[0]).toMatchObject({
+      kind: "api",
+      category: "maps",
+      locations: [{ line: 1, usage: "api_key", secret: { kind: "mapbox_token" }, context: 'MAPBOX_ACCESS_TOKEN: "[REDACTED]"' }],
+    });
+  });
+
+  it("ignores keys without a value", () => {
+    expect(detectMapsKeys("GOOGLE_MAPS_API_KEY=\nmapbox_token: ''\n", "c.ini")).toEqual([]);
+  });
+});
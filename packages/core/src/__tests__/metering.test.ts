@@ -0,0 +1,62 @@
+import { describe, it, expect } from "vitest";
+import { EgressMeter } from "../metering.js";
+import type { RuntimeObservation } from "../runtime.js";
+
+const ts = "2026-10-14T10:00:00.000Z";
+const matchVendor = (h: string) => (h.endsWith("stripe.com") ? "stripe" : h.endsWith("openai.com") ? "openai" : null);
+
+function calls(host: string, n: number, client = "checkout"): RuntimeObservation[] {
+  return Array.from({ length: n }, () => ({ source: "proxy", host, port: 443, client, timestamp: ts }));
+}
+
+describe("EgressMeter", () => {
+  it("alerts on vendors first called after the learning period", () => {
+    const meter = new EgressMeter({ matchVendor, defaultService: "host-1", learningWindows: 2, now: () => ts });
+    for (const o of calls("api.stripe.com", 5)) meter.record(o);
+    expect(meter.closeWindow()).toEqual([]);
+    expect(meter.closeWindow()).toEqual([]);
+
+    for (const o of calls("api.openai.com", 3)) meter.record(o);
+    for (const o of calls("files.example.net", 1)) meter.record(o);
+    const alerts = meter.closeWindow();
+    expect(alerts.map((a) => [a.kind, a.service, a.vendor, a.known_vendor, a.calls])).toEqual([
+      ["new_vendor", "checkout", "openai", true, 3],
+      ["new_vendor", "checkout", "files.example.net", false, 1],
+    ]);
+    expect(meter.closeWindow()).toEqual([]);
+  });
+
+  it("alerts when a window's volume far exceeds the moving average", () => {
+    const meter = new EgressMeter({ matchVendor, defaultService: "host-1", learningWindows: 0, now: () => ts });
+    for (let i = 0; i < 12; i++) {
+      for (const o of calls("api.stripe.com", 10)) meter.record(o);
+      meter.closeWindow();
+    }
+    for (const o of calls("api.stripe.com", 25)) meter.record(o);
+    expect(meter.closeWindow()).toEqual([]);
+
+    for (const o of calls("api.stripe.com", 200)) meter.record(o);
+    const [spike] = meter.closeWindow();
+    expect(spike).toMatchObject({ kind: "volume_spike", vendor: "stripe", metric: "calls", calls: 200 });
+  });
+
+  it("restores a persisted baseline and exposes Prometheus counters", () => {
+    const first = new EgressMeter({ matchVendor, defaultService: "host-1", learningWindows: 1, now: () => ts });
+    for (const o of calls("api.stripe.com", 2)) first.record(o);
+    first.closeWindow();
+
+    const meter = new EgressMeter({
+      matchVendor, defaultService: "host-1", learningWindows: 1, now: () => ts,
+      state: JSON.parse(JSON.stringify(first.snapshot())),
+    });
+    const [obs] = calls("api.stripe.com", 1);
+    meter.record(obs!);
+    meter.addBytes(obs!, 4096);
+    expect(meter.closeWindow()).toEqual([]);
+
+    const text = meter.metrics();
+    expect(text).toContain('thirdwatch_egress_calls_total{service="checkout",vendor="stripe"} 1');
+    expect(text).toContain('thirdwatch_egress_bytes_total{service="checkout",vendor="stripe"} 4096');
+    expect(text).toContain("thirdwatch_egress_learning 0");
+  });
+});
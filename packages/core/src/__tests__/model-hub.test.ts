@@ -0,0 +1,49 @@
+import { describe, it, expect } from "vitest";
+import { readFile } from "node:fs/promises";
+import { resolve } from "node:path";
+import { detectModelDownloads, isBuildScript } from "../model-hub.js";
+
+const fixturesRoot = resolve(__dirname, "../../../../fixtures/model-hub");
+
+async function downloads(file: string): Promise<Array<[string, number]>> {
+  const source = await readFile(resolve(fixturesRoot, file), "utf-8");
+  return detectModelDownloads(source, file).flatMap((e) =>
+    e.kind === "api" ? [[e.url, e.locations[0]!.line] as [string, number]] : [],
+  );
+}
+
+describe("isBuildScript", () => {
+  it("selects Dockerfiles and shell scripts", () => {
+    expect(isBuildScript("Dockerfile")).toBe(true);
+    expect(isBuildScript("docker/Dockerfile.gpu")).toBe(true);
+    expect(isBuildScript("worker.dockerfile")).toBe(true);
+    expect(isBuildScript("scripts/entrypoint.sh")).toBe(true);
+    expect(isBuildScript("app.py")).toBe(false);
+  });
+});
+
+describe("detectModelDownloads", () => {
+  it("finds CLI downloads, hub clones, and inline from_pretrained in a Dockerfile", async () => {
+    expect(await downloads("Dockerfile")).toEqual([
+      ["https://huggingface.co/BAAI/bge-small-en-v1.5", 8],
+      ["https://huggingface.co/dslim/bert-base-NER", 9],
+      ["https://huggingface.co/distilbert/distilbert-base-uncased", 10],
+    ]);
+  });
+
+  it("finds start-up downloads of models and datasets, skipping comments", async () => {
+    expect(await downloads("entrypoint.sh")).toEqual([
+      ["https://huggingface.co/acme/support-lora", 5],
+      ["https://huggingface.co/datasets/acme/eval-set", 7],
+    ]);
+  });
+
+  it("reports downloads as GETs with the model_download usage", () => {
+    const [entry] = detectModelDownloads("RUN hf download org/model\n", "Dockerfile");
+    expect(entry).toMatchObject({
+      kind: "api",
+      method: "GET",
+      locations: [{ file: "Dockerfile", line: 1, usage: "model_download" }],
+    });
+  });
+});
@@ -0,0 +1,40 @@
+import { describe, it, expect } from "vitest";
+import { readFile } from "node:fs/promises";
+import { resolve } from "node:path";
+import { detectOidcIssuers } from "../oidc.js";
+
+const fixturesRoot = resolve(__dirname, "../../../../fixtures/oidc-config");
+
+async function issuers(file: string): Promise<string[]> {
+  const source = await readFile(resolve(fixturesRoot, file), "utf-8");
+  return detectOidcIssuers(source, file).flatMap((e) => (e.kind === "api" ? [e.url] : []));
+}
+
+describe("detectOidcIssuers", () => {
+  it("finds issuer keys in YAML, skipping comments", async () => {
+    expect(await issuers("application.yml")).toEqual([
+      "https://acme.okta.com/oauth2/default",
+      "https://sso.acme.internal/realms/platform",
+    ]);
+  });
+
+  it("finds authorities in JSON and bare tenant domains in properties", async () => {
+    expect(await issuers("appsettings.json")).toEqual([
+      "https://login.microsoftonline.com/contoso.onmicrosoft.com/v2.0",
+    ]);
+    expect(await issuers("auth.properties")).toEqual(["https://acme.us.auth0.com"]);
+  });
+
+  it("reports issuers in the auth category with their config line", () => {
+    const [entry] = detectOidcIssuers("oidc:\n  issuer: https://accounts.google.com\n", "config.yml");
+    expect(entry).toMatchObject({
+      kind: "api",
+      category: "auth",
+      locations: [{ file: "config.yml", line: 2, context: "issuer: https://accounts.google.com" }],
+    });
+  });
+
+  it("ignores unresolved placeholders", () => {
+    expect(detectOidcIssuers("issuer: ${OIDC_ISSUER}\nauthority: internal\n", "c.yml")).toEqual([]);
+  });
+});
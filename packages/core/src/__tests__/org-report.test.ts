@@ -0,0 +1,129 @@
+import { describe, it, expect } from "vitest";
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "../registry.js";
+import { groupByService } from "../concentration.js";
+import { buildOrgReport } from "../org-report.js";
+
+function tdm(repository: string, providers: string[], apis: string[] = []): TDM {
+  return {
+    version: "1.0",
+    metadata: {
+      scan_timestamp: "2026-10-14T10:00:00.000Z",
+      scanner_version: "0.1.0",
+      repository,
+      languages_detected: [],
+      total_dependencies_found: 0,
+      scan_duration_ms: 0,
+    },
+    packages: [],
+    apis: apis.map((url) => ({
+      url,
+      method: "GET",
+      locations: [{ file: "client.ts", line: 1 }],
+      usage_count: 1,
+      confidence: "medium" as const,
+    })),
+    sdks: providers.map((provider) => ({
+      provider,
+      sdk_package: provider,
+      locations: [{ file: `${provider}.ts`, line: 1 }, { file: `${provider}.ts`, line: 9 }],
+      usage_count: 2,
+      confidence: "high" as const,
+    })),
+    infrastructure: [],
+    webhooks: [],
+  };
+}
+
+const registry: SDKRegistryEntry[] = [
+  {
+    provider: "stripe",
+    display_name: "Stripe",
+    category: "payments",
+    dpa_url: "https://stripe.com/legal/dpa",
+    subprocessors_url: "https://stripe.com/legal/service-providers",
+    patterns: {},
+  },
+  { provider: "adyen", display_name: "Adyen", category: "payments", patterns: {} },
+  { provider: "openai", display_name: "OpenAI", category: "ai", patterns: {}, domains: ["openai.com"] },
+];
+
+describe("buildOrgReport", () => {
+  const inventory = groupByService(
+    [
+      tdm("web", ["stripe"], ["https://api.openai.com/v1/chat/completions"]),
+      tdm("billing", ["stripe", "adyen"]),
+      tdm("search", ["openai", "acme-internal"]),
+    ],
+    (i) => `file-${i}`,
+  );
+
+  it("builds the vendor × repository matrix and category breakdown", () => {
+    const report = buildOrgReport(inventory, registry, { now: new Date("2026-10-14T12:00:00Z") });
+
+    expect(report.generated_at).toBe("2026-10-14T12:00:00.000Z");
+    expect(report.repositories).toEqual(["billing", "search", "web"]);
+    expect(report.vendors.map((v) => [v.vendor, v.repositories, v.total_usages])).toEqual([
+      ["stripe", 2, 4],
+      ["openai", 2, 3],
+      ["acme-internal", 1, 2],
+      ["adyen", 1, 2],
+    ]);
+    expect(report.vendors[1]!.usages).toEqual({ web: 1, search: 2 });
+    expect(report.vendors[0]!.approved).toBeUndefined();
+    expect(report.categories).toEqual([
+      { category: "payments", vendors: ["stripe", "adyen"], repositories: 2, total_usages: 6 },
+      { category: "ai", vendors: ["openai"], repositories: 2, total_usages: 3 },
+      { category: "uncategorized", vendors: ["acme-internal"], repositories: 1, total_usages: 2 },
+    ]);
+    expect(report.top_unapproved).toEqual([]);
+  });
+
+  it("counts a vendor named by an alias, or by one of its hosts, as the vendor", () => {
+    const aliased: SDKRegistryEntry[] = [
+      ...registry,
+      { provider: "aws-ses", display_name: "AWS SES", aliases: ["Amazon SES"], patterns: {}, domains: ["email-smtp.*.amazonaws.com"] },
+    ];
+    const report = buildOrgReport(
+      groupByService(
+        [
+          tdm("web", ["aws-ses"]),
+          tdm("billing", ["amazon-ses"]),
+          tdm("mailer", [], ["https://email-smtp.eu-west-1.amazonaws.com"]),
+        ],
+        (i) => `file-${i}`,
+      ),
+      aliased,
+      { approved: ["Amazon SES"] },
+    );
+    expect(report.vendors.map((v) => [v.vendor, v.repositories, v.approved])).toEqual([["aws-ses", 3, true]]);
+  });
+
+  it("carries each vendor's compliance links from the catalog", () => {
+    const report = buildOrgReport(inventory, registry);
+
+    expect(report.vendors.find((v) => v.vendor === "stripe")!.compliance).toEqual({
+      dpa_url: "https://stripe.com/legal/dpa",
+      subprocessors_url: "https://stripe.com/legal/service-providers",
+    });
+    expect(report.vendors.find((v) => v.vendor === "openai")!.compliance).toBeUndefined();
+  });
+
+  it("ranks unapproved vendors by reach", () => {
+    const report = buildOrgReport(inventory, registry, { approved: ["stripe"], top: 2 });
+
+    expect(report.vendors.find((v) => v.vendor === "stripe")!.approved).toBe(true);
+    expect(report.top_unapproved.map((v) => v.vendor)).toEqual(["openai", "acme-internal"]);
+  });
+
+  it("unions the data classification of each vendor's findings across repositories", () => {
+    const web = tdm("web", ["stripe"]);
+    web.sdks[0]!.data_classification = ["pci"];
+    const billing = tdm("billing", ["stripe"]);
+    billing.sdks[0]!.data_classification = ["pii", "pci"];
+    const report = buildOrgReport(groupByService([web, billing], (i) => `file-${i}`), registry);
+
+    expect(report.vendors[0]!.data_classification).toEqual(["pci", "pii"]);
+    expect(buildOrgReport(inventory, registry).vendors[0]!.data_classification).toBeUndefined();
+  });
+});
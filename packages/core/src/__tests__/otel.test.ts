@@ -0,0 +1,84 @@
+import { describe, it, expect } from "vitest";
+import { request } from "node:http";
+import { spanObservations, startOtlpReceiver } from "../otel.js";
+
+const now = () => "2026-10-14T12:00:00.000Z";
+const str = (key: string, value: string) => ({ key, value: { stringValue: value } });
+const int = (key: string, value: number) => ({ key, value: { intValue: String(value) } });
+
+const exportRequest = {
+  resourceSpans: [
+    {
+      resource: { attributes: [str("service.name", "checkout")] },
+      scopeSpans: [
+        {
+          spans: [
+            {
+              name: "POST",
+              kind: 3,
+              startTimeUnixNano: "1791972000000000000",
+              attributes: [str("http.request.method", "POST"), str("url.full", "https://api.stripe.com/v1/charges/ch_3NkLmN2pQrStUvWxYz?expand=x")],
+            },
+            {
+              name: "GET",
+              kind: "SPAN_KIND_CLIENT",
+              startTimeUnixNano: "1791972001000000000",
+              attributes: [str("http.method", "GET"), str("net.peer.name", "api.openai.com"), int("net.peer.port", 443), str("user_agent.original", "OpenAI/Python 1.40.6")],
+            },
+            { name: "charge", kind: 3, attributes: [str("peer.service", "Twilio")] },
+            { name: "internal", kind: 3, attributes: [str("url.full", "http://inventory.default.svc.cluster.local/items")] },
+            { name: "db", kind: 3, attributes: [str("server.address", "10.0.0.12"), int("server.port", 5432)] },
+            { name: "handler", kind: 2, attributes: [str("url.full", "https://shop.acme.io/cart")] },
+          ],
+        },
+      ],
+    },
+  ],
+};
+
+describe("spanObservations", () => {
+  it("turns CLIENT spans into observations and skips internal destinations", () => {
+    expect(spanObservations(exportRequest, now)).toEqual([
+      {
+        source: "otel", host: "api.stripe.com", port: 443, method: "POST", path: "/v1/charges/{id}",
+        client: "checkout", timestamp: "2026-10-14T10:00:00.000Z",
+      },
+      {
+        source: "otel", host: "api.openai.com", port: 443, method: "GET", client: "checkout",
+        user_agent: "OpenAI/Python 1.40.6", timestamp: "2026-10-14T10:00:01.000Z",
+      },
+      { source: "otel", service: "twilio", client: "checkout", timestamp: now() },
+    ]);
+  });
+
+  it("ignores malformed input", () => {
+    expect(spanObservations(null)).toEqual([]);
+    expect(spanObservations({ resourceSpans: [{ scopeSpans: "nope" }] })).toEqual([]);
+  });
+});
+
+describe("startOtlpReceiver", () => {
+  const post = (port: number, body: string, contentType: string) =>
+    new Promise<number>((resolveFn, reject) => {
+      const req = request(
+        { port, method: "POST", path: "/v1/traces", headers: { "content-type": contentType } },
+        (res) => {
+          res.resume();
+          resolveFn(res.statusCode ?? 0);
+        },
+      );
+      req.on("error", reject);
+      req.end(body);
+    });
+
+  it("accepts OTLP/HTTP JSON and rejects protobuf", async () => {
+    const receiver = await startOtlpReceiver({ port: 0 });
+    try {
+      expect(await post(receiver.port, JSON.stringify(exportRequest), "application/json")).toBe(200);
+      expect(await post(receiver.port, "\x0a\x00", "application/x-protobuf")).toBe(415);
+      expect(receiver.observations.map((o) => o.host ?? o.service)).toEqual(["api.stripe.com", "api.openai.com", "twilio"]);
+    } finally {
+      await receiver.close();
+    }
+  });
+});
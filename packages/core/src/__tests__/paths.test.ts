@@ -0,0 +1,22 @@
+import { describe, it, expect } from "vitest";
+import type { DependencyEntry } from "../plugin.js";
+import { normalizeEntryPaths, toPosixPath } from "../paths.js";
+
+describe("toPosixPath", () => {
+  it("replaces Windows separators and leaves POSIX paths alone", () => {
+    expect(toPosixPath("src\\pay\\charge.py", "\\")).toBe("src/pay/charge.py");
+    expect(toPosixPath("src/pay/charge.py", "/")).toBe("src/pay/charge.py");
+  });
+});
+
+describe("normalizeEntryPaths", () => {
+  it("rewrites locations and manifests reported with Windows separators", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "package", name: "stripe", ecosystem: "pypi", current_version: "7.9.0", manifest_file: "services\\payments\\requirements.txt", locations: [], usage_count: 0, confidence: "high" },
+      { kind: "sdk", provider: "stripe", sdk_package: "stripe", locations: [{ file: "services\\payments\\pay.py", line: 3 }], usage_count: 1, confidence: "high" },
+    ];
+    normalizeEntryPaths(entries, "\\");
+    expect(entries[0]).toMatchObject({ manifest_file: "services/payments/requirements.txt" });
+    expect(entries[1]!.locations[0]!.file).toBe("services/payments/pay.py");
+  });
+});
@@ -0,0 +1,149 @@
+import { describe, it, expect, afterEach } from "vitest";
+import { createServer, request } from "node:http";
+import type { Server } from "node:http";
+import { connect } from "node:net";
+import type { AddressInfo } from "node:net";
+import { startRecordingProxy, parseConnectTarget } from "../proxy.js";
+import type { RecordingProxy } from "../proxy.js";
+import { normalizePath } from "../runtime.js";
+
+let proxy: RecordingProxy | undefined;
+let upstream: Server | undefined;
+
+afterEach(async () => {
+  await proxy?.close();
+  await new Promise<void>((r) => (upstream ? upstream.close(() => r()) : r()));
+  proxy = undefined;
+  upstream = undefined;
+});
+
+async function startUpstream(): Promise<number> {
+  upstream = createServer((req, res) => {
+    res.writeHead(200, { "content-type": "text/plain" });
+    res.end(`hello ${req.method} ${req.url}`);
+  });
+  await new Promise<void>((r) => upstream!.listen(0, "127.0.0.1", () => r()));
+  return (upstream.address() as AddressInfo).port;
+}
+
+describe("parseConnectTarget", () => {
+  it("parses host:port and IPv6 targets", () => {
+    expect(parseConnectTarget("api.stripe.com:443")).toEqual({ host: "api.stripe.com", port: 443 });
+    expect(parseConnectTarget("[2600::1]:8443")).toEqual({ host: "2600::1", port: 8443 });
+    expect(parseConnectTarget("bad host:99999")).toBeNull();
+  });
+});
+
+describe("normalizePath", () => {
+  it("collapses identifiers and drops the query string", () => {
+    expect(normalizePath("/v1/customers/cus_8FkLmN2pQrStUvWx/charges?limit=3")).toBe("/v1/customers/{id}/charges");
+    expect(normalizePath("/orders/12345")).toBe("/orders/{id}");
+    expect(normalizePath("/items/3f2504e0-4f89-11d3-9a0c-0305e82c3301/")).toBe("/items/{id}");
+    expect(normalizePath("/")).toBe("");
+  });
+});
+
+describe("startRecordingProxy", () => {
+  it("forwards plain HTTP requests and records method, host, path, and User-Agent", async () => {
+    const port = await startUpstream();
+    proxy = await startRecordingProxy({ port: 0, now: () => "2026-10-14T10:00:00.000Z" });
+
+    const body = await new Promise<string>((resolveFn, reject) => {
+      const req = request(
+        {
+          host: "127.0.0.1",
+          port: proxy!.port,
+          method: "POST",
+          path: `http://127.0.0.1:${port}/v1/orders/42?x=1`,
+          headers: { "user-agent": "Stripe/v1 PythonBindings/7.1.0" },
+        },
+        (res) => {
+          let data = "";
+          res.on("data", (c: Buffer) => (data += c.toString()));
+          res.on("end", () => resolveFn(data));
+        },
+      );
+      req.on("error", reject);
+      req.end("secret-body");
+    });
+
+    expect(body).toBe("hello POST /v1/orders/42?x=1");
+    expect(proxy.observations).toEqual([
+      {
+        source: "proxy",
+        ip: "127.0.0.1",
+        port,
+        method: "POST",
+        path: "/v1/orders/{id}",
+        user_agent: "Stripe/v1 PythonBindings/7.1.0",
+        timestamp: "2026-10-14T10:00:00.000Z",
+      },
+    ]);
+  });
+
+  it("folds repeat requests into one observation per endpoint with a count and last seen", async () => {
+    const port = await startUpstream();
+    let tick = 0;
+    const seen: string[] = [];
+    proxy = await startRecordingProxy({
+      port: 0,
+      now: () => `2026-10-14T10:00:0${tick++}.000Z`,
+      onObservation: (o) => seen.push(`${o.method} ${o.path}`),
+    });
+    const get = (path: string) =>
+      new Promise<void>((resolveFn, reject) => {
+        const req = request({ host: "127.0.0.1", port: proxy!.port, path: `http://127.0.0.1:${port}${path}` }, (res) => {
+          res.resume();
+          res.on("end", () => resolveFn());
+        });
+        req.on("error", reject);
+        req.end();
+      });
+
+    await get("/v1/orders/1");
+    await get("/v1/orders/2");
+    await get("/v1/health");
+    await get("/v1/orders/3");
+
+    expect(seen).toEqual(["GET /v1/orders/{id}", "GET /v1/orders/{id}", "GET /v1/health", "GET /v1/orders/{id}"]);
+    expect(proxy.observations).toEqual([
+      {
+        source: "proxy",
+        ip: "127.0.0.1",
+        port,
+        method: "GET",
+        path: "/v1/orders/{id}",
+        timestamp: "2026-10-14T10:00:00.000Z",
+        count: 3,
+        last_seen: "2026-10-14T10:00:03.000Z",
+      },
+      { source: "proxy", ip: "127.0.0.1", port, method: "GET", path: "/v1/health", timestamp: "2026-10-14T10:00:02.000Z" },
+    ]);
+  });
+
+  it("tunnels CONNECT requests and records only host and port", async () => {
+    const port = await startUpstream();
+    proxy = await startRecordingProxy({ port: 0 });
+
+    const response = await new Promise<string>((resolveFn, reject) => {
+      const socket = connect(proxy!.port, "127.0.0.1", () => {
+        socket.write(`CONNECT localhost:${port} HTTP/1.1\r\nHost: localhost:${port}\r\n\r\n`);
+      });
+      let data = "";
+      socket.on("data", (c: Buffer) => {
+        data += c.toString();
+        if (data.includes("\r\n\r\n") && !data.includes("GET")) {
+          socket.write(`GET /tunnelled HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n`);
+        }
+      });
+      socket.on("end", () => resolveFn(data));
+      socket.on("error", reject);
+    });
+
+    expect(response).toContain("200 Connection Established");
+    expect(response).toContain("hello GET /tunnelled");
+    expect(proxy.observations).toHaveLength(1);
+    expect(proxy.observations[0]).toMatchObject({ source: "proxy", host: "localhost", port });
+    expect(proxy.observations[0]!.path).toBeUndefined();
+  });
+});
@@ -0,0 +1,101 @@
+import { describe, it, expect } from "vitest";
+import type { TDM, TDMApi } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "../registry.js";
+import { buildQuotaReport } from "../quota.js";
+
+function tdm(apis: TDMApi[]): TDM {
+  return {
+    version: "1.0",
+    metadata: {
+      scan_timestamp: "2026-10-14T10:00:00.000Z",
+      scanner_version: "0.1.0",
+      languages_detected: [],
+      total_dependencies_found: 0,
+      scan_duration_ms: 0,
+    },
+    packages: [],
+    apis,
+    sdks: [],
+    infrastructure: [],
+    webhooks: [],
+  };
+}
+
+function observed(url: string, count: number, minutes: number): TDMApi {
+  const first = Date.parse("2026-10-14T10:00:00.000Z");
+  return {
+    url,
+    runtime: {
+      source: "agent",
+      count,
+      first_seen: new Date(first).toISOString(),
+      last_seen: new Date(first + minutes * 60_000).toISOString(),
+    },
+    locations: [],
+    usage_count: 0,
+    confidence: "high",
+  };
+}
+
+const registry: SDKRegistryEntry[] = [
+  {
+    provider: "stripe",
+    display_name: "Stripe",
+    patterns: {},
+    domains: ["stripe.com"],
+    rate_limits: [{ requests: 100, window_seconds: 1, url: "https://example.com/stripe-limits", plan: "Live mode", domains: ["api.stripe.com"] }],
+  },
+  {
+    provider: "shopify",
+    display_name: "Shopify",
+    patterns: {},
+    domains: ["myshopify.com"],
+    rate_limits: [
+      { requests: 2, window_seconds: 1, url: "https://example.com/shopify-limits", plan: "Standard" },
+      { requests: 20, window_seconds: 1, url: "https://example.com/shopify-limits", plan: "Shopify Plus" },
+    ],
+  },
+  { provider: "openai", display_name: "OpenAI", patterns: {}, domains: ["openai.com"] },
+];
+
+describe("buildQuotaReport", () => {
+  it("compares average runtime rates with the vendor's published limit", () => {
+    const report = buildQuotaReport(
+      [tdm([observed("https://api.stripe.com/v1/charges", 41_000, 10), observed("https://acme.myshopify.com/admin/api/2024-01/orders.json", 3_000, 20)])],
+      registry,
+    );
+    expect(report.usages.map((u) => [u.vendor, u.requests_per_second, u.utilization, u.status])).toEqual([
+      ["shopify", 2.5, 1.25, "over"],
+      ["stripe", 68.333, 0.683, "ok"],
+    ]);
+    expect(report.near_quota).toEqual(["shopify"]);
+  });
+
+  it("adds up traffic from every instance against one account-wide limit", () => {
+    const instance = () => tdm([observed("https://api.stripe.com/v1/charges", 27_000, 5)]);
+    const report = buildQuotaReport([instance(), instance()], registry);
+    expect(report.usages).toHaveLength(1);
+    expect(report.usages[0]).toMatchObject({ observed_requests: 54_000, requests_per_second: 180, status: "over" });
+  });
+
+  it("reports near-quota integrations at the threshold", () => {
+    const apis = [observed("https://api.stripe.com/v1/charges", 51_000, 10)];
+    expect(buildQuotaReport([tdm(apis)], registry).usages[0]!.status).toBe("near");
+    expect(buildQuotaReport([tdm(apis)], registry, { threshold: 0.9 }).usages[0]!.status).toBe("ok");
+  });
+
+  it("measures against the chosen plan or a contracted limit", () => {
+    const apis = [observed("https://acme.myshopify.com/admin/api/2024-01/orders.json", 3_000, 20)];
+    const plus = buildQuotaReport([tdm(apis)], registry, { plans: { shopify: "plus" } }).usages[0]!;
+    expect(plus).toMatchObject({ plan: "Shopify Plus", utilization: 0.125, status: "ok" });
+    const contracted = buildQuotaReport([tdm(apis)], registry, { assume: { shopify: { requests: 600, window_seconds: 60 } } }).usages[0]!;
+    expect(contracted).toMatchObject({ source: "assumed", requests_per_window: 150, status: "ok" });
+  });
+
+  it("lists vendors without a published limit and ignores static findings", () => {
+    const staticCall: TDMApi = { url: "https://api.stripe.com/v1/refunds", locations: [{ file: "app.py", line: 1 }], usage_count: 1, confidence: "high" };
+    const report = buildQuotaReport([tdm([observed("https://api.openai.com/v1/responses", 500, 60), staticCall])], registry);
+    expect(report.usages).toEqual([]);
+    expect(report.unknown).toEqual(["openai"]);
+  });
+});
@@ -8,6 +8,7 @@ import {
   buildUrlProviderMap,
   buildRegistryMaps,
 } from "../registry.js";
+import { createVendorMatcher } from "../runtime.js";
 
 const registriesDir = resolve(__dirname, "../../../../registries");
 
@@ -52,7 +53,7 @@ describe("buildConstructorProviderMap", () => {
     expect(map.get("Stripe")).toEqual(["stripe", "stripe"]);
     const s3 = map.get("S3Client");
     expect(s3).toBeDefined();
-    expect(s3![0]).toBe("aws");
+    expect(s3).toEqual(["aws-s3", "@aws-sdk/client-s3"]);
     expect(map.size).toBeGreaterThan(0);
   });
 
@@ -105,4 +106,376 @@ describe("buildRegistryMaps", () => {
     ]);
     expect(maps.urlProviders.get("https://api.stripe.com")).toBe("stripe");
   });
+
+  it("maps SMS and voice REST hosts for calls made without an SDK", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const maps = buildRegistryMaps(registry, "npm");
+
+    expect(maps.urlProviders.get("https://api.twilio.com")).toBe("twilio");
+    expect(maps.urlProviders.get("https://rest.nexmo.com")).toBe("vonage");
+    expect(maps.constructorProviders.get("Vonage")).toEqual(["vonage", "@vonage/server-sdk"]);
+  });
+});
+
+describe("catalog host attribution", () => {
+  it("attributes Firebase and Supabase hosts ahead of generic Google APIs", async () => {
+    const match = createVendorMatcher(await loadSDKRegistry(registriesDir));
+
+    expect(match("acme-default-rtdb.firebaseio.com")).toBe("firebase");
+    expect(match("acme.europe-west1.firebasedatabase.app")).toBe("firebase");
+    expect(match("fcm.googleapis.com")).toBe("firebase-cloud-messaging");
+    expect(match("firestore.googleapis.com")).toBe("gcp-firestore");
+    expect(match("xyzcompany.supabase.co")).toBe("supabase");
+  });
+
+  it("attributes LLM provider hosts, keeping the Gemini API apart from Vertex AI", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("api.anthropic.com")).toBe("anthropic");
+    expect(match("api.cohere.ai")).toBe("cohere");
+    expect(match("api.mistral.ai")).toBe("mistral");
+    expect(match("generativelanguage.googleapis.com")).toBe("gemini");
+    expect(match("us-central1-aiplatform.googleapis.com")).toBe("gcp-vertex-ai");
+    for (const provider of ["anthropic", "cohere", "mistral", "gemini", "openai"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("ai");
+    }
+  });
+
+  it("attributes managed Kafka brokers, keeping MSK apart from other AWS hosts", async () => {
+    const match = createVendorMatcher(await loadSDKRegistry(registriesDir));
+
+    expect(match("pkc-abc12.us-east-1.aws.confluent.cloud")).toBe("confluent-cloud");
+    expect(match("b-1.orders.abc123.c2.kafka.us-east-1.amazonaws.com")).toBe("aws-msk");
+    expect(match("boot-abc123.c1.kafka-serverless.eu-west-1.amazonaws.com")).toBe("aws-msk");
+    expect(match("kafka-audit-acme.aivencloud.com")).toBe("aiven");
+    expect(match("ec2.us-east-1.amazonaws.com")).toBe("aws");
+  });
+
+  it("attributes hosted RabbitMQ and NATS to message-broker vendors", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("kangaroo.rmq.cloudamqp.com")).toBe("cloudamqp");
+    expect(match("connect.ngs.global")).toBe("synadia");
+    expect(match("nats.messaging.svc.cluster.local")).toBeNull();
+    for (const provider of ["cloudamqp", "synadia", "confluent-cloud", "aws-msk"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("message-broker");
+    }
+  });
+
+  it("attributes Atlas cluster hosts to MongoDB", async () => {
+    const match = createVendorMatcher(await loadSDKRegistry(registriesDir));
+
+    expect(match("cluster0.abcde.mongodb.net")).toBe("mongodb");
+    expect(match("mongo-0.mongo.internal")).toBeNull();
+  });
+
+  it("attributes managed SQL, Cassandra, and ClickHouse hosts to their vendors", async () => {
+    const match = createVendorMatcher(await loadSDKRegistry(registriesDir));
+
+    expect(match("aws.connect.psdb.cloud")).toBe("planetscale");
+    expect(match("orders.cluster-abc123.us-east-1.rds.amazonaws.com")).toBe("aws-rds");
+    expect(match("0123abcd-us-east1.apps.astra.datastax.com")).toBe("datastax-astra");
+    expect(match("abc123.us-east-1.aws.clickhouse.cloud")).toBe("clickhouse-cloud");
+    expect(match("cassandra-0.events.internal")).toBeNull();
+  });
+
+  it("attributes managed Redis hosts to their vendors, leaving self-hosted ones unattributed", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("eu1-pleasant-moth-31337.upstash.io")).toBe("upstash");
+    expect(match("redis-12345.c1.us-east-1-2.ec2.redns.redis-cloud.com")).toBe("redis-cloud");
+    expect(match("redis-12345.c1.us-east-1-2.ec2.cloud.redislabs.com")).toBe("redis-cloud");
+    expect(match("clustercfg.sessions.abc123.use1.cache.amazonaws.com")).toBe("aws-elasticache");
+    expect(match("redis.cache.internal")).toBeNull();
+    for (const provider of ["redis", "upstash", "redis-cloud", "aws-elasticache"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("cache");
+    }
+  });
+
+  it("attributes HCP Vault, HCP Consul, and HCP Terraform hosts", async () => {
+    const match = createVendorMatcher(await loadSDKRegistry(registriesDir));
+
+    expect(match("vault-cluster-public-vault-abc123.def456.z1.hashicorp.cloud")).toBe("hashicorp-vault");
+    expect(match("acme.consul.11eb1234-abcd-5678-ef90-0242ac110002.aws.hashicorp.cloud")).toBe("hashicorp-consul");
+    expect(match("app.terraform.io")).toBe("terraform-cloud");
+    expect(match("vault.platform.internal")).toBeNull();
+  });
+
+  it("attributes Temporal Cloud namespaces and workflow service endpoints", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("orders-prod.a1b2c.tmprl.cloud")).toBe("temporal");
+    expect(match("us-east-1.aws.api.temporal.io")).toBe("temporal");
+    expect(match("states.eu-west-1.amazonaws.com")).toBe("aws-step-functions");
+    expect(match("workflowexecutions.googleapis.com")).toBe("gcp-workflows");
+    expect(match("temporal-frontend.temporal.svc.cluster.local")).toBeNull();
+    for (const provider of ["temporal", "aws-step-functions", "gcp-workflows"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("workflow");
+    }
+  });
+
+  it("groups card processors under payments, including live Adyen prefixes", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("api-m.sandbox.paypal.com")).toBe("paypal");
+    expect(match("payments.braintree-api.com")).toBe("braintree");
+    expect(match("1797a841fbb37ca7-adyendemo-checkout-live.adyenpayments.com")).toBe("adyen");
+    expect(match("connect.squareupsandbox.com")).toBe("square");
+    for (const provider of ["stripe", "paypal", "braintree", "adyen", "square"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("payments");
+    }
+  });
+
+  it("attributes IP lookup services and GeoIP database downloads", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("geoip.maxmind.com")).toBe("maxmind");
+    expect(match("geolite.info")).toBe("maxmind");
+    expect(match("download.maxmind.com")).toBe("maxmind");
+    expect(match("ipinfo.io")).toBe("ipinfo");
+    expect(match("api.ipstack.com")).toBe("ipstack");
+    for (const provider of ["maxmind", "ipinfo", "ipstack"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("ip-intelligence");
+    }
+  });
+
+  it("attributes Workspace and Zoom hosts, leaving other googleapis.com hosts to GCP", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("gmail.googleapis.com")).toBe("google-workspace");
+    expect(match("sheets.googleapis.com")).toBe("google-workspace");
+    expect(match("admin.googleapis.com")).toBe("google-workspace");
+    expect(match("www.googleapis.com")).toBe("gcp");
+    expect(match("graph.microsoft.com")).toBe("microsoft-graph");
+    expect(match("api.zoom.us")).toBe("zoom");
+    expect(registry.find((e) => e.provider === "google-workspace")?.category).toBe("productivity");
+  });
+
+  it("attributes e-signature API hosts", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("demo.docusign.net")).toBe("docusign");
+    expect(match("account-d.docusign.com")).toBe("docusign");
+    expect(match("api.hellosign.com")).toBe("hellosign");
+    expect(match("api.pandadoc.com")).toBe("pandadoc");
+    for (const provider of ["docusign", "hellosign", "pandadoc"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("documents");
+    }
+  });
+
+  it("attributes image and video processing hosts", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("api.cloudinary.com")).toBe("cloudinary");
+    expect(match("res.cloudinary.com")).toBe("cloudinary");
+    expect(match("acme.imgix.net")).toBe("imgix");
+    expect(match("stream.mux.com")).toBe("mux");
+    expect(match("api2-eu-west-1.transloadit.com")).toBe("transloadit");
+    for (const provider of ["cloudinary", "imgix", "mux", "transloadit"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("media");
+    }
+  });
+
+  it("attributes S3-compatible storage hosts apart from Cloudflare and AWS", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("8d5c1a2f9e0b4c7d.r2.cloudflarestorage.com")).toBe("cloudflare-r2");
+    expect(match("s3.us-west-004.backblazeb2.com")).toBe("backblaze-b2");
+    expect(match("nyc3.digitaloceanspaces.com")).toBe("digitalocean-spaces");
+    expect(match("play.min.io")).toBe("minio");
+    expect(match("acme-uploads.s3.amazonaws.com")).toBe("aws-s3");
+    for (const provider of ["cloudflare-r2", "backblaze-b2", "digitalocean-spaces", "minio", "aws-s3"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("storage");
+    }
+  });
+
+  it("attributes CDN management APIs,not the edge hosts that serve traffic", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("api.cloudflare.com")).toBe("cloudflare");
+    expect(match("api.fastly.com")).toBe("fastly");
+    expect(match("akab-hqbvzjbjtcbss3xv.purge.akamaiapis.net")).toBe("akamai");
+    expect(match("dualstack.global.ssl.fastly.net")).toBeNull();
+    expect(match("www.example.com.edgekey.net")).toBeNull();
+    for (const provider of ["cloudflare", "fastly", "akamai"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("cdn");
+    }
+  });
+
+  it("attributes push gateways, reporting FCM apart from the rest of Firebase", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("fcm.googleapis.com")).toBe("firebase-cloud-messaging");
+    expect(match("api.sandbox.push.apple.com")).toBe("apns");
+    expect(match("api.onesignal.com")).toBe("onesignal");
+    expect(match("exp.host")).toBe("expo");
+    for (const provider of ["firebase-cloud-messaging", "apns", "onesignal", "expo"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("push");
+    }
+  });
+
+  it("attributes CAPTCHA verification hosts without claiming google.com or cloudflare.com", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("www.recaptcha.net")).toBe("recaptcha");
+    expect(match("recaptchaenterprise.googleapis.com")).toBe("recaptcha");
+    expect(match("www.google.com")).toBeNull();
+    expect(match("api.hcaptcha.com")).toBe("hcaptcha");
+    expect(match("challenges.cloudflare.com")).toBe("cloudflare-turnstile");
+    expect(match("api.cloudflare.com")).toBe("cloudflare");
+    for (const provider of ["recaptcha", "hcaptcha", "cloudflare-turnstile"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("bot-mitigation");
+    }
+  });
+
+  it("attributes Maps Platform hosts apart from the rest of googleapis.com", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("maps.googleapis.com")).toBe("google-maps");
+    expect(match("places.googleapis.com")).toBe("google-maps");
+    expect(match("storage.googleapis.com")).not.toBe("google-maps");
+    expect(match("api.mapbox.com")).toBe("mapbox");
+    expect(match("autosuggest.search.hereapi.com")).toBe("here");
+    for (const provider of ["google-maps", "mapbox", "here"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("maps");
+    }
+  });
+
+  it("attributes shipping APIs and carriers in the shipping category", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("api.easypost.com")).toBe("easypost");
+    expect(match("api.goshippo.com")).toBe("shippo");
+    expect(match("apis-sandbox.fedex.com")).toBe("fedex");
+    expect(match("wwwcie.ups.com")).toBe("ups");
+    for (const provider of ["easypost", "shippo", "fedex", "ups"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("shipping");
+    }
+  });
+
+  it("attributes KYC vendors, including regional and legacy hosts", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("api.us.onfido.com")).toBe("onfido");
+    expect(match("withpersona.com")).toBe("persona");
+    expect(match("retrieval.emea-1.jumio.ai")).toBe("jumio");
+    expect(match("lon.netverify.com")).toBe("jumio");
+    for (const provider of ["onfido", "persona", "jumio"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("identity-verification");
+    }
+  });
+
+  it("attributes merchant platforms and marketplaces in the commerce category", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("acme-store.myshopify.com")).toBe("shopify");
+    expect(match("store-abc123.mybigcommerce.com")).toBe("bigcommerce");
+    expect(match("sandbox.sellingpartnerapi-eu.amazon.com")).toBe("amazon-selling-partner");
+    expect(match("api.sandbox.ebay.com")).toBe("ebay");
+    expect(match("openapi.etsy.com")).toBe("etsy");
+    expect(match("www.amazon.com")).toBeNull();
+    for (const provider of ["shopify", "woocommerce", "bigcommerce", "amazon-selling-partner", "ebay", "etsy"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("commerce");
+    }
+  });
+
+  it("attributes billing and tax platforms, including per-site Chargebee hosts", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("sandbox-rest.avatax.com")).toBe("avalara");
+    expect(match("api.sandbox.taxjar.com")).toBe("taxjar");
+    expect(match("acme-test.chargebee.com")).toBe("chargebee");
+    expect(match("v3.eu.recurly.com")).toBe("recurly");
+    for (const provider of ["avalara", "taxjar", "chargebee", "recurly"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("billing");
+    }
+  });
+
+  it("attributes open-banking aggregators in the banking category", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("production.plaid.com")).toBe("plaid");
+    expect(match("auth.truelayer-sandbox.com")).toBe("truelayer");
+    expect(match("acmebank.api.yodlee.com")).toBe("yodlee");
+    for (const provider of ["plaid", "truelayer", "yodlee"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("banking");
+    }
+  });
+
+  it("attributes analytics ingestion endpoints, including RudderStack data planes", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("api.segment.io")).toBe("segment");
+    expect(match("events.eu1.segmentapis.com")).toBe("segment");
+    expect(match("api-eu.mixpanel.com")).toBe("mixpanel");
+    expect(match("api2.amplitude.com")).toBe("amplitude");
+    expect(match("acme.dataplane.rudderstack.com")).toBe("rudderstack");
+    expect(match("hosted.rudderlabs.com")).toBe("rudderstack");
+    for (const provider of ["segment", "mixpanel", "amplitude", "rudderstack"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("analytics");
+    }
+  });
+
+  it("attributes managed search hosts to their vendors", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("a1b2c3d4e5f6.us-central1.gcp.cloud.es.io")).toBe("elasticsearch");
+    expect(match("search-audit-abc123.us-east-1.es.amazonaws.com")).toBe("aws-opensearch");
+    expect(match("abc123.us-east-1.aoss.amazonaws.com")).toBe("aws-opensearch");
+    expect(match("acme-dsn.algolia.net")).toBe("algolia");
+    expect(match("ms-abc123.lon.meilisearch.io")).toBe("meilisearch");
+    expect(match("xyz123-1.a1.typesense.net")).toBe("typesense");
+    for (const provider of ["elasticsearch", "aws-opensearch", "algolia", "meilisearch", "typesense"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("search");
+    }
+  });
+
+  it("attributes incident platforms, including their event ingestion hosts", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("events.pagerduty.com")).toBe("pagerduty");
+    expect(match("events.eu.pagerduty.com")).toBe("pagerduty");
+    expect(match("api.eu.opsgenie.com")).toBe("opsgenie");
+    expect(match("api.incident.io")).toBe("incident-io");
+    expect(match("alert.victorops.com")).toBe("splunk-oncall");
+    for (const provider of ["pagerduty", "opsgenie", "incident-io", "firehydrant", "rootly", "splunk-oncall"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("incident");
+    }
+  });
+
+  it("attributes enrichment APIs, including Clearbit's per-product hosts", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const match = createVendorMatcher(registry);
+
+    expect(match("person.clearbit.com")).toBe("clearbit");
+    expect(match("company.clearbit.com")).toBe("clearbit");
+    expect(match("api.zoominfo.com")).toBe("zoominfo");
+    expect(match("api.hunter.io")).toBe("hunter");
+    expect(match("api.peopledatalabs.com")).toBe("peopledatalabs");
+    for (const provider of ["clearbit", "zoominfo", "hunter", "apollo", "peopledatalabs"]) {
+      expect(registry.find((e) => e.provider === provider)?.category).toBe("enrichment");
+    }
+  });
 });
@@ -0,0 +1,109 @@
+import { describe, it, expect } from "vitest";
+import { ruleIdFor, assignRuleIds, ruleSettingFor, applyRuleSettings } from "../rule-ids.js";
+import type { DependencyEntry } from "../plugin.js";
+
+const loc = [{ file: "main.go", line: 1 }];
+
+const sdk: DependencyEntry = {
+  kind: "sdk",
+  provider: "stripe",
+  sdk_package: "github.com/stripe/stripe-go/v76",
+  locations: loc,
+  usage_count: 1,
+  confidence: "high",
+};
+const unknownApi: DependencyEntry = {
+  kind: "api",
+  url: "https://api.example.com/v1",
+  locations: loc,
+  usage_count: 1,
+  confidence: "high",
+};
+
+describe("ruleIdFor", () => {
+  it("derives stable IDs per entry kind", () => {
+    expect(ruleIdFor(sdk, "go")).toBe("TW-STRIPE-SDK-GO");
+    expect(ruleIdFor(unknownApi)).toBe("TW-HTTP-URL");
+    expect(ruleIdFor({ ...unknownApi, provider: "openai" } as DependencyEntry)).toBe("TW-OPENAI-API");
+    expect(ruleIdFor({ ...unknownApi, url: "tcp://34.120.18.9:9000" })).toBe("TW-UNCLASSIFIED-ENDPOINT");
+    expect(ruleIdFor({ ...unknownApi, url: "http://[2600:1f18::1]:8080/v1" })).toBe("TW-UNCLASSIFIED-ENDPOINT");
+    expect(ruleIdFor({ ...unknownApi, url: "http://10.0.4.12:8080/v1" })).toBe("TW-HTTP-URL");
+    expect(
+      ruleIdFor({
+        kind: "package",
+        name: "@aws-sdk/client-s3",
+        ecosystem: "npm",
+        current_version: "3.0.0",
+        manifest_file: "package.json",
+        locations: loc,
+        usage_count: 0,
+        confidence: "high",
+      }),
+    ).toBe("TW-PKG-NPM");
+    expect(
+      ruleIdFor({ kind: "infrastructure", type: "azure-blob", connection_ref: "X", locations: loc, confidence: "high" }),
+    ).toBe("TW-INFRA-AZURE-BLOB");
+    expect(
+      ruleIdFor({ kind: "webhook", direction: "inbound_callback", target_url: "/hooks", locations: loc, confidence: "high" }),
+    ).toBe("TW-WEBHOOK-INBOUND-CALLBACK");
+  });
+
+  it("keeps rule IDs set by custom rules and detectors", () => {
+    const entries = [{ ...sdk, rule_id: "acme-billing" }, { ...sdk, rule_id: "bad id!" }] as DependencyEntry[];
+    assignRuleIds(entries, "go");
+    expect(entries.map((e) => e.rule_id)).toEqual(["acme-billing", "TW-STRIPE-SDK-GO"]);
+  });
+});
+
+describe("rule settings", () => {
+  const settings = {
+    "TW-*-SDK-*": "warning",
+    "TW-STRIPE-SDK-GO": "error",
+    "tw-http-url": "off",
+  } as const;
+
+  it("prefers the most specific key and matches case-insensitively", () => {
+    expect(ruleSettingFor("TW-STRIPE-SDK-GO", settings)).toBe("error");
+    expect(ruleSettingFor("TW-OPENAI-SDK-PYTHON", settings)).toBe("warning");
+    expect(ruleSettingFor("TW-HTTP-URL", settings)).toBe("off");
+    expect(ruleSettingFor("TW-INFRA-REDIS", settings)).toBeUndefined();
+  });
+
+  it("drops disabled rules and records severities", () => {
+    const entries = [
+      { ...sdk, rule_id: "TW-STRIPE-SDK-GO" },
+      { ...sdk, provider: "openai", rule_id: "TW-OPENAI-SDK-GO" },
+      { ...unknownApi, rule_id: "TW-HTTP-URL" },
+    ] as DependencyEntry[];
+    const result = applyRuleSettings(entries, settings);
+    expect(result.map((e) => [e.rule_id, e.severity])).toEqual([
+      ["TW-STRIPE-SDK-GO", "error"],
+      ["TW-OPENAI-SDK-GO", "warning"],
+    ]);
+  });
+
+  it("applies category keys when no rule ID key matches", () => {
+    const entries = [
+      { ...sdk, provider: "sendgrid", category: "email", rule_id: "TW-SENDGRID-SDK-GO" },
+      { ...sdk, provider: "postmark", category: "email", rule_id: "TW-POSTMARK-SDK-GO" },
+      { ...unknownApi, url: "smtp://smtp.mailgun.org:587", category: "email", rule_id: "TW-HTTP-URL" },
+      { ...sdk, rule_id: "TW-STRIPE-SDK-GO", category: "payments" },
+    ] as DependencyEntry[];
+    const result = applyRuleSettings(entries, {
+      "category:email": "error",
+      "TW-POSTMARK-SDK-GO": "info",
+      "TW-HTTP-URL": "off",
+    });
+    expect(result.map((e) => [e.rule_id, e.severity])).toEqual([
+      ["TW-SENDGRID-SDK-GO", "error"],
+      ["TW-POSTMARK-SDK-GO", undefined],
+      ["TW-STRIPE-SDK-GO", undefined],
+    ]);
+  });
+
+  it("leaves entries untouched without settings", () => {
+    const entries = [{ ...unknownApi }] as DependencyEntry[];
+    expect(applyRuleSettings(entries, {})).toBe(entries);
+    expect(entries[0]!.severity).toBeUndefined();
+  });
+});
@@ -0,0 +1,155 @@
+import { describe, it, expect } from "vitest";
+import {
+  createVendorMatcher,
+  aggregateObservations,
+  buildRuntimeTDM,
+  observationUrl,
+  isPrivateIp,
+} from "../runtime.js";
+import { createUserAgentMatcher } from "../user-agents.js";
+import type { SDKRegistryEntry } from "../registry.js";
+
+const registry: SDKRegistryEntry[] = [
+  {
+    provider: "stripe",
+    display_name: "Stripe",
+    patterns: {},
+    known_api_base_urls: ["https://api.stripe.com"],
+    ip_ranges: ["198.51.100.0/24"],
+    user_agents: [{ pattern: "PythonBindings/([0-9][\\w.-]*)", sdk_package: "stripe", ecosystem: "pypi" }],
+  },
+  {
+    provider: "azure",
+    display_name: "Azure",
+    patterns: {},
+    domains: ["*.azure.com"],
+  },
+  {
+    provider: "azure-openai",
+    display_name: "Azure OpenAI",
+    patterns: {},
+    domains: ["*.openai.azure.com"],
+  },
+];
+
+describe("createVendorMatcher", () => {
+  it("matches base URL hosts and domains, preferring the longest pattern", () => {
+    const match = createVendorMatcher(registry);
+    expect(match("api.stripe.com")).toBe("stripe");
+    expect(match("API.Stripe.com.")).toBe("stripe");
+    expect(match("myvault.vault.azure.com")).toBe("azure");
+    expect(match("acme.openai.azure.com")).toBe("azure-openai");
+    expect(match("example.com")).toBeNull();
+  });
+});
+
+describe("address helpers", () => {
+  it("recognizes private and loopback addresses", () => {
+    expect(isPrivateIp("10.1.2.3")).toBe(true);
+    expect(isPrivateIp("172.20.0.1")).toBe(true);
+    expect(isPrivateIp("192.168.1.1")).toBe(true);
+    expect(isPrivateIp("127.0.0.1")).toBe(true);
+    expect(isPrivateIp("::1")).toBe(true);
+    expect(isPrivateIp("fd00::1")).toBe(true);
+    expect(isPrivateIp("::ffff:10.0.0.1")).toBe(true);
+    expect(isPrivateIp("52.1.2.3")).toBe(false);
+    expect(isPrivateIp("2600:1f18::1")).toBe(false);
+  });
+
+  it("builds URLs from host and port", () => {
+    expect(observationUrl({ source: "agent", host: "api.stripe.com", port: 443, timestamp: "" })).toBe("https://api.stripe.com");
+    expect(observationUrl({ source: "agent", ip: "52.1.2.3", port: 5432, timestamp: "" })).toBe("tcp://52.1.2.3:5432");
+    expect(observationUrl({ source: "agent", ip: "2600::1", port: 80, timestamp: "" })).toBe("http://[2600::1]");
+    expect(observationUrl({ source: "agent", timestamp: "" })).toBeNull();
+  });
+});
+
+describe("aggregateObservations", () => {
+  it("folds observations per destination with counts and time range", () => {
+    const apis = aggregateObservations(
+      [
+        { source: "agent", host: "api.stripe.com", ip: "1.1.1.1", port: 443, process: "node", timestamp: "2026-10-14T10:00:05.000Z" },
+        { source: "agent", host: "api.stripe.com", ip: "1.1.1.2", port: 443, process: "worker", timestamp: "2026-10-14T10:00:01.000Z" },
+        { source: "agent", ip: "52.1.2.3", port: 443, process: "node", timestamp: "2026-10-14T10:00:02.000Z", count: 3, last_seen: "2026-10-14T10:00:09.000Z" },
+      ],
+      createVendorMatcher(registry),
+    );
+
+    expect(apis).toHaveLength(2);
+    expect(apis[0]).toMatchObject({
+      url: "https://api.stripe.com",
+      provider: "stripe",
+      rule_id: "TW-STRIPE-API",
+      usage_count: 2,
+      confidence: "high",
+      locations: [{ file: "runtime:agent", line: 1 }],
+      runtime: {
+        source: "agent",
+        count: 2,
+        first_seen: "2026-10-14T10:00:01.000Z",
+        last_seen: "2026-10-14T10:00:05.000Z",
+        processes: ["node", "worker"],
+      },
+    });
+    expect(apis[1]).toMatchObject({
+      url: "https://52.1.2.3",
+      provider: null,
+      rule_id: "TW-UNCLASSIFIED-ENDPOINT",
+      usage_count: 3,
+      confidence: "medium",
+      runtime: { first_seen: "2026-10-14T10:00:02.000Z", last_seen: "2026-10-14T10:00:09.000Z" },
+    });
+  });
+
+  it("counts requests per SDK version from User-Agents", () => {
+    const ts = "2026-10-14T10:00:00.000Z";
+    const apis = aggregateObservations(
+      [
+        { source: "proxy", host: "api.stripe.com", method: "POST", path: "/v1/charges", user_agent: "Stripe/v1 PythonBindings/7.1.0", timestamp: ts },
+        { source: "proxy", host: "api.stripe.com", method: "POST", path: "/v1/charges", user_agent: "Stripe/v1 PythonBindings/7.1.0", timestamp: ts, count: 2 },
+        { source: "proxy", host: "api.stripe.com", method: "POST", path: "/v1/charges", user_agent: "Stripe/v1 PythonBindings/5.4.0", timestamp: ts },
+        { source: "proxy", host: "api.stripe.com", method: "POST", path: "/v1/charges", user_agent: "curl/8.4.0", timestamp: ts },
+        // A mock or gateway host the catalog doesn't list is named by the SDK calling it
+        { source: "proxy", host: "stripe-mock.staging.acme.io", method: "GET", path: "/v1/customers", user_agent: "Stripe/v1 PythonBindings/7.1.0", timestamp: ts },
+      ],
+      createVendorMatcher(registry),
+      undefined,
+      createUserAgentMatcher(registry),
+    );
+
+    expect(apis[0]!.runtime).toMatchObject({
+      count: 5,
+      sdks: [
+        { sdk_package: "stripe", ecosystem: "pypi", version: "7.1.0", count: 3 },
+        { sdk_package: "stripe", ecosystem: "pypi", version: "5.4.0", count: 1 },
+      ],
+    });
+    expect(apis[1]).toMatchObject({ url: "https://stripe-mock.staging.acme.io/v1/customers", provider: "stripe" });
+  });
+
+  it("builds a TDM with only apis populated", () => {
+    const tdm = buildRuntimeTDM(
+      [{ source: "agent", host: "api.stripe.com", port: 443, timestamp: "2026-10-14T10:00:00.000Z" }],
+      registry,
+      { duration: 1000, catalogVersion: "2026.10.1" },
+    );
+    expect(tdm.apis).toHaveLength(1);
+    expect(tdm.packages).toEqual([]);
+    expect(tdm.metadata).toMatchObject({ total_dependencies_found: 1, scan_duration_ms: 1000, catalog_version: "2026.10.1" });
+  });
+
+  it("attributes bare IPs through catalog ip_ranges, but never overrides a hostname", () => {
+    const tdm = buildRuntimeTDM(
+      [
+        { source: "flow", ip: "198.51.100.7", port: 443, timestamp: "2026-10-14T10:00:00.000Z" },
+        { source: "flow", host: "api.partner.io", ip: "198.51.100.8", port: 443, timestamp: "2026-10-14T10:00:00.000Z" },
+      ],
+      registry,
+      { duration: 1000 },
+    );
+    expect(tdm.apis.map((a) => [a.url, a.provider, a.confidence])).toEqual([
+      ["https://198.51.100.7", "stripe", "medium"],
+      ["https://api.partner.io", null, "high"],
+    ]);
+  });
+});
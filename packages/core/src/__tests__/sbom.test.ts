@@ -0,0 +1,75 @@
+import { describe, it, expect } from "vitest";
+import { readFile } from "node:fs/promises";
+import { resolve } from "node:path";
+import { parsePurl, parseSbom, buildSbomTDM } from "../sbom.js";
+import { loadSDKRegistry } from "../registry.js";
+
+const fixturesRoot = resolve(__dirname, "../../../../fixtures/sbom");
+const registriesDir = resolve(__dirname, "../../../../registries");
+
+const fixture = (name: string) => readFile(resolve(fixturesRoot, name), "utf-8");
+
+describe("parsePurl", () => {
+  it("maps package URLs to TDM package names and ecosystems", () => {
+    expect(parsePurl("pkg:npm/%40aws-sdk/client-s3@3.490.0")).toMatchObject({ name: "@aws-sdk/client-s3", ecosystem: "npm", version: "3.490.0" });
+    expect(parsePurl("pkg:maven/com.stripe/stripe-java@24.3.0?type=jar")).toMatchObject({ name: "com.stripe:stripe-java", ecosystem: "maven" });
+    expect(parsePurl("pkg:golang/github.com/stripe/stripe-go/v76@v76.8.0")).toMatchObject({ name: "github.com/stripe/stripe-go/v76", ecosystem: "go" });
+    expect(parsePurl("pkg:pypi/Google_Cloud.Storage@2.14.0")).toMatchObject({ name: "google-cloud-storage", ecosystem: "pypi" });
+    expect(parsePurl("pkg:composer/stripe/stripe-php@13.0.0")).toMatchObject({ name: "stripe/stripe-php", ecosystem: "packagist" });
+    expect(parsePurl("not-a-purl")).toBeNull();
+  });
+});
+
+describe("parseSbom", () => {
+  it("reads nested CycloneDX JSON components and counts those without a purl", async () => {
+    const sbom = parseSbom(await fixture("checkout.cdx.json"));
+    expect(sbom.format).toBe("cyclonedx-json");
+    expect(sbom.subject).toBe("checkout-service");
+    expect(sbom.components.map((c) => c.name)).toEqual(["stripe", "@aws-sdk/client-s3", "express", "body-parser"]);
+    expect(sbom.skipped).toBe(1);
+  });
+
+  it("reads SPDX JSON and tag-value, leaving out the described package", async () => {
+    const json = parseSbom(await fixture("billing.spdx.json"));
+    expect(json).toMatchObject({ format: "spdx-json", subject: "billing-worker", skipped: 0 });
+    expect(json.components.map((c) => c.name)).toEqual(["com.stripe:stripe-java", "github.com/stripe/stripe-go/v76", "requests"]);
+
+    const tag = parseSbom(await fixture("search.spdx"));
+    expect(tag).toMatchObject({ format: "spdx-tag", subject: "search-api", skipped: 1 });
+    expect(tag.components.map((c) => c.name)).toEqual(["openai"]);
+  });
+
+  it("reads CycloneDX XML without the subject's own purl", async () => {
+    const sbom = parseSbom(await fixture("search.cdx.xml"));
+    expect(sbom).toMatchObject({ format: "cyclonedx-xml", subject: "search-api" });
+    expect(sbom.components.map((c) => c.name)).toEqual(["openai", "flask"]);
+  });
+
+  it("rejects documents that aren't SBOMs", () => {
+    expect(() => parseSbom('{"name": "package.json"}')).toThrow(/CycloneDX/);
+  });
+});
+
+describe("buildSbomTDM", () => {
+  it("lists every component as a package and vendor SDKs as SDK dependencies", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const { tdm } = buildSbomTDM(await fixture("checkout.cdx.json"), registry, { file: "sboms/checkout.cdx.json" });
+    expect(tdm.metadata.repository).toBe("checkout-service");
+    expect(tdm.metadata.artifact).toMatchObject({ type: "sbom", reference: "sboms/checkout.cdx.json" });
+    expect(tdm.packages.map((p) => `${p.name}@${p.current_version}`)).toEqual(
+      expect.arrayContaining(["stripe@14.12.0", "@aws-sdk/client-s3@3.490.0", "express@4.18.2", "body-parser@1.20.1"]),
+    );
+    expect(tdm.sdks.map((s) => [s.provider, s.sdk_package, s.locations[0]!.line])).toEqual([
+      ["stripe", "stripe", 17],
+      ["aws-s3", "@aws-sdk/client-s3", 24],
+    ]);
+  });
+
+  it("matches Go modules past their major-version suffix and Maven coordinates", async () => {
+    const registry = await loadSDKRegistry(registriesDir);
+    const { tdm } = buildSbomTDM(await fixture("billing.spdx.json"), registry, { file: "billing.spdx.json", service: "billing" });
+    expect(tdm.metadata.repository).toBe("billing");
+    expect(tdm.sdks.map((s) => s.sdk_package).sort()).toEqual(["com.stripe:stripe-java", "github.com/stripe/stripe-go/v76"]);
+    expect(tdm.sdks.every((s) => s.provider === "stripe")).toBe(true);
+  });
+});
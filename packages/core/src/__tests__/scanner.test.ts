@@ -1,5 +1,7 @@
-import { describe, it, expect } from "vitest";
-import { resolve } from "node:path";
+import { describe, it, expect, vi, afterEach } from "vitest";
+import { mkdtemp, mkdir, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join, resolve } from "node:path";
 import { scan } from "../scanner.js";
 import type { LanguageAnalyzerPlugin, DependencyEntry } from "../plugin.js";
 
@@ -148,5 +150,210 @@ describe("scan()", () => {
     // Should have errors logged but scan should complete
     expect(result.errors.length).toBeGreaterThan(0);
     expect(result.errors[0]!.error).toBe("simulated crash");
+    expect(result.errors[0]).toMatchObject({ stage: "analyze", detector: "python" });
+    expect(result.tdm.errors![0]).toMatchObject({ stage: "analyze", detector: "python", message: "simulated crash" });
+    expect(result.tdm.errors![0]!.file).not.toMatch(/^\//);
+  });
+
+  it("keeps the other manifests' packages when one manifest is malformed", async () => {
+    const root = await mkdtemp(join(tmpdir(), "thirdwatch-scan-test-"));
+    try {
+      await writeFile(join(root, "requirements.txt"), "stripe==7.9.0\n");
+      await mkdir(join(root, "legacy"));
+      await writeFile(join(root, "legacy", "requirements.txt"), "<<<<<<< HEAD\n");
+      const strictPlugin: LanguageAnalyzerPlugin = {
+        ...stubPythonPlugin,
+        async analyzeManifests(manifestFiles, scanRoot) {
+          const { readFile } = await import("node:fs/promises");
+          for (const f of manifestFiles) {
+            if ((await readFile(f, "utf-8")).startsWith("<<<<<<<")) throw new Error(`merge conflict in ${f}`);
+          }
+          return stubPythonPlugin.analyzeManifests!(manifestFiles, scanRoot);
+        },
+      };
+
+      const result = await scan({ root, plugins: [strictPlugin], resolveEnv: false, secretHistory: false });
+
+      expect(result.tdm.packages.map((p) => p.name)).toEqual(["stripe"]);
+      expect(result.tdm.errors).toEqual([
+        {
+          file: join("legacy", "requirements.txt"),
+          stage: "manifests",
+          detector: "python",
+          message: `merge conflict in ${join(root, "legacy", "requirements.txt")}`,
+        },
+      ]);
+    } finally {
+      await rm(root, { recursive: true, force: true });
+    }
+  });
+
+  it("extracts vendor URLs from files no plugin supports", async () => {
+    const root = await mkdtemp(join(tmpdir(), "thirdwatch-scan-test-"));
+    try {
+      await writeFile(join(root, "charge.lua"), 'local res = http.request("https://api.stripe.com/v1/charges")\n');
+      await writeFile(join(root, "logo.png"), "https://api.stripe.com/v1/charges\n");
+
+      const registriesDir = resolve(__dirname, "../../../../registries");
+      const result = await scan({ root, plugins: [stubPythonPlugin], resolveEnv: false, secretHistory: false, registriesDir });
+      expect(result.tdm.apis).toEqual([
+        expect.objectContaining({
+          url: "https://api.stripe.com/v1/charges",
+          provider: "stripe",
+          confidence: "medium",
+          locations: [expect.objectContaining({ file: "charge.lua", line: 1, usage: "fallback" })],
+        }),
+      ]);
+
+      const off = await scan({ root, plugins: [stubPythonPlugin], resolveEnv: false, secretHistory: false, registriesDir, fallback: false });
+      expect(off.tdm.apis).toEqual([]);
+    } finally {
+      await rm(root, { recursive: true, force: true });
+    }
+  });
+
+  it("tags findings with the services mapped in .thirdwatch.yml", async () => {
+    const root = await mkdtemp(join(tmpdir(), "thirdwatch-scan-test-"));
+    try {
+      await writeFile(join(root, ".thirdwatch.yml"), "services:\n  services/payments: payments-api\n");
+      await mkdir(join(root, "services", "payments"), { recursive: true });
+      await writeFile(join(root, "services", "payments", "requirements.txt"), "stripe==7.9.0\n");
+      await writeFile(join(root, "services", "payments", "pay.py"), "import stripe\n");
+
+      const result = await scan({ root, plugins: [stubPythonPlugin], resolveEnv: false, secretHistory: false });
+      expect(result.tdm.packages[0]).toMatchObject({ name: "stripe", services: ["payments-api"] });
+    } finally {
+      await rm(root, { recursive: true, force: true });
+    }
+  });
+
+  it("attaches redacted context lines from context_lines, overridable per scan", async () => {
+    const root = await mkdtemp(join(tmpdir(), "thirdwatch-scan-test-"));
+    try {
+      await writeFile(join(root, ".thirdwatch.yml"), "context_lines: 1\n");
+      await writeFile(
+        join(root, "pay.py"),
+        'TOKEN = "sk_live_abcdefghijklmnop1234"\nrequests.post("https://api.acme-billing.io/v1/charge")\nprint("done")\n',
+      );
+
+      const result = await scan({ root, plugins: [stubPythonPlugin], resolveEnv: false, secretHistory: false });
+      expect(result.tdm.apis[0]!.locations[0]!.snippet).toEqual({
+        start_line: 1,
+        lines: ['TOKEN = "[REDACTED]"', 'requests.post("https://api.acme-billing.io/v1/charge")', 'print("done")'],
+      });
+
+      const off = await scan({ root, plugins: [stubPythonPlugin], resolveEnv: false, secretHistory: false, contextLines: 0 });
+      expect(off.tdm.apis[0]!.locations[0]).not.toHaveProperty("snippet");
+    } finally {
+      await rm(root, { recursive: true, force: true });
+    }
+  });
+
+  it("reports findings under the canonical slug from vendor_aliases", async () => {
+    const root = await mkdtemp(join(tmpdir(), "thirdwatch-scan-test-"));
+    try {
+      await writeFile(
+        join(root, ".thirdwatch.yml"),
+        [
+          "vendor_aliases:",
+          "  aws-ses: [mailer]",
+          "rules:",
+          "  - id: internal-mailer",
+          "    vendor: mailer",
+          "    match:",
+          "      code: 'Mailer\\.send\\('",
+          "",
+        ].join("\n"),
+      );
+      await writeFile(join(root, "notify.py"), 'Mailer.send("hi")\n');
+
+      const registriesDir = resolve(__dirname, "../../../../registries");
+      const result = await scan({ root, plugins: [stubPythonPlugin], resolveEnv: false, secretHistory: false, registriesDir });
+      expect(result.tdm.sdks).toEqual([expect.objectContaining({ provider: "aws-ses", category: "email" })]);
+    } finally {
+      await rm(root, { recursive: true, force: true });
+    }
+  });
+
+  it("gives REST calls to a catalog host the vendor's rule ID", async () => {
+    const root = await mkdtemp(join(tmpdir(), "thirdwatch-scan-test-"));
+    try {
+      await writeFile(join(root, ".thirdwatch.yml"), "rule_settings:\n  TW-HTTP-URL: off\n");
+      await writeFile(
+        join(root, "pay.py"),
+        'requests.post("https://api.stripe.com/v1/charges")\nrequests.get("https://api.acme-billing.io/v1/invoices")\n',
+      );
+
+      const registriesDir = resolve(__dirname, "../../../../registries");
+      const result = await scan({ root, plugins: [stubPythonPlugin], resolveEnv: false, secretHistory: false, registriesDir });
+      expect(result.tdm.apis).toEqual([
+        expect.objectContaining({ url: "https://api.stripe.com/v1/charges", provider: "stripe", rule_id: "TW-STRIPE-API" }),
+      ]);
+    } finally {
+      await rm(root, { recursive: true, force: true });
+    }
+  });
+
+  it("omits the errors section when nothing failed", async () => {
+    const result = await scan({
+      root: resolve(fixturesRoot, "python-app"),
+      plugins: [stubPythonPlugin],
+      resolveEnv: false,
+    });
+    expect(result.tdm.errors).toBeUndefined();
+  });
+
+  describe("LLM classification", () => {
+    afterEach(() => {
+      vi.unstubAllGlobals();
+      delete process.env["THIRDWATCH_LLM_API_KEY"];
+      delete process.env["OPENAI_API_KEY"];
+    });
+
+    const answer = { choices: [{ message: { content: JSON.stringify({ classifications: [] }) } }] };
+
+    it("sends no API key to an endpoint named in the scanned repository's config", async () => {
+      const root = await mkdtemp(join(tmpdir(), "thirdwatch-scan-test-"));
+      try {
+        await writeFile(join(root, ".thirdwatch.yml"), "llm_classification:\n  endpoint: https://llm.attacker.example/v1/chat/completions\n  model: m\n  api_key_env: OPENAI_API_KEY\n");
+        await writeFile(join(root, "requirements.txt"), "acmepay==1.0.0\n");
+        process.env["THIRDWATCH_LLM_API_KEY"] = "tw-key";
+        process.env["OPENAI_API_KEY"] = "sk-real-key";
+        const fetchSpy = vi.fn().mockResolvedValue(new Response(JSON.stringify(answer)));
+        vi.stubGlobal("fetch", fetchSpy);
+
+        await scan({ root, plugins: [stubPythonPlugin], resolveEnv: false, secretHistory: false, llmClassify: true });
+        expect(fetchSpy).toHaveBeenCalledTimes(1);
+        const [url, init] = fetchSpy.mock.calls[0] as [string, RequestInit];
+        expect(url).toBe("https://llm.attacker.example/v1/chat/completions");
+        expect(init.headers).not.toHaveProperty("Authorization");
+      } finally {
+        await rm(root, { recursive: true, force: true });
+      }
+    });
+
+    it("sends the key only to the endpoint the caller passes", async () => {
+      const root = await mkdtemp(join(tmpdir(), "thirdwatch-scan-test-"));
+      try {
+        await writeFile(join(root, ".thirdwatch.yml"), "llm_classification:\n  endpoint: https://llm.attacker.example/v1/chat/completions\n  model: m\n");
+        await writeFile(join(root, "requirements.txt"), "acmepay==1.0.0\n");
+        const fetchSpy = vi.fn().mockResolvedValue(new Response(JSON.stringify(answer)));
+        vi.stubGlobal("fetch", fetchSpy);
+
+        await scan({
+          root,
+          plugins: [stubPythonPlugin],
+          resolveEnv: false,
+          secretHistory: false,
+          llmClassify: true,
+          llm: { endpoint: "https://llm.internal.acme.io/v1/chat/completions", model: "gpt-4o-mini", apiKey: "tw-key" },
+        });
+        const [url, init] = fetchSpy.mock.calls[0] as [string, RequestInit];
+        expect(url).toBe("https://llm.internal.acme.io/v1/chat/completions");
+        expect(init.headers).toMatchObject({ Authorization: "Bearer tw-key" });
+      } finally {
+        await rm(root, { recursive: true, force: true });
+      }
+    });
   });
 });
@@ -0,0 +1,88 @@
+import { describe, it, expect } from "vitest";
+import { mkdtemp, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { parseCron, nextRun, loadServerConfig, repositoryName } from "../schedule.js";
+
+const at = (iso: string) => new Date(iso);
+
+describe("parseCron", () => {
+  it("rejects malformed expressions", () => {
+    expect(() => parseCron("0 4 * *")).toThrow(/expected 5 fields/);
+    expect(() => parseCron("60 * * * *")).toThrow(/minute "60" is not between 0 and 59/);
+    expect(() => parseCron("0 4 * * mon-sun/0")).toThrow();
+    expect(() => parseCron("0 10-2 * * *")).toThrow(/runs backwards/);
+    expect(() => parseCron("@fortnightly")).toThrow(/expected 5 fields/);
+  });
+
+  it("treats weekday 7 as Sunday", () => {
+    expect([...parseCron("0 0 * * 7").weekdays]).toEqual([0]);
+  });
+});
+
+describe("nextRun", () => {
+  it("steps through minutes and hours", () => {
+    const every15 = parseCron("*/15 9-17 * * *");
+    expect(nextRun(every15, at("2026-03-02T09:07:30Z")).toISOString()).toBe("2026-03-02T09:15:00.000Z");
+    expect(nextRun(every15, at("2026-03-02T17:45:00Z")).toISOString()).toBe("2026-03-03T09:00:00.000Z");
+  });
+
+  it("matches month and weekday names", () => {
+    // 2026-03-02 is a Monday
+    const weekdays = parseCron("30 4 * jan-mar mon-fri");
+    expect(nextRun(weekdays, at("2026-03-06T05:00:00Z")).toISOString()).toBe("2026-03-09T04:30:00.000Z");
+    expect(nextRun(weekdays, at("2026-03-31T05:00:00Z")).toISOString()).toBe("2027-01-01T04:30:00.000Z");
+  });
+
+  it("runs on either day when both day fields are restricted", () => {
+    const either = parseCron("0 0 13 * fri");
+    expect(nextRun(either, at("2026-03-02T00:00:00Z")).toISOString()).toBe("2026-03-06T00:00:00.000Z");
+    expect(nextRun(either, at("2026-03-10T00:00:00Z")).toISOString()).toBe("2026-03-13T00:00:00.000Z");
+  });
+
+  it("requires both day fields when one is a stepped wildcard", () => {
+    // Odd days of the month that are Mondays: 2026-03-09, 2026-03-23
+    const both = parseCron("0 0 */2 * 1");
+    expect(nextRun(both, at("2026-03-02T00:00:00Z")).toISOString()).toBe("2026-03-09T00:00:00.000Z");
+    expect(nextRun(both, at("2026-03-09T00:00:00Z")).toISOString()).toBe("2026-03-23T00:00:00.000Z");
+  });
+
+  it("expands shorthands and rolls over short months", () => {
+    expect(nextRun(parseCron("@weekly"), at("2026-03-04T12:00:00Z")).toISOString()).toBe("2026-03-08T00:00:00.000Z");
+    expect(nextRun(parseCron("0 0 31 * *"), at("2026-04-01T00:00:00Z")).toISOString()).toBe("2026-05-31T00:00:00.000Z");
+    expect(() => nextRun(parseCron("0 0 30 2 *"), at("2026-01-01T00:00:00Z"))).toThrow(/never fires/);
+  });
+});
+
+describe("repositoryName", () => {
+  it("names repositories by host and path", () => {
+    expect(repositoryName({ url: "https://token@github.com/acme/checkout.git" })).toBe("github.com/acme/checkout");
+    expect(repositoryName({ url: "ssh://git@GitLab.example.com:2222/platform/search/" })).toBe("gitlab.example.com/platform/search");
+    expect(repositoryName({ url: "git@github.com:acme/billing.git" })).toBe("github.com/acme/billing");
+    expect(repositoryName({ url: "/srv/git/search.git" })).toBe("search");
+    expect(repositoryName({ url: "C:\\repos\\ledger" })).toBe("ledger");
+    expect(repositoryName({ url: "git@github.com:acme/billing.git", name: "billing" })).toBe("billing");
+  });
+});
+
+describe("loadServerConfig", () => {
+  async function write(content: string): Promise<string> {
+    const path = join(await mkdtemp(join(tmpdir(), "tw-server-")), "thirdwatch-server.yml");
+    await writeFile(path, content);
+    return path;
+  }
+
+  it("reads the schedule and repositories", async () => {
+    const path = await write('schedule: "@daily"\nrepositories:\n  - url: https://github.com/acme/checkout.git\n    branch: main\n');
+    expect(await loadServerConfig(path)).toEqual({
+      schedule: "@daily",
+      repositories: [{ url: "https://github.com/acme/checkout.git", branch: "main" }],
+    });
+  });
+
+  it("rejects option-like values and bad schedules", async () => {
+    await expect(loadServerConfig(await write("repositories:\n  - url: --upload-pack=touch /tmp/x\n"))).rejects.toThrow(/must not start with '-'/);
+    await expect(loadServerConfig(await write('schedule: "daily"\nrepositories:\n  - url: /srv/git/a\n'))).rejects.toThrow(/Invalid cron/);
+    await expect(loadServerConfig(await write("repositories: []\n"))).rejects.toThrow(/Invalid/);
+  });
+});
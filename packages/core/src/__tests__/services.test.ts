@@ -0,0 +1,115 @@
+import { describe, it, expect } from "vitest";
+import type { TDM } from "@thirdwatch/tdm";
+import type { DependencyEntry } from "../plugin.js";
+import { annotateServices, serviceOf, splitByService, vendorsByService } from "../services.js";
+
+const services = {
+  "services/payments": "payments-api",
+  "services/payments/worker/": "payments-worker",
+  "./web": "storefront",
+};
+
+describe("serviceOf", () => {
+  it("picks the longest prefix that covers whole path segments", () => {
+    expect(serviceOf("services/payments/app.ts", services)).toBe("payments-api");
+    expect(serviceOf("services/payments/worker/jobs/charge.py", services)).toBe("payments-worker");
+    expect(serviceOf("web/index.html", services)).toBe("storefront");
+    expect(serviceOf("services/payments-legacy/app.ts", services)).toBeNull();
+    expect(serviceOf("tools/report.ts", services)).toBeNull();
+    expect(serviceOf("tools/report.ts", { ...services, ".": "platform" })).toBe("platform");
+  });
+});
+
+describe("annotateServices", () => {
+  it("tags locations and package manifests", () => {
+    const entries: DependencyEntry[] = [
+      { kind: "package", name: "stripe", ecosystem: "npm", current_version: "15.2.0", manifest_file: "services/payments/package.json", locations: [], usage_count: 0, confidence: "high" },
+      {
+        kind: "sdk",
+        provider: "stripe",
+        sdk_package: "stripe",
+        locations: [
+          { file: "services/payments/pay.ts", line: 3 },
+          { file: "scripts/backfill.ts", line: 9 },
+        ],
+        usage_count: 2,
+        confidence: "high",
+      },
+    ];
+    annotateServices(entries, services);
+
+    expect(entries[0]).toMatchObject({ services: ["payments-api"] });
+    expect(entries[1]!.locations).toEqual([
+      { file: "services/payments/pay.ts", line: 3, service: "payments-api" },
+      { file: "scripts/backfill.ts", line: 9 },
+    ]);
+  });
+});
+
+function monorepo(): TDM {
+  return {
+    version: "1.0",
+    metadata: {
+      scan_timestamp: "2026-10-14T10:00:00.000Z",
+      scanner_version: "0.1.0",
+      repository: "github.com/acme/platform",
+      languages_detected: ["javascript"],
+      total_dependencies_found: 3,
+      scan_duration_ms: 0,
+    },
+    packages: [
+      { name: "stripe", ecosystem: "npm", current_version: "15.2.0", manifest_file: "services/payments/package.json", services: ["payments-api", "storefront"], locations: [], usage_count: 0, confidence: "high" },
+    ],
+    apis: [
+      { url: "https://api.internal/v1/users", first_party: true, locations: [{ file: "web/users.ts", line: 1, service: "storefront" }], usage_count: 1, confidence: "medium" },
+    ],
+    sdks: [
+      {
+        provider: "stripe",
+        sdk_package: "stripe",
+        locations: [
+          { file: "services/payments/pay.ts", line: 3, service: "payments-api" },
+          { file: "web/checkout.ts", line: 12, service: "storefront" },
+          { file: "scripts/backfill.ts", line: 9 },
+        ],
+        usage_count: 3,
+        confidence: "high",
+      },
+    ],
+    infrastructure: [],
+    webhooks: [],
+  };
+}
+
+describe("splitByService", () => {
+  it("gives each service its own locations, and keeps unmapped ones with the repository", () => {
+    const parts = splitByService(monorepo(), "github.com/acme/platform");
+
+    expect([...parts.keys()]).toEqual(["payments-api", "storefront", "github.com/acme/platform"]);
+    expect(parts.get("payments-api")!.sdks).toEqual([
+      expect.objectContaining({ locations: [expect.objectContaining({ file: "services/payments/pay.ts" })], usage_count: 1 }),
+    ]);
+    expect(parts.get("storefront")!.packages.map((p) => p.name)).toEqual(["stripe"]);
+    expect(parts.get("storefront")!.apis).toHaveLength(1);
+    expect(parts.get("github.com/acme/platform")!.sdks[0]!.locations.map((l) => l.file)).toEqual(["scripts/backfill.ts"]);
+  });
+
+  it("returns a scan without services whole", () => {
+    const tdm = monorepo();
+    tdm.packages[0]!.services = [];
+    for (const finding of [...tdm.apis, ...tdm.sdks]) for (const loc of finding.locations) delete loc.service;
+    const parts = splitByService(tdm, "github.com/acme/platform");
+    expect([...parts.keys()]).toEqual(["github.com/acme/platform"]);
+    expect(parts.get("github.com/acme/platform")).toBe(tdm);
+  });
+});
+
+describe("vendorsByService", () => {
+  it("lists third-party vendors per service, unmapped last", () => {
+    expect(vendorsByService(monorepo())).toEqual([
+      { service: "payments-api", vendors: ["stripe"] },
+      { service: "storefront", vendors: ["stripe"] },
+      { service: "(unmapped)", vendors: ["stripe"] },
+    ]);
+  });
+});
@@ -0,0 +1,101 @@
+import { describe, it, expect } from "vitest";
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "../registry.js";
+import { computeSlaReport } from "../sla.js";
+
+function tdm(partial: Partial<TDM>): TDM {
+  return {
+    version: "1.0",
+    metadata: {
+      scan_timestamp: "2026-10-14T10:00:00.000Z",
+      scanner_version: "0.1.0",
+      repository: "github.com/acme/checkout",
+      languages_detected: [],
+      total_dependencies_found: 0,
+      scan_duration_ms: 0,
+    },
+    packages: [],
+    apis: [],
+    sdks: [],
+    infrastructure: [],
+    webhooks: [],
+    ...partial,
+  };
+}
+
+const registry: SDKRegistryEntry[] = [
+  { provider: "stripe", display_name: "Stripe", category: "payments", patterns: {}, domains: ["stripe.com"] },
+  { provider: "adyen", display_name: "Adyen", category: "payments", patterns: {}, sla: { uptime: 99.9, url: "https://example.com/adyen-sla" } },
+  { provider: "openai", display_name: "OpenAI", category: "ai", patterns: {}, sla: { uptime: 99.9, url: "https://example.com/openai-sla", plan: "Scale Tier" } },
+  {
+    provider: "aws",
+    display_name: "Amazon Web Services",
+    patterns: {},
+    domains: ["amazonaws.com"],
+    sla: {
+      uptime: 99.9,
+      url: "https://example.com/aws-sla",
+      services: [{ name: "RDS Multi-AZ", uptime: 99.95, domains: ["*.rds.amazonaws.com"] }],
+    },
+  },
+];
+
+const sdk = (provider: string) => ({
+  provider,
+  sdk_package: provider,
+  locations: [{ file: `${provider}.ts`, line: 1 }],
+  usage_count: 1,
+  confidence: "high" as const,
+});
+
+describe("computeSlaReport", () => {
+  it("multiplies serial commitments and flags single points of failure", () => {
+    const report = computeSlaReport(
+      [tdm({
+        sdks: [sdk("openai"), sdk("stripe")],
+        infrastructure: [
+          {
+            type: "postgresql",
+            connection_ref: "DATABASE_URL",
+            resolved_host: "prod.abc123.us-east-1.rds.amazonaws.com",
+            locations: [{ file: "db.ts", line: 2 }],
+            confidence: "high",
+          },
+        ],
+      })],
+      registry,
+    );
+
+    expect(report.service).toBe("github.com/acme/checkout");
+    expect(report.dependencies.map((d) => [d.vendor, d.scope ?? null, d.uptime])).toEqual([
+      ["openai", null, 99.9],
+      ["aws", "RDS Multi-AZ", 99.95],
+      ["stripe", null, null],
+    ]);
+    // 0.999 × 0.9995
+    expect(report.composite_uptime).toBeCloseTo(99.85, 3);
+    expect(report.monthly_downtime_minutes).toBe(64.8);
+    expect(report.unknown).toEqual(["stripe"]);
+    expect(report.single_points_of_failure).toEqual(["openai", "aws", "stripe"]);
+    expect(report.dependencies[0]).toMatchObject({ plan: "Scale Tier", monthly_downtime_minutes: 43.2 });
+  });
+
+  it("treats same-category vendors as failover and applies assumed uptimes", () => {
+    const report = computeSlaReport(
+      [tdm({ sdks: [sdk("stripe")] }), tdm({ sdks: [sdk("adyen")] })],
+      registry,
+      { service: "checkout", assume: { stripe: 99.99 } },
+    );
+
+    expect(report.service).toBe("checkout");
+    expect(report.single_points_of_failure).toEqual([]);
+    expect(report.dependencies.find((d) => d.vendor === "stripe")).toMatchObject({
+      uptime: 99.99,
+      source: "assumed",
+      alternatives: ["adyen"],
+    });
+    expect(report.composite_uptime).toBe(99.89);
+    // Either processor keeps payments up: 1 - 0.0001 × 0.001
+    expect(report.composite_with_failover).toBe(100);
+  });
+});
@@ -0,0 +1,89 @@
+import { describe, it, expect } from "vitest";
+import { parseProcNetTcp, parseNetstat, parseLsof, parseConntrack, outboundObservations } from "../snapshot.js";
+
+const now = () => "2026-10-14T10:00:00.000Z";
+
+describe("parseProcNetTcp", () => {
+  it("decodes IPv4 and IPv6 socket tables", () => {
+    const v4 = [
+      "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode",
+      "   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1111 1",
+      "   1: 0501000A:C822 03020134:01BB 01 00000000:00000000 02:000A7D8C 00000000  1000        0 2222 1",
+    ].join("\n");
+    expect(parseProcNetTcp(v4)).toEqual([
+      { localIp: "0.0.0.0", localPort: 8080, remoteIp: "0.0.0.0", remotePort: 0, state: "LISTEN", inode: "1111" },
+      { localIp: "10.0.1.5", localPort: 51234, remoteIp: "52.1.2.3", remotePort: 443, state: "ESTABLISHED", inode: "2222" },
+    ]);
+
+    const v6 = [
+      "  sl  local_address                         remote_address                        st",
+      "   0: 0000000000000000FFFF00000501000A:C822 0000000000000000FFFF000003020134:01BB 01 00000000:00000000 00:00000000 00000000  1000 0 3333 1",
+      "   1: 00000000000000000000000001000000:C823 00470626000000000000000001000000:01BB 02 00000000:00000000 00:00000000 00000000  1000 0 4444 1",
+    ].join("\n");
+    expect(parseProcNetTcp(v6).map((e) => [e.localIp, e.remoteIp, e.state])).toEqual([
+      ["10.0.1.5", "52.1.2.3", "ESTABLISHED"],
+      ["::1", "2606:4700::1", "SYN_SENT"],
+    ]);
+  });
+});
+
+describe("parseNetstat", () => {
+  it("reads Linux, BSD, and Windows output", () => {
+    expect(parseNetstat("tcp        0      0 10.0.1.5:51234          52.1.2.3:443            ESTABLISHED 1234/node")).toEqual([
+      { localIp: "10.0.1.5", localPort: 51234, remoteIp: "52.1.2.3", remotePort: 443, state: "ESTABLISHED", pid: 1234, process: "node" },
+    ]);
+    expect(parseNetstat("tcp4       0      0  192.168.1.5.51234      140.82.112.3.443       ESTABLISHED")[0]).toMatchObject({
+      localIp: "192.168.1.5", remoteIp: "140.82.112.3", remotePort: 443,
+    });
+    expect(parseNetstat("  TCP    0.0.0.0:135            0.0.0.0:0              LISTENING       900")[0]).toMatchObject({
+      localPort: 135, state: "LISTEN", pid: 900,
+    });
+  });
+});
+
+describe("parseLsof", () => {
+  it("reads connected and listening sockets", () => {
+    const text = [
+      "COMMAND     PID USER   FD   TYPE             DEVICE SIZE/OFF NODE NAME",
+      "Google\\x20Chrome 812 me 30u IPv4 0x1 0t0 TCP 192.168.1.5:51234->142.250.1.1:443 (ESTABLISHED)",
+      "node       4242 me   21u  IPv6 0x2      0t0  TCP [2001:db8::5]:51300->[2606:4700::1]:443 (ESTABLISHED)",
+      "node       4242 me   22u  IPv4 0x3      0t0  TCP *:3000 (LISTEN)",
+    ].join("\n");
+    expect(parseLsof(text)).toEqual([
+      { localIp: "192.168.1.5", localPort: 51234, remoteIp: "142.250.1.1", remotePort: 443, state: "ESTABLISHED", pid: 812, process: "Google Chrome" },
+      { localIp: "2001:db8::5", localPort: 51300, remoteIp: "2606:4700::1", remotePort: 443, state: "ESTABLISHED", pid: 4242, process: "node" },
+    ]);
+  });
+});
+
+describe("parseConntrack", () => {
+  it("keeps the original direction of open TCP flows and QUIC", () => {
+    const text = [
+      "ipv4     2 tcp      6 431999 ESTABLISHED src=10.8.0.4 dst=52.1.2.3 sport=51234 dport=443 src=52.1.2.3 dst=203.0.113.5 sport=443 dport=51234 [ASSURED] mark=0 use=2",
+      "tcp      6 110 TIME_WAIT src=10.8.0.4 dst=52.1.2.4 sport=51235 dport=443 src=52.1.2.4 dst=203.0.113.5 sport=443 dport=51235 [ASSURED] mark=0 use=1",
+      "udp      17 29 src=10.8.0.4 dst=142.250.1.1 sport=40000 dport=443 src=142.250.1.1 dst=203.0.113.5 sport=443 dport=40000 mark=0 use=1",
+      "udp      17 29 src=10.8.0.4 dst=10.96.0.10 sport=40001 dport=53 src=10.96.0.10 dst=10.8.0.4 sport=53 dport=40001 mark=0 use=1",
+    ].join("\n");
+    expect(parseConntrack(text).map((e) => `${e.localIp}>${e.remoteIp}:${e.remotePort}`)).toEqual([
+      "10.8.0.4>52.1.2.3:443",
+      "10.8.0.4>142.250.1.1:443",
+    ]);
+  });
+});
+
+describe("outboundObservations", () => {
+  it("drops inbound, private, and duplicate connections", () => {
+    const obs = outboundObservations(
+      [
+        { localIp: "0.0.0.0", localPort: 8080, remoteIp: "0.0.0.0", remotePort: 0, state: "LISTEN" },
+        { localIp: "10.0.1.5", localPort: 8080, remoteIp: "198.51.100.7", remotePort: 50000, state: "ESTABLISHED" },
+        { localIp: "10.0.1.5", localPort: 51234, remoteIp: "52.1.2.3", remotePort: 443, state: "ESTABLISHED", process: "node" },
+        { localIp: "10.0.1.5", localPort: 51234, remoteIp: "52.1.2.3", remotePort: 443, state: "ESTABLISHED" },
+        { localIp: "10.0.1.5", localPort: 51240, remoteIp: "10.0.9.9", remotePort: 5432, state: "ESTABLISHED" },
+        { localIp: "198.51.100.7", localPort: 40000, remoteIp: "203.0.113.5", remotePort: 22, state: "ESTABLISHED" },
+      ],
+      { now, localAddresses: new Set(["203.0.113.5"]) },
+    );
+    expect(obs).toEqual([{ source: "snapshot", ip: "52.1.2.3", port: 443, timestamp: now(), process: "node" }]);
+  });
+});
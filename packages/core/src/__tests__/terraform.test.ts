@@ -0,0 +1,36 @@
+import { describe, it, expect } from "vitest";
+import { readFile } from "node:fs/promises";
+import { resolve } from "node:path";
+import { detectTerraformBackends, isTerraformConfig } from "../terraform.js";
+
+const fixturesRoot = resolve(__dirname, "../../../../fixtures/terraform");
+
+async function backends(file: string): Promise<Array<[string, number]>> {
+  const source = await readFile(resolve(fixturesRoot, file), "utf-8");
+  return detectTerraformBackends(source, file).flatMap((e) =>
+    e.kind === "api" ? [[e.url, e.locations[0]!.line] as [string, number]] : [],
+  );
+}
+
+describe("isTerraformConfig", () => {
+  it("selects .tf files", () => {
+    expect(isTerraformConfig("infra/main.tf")).toBe(true);
+    expect(isTerraformConfig("infra/terraform.tfvars")).toBe(false);
+    expect(isTerraformConfig("main.go")).toBe(false);
+  });
+});
+
+describe("detectTerraformBackends", () => {
+  it("reports a cloud block as HCP Terraform", async () => {
+    expect(await backends("main.tf")).toEqual([["https://app.terraform.io/api/v2", 4]]);
+  });
+
+  it("uses the remote backend's hostname and skips comments and other backends", async () => {
+    expect(await backends("legacy.tf")).toEqual([["https://tfe.acme-corp.com/api/v2", 3]]);
+  });
+
+  it("reads a hostname on the block's opening line", () => {
+    const entries = detectTerraformBackends('backend "remote" { hostname = "tfe.internal" }\n', "x.tf");
+    expect(entries.map((e) => (e.kind === "api" ? e.url : null))).toEqual(["https://tfe.internal/api/v2"]);
+  });
+});
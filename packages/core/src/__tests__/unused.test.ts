@@ -0,0 +1,67 @@
+import { describe, it, expect } from "vitest";
+import { readFile } from "node:fs/promises";
+import { resolve } from "node:path";
+import type { DependencyEntry } from "../plugin.js";
+import { flagUnusedIntegrations } from "../unused.js";
+
+const fixturesRoot = resolve(__dirname, "../../../../fixtures");
+
+function sdk(provider: string, lines: Array<[number, string?]>, file = "main.go"): DependencyEntry {
+  return {
+    kind: "sdk",
+    provider,
+    sdk_package: provider,
+    locations: lines.map(([line, context]) => ({ file, line, ...(context ? { context } : {}) })),
+    usage_count: lines.length,
+    confidence: "high",
+  };
+}
+
+function infra(type: "postgresql" | "redis", line: number): DependencyEntry {
+  return { kind: "infrastructure", type, connection_ref: type, locations: [{ file: "main.go", line }], confidence: "high" };
+}
+
+const unusedOf = (entries: DependencyEntry[]) =>
+  entries.flatMap((e) => (e.kind === "sdk" || e.kind === "infrastructure") && e.unused ? [e.kind === "sdk" ? e.provider : e.type] : []);
+
+describe("flagUnusedIntegrations", () => {
+  it("flags clients in go-app/main.go that are constructed and discarded", async () => {
+    const source = await readFile(resolve(fixturesRoot, "go-app/main.go"), "utf-8");
+    const entries = [
+      sdk("aws-s3", [[1, 'import "github.com/aws/aws-sdk-go-v2/service/s3"'], [36]]),
+      sdk("stripe", [[1, 'import "github.com/stripe/stripe-go/v78"'], [42]]),
+      infra("postgresql", 46),
+      infra("redis", 50),
+      sdk("openai", [[1, 'import "github.com/sashabaranov/go-openai"'], [54]]),
+    ];
+    flagUnusedIntegrations(entries, source, "main.go");
+
+    // charge.New(params) is a call, not a constructor; the pgx connection is only closed
+    expect(unusedOf(entries)).toEqual(["aws-s3", "postgresql", "redis", "openai"]);
+  });
+
+  it("counts any other reference to the client as use", () => {
+    const source = [
+      "from openai import OpenAI",
+      "",
+      "client = OpenAI(api_key=settings.OPENAI_API_KEY)",
+      "",
+      "def summarize(text):",
+      "    return client.chat.completions.create(model=MODEL, messages=[text])",
+    ].join("\n");
+    const entries = [sdk("openai", [[1], [3]], "app.py")];
+    flagUnusedIntegrations(entries, source, "app.py");
+    expect(unusedOf(entries)).toEqual([]);
+  });
+
+  it("ignores commented-out calls and clients kept on an object", () => {
+    const source = [
+      "const stripe = new Stripe(process.env.STRIPE_SECRET_KEY);",
+      "// await stripe.charges.create({ amount });",
+      "this.mailer = new Resend(process.env.RESEND_API_KEY);",
+    ].join("\n");
+    const entries = [sdk("stripe", [[1]], "app.ts"), sdk("resend", [[3]], "app.ts")];
+    flagUnusedIntegrations(entries, source, "app.ts");
+    expect(unusedOf(entries)).toEqual(["stripe"]);
+  });
+});
@@ -0,0 +1,40 @@
+import { describe, it, expect } from "vitest";
+import { buildUsageReports } from "../usage-report.js";
+
+describe("buildUsageReports", () => {
+  it("groups observations by client and destination", () => {
+    const ts = "2026-10-14T10:00:00.000Z";
+    const reports = buildUsageReports(
+      [
+        { source: "agent", host: "api.stripe.com", ip: "52.1.2.3", port: 443, client: "shop/checkout", timestamp: ts },
+        { source: "agent", host: "api.stripe.com", ip: "52.1.2.4", port: 443, client: "shop/checkout", timestamp: ts },
+        { source: "agent", ip: "104.16.1.1", port: 443, timestamp: ts },
+      ],
+      {
+        matchVendor: (h) => (h.endsWith("stripe.com") ? "stripe" : null),
+        matchIp: (ip) => (ip.startsWith("104.16.") ? "cloudflare" : null),
+        windowStart: "2026-10-14T10:00:00.000Z",
+        windowEnd: "2026-10-14T10:01:00.000Z",
+        defaultService: "_node/node-1",
+        environment: "production",
+      },
+    );
+
+    expect(reports).toEqual([
+      {
+        service: "shop/checkout",
+        environment: "production",
+        window_start: "2026-10-14T10:00:00.000Z",
+        window_end: "2026-10-14T10:01:00.000Z",
+        calls: [{ kind: "tcp", host: "api.stripe.com", vendor: "stripe", count: 2, errors: 0, latency_ms_total: 0, latency_ms_max: 0 }],
+      },
+      {
+        service: "_node/node-1",
+        environment: "production",
+        window_start: "2026-10-14T10:00:00.000Z",
+        window_end: "2026-10-14T10:01:00.000Z",
+        calls: [{ kind: "tcp", host: "104.16.1.1", vendor: "cloudflare", count: 1, errors: 0, latency_ms_total: 0, latency_ms_max: 0 }],
+      },
+    ]);
+  });
+});
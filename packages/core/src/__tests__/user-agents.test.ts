@@ -0,0 +1,71 @@
+import { describe, it, expect } from "vitest";
+import { readFile } from "node:fs/promises";
+import { resolve } from "node:path";
+import * as yaml from "js-yaml";
+import type { SDKRegistryEntry } from "../registry.js";
+import { createUserAgentMatcher } from "../user-agents.js";
+
+const V = "([0-9][\\w.-]*)";
+
+const registry: SDKRegistryEntry[] = [
+  {
+    provider: "stripe",
+    display_name: "Stripe",
+    patterns: {},
+    user_agents: [
+      { pattern: `PythonBindings/${V}`, sdk_package: "stripe", ecosystem: "pypi" },
+      { pattern: `GoBindings/${V}`, sdk_package: "github.com/stripe/stripe-go", ecosystem: "go" },
+    ],
+  },
+  {
+    provider: "aws",
+    display_name: "Amazon Web Services",
+    patterns: {},
+    user_agents: [{ pattern: `Boto3/${V}`, sdk_package: "boto3", ecosystem: "pypi" }],
+  },
+  {
+    provider: "aws-s3",
+    display_name: "Amazon S3",
+    patterns: {},
+    user_agents: [{ pattern: "(", sdk_package: "broken" }, { pattern: "md/S3Transfer", sdk_package: "s3transfer", ecosystem: "pypi" }],
+  },
+];
+
+describe("createUserAgentMatcher", () => {
+  const match = createUserAgentMatcher(registry);
+
+  it("names the SDK and version", () => {
+    expect(match("Stripe/v1 PythonBindings/7.1.0")).toEqual({ provider: "stripe", sdk_package: "stripe", ecosystem: "pypi", version: "7.1.0" });
+    expect(match("Stripe/v1 GoBindings/76.2.0")).toMatchObject({ sdk_package: "github.com/stripe/stripe-go", version: "76.2.0" });
+    expect(match("python-requests/2.31.0")).toBeNull();
+  });
+
+  it("prefers the destination vendor's fingerprints and skips invalid patterns", () => {
+    const ua = "Boto3/1.34.2 md/Botocore#1.34.2 md/S3Transfer ua/2.0 os/linux";
+    expect(match(ua)).toMatchObject({ provider: "aws", sdk_package: "boto3", version: "1.34.2" });
+    // No capture group, so no version
+    expect(match(ua, "aws-s3")).toEqual({ provider: "aws-s3", sdk_package: "s3transfer", ecosystem: "pypi" });
+    expect(match(ua, "stripe")).toMatchObject({ provider: "aws" });
+  });
+
+  it("recognizes the User-Agents the built-in catalog's SDKs send", async () => {
+    const sdks = resolve(__dirname, "../../../../registries/sdks");
+    const catalog = await Promise.all(
+      ["stripe", "openai", "anthropic", "aws", "twilio", "sendgrid"].map(
+        async (name) => yaml.load(await readFile(resolve(sdks, `${name}.yml`), "utf8")) as SDKRegistryEntry,
+      ),
+    );
+    const builtIn = createUserAgentMatcher(catalog);
+    const cases: Array<[string, string, string, string]> = [
+      ["Stripe/v1 NodeBindings/14.25.0", "stripe", "npm", "14.25.0"],
+      ["OpenAI/Python 1.40.6", "openai", "pypi", "1.40.6"],
+      ["Anthropic/JS 0.27.3", "@anthropic-ai/sdk", "npm", "0.27.3"],
+      ["aws-sdk-go-v2/1.30.3 os/linux lang/go#1.22.5 md/GOOS#linux md/GOARCH#amd64 api/s3#1.58.2", "github.com/aws/aws-sdk-go-v2", "go", "1.30.3"],
+      ["twilio-node/5.2.2 (linux x64) node/v20.15.0", "twilio", "npm", "5.2.2"],
+      ["sendgrid/6.11.0;python", "sendgrid", "pypi", "6.11.0"],
+    ];
+    for (const [ua, sdk_package, ecosystem, version] of cases) {
+      expect(builtIn(ua), ua).toMatchObject({ sdk_package, ecosystem, version });
+    }
+  });
+});
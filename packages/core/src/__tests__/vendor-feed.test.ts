@@ -0,0 +1,105 @@
+import { describe, it, expect } from "vitest";
+import { createHmac } from "node:crypto";
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "../registry.js";
+import { createVendorMatcher } from "../runtime.js";
+import { diffVendors, vendorFeedRequest } from "../vendor-feed.js";
+
+function tdm(partial: Partial<TDM>): TDM {
+  return {
+    version: "1.0",
+    metadata: {
+      scan_timestamp: "2026-10-14T10:00:00.000Z",
+      scanner_version: "0.1.0",
+      repository: "github.com/acme/checkout",
+      languages_detected: [],
+      total_dependencies_found: 0,
+      scan_duration_ms: 0,
+    },
+    packages: [],
+    apis: [],
+    sdks: [],
+    infrastructure: [],
+    webhooks: [],
+    ...partial,
+  };
+}
+
+const registry: SDKRegistryEntry[] = [
+  { provider: "stripe", display_name: "Stripe", patterns: {}, domains: ["stripe.com"] },
+  { provider: "openai", display_name: "OpenAI", patterns: {}, domains: ["openai.com"] },
+  { provider: "aws-ses", display_name: "AWS SES", aliases: ["Amazon SES"], patterns: {} },
+  { provider: "twilio", display_name: "Twilio", patterns: {} },
+];
+const matchVendor = createVendorMatcher(registry);
+
+const sdk = (provider: string) => ({
+  provider,
+  sdk_package: provider,
+  locations: [{ file: `${provider}.ts`, line: 1 }],
+  usage_count: 1,
+  confidence: "high" as const,
+});
+
+const api = (url: string, extra: { provider?: string; first_party?: boolean } = {}) => ({
+  url,
+  locations: [{ file: "client.ts", line: 3 }],
+  usage_count: 1,
+  confidence: "high" as const,
+  ...extra,
+});
+
+describe("diffVendors", () => {
+  it("reports every vendor as added on a repository's first upload", () => {
+    const next = tdm({ sdks: [sdk("stripe")], apis: [api("https://api.openai.com/v1/chat/completions")] });
+    expect(diffVendors(null, next, matchVendor)).toEqual([
+      { vendor: "openai", change: "added" },
+      { vendor: "stripe", change: "added" },
+    ]);
+  });
+
+  it("reports vendors that appeared and left, sorted by vendor", () => {
+    const previous = tdm({ sdks: [sdk("twilio"), sdk("stripe")] });
+    const next = tdm({ sdks: [sdk("stripe")], apis: [api("https://api.openai.com/v1/embeddings")] });
+    expect(diffVendors(previous, next, matchVendor)).toEqual([
+      { vendor: "openai", change: "added" },
+      { vendor: "twilio", change: "removed" },
+    ]);
+  });
+
+  it("reports nothing when the set of vendors is unchanged", () => {
+    const previous = tdm({ sdks: [sdk("stripe"), sdk("stripe")] });
+    const next = tdm({ apis: [api("https://api.stripe.com/v1/charges")] });
+    expect(diffVendors(previous, next, matchVendor)).toEqual([]);
+  });
+
+  it("treats an alias and its slug as one vendor, and ignores first-party endpoints", () => {
+    const previous = tdm({ sdks: [sdk("aws-ses")] });
+    const next = tdm({
+      apis: [api("https://mail.internal.acme.io/send", { provider: "Amazon SES" }), api("https://api.openai.com/v1/chat", { first_party: true })],
+    });
+    expect(diffVendors(previous, next, matchVendor)).toEqual([]);
+  });
+});
+
+describe("vendorFeedRequest", () => {
+  const events = [{ seq: 7, vendor: "openai", change: "added", repository: "acme/checkout" }];
+
+  it("wraps events in a versioned vendor_change body", () => {
+    const { body, headers } = vendorFeedRequest(events);
+    expect(JSON.parse(body)).toEqual({ version: "1", event: "vendor_change", events });
+    expect(headers).toEqual({ "Content-Type": "application/json" });
+  });
+
+  it("signs the exact body with the subscription secret", () => {
+    const { body, headers } = vendorFeedRequest(events, "feed-secret");
+    const expected = "sha256=" + createHmac("sha256", "feed-secret").update(body).digest("hex");
+    expect(headers["X-Thirdwatch-Signature"]).toBe(expected);
+    expect(vendorFeedRequest(events, "other-secret").headers["X-Thirdwatch-Signature"]).not.toBe(expected);
+  });
+
+  it("leaves the body unsigned without a secret", () => {
+    expect(vendorFeedRequest(events, null).headers).not.toHaveProperty("X-Thirdwatch-Signature");
+    expect(vendorFeedRequest(events, "").headers).not.toHaveProperty("X-Thirdwatch-Signature");
+  });
+});
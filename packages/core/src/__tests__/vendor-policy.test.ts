@@ -0,0 +1,95 @@
+import { describe, it, expect } from "vitest";
+import { gunzipSync } from "node:zlib";
+import type { SDKRegistryEntry } from "../registry.js";
+import { domainPatternRegex, matchesDomain } from "../first-party.js";
+import { buildOpaBundle, buildVendorPolicy, opaData, renderTerraformVars } from "../vendor-policy.js";
+
+const registry: SDKRegistryEntry[] = [
+  {
+    provider: "stripe",
+    display_name: "Stripe",
+    category: "payments",
+    patterns: {},
+    domains: ["stripe.com"],
+    known_api_base_urls: ["https://api.stripe.com/v1", "https://files.stripecdn.net"],
+    ip_ranges: ["3.18.12.63/32"],
+  },
+  { provider: "openai", display_name: "OpenAI", category: "ai", patterns: {}, domains: ["*.openai.com"] },
+  { provider: "pusher", display_name: "Pusher", patterns: {}, domains: ["*.pusher.com"] },
+];
+
+const now = new Date("2026-10-14T10:00:00.000Z");
+
+describe("domainPatternRegex", () => {
+  it("matches the same hosts as matchesDomain", () => {
+    const patterns = ["stripe.com", "*.openai.com", "kafka.*.amazonaws.com", "api.my-co.io"];
+    const hosts = [
+      "stripe.com", "api.stripe.com", "notstripe.com", "openai.com", "api.openai.com",
+      "kafka.us-east-1.amazonaws.com", "x.kafka.eu-west-1.amazonaws.com", "kafka.amazonaws.com",
+      "api.my-co.io", "apixmy-co.io",
+    ];
+    for (const pattern of patterns) {
+      const re = new RegExp(domainPatternRegex(pattern));
+      for (const host of hosts) expect(re.test(host), `${pattern} ~ ${host}`).toBe(matchesDomain(host, pattern));
+    }
+  });
+});
+
+describe("buildVendorPolicy", () => {
+  it("collects approved vendors' domains and CIDRs, and lists slugs the catalog lacks", () => {
+    const policy = buildVendorPolicy(["Stripe", "openai", "acme-internal"], registry, { now });
+    expect(policy.approved_vendors).toEqual(["acme-internal", "openai", "stripe"]);
+    // api.stripe.com is covered by stripe.com; files.stripecdn.net is not
+    expect(policy.vendor_egress["stripe"]).toEqual({
+      display_name: "Stripe",
+      category: "payments",
+      domains: ["files.stripecdn.net", "stripe.com"],
+      cidrs: ["3.18.12.63/32"],
+    });
+    expect(policy.allowed_egress_domains).toEqual(["*.openai.com", "files.stripecdn.net", "stripe.com"]);
+    expect(policy.allowed_egress_cidrs).toEqual(["3.18.12.63/32"]);
+    expect(policy.unknown_vendors).toEqual(["acme-internal"]);
+    expect(policy.vendor_egress["pusher"]).toBeUndefined();
+  });
+});
+
+describe("renderTerraformVars", () => {
+  it("renders lists and a per-vendor map", () => {
+    const tfvars = renderTerraformVars(buildVendorPolicy(["stripe", "acme-internal"], registry, { now }));
+    expect(tfvars).toContain("# Not in the vendor catalog, so no egress is listed: acme-internal");
+    expect(tfvars).toContain('approved_vendors = [\n  "acme-internal",\n  "stripe",\n]');
+    expect(tfvars).toContain('allowed_egress_cidrs = [\n  "3.18.12.63/32",\n]');
+    expect(tfvars).toContain('vendor_egress = {\n  "stripe" = {\n    domains = [\n      "files.stripecdn.net",\n      "stripe.com",\n    ]');
+  });
+
+  it("renders empty values when nothing is approved", () => {
+    const tfvars = renderTerraformVars(buildVendorPolicy([], registry, { now }));
+    expect(tfvars).toContain("approved_vendors = []");
+    expect(tfvars).toContain("vendor_egress = {}");
+  });
+});
+
+describe("buildOpaBundle", () => {
+  it("packs a manifest, the data document, and the policy", () => {
+    const policy = buildVendorPolicy(["stripe", "openai"], registry, { now });
+    const archive = gunzipSync(buildOpaBundle(policy));
+    const files = new Map<string, string>();
+    let offset = 0;
+    while (offset < archive.length && archive[offset] !== 0) {
+      const header = archive.subarray(offset, offset + 512);
+      const name = header.subarray(0, 100).toString("utf8").replace(/\0.*$/s, "");
+      const size = parseInt(header.subarray(124, 136).toString("ascii"), 8);
+      const stored = parseInt(header.subarray(148, 156).toString("ascii"), 8);
+      const sum = [...header].reduce((acc, byte, i) => acc + (i >= 148 && i < 156 ? 32 : byte), 0);
+      expect(stored, name).toBe(sum);
+      files.set(name, archive.subarray(offset + 512, offset + 512 + size).toString("utf8"));
+      offset += 512 + Math.ceil(size / 512) * 512;
+    }
+
+    expect([...files.keys()]).toEqual([".manifest", "thirdwatch/data.json", "thirdwatch/vendors.rego"]);
+    expect(JSON.parse(files.get(".manifest")!)).toEqual({ revision: "2026-10-14T10:00:00.000Z", roots: ["thirdwatch"] });
+    expect(JSON.parse(files.get("thirdwatch/data.json")!)).toEqual(opaData(policy));
+    expect(files.get("thirdwatch/vendors.rego")).toContain("package thirdwatch.vendors");
+    expect(opaData(policy).domain_patterns).toContainEqual({ pattern: "*.openai.com", regex: "^.+\\.openai\\.com$" });
+  });
+});
@@ -0,0 +1,15 @@
+import { describe, it, expect } from "vitest";
+import { createHmac } from "node:crypto";
+import { signPayload } from "../webhook-signature.js";
+
+describe("signPayload", () => {
+  it("signs the exact body as sha256=<hex hmac>", () => {
+    const body = '{"version":"1","event":"egress_anomaly"}';
+    expect(signPayload(body, "s3cret")).toBe("sha256=" + createHmac("sha256", "s3cret").update(body).digest("hex"));
+  });
+
+  it("changes with the secret and with any byte of the body", () => {
+    expect(signPayload("hello", "a")).not.toBe(signPayload("hello", "b"));
+    expect(signPayload("hello", "a")).not.toBe(signPayload("hello ", "a"));
+  });
+});
@@ -0,0 +1,300 @@
+/**
+ * @module agent
+ *
+ * eBPF egress agent behind `thirdwatch agent`. Rather than shipping compiled
+ * BPF objects, the agent drives bpftrace (which must be installed and run as
+ * root) with a small embedded program that reports three event kinds as
+ * tab-separated lines:
+ *
+ *   tcp  <pid> <comm> <daddr> <dport>   kprobe:tcp_connect
+ *   dns  <pid> <comm> <name>            uprobe on libc getaddrinfo()
+ *   sni  <pid> <comm> <name>            uprobe on OpenSSL SSL_ctrl(SET_TLSEXT_HOSTNAME)
+ *
+ * DNS and SNI names are used to put hostnames on the connections; nothing
+ * about payloads is captured.
+ */
+
+import { spawn } from "node:child_process";
+import { lookup } from "node:dns/promises";
+import { createInterface } from "node:readline";
+import type { RuntimeObservation } from "./runtime.js";
+import { isPrivateIp } from "./runtime.js";
+
+export interface AgentScriptOptions {
+  /** Trace getaddrinfo() to name destinations (default: true) */
+  dns?: boolean;
+  /** Trace TLS SNI via OpenSSL to name destinations (default: true) */
+  tls?: boolean;
+  /** Library paths for the uprobes (default: "libc" / "libssl") */
+  libc?: string;
+  libssl?: string;
+}
+
+/** Generate the bpftrace program for the requested probes */
+export function buildAgentScript(options: AgentScriptOptions = {}): string {
+  const { dns = true, tls = true, libc = "libc", libssl = "libssl" } = options;
+  const parts = [
+    `#include <net/sock.h>
+
+kprobe:tcp_connect
+{
+  $sk = (struct sock *)arg0;
+  $family = $sk->__sk_common.skc_family;
+  if ($family == 2 || $family == 10) {
+    if ($family == 2) {
+      $daddr = ntop($sk->__sk_common.skc_daddr);
+    } else {
+      $daddr = ntop($sk->__sk_common.skc_v6_daddr.in6_u.u6_addr8);
+    }
+    $dport = $sk->__sk_common.skc_dport;
+    $dport = ($dport >> 8) | (($dport << 8) & 0x00FF00);
+    printf("tcp\\t%d\\t%s\\t%s\\t%d\\n", pid, comm, $daddr, $dport);
+  }
+}`,
+  ];
+  if (dns) {
+    parts.push(`uprobe:${libc}:getaddrinfo
+{
+  printf("dns\\t%d\\t%s\\t%s\\n", pid, comm, str(arg0));
+}`);
+  }
+  if (tls) {
+    // SSL_set_tlsext_host_name() is a macro for SSL_ctrl(ssl, 55, 0, name)
+    parts.push(`uprobe:${libssl}:SSL_ctrl /arg1 == 55/
+{
+  printf("sni\\t%d\\t%s\\t%s\\n", pid, comm, str(arg3));
+}`);
+  }
+  return parts.join("\n\n") + "\n";
+}
+
+export type AgentEvent =
+  | { kind: "tcp"; pid: number; comm: string; ip: string; port: number }
+  | { kind: "dns" | "sni"; pid: number; comm: string; name: string };
+
+const HOSTNAME_RE = /^[a-z0-9](?:[a-z0-9-]*[a-z0-9])?(?:\.[a-z0-9](?:[a-z0-9-]*[a-z0-9])?)+\.?$/i;
+
+/** Parse one line of bpftrace output; returns null for anything else */
+export function parseAgentLine(line: string): AgentEvent | null {
+  const [kind, pidStr, comm, a, b] = line.split("\t");
+  const pid = Number(pidStr);
+  if (!Number.isInteger(pid) || comm == null || a == null) return null;
+
+  if (kind === "tcp") {
+    const port = Number(b);
+    if (!Number.isInteger(port) || port <= 0 || port > 65535) return null;
+    return { kind, pid, comm, ip: a, port };
+  }
+  if (kind === "dns" || kind === "sni") {
+    if (!HOSTNAME_RE.test(a)) return null;
+    return { kind, pid, comm, name: a.toLowerCase().replace(/\.$/, "") };
+  }
+  return null;
+}
+
+// ---------------------------------------------------------------------------
+// Correlation — attach hostnames to connections
+// ---------------------------------------------------------------------------
+
+export interface AgentCorrelatorOptions {
+  /** Keep connections to private / loopback addresses (default: false) */
+  includePrivate?: boolean;
+  /** Resolve a name to its addresses; defaults to the system resolver */
+  resolve?: (name: string) => Promise<string[]>;
+  /** Name the workload a process belongs to (Kubernetes pod attribution) */
+  clientFor?: (pid: number) => string | undefined;
+  /** Clock, for tests */
+  now?: () => string;
+}
+
+async function systemResolve(name: string): Promise<string[]> {
+  const results = await lookup(name, { all: true });
+  return results.map((r) => r.address);
+}
+
+/**
+ * Turns the raw event stream into observations. Connections are named by
+ * (1) addresses learned from resolving names the host looked up, or (2) the
+ * SNI the same process sent right after connecting.
+ */
+export class AgentCorrelator {
+  readonly observations: RuntimeObservation[] = [];
+  private readonly ipHost = new Map<string, string>();
+  private readonly unnamedByPid = new Map<number, RuntimeObservation>();
+  private readonly resolving = new Set<string>();
+  private readonly pending: Promise<void>[] = [];
+  private readonly includePrivate: boolean;
+  private readonly resolve: (name: string) => Promise<string[]>;
+  private readonly clientFor: ((pid: number) => string | undefined) | undefined;
+  private readonly now: () => string;
+
+  constructor(options: AgentCorrelatorOptions = {}) {
+    this.includePrivate = options.includePrivate ?? false;
+    this.resolve = options.resolve ?? systemResolve;
+    this.clientFor = options.clientFor;
+    this.now = options.now ?? (() => new Date().toISOString());
+  }
+
+  handle(event: AgentEvent): void {
+    switch (event.kind) {
+      case "tcp": {
+        if (!this.includePrivate && isPrivateIp(event.ip)) return;
+        const host = this.ipHost.get(event.ip);
+        const client = this.clientFor?.(event.pid);
+        const obs: RuntimeObservation = {
+          source: "agent",
+          ip: event.ip,
+          port: event.port,
+          process: event.comm,
+          timestamp: this.now(),
+          ...(host ? { host } : {}),
+          ...(client ? { client } : {}),
+        };
+        this.observations.push(obs);
+        if (!host) this.unnamedByPid.set(event.pid, obs);
+        return;
+      }
+      case "sni": {
+        const obs = this.unnamedByPid.get(event.pid);
+        if (obs && obs.ip) {
+          obs.host = event.name;
+          this.ipHost.set(obs.ip, event.name);
+          this.unnamedByPid.delete(event.pid);
+        }
+        return;
+      }
+      case "dns": {
+        if (this.resolving.has(event.name)) return;
+        this.resolving.add(event.name);
+        this.pending.push(
+          this.resolve(event.name)
+            .then((ips) => {
+              for (const ip of ips) this.learn(ip, event.name);
+            })
+            .catch(() => {
+              // Unresolvable from here — the connection stays unnamed
+            }),
+        );
+        return;
+      }
+    }
+  }
+
+  /** Record ip → name and backfill connections that raced the lookup */
+  private learn(ip: string, name: string): void {
+    this.ipHost.set(ip, name);
+    for (const obs of this.observations) {
+      if (!obs.host && obs.ip === ip) obs.host = name;
+    }
+    for (const [pid, obs] of this.unnamedByPid) {
+      if (obs.host) this.unnamedByPid.delete(pid);
+    }
+  }
+
+  /** Wait for outstanding name lookups */
+  async settle(): Promise<void> {
+    await Promise.all(this.pending.splice(0));
+  }
+
+  /**
+   * Hand over the observations collected so far and start a new window.
+   * Learned names are kept, so later connections are still named.
+   */
+  take(): RuntimeObservation[] {
+    const taken = this.observations.splice(0);
+    this.unnamedByPid.clear();
+    return taken;
+  }
+}
+
+// ---------------------------------------------------------------------------
+// Runner
+// ---------------------------------------------------------------------------
+
+export interface EgressAgentOptions extends AgentScriptOptions, AgentCorrelatorOptions {
+  /** bpftrace executable (default: "bpftrace") */
+  bpftrace?: string;
+  /** Stop after this many milliseconds; otherwise run until `signal` aborts */
+  durationMs?: number;
+  signal?: AbortSignal;
+  onEvent?: (event: AgentEvent) => void;
+  /**
+   * Deliver observations in windows of this many milliseconds instead of
+   * keeping them all; used by long-running deployments that push to a server
+   */
+  windowMs?: number;
+  onWindow?: (observations: RuntimeObservation[]) => void | Promise<void>;
+}
+
+/**
+ * Run bpftrace until the duration elapses or the signal aborts, and return
+ * the observed outbound connections. With `windowMs`, observations go to
+ * `onWindow` as each window closes (the last one on exit) and the returned
+ * list is empty. Rejects if bpftrace cannot start or fails to attach its
+ * probes.
+ */
+export async function runEgressAgent(
+  options: EgressAgentOptions = {},
+): Promise<RuntimeObservation[]> {
+  const correlator = new AgentCorrelator(options);
+  const child = spawn(options.bpftrace ?? "bpftrace", ["-e", buildAgentScript(options)], {
+    stdio: ["ignore", "pipe", "pipe"],
+  });
+
+  let stderr = "";
+  child.stderr.on("data", (chunk: Buffer) => {
+    stderr = (stderr + chunk.toString()).slice(-4096);
+  });
+  createInterface({ input: child.stdout }).on("line", (line) => {
+    const event = parseAgentLine(line);
+    if (!event) return;
+    options.onEvent?.(event);
+    correlator.handle(event);
+  });
+
+  let stopping = false;
+  const stop = () => {
+    stopping = true;
+    child.kill("SIGINT");
+  };
+  const timer = options.durationMs != null ? setTimeout(stop, options.durationMs) : undefined;
+  options.signal?.addEventListener("abort", stop, { once: true });
+  if (options.signal?.aborted) stop();
+
+  // Windows are delivered one at a time so a slow push never overlaps the next
+  let delivering = Promise.resolve();
+  const flushWindow = () => {
+    delivering = delivering
+      .then(async () => {
+        await correlator.settle();
+        await options.onWindow?.(correlator.take());
+      })
+      .catch(() => {
+        // onWindow reports its own failures; a lost window must not stop the agent
+      });
+    return delivering;
+  };
+  const windowTimer =
+    options.windowMs != null ? setInterval(() => void flushWindow(), options.windowMs) : undefined;
+
+  try {
+    await new Promise<void>((resolveFn, reject) => {
+      child.on("error", (err) => reject(new Error(`Could not start bpftrace: ${err.message}`)));
+      child.on("exit", (code) => {
+        if (stopping || code === 0) resolveFn();
+        else reject(new Error(`bpftrace exited with code ${code}: ${stderr.trim() || "no output"}`));
+      });
+    });
+  } finally {
+    if (timer) clearTimeout(timer);
+    if (windowTimer) clearInterval(windowTimer);
+    options.signal?.removeEventListener("abort", stop);
+  }
+
+  if (windowTimer) {
+    await flushWindow();
+    return [];
+  }
+  await correlator.settle();
+  return correlator.observations;
+}
@@ -0,0 +1,121 @@
+/**
+ * @module aliases
+ *
+ * One identity per vendor, however it is spelled. Catalog entries list the
+ * names a vendor also goes by (`aliases`), and .thirdwatch.yml can add an
+ * organization's own naming conventions:
+ *
+ *   vendor_aliases:
+ *     aws-ses: ["Amazon SES", mailer, mail-relay.corp.acme.io]
+ *     acme-billing: [billing-gw]
+ *
+ * "AWS SES", "Amazon SES", "amazon_ses", a custom rule's `vendor: mailer`,
+ * and hosts such as email-smtp.eu-west-1.amazonaws.com all resolve to
+ * aws-ses, so repositories that name the vendor differently add up to one
+ * row in organization reports.
+ *
+ * Names are compared normalized (lowercase, punctuation and spaces folded
+ * to "-"). An alias with a dot and no spaces is a hostname pattern and
+ * joins the vendor's `domains` instead. A slug always names its own entry,
+ * even when another entry lists it as an alias.
+ */
+
+import { readFile } from "node:fs/promises";
+import * as yaml from "js-yaml";
+import type { DependencyEntry } from "./plugin.js";
+import type { SDKRegistryEntry } from "./registry.js";
+
+/** Canonical vendor slug → other names and hostname patterns for it */
+export type VendorAliases = Record<string, string[]>;
+
+/** "Amazon SES" → "amazon-ses" */
+export function normalizeVendorName(name: string): string {
+  return name
+    .trim()
+    .toLowerCase()
+    .replace(/[^a-z0-9]+/g, "-")
+    .replace(/^-+|-+$/g, "");
+}
+
+const HOST_ALIAS_RE = /^(?:\*\.)?[a-z0-9-]+(?:\.(?:[a-z0-9-]+|\*))*\.[a-z0-9-]+$/i;
+
+/** Whether an alias is a hostname pattern rather than a name */
+export function isHostAlias(alias: string): boolean {
+  return HOST_ALIAS_RE.test(alias.trim());
+}
+
+/**
+ * The registry with `aliases` merged in: names join each entry's `aliases`,
+ * hostname patterns its `domains`. A slug the catalog doesn't know (an
+ * internal vendor, or a custom rule's) gets an entry of its own, so its
+ * spellings still converge. Entries are copied, never modified.
+ */
+export function applyVendorAliases(registry: SDKRegistryEntry[], aliases: VendorAliases = {}): SDKRegistryEntry[] {
+  if (Object.keys(aliases).length === 0) return registry;
+  const out = registry.map((e) => ({ ...e }));
+  const bySlug = new Map(out.map((e) => [e.provider, e]));
+  for (const [vendor, names] of Object.entries(aliases)) {
+    const slug = normalizeVendorName(vendor);
+    let entry = bySlug.get(slug);
+    if (!entry) {
+      entry = { provider: slug, display_name: vendor, patterns: {} };
+      out.push(entry);
+      bySlug.set(slug, entry);
+    }
+    const hosts = names.filter(isHostAlias).map((h) => h.trim().toLowerCase());
+    const others = names.filter((n) => !isHostAlias(n));
+    if (others.length > 0) entry.aliases = [...(entry.aliases ?? []), ...others];
+    if (hosts.length > 0) entry.domains = [...(entry.domains ?? []), ...hosts];
+  }
+  return out;
+}
+
+/**
+ * Normalized name → canonical slug, from every entry's slug, display name,
+ * and aliases. Slugs are indexed last so they can't be taken over; between
+ * other names the first entry to claim one keeps it (`thirdwatch catalog
+ * validate` reports the clash).
+ */
+export function vendorNameIndex(registry: SDKRegistryEntry[]): Map<string, string> {
+  const index = new Map<string, string>();
+  for (const entry of registry) {
+    for (const name of [entry.display_name, ...(entry.aliases ?? [])]) {
+      const key = normalizeVendorName(name);
+      if (key && !index.has(key)) index.set(key, entry.provider);
+    }
+  }
+  for (const entry of registry) index.set(entry.provider, entry.provider);
+  return index;
+}
+
+/**
+ * Rewrite each finding's provider to its canonical slug, using a matcher
+ * from `createVendorMatcher` (which resolves names as well as hosts).
+ * Providers nothing resolves are left as they are.
+ */
+export function canonicalizeProviders(entries: DependencyEntry[], matchVendor: (name: string) => string | null): void {
+  for (const entry of entries) {
+    if (entry.kind === "package" || !entry.provider) continue;
+    const canonical = matchVendor(entry.provider);
+    if (canonical && canonical !== entry.provider) entry.provider = canonical;
+  }
+}
+
+/**
+ * Read aliases for organization-wide reports from a YAML file: either a
+ * .thirdwatch.yml (its `vendor_aliases`) or a bare slug → names mapping.
+ */
+export async function loadVendorAliases(path: string): Promise<VendorAliases> {
+  const raw = yaml.load(await readFile(path, "utf8"), { schema: yaml.FAILSAFE_SCHEMA }) as unknown;
+  const isMapping = (v: unknown): v is Record<string, unknown> => v != null && typeof v === "object" && !Array.isArray(v);
+  const mapping = isMapping(raw) && "vendor_aliases" in raw ? raw["vendor_aliases"] : raw;
+  if (!isMapping(mapping)) throw new Error(`${path} has no vendor aliases (expected a mapping of vendor slug to names)`);
+  const aliases: VendorAliases = {};
+  for (const [vendor, names] of Object.entries(mapping)) {
+    if (!Array.isArray(names) || !names.every((n) => typeof n === "string" && n.trim())) {
+      throw new Error(`${path}: aliases for ${vendor} must be a list of names`);
+    }
+    aliases[vendor] = names as string[];
+  }
+  return aliases;
+}
@@ -0,0 +1,633 @@
+/**
+ * @module artifact
+ *
+ * Build artifacts as scan targets, so what shipped can be audited after the
+ * build rather than only the repository it came from:
+ *
+ *   dist/app.tar.gz, release.zip, service.jar   → extracted and scanned as a tree
+ *   image.tar (`docker save`, OCI layout)       → layers applied, then scanned
+ *   ghcr.io/acme/checkout:1.4                   → saved with docker or podman first
+ *
+ * Image layers are applied in order with their whiteouts, so a file deleted by
+ * a later layer is not reported. The image's configured environment
+ * (`ENV STRIPE_API_BASE=...`) is written to `image-env.properties` at the root
+ * of the tree, where the config pass picks up the endpoints and keys baked
+ * into it. Operating-system directories and the language runtimes' own
+ * libraries are ignored (`IMAGE_IGNORE`): the application's dependencies are
+ * reported from its manifests, not from scanning their source.
+ *
+ * Extraction never follows or creates symlinks, drops entries that would land
+ * outside the target directory, skips files over `maxFileBytes`, and fails
+ * once more than `maxTotalBytes` would be written.
+ */
+
+import { execFile } from "node:child_process";
+import { createHash } from "node:crypto";
+import { createReadStream } from "node:fs";
+import { copyFile, mkdir, mkdtemp, open, readFile, readdir, rm, stat, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { basename, dirname, join, resolve } from "node:path";
+import { createGunzip, inflateRawSync } from "node:zlib";
+import type { TDMArtifact } from "@thirdwatch/tdm";
+
+export interface ExtractLimits {
+  /** Larger files are skipped (default 16 MiB) */
+  maxFileBytes?: number;
+  /** Extraction fails once this much has been written (default 4 GiB) */
+  maxTotalBytes?: number;
+}
+
+const DEFAULT_MAX_FILE_BYTES = 16 * 1024 * 1024;
+const DEFAULT_MAX_TOTAL_BYTES = 4 * 1024 * 1024 * 1024;
+
+/** Paths in an image filesystem that hold the OS and runtimes, not the application */
+export const IMAGE_IGNORE = [
+  "/bin/",
+  "/sbin/",
+  "/lib/",
+  "/lib32/",
+  "/lib64/",
+  "/libx32/",
+  "/proc/",
+  "/sys/",
+  "/dev/",
+  "/run/",
+  "/tmp/",
+  "/usr/bin/",
+  "/usr/sbin/",
+  "/usr/lib/",
+  "/usr/lib32/",
+  "/usr/lib64/",
+  "/usr/libexec/",
+  "/usr/include/",
+  "/usr/share/",
+  "/usr/local/lib/",
+  "/usr/local/include/",
+  "/usr/local/share/",
+  "/usr/local/go/",
+  "/usr/local/bundle/",
+  "/opt/java/",
+  "/var/cache/",
+  "/var/lib/",
+  "/var/log/",
+  // Installed dependencies, reported from the application's manifests
+  "**/site-packages/",
+  "**/dist-packages/",
+  "**/vendor/",
+  "**/pkg/mod/",
+];
+
+/** File the image's configured environment is written to */
+export const IMAGE_ENV_FILE = "image-env.properties";
+
+const ZIP_RE = /\.(?:zip|jar|war|ear|whl)$/i;
+const TAR_RE = /\.(?:tar|tgz|tar\.gz)$/i;
+
+// nginx:1.27, ghcr.io/acme/checkout:1.4, registry:5000/app@sha256:<hex> — a tag
+// or digest is required, so a missing directory isn't mistaken for an image
+const IMAGE_REF_RE =
+  /^(?:[a-z0-9.-]+(?::\d+)?\/)?[a-z0-9]+(?:[._-][a-z0-9]+)*(?:\/[a-z0-9]+(?:[._-][a-z0-9]+)*)*(?::\w[\w.-]{0,127}|@sha256:[a-f0-9]{64}|:\w[\w.-]{0,127}@sha256:[a-f0-9]{64})$/;
+
+/** An archive `openScanTarget` can extract, judged by its extension */
+export function isArchivePath(path: string): boolean {
+  return ZIP_RE.test(path) || TAR_RE.test(path);
+}
+
+/** A container image reference with an explicit tag or digest */
+export function isImageReference(target: string): boolean {
+  return IMAGE_REF_RE.test(target);
+}
+
+// Drive letters, alternate data streams, and device names (CON, NUL, COM1)
+const WINDOWS_UNSAFE_RE = /:|^(?:con|prn|aux|nul|com\d|lpt\d)(?:\..*)?$/i;
+
+/** An entry's path relative to the extraction root, or null if it would escape it */
+function safePath(name: string): string | null {
+  const parts = name.replace(/\\/g, "/").split("/").filter((p) => p !== "" && p !== ".");
+  if (parts.length === 0 || parts.includes("..")) return null;
+  if (process.platform === "win32" && parts.some((p) => WINDOWS_UNSAFE_RE.test(p))) return null;
+  return parts.join("/");
+}
+
+interface ExtractState {
+  maxFileBytes: number;
+  maxTotalBytes: number;
+  written: number;
+}
+
+function extractState(limits: ExtractLimits): ExtractState {
+  return {
+    maxFileBytes: limits.maxFileBytes ?? DEFAULT_MAX_FILE_BYTES,
+    maxTotalBytes: limits.maxTotalBytes ?? DEFAULT_MAX_TOTAL_BYTES,
+    written: 0,
+  };
+}
+
+async function writeEntry(dest: string, rel: string, data: Buffer, state: ExtractState): Promise<void> {
+  state.written += data.length;
+  if (state.written > state.maxTotalBytes) {
+    throw new Error(`Archive expands past ${Math.round(state.maxTotalBytes / 1024 ** 2)} MiB; refusing to extract further`);
+  }
+  const target = join(dest, rel);
+  try {
+    await mkdir(dirname(target), { recursive: true });
+  } catch {
+    // A lower layer put a file where this one has a directory
+    await removeFileAncestors(dest, rel);
+    await mkdir(dirname(target), { recursive: true });
+  }
+  await rm(target, { recursive: true, force: true });
+  await writeFile(target, data);
+}
+
+async function removeFileAncestors(dest: string, rel: string): Promise<void> {
+  const parts = rel.split("/");
+  for (let i = 1; i < parts.length; i++) {
+    const path = join(dest, ...parts.slice(0, i));
+    const info = await stat(path).catch(() => null);
+    if (info && !info.isDirectory()) await rm(path, { force: true });
+  }
+}
+
+// ---------------------------------------------------------------------------
+// tar (ustar, pax, and GNU long names), optionally gzip-compressed
+// ---------------------------------------------------------------------------
+
+interface TarEntry {
+  path: string;
+  type: "file" | "directory" | "symlink" | "hardlink" | "other";
+  size: number;
+  linkname: string;
+  /** Read the contents into memory; unread contents are skipped */
+  read(): Promise<Buffer>;
+  /** Stream the contents to a file, for entries too large to buffer */
+  pipeTo(path: string): Promise<void>;
+}
+
+class ByteReader {
+  private chunks: Buffer[] = [];
+  private length = 0;
+  private done = false;
+  private readonly source: AsyncIterator<Buffer>;
+
+  constructor(source: AsyncIterator<Buffer>) {
+    this.source = source;
+  }
+
+  private async fill(n: number): Promise<boolean> {
+    while (this.length < n && !this.done) {
+      const next = await this.source.next();
+      if (next.done) this.done = true;
+      else {
+        this.chunks.push(next.value);
+        this.length += next.value.length;
+      }
+    }
+    return this.length >= n;
+  }
+
+  async read(n: number): Promise<Buffer | null> {
+    if (!(await this.fill(n))) return null;
+    const all = this.chunks.length === 1 ? this.chunks[0]! : Buffer.concat(this.chunks);
+    this.chunks = [all.subarray(n)];
+    this.length -= n;
+    return all.subarray(0, n);
+  }
+
+  /** Pass the next `n` bytes to `write` as they arrive */
+  async copy(n: number, write: (chunk: Buffer) => Promise<unknown>): Promise<void> {
+    while (n > 0) {
+      if (this.length === 0 && !(await this.fill(1))) throw new Error("Truncated tar archive");
+      const head = this.chunks[0]!;
+      const k = Math.min(n, head.length);
+      await write(head.subarray(0, k));
+      if (k === head.length) this.chunks.shift();
+      else this.chunks[0] = head.subarray(k);
+      this.length -= k;
+      n -= k;
+    }
+  }
+
+  async skip(n: number): Promise<void> {
+    while (n > 0) {
+      if (this.length === 0 && !(await this.fill(1))) return;
+      const head = this.chunks[0]!;
+      const k = Math.min(n, head.length);
+      if (k === head.length) this.chunks.shift();
+      else this.chunks[0] = head.subarray(k);
+      this.length -= k;
+      n -= k;
+    }
+  }
+}
+
+function headerString(header: Buffer, offset: number, length: number): string {
+  const field = header.subarray(offset, offset + length);
+  const end = field.indexOf(0);
+  return field.subarray(0, end === -1 ? length : end).toString("utf8");
+}
+
+function headerNumber(header: Buffer, offset: number, length: number): number {
+  // GNU base-256 for sizes over 8 GiB
+  if (header[offset]! & 0x80) {
+    let n = header[offset]! & 0x7f;
+    for (let i = 1; i < length; i++) n = n * 256 + header[offset + i]!;
+    return n;
+  }
+  return parseInt(headerString(header, offset, length).trim() || "0", 8);
+}
+
+/** "27 path=some/long/name.py\n" records */
+function parsePax(body: Buffer): Record<string, string> {
+  const records: Record<string, string> = {};
+  let i = 0;
+  while (i < body.length) {
+    const space = body.indexOf(0x20, i);
+    if (space === -1) break;
+    const length = Number(body.subarray(i, space).toString("latin1"));
+    if (!Number.isInteger(length) || length <= 0) break;
+    const record = body.subarray(space + 1, i + length - 1).toString("utf8");
+    const eq = record.indexOf("=");
+    if (eq > 0) records[record.slice(0, eq)] = record.slice(eq + 1);
+    i += length;
+  }
+  return records;
+}
+
+async function* readTar(input: AsyncIterable<Buffer>): AsyncGenerator<TarEntry> {
+  const reader = new ByteReader(input[Symbol.asyncIterator]());
+  let longName: string | null = null;
+  let longLink: string | null = null;
+  let pax: Record<string, string> = {};
+
+  for (;;) {
+    const header = await reader.read(512);
+    if (!header || header.every((b) => b === 0)) return;
+    const flag = String.fromCharCode(header[156]!);
+    const headerSize = headerNumber(header, 124, 12);
+
+    if (flag === "L" || flag === "K" || flag === "x") {
+      const body = await reader.read(Math.ceil(headerSize / 512) * 512);
+      if (!body) throw new Error("Truncated tar archive");
+      const value = body.subarray(0, headerSize);
+      if (flag === "x") pax = parsePax(value);
+      else if (flag === "L") longName = value.toString("utf8").replace(/\0+$/, "");
+      else longLink = value.toString("utf8").replace(/\0+$/, "");
+      continue;
+    }
+
+    const size = pax.size !== undefined ? Number(pax.size) : headerSize;
+    const padded = Math.ceil(size / 512) * 512;
+    if (flag === "g") {
+      await reader.skip(padded);
+      continue;
+    }
+    const ustar = header.subarray(257, 262).toString("latin1") === "ustar";
+    const prefix = ustar ? headerString(header, 345, 155) : "";
+    const shortName = headerString(header, 0, 100);
+    const path = pax.path ?? longName ?? (prefix ? `${prefix}/${shortName}` : shortName);
+    const linkname = pax.linkpath ?? longLink ?? headerString(header, 157, 100);
+    longName = longLink = null;
+    pax = {};
+
+    const type =
+      flag === "0" || flag === "\0" || flag === "7"
+        ? "file"
+        : flag === "5"
+          ? "directory"
+          : flag === "2"
+            ? "symlink"
+            : flag === "1"
+              ? "hardlink"
+              : "other";
+    let consumed = false;
+    yield {
+      path,
+      type,
+      size,
+      linkname,
+      read: async () => {
+        consumed = true;
+        const body = await reader.read(padded);
+        if (!body) throw new Error("Truncated tar archive");
+        return Buffer.from(body.subarray(0, size));
+      },
+      pipeTo: async (target) => {
+        consumed = true;
+        const fh = await open(target, "w");
+        try {
+          await reader.copy(size, (chunk) => fh.write(chunk));
+        } finally {
+          await fh.close();
+        }
+        await reader.skip(padded - size);
+      },
+    };
+    if (!consumed) await reader.skip(padded);
+  }
+}
+
+async function tarStream(file: string): Promise<AsyncIterable<Buffer>> {
+  const fh = await open(file, "r");
+  const magic = Buffer.alloc(4);
+  try {
+    await fh.read(magic, 0, 4, 0);
+  } finally {
+    await fh.close();
+  }
+  if (magic[0] === 0x1f && magic[1] === 0x8b) return createReadStream(file).pipe(createGunzip());
+  if (magic.readUInt32LE(0) === 0xfd2fb528) {
+    throw new Error(`${basename(file)} is zstd-compressed, which is not supported; re-export it with gzip`);
+  }
+  return createReadStream(file);
+}
+
+async function listTar(file: string): Promise<string[]> {
+  const names: string[] = [];
+  for await (const entry of readTar(await tarStream(file))) names.push(entry.path.replace(/^\.\//, ""));
+  return names;
+}
+
+/** Remove what lower layers put under `dir`, keeping this layer's own entries */
+async function clearOpaqueDir(dest: string, dir: string, layerPaths: Set<string>): Promise<void> {
+  let names: string[];
+  try {
+    names = await readdir(join(dest, dir));
+  } catch {
+    return;
+  }
+  for (const name of names) {
+    const rel = dir ? `${dir}/${name}` : name;
+    if (layerPaths.has(rel)) continue;
+    if ([...layerPaths].some((p) => p.startsWith(`${rel}/`))) await clearOpaqueDir(dest, rel, layerPaths);
+    else await rm(join(dest, rel), { recursive: true, force: true });
+  }
+}
+
+/**
+ * Extract a tar or tar.gz into `dest`. As an image layer (`layer: true`),
+ * `.wh.<name>` entries delete `<name>` and `.wh..wh..opq` hides everything
+ * lower layers put in that directory. With `keepLarge`, files over the size
+ * limit are streamed to disk instead of skipped (an image tarball's layers).
+ */
+export async function extractTar(
+  file: string,
+  dest: string,
+  limits: ExtractLimits = {},
+  options: { layer?: boolean; keepLarge?: boolean } = {},
+  state: ExtractState = extractState(limits),
+): Promise<void> {
+  const layerPaths = new Set<string>();
+  for await (const entry of readTar(await tarStream(file))) {
+    const rel = safePath(entry.path);
+    if (!rel) continue;
+    const name = basename(rel);
+    if (options.layer && name === ".wh..wh..opq") {
+      await clearOpaqueDir(dest, dirname(rel) === "." ? "" : dirname(rel), layerPaths);
+      continue;
+    }
+    if (options.layer && name.startsWith(".wh.")) {
+      // ".wh.." and ".wh..." would name the directory itself or its parent
+      const hidden = name.slice(4);
+      const target = safePath(hidden) === hidden ? safePath(join(dirname(rel), hidden)) : null;
+      if (target) await rm(join(dest, target), { recursive: true, force: true });
+      continue;
+    }
+    layerPaths.add(rel);
+    if (entry.type === "directory") {
+      await mkdir(join(dest, rel), { recursive: true }).catch(() => undefined);
+    } else if (entry.type === "file" && entry.size <= state.maxFileBytes) {
+      await writeEntry(dest, rel, await entry.read(), state);
+    } else if (entry.type === "file" && options.keepLarge) {
+      state.written += entry.size;
+      await writeEntry(dest, rel, Buffer.alloc(0), state);
+      await entry.pipeTo(join(dest, rel));
+    } else if (entry.type === "hardlink") {
+      const target = safePath(entry.linkname);
+      if (target && (await stat(join(dest, target)).catch(() => null))?.isFile()) {
+        await mkdir(dirname(join(dest, rel)), { recursive: true });
+        await copyFile(join(dest, target), join(dest, rel));
+      }
+    }
+    // Symlinks and device nodes are skipped: the scanner doesn't follow links
+  }
+}
+
+// ---------------------------------------------------------------------------
+// zip (and jar, war, ear, whl)
+// ---------------------------------------------------------------------------
+
+/** Extract a zip archive into `dest`; stored and deflated entries are supported */
+export async function extractZip(file: string, dest: string, limits: ExtractLimits = {}): Promise<void> {
+  const state = extractState(limits);
+  const fh = await open(file, "r");
+  try {
+    const { size } = await fh.stat();
+    // End of central directory record: 22 bytes plus a comment of up to 64 KiB
+    const tailLength = Math.min(size, 22 + 0xffff);
+    const tail = Buffer.alloc(tailLength);
+    await fh.read(tail, 0, tailLength, size - tailLength);
+    const eocd = tail.lastIndexOf(Buffer.from([0x50, 0x4b, 0x05, 0x06]));
+    if (eocd === -1) throw new Error(`${basename(file)} is not a zip archive`);
+    const count = tail.readUInt16LE(eocd + 10);
+    const directorySize = tail.readUInt32LE(eocd + 12);
+    const directoryOffset = tail.readUInt32LE(eocd + 16);
+    if (count === 0xffff || directoryOffset === 0xffffffff) throw new Error(`${basename(file)} is a Zip64 archive, which is not supported`);
+
+    const directory = Buffer.alloc(directorySize);
+    await fh.read(directory, 0, directorySize, directoryOffset);
+    let p = 0;
+    for (let i = 0; i < count; i++) {
+      if (directory.readUInt32LE(p) !== 0x02014b50) throw new Error(`${basename(file)} has a corrupt central directory`);
+      const method = directory.readUInt16LE(p + 10);
+      const compressedSize = directory.readUInt32LE(p + 20);
+      const size = directory.readUInt32LE(p + 24);
+      const nameLength = directory.readUInt16LE(p + 28);
+      const extraLength = directory.readUInt16LE(p + 30);
+      const commentLength = directory.readUInt16LE(p + 32);
+      const mode = directory.readUInt32LE(p + 38) >>> 16;
+      const localOffset = directory.readUInt32LE(p + 42);
+      const name = directory.subarray(p + 46, p + 46 + nameLength).toString("utf8");
+      p += 46 + nameLength + extraLength + commentLength;
+
+      const rel = safePath(name);
+      const symlink = (mode & 0o170000) === 0o120000;
+      if (!rel || name.endsWith("/") || symlink || size > state.maxFileBytes || (method !== 0 && method !== 8)) continue;
+
+      const local = Buffer.alloc(30);
+      await fh.read(local, 0, 30, localOffset);
+      const dataOffset = localOffset + 30 + local.readUInt16LE(26) + local.readUInt16LE(28);
+      const compressed = Buffer.alloc(compressedSize);
+      await fh.read(compressed, 0, compressedSize, dataOffset);
+      let data: Buffer;
+      try {
+        data = method === 0 ? compressed : inflateRawSync(compressed, { maxOutputLength: state.maxFileBytes });
+      } catch {
+        // Corrupt, or inflates past the size it declared
+        continue;
+      }
+      await writeEntry(dest, rel, data, state);
+    }
+  } finally {
+    await fh.close();
+  }
+}
+
+// ---------------------------------------------------------------------------
+// Container images
+// ---------------------------------------------------------------------------
+
+interface OciDescriptor {
+  mediaType?: string;
+  digest: string;
+  platform?: { os?: string; architecture?: string };
+}
+
+const INDEX_MEDIA_TYPES = new Set([
+  "application/vnd.oci.image.index.v1+json",
+  "application/vnd.docker.distribution.manifest.list.v2+json",
+]);
+
+// OCI digests: algorithm, then the hex-encoded hash
+const DIGEST_RE = /^[a-z0-9]+(?:[+._-][a-z0-9]+)*:[a-f0-9]{32,}$/;
+
+async function readJson<T>(path: string): Promise<T> {
+  return JSON.parse(await readFile(path, "utf-8")) as T;
+}
+
+/** The layer files and config of an extracted `docker save` or OCI image layout */
+async function imageLayout(dir: string): Promise<{ layers: string[]; config: string; digest: string }> {
+  const blob = (digest: string) => {
+    if (!DIGEST_RE.test(String(digest))) throw new Error(`OCI image layout has an invalid digest: ${String(digest)}`);
+    return join(dir, "blobs", ...digest.split(":"));
+  };
+  // OCI layout (skopeo, buildx --output type=oci, docker save since 25.0)
+  if (await stat(join(dir, "index.json")).catch(() => null)) {
+    let descriptor = (await readJson<{ manifests?: OciDescriptor[] }>(join(dir, "index.json"))).manifests?.[0];
+    let manifest: { mediaType?: string; manifests?: OciDescriptor[]; config?: OciDescriptor; layers?: OciDescriptor[] } = {};
+    while (descriptor) {
+      manifest = await readJson(blob(descriptor.digest));
+      if (!INDEX_MEDIA_TYPES.has(manifest.mediaType ?? descriptor.mediaType ?? "") && !manifest.manifests) break;
+      // Multi-platform index: prefer linux/amd64
+      const platforms = manifest.manifests ?? [];
+      descriptor = platforms.find((m) => m.platform?.os === "linux" && m.platform.architecture === "amd64") ?? platforms[0];
+    }
+    if (!manifest.config || !manifest.layers) throw new Error("OCI image layout has no image manifest");
+    return { layers: manifest.layers.map((l) => blob(l.digest)), config: blob(manifest.config.digest), digest: manifest.config.digest };
+  }
+  // docker save, older format
+  const manifest = (await readJson<Array<{ Config: string; Layers: string[] }>>(join(dir, "manifest.json")))[0];
+  if (!manifest) throw new Error("Image archive has an empty manifest.json");
+  const inside = (path: string) => {
+    const rel = safePath(String(path));
+    if (!rel) throw new Error(`Image manifest.json names a file outside the archive: ${String(path)}`);
+    return join(dir, rel);
+  };
+  const id = basename(manifest.Config).replace(/\.json$/, "");
+  return { layers: manifest.Layers.map(inside), config: inside(manifest.Config), digest: `sha256:${id}` };
+}
+
+/** Apply an extracted image's layers to `rootfs`; returns the image digest */
+async function unpackImage(layoutDir: string, rootfs: string, limits: ExtractLimits): Promise<string> {
+  const { layers, config, digest } = await imageLayout(layoutDir);
+  const state = extractState(limits);
+  for (const layer of layers) await extractTar(layer, rootfs, limits, { layer: true }, state);
+
+  const env = (await readJson<{ config?: { Env?: string[] } }>(config)).config?.Env ?? [];
+  if (env.length > 0) await writeFile(join(rootfs, IMAGE_ENV_FILE), env.join("\n") + "\n");
+  return digest;
+}
+
+function run(cmd: string, args: string[]): Promise<void> {
+  return new Promise((resolvePromise, reject) => {
+    execFile(cmd, args, { timeout: 10 * 60_000, maxBuffer: 16 * 1024 * 1024 }, (err, _stdout, stderr) => {
+      if (err) reject(Object.assign(err, { stderr: String(stderr).trim() }));
+      else resolvePromise();
+    });
+  });
+}
+
+/**
+ * `docker save` (or podman) an image to `file`, pulling it first if it is not
+ * local. THIRDWATCH_CONTAINER_CLI picks the tool.
+ */
+export async function saveImage(reference: string, file: string): Promise<void> {
+  const clis = process.env.THIRDWATCH_CONTAINER_CLI ? [process.env.THIRDWATCH_CONTAINER_CLI] : ["docker", "podman"];
+  for (const cli of clis) {
+    try {
+      await run(cli, ["image", "inspect", reference]);
+    } catch (err) {
+      if ((err as NodeJS.ErrnoException).code === "ENOENT") continue;
+      await run(cli, ["pull", reference]).catch((pullErr: Error & { stderr?: string }) => {
+        throw new Error(`Could not pull ${reference}: ${pullErr.stderr || pullErr.message}`);
+      });
+    }
+    await run(cli, ["save", "-o", file, reference]);
+    return;
+  }
+  throw new Error(`Scanning the image ${reference} needs docker or podman on PATH; alternatively, scan a \`docker save\` tarball`);
+}
+
+async function sha256File(file: string): Promise<string> {
+  const hash = createHash("sha256");
+  for await (const chunk of createReadStream(file)) hash.update(chunk as Buffer);
+  return `sha256:${hash.digest("hex")}`;
+}
+
+export interface ScanTarget {
+  /** Directory to scan */
+  root: string;
+  /** What was extracted, for the TDM's metadata; absent for a directory */
+  artifact?: TDMArtifact;
+  /** Extra ignore patterns for the target, e.g. the OS directories of an image */
+  ignore: string[];
+  /** Remove the extracted files */
+  cleanup(): Promise<void>;
+}
+
+/**
+ * Resolve a scan target: a directory as-is, or an archive, image tarball, or
+ * image reference extracted to a temporary directory. Call `cleanup` when done.
+ */
+export async function openScanTarget(target: string, limits: ExtractLimits = {}): Promise<ScanTarget> {
+  const info = await stat(target).catch(() => null);
+  if (info?.isDirectory()) return { root: resolve(target), ignore: [], cleanup: async () => {} };
+  if (!info && !isImageReference(target)) throw new Error(`${target}: no such directory, archive, or image reference`);
+  if (info && !isArchivePath(target)) throw new Error(`${target}: not a directory or a .tar, .tar.gz, .tgz, .zip, .jar, .war, .ear, or .whl archive`);
+
+  const work = await mkdtemp(join(tmpdir(), "thirdwatch-"));
+  const cleanup = () => rm(work, { recursive: true, force: true });
+  try {
+    const root = join(work, "root");
+    await mkdir(root);
+    if (ZIP_RE.test(target)) {
+      await extractZip(target, root, limits);
+      return { root, artifact: { type: "archive", reference: basename(target), digest: await sha256File(target) }, ignore: [], cleanup };
+    }
+
+    let archive = target;
+    if (!info) {
+      archive = join(work, "image.tar");
+      await saveImage(target, archive);
+    }
+    const names = await listTar(archive);
+    if (!names.includes("manifest.json") && !names.includes("oci-layout")) {
+      await extractTar(archive, root, limits);
+      return { root, artifact: { type: "archive", reference: basename(target), digest: await sha256File(target) }, ignore: [], cleanup };
+    }
+    // An image tarball: its layer blobs are large by design
+    const layout = join(work, "layout");
+    await extractTar(archive, layout, limits, { keepLarge: true });
+    const digest = await unpackImage(layout, root, limits);
+    await rm(layout, { recursive: true, force: true });
+    return {
+      root,
+      artifact: { type: "image", reference: info ? basename(target) : target, digest },
+      ignore: IMAGE_IGNORE,
+      cleanup,
+    };
+  } catch (err) {
+    await cleanup();
+    throw err;
+  }
+}
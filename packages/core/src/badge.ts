@@ -0,0 +1,124 @@
+/**
+ * @module badge
+ *
+ * Per-repository summaries and status badges behind the server's
+ * GET /api/v1/badges/<repository>.svg and .json, for embedding in READMEs
+ * and internal portals. A summary is the repository's vendor count plus its
+ * policy status:
+ *
+ *   failing  — a vendor denied to a team owning the repository is in use,
+ *              or a finding has severity "error" (from `rule_settings` or
+ *              `gateways` in .thirdwatch.yml)
+ *   warning  — a finding has severity "warning"
+ *   passing  — neither
+ *
+ * Badges are flat, shields.io-style SVGs sized with approximate Verdana 11px
+ * glyph widths, so they need no font metrics at runtime.
+ */
+
+import type { Severity, TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "./registry.js";
+import { collectVendorUsages } from "./sla.js";
+import { createVendorMatcher } from "./runtime.js";
+
+export type PolicyStatus = "passing" | "warning" | "failing";
+
+export interface RepositorySummary {
+  repository: string;
+  scanned_at: string;
+  /** Distinct third-party vendors */
+  vendors: number;
+  dependencies: number;
+  policy: PolicyStatus;
+  /** Denied vendors in use, sorted */
+  denied_vendors: string[];
+  /** Findings by configured severity; "info" is the default and not counted */
+  findings: Record<Exclude<Severity, "info">, number>;
+}
+
+export type BadgeMetric = "vendors" | "policy";
+
+export interface Badge {
+  label: string;
+  message: string;
+  /** Hex color of the message side */
+  color: string;
+}
+
+const COLORS = {
+  passing: "#4c1",
+  warning: "#dfb317",
+  failing: "#e05d44",
+  neutral: "#007ec6",
+  unknown: "#9f9f9f",
+} as const;
+
+export function summarizeRepository(
+  repository: string,
+  tdm: TDM,
+  registry: SDKRegistryEntry[],
+  policy: { deniedVendors?: string[] } = {},
+): RepositorySummary {
+  const vendors = new Set(collectVendorUsages(tdm, createVendorMatcher(registry)).map((u) => u.vendor));
+  const denied = (policy.deniedVendors ?? []).filter((v) => vendors.has(v));
+
+  const findings = { error: 0, warning: 0 };
+  const entries: Array<{ severity?: Severity }> = [...tdm.packages, ...tdm.apis, ...tdm.sdks, ...tdm.infrastructure, ...tdm.webhooks];
+  for (const entry of entries) {
+    if (entry.severity === "error" || entry.severity === "warning") findings[entry.severity]++;
+  }
+
+  return {
+    repository,
+    scanned_at: tdm.metadata.scan_timestamp,
+    vendors: vendors.size,
+    dependencies: tdm.metadata.total_dependencies_found,
+    policy: denied.length > 0 || findings.error > 0 ? "failing" : findings.warning > 0 ? "warning" : "passing",
+    denied_vendors: [...new Set(denied)].sort(),
+    findings,
+  };
+}
+
+/** The badge for one metric of a summary; null renders "not scanned" */
+export function badgeFor(summary: RepositorySummary | null, metric: BadgeMetric): Badge {
+  const label = metric === "vendors" ? "vendors" : "vendor policy";
+  if (!summary) return { label, message: "not scanned", color: COLORS.unknown };
+  if (metric === "vendors") return { label, message: String(summary.vendors), color: COLORS.neutral };
+  return { label, message: summary.policy, color: COLORS[summary.policy] };
+}
+
+// Verdana 11px advance widths, in pixels, for the characters badges use most
+const NARROW = new Set([..."fijlrt.,:;'!|()[] "]);
+const WIDE = new Set([..."mwMW@%"]);
+
+function textWidth(text: string): number {
+  let width = 0;
+  for (const ch of text) {
+    width += NARROW.has(ch) ? 4 : WIDE.has(ch) ? 10 : /[A-Z0-9]/.test(ch) ? 7.5 : 6.5;
+  }
+  return Math.ceil(width);
+}
+
+function escapeXml(text: string): string {
+  return text.replace(/[<>&"']/g, (c) => ({ "<": "&lt;", ">": "&gt;", "&": "&amp;", '"': "&quot;", "'": "&apos;" })[c]!);
+}
+
+/** A flat two-part SVG badge */
+export function renderBadge(badge: Badge): string {
+  const left = textWidth(badge.label) + 10;
+  const right = textWidth(badge.message) + 10;
+  const width = left + right;
+  const label = escapeXml(badge.label);
+  const message = escapeXml(badge.message);
+  return [
+    `<svg xmlns="http://www.w3.org/2000/svg" width="${width}" height="20" role="img" aria-label="${label}: ${message}">`,
+    `<title>${label}: ${message}</title>`,
+    `<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`,
+    `<clipPath id="r"><rect width="${width}" height="20" rx="3" fill="#fff"/></clipPath>`,
+    `<g clip-path="url(#r)"><rect width="${left}" height="20" fill="#555"/><rect x="${left}" width="${right}" height="20" fill="${badge.color}"/><rect width="${width}" height="20" fill="url(#s)"/></g>`,
+    `<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`,
+    `<text x="${left / 2}" y="15" fill="#010101" fill-opacity=".3">${label}</text><text x="${left / 2}" y="14">${label}</text>`,
+    `<text x="${left + right / 2}" y="15" fill="#010101" fill-opacity=".3">${message}</text><text x="${left + right / 2}" y="14">${message}</text>`,
+    `</g></svg>`,
+  ].join("");
+}
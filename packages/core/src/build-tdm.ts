@@ -6,6 +6,8 @@ import type {
   TDMInfrastructure,
   TDMWebhook,
   TDMLocation,
+  TDMArtifact,
+  DataFlow,
 } from "@thirdwatch/tdm";
 import { TDM_SCHEMA_VERSION } from "@thirdwatch/tdm";
 import type { DependencyEntry, LanguageAnalyzerPlugin } from "./plugin.js";
@@ -21,6 +23,8 @@ export interface BuildContext {
   plugins: LanguageAnalyzerPlugin[];
   duration: number;
   repository?: string;
+  catalogVersion?: string;
+  artifact?: TDMArtifact;
 }
 
 // ---------------------------------------------------------------------------
@@ -40,6 +44,19 @@ function mergeLocations(a: TDMLocation[], b: TDMLocation[]): TDMLocation[] {
   return result;
 }
 
+/** Fold a duplicate's data labels into the kept entry; opposite flows make it bidirectional */
+function mergeDataLabels(
+  existing: { data_flow?: DataFlow; data_classification?: string[] },
+  entry: { data_flow?: DataFlow; data_classification?: string[] },
+): void {
+  if (entry.data_flow && entry.data_flow !== existing.data_flow) {
+    existing.data_flow = existing.data_flow ? "bidirectional" : entry.data_flow;
+  }
+  if (entry.data_classification) {
+    existing.data_classification = [...new Set([...(existing.data_classification ?? []), ...entry.data_classification])];
+  }
+}
+
 function deduplicatePackages(entries: TDMPackage[]): TDMPackage[] {
   const map = new Map<string, TDMPackage>();
   for (const entry of entries) {
@@ -48,6 +65,7 @@ function deduplicatePackages(entries: TDMPackage[]): TDMPackage[] {
     if (existing) {
       existing.locations = mergeLocations(existing.locations, entry.locations);
       existing.usage_count = existing.locations.length;
+      if (entry.services) existing.services = [...new Set([...(existing.services ?? []), ...entry.services])].sort();
     } else {
       map.set(key, { ...entry });
     }
@@ -63,6 +81,8 @@ function deduplicateApis(entries: TDMApi[]): TDMApi[] {
     if (existing) {
       existing.locations = mergeLocations(existing.locations, entry.locations);
       existing.usage_count = existing.locations.length;
+      if (entry.gateway_bypass) existing.gateway_bypass ??= entry.gateway_bypass;
+      mergeDataLabels(existing, entry);
     } else {
       map.set(key, { ...entry });
     }
@@ -73,7 +93,8 @@ function deduplicateApis(entries: TDMApi[]): TDMApi[] {
 function deduplicateSdks(entries: TDMSdk[]): TDMSdk[] {
   const map = new Map<string, TDMSdk>();
   for (const entry of entries) {
-    const key = `${entry.provider}:${entry.sdk_package}`;
+    // Clients of one SDK pointed at different gateways stay separate
+    const key = `${entry.provider}:${entry.sdk_package}:${entry.base_url ?? ""}`;
     const existing = map.get(key);
     if (existing) {
       existing.locations = mergeLocations(existing.locations, entry.locations);
@@ -85,6 +106,12 @@ function deduplicateSdks(entries: TDMSdk[]): TDMSdk[] {
         ]);
         existing.services_used = [...services];
       }
+      if (entry.regions) {
+        existing.regions = [...new Set([...(existing.regions ?? []), ...entry.regions])];
+      }
+      if (entry.data_exported) {
+        existing.data_exported = [...new Set([...(existing.data_exported ?? []), ...entry.data_exported])];
+      }
       if (entry.api_methods) {
         const methods = new Set([
           ...(existing.api_methods ?? []),
@@ -92,6 +119,13 @@ function deduplicateSdks(entries: TDMSdk[]): TDMSdk[] {
         ]);
         existing.api_methods = [...methods];
       }
+      if (entry.intents) {
+        existing.intents = [...new Set([...(existing.intents ?? []), ...entry.intents])];
+      }
+      // Unused only if no file that sets it up calls it
+      if (!entry.unused) delete existing.unused;
+      if (entry.gateway_bypass) existing.gateway_bypass ??= entry.gateway_bypass;
+      mergeDataLabels(existing, entry);
     } else {
       map.set(key, { ...entry });
     }
@@ -108,6 +142,8 @@ function deduplicateInfrastructure(
     const existing = map.get(key);
     if (existing) {
       existing.locations = mergeLocations(existing.locations, entry.locations);
+      if (!entry.unused) delete existing.unused;
+      mergeDataLabels(existing, entry);
     } else {
       map.set(key, { ...entry });
     }
@@ -118,10 +154,11 @@ function deduplicateInfrastructure(
 function deduplicateWebhooks(entries: TDMWebhook[]): TDMWebhook[] {
   const map = new Map<string, TDMWebhook>();
   for (const entry of entries) {
-    const key = `${entry.direction}:${entry.target_url}`;
+    const key = `${entry.direction}:${entry.target_url}:${entry.provider ?? ""}`;
     const existing = map.get(key);
     if (existing) {
       existing.locations = mergeLocations(existing.locations, entry.locations);
+      mergeDataLabels(existing, entry);
     } else {
       map.set(key, { ...entry });
     }
@@ -184,6 +221,12 @@ export function buildTDM(entries: DependencyEntry[], context: BuildContext): TDM
   if (context.repository !== undefined) {
     metadata.repository = context.repository;
   }
+  if (context.catalogVersion !== undefined) {
+    metadata.catalog_version = context.catalogVersion;
+  }
+  if (context.artifact !== undefined) {
+    metadata.artifact = context.artifact;
+  }
 
   return {
     version: TDM_SCHEMA_VERSION,
@@ -0,0 +1,375 @@
+/**
+ * @module catalog-validate
+ *
+ * Validation for catalog entries (registries/sdks/*.yml), backing
+ * `thirdwatch catalog validate`. Two layers:
+ *
+ *   1. Schema checks — field names, types, slugs, categories, domains
+ *      (mirrors schema/registry.schema.json).
+ *   2. Example checks — every `examples` fixture must actually be detected
+ *      by the entry's import_patterns, known_api_base_urls, or domains.
+ */
+
+import fg from "fast-glob";
+import { readFile, stat } from "node:fs/promises";
+import { basename, resolve } from "node:path";
+import * as yaml from "js-yaml";
+import { VENDOR_CATEGORIES } from "./registry.js";
+import type { SDKRegistryEntry } from "./registry.js";
+import { extractHost, matchesDomain } from "./first-party.js";
+import { parseCidr } from "./ip-ranges.js";
+import { pathVendor } from "./categories.js";
+import { isHostAlias, normalizeVendorName } from "./aliases.js";
+
+const ECOSYSTEMS = new Set(["npm", "pypi", "go", "maven", "cargo", "packagist"]);
+const CATEGORIES = new Set<string>(VENDOR_CATEGORIES);
+const TOP_LEVEL_KEYS = new Set([
+  "provider", "display_name", "category", "homepage", "changelog_url",
+  "docs_url", "status_page_url", "privacy_policy_url", "dpa_url", "subprocessors_url",
+  "trust_center_url", "aliases", "patterns", "constructors", "factories",
+  "known_api_base_urls", "domains", "ip_ranges", "sla", "rate_limits", "user_agents", "env_var_patterns", "examples",
+]);
+const URL_KEYS = [
+  "homepage", "changelog_url", "docs_url", "status_page_url",
+  "privacy_policy_url", "dpa_url", "subprocessors_url", "trust_center_url",
+] as const;
+const STRING_LIST_KEYS = ["aliases", "known_api_base_urls", "domains", "ip_ranges", "env_var_patterns"] as const;
+const DOMAIN_RE = /^(\*\.)?[a-z0-9-]+(\.(?:[a-z0-9-]+|\*))*\.[a-z0-9-]+$/;
+
+function isObject(value: unknown): value is Record<string, unknown> {
+  return value != null && typeof value === "object" && !Array.isArray(value);
+}
+
+function isStringArray(value: unknown): value is string[] {
+  return Array.isArray(value) && value.every((v) => typeof v === "string");
+}
+
+function isUptime(value: unknown): boolean {
+  return typeof value === "number" && value > 0 && value <= 100;
+}
+
+function isPositiveInteger(value: unknown): boolean {
+  return Number.isInteger(value) && (value as number) > 0;
+}
+
+// ---------------------------------------------------------------------------
+// Schema checks
+// ---------------------------------------------------------------------------
+
+/** Structural validation of a parsed entry. Returns human-readable errors. */
+export function validateCatalogEntry(raw: unknown): string[] {
+  if (!isObject(raw)) return ["top-level value must be a YAML mapping"];
+  const errors: string[] = [];
+
+  for (const key of Object.keys(raw)) {
+    if (!TOP_LEVEL_KEYS.has(key)) errors.push(`unknown top-level key '${key}'`);
+  }
+  for (const req of ["provider", "display_name", "patterns"]) {
+    if (raw[req] == null) errors.push(`missing required field '${req}'`);
+  }
+
+  if (raw.provider != null && (typeof raw.provider !== "string" || !/^[a-z0-9-]+$/.test(raw.provider))) {
+    errors.push(`'provider' must match ^[a-z0-9-]+$ (got ${JSON.stringify(raw.provider)})`);
+  }
+  if (raw.display_name != null && typeof raw.display_name !== "string") {
+    errors.push("'display_name' must be a string");
+  }
+  if (raw.category != null && (typeof raw.category !== "string" || !CATEGORIES.has(raw.category))) {
+    errors.push(`'category' must be one of: ${VENDOR_CATEGORIES.join(", ")}`);
+  }
+  for (const key of URL_KEYS) {
+    const value = raw[key];
+    if (value == null) continue;
+    if (typeof value !== "string" || !/^https?:\/\//.test(value)) {
+      errors.push(`'${key}' must be an http(s) URL`);
+    }
+  }
+
+  if (raw.patterns != null) {
+    if (!isObject(raw.patterns)) {
+      errors.push("'patterns' must be an object");
+    } else {
+      for (const [eco, patterns] of Object.entries(raw.patterns)) {
+        if (!ECOSYSTEMS.has(eco)) {
+          errors.push(`unknown ecosystem '${eco}' in patterns (valid: ${[...ECOSYSTEMS].join(", ")})`);
+        }
+        if (!Array.isArray(patterns)) {
+          errors.push(`patterns.${eco} must be an array`);
+          continue;
+        }
+        patterns.forEach((p: unknown, i) => {
+          if (!isObject(p)) {
+            errors.push(`patterns.${eco}[${i}] must be an object`);
+            return;
+          }
+          for (const key of Object.keys(p)) {
+            if (key !== "package" && key !== "import_patterns") {
+              errors.push(`unknown key '${key}' in patterns.${eco}[${i}]`);
+            }
+          }
+          if (typeof p.package !== "string") {
+            errors.push(`patterns.${eco}[${i}].package must be a string`);
+          }
+          if (p.import_patterns != null && !isStringArray(p.import_patterns)) {
+            errors.push(`patterns.${eco}[${i}].import_patterns must be an array of strings`);
+          }
+        });
+      }
+    }
+  }
+
+  for (const key of ["constructors", "factories"] as const) {
+    const value = raw[key];
+    if (value == null) continue;
+    if (!isObject(value)) {
+      errors.push(`'${key}' must be an object`);
+      continue;
+    }
+    for (const [eco, list] of Object.entries(value)) {
+      if (!Array.isArray(list)) {
+        errors.push(`${key}.${eco} must be an array`);
+        continue;
+      }
+      list.forEach((item: unknown, i) => {
+        const ok = key === "factories"
+          ? typeof item === "string"
+          : isObject(item) && typeof item.name === "string";
+        if (!ok) {
+          errors.push(key === "factories"
+            ? `factories.${eco}[${i}] must be a string`
+            : `constructors.${eco}[${i}] must have a string 'name'`);
+        }
+      });
+    }
+  }
+
+  for (const key of STRING_LIST_KEYS) {
+    if (raw[key] != null && !isStringArray(raw[key])) {
+      errors.push(`'${key}' must be an array of strings`);
+    }
+  }
+  if (isStringArray(raw.domains)) {
+    for (const d of raw.domains) {
+      if (!DOMAIN_RE.test(d)) errors.push(`invalid domain '${d}' (lowercase hostname, optional "*." prefix and "*" labels)`);
+    }
+  }
+
+  if (isStringArray(raw.aliases)) {
+    for (const a of raw.aliases) {
+      if (!normalizeVendorName(a)) errors.push(`alias '${a}' has no letters or digits`);
+      else if (isHostAlias(a)) errors.push(`alias '${a}' is a hostname; list it under 'domains'`);
+    }
+  }
+
+  if (isStringArray(raw.ip_ranges)) {
+    for (const r of raw.ip_ranges) {
+      if (!parseCidr(r)) errors.push(`invalid ip range '${r}' (IPv4 or IPv6 CIDR, e.g. "192.0.2.0/24")`);
+    }
+  }
+
+  if (raw.sla != null) {
+    if (!isObject(raw.sla)) {
+      errors.push("'sla' must be an object");
+    } else {
+      for (const key of Object.keys(raw.sla)) {
+        if (!["uptime", "url", "plan", "services"].includes(key)) errors.push(`unknown key '${key}' in sla`);
+      }
+      if (!isUptime(raw.sla.uptime)) errors.push("sla.uptime must be a percentage in (0, 100], e.g. 99.95");
+      if (typeof raw.sla.url !== "string" || !/^https?:\/\//.test(raw.sla.url)) {
+        errors.push("sla.url must be an http(s) URL");
+      }
+      if (raw.sla.plan != null && typeof raw.sla.plan !== "string") errors.push("sla.plan must be a string");
+      if (raw.sla.services != null) {
+        if (!Array.isArray(raw.sla.services)) {
+          errors.push("sla.services must be an array");
+        } else {
+          raw.sla.services.forEach((svc: unknown, i) => {
+            if (!isObject(svc) || typeof svc.name !== "string" || !isUptime(svc.uptime) || !isStringArray(svc.domains)) {
+              errors.push(`sla.services[${i}] needs a string 'name', a percentage 'uptime', and 'domains'`);
+            }
+          });
+        }
+      }
+    }
+  }
+
+  if (raw.rate_limits != null) {
+    if (!Array.isArray(raw.rate_limits)) {
+      errors.push("'rate_limits' must be an array");
+    } else {
+      raw.rate_limits.forEach((limit: unknown, i) => {
+        if (!isObject(limit)) {
+          errors.push(`rate_limits[${i}] must be an object`);
+          return;
+        }
+        for (const key of Object.keys(limit)) {
+          if (!["requests", "window_seconds", "url", "plan", "scope", "domains"].includes(key)) {
+            errors.push(`unknown key '${key}' in rate_limits[${i}]`);
+          }
+        }
+        if (!isPositiveInteger(limit.requests)) errors.push(`rate_limits[${i}].requests must be a positive integer`);
+        if (!isPositiveInteger(limit.window_seconds)) errors.push(`rate_limits[${i}].window_seconds must be a positive integer`);
+        if (typeof limit.url !== "string" || !/^https?:\/\//.test(limit.url)) errors.push(`rate_limits[${i}].url must be an http(s) URL`);
+        for (const key of ["plan", "scope"]) {
+          if (limit[key] != null && typeof limit[key] !== "string") errors.push(`rate_limits[${i}].${key} must be a string`);
+        }
+        if (limit.domains != null && !isStringArray(limit.domains)) errors.push(`rate_limits[${i}].domains must be an array of strings`);
+      });
+    }
+  }
+
+  if (raw.user_agents != null) {
+    if (!Array.isArray(raw.user_agents)) {
+      errors.push("'user_agents' must be an array");
+    } else {
+      raw.user_agents.forEach((ua: unknown, i) => {
+        if (!isObject(ua)) {
+          errors.push(`user_agents[${i}] must be an object`);
+          return;
+        }
+        for (const key of Object.keys(ua)) {
+          if (!["pattern", "sdk_package", "ecosystem"].includes(key)) errors.push(`unknown key '${key}' in user_agents[${i}]`);
+        }
+        if (typeof ua.pattern !== "string") {
+          errors.push(`user_agents[${i}].pattern must be a string`);
+        } else {
+          try {
+            new RegExp(ua.pattern);
+          } catch {
+            errors.push(`user_agents[${i}].pattern is not a valid regex: ${ua.pattern}`);
+          }
+        }
+        if (typeof ua.sdk_package !== "string") errors.push(`user_agents[${i}].sdk_package must be a string`);
+        if (ua.ecosystem != null && (typeof ua.ecosystem !== "string" || !ECOSYSTEMS.has(ua.ecosystem))) {
+          errors.push(`user_agents[${i}].ecosystem must be one of ${[...ECOSYSTEMS].join(", ")}`);
+        }
+      });
+    }
+  }
+
+  if (raw.examples != null) {
+    if (!Array.isArray(raw.examples)) {
+      errors.push("'examples' must be an array");
+    } else {
+      raw.examples.forEach((ex: unknown, i) => {
+        if (!isObject(ex)) {
+          errors.push(`examples[${i}] must be an object`);
+          return;
+        }
+        for (const key of Object.keys(ex)) {
+          if (key !== "ecosystem" && key !== "code" && key !== "url") {
+            errors.push(`unknown key '${key}' in examples[${i}]`);
+          }
+        }
+        if (ex.code == null && ex.url == null) {
+          errors.push(`examples[${i}] needs 'code' or 'url'`);
+        }
+        if (ex.code != null && (typeof ex.ecosystem !== "string" || !ECOSYSTEMS.has(ex.ecosystem))) {
+          errors.push(`examples[${i}] with 'code' needs a valid 'ecosystem'`);
+        }
+      });
+    }
+  }
+
+  return errors;
+}
+
+// ---------------------------------------------------------------------------
+// Example checks — does the entry detect its own fixtures?
+// ---------------------------------------------------------------------------
+
+function urlMatches(entry: SDKRegistryEntry, url: string): boolean {
+  // Vendors attributed by path (WooCommerce on the store's own domain)
+  if (pathVendor(url) === entry.provider) return true;
+  if ((entry.known_api_base_urls ?? []).some((base) => url.startsWith(base))) return true;
+  const host = extractHost(url);
+  return host != null && (entry.domains ?? []).some((d) => matchesDomain(host, d));
+}
+
+/** Run an entry's `examples`; returns one error per fixture it fails to detect */
+export function checkCatalogExamples(entry: SDKRegistryEntry): string[] {
+  const errors: string[] = [];
+  (entry.examples ?? []).forEach((ex, i) => {
+    if (ex.code != null && ex.ecosystem) {
+      const patterns = entry.patterns[ex.ecosystem as keyof SDKRegistryEntry["patterns"]] ?? [];
+      const hit = patterns.some((p) =>
+        [p.package, ...(p.import_patterns ?? [])].some((pat) => ex.code!.includes(pat)),
+      );
+      if (!hit) errors.push(`examples[${i}]: no ${ex.ecosystem} import pattern matches ${JSON.stringify(ex.code)}`);
+    }
+    if (ex.url != null && !urlMatches(entry, ex.url)) {
+      errors.push(`examples[${i}]: ${ex.url} matches no known_api_base_urls or domains`);
+    }
+  });
+  return errors;
+}
+
+// ---------------------------------------------------------------------------
+// File / directory validation
+// ---------------------------------------------------------------------------
+
+export interface CatalogValidationResult {
+  file: string;
+  errors: string[];
+}
+
+/**
+ * Validate catalog YAML files. Directories are expanded to their `*.yml`
+ * files. Also flags provider slugs duplicated across files, aliases that
+ * already name another vendor, and files whose name does not match their
+ * provider.
+ */
+export async function validateCatalogFiles(paths: string[]): Promise<CatalogValidationResult[]> {
+  const files: string[] = [];
+  for (const p of paths) {
+    const abs = resolve(p);
+    if ((await stat(abs)).isDirectory()) {
+      files.push(...(await fg.glob("*.yml", { cwd: abs, absolute: true })).sort());
+    } else {
+      files.push(abs);
+    }
+  }
+
+  const seen = new Map<string, string>();
+  const results: CatalogValidationResult[] = [];
+  const entries: Array<{ entry: SDKRegistryEntry; errors: string[] }> = [];
+  for (const file of files) {
+    let raw: unknown;
+    try {
+      raw = yaml.load(await readFile(file, "utf8"));
+    } catch (err) {
+      results.push({ file, errors: [`YAML parse error — ${err instanceof Error ? err.message : String(err)}`] });
+      continue;
+    }
+
+    const errors = validateCatalogEntry(raw);
+    if (errors.length === 0) {
+      const entry = raw as SDKRegistryEntry;
+      errors.push(...checkCatalogExamples(entry));
+      if (basename(file, ".yml") !== entry.provider) {
+        errors.push(`file name should be ${entry.provider}.yml`);
+      }
+      const previous = seen.get(entry.provider);
+      if (previous) errors.push(`provider '${entry.provider}' is already defined in ${basename(previous)}`);
+      seen.set(entry.provider, file);
+      entries.push({ entry, errors });
+    }
+    results.push({ file, errors });
+  }
+
+  // Every spelling must lead to one vendor, whichever file is read first
+  const owners = new Map<string, string>();
+  for (const { entry } of entries) owners.set(entry.provider, entry.provider);
+  for (const { entry, errors } of entries) {
+    for (const name of [entry.display_name, ...(entry.aliases ?? [])]) {
+      const key = normalizeVendorName(name);
+      const owner = owners.get(key);
+      if (owner && owner !== entry.provider) {
+        errors.push(`'${name}' already names ${owner}`);
+      } else if (!owner) {
+        owners.set(key, entry.provider);
+      }
+    }
+  }
+  return results;
+}
@@ -0,0 +1,241 @@
+/**
+ * @module catalog
+ *
+ * Remote vendor catalog bundles, so detector coverage can ship faster than
+ * CLI releases. A bundle is a single JSON file holding every registry entry,
+ * with a detached Ed25519 signature next to it:
+ *
+ *   <base>/latest.json                → { "version": "2026.10.1" }
+ *   <base>/<version>/catalog.json     → CatalogBundle
+ *   <base>/<version>/catalog.json.sig → base64 signature over catalog.json
+ *
+ * Installed bundles live in ~/.thirdwatch/catalog/<version>/ (override with
+ * THIRDWATCH_HOME). `current` in that directory names the bundle used when
+ * a scan does not pin one. Offline environments can vendor catalog.json and
+ * its .sig into the repository and point `--catalog-bundle` at it, or
+ * install it into the cache with `importCatalogBundle`. In offline mode
+ * nothing is downloaded.
+ */
+
+import { verify } from "node:crypto";
+import { mkdir, readFile, writeFile, rename } from "node:fs/promises";
+import { homedir } from "node:os";
+import { join, resolve } from "node:path";
+import type { SDKRegistryEntry } from "./registry.js";
+import { isValidRegistryEntry } from "./registry.js";
+import { assertOnline } from "./offline.js";
+
+export const DEFAULT_CATALOG_URL = "https://catalog.thirdwatch.dev/v1";
+
+/**
+ * Public keys trusted to sign catalog bundles. Listing more than one allows
+ * key rotation without breaking older CLIs.
+ */
+export const CATALOG_PUBLIC_KEYS = [
+  `-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEAM6bijU0oZhx8dI75dYwDrQaDK/fDz6WiPbHu1zdKpDY=
+-----END PUBLIC KEY-----`,
+];
+
+const FETCH_TIMEOUT_MS = 30_000;
+const VERSION_RE = /^[0-9A-Za-z][0-9A-Za-z.+-]{0,63}$/;
+
+export interface CatalogBundle {
+  /** Bundle version, e.g. "2026.10.1" */
+  version: string;
+  /** ISO 8601 build timestamp */
+  created_at: string;
+  entries: SDKRegistryEntry[];
+}
+
+// ---------------------------------------------------------------------------
+// Parsing + signature verification
+// ---------------------------------------------------------------------------
+
+function trustedKeys(): string[] {
+  const override = process.env["THIRDWATCH_CATALOG_PUBLIC_KEY"];
+  return override ? [override] : CATALOG_PUBLIC_KEYS;
+}
+
+/** Verify a detached base64 Ed25519 signature against the trusted keys */
+export function verifyCatalogSignature(
+  data: Buffer,
+  signature: string,
+  publicKeys: string[] = trustedKeys(),
+): boolean {
+  const sig = Buffer.from(signature.trim(), "base64");
+  if (sig.length === 0) return false;
+  return publicKeys.some((key) => {
+    try {
+      return verify(null, data, key, sig);
+    } catch {
+      return false;
+    }
+  });
+}
+
+/** Parse and shape-check a bundle; invalid entries are dropped */
+export function parseCatalogBundle(data: Buffer | string): CatalogBundle {
+  const raw = JSON.parse(data.toString()) as Partial<CatalogBundle>;
+  if (typeof raw.version !== "string" || !VERSION_RE.test(raw.version)) {
+    throw new Error("Catalog bundle has no valid version");
+  }
+  if (!Array.isArray(raw.entries)) {
+    throw new Error(`Catalog bundle ${raw.version} has no entries`);
+  }
+  return {
+    version: raw.version,
+    created_at: typeof raw.created_at === "string" ? raw.created_at : "",
+    entries: raw.entries.filter(isValidRegistryEntry),
+  };
+}
+
+/**
+ * Load a bundle file and its `.sig` sibling, rejecting unsigned or tampered
+ * bundles.
+ */
+export async function loadCatalogBundle(path: string): Promise<CatalogBundle> {
+  const file = resolve(path);
+  const [data, signature] = await Promise.all([
+    readFile(file),
+    readFile(`${file}.sig`, "utf8").catch(() => ""),
+  ]);
+  if (!verifyCatalogSignature(data, signature)) {
+    throw new Error(`Catalog bundle ${file} has a missing or invalid signature`);
+  }
+  return parseCatalogBundle(data);
+}
+
+// ---------------------------------------------------------------------------
+// Local cache
+// ---------------------------------------------------------------------------
+
+export function catalogCacheDir(): string {
+  return join(process.env["THIRDWATCH_HOME"] ?? join(homedir(), ".thirdwatch"), "catalog");
+}
+
+function bundlePath(version: string): string {
+  if (!VERSION_RE.test(version)) throw new Error(`Invalid catalog version: ${version}`);
+  return join(catalogCacheDir(), version, "catalog.json");
+}
+
+/** Version named by the cache's `current` pointer, if any */
+export async function currentCatalogVersion(): Promise<string | null> {
+  try {
+    const version = (await readFile(join(catalogCacheDir(), "current"), "utf8")).trim();
+    return VERSION_RE.test(version) ? version : null;
+  } catch {
+    return null;
+  }
+}
+
+// ---------------------------------------------------------------------------
+// Remote fetch
+// ---------------------------------------------------------------------------
+
+async function fetchOk(url: string): Promise<Response> {
+  const res = await fetch(url, { signal: AbortSignal.timeout(FETCH_TIMEOUT_MS) });
+  if (!res.ok) throw new Error(`GET ${url} returned ${res.status}`);
+  return res;
+}
+
+/**
+ * Download, verify, and install a bundle into the cache. With no version the
+ * latest published bundle is installed and becomes `current`.
+ */
+export async function updateCatalog(
+  options: { version?: string; baseUrl?: string; setCurrent?: boolean } = {},
+): Promise<CatalogBundle> {
+  assertOnline(
+    `Downloading catalog bundle ${options.version ?? "latest"}`,
+    "copy catalog.json and catalog.json.sig from a connected machine and run `thirdwatch catalog import`, or pass --catalog-bundle",
+  );
+  const baseUrl = (options.baseUrl ?? process.env["THIRDWATCH_CATALOG_URL"] ?? DEFAULT_CATALOG_URL)
+    .replace(/\/+$/, "");
+
+  let version = options.version;
+  if (!version) {
+    const latest = (await (await fetchOk(`${baseUrl}/latest.json`)).json()) as { version?: unknown };
+    if (typeof latest.version !== "string") throw new Error("latest.json has no version");
+    version = latest.version;
+  }
+  const target = bundlePath(version);
+
+  const [data, signature] = await Promise.all([
+    fetchOk(`${baseUrl}/${version}/catalog.json`).then(async (r) => Buffer.from(await r.arrayBuffer())),
+    fetchOk(`${baseUrl}/${version}/catalog.json.sig`).then((r) => r.text()),
+  ]);
+  if (!verifyCatalogSignature(data, signature)) {
+    throw new Error(`Catalog bundle ${version} failed signature verification`);
+  }
+  const bundle = parseCatalogBundle(data);
+  if (bundle.version !== version) {
+    throw new Error(`Catalog bundle claims version ${bundle.version}, expected ${version}`);
+  }
+
+  await installBundle(target, data, signature, options.setCurrent ?? !options.version, version);
+  return bundle;
+}
+
+async function installBundle(target: string, data: Buffer, signature: string, setCurrent: boolean, version: string): Promise<void> {
+  await mkdir(join(catalogCacheDir(), version), { recursive: true });
+  await writeFile(`${target}.sig`, signature);
+  // Write-then-rename so a concurrent scan never reads a partial bundle
+  await writeFile(`${target}.tmp`, data);
+  await rename(`${target}.tmp`, target);
+
+  if (setCurrent) {
+    await writeFile(join(catalogCacheDir(), "current"), `${version}\n`);
+  }
+}
+
+/**
+ * Install a bundle copied from elsewhere (and its `.sig` sibling) into the
+ * cache, for hosts that can't download one. It becomes `current` unless
+ * `setCurrent` is false.
+ */
+export async function importCatalogBundle(path: string, options: { setCurrent?: boolean } = {}): Promise<CatalogBundle> {
+  const file = resolve(path);
+  const [data, signature] = await Promise.all([readFile(file), readFile(`${file}.sig`, "utf8").catch(() => "")]);
+  if (!verifyCatalogSignature(data, signature)) {
+    throw new Error(`Catalog bundle ${file} has a missing or invalid signature`);
+  }
+  const bundle = parseCatalogBundle(data);
+  await installBundle(bundlePath(bundle.version), data, signature, options.setCurrent ?? true, bundle.version);
+  return bundle;
+}
+
+// ---------------------------------------------------------------------------
+// Resolution for scans
+// ---------------------------------------------------------------------------
+
+export interface CatalogSelection {
+  /** Vendored bundle file (offline) — takes precedence over everything */
+  bundlePath?: string;
+  /** Pinned version; fetched into the cache on first use */
+  version?: string;
+}
+
+/**
+ * Pick the bundle for a scan: vendored file, then pinned version, then the
+ * cache's `current`. Returns null to fall back to the built-in registries.
+ */
+export async function resolveCatalog(selection: CatalogSelection = {}): Promise<CatalogBundle | null> {
+  if (selection.bundlePath) return loadCatalogBundle(selection.bundlePath);
+
+  if (selection.version) {
+    try {
+      return await loadCatalogBundle(bundlePath(selection.version));
+    } catch {
+      return updateCatalog({ version: selection.version, setCurrent: false });
+    }
+  }
+
+  const current = await currentCatalogVersion();
+  if (!current) return null;
+  try {
+    return await loadCatalogBundle(bundlePath(current));
+  } catch {
+    return null;
+  }
+}
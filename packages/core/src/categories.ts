@@ -0,0 +1,129 @@
+/**
+ * @module categories
+ *
+ * Copy each vendor's catalog category onto the SDK and API findings that
+ * belong to it, so policy can be written per category ("every email
+ * delivery provider") rather than per vendor. API calls made without an SDK
+ * are attributed by host and get that vendor as provider, except where the path names the vendor better:
+ * platforms merchants host themselves (WooCommerce's /wp-json/wc/v3/ on the
+ * store's own domain) and endpoints on a shared host (reCAPTCHA's
+ * www.google.com/recaptcha/; Calendar and Drive on www.googleapis.com, which
+ * is otherwise Google Cloud; Fixer on the APILayer marketplace's
+ * api.apilayer.com/fixer/). Infrastructure connections hosted by a catalog
+ * vendor (cluster0.abcde.mongodb.net) get that vendor as provider; without a
+ * known host, a driver type that names a vendor (sql.Open("snowflake", ...))
+ * still supplies the category. Clusters on any other host get neither. Categories
+ * set by custom rules are kept. Inbound webhook routes with no known sender get
+ * the vendor their path names (/webhooks/stripe).
+ *
+ * Some categories export data by design, whatever else the analyzer found:
+ * error-tracking SDKs ship stack traces; feature-flag SDKs send the
+ * evaluation context (user keys and attributes) with every evaluation;
+ * analytics SDKs send behavioral events tied to user identifiers; banking
+ * aggregators receive the linked accounts' balances and transactions;
+ * commerce platforms hold the shoppers' names, addresses, and orders;
+ * shipping APIs receive the addresses labels are printed for; push services
+ * receive the device tokens of everyone notified; e-signature platforms hold
+ * the contracts and the signers' names and emails; maps and geocoding APIs
+ * receive the addresses and coordinates they resolve; IP intelligence APIs
+ * receive the end-user addresses they look up; media pipelines receive the
+ * photos and videos users upload; bot-mitigation vendors score the visitor's
+ * browser and behavior; enrichment APIs receive the email addresses and
+ * company domains they build profiles from; and identity-verification vendors
+ * receive ID documents and selfies. Those SDKs are marked as exporting it, for
+ * DPIA and subprocessor reviews.
+ *
+ * Identity verification and enrichment are the most sensitive of these, so
+ * their findings default to "warning" severity; `rule_settings` can still
+ * change that.
+ */
+
+import type { Severity } from "@thirdwatch/tdm";
+import type { DependencyEntry } from "./plugin.js";
+import type { SDKRegistryEntry } from "./registry.js";
+import { extractHost } from "./first-party.js";
+import { createVendorMatcher } from "./runtime.js";
+
+/** Data every SDK in a category exports, listed first in data_exported */
+const CATEGORY_DATA_EXPORTED: Record<string, string[]> = {
+  "error-tracking": ["stack_traces"],
+  "feature-flags": ["user_context"],
+  analytics: ["behavioral_events", "user_identifiers"],
+  banking: ["financial_data"],
+  commerce: ["customer_data"],
+  documents: ["documents", "user_data"],
+  shipping: ["customer_addresses"],
+  push: ["device_tokens"],
+  maps: ["location_data"],
+  "ip-intelligence": ["ip_addresses"],
+  media: ["user_content"],
+  "identity-verification": ["identity_documents", "biometrics"],
+  "bot-mitigation": ["browsing_signals"],
+  enrichment: ["email_addresses", "company_data"],
+};
+
+/** Severity for high-sensitivity categories when no rule setting applies */
+const CATEGORY_SEVERITY: Record<string, Severity> = {
+  "identity-verification": "warning",
+  enrichment: "warning",
+};
+
+/** Vendors recognised by their API path rather than a host of their own */
+const PATH_VENDORS: [RegExp, string][] = [
+  [/\/wp-json\/wc\/v\d+\//, "woocommerce"],
+  [/^https?:\/\/www\.google\.com\/recaptcha\//, "recaptcha"],
+  [/^https:\/\/www\.googleapis\.com\/(?:upload\/)?(?:calendar|drive|gmail|admin\/directory)\//, "google-workspace"],
+  [/^https:\/\/api\.apilayer\.com\/fixer\//, "fixer"],
+];
+
+/** The vendor an API URL's path names, for hosts the path vendor doesn't own */
+export function pathVendor(url: string): string | null {
+  return PATH_VENDORS.find(([pattern]) => pattern.test(url))?.[1] ?? null;
+}
+
+export function applyCatalogCategories(
+  entries: DependencyEntry[],
+  registry: SDKRegistryEntry[],
+): void {
+  const categories = new Map<string, string>();
+  for (const entry of registry) {
+    if (entry.category) categories.set(entry.provider, entry.category);
+  }
+
+  const matchVendor = createVendorMatcher(registry);
+  for (const entry of entries) {
+    if (entry.kind === "infrastructure") {
+      const host = extractHost(entry.resolved_host ?? entry.connection_ref);
+      const vendor = host ? matchVendor(host) : null;
+      if (vendor) entry.provider ??= vendor;
+      const provider = entry.provider ?? (host ? null : matchVendor(entry.type));
+      const category = provider ? categories.get(provider) : undefined;
+      if (category && !entry.category) entry.category = category;
+      continue;
+    }
+    if (entry.kind === "webhook") {
+      // Inbound callback routes usually name their sender: /webhooks/stripe
+      if (entry.direction === "inbound_callback" && !entry.provider) {
+        const sender = entry.target_url.toLowerCase().split(/[/_-]/).find((s) => categories.has(s));
+        if (sender) entry.provider = sender;
+      }
+      continue;
+    }
+    if ((entry.kind !== "sdk" && entry.kind !== "api") || entry.category) continue;
+    let provider = entry.provider ?? null;
+    if (!provider && entry.kind === "api") {
+      const url = entry.resolved_url ?? entry.url;
+      const host = extractHost(url);
+      provider = pathVendor(url) ?? (host ? matchVendor(host) : null);
+      if (provider) entry.provider = provider;
+    }
+    const category = provider ? categories.get(provider) : undefined;
+    if (category) entry.category = category;
+    const severity = entry.category ? CATEGORY_SEVERITY[entry.category] : undefined;
+    if (severity) entry.severity ??= severity;
+    const exported = entry.kind === "sdk" && entry.category ? CATEGORY_DATA_EXPORTED[entry.category] : undefined;
+    if (entry.kind === "sdk" && exported) {
+      entry.data_exported = [...exported, ...(entry.data_exported ?? []).filter((d) => !exported.includes(d))];
+    }
+  }
+}
@@ -0,0 +1,329 @@
+/**
+ * @module compare
+ *
+ * Third-party dependency delta between two scans of the same repository,
+ * behind `thirdwatch compare --from v1.4.0 --to v1.5.0`. Reads like release
+ * notes for change-management records:
+ *
+ *   vendors         — vendors the release starts or stops depending on
+ *   packages        — libraries added, removed, or moved to another version
+ *   sdks            — vendor SDK usages added or removed, and SDKs whose
+ *                     call sites or called methods changed
+ *   endpoints       — external API calls (method + URL) added or removed,
+ *                     and endpoints whose number of call sites changed
+ *   infrastructure  — database, queue, and storage connections added or removed
+ *   webhooks        — webhook registrations and callbacks added or removed
+ *
+ * First-party (internal) APIs, connections, and webhooks are left out.
+ */
+
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "./registry.js";
+import { collectVendorUsages } from "./sla.js";
+import { createVendorMatcher } from "./runtime.js";
+
+export interface CompareSide {
+  /** Git ref or file the scan came from, e.g. "v1.4.0" */
+  ref: string;
+  /** Commit the ref resolved to, when it is a git ref */
+  commit?: string;
+}
+
+export interface VendorDelta {
+  vendor: string;
+  display_name: string;
+  category?: string;
+  /** Usages on the side where the vendor is present */
+  usages: number;
+}
+
+export interface PackageDelta {
+  ecosystem: string;
+  name: string;
+  /** Versions across manifests, comma-separated */
+  version: string;
+}
+
+export interface PackageChange {
+  ecosystem: string;
+  name: string;
+  from_version: string;
+  to_version: string;
+}
+
+export interface SdkDelta {
+  provider: string;
+  sdk_package: string;
+  usages: number;
+}
+
+export interface SdkChange {
+  provider: string;
+  sdk_package: string;
+  from_usages: number;
+  to_usages: number;
+  methods_added: string[];
+  methods_removed: string[];
+}
+
+export interface EndpointDelta {
+  method: string;
+  url: string;
+  provider?: string;
+  usages: number;
+}
+
+export interface EndpointChange {
+  method: string;
+  url: string;
+  provider?: string;
+  from_usages: number;
+  to_usages: number;
+}
+
+export interface ConnectionDelta {
+  /** Infrastructure type, or the webhook direction */
+  type: string;
+  /** Connection reference, or the webhook target */
+  target: string;
+  provider?: string;
+}
+
+export interface ComparisonReport {
+  from: CompareSide;
+  to: CompareSide;
+  repository?: string;
+  vendors: { added: VendorDelta[]; removed: VendorDelta[] };
+  packages: { added: PackageDelta[]; removed: PackageDelta[]; changed: PackageChange[] };
+  sdks: { added: SdkDelta[]; removed: SdkDelta[]; changed: SdkChange[] };
+  endpoints: { added: EndpointDelta[]; removed: EndpointDelta[]; changed: EndpointChange[] };
+  infrastructure: { added: ConnectionDelta[]; removed: ConnectionDelta[] };
+  webhooks: { added: ConnectionDelta[]; removed: ConnectionDelta[] };
+}
+
+/** Keyed entries present only on one side, and pairs present on both */
+function split<T>(before: Map<string, T>, after: Map<string, T>) {
+  const byKey = (a: [string, T], b: [string, T]) => a[0].localeCompare(b[0]);
+  return {
+    added: [...after].filter(([k]) => !before.has(k)).sort(byKey).map(([, v]) => v),
+    removed: [...before].filter(([k]) => !after.has(k)).sort(byKey).map(([, v]) => v),
+    both: [...after].filter(([k]) => before.has(k)).sort(byKey).map(([k, v]) => [before.get(k)!, v] as const),
+  };
+}
+
+function vendorUsages(tdm: TDM, matchVendor: (host: string) => string | null): Map<string, number> {
+  const usages = new Map<string, number>();
+  for (const u of collectVendorUsages(tdm, matchVendor)) usages.set(u.vendor, (usages.get(u.vendor) ?? 0) + u.count);
+  return usages;
+}
+
+function packageVersions(tdm: TDM): Map<string, PackageDelta> {
+  const versions = new Map<string, { ecosystem: string; name: string; versions: Set<string> }>();
+  for (const pkg of tdm.packages) {
+    const key = `${pkg.ecosystem}/${pkg.name}`;
+    const entry = versions.get(key) ?? { ecosystem: pkg.ecosystem, name: pkg.name, versions: new Set<string>() };
+    entry.versions.add(pkg.current_version);
+    versions.set(key, entry);
+  }
+  return new Map(
+    [...versions].map(([key, p]) => [key, { ecosystem: p.ecosystem, name: p.name, version: [...p.versions].sort().join(", ") }]),
+  );
+}
+
+function sdkUsages(tdm: TDM): Map<string, { delta: SdkDelta; methods: Set<string> }> {
+  const sdks = new Map<string, { delta: SdkDelta; methods: Set<string> }>();
+  for (const sdk of tdm.sdks) {
+    const key = `${sdk.provider}/${sdk.sdk_package}`;
+    const entry = sdks.get(key) ?? { delta: { provider: sdk.provider, sdk_package: sdk.sdk_package, usages: 0 }, methods: new Set<string>() };
+    entry.delta.usages += sdk.usage_count;
+    for (const m of sdk.api_methods ?? []) entry.methods.add(m);
+    sdks.set(key, entry);
+  }
+  return sdks;
+}
+
+function endpoints(tdm: TDM): Map<string, EndpointDelta> {
+  const result = new Map<string, EndpointDelta>();
+  for (const api of tdm.apis) {
+    if (api.first_party) continue;
+    const method = api.method ?? "ANY";
+    const key = `${method} ${api.url}`;
+    const entry = result.get(key) ?? { method, url: api.url, ...(api.provider ? { provider: api.provider } : {}), usages: 0 };
+    entry.usages += api.usage_count;
+    result.set(key, entry);
+  }
+  return result;
+}
+
+function connections(tdm: TDM, kind: "infrastructure" | "webhooks"): Map<string, ConnectionDelta> {
+  const items =
+    kind === "infrastructure"
+      ? tdm.infrastructure.map((i) => ({ first_party: i.first_party, type: i.type, target: i.connection_ref, provider: i.provider }))
+      : tdm.webhooks.map((w) => ({ first_party: w.first_party, type: w.direction, target: w.target_url, provider: w.provider }));
+  const result = new Map<string, ConnectionDelta>();
+  for (const { first_party, type, target, provider } of items) {
+    if (first_party) continue;
+    result.set(`${type} ${target}`, { type, target, ...(provider ? { provider } : {}) });
+  }
+  return result;
+}
+
+export function compareTDMs(
+  from: TDM,
+  to: TDM,
+  registry: SDKRegistryEntry[],
+  sides: { from: CompareSide; to: CompareSide },
+): ComparisonReport {
+  const entries = new Map(registry.map((e) => [e.provider, e]));
+  const matchVendor = createVendorMatcher(registry);
+  const vendorDelta = (usages: Map<string, number>) =>
+    new Map(
+      [...usages].map(([vendor, count]) => {
+        const entry = entries.get(vendor);
+        return [
+          vendor,
+          {
+            vendor,
+            display_name: entry?.display_name ?? vendor,
+            ...(entry?.category ? { category: entry.category } : {}),
+            usages: count,
+          },
+        ];
+      }),
+    );
+  const vendors = split(vendorDelta(vendorUsages(from, matchVendor)), vendorDelta(vendorUsages(to, matchVendor)));
+
+  const packages = split(packageVersions(from), packageVersions(to));
+  const sdks = split(sdkUsages(from), sdkUsages(to));
+  const apis = split(endpoints(from), endpoints(to));
+  const infrastructure = split(connections(from, "infrastructure"), connections(to, "infrastructure"));
+  const webhooks = split(connections(from, "webhooks"), connections(to, "webhooks"));
+
+  const repository = to.metadata.repository ?? from.metadata.repository;
+  return {
+    from: sides.from,
+    to: sides.to,
+    ...(repository ? { repository } : {}),
+    vendors: { added: vendors.added, removed: vendors.removed },
+    packages: {
+      added: packages.added,
+      removed: packages.removed,
+      changed: packages.both
+        .filter(([a, b]) => a.version !== b.version)
+        .map(([a, b]) => ({ ecosystem: b.ecosystem, name: b.name, from_version: a.version, to_version: b.version })),
+    },
+    sdks: {
+      added: sdks.added.map((s) => s.delta),
+      removed: sdks.removed.map((s) => s.delta),
+      changed: sdks.both
+        .map(([a, b]) => ({
+          provider: b.delta.provider,
+          sdk_package: b.delta.sdk_package,
+          from_usages: a.delta.usages,
+          to_usages: b.delta.usages,
+          methods_added: [...b.methods].filter((m) => !a.methods.has(m)).sort(),
+          methods_removed: [...a.methods].filter((m) => !b.methods.has(m)).sort(),
+        }))
+        .filter((c) => c.from_usages !== c.to_usages || c.methods_added.length > 0 || c.methods_removed.length > 0),
+    },
+    endpoints: {
+      added: apis.added,
+      removed: apis.removed,
+      changed: apis.both
+        .filter(([a, b]) => a.usages !== b.usages)
+        .map(([a, b]) => ({
+          method: b.method,
+          url: b.url,
+          ...(b.provider ? { provider: b.provider } : {}),
+          from_usages: a.usages,
+          to_usages: b.usages,
+        })),
+    },
+    infrastructure: { added: infrastructure.added, removed: infrastructure.removed },
+    webhooks: { added: webhooks.added, removed: webhooks.removed },
+  };
+}
+
+/** Whether the two scans differ at all */
+export function hasChanges(report: ComparisonReport): boolean {
+  return [report.vendors, report.packages, report.sdks, report.endpoints, report.infrastructure, report.webhooks].some(
+    (section) => Object.values(section).some((list: unknown[]) => list.length > 0),
+  );
+}
+
+// ---------------------------------------------------------------------------
+// Markdown
+// ---------------------------------------------------------------------------
+
+function side(s: CompareSide): string {
+  return s.commit ? `${s.ref} (${s.commit.slice(0, 7)})` : s.ref;
+}
+
+/** Backslash-escape characters that would break a Markdown list item */
+function md(value: string): string {
+  return value.replace(/[\\`*_[\]<>|]/g, "\\$&");
+}
+
+function plural(n: number, word: string): string {
+  return `${n} ${word}${n === 1 ? "" : "s"}`;
+}
+
+function section(title: string, items: string[]): string[] {
+  return items.length > 0 ? [`### ${title}`, "", ...items.map((i) => `- ${i}`), ""] : [];
+}
+
+const via = (provider: string | undefined) => (provider ? ` (${md(provider)})` : "");
+
+/** Release-notes Markdown for a change record or a release description */
+export function renderComparisonMarkdown(report: ComparisonReport): string {
+  const lines = [
+    `## Third-party dependency changes: ${md(side(report.from))} → ${md(side(report.to))}`,
+    "",
+    ...(report.repository ? [`Repository: ${md(report.repository)}`, ""] : []),
+  ];
+  if (!hasChanges(report)) {
+    lines.push("No third-party dependency changes.", "");
+    return lines.join("\n");
+  }
+
+  const change = (from: number, to: number) => `${from} → ${plural(to, "call site")}`;
+  lines.push(
+    ...section(
+      "New vendors",
+      report.vendors.added.map((v) => `**${md(v.display_name)}**${v.category ? ` (${md(v.category)})` : ""} — ${plural(v.usages, "usage")}`),
+    ),
+    ...section(
+      "Removed vendors",
+      report.vendors.removed.map((v) => `**${md(v.display_name)}**${v.category ? ` (${md(v.category)})` : ""}`),
+    ),
+    ...section("Added packages", report.packages.added.map((p) => `${md(p.name)} ${md(p.version)} (${md(p.ecosystem)})`)),
+    ...section("Removed packages", report.packages.removed.map((p) => `${md(p.name)} ${md(p.version)} (${md(p.ecosystem)})`)),
+    ...section(
+      "Updated packages",
+      report.packages.changed.map((p) => `${md(p.name)} ${md(p.from_version)} → ${md(p.to_version)} (${md(p.ecosystem)})`),
+    ),
+    ...section("New SDK usage", report.sdks.added.map((s) => `${md(s.sdk_package)} (${md(s.provider)}) — ${plural(s.usages, "call site")}`)),
+    ...section("Removed SDK usage", report.sdks.removed.map((s) => `${md(s.sdk_package)} (${md(s.provider)})`)),
+    ...section(
+      "Changed SDK usage",
+      report.sdks.changed.map((s) =>
+        [
+          `${md(s.sdk_package)} (${md(s.provider)}) — ${change(s.from_usages, s.to_usages)}`,
+          ...(s.methods_added.length > 0 ? [`now calls ${s.methods_added.map((m) => `\`${m}\``).join(", ")}`] : []),
+          ...(s.methods_removed.length > 0 ? [`no longer calls ${s.methods_removed.map((m) => `\`${m}\``).join(", ")}`] : []),
+        ].join("; "),
+      ),
+    ),
+    ...section("New endpoints", report.endpoints.added.map((e) => `\`${e.method} ${e.url}\`${via(e.provider)}`)),
+    ...section("Removed endpoints", report.endpoints.removed.map((e) => `\`${e.method} ${e.url}\`${via(e.provider)}`)),
+    ...section(
+      "Changed endpoints",
+      report.endpoints.changed.map((e) => `\`${e.method} ${e.url}\`${via(e.provider)} — ${change(e.from_usages, e.to_usages)}`),
+    ),
+    ...section("New infrastructure", report.infrastructure.added.map((c) => `${md(c.type)} \`${c.target}\`${via(c.provider)}`)),
+    ...section("Removed infrastructure", report.infrastructure.removed.map((c) => `${md(c.type)} \`${c.target}\`${via(c.provider)}`)),
+    ...section("New webhooks", report.webhooks.added.map((c) => `${md(c.type.replace(/_/g, " "))} \`${c.target}\`${via(c.provider)}`)),
+    ...section("Removed webhooks", report.webhooks.removed.map((c) => `${md(c.type.replace(/_/g, " "))} \`${c.target}\`${via(c.provider)}`)),
+  );
+  return lines.join("\n");
+}
@@ -0,0 +1,88 @@
+/**
+ * @module compatible-endpoints
+ *
+ * Clients of a widely implemented API pointed somewhere other than its
+ * vendor. The openai SDKs also talk to Azure OpenAI, vLLM, Ollama, and hosted
+ * gateways (Groq, OpenRouter, ...) through a custom base URL; the S3 clients
+ * talk to Cloudflare R2, Backblaze B2, DigitalOcean Spaces, and MinIO through
+ * a custom endpoint. Analyzers record that URL as `base_url` — env lookups as
+ * `${VAR}` — and this pass reports the provider actually behind it:
+ *
+ *   https://acme.openai.azure.com                   → azure-openai (any catalog vendor)
+ *   https://<account>.r2.cloudflarestorage.com      → cloudflare-r2
+ *   http://localhost:8000/v1, http://minio:9000     → self-hosted
+ *   https://llm-gateway.example.com                 → openai-compatible
+ *   https://objects.example.com                     → s3-compatible
+ *
+ * Clients with no explicit base URL read the SDK's endpoint variable
+ * (OPENAI_BASE_URL, AWS_ENDPOINT_URL_S3), as the SDKs do. A client pointed at
+ * an egress gateway declared for its vendor (see gateways.ts) still reaches
+ * that vendor and keeps its provider.
+ */
+
+import type { DependencyEntry } from "./plugin.js";
+import type { SDKRegistryEntry } from "./registry.js";
+import { extractHost } from "./first-party.js";
+import { gatewayFor, isGatewayUrl } from "./gateways.js";
+import type { GatewayPolicy } from "./gateways.js";
+import { resolveUrl } from "./resolve.js";
+import { createVendorMatcher, isPrivateIp } from "./runtime.js";
+
+/**
+ * SDKs whose wire protocol other services implement → the env vars they read
+ * (most specific first) and the provider reported for an unknown host
+ */
+const COMPATIBLE_SDKS: Record<string, { env: string[]; compatible: string }> = {
+  openai: { env: ["OPENAI_BASE_URL"], compatible: "openai-compatible" },
+  "aws-s3": { env: ["AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"], compatible: "s3-compatible" },
+};
+
+/** Loopback, private, and internal-only names: a model server the team runs */
+function isSelfHosted(host: string): boolean {
+  return (
+    host === "localhost" ||
+    isPrivateIp(host) ||
+    !host.includes(".") ||
+    /\.(?:internal|local|localdomain|lan|svc|cluster\.local)$/.test(host)
+  );
+}
+
+/**
+ * Build a resolver that reassigns compatible-API SDK entries to the provider
+ * behind their base URL. Entries whose URL can't be resolved keep the SDK's
+ * own provider, as do those pointed at another endpoint of the same vendor
+ * (an S3 client on a regional or FIPS amazonaws.com host).
+ */
+export function createCompatibleEndpointResolver(
+  registry: SDKRegistryEntry[],
+  env: Record<string, string>,
+  gateways: GatewayPolicy[] = [],
+): (entries: DependencyEntry[]) => void {
+  const matchVendor = createVendorMatcher(registry);
+  const categories = new Map(registry.map((e) => [e.provider, e.category]));
+
+  return (entries) => {
+    for (const entry of entries) {
+      if (entry.kind !== "sdk") continue;
+      const sdk = COMPATIBLE_SDKS[entry.provider];
+      if (!sdk) continue;
+
+      const envVar = sdk.env.find((name) => env[name]);
+      const template = entry.base_url ?? (envVar ? `\${${envVar}}` : undefined);
+      if (!template) continue;
+      const { resolved } = resolveUrl(template, env);
+      const host = resolved ? extractHost(resolved) : null;
+      if (!resolved || !host) continue;
+
+      entry.base_url = resolved;
+      const vendor = matchVendor(host);
+      if (vendor === entry.provider || (vendor && entry.provider.startsWith(`${vendor}-`))) continue;
+
+      const category = categories.get(entry.provider);
+      const gateway = gatewayFor(gateways, entry.provider, category);
+      if (gateway && isGatewayUrl(resolved, gateway)) continue;
+      entry.provider = vendor ?? (isSelfHosted(host) ? "self-hosted" : sdk.compatible);
+      if (!vendor && category) entry.category = category;
+    }
+  };
+}
@@ -0,0 +1,172 @@
+/**
+ * @module concentration
+ *
+ * Vendor concentration across an organization's services, behind
+ * `thirdwatch concentration` and GET /api/v1/inventory/concentration. For
+ * business-continuity planning the question is not "what does this repo
+ * use" but "which vendor outage takes down the most of us":
+ *
+ *   reach          — share of services that depend on the vendor
+ *   critical reach — share of the services marked critical that do
+ *   lock-in        — share of its dependents with no same-category
+ *                    alternative, i.e. where it is a single point of failure
+ *
+ * The criticality score weighs the three (0.5 / 0.3 / 0.2; reach takes the
+ * critical weight when no services are marked critical) into 0–100. Per
+ * category, the Herfindahl–Hirschman index over services shows whether the
+ * organization has standardized on one vendor (10000) or spread its risk.
+ */
+
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "./registry.js";
+import { computeSlaReport } from "./sla.js";
+import { splitByService } from "./services.js";
+
+export interface ServiceInventory {
+  service: string;
+  /** The service's static scan and runtime reports */
+  tdms: TDM[];
+}
+
+export type CriticalityTier = "high" | "medium" | "low";
+
+export interface VendorConcentration {
+  vendor: string;
+  display_name: string;
+  category?: string;
+  /** Services that depend on the vendor */
+  services: string[];
+  /** Critical services among them */
+  critical_services: string[];
+  /** Services where no same-category vendor could stand in */
+  sole_provider_in: string[];
+  reach: number;
+  critical_reach: number;
+  lock_in: number;
+  score: number;
+  tier: CriticalityTier;
+  /** Published uptime commitment, when the catalog has one */
+  uptime: number | null;
+}
+
+export interface CategoryConcentration {
+  category: string;
+  /** Services per vendor, most-used first */
+  vendors: Array<{ vendor: string; services: number }>;
+  /** Herfindahl–Hirschman index of service shares, 0–10000 */
+  hhi: number;
+}
+
+export interface ConcentrationReport {
+  total_services: number;
+  critical_services: string[];
+  /** Highest score first */
+  vendors: VendorConcentration[];
+  categories: CategoryConcentration[];
+  /** Vendors two or more critical services depend on */
+  shared_critical_vendors: string[];
+}
+
+export interface ConcentrationOptions {
+  /** Services on the critical path; matched against ServiceInventory.service */
+  critical?: string[];
+}
+
+function tier(score: number): CriticalityTier {
+  return score >= 60 ? "high" : score >= 30 ? "medium" : "low";
+}
+
+const ratio = (n: number, d: number) => (d === 0 ? 0 : Math.round((n / d) * 1000) / 1000);
+
+export function computeConcentration(
+  inventory: ServiceInventory[],
+  registry: SDKRegistryEntry[],
+  options: ConcentrationOptions = {},
+): ConcentrationReport {
+  const critical = new Set(options.critical ?? []);
+  const criticalPresent = inventory.filter((s) => critical.has(s.service)).map((s) => s.service);
+  const byVendor = new Map<string, VendorConcentration>();
+
+  for (const { service, tdms } of inventory) {
+    const report = computeSlaReport(tdms, registry, { service });
+    for (const dep of report.dependencies) {
+      let v = byVendor.get(dep.vendor);
+      if (!v) {
+        v = {
+          vendor: dep.vendor,
+          display_name: dep.display_name,
+          ...(dep.category ? { category: dep.category } : {}),
+          services: [],
+          critical_services: [],
+          sole_provider_in: [],
+          reach: 0,
+          critical_reach: 0,
+          lock_in: 0,
+          score: 0,
+          tier: "low",
+          uptime: null,
+        };
+        byVendor.set(dep.vendor, v);
+      }
+      // Scoped commitments (e.g. one AWS service) appear once per service
+      if (v.services.includes(service)) continue;
+      v.services.push(service);
+      if (critical.has(service)) v.critical_services.push(service);
+      if (dep.single_point_of_failure) v.sole_provider_in.push(service);
+      if (!dep.scope) v.uptime = dep.uptime;
+    }
+  }
+
+  const total = inventory.length;
+  for (const v of byVendor.values()) {
+    v.reach = ratio(v.services.length, total);
+    v.critical_reach = ratio(v.critical_services.length, criticalPresent.length);
+    v.lock_in = ratio(v.sole_provider_in.length, v.services.length);
+    const weighted = criticalPresent.length > 0
+      ? 0.5 * v.reach + 0.3 * v.critical_reach + 0.2 * v.lock_in
+      : 0.8 * v.reach + 0.2 * v.lock_in;
+    v.score = Math.round(weighted * 100);
+    v.tier = tier(v.score);
+  }
+
+  const vendors = [...byVendor.values()].sort(
+    (a, b) => b.score - a.score || b.services.length - a.services.length || a.vendor.localeCompare(b.vendor),
+  );
+
+  const byCategory = new Map<string, Array<{ vendor: string; services: number }>>();
+  for (const v of vendors) {
+    if (!v.category) continue;
+    byCategory.set(v.category, [...(byCategory.get(v.category) ?? []), { vendor: v.vendor, services: v.services.length }]);
+  }
+  const categories = [...byCategory].map(([category, list]) => {
+    const sum = list.reduce((a, x) => a + x.services, 0);
+    return {
+      category,
+      vendors: list.sort((a, b) => b.services - a.services),
+      hhi: Math.round(list.reduce((a, x) => a + ((x.services / sum) * 100) ** 2, 0)),
+    };
+  }).sort((a, b) => b.hhi - a.hhi || a.category.localeCompare(b.category));
+
+  return {
+    total_services: total,
+    critical_services: criticalPresent,
+    vendors,
+    categories,
+    shared_critical_vendors: vendors.filter((v) => v.critical_services.length >= 2).map((v) => v.vendor),
+  };
+}
+
+/**
+ * Group TDMs into services by repository, falling back to `fallbackName(i)`.
+ * A monorepo scan with `services` mapped is split into its logical services;
+ * findings outside every mapped directory stay with the repository.
+ */
+export function groupByService(tdms: TDM[], fallbackName: (index: number) => string): ServiceInventory[] {
+  const services = new Map<string, TDM[]>();
+  tdms.forEach((tdm, i) => {
+    for (const [service, part] of splitByService(tdm, tdm.metadata.repository ?? fallbackName(i))) {
+      services.set(service, [...(services.get(service) ?? []), part]);
+    }
+  });
+  return [...services].map(([service, list]) => ({ service, tdms: list }));
+}
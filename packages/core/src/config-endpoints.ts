@@ -0,0 +1,89 @@
+/**
+ * @module config-endpoints
+ *
+ * Vendor endpoints and credentials set in configuration files, where an
+ * environment's overlay decides which vendor account the code talks to:
+ *
+ *   payments.base_url: https://api-m.sandbox.paypal.com   → paypal endpoint
+ *   STRIPE_SECRET_KEY: sk_test_51H...                     → stripe, test key
+ *   openai: { api_key: "sk-proj-..." }                    → openai endpoint
+ *
+ * Only hosts the catalog knows are reported, so service-internal URLs and
+ * documentation links stay out. Literal credentials are flagged as secrets by
+ * the scanner like any other location.
+ */
+
+import type { DependencyEntry } from "./plugin.js";
+import type { VendorMatcher } from "./runtime.js";
+import { extractHost } from "./first-party.js";
+import { detectHardcodedSecret } from "./secrets.js";
+
+// Keys whose URLs describe the file rather than configure a client
+export const DOC_KEY_RE = /^\s*["']?(?:\$schema|\$id|homepage|documentation|docs?(?:_url)?|repository|bugs|license|url\.docs)["']?\s*[:=]/i;
+
+const URL_RE = /\bhttps?:\/\/[^\s"'<>,`)}\]]+/g;
+
+// Credential kinds that name the API they authorize
+const CREDENTIAL_ENDPOINTS: Record<string, string> = {
+  stripe_secret_key: "https://api.stripe.com",
+  stripe_publishable_key: "https://api.stripe.com",
+  openai_api_key: "https://api.openai.com",
+  anthropic_api_key: "https://api.anthropic.com",
+  slack_token: "https://slack.com/api",
+  huggingface_token: "https://huggingface.co",
+  replicate_api_token: "https://api.replicate.com",
+};
+
+// Publishable keys aren't secrets, but still say which Stripe account is used
+const STRIPE_PUBLISHABLE_RE = /\bpk_(?:live|test)_[A-Za-z0-9]{16,}/;
+
+function credentialEndpoint(line: string): string | null {
+  const secret = detectHardcodedSecret(line);
+  if (secret && CREDENTIAL_ENDPOINTS[secret.kind]) return CREDENTIAL_ENDPOINTS[secret.kind]!;
+  return STRIPE_PUBLISHABLE_RE.test(line) ? CREDENTIAL_ENDPOINTS.stripe_publishable_key! : null;
+}
+
+/** Find catalog vendor URLs and vendor credentials in one config file */
+export function detectConfigEndpoints(source: string, relPath: string, matchVendor: VendorMatcher): DependencyEntry[] {
+  const entries: DependencyEntry[] = [];
+  const lines = source.split("\n");
+
+  for (let i = 0; i < lines.length; i++) {
+    const trimmed = lines[i]!.trim();
+    if (!trimmed || trimmed.startsWith("#") || trimmed.startsWith(";") || trimmed.startsWith("//")) continue;
+    if (DOC_KEY_RE.test(trimmed)) continue;
+    const location = { file: relPath, line: i + 1, context: trimmed.slice(0, 200) };
+
+    const urls = [...trimmed.matchAll(URL_RE)].map((m) => m[0].replace(/[.;]+$/, ""));
+    const vendorUrls = urls.flatMap((url) => {
+      const host = extractHost(url);
+      const provider = host ? matchVendor(host) : null;
+      return provider ? [{ url, provider }] : [];
+    });
+    for (const { url, provider } of vendorUrls) {
+      entries.push({
+        kind: "api",
+        url,
+        provider,
+        locations: [{ ...location, usage: "config" }],
+        usage_count: 1,
+        confidence: "medium",
+      });
+    }
+    if (vendorUrls.length > 0) continue;
+
+    const endpoint = credentialEndpoint(trimmed);
+    const provider = endpoint ? matchVendor(extractHost(endpoint)!) : null;
+    if (endpoint) {
+      entries.push({
+        kind: "api",
+        url: endpoint,
+        ...(provider ? { provider } : {}),
+        locations: [{ ...location, usage: "api_key" }],
+        usage_count: 1,
+        confidence: "medium",
+      });
+    }
+  }
+  return entries;
+}
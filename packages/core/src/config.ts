@@ -13,6 +13,8 @@ const ignore: () => Ignore =
     ? (_raw as () => Ignore)
     : ((_raw as { default: () => Ignore }).default);
 import { z } from "zod";
+import { DATA_CLASSIFICATION_PATTERN } from "./data-flow.js";
+import { isAllowedLLMEndpoint } from "./llm-classify.js";
 
 // ---------------------------------------------------------------------------
 // Schema — Zod validation for .thirdwatch.yml
@@ -24,6 +26,89 @@ const SdkOverrideSchema = z.object({
   patterns: z.array(z.string()).optional(),
 });
 
+const FirstPartySchema = z.object({
+  /** Hostnames or patterns owned by the organization, e.g. "*.internal" */
+  domains: z.array(z.string()),
+  /** "separate" flags entries as first_party; "hide" drops them (default: separate) */
+  mode: z.enum(["separate", "hide"]).optional(),
+});
+
+const LLMClassificationSchema = z.object({
+  /** OpenAI-compatible chat completions URL (https, or http on localhost) */
+  endpoint: z.string().url().refine(isAllowedLLMEndpoint, {
+    message: "endpoint must use https (plain http is only allowed for localhost)",
+  }),
+  model: z.string(),
+});
+
+const DetectorSchema = z.object({
+  name: z.string(),
+  /** Executable; "./"-relative paths resolve against the scan root */
+  command: z.string(),
+  args: z.array(z.string()).optional(),
+  /** File extensions routed to this detector, e.g. [".go", ".py"] */
+  extensions: z.array(z.string().regex(/^\.[A-Za-z0-9]+$/)),
+});
+
+const CustomRuleSchema = z.object({
+  /** Reported as the finding's rule_id */
+  id: z.string().regex(/^[A-Za-z0-9][A-Za-z0-9_.-]*$/),
+  /** Vendor name reported as the finding's provider */
+  vendor: z.string(),
+  category: z.string().optional(),
+  package: z.string().optional(),
+  confidence: z.enum(["high", "medium", "low"]).optional(),
+  match: z
+    .object({
+      import: z.string().optional(),
+      domain: z.string().optional(),
+      config_key: z.string().optional(),
+      code: z.string().optional(),
+    })
+    .refine((m) => Object.values(m).some(Boolean), {
+      message: "a rule needs at least one of import, domain, config_key, or code",
+    }),
+});
+
+const GatewaySchema = z
+  .object({
+    name: z.string(),
+    /** Origin clients use instead of the vendor's, e.g. https://llm-proxy.internal */
+    url: z.string().url(),
+    /** Providers that must be reached through the gateway, e.g. [openai, anthropic] */
+    vendors: z.array(z.string()).optional(),
+    /** Catalog categories that must be, e.g. [ai] */
+    categories: z.array(z.string()).optional(),
+    /** Severity of a bypass (default: error) */
+    severity: z.enum(["error", "warning", "info"]).optional(),
+  })
+  .refine((g) => (g.vendors?.length ?? 0) + (g.categories?.length ?? 0) > 0, {
+    message: "a gateway needs at least one vendor or category",
+  });
+
+const AnnotationSchema = z
+  .object({
+    reason: z.string().optional(),
+    /** Provider glob, e.g. "aws-*" */
+    provider: z.string().optional(),
+    /** Rule ID glob, e.g. "TW-*-SDK-PYTHON" */
+    rule: z.string().optional(),
+    /** Path glob matched against the finding's locations */
+    file: z.string().optional(),
+    /** Service name glob matched against the finding's locations */
+    service: z.string().optional(),
+    fingerprints: z.array(z.string()).optional(),
+    data_flow: z.enum(["send", "receive", "bidirectional"]).optional(),
+    classification: z.array(z.string().regex(DATA_CLASSIFICATION_PATTERN)).optional(),
+  })
+  .refine(
+    (a) => a.provider !== undefined || a.rule !== undefined || a.file !== undefined || a.service !== undefined || a.fingerprints !== undefined,
+    { message: "an annotation needs at least one of provider, rule, file, service, or fingerprints" },
+  )
+  .refine((a) => a.data_flow !== undefined || a.classification !== undefined, {
+    message: "an annotation needs data_flow or classification",
+  });
+
 const ConfigSchema = z.object({
   version: z.string().optional(),
   output: z.enum(["json", "yaml", "table"]).optional(),
@@ -35,6 +120,22 @@ const ConfigSchema = z.object({
   sdks: z.record(SdkOverrideSchema).optional(),
   min_confidence: z.enum(["high", "medium", "low"]).optional(),
   max_file_size_mb: z.number().positive().optional(),
+  /** Source lines to include either side of each location, secrets redacted (0–20) */
+  context_lines: z.coerce.number().int().min(0).max(20).optional(),
+  first_party: FirstPartySchema.optional(),
+  llm_classification: LLMClassificationSchema.optional(),
+  detectors: z.array(DetectorSchema).optional(),
+  rules: z.array(CustomRuleSchema).optional(),
+  /** Rule ID (or `*` pattern) → off | error | warning | info */
+  rule_settings: z.record(z.enum(["off", "error", "warning", "info"])).optional(),
+  /** Egress gateways vendors must be reached through */
+  gateways: z.array(GatewaySchema).optional(),
+  /** Data flow and classification labels for matching findings */
+  annotations: z.array(AnnotationSchema).optional(),
+  /** Directory prefix → logical service name, e.g. services/payments: payments-api */
+  services: z.record(z.string().min(1)).optional(),
+  /** Vendor slug → other names and hostname patterns that mean the same vendor */
+  vendor_aliases: z.record(z.array(z.string().min(1))).optional(),
 });
 
 export type ThirdwatchConfig = z.infer<typeof ConfigSchema>;
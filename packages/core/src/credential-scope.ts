@@ -0,0 +1,102 @@
+/**
+ * @module credential-scope
+ *
+ * What a hardcoded credential can do, read from its format. Several vendors
+ * encode the key type in the prefix:
+ *
+ *   sk_live_… / rk_live_…        Stripe secret key (full) / restricted key
+ *   AKIA… / ASIA…                AWS long-term IAM key / temporary STS key
+ *   ghp_… / github_pat_… / ghs_… GitHub classic token (full) / fine-grained
+ *                                token / App installation token
+ *   sk-proj-… / sk-admin-…       OpenAI project key / organization admin key
+ *
+ * A full-access (or admin) key on a narrow integration — read-only calls, or
+ * an SDK calling a handful of methods — is flagged as overly broad, with the
+ * narrower credential the vendor offers. Formats that don't encode a scope
+ * (AWS keys, whose power is in the IAM policy) get a key type but no
+ * privilege.
+ */
+
+import type { TDMCredentialPrivilege } from "@thirdwatch/tdm";
+import type { DependencyEntry } from "./plugin.js";
+
+export interface CredentialScope {
+  /** Key type within the vendor's scheme, e.g. "restricted_key" */
+  key_type: string;
+  privilege?: TDMCredentialPrivilege;
+  /** The narrower credential to use instead, for full and admin keys */
+  narrower?: string;
+}
+
+/** [secret kind, value prefix, scope]; the first matching prefix wins */
+const KEY_FORMATS: Array<[string, string, CredentialScope]> = [
+  ["stripe_secret_key", "rk_", { key_type: "restricted_key", privilege: "scoped" }],
+  ["stripe_secret_key", "sk_", { key_type: "secret_key", privilege: "full", narrower: "a restricted key (rk_) with only the permissions it needs" }],
+  ["aws_access_key_id", "ASIA", { key_type: "temporary_session_key" }],
+  ["aws_access_key_id", "AKIA", { key_type: "long_term_access_key" }],
+  ["github_token", "github_pat_", { key_type: "fine_grained_token", privilege: "scoped" }],
+  ["github_token", "ghs_", { key_type: "app_installation_token", privilege: "scoped" }],
+  ["github_token", "ghu_", { key_type: "app_user_token", privilege: "scoped" }],
+  ["github_token", "ghp_", { key_type: "classic_token", privilege: "full", narrower: "a fine-grained token (github_pat_) limited to the repositories it needs" }],
+  ["github_token", "gho_", { key_type: "oauth_token", privilege: "full", narrower: "a GitHub App installation token" }],
+  ["github_token", "ghr_", { key_type: "refresh_token" }],
+  ["gitlab_token", "glpat-", { key_type: "personal_access_token", privilege: "full", narrower: "a project access token or deploy token" }],
+  ["gitlab_token", "gldt-", { key_type: "deploy_token", privilege: "scoped" }],
+  ["gitlab_token", "glrt-", { key_type: "runner_token", privilege: "scoped" }],
+  ["gitlab_token", "glcbt-", { key_type: "ci_job_token", privilege: "scoped" }],
+  ["gitlab_token", "glptt-", { key_type: "pipeline_trigger_token", privilege: "scoped" }],
+  ["anthropic_api_key", "sk-ant-admin", { key_type: "admin_key", privilege: "admin", narrower: "a workspace API key" }],
+  ["anthropic_api_key", "sk-ant-api", { key_type: "api_key", privilege: "full" }],
+  ["openai_api_key", "sk-admin-", { key_type: "admin_key", privilege: "admin", narrower: "a project key (sk-proj-)" }],
+  ["openai_api_key", "sk-proj-", { key_type: "project_key", privilege: "scoped" }],
+  ["openai_api_key", "sk-svcacct-", { key_type: "service_account_key", privilege: "scoped" }],
+  ["openai_api_key", "sk-", { key_type: "user_key", privilege: "full", narrower: "a project key (sk-proj-) with restricted permissions" }],
+  ["mapbox_token", "pk.", { key_type: "public_token", privilege: "public" }],
+  ["mapbox_token", "sk.", { key_type: "secret_token", privilege: "scoped" }],
+  ["slack_token", "xoxb-", { key_type: "bot_token", privilege: "scoped" }],
+  ["slack_token", "xoxp-", { key_type: "user_token", privilege: "full", narrower: "a bot token (xoxb-) with only the scopes it needs" }],
+  ["slack_token", "xoxr-", { key_type: "refresh_token" }],
+  ["supabase_secret_key", "sb_secret_", { key_type: "secret_key", privilege: "full", narrower: "a publishable key with row level security" }],
+  ["fcm_server_key", "AAAA", { key_type: "legacy_server_key", privilege: "full", narrower: "a service account limited to FCM (HTTP v1 API)" }],
+  ["newrelic_api_key", "NRAK-", { key_type: "user_key", privilege: "full" }],
+];
+
+// Kinds whose whole format implies the scope
+const KIND_SCOPES: Record<string, CredentialScope> = {
+  newrelic_license_key: { key_type: "license_key", privilege: "scoped" },
+  error_tracking_dsn: { key_type: "dsn", privilege: "public" },
+};
+
+/** The key type and privilege a credential's format encodes, or null when it encodes none */
+export function inferCredentialScope(kind: string, value: string): CredentialScope | null {
+  for (const [k, prefix, scope] of KEY_FORMATS) {
+    if (k === kind && value.startsWith(prefix)) return scope;
+  }
+  return KIND_SCOPES[kind] ?? null;
+}
+
+// Most SDK methods a narrow integration calls
+const NARROW_METHODS = 3;
+
+/** What makes an integration narrow, or null when it isn't (or can't be told) */
+export function narrowUsage(entry: DependencyEntry): string | null {
+  if (entry.kind === "api") {
+    return entry.method === "GET" || entry.method === "HEAD" ? `read-only ${entry.method} calls` : null;
+  }
+  if (entry.kind !== "sdk") return null;
+  if (entry.intents && entry.intents.length > 0 && entry.intents.every((i) => i === "data_read")) return "reads";
+  const methods = entry.api_methods ?? [];
+  if (methods.length > 0 && methods.length <= NARROW_METHODS) {
+    return methods.length === 1 ? `one method (${methods[0]})` : `${methods.length} methods (${methods.join(", ")})`;
+  }
+  return null;
+}
+
+/** Why a full or admin credential is broader than the entry needs, or null */
+export function overlyBroadReason(scope: CredentialScope, entry: DependencyEntry): string | null {
+  if (scope.privilege !== "full" && scope.privilege !== "admin") return null;
+  const usage = narrowUsage(entry);
+  if (!usage) return null;
+  const key = `${scope.privilege === "admin" ? "An admin" : "A full-access"} ${scope.key_type.replace(/_/g, " ")}`;
+  return `${key} is used only for ${usage}${scope.narrower ? `; use ${scope.narrower}` : ""}`;
+}
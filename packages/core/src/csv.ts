@@ -0,0 +1,21 @@
+/**
+ * @module csv
+ *
+ * CSV for spreadsheets: the org report matrix and the server's audit-log
+ * export. Rows end in CRLF (RFC 4180). Fields holding a comma, quote, or
+ * line break are quoted, with quotes doubled. Many fields are user input —
+ * a team name, a suppression reason — so one starting with = + - @ or a
+ * tab is prefixed with ' to keep a spreadsheet from running it as a formula.
+ */
+
+/** One field, quoted and defused as needed */
+export function csvCell(value: string | number): string {
+  const s = String(value);
+  const safe = /^[=+\-@\t\r]/.test(s) ? `'${s}` : s;
+  return /[",\r\n]/.test(safe) ? `"${safe.replace(/"/g, '""')}"` : safe;
+}
+
+/** Rows (header first) as a CSV document */
+export function formatCsv(rows: Array<Array<string | number>>): string {
+  return rows.map((row) => row.map(csvCell).join(",")).join("\r\n") + "\r\n";
+}
@@ -0,0 +1,221 @@
+/**
+ * @module currency
+ *
+ * SDK version currency behind `thirdwatch outdated`: for each vendor SDK a
+ * scan found in the manifests, the pinned version against the latest release
+ * in the package registry, and how many major and minor versions behind it
+ * is. Payment and auth SDKs that fall far behind stop receiving fixes for
+ * API changes, so policies such as "payments SDKs at most 2 majors behind"
+ * can fail a build.
+ *
+ * Latest releases come from each ecosystem's public metadata:
+ *
+ *   go        proxy.golang.org/<module>/@latest, probing /v(N+1), /v(N+2), ...
+ *             because Go puts the major version in the module path
+ *   npm       registry.npmjs.org/<name>/latest
+ *   pypi      pypi.org/pypi/<name>/json
+ *   maven     search.maven.org (latestVersion)
+ *   cargo     crates.io/api/v1/crates/<name> (max_stable_version)
+ *   packagist repo.packagist.org/p2/<name>.json
+ *
+ * Lookups are best-effort: a registry that can't be reached leaves the
+ * package's latest version unknown rather than failing the report.
+ */
+
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "./registry.js";
+import { buildPackageProviderMap } from "./registry.js";
+
+const TIMEOUT_MS = 10_000;
+// Highest Go major versions probed past the pinned one
+const MAX_GO_MAJOR_PROBES = 30;
+
+export type FetchFn = (url: string, init?: { signal?: AbortSignal }) => Promise<Response>;
+
+export interface SdkCurrency {
+  package: string;
+  ecosystem: string;
+  provider: string;
+  category?: string;
+  current_version: string;
+  /** null when the registry could not be reached or the version isn't semver-like */
+  latest_version: string | null;
+  majors_behind: number | null;
+  minors_behind: number | null;
+  manifest_file: string;
+}
+
+/** A threshold: SDKs matching `match` (a provider, a category, or "*") may be at most `max_majors_behind` behind */
+export interface CurrencyPolicy {
+  match: string;
+  max_majors_behind: number;
+}
+
+export interface CurrencyViolation {
+  sdk: SdkCurrency;
+  policy: CurrencyPolicy;
+}
+
+export interface CurrencyReport {
+  sdks: SdkCurrency[];
+  violations: CurrencyViolation[];
+}
+
+/** [major, minor, patch] of a version such as "v78.3.0", "7.0.0", "^2.1" */
+export function parseVersion(version: string): [number, number, number] | null {
+  const m = version.trim().match(/^[\^~>=<v\s]*(\d+)(?:\.(\d+))?(?:\.(\d+))?/);
+  return m ? [Number(m[1]), Number(m[2] ?? 0), Number(m[3] ?? 0)] : null;
+}
+
+/** Majors behind, and minors behind within the same major (0 once a major behind) */
+export function versionsBehind(current: string, latest: string): { majors: number; minors: number } | null {
+  const c = parseVersion(current);
+  const l = parseVersion(latest);
+  if (!c || !l) return null;
+  const majors = Math.max(0, l[0] - c[0]);
+  return { majors, minors: majors > 0 ? 0 : Math.max(0, l[1] - c[1]) };
+}
+
+async function getJson(fetchFn: FetchFn, url: string): Promise<unknown> {
+  const res = await fetchFn(url, { signal: AbortSignal.timeout(TIMEOUT_MS) });
+  if (!res.ok) return null;
+  return res.json();
+}
+
+/** github.com/stripe/stripe-go/v78 → github.com/stripe/stripe-go */
+function goModuleBase(module: string): string {
+  return module.replace(/\/v\d+$/, "");
+}
+
+// proxy.golang.org escapes capitals as "!" + lowercase
+const goEscape = (module: string) => module.replace(/[A-Z]/g, (c) => `!${c.toLowerCase()}`);
+
+async function latestGo(fetchFn: FetchFn, module: string, current: string): Promise<string | null> {
+  const base = goModuleBase(module);
+  const version = (path: string) =>
+    getJson(fetchFn, `https://proxy.golang.org/${goEscape(path)}/@latest`).then(
+      (data) => (data as { Version?: string } | null)?.Version ?? null,
+    );
+  let latest = await version(module);
+  const major = parseVersion(latest ?? current)?.[0] ?? 1;
+  for (let next = Math.max(major + 1, 2), probes = 0; probes < MAX_GO_MAJOR_PROBES; next++, probes++) {
+    const found = await version(`${base}/v${next}`).catch(() => null);
+    if (!found) break;
+    latest = found;
+  }
+  return latest;
+}
+
+/** Latest published version of a package, or null when unknown */
+export async function fetchLatestVersion(
+  ecosystem: string,
+  name: string,
+  current: string,
+  fetchFn: FetchFn = fetch,
+): Promise<string | null> {
+  try {
+    switch (ecosystem) {
+      case "go":
+        return await latestGo(fetchFn, name, current);
+      case "npm": {
+        const data = (await getJson(fetchFn, `https://registry.npmjs.org/${name.replace("/", "%2F")}/latest`)) as { version?: string } | null;
+        return data?.version ?? null;
+      }
+      case "pypi": {
+        const data = (await getJson(fetchFn, `https://pypi.org/pypi/${encodeURIComponent(name)}/json`)) as { info?: { version?: string } } | null;
+        return data?.info?.version ?? null;
+      }
+      case "maven": {
+        const [group, artifact] = name.split(":");
+        if (!group || !artifact) return null;
+        const q = encodeURIComponent(`g:"${group}" AND a:"${artifact}"`);
+        const data = (await getJson(fetchFn, `https://search.maven.org/solrsearch/select?q=${q}&rows=1&wt=json`)) as {
+          response?: { docs?: Array<{ latestVersion?: string }> };
+        } | null;
+        return data?.response?.docs?.[0]?.latestVersion ?? null;
+      }
+      case "cargo": {
+        const data = (await getJson(fetchFn, `https://crates.io/api/v1/crates/${encodeURIComponent(name)}`)) as {
+          crate?: { max_stable_version?: string };
+        } | null;
+        return data?.crate?.max_stable_version ?? null;
+      }
+      case "packagist": {
+        const data = (await getJson(fetchFn, `https://repo.packagist.org/p2/${name}.json`)) as {
+          packages?: Record<string, Array<{ version?: string }>>;
+        } | null;
+        const stable = data?.packages?.[name]?.find((v) => v.version && !/dev|alpha|beta|rc/i.test(v.version));
+        return stable?.version ?? null;
+      }
+      default:
+        return null;
+    }
+  } catch {
+    return null;
+  }
+}
+
+/** The policy that applies to an SDK: its provider, then its category, then "*" */
+function policyFor(sdk: SdkCurrency, policies: CurrencyPolicy[]): CurrencyPolicy | undefined {
+  return (
+    policies.find((p) => p.match === sdk.provider) ??
+    (sdk.category ? policies.find((p) => p.match === sdk.category) : undefined) ??
+    policies.find((p) => p.match === "*")
+  );
+}
+
+/**
+ * Compare every vendor SDK in a TDM's packages against its latest release.
+ * Packages that no catalog entry names are skipped; they aren't vendor SDKs.
+ */
+export async function buildCurrencyReport(
+  tdm: TDM,
+  registry: SDKRegistryEntry[],
+  options: { policies?: CurrencyPolicy[]; fetchFn?: FetchFn } = {},
+): Promise<CurrencyReport> {
+  const categories = new Map(registry.map((e) => [e.provider, e.category]));
+  const providerMaps = new Map<string, Map<string, string>>();
+  const providerOf = (ecosystem: string, name: string) => {
+    if (!providerMaps.has(ecosystem)) providerMaps.set(ecosystem, buildPackageProviderMap(registry, ecosystem));
+    const map = providerMaps.get(ecosystem)!;
+    return map.get(name) ?? (ecosystem === "go" ? map.get(goModuleBase(name)) : undefined);
+  };
+
+  const seen = new Set<string>();
+  const pending: Array<Promise<SdkCurrency>> = [];
+  for (const pkg of tdm.packages) {
+    const provider = providerOf(pkg.ecosystem, pkg.name);
+    const key = `${pkg.ecosystem}:${pkg.name}:${pkg.current_version}`;
+    if (!provider || seen.has(key)) continue;
+    seen.add(key);
+    pending.push(
+      fetchLatestVersion(pkg.ecosystem, pkg.name, pkg.current_version, options.fetchFn).then((latest) => {
+        const behind = latest ? versionsBehind(pkg.current_version, latest) : null;
+        const category = categories.get(provider);
+        return {
+          package: pkg.name,
+          ecosystem: pkg.ecosystem,
+          provider,
+          ...(category ? { category } : {}),
+          current_version: pkg.current_version,
+          latest_version: latest,
+          majors_behind: behind?.majors ?? null,
+          minors_behind: behind?.minors ?? null,
+          manifest_file: pkg.manifest_file,
+        };
+      }),
+    );
+  }
+
+  const sdks = (await Promise.all(pending)).sort(
+    (a, b) => (b.majors_behind ?? -1) - (a.majors_behind ?? -1) || a.package.localeCompare(b.package),
+  );
+  const violations: CurrencyViolation[] = [];
+  for (const sdk of sdks) {
+    const policy = policyFor(sdk, options.policies ?? []);
+    if (policy && sdk.majors_behind !== null && sdk.majors_behind > policy.max_majors_behind) {
+      violations.push({ sdk, policy });
+    }
+  }
+  return { sdks, violations };
+}
@@ -0,0 +1,153 @@
+import type { Confidence } from "@thirdwatch/tdm";
+import type { DependencyEntry } from "./plugin.js";
+
+// ---------------------------------------------------------------------------
+// Declarative detector rules from the `rules` section of .thirdwatch.yml.
+//
+//   rules:
+//     - id: acme-billing
+//       vendor: acme-billing
+//       category: payments
+//       match:
+//         import: github.com/acme/billing-go   # import path (prefix)
+//         domain: '^api\.acme\.io$'            # regex on URL hosts
+//         config_key: '^ACME_(API|SECRET)_KEY$' # regex on env/config keys
+//         code: 'acme\.NewClient\('            # regex on source lines
+//
+// Any matcher hitting a line produces a finding: `domain` yields an API
+// entry, every other matcher yields an SDK entry for the vendor. Findings
+// carry the rule's `id` as their rule_id.
+// ---------------------------------------------------------------------------
+
+export interface CustomRule {
+  id: string;
+  vendor: string;
+  category?: string;
+  /** Package name reported on SDK findings (default: the import path or rule id) */
+  package?: string;
+  confidence?: Confidence;
+  match: {
+    import?: string;
+    domain?: string;
+    config_key?: string;
+    code?: string;
+  };
+}
+
+/** Non-source files that rules also scan, so config_key can hit YAML / TOML */
+export const RULE_CONFIG_EXTENSIONS = new Set([
+  ".yml",
+  ".yaml",
+  ".toml",
+  ".ini",
+  ".properties",
+]);
+
+interface CompiledRule {
+  rule: CustomRule;
+  importRe?: RegExp;
+  domainRe?: RegExp;
+  configKeyRe?: RegExp;
+  codeRe?: RegExp;
+}
+
+function escapeRegExp(s: string): string {
+  return s.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+}
+
+/**
+ * Compile rule patterns once per scan. Throws on an invalid regex so a broken
+ * rule fails loudly instead of silently matching nothing.
+ */
+export function compileRules(rules: CustomRule[]): CompiledRule[] {
+  return rules.map((rule) => {
+    const compile = (pattern: string, field: string): RegExp => {
+      try {
+        return new RegExp(pattern);
+      } catch (err) {
+        throw new Error(
+          `Rule ${rule.id}: invalid ${field} pattern: ${err instanceof Error ? err.message : String(err)}`,
+        );
+      }
+    };
+
+    const compiled: CompiledRule = { rule };
+    const { match } = rule;
+    if (match.import) {
+      const path = escapeRegExp(match.import);
+      // Quoted import/require paths, or Python-style `import x` / `from x import`
+      compiled.importRe = new RegExp(
+        `["'\`]${path}(?:[/.][^"'\`]*)?["'\`]|\\b(?:import|from)\\s+${path}\\b`,
+      );
+    }
+    if (match.domain) compiled.domainRe = compile(match.domain, "domain");
+    if (match.config_key) compiled.configKeyRe = compile(match.config_key, "config_key");
+    if (match.code) compiled.codeRe = compile(match.code, "code");
+    return compiled;
+  });
+}
+
+const URL_RE = /\b(?:https?|wss?):\/\/([a-z0-9.-]+)(?::\d+)?[^\s"'`)]*/gi;
+const KEY_RE = /[A-Za-z_][A-Za-z0-9_.-]*/g;
+
+/**
+ * Apply compiled rules to one file. Returns at most one entry per rule per
+ * line, with the raw line as location context.
+ */
+export function applyCustomRules(
+  compiled: CompiledRule[],
+  source: string,
+  relPath: string,
+): DependencyEntry[] {
+  if (compiled.length === 0) return [];
+  const entries: DependencyEntry[] = [];
+  const lines = source.split("\n");
+
+  for (let i = 0; i < lines.length; i++) {
+    const line = lines[i]!;
+    const trimmed = line.trim();
+    if (!trimmed) continue;
+    const location = { file: relPath, line: i + 1, context: trimmed.slice(0, 200) };
+
+    for (const { rule, importRe, domainRe, configKeyRe, codeRe } of compiled) {
+      const confidence = rule.confidence ?? "high";
+      const category = rule.category ? { category: rule.category } : {};
+
+      if (domainRe) {
+        const url = [...line.matchAll(URL_RE)].find((m) => domainRe.test(m[1]!.toLowerCase()));
+        if (url) {
+          entries.push({
+            kind: "api",
+            url: url[0],
+            provider: rule.vendor,
+            rule_id: rule.id,
+            ...category,
+            locations: [location],
+            usage_count: 1,
+            confidence,
+          });
+          continue;
+        }
+      }
+
+      const hit =
+        (importRe?.test(line) ?? false) ||
+        (configKeyRe ? (line.match(KEY_RE) ?? []).some((k) => configKeyRe.test(k)) : false) ||
+        (codeRe?.test(line) ?? false);
+      if (hit) {
+        entries.push({
+          kind: "sdk",
+          provider: rule.vendor,
+          sdk_package: rule.package ?? rule.match.import ?? rule.id,
+          rule_id: rule.id,
+          ...category,
+          locations: [location],
+          usage_count: 1,
+          confidence,
+        });
+      }
+    }
+  }
+
+  return entries;
+}
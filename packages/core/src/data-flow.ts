@@ -0,0 +1,177 @@
+/**
+ * @module data-flow
+ *
+ * Direction and classification of the data behind each finding, so reviews
+ * can ask "what PII leaves this service, and to whom" of the TDM alone.
+ *
+ * `data_flow` is inferred from what the code does:
+ *
+ *   GET/HEAD calls, data_read intents                 → receive
+ *   POST/PUT/PATCH/DELETE calls, writes, payments,
+ *     messages, telemetry, exported data              → send
+ *   inference and embeddings, or a mix of the above   → bidirectional
+ *   webhooks (registered or exposed)                  → receive
+ *
+ * `data_classification` starts from the catalog: payment providers handle
+ * cardholder data (pci), and SDKs exporting identifiers, addresses, locations,
+ * or identity documents handle personal data (pii). Neither is knowable for
+ * sure from code, so both are defaults to correct through `annotations` in
+ * .thirdwatch.yml, matched like analyzer suppressions:
+ *
+ *   annotations:
+ *     - provider: stripe
+ *       classification: [pci, pii]
+ *     - rule: TW-HTTP-URL
+ *       file: "services/status/**"
+ *       classification: [public]
+ *       data_flow: receive
+ *     - service: "payments-*"
+ *       classification: [pci]
+ *     - fingerprints: [3f9a0c2e1b7d4a58]
+ *       classification: [internal]
+ *
+ * Every matcher given must match; an annotation's labels replace the
+ * inferred ones, and later annotations win over earlier ones.
+ */
+
+import type { DataFlow } from "@thirdwatch/tdm";
+import type { DependencyEntry } from "./plugin.js";
+
+/** Labels the scanner infers or documents; organizations may add their own */
+export const DATA_CLASSIFICATIONS = ["public", "internal", "pii", "pci"] as const;
+
+export const DATA_CLASSIFICATION_PATTERN = /^[a-z][a-z0-9_-]*$/;
+
+export interface DataAnnotation {
+  reason?: string;
+  /** Provider glob, e.g. "aws-*" */
+  provider?: string;
+  /** Rule ID glob, e.g. "TW-*-SDK-PYTHON" */
+  rule?: string;
+  /** Path glob; matches when any location is in such a file */
+  file?: string;
+  /** Service name glob; matches when any location belongs to such a service */
+  service?: string;
+  /** Matches when any location has one of these fingerprints */
+  fingerprints?: string[];
+  data_flow?: DataFlow;
+  classification?: string[];
+}
+
+const RECEIVE_INTENTS = new Set(["data_read"]);
+const SEND_INTENTS = new Set(["data_write", "data_delete", "payment", "refund", "message_send", "telemetry_export"]);
+const EXCHANGE_INTENTS = new Set(["inference", "embedding"]);
+
+const RECEIVE_METHODS = new Set(["GET", "HEAD", "OPTIONS"]);
+
+const PCI_CATEGORIES = new Set(["payments"]);
+
+// data_exported values that are personal data
+const PERSONAL_DATA = new Set([
+  "user_identifiers",
+  "user_context",
+  "user_traits",
+  "user_data",
+  "customer_data",
+  "customer_addresses",
+  "location_data",
+  "ip_addresses",
+  "identity_documents",
+  "biometrics",
+  "email_addresses",
+]);
+
+function combine(flows: Iterable<DataFlow>): DataFlow | undefined {
+  const seen = new Set(flows);
+  if (seen.size === 0) return undefined;
+  if (seen.size > 1 || seen.has("bidirectional")) return "bidirectional";
+  return [...seen][0];
+}
+
+function inferFlow(entry: DependencyEntry): DataFlow | undefined {
+  switch (entry.kind) {
+    case "api":
+      if (!entry.method) return undefined;
+      return RECEIVE_METHODS.has(entry.method) ? "receive" : "send";
+    case "sdk": {
+      const flows: DataFlow[] = [];
+      for (const intent of entry.intents ?? []) {
+        if (RECEIVE_INTENTS.has(intent)) flows.push("receive");
+        else if (SEND_INTENTS.has(intent)) flows.push("send");
+        else if (EXCHANGE_INTENTS.has(intent)) flows.push("bidirectional");
+      }
+      if ((entry.data_exported ?? []).length > 0) flows.push("send");
+      return combine(flows);
+    }
+    case "webhook":
+      return "receive";
+    default:
+      return undefined;
+  }
+}
+
+function inferClassification(entry: DependencyEntry): string[] {
+  const labels = new Set<string>();
+  if (entry.kind === "package" || entry.kind === "infrastructure" || entry.kind === "webhook") return [];
+  if (entry.category && PCI_CATEGORIES.has(entry.category)) labels.add("pci");
+  if (entry.kind === "sdk" && (entry.data_exported ?? []).some((d) => PERSONAL_DATA.has(d))) labels.add("pii");
+  return DATA_CLASSIFICATIONS.filter((l) => labels.has(l));
+}
+
+function glob(pattern: string): RegExp {
+  // "**" spans directories, "*" stays within one path segment or ID part
+  const body = pattern
+    .split("**")
+    .map((part) => part.split("*").map((s) => s.replace(/[.+?^${}()|[\]\\]/g, "\\$&")).join("[^/]*"))
+    .join(".*");
+  return new RegExp(`^${body}$`, "i");
+}
+
+function providerOf(entry: DependencyEntry): string | undefined {
+  return entry.kind === "package" ? undefined : (entry.provider ?? undefined);
+}
+
+function matches(entry: DependencyEntry, annotation: DataAnnotation): boolean {
+  if (annotation.provider !== undefined) {
+    const provider = providerOf(entry);
+    if (!provider || !glob(annotation.provider).test(provider)) return false;
+  }
+  if (annotation.rule !== undefined) {
+    if (!entry.rule_id || !glob(annotation.rule).test(entry.rule_id)) return false;
+  }
+  if (annotation.file !== undefined) {
+    const re = glob(annotation.file);
+    if (!entry.locations.some((loc) => re.test(loc.file))) return false;
+  }
+  if (annotation.service !== undefined) {
+    const re = glob(annotation.service);
+    if (!entry.locations.some((loc) => loc.service && re.test(loc.service))) return false;
+  }
+  if (annotation.fingerprints !== undefined) {
+    const listed = new Set(annotation.fingerprints);
+    if (!entry.locations.some((loc) => loc.fingerprint && listed.has(loc.fingerprint))) return false;
+  }
+  return true;
+}
+
+/**
+ * Set `data_flow` and `data_classification` on every non-package entry:
+ * inferred first, then overridden by matching annotations. Run after catalog
+ * categories are applied, since classification depends on them.
+ */
+export function labelDataFlows(entries: DependencyEntry[], annotations: DataAnnotation[] = []): void {
+  for (const entry of entries) {
+    if (entry.kind === "package") continue;
+    let flow = entry.data_flow ?? inferFlow(entry);
+    let labels = entry.data_classification ?? inferClassification(entry);
+    for (const annotation of annotations) {
+      if (!matches(entry, annotation)) continue;
+      if (annotation.data_flow) flow = annotation.data_flow;
+      if (annotation.classification) labels = [...new Set(annotation.classification)];
+    }
+    if (flow) entry.data_flow = flow;
+    else delete entry.data_flow;
+    if (labels.length > 0) entry.data_classification = labels;
+    else delete entry.data_classification;
+  }
+}
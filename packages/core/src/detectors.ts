@@ -0,0 +1,215 @@
+/**
+ * @module detectors
+ *
+ * Custom detector plugins over a simple exec protocol, so teams can ship
+ * proprietary detectors (internal SaaS contracts, partner APIs) without
+ * forking thirdwatch. Detectors are discovered from the `detectors` section
+ * of .thirdwatch.yml:
+ *
+ *   detectors:
+ *     - name: acme-contracts
+ *       command: ./tools/acme-detector
+ *       args: ["--thirdwatch"]
+ *       extensions: [".go", ".py"]
+ *
+ * Protocol (v1): one long-lived process per detector, newline-delimited JSON
+ * over stdin/stdout.
+ *
+ *   → {"type":"handshake","protocol":1}
+ *   ← {"protocol":1}
+ *   → {"type":"analyze","id":1,"file":"src/pay.go","source":"..."}
+ *   ← {"id":1,"entries":[ ...DependencyEntry... ]}   or   {"id":1,"error":"..."}
+ *
+ * stdin is closed when the scan finishes; the detector should then exit.
+ * Anything written to stderr is ignored.
+ */
+
+import { spawn } from "node:child_process";
+import type { ChildProcessWithoutNullStreams } from "node:child_process";
+import { createInterface } from "node:readline";
+import { isAbsolute, relative, resolve } from "node:path";
+import type { DependencyEntry, LanguageAnalyzerPlugin, AnalyzerContext } from "./plugin.js";
+import { toPosixPath } from "./paths.js";
+
+export const DETECTOR_PROTOCOL_VERSION = 1;
+const REQUEST_TIMEOUT_MS = 30_000;
+
+const ENTRY_KINDS = new Set(["package", "api", "sdk", "infrastructure", "webhook"]);
+const SEVERITIES = new Set(["error", "warning", "info"]);
+
+export interface DetectorConfig {
+  name: string;
+  /** Executable; paths starting with "./" resolve against the scan root */
+  command: string;
+  args?: string[];
+  /** File extensions to send to the detector, e.g. [".go"] */
+  extensions: string[];
+}
+
+/** A custom detector is a language plugin that must be shut down after use */
+export interface ExecDetector extends LanguageAnalyzerPlugin {
+  close(): Promise<void>;
+}
+
+interface DetectorResponse {
+  id?: unknown;
+  protocol?: unknown;
+  entries?: unknown;
+  error?: unknown;
+}
+
+/**
+ * Keep only well-formed entries, and clamp their locations to the analyzed
+ * file so a detector cannot report findings in files it was never shown.
+ */
+export function sanitizeDetectorEntries(
+  raw: unknown,
+  relPath: string,
+): DependencyEntry[] {
+  if (!Array.isArray(raw)) return [];
+  const entries: DependencyEntry[] = [];
+  for (const item of raw) {
+    if (item == null || typeof item !== "object") continue;
+    const entry = item as Record<string, unknown>;
+    if (typeof entry.kind !== "string" || !ENTRY_KINDS.has(entry.kind)) continue;
+    if (!Array.isArray(entry.locations)) continue;
+    const locations = entry.locations.filter(
+      (l): l is { file: string; line: number } =>
+        l != null &&
+        typeof l === "object" &&
+        (l as { file?: unknown }).file === relPath &&
+        Number.isInteger((l as { line?: unknown }).line),
+    );
+    if (locations.length === 0) continue;
+    if (typeof entry.confidence !== "string") entry.confidence = "medium";
+    if (!SEVERITIES.has(entry.severity as string)) delete entry.severity;
+    entries.push({ ...entry, locations } as unknown as DependencyEntry);
+  }
+  return entries;
+}
+
+function resolveCommand(command: string, scanRoot: string): string {
+  if (/^\.\.?[/\\]/.test(command)) {
+    return resolve(scanRoot, command);
+  }
+  return command;
+}
+
+// Windows runs batch files only through cmd.exe
+const needsShell = (command: string) => process.platform === "win32" && /\.(?:cmd|bat)$/i.test(command);
+
+/**
+ * Spawn a detector and complete the handshake. Rejects if the process cannot
+ * start or speaks a different protocol version.
+ */
+export async function startExecDetector(
+  config: DetectorConfig,
+  scanRoot: string,
+): Promise<ExecDetector> {
+  const command = resolveCommand(config.command, scanRoot);
+  const shell = needsShell(command);
+  const child: ChildProcessWithoutNullStreams = spawn(
+    shell ? `"${command}"` : command,
+    shell ? (config.args ?? []).map((a) => `"${a.replace(/"/g, '""')}"`) : (config.args ?? []),
+    { cwd: scanRoot, stdio: ["pipe", "pipe", "pipe"], shell },
+  );
+  child.stderr.resume();
+  // EPIPE when the detector dies mid-write is reported through "exit" instead
+  child.stdin.on("error", () => {});
+
+  const pending = new Map<number, (res: DetectorResponse) => void>();
+  let handshake: ((res: DetectorResponse) => void) | null = null;
+  let exited = false;
+  let nextId = 1;
+
+  const failAll = (message: string) => {
+    exited = true;
+    for (const resolveFn of pending.values()) resolveFn({ error: message });
+    pending.clear();
+    handshake?.({ error: message });
+  };
+
+  child.on("error", (err) => failAll(`detector ${config.name} failed: ${err.message}`));
+  child.on("exit", (code) => failAll(`detector ${config.name} exited with code ${code}`));
+
+  createInterface({ input: child.stdout }).on("line", (line) => {
+    let res: DetectorResponse;
+    try {
+      res = JSON.parse(line) as DetectorResponse;
+    } catch {
+      return;
+    }
+    if (handshake) {
+      const h = handshake;
+      handshake = null;
+      h(res);
+      return;
+    }
+    if (typeof res.id !== "number") return;
+    const resolveFn = pending.get(res.id);
+    if (!resolveFn) return;
+    pending.delete(res.id);
+    resolveFn(res);
+  });
+
+  const send = (message: object) => {
+    if (!exited) child.stdin.write(JSON.stringify(message) + "\n");
+  };
+
+  const hello = await new Promise<DetectorResponse>((resolveFn) => {
+    handshake = resolveFn;
+    send({ type: "handshake", protocol: DETECTOR_PROTOCOL_VERSION });
+    setTimeout(() => {
+      if (handshake === resolveFn) {
+        handshake = null;
+        resolveFn({ error: "handshake timed out" });
+      }
+    }, REQUEST_TIMEOUT_MS).unref();
+  });
+  if (hello.protocol !== DETECTOR_PROTOCOL_VERSION) {
+    child.kill();
+    throw new Error(
+      `Detector ${config.name}: ${typeof hello.error === "string" ? hello.error : `unsupported protocol ${String(hello.protocol)}`}`,
+    );
+  }
+
+  return {
+    name: config.name,
+    language: config.name,
+    extensions: config.extensions,
+
+    async analyze(ctx: AnalyzerContext): Promise<DependencyEntry[]> {
+      const relPath = toPosixPath(isAbsolute(ctx.filePath) ? relative(ctx.scanRoot, ctx.filePath) : ctx.filePath);
+      const id = nextId++;
+
+      const res = await new Promise<DetectorResponse>((resolveFn) => {
+        if (exited) {
+          resolveFn({ error: `detector ${config.name} is not running` });
+          return;
+        }
+        pending.set(id, resolveFn);
+        send({ type: "analyze", id, file: relPath, source: ctx.source });
+        setTimeout(() => {
+          if (pending.delete(id)) resolveFn({ error: "request timed out" });
+        }, REQUEST_TIMEOUT_MS).unref();
+      });
+
+      if (typeof res.error === "string") {
+        throw new Error(`Detector ${config.name}: ${res.error}`);
+      }
+      return sanitizeDetectorEntries(res.entries, relPath);
+    },
+
+    close(): Promise<void> {
+      if (exited) return Promise.resolve();
+      return new Promise((resolveFn) => {
+        child.once("exit", () => resolveFn());
+        child.stdin.end();
+        setTimeout(() => {
+          child.kill();
+          resolveFn();
+        }, 5_000).unref();
+      });
+    },
+  };
+}
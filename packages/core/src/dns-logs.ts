@@ -0,0 +1,122 @@
+/**
+ * @module dns-logs
+ *
+ * DNS query logs as a runtime source (`thirdwatch ingest dns`), so platform
+ * teams can build fleet-wide vendor inventories from resolver logs without
+ * deploying anything into workloads. Supported formats:
+ *
+ *   coredns  — the CoreDNS `log` plugin's default format
+ *     [INFO] 10.244.0.5:50553 - 27759 "A IN api.stripe.com. udp 32 false 512" NOERROR qr,rd,ra 106 0.0001s
+ *
+ *   route53  — Route 53 Resolver query logs (one JSON object per line)
+ *     {"query_timestamp":"2026-10-14T10:00:00Z","query_name":"api.stripe.com.","query_type":"A","rcode":"NOERROR","srcaddr":"10.0.1.5",...}
+ *
+ *   dnstap   — text output of `dnstap -r <file>` (golang-dnstap)
+ *     10:00:00.123456 CQ 10.0.0.5 UDP 43b "api.stripe.com." IN A
+ */
+
+import type { RuntimeObservation } from "./runtime.js";
+
+export type DnsLogFormat = "coredns" | "route53" | "dnstap";
+
+export const DNS_LOG_FORMATS: DnsLogFormat[] = ["coredns", "route53", "dnstap"];
+
+// Record types that indicate a client is about to connect somewhere
+const CONNECT_QTYPES = new Set(["A", "AAAA", "CNAME", "HTTPS", "SVCB"]);
+
+// Cluster-internal and reverse-lookup names are never third-party vendors
+const INTERNAL_SUFFIXES = [
+  ".cluster.local",
+  ".svc",
+  ".internal",
+  ".local",
+  ".localdomain",
+  ".in-addr.arpa",
+  ".ip6.arpa",
+  ".compute.internal",
+];
+
+/** Normalize a queried name; returns null for names that can't be a vendor */
+export function normalizeQueryName(name: string): string | null {
+  const n = name.trim().toLowerCase().replace(/^"|"$/g, "").replace(/\.$/, "");
+  if (!n.includes(".") || !/^[a-z0-9._-]+$/.test(n)) return null;
+  if (INTERNAL_SUFFIXES.some((s) => n.endsWith(s))) return null;
+  return n;
+}
+
+function observation(
+  name: string,
+  qtype: string,
+  timestamp: string,
+  client: string | undefined,
+): RuntimeObservation | null {
+  if (!CONNECT_QTYPES.has(qtype.toUpperCase())) return null;
+  const host = normalizeQueryName(name);
+  if (!host) return null;
+  return { source: "dns", host, timestamp, ...(client ? { client } : {}) };
+}
+
+const COREDNS_RE = /^(?:(\S+)\s+)?\[\w+\]\s+(\S+?):\d+\s+-\s+\d+\s+"(\S+)\s+IN\s+(\S+)\s+(?:udp|tcp)\b[^"]*"\s+(\S+)/;
+const DNSTAP_RE = /^(\S+)\s+CQ\s+(\S+)\s+\S+\s+\d+b\s+"?([^"\s]+)"?\s+IN\s+(\S+)/;
+
+function validTimestamp(value: string | undefined, fallback: string): string {
+  return value && !Number.isNaN(Date.parse(value)) ? new Date(value).toISOString() : fallback;
+}
+
+/**
+ * Parse one log line. CoreDNS lines carry no timestamp of their own unless a
+ * log shipper prefixed one (e.g. `kubectl logs --timestamps`); `now` is used
+ * otherwise.
+ */
+export function parseDnsLogLine(
+  line: string,
+  format: DnsLogFormat,
+  now: () => string = () => new Date().toISOString(),
+): RuntimeObservation | null {
+  switch (format) {
+    case "coredns": {
+      const m = line.match(COREDNS_RE);
+      if (!m) return null;
+      const [, ts, client, qtype, name, rcode] = m;
+      if (rcode !== "NOERROR") return null;
+      return observation(name!, qtype!, validTimestamp(ts, now()), client);
+    }
+    case "route53": {
+      let rec: Record<string, unknown>;
+      try {
+        rec = JSON.parse(line) as Record<string, unknown>;
+      } catch {
+        return null;
+      }
+      if (typeof rec.query_name !== "string" || typeof rec.query_type !== "string") return null;
+      if (rec.rcode != null && rec.rcode !== "NOERROR") return null;
+      const srcids = rec.srcids as Record<string, unknown> | undefined;
+      const client = typeof srcids?.instance === "string"
+        ? srcids.instance
+        : typeof rec.srcaddr === "string" ? rec.srcaddr : undefined;
+      return observation(
+        rec.query_name,
+        rec.query_type,
+        validTimestamp(typeof rec.query_timestamp === "string" ? rec.query_timestamp : undefined, now()),
+        client,
+      );
+    }
+    case "dnstap": {
+      const m = line.match(DNSTAP_RE);
+      if (!m) return null;
+      const [, time, client, name, qtype] = m;
+      // dnstap's text output has a time of day only; anchor it to today (UTC)
+      const ts = /^\d\d:\d\d:\d\d/.test(time!) ? `${now().slice(0, 10)}T${time}Z` : time;
+      return observation(name!, qtype!, validTimestamp(ts, now()), client!.replace(/:\d+$/, ""));
+    }
+  }
+}
+
+/** Guess the format from a sample line */
+export function detectDnsLogFormat(line: string): DnsLogFormat | null {
+  const trimmed = line.trim();
+  if (trimmed.startsWith("{") && trimmed.includes("query_name")) return "route53";
+  if (COREDNS_RE.test(trimmed)) return "coredns";
+  if (DNSTAP_RE.test(trimmed)) return "dnstap";
+  return null;
+}
@@ -0,0 +1,207 @@
+/**
+ * @module drift
+ *
+ * Static-vs-runtime drift, behind `thirdwatch drift`. Compares the vendors a
+ * static scan found in code with the vendors runtime sources (agent, proxy,
+ * flow logs, ...) actually saw on the wire:
+ *
+ *   runtime_only — called at runtime but absent from code: transitive or
+ *                  hidden dependencies the analyzers cannot see
+ *   static_only  — integrated in code but never observed: candidates for
+ *                  dead integrations (or paths the observation window missed)
+ *
+ * Unclassified runtime hosts are compared by hostname instead of vendor.
+ * SDK versions identified from runtime User-Agents are lined up with the
+ * versions the static scan's manifests declare, so a deployment running an
+ * older SDK than the code pins stands out.
+ */
+
+import type { TDM } from "@thirdwatch/tdm";
+import { extractHost } from "./first-party.js";
+
+export interface DriftVendor {
+  vendor: string;
+  /** Code locations referencing the vendor (static side) */
+  static_usages: number;
+  /** Connections / requests observed (runtime side) */
+  runtime_count: number;
+  /** Hosts the vendor was reached on at runtime */
+  hosts: string[];
+  /** Files that reference the vendor, for static_only triage */
+  files: string[];
+  /** SDKs identified from runtime User-Agent headers */
+  sdks?: DriftSdk[];
+}
+
+export interface DriftSdk {
+  sdk_package: string;
+  ecosystem?: string;
+  /** Versions the User-Agents reported */
+  runtime_versions: string[];
+  /** Version the static scan's manifests declare */
+  static_version?: string;
+  /** Whether the declared version is among the runtime versions; absent when either is unknown */
+  version_matches?: boolean;
+}
+
+export interface DriftUnknownHost {
+  host: string;
+  runtime_count: number;
+}
+
+export interface DriftReport {
+  /** Vendors seen at runtime that no code references */
+  runtime_only: DriftVendor[];
+  /** Vendors referenced in code that were never observed */
+  static_only: DriftVendor[];
+  /** Vendors in both views */
+  confirmed: DriftVendor[];
+  /** Unclassified runtime hosts not referenced in code */
+  unknown_runtime_hosts: DriftUnknownHost[];
+}
+
+interface Tally {
+  usages: number;
+  count: number;
+  hosts: Set<string>;
+  files: Set<string>;
+  /** "ecosystem:package" → SDK seen at runtime */
+  sdks: Map<string, { sdk_package: string; ecosystem?: string; versions: Set<string> }>;
+}
+
+function tally(map: Map<string, Tally>, key: string): Tally {
+  let t = map.get(key);
+  if (!t) {
+    t = { usages: 0, count: 0, hosts: new Set(), files: new Set(), sdks: new Map() };
+    map.set(key, t);
+  }
+  return t;
+}
+
+function staticView(tdm: TDM): { vendors: Map<string, Tally>; hosts: Set<string>; packages: TDM["packages"] } {
+  const vendors = new Map<string, Tally>();
+  const hosts = new Set<string>();
+  const add = (vendor: string | null | undefined, locations: Array<{ file: string }>) => {
+    if (!vendor) return;
+    const t = tally(vendors, vendor);
+    t.usages += locations.length;
+    for (const loc of locations) t.files.add(loc.file);
+  };
+
+  for (const sdk of tdm.sdks) add(sdk.provider, sdk.locations);
+  for (const api of tdm.apis) {
+    if (api.first_party) continue;
+    add(api.provider, api.locations);
+    const host = extractHost(api.resolved_url ?? api.url);
+    if (host) hosts.add(host);
+  }
+  for (const wh of tdm.webhooks) {
+    if (!wh.first_party) add(wh.provider, wh.locations);
+  }
+  for (const infra of tdm.infrastructure) {
+    if (!infra.first_party) add(infra.provider, infra.locations);
+  }
+  return { vendors, hosts, packages: tdm.packages };
+}
+
+function runtimeView(tdms: TDM[]): { vendors: Map<string, Tally>; unknown: Map<string, number> } {
+  const vendors = new Map<string, Tally>();
+  const unknown = new Map<string, number>();
+  for (const tdm of tdms) {
+    for (const api of tdm.apis) {
+      if (api.first_party) continue;
+      const count = api.runtime?.count ?? api.usage_count;
+      const host = extractHost(api.url);
+      if (api.provider) {
+        const t = tally(vendors, api.provider);
+        t.count += count;
+        if (host) t.hosts.add(host);
+        for (const sdk of api.runtime?.sdks ?? []) {
+          const key = `${sdk.ecosystem ?? ""}:${sdk.sdk_package}`;
+          const seen = t.sdks.get(key) ?? {
+            sdk_package: sdk.sdk_package,
+            ...(sdk.ecosystem ? { ecosystem: sdk.ecosystem } : {}),
+            versions: new Set<string>(),
+          };
+          if (sdk.version) seen.versions.add(sdk.version);
+          t.sdks.set(key, seen);
+        }
+      } else if (host) {
+        unknown.set(host, (unknown.get(host) ?? 0) + count);
+      }
+    }
+  }
+  return { vendors, unknown };
+}
+
+const bareVersion = (v: string) => v.trim().replace(/^[\^~=<>v\s]+/, "");
+
+/** The manifest entry for a runtime SDK; Go modules may carry a major-version suffix ("…/stripe-go/v76") */
+function declaredPackage(packages: TDM["packages"], sdk: { sdk_package: string; ecosystem?: string }) {
+  const majorSuffix = (name: string) =>
+    name.startsWith(`${sdk.sdk_package}/v`) && /^\d+$/.test(name.slice(sdk.sdk_package.length + 2));
+  return packages.find(
+    (p) =>
+      (!sdk.ecosystem || p.ecosystem === sdk.ecosystem) &&
+      (p.name === sdk.sdk_package || (p.ecosystem === "go" && majorSuffix(p.name))),
+  );
+}
+
+function driftSdks(r: Tally, packages: TDM["packages"]): DriftSdk[] {
+  return [...r.sdks.values()]
+    .map(({ sdk_package, ecosystem, versions }) => {
+      const declared = declaredPackage(packages, { sdk_package, ...(ecosystem ? { ecosystem } : {}) })?.current_version;
+      const runtime_versions = [...versions].sort();
+      return {
+        sdk_package,
+        ...(ecosystem ? { ecosystem } : {}),
+        runtime_versions,
+        ...(declared ? { static_version: declared } : {}),
+        ...(declared && runtime_versions.length > 0
+          ? { version_matches: runtime_versions.some((v) => bareVersion(v) === bareVersion(declared)) }
+          : {}),
+      };
+    })
+    .sort((a, b) => a.sdk_package.localeCompare(b.sdk_package));
+}
+
+function toVendor(vendor: string, s: Tally | undefined, r: Tally | undefined, packages: TDM["packages"] = []): DriftVendor {
+  return {
+    vendor,
+    static_usages: s?.usages ?? 0,
+    runtime_count: r?.count ?? 0,
+    hosts: [...(r?.hosts ?? [])].sort(),
+    files: [...(s?.files ?? [])].sort(),
+    ...(r && r.sdks.size > 0 ? { sdks: driftSdks(r, packages) } : {}),
+  };
+}
+
+/** Compare a static TDM with one or more runtime TDMs */
+export function computeDrift(staticTdm: TDM, runtimeTdms: TDM[]): DriftReport {
+  const code = staticView(staticTdm);
+  const live = runtimeView(runtimeTdms);
+
+  const report: DriftReport = {
+    runtime_only: [],
+    static_only: [],
+    confirmed: [],
+    unknown_runtime_hosts: [],
+  };
+
+  for (const [vendor, r] of live.vendors) {
+    const s = code.vendors.get(vendor);
+    (s ? report.confirmed : report.runtime_only).push(toVendor(vendor, s, r, code.packages));
+  }
+  for (const [vendor, s] of code.vendors) {
+    if (!live.vendors.has(vendor)) report.static_only.push(toVendor(vendor, s, undefined));
+  }
+  for (const [host, count] of live.unknown) {
+    if (!code.hosts.has(host)) report.unknown_runtime_hosts.push({ host, runtime_count: count });
+  }
+
+  report.runtime_only.sort((a, b) => b.runtime_count - a.runtime_count || a.vendor.localeCompare(b.vendor));
+  report.static_only.sort((a, b) => b.static_usages - a.static_usages || a.vendor.localeCompare(b.vendor));
+  report.confirmed.sort((a, b) => a.vendor.localeCompare(b.vendor));
+  report.unknown_runtime_hosts.sort((a, b) => b.runtime_count - a.runtime_count || a.host.localeCompare(b.host));
+  return report;
+}
@@ -0,0 +1,256 @@
+/**
+ * @module enrich
+ *
+ * Optional network enrichment for API hosts that match no catalog entry, so
+ * reviewers can triage unknown external endpoints straight from the TDM:
+ *
+ *   - RDAP (rdap.org)       → registrant, registrar, registration date
+ *   - Team Cymru DNS TXT    → hosting ASN and AS name
+ *   - TLS handshake on :443 → certificate subject / issuer organization
+ *
+ * Every lookup is best-effort with a short timeout. Enrichment is off by
+ * default and enabled with `thirdwatch scan --enrich`.
+ *
+ * Air-gapped hosts use a dataset instead: the enrichment an online scan
+ * found, keyed by host or registrable domain. Hosts in the dataset are not
+ * looked up, and in offline mode nothing else is either.
+ */
+
+import { lookup, resolveTxt } from "node:dns/promises";
+import { isIP } from "node:net";
+import { connect } from "node:tls";
+import { readFile } from "node:fs/promises";
+import type { TDM, TDMApi, TDMEnrichment } from "@thirdwatch/tdm";
+import { extractHost, matchesDomain } from "./first-party.js";
+import { isOffline } from "./offline.js";
+
+const TIMEOUT_MS = 5_000;
+
+// Second-level public suffixes common enough to special-case without a PSL
+const TWO_LEVEL_SUFFIXES = new Set([
+  "co.uk", "org.uk", "ac.uk", "com.au", "net.au", "co.jp", "co.nz",
+  "com.br", "co.in", "co.za", "com.mx", "com.sg", "com.cn",
+]);
+
+/** Reduce api.eu.example.co.uk → example.co.uk for RDAP domain lookups */
+export function registrableDomain(host: string): string {
+  const parts = host.toLowerCase().split(".");
+  if (parts.length <= 2) return parts.join(".");
+  const lastTwo = parts.slice(-2).join(".");
+  return TWO_LEVEL_SUFFIXES.has(lastTwo)
+    ? parts.slice(-3).join(".")
+    : lastTwo;
+}
+
+// ---------------------------------------------------------------------------
+// RDAP
+// ---------------------------------------------------------------------------
+
+interface RdapEntity {
+  roles?: string[];
+  vcardArray?: [string, [string, unknown, string, unknown][]];
+}
+
+interface RdapDomain {
+  events?: { eventAction?: string; eventDate?: string }[];
+  entities?: RdapEntity[];
+}
+
+function vcardField(entity: RdapEntity, field: string): string | undefined {
+  const props = entity.vcardArray?.[1] ?? [];
+  for (const prop of props) {
+    if (prop[0] === field && typeof prop[3] === "string" && prop[3].trim()) {
+      return prop[3].trim();
+    }
+  }
+  return undefined;
+}
+
+/** Extract registrant / registrar / registration date from an RDAP domain response */
+export function parseRdapDomain(data: RdapDomain): TDMEnrichment {
+  const result: TDMEnrichment = {};
+
+  const registration = data.events?.find((e) => e.eventAction === "registration");
+  if (registration?.eventDate) result.registered_at = registration.eventDate;
+
+  for (const entity of data.entities ?? []) {
+    const name = vcardField(entity, "org") ?? vcardField(entity, "fn");
+    if (!name || /redacted|privacy|withheld/i.test(name)) continue;
+    if (entity.roles?.includes("registrant") && !result.registrant) {
+      result.registrant = name;
+    }
+    if (entity.roles?.includes("registrar") && !result.registrar) {
+      result.registrar = name;
+    }
+  }
+
+  return result;
+}
+
+async function rdapLookup(host: string): Promise<TDMEnrichment> {
+  try {
+    const res = await fetch(`https://rdap.org/domain/${registrableDomain(host)}`, {
+      headers: { Accept: "application/rdap+json" },
+      signal: AbortSignal.timeout(TIMEOUT_MS),
+    });
+    if (!res.ok) return {};
+    return parseRdapDomain((await res.json()) as RdapDomain);
+  } catch {
+    return {};
+  }
+}
+
+// ---------------------------------------------------------------------------
+// ASN via Team Cymru DNS (origin.asn.cymru.com)
+// ---------------------------------------------------------------------------
+
+/**
+ * Parse a Team Cymru TXT record.
+ *   origin: "15169 | 8.8.8.0/24 | US | arin | 1992-12-01" → { asn: 15169 }
+ *   AS:     "15169 | US | arin | 2000-03-30 | GOOGLE, US"  → { as_name: "GOOGLE, US" }
+ */
+export function parseCymruTxt(txt: string, kind: "origin" | "as"): TDMEnrichment {
+  const fields = txt.split("|").map((f) => f.trim());
+  if (kind === "origin") {
+    const asn = Number(fields[0]?.split(" ")[0]);
+    return Number.isInteger(asn) && asn > 0 ? { asn } : {};
+  }
+  const name = fields[4];
+  return name ? { as_name: name } : {};
+}
+
+async function asnLookup(host: string): Promise<TDMEnrichment> {
+  try {
+    const ip = isIP(host) ? host : (await lookup(host, { family: 4 })).address;
+    const result: TDMEnrichment = { ip };
+
+    const reversed = ip.split(".").reverse().join(".");
+    const origin = await resolveTxt(`${reversed}.origin.asn.cymru.com`);
+    Object.assign(result, parseCymruTxt(origin[0]?.join("") ?? "", "origin"));
+
+    if (result.asn) {
+      const as = await resolveTxt(`AS${result.asn}.asn.cymru.com`);
+      Object.assign(result, parseCymruTxt(as[0]?.join("") ?? "", "as"));
+    }
+    return result;
+  } catch {
+    return {};
+  }
+}
+
+// ---------------------------------------------------------------------------
+// TLS certificate organization
+// ---------------------------------------------------------------------------
+
+function certLookup(host: string): Promise<TDMEnrichment> {
+  return new Promise((resolve) => {
+    const socket = connect({ host, port: 443, servername: host, timeout: TIMEOUT_MS });
+    const done = (result: TDMEnrichment) => {
+      socket.destroy();
+      resolve(result);
+    };
+    socket.once("secureConnect", () => {
+      const cert = socket.getPeerCertificate();
+      const result: TDMEnrichment = {};
+      if (cert.subject?.O) result.cert_subject_org = String(cert.subject.O);
+      if (cert.issuer?.O) result.cert_issuer_org = String(cert.issuer.O);
+      done(result);
+    });
+    socket.once("timeout", () => done({}));
+    socket.once("error", () => done({}));
+  });
+}
+
+// ---------------------------------------------------------------------------
+// Public API
+// ---------------------------------------------------------------------------
+
+/** Run all enrichment lookups for a single host in parallel */
+export async function enrichHost(host: string): Promise<TDMEnrichment> {
+  const [rdap, asn, cert] = await Promise.all([
+    isIP(host) ? Promise.resolve({}) : rdapLookup(host),
+    asnLookup(host),
+    certLookup(host),
+  ]);
+  return { ...rdap, ...asn, ...cert };
+}
+
+/**
+ * Collect the hostnames of every `known_api_base_urls` entry in the catalog.
+ */
+export function catalogHosts(baseUrls: Iterable<string>): string[] {
+  const hosts: string[] = [];
+  for (const url of baseUrls) {
+    const host = extractHost(url);
+    if (host) hosts.push(host);
+  }
+  return hosts;
+}
+
+// ---------------------------------------------------------------------------
+// Offline datasets
+// ---------------------------------------------------------------------------
+
+/** Enrichment by host ("api.acme-partner.io") or registrable domain ("acme-partner.io") */
+export type EnrichmentDataset = Record<string, TDMEnrichment>;
+
+/** The enrichment already recorded in TDMs, keyed by host, for offline scans */
+export function buildEnrichmentDataset(tdms: TDM[]): EnrichmentDataset {
+  const dataset: EnrichmentDataset = {};
+  for (const api of tdms.flatMap((t) => t.apis)) {
+    const host = api.enrichment ? extractHost(api.resolved_url ?? api.url) : null;
+    if (host && !dataset[host]) dataset[host] = api.enrichment!;
+  }
+  return Object.fromEntries(Object.entries(dataset).sort(([a], [b]) => a.localeCompare(b)));
+}
+
+export async function loadEnrichmentDataset(path: string): Promise<EnrichmentDataset> {
+  const raw = JSON.parse(await readFile(path, "utf8")) as unknown;
+  if (raw == null || typeof raw !== "object" || Array.isArray(raw)) {
+    throw new Error(`${path} is not an enrichment dataset (expected an object keyed by host)`);
+  }
+  return Object.fromEntries(
+    Object.entries(raw as Record<string, unknown>).filter(
+      (e): e is [string, TDMEnrichment] => e[1] != null && typeof e[1] === "object" && !Array.isArray(e[1]),
+    ),
+  );
+}
+
+export interface EnrichOptions {
+  /** Enrichment to use before (or, offline, instead of) network lookups */
+  dataset?: EnrichmentDataset;
+  /** Skip network lookups (default: offline mode) */
+  offline?: boolean;
+}
+
+/**
+ * Attach `enrichment` to every API whose host is not in the catalog, has no
+ * provider, and is not first-party. Each distinct host is looked up once.
+ */
+export async function enrichUnknownApis(
+  apis: TDMApi[],
+  knownHosts: string[],
+  options: EnrichOptions = {},
+): Promise<void> {
+  const offline = options.offline ?? isOffline();
+  const byHost = new Map<string, TDMApi[]>();
+  for (const api of apis) {
+    if (api.provider || api.first_party) continue;
+    const host = extractHost(api.resolved_url ?? api.url);
+    if (!host || host === "localhost") continue;
+    if (knownHosts.some((k) => matchesDomain(host, k))) continue;
+    const list = byHost.get(host) ?? [];
+    list.push(api);
+    byHost.set(host, list);
+  }
+
+  await Promise.all(
+    [...byHost].map(async ([host, list]) => {
+      const known = options.dataset?.[host] ?? options.dataset?.[registrableDomain(host)];
+      if (!known && offline) return;
+      const enrichment = known ?? (await enrichHost(host));
+      if (Object.keys(enrichment).length === 0) return;
+      for (const api of list) api.enrichment = enrichment;
+    }),
+  );
+}
@@ -0,0 +1,120 @@
+/**
+ * @module environments
+ *
+ * Which deployment environment a finding belongs to, so a sandbox Stripe key
+ * in config/dev.yaml isn't read as the production Stripe dependency:
+ *
+ *   config/prod.yaml, application-prod.yml, appsettings.Production.json,
+ *   values-staging.yaml, .env.development      → from the file name
+ *   k8s/overlays/prod/kustomization.yaml,
+ *   environments/staging/main.tfvars            → from the overlay directory
+ *   STRIPE_KEY: sk_test_...                     → test, from the key prefix
+ *   https://api-m.sandbox.paypal.com            → test, from the host
+ *
+ * Names are normalized to development, test, staging, and production.
+ * Locations in files that name no environment are left untagged: they apply
+ * to every environment.
+ */
+
+import type { TDM } from "@thirdwatch/tdm";
+import { basename, dirname } from "node:path";
+import type { DependencyEntry } from "./plugin.js";
+import { extractHost } from "./first-party.js";
+
+const ENVIRONMENT_NAMES: Record<string, string> = {
+  dev: "development",
+  develop: "development",
+  development: "development",
+  local: "development",
+  test: "test",
+  testing: "test",
+  qa: "test",
+  sandbox: "test",
+  stage: "staging",
+  staging: "staging",
+  preprod: "staging",
+  uat: "staging",
+  prod: "production",
+  production: "production",
+  live: "production",
+};
+
+// Directories whose children are one environment each
+const OVERLAY_PARENTS = new Set(["overlays", "environments", "envs", "env", "config", "deploy", "deployments", "clusters", "stages"]);
+
+const CONFIG_FILE_RE = /(?:^\.env(?:\.|$)|\.(?:ya?ml|json|toml|ini|properties|conf|env|tfvars)$)/i;
+
+// Test-mode credentials: Stripe sk_test_/pk_test_/rk_test_, Square sandbox-sq0...
+const TEST_KEY_RE = /\b(?:sk|pk|rk)_test_[A-Za-z0-9]{8,}|\bsandbox-sq0[a-z]{3}-/;
+const TEST_HOST_RE = /(?:^|[.-])(?:sandbox|test)(?:[.-]|$)/;
+
+/** The environment a file's path names, or null when it applies to all */
+export function environmentOf(path: string): string | null {
+  const name = basename(path);
+  if (CONFIG_FILE_RE.test(name)) {
+    // prod.yaml, application-prod.yml, appsettings.Production.json, .env.production
+    const tokens = name.toLowerCase().replace(/^\./, "").split(/[._-]/);
+    for (const token of tokens.slice(0, name.startsWith(".env") ? undefined : -1)) {
+      const env = ENVIRONMENT_NAMES[token];
+      if (env) return env;
+    }
+  }
+  // overlays/prod/..., environments/staging/...
+  const segments = dirname(path).split(/[/\\]/);
+  for (let i = 1; i < segments.length; i++) {
+    const env = ENVIRONMENT_NAMES[segments[i]!.toLowerCase()];
+    if (env && OVERLAY_PARENTS.has(segments[i - 1]!.toLowerCase())) return env;
+  }
+  return null;
+}
+
+/**
+ * Tag the locations of one file with the environment its path, credential, or
+ * host names. Runs before secrets are redacted from the location context.
+ */
+export function annotateEnvironments(entries: DependencyEntry[], source: string, relPath: string): void {
+  const lines = source.split("\n");
+  const fromPath = environmentOf(relPath);
+
+  for (const entry of entries) {
+    if (entry.kind === "package") continue;
+    const url = entry.kind === "api" ? entry.resolved_url ?? entry.url : null;
+    const host = url ? extractHost(url) : null;
+    const sandboxHost = host != null && host.split(".").slice(0, -2).some((label) => TEST_HOST_RE.test(label));
+    for (const loc of entry.locations) {
+      if (loc.file !== relPath || loc.environment) continue;
+      const env = fromPath ?? (sandboxHost || TEST_KEY_RE.test(lines[loc.line - 1] ?? "") ? "test" : null);
+      if (env) loc.environment = env;
+    }
+  }
+}
+
+export interface EnvironmentVendors {
+  environment: string;
+  /** Provider slugs (or hosts for unattributed APIs) found under the environment */
+  vendors: string[];
+}
+
+/**
+ * Vendors per environment. Findings with no environment-specific location are
+ * listed under "all", since every environment loads them.
+ */
+export function vendorsByEnvironment(tdm: TDM): EnvironmentVendors[] {
+  const groups = new Map<string, Set<string>>();
+  const add = (vendor: string | null | undefined, locations: Array<{ environment?: string }>) => {
+    if (!vendor) return;
+    const envs = new Set(locations.map((l) => l.environment ?? "all"));
+    for (const env of envs) {
+      if (!groups.has(env)) groups.set(env, new Set());
+      groups.get(env)!.add(vendor);
+    }
+  };
+  for (const api of tdm.apis) add(api.provider ?? extractHost(api.resolved_url ?? api.url), api.locations);
+  for (const sdk of tdm.sdks) add(sdk.provider, sdk.locations);
+  for (const infra of tdm.infrastructure) add(infra.provider ?? infra.type, infra.locations);
+
+  const order = ["all", "development", "test", "staging", "production"];
+  return [...groups]
+    .map(([environment, vendors]) => ({ environment, vendors: [...vendors].sort() }))
+    .sort((a, b) => order.indexOf(a.environment) - order.indexOf(b.environment));
+}
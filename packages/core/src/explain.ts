@@ -0,0 +1,57 @@
+/**
+ * @module explain
+ *
+ * Everything the catalog knows about one vendor, behind `thirdwatch explain`:
+ * category, documentation and status links, the published SLA, and the
+ * privacy policy, DPA, subprocessor list, and trust center a reviewer needs
+ * before approving it. The vendor can be named by slug ("stripe"), display
+ * name ("Google Analytics"), or any host or URL it serves
+ * (https://js.stripe.com/v3/).
+ */
+
+import type { ComplianceLinks, SDKRegistryEntry, VendorSla } from "./registry.js";
+import { complianceLinks } from "./registry.js";
+import { extractHost } from "./first-party.js";
+import { createVendorMatcher } from "./runtime.js";
+import { normalizeVendorName, vendorNameIndex } from "./aliases.js";
+
+export interface VendorExplanation {
+  vendor: string;
+  display_name: string;
+  category?: string;
+  homepage?: string;
+  docs_url?: string;
+  changelog_url?: string;
+  status_page_url?: string;
+  /** Empty when the catalog lists none */
+  compliance: ComplianceLinks;
+  sla?: VendorSla;
+  domains: string[];
+}
+
+/** Look up a vendor by slug, display name, alias, or host; null when the catalog has no match */
+export function explainVendor(query: string, registry: SDKRegistryEntry[]): VendorExplanation | null {
+  const q = query.trim().toLowerCase();
+  const slug = vendorNameIndex(registry).get(normalizeVendorName(q));
+  let entry = slug ? registry.find((e) => e.provider === slug) : undefined;
+  if (!entry) {
+    const host = extractHost(q);
+    const vendor = host ? createVendorMatcher(registry)(host) : null;
+    entry = vendor ? registry.find((e) => e.provider === vendor) : undefined;
+  }
+  if (!entry) return null;
+
+  const hosts = [...(entry.known_api_base_urls ?? []).map(extractHost), ...(entry.domains ?? [])];
+  return {
+    vendor: entry.provider,
+    display_name: entry.display_name,
+    ...(entry.category ? { category: entry.category } : {}),
+    ...(entry.homepage ? { homepage: entry.homepage } : {}),
+    ...(entry.docs_url ? { docs_url: entry.docs_url } : {}),
+    ...(entry.changelog_url ? { changelog_url: entry.changelog_url } : {}),
+    ...(entry.status_page_url ? { status_page_url: entry.status_page_url } : {}),
+    compliance: complianceLinks(entry),
+    ...(entry.sla ? { sla: entry.sla } : {}),
+    domains: [...new Set(hosts.filter((h): h is string => !!h))],
+  };
+}
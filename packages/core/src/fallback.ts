@@ -0,0 +1,181 @@
+/**
+ * @module fallback
+ *
+ * Language-agnostic pass for files no analyzer understands — Lua, Elixir,
+ * Terraform-less HCL, Makefiles, one-off config formats. Instead of reporting
+ * nothing, it pulls out what any language spells the same way:
+ *
+ *   http.request("https://api.stripe.com/v1/charges")  → api, stripe
+ *   url = "redis://cache.acme.upstash.io:6379"          → infrastructure, redis
+ *   {:host, "api.openai.com"}                           → api, openai (bare host)
+ *
+ * Without syntax to go on, results are less certain than an analyzer's:
+ * URLs are `medium` confidence when the catalog knows the host and `low`
+ * otherwise, and bare hostnames are reported only when the catalog knows
+ * them. Comments, documentation keys, local and example hosts, XML
+ * namespaces, and hosts embedded in high-entropy blobs (base64, hashes,
+ * minified data) are ignored.
+ */
+
+import { basename, extname } from "node:path";
+import type { DependencyEntry } from "./plugin.js";
+import type { VendorMatcher } from "./runtime.js";
+import { extractHost } from "./first-party.js";
+import { isPrivateIp, parseIp } from "./ip-ranges.js";
+import { DOC_KEY_RE } from "./config-endpoints.js";
+
+// Binary, media, data, and documentation files: links there aren't integrations
+const SKIP_EXTENSIONS = new Set([
+  ".png", ".jpg", ".jpeg", ".gif", ".ico", ".webp", ".bmp", ".tiff", ".svg", ".psd",
+  ".woff", ".woff2", ".ttf", ".otf", ".eot",
+  ".mp3", ".mp4", ".mov", ".avi", ".wav", ".ogg", ".webm",
+  ".zip", ".gz", ".tgz", ".bz2", ".xz", ".zst", ".7z", ".rar", ".tar", ".jar", ".war", ".whl",
+  ".exe", ".dll", ".so", ".dylib", ".a", ".o", ".class", ".pyc", ".wasm", ".bin", ".dat", ".db", ".sqlite",
+  ".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx",
+  ".md", ".markdown", ".rst", ".adoc", ".txt", ".rtf",
+  ".csv", ".tsv", ".parquet", ".avro",
+  ".lock", ".sum", ".map", ".pem", ".crt", ".key", ".p12",
+]);
+
+// Languages with their own analyzer: when it isn't loaded (`--languages`),
+// the files were left out on purpose
+const ANALYZER_EXTENSIONS = new Set([".py", ".js", ".mjs", ".cjs", ".ts", ".tsx", ".jsx", ".go", ".java", ".kt", ".rs", ".php"]);
+
+const SKIP_NAMES = /^(?:LICEN[CS]E|COPYING|NOTICE|AUTHORS|CONTRIBUTORS|CHANGELOG|CHANGES|HISTORY|CODEOWNERS)(?:\..*)?$/i;
+
+/** Whether a file no analyzer claims is worth the generic pass */
+export function isFallbackCandidate(path: string): boolean {
+  const ext = extname(path).toLowerCase();
+  return !SKIP_EXTENSIONS.has(ext) && !ANALYZER_EXTENSIONS.has(ext) && !SKIP_NAMES.test(basename(path));
+}
+
+/** Whether decoded file contents look binary (a NUL in the first 8 KB) */
+export function looksBinary(source: string): boolean {
+  return source.slice(0, 8192).includes("\0");
+}
+
+/** Shannon entropy in bits per character */
+export function shannonEntropy(s: string): number {
+  if (!s) return 0;
+  const counts = new Map<string, number>();
+  for (const ch of s) counts.set(ch, (counts.get(ch) ?? 0) + 1);
+  let bits = 0;
+  for (const n of counts.values()) {
+    const p = n / s.length;
+    bits -= p * Math.log2(p);
+  }
+  return bits;
+}
+
+// A run this long and this random is a blob (base64, a hash, minified data),
+// not a hostname, whatever it happens to contain
+const BLOB_MIN_LENGTH = 40;
+const BLOB_MIN_ENTROPY = 4.5;
+// Minified bundles and data dumps
+const MAX_LINE_LENGTH = 2000;
+
+// Line comments across languages: # (shell, Ruby, Elixir, YAML), // (C family),
+// -- (Lua, SQL, Haskell), ; (Lisp, INI), % (Erlang, LaTeX), * (block comment bodies)
+const COMMENT_RE = /^(?:#|\/\/|--(?:\s|\[|$)|;|%|\/?\*|<!--)/;
+
+const URL_RE = /\b(?:https?|wss?):\/\/[^\s"'<>,`)}\]|\\]+/gi;
+
+// Connection strings, mapped to the infrastructure type they connect to
+const DSN_PATTERNS: Array<[RegExp, string]> = [
+  [/\b(?:postgres(?:ql)?|redshift)(?:\+\w+)?:\/\/[^\s"'<>`,]+/gi, "postgresql"],
+  [/\bmysql(?:\+\w+)?:\/\/[^\s"'<>`,]+/gi, "mysql"],
+  [/\bmongodb(?:\+srv)?:\/\/[^\s"'<>`,]+/gi, "mongodb"],
+  [/\brediss?:\/\/[^\s"'<>`,]+/gi, "redis"],
+  [/\bamqps?:\/\/[^\s"'<>`,]+/gi, "rabbitmq"],
+  [/\bnats:\/\/[^\s"'<>`,]+/gi, "nats"],
+  [/\bsnowflake:\/\/[^\s"'<>`,]+/gi, "snowflake"],
+  [/\bclickhouse:\/\/[^\s"'<>`,]+/gi, "clickhouse"],
+];
+
+// A hostname not preceded by a scheme, path, or address part
+const BARE_HOST_RE = /(?<![\w.@/:-])((?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,24})(?![\w-]|\.[\w-])/gi;
+
+// Hosts that name a document, namespace, or placeholder rather than a service
+const NOISE_HOSTS = new Set([
+  "www.w3.org", "w3.org", "json-schema.org", "schemas.xmlsoap.org", "schemas.microsoft.com",
+  "purl.org", "xmlns.com", "ns.adobe.com", "schema.org", "semver.org", "spdx.org",
+]);
+const NOISE_SUFFIX_RE = /(?:^|\.)(?:example\.(?:com|org|net)|localhost|local|internal|test|invalid|svc|cluster\.local)$/;
+
+function isNoiseHost(host: string): boolean {
+  if (host === "localhost" || NOISE_HOSTS.has(host) || NOISE_SUFFIX_RE.test(host)) return true;
+  return parseIp(host) != null && isPrivateIp(host);
+}
+
+/** The whitespace- and quote-delimited run around `index` */
+function tokenAt(line: string, index: number, length: number): string {
+  let start = index;
+  let end = index + length;
+  while (start > 0 && !/[\s"'`]/.test(line[start - 1]!)) start--;
+  while (end < line.length && !/[\s"'`]/.test(line[end]!)) end++;
+  return line.slice(start, end);
+}
+
+const isBlob = (token: string) => token.length >= BLOB_MIN_LENGTH && shannonEntropy(token) >= BLOB_MIN_ENTROPY;
+
+/** Find URLs, connection strings, and catalog hostnames in a file of any language */
+export function detectFallbackEndpoints(source: string, relPath: string, matchVendor: VendorMatcher): DependencyEntry[] {
+  const entries: DependencyEntry[] = [];
+  const lines = source.split("\n");
+
+  for (let i = 0; i < lines.length; i++) {
+    const line = lines[i]!;
+    const trimmed = line.trim();
+    if (!trimmed || trimmed.length > MAX_LINE_LENGTH || COMMENT_RE.test(trimmed) || DOC_KEY_RE.test(trimmed)) continue;
+    const location = { file: relPath, line: i + 1, context: trimmed.slice(0, 200), usage: "fallback" };
+    // Spans already reported, so a bare-host pass doesn't repeat them
+    let rest = trimmed;
+    const seen = new Set<string>();
+
+    for (const [pattern, type] of DSN_PATTERNS) {
+      for (const m of trimmed.matchAll(pattern)) {
+        const ref = m[0].replace(/[.;]+$/, "");
+        rest = rest.replace(m[0], " ");
+        const host = extractHost(ref);
+        if (seen.has(ref) || (host && isNoiseHost(host))) continue;
+        seen.add(ref);
+        entries.push({ kind: "infrastructure", type, connection_ref: ref, locations: [location], confidence: "medium" });
+      }
+    }
+
+    for (const m of rest.matchAll(URL_RE)) {
+      const url = m[0].replace(/[.;:]+$/, "");
+      rest = rest.replace(m[0], " ");
+      const host = extractHost(url);
+      if (!host || seen.has(url) || isNoiseHost(host) || isBlob(tokenAt(trimmed, trimmed.indexOf(m[0]), m[0].length))) continue;
+      seen.add(url);
+      const provider = matchVendor(host);
+      entries.push({
+        kind: "api",
+        url,
+        ...(provider ? { provider } : {}),
+        locations: [location],
+        usage_count: 1,
+        confidence: provider ? "medium" : "low",
+      });
+    }
+
+    // Bare hostnames are everywhere (file names, module paths), so only catalog hosts count
+    for (const m of rest.matchAll(BARE_HOST_RE)) {
+      const host = m[1]!.toLowerCase();
+      if (seen.has(host) || isNoiseHost(host)) continue;
+      const provider = matchVendor(host);
+      if (!provider || isBlob(tokenAt(rest, m.index!, m[0].length))) continue;
+      seen.add(host);
+      entries.push({
+        kind: "api",
+        url: `https://${host}`,
+        provider,
+        locations: [location],
+        usage_count: 1,
+        confidence: "low",
+      });
+    }
+  }
+  return entries;
+}
@@ -0,0 +1,177 @@
+/**
+ * @module fingerprint
+ *
+ * Finding fingerprints that survive refactors. A location's fingerprint
+ * hashes what was found and where it sits semantically, not its line number:
+ *
+ *   what    sdk:stripe/stripe, api:POST https://api.stripe.com/v1/charges, ...
+ *   where   the file name (not its directory), the enclosing function or
+ *           class, and the whitespace-normalized source line
+ *
+ * so moving payments/billing.py to services/billing.py, or adding code above
+ * a call, keeps every fingerprint. Renaming the function or editing the line
+ * itself produces a new finding, as it should. Identical lines in the same
+ * function are told apart by their order (…-2, …-3). The same finding in two
+ * files of the same name (api/handlers/index.ts, web/handlers/index.ts) is
+ * told apart by directory, in every file after the first by path
+ * (`disambiguateFingerprints`). Package fingerprints ignore the version, so a
+ * dependency bump is not a new finding.
+ *
+ * Baselines compare fingerprints (`diffFindings`), and analyzer suppressions
+ * can name them.
+ */
+
+import { createHash } from "node:crypto";
+import { basename, dirname } from "node:path";
+import type { TDM, TDMLocation } from "@thirdwatch/tdm";
+import type { DependencyEntry } from "./plugin.js";
+import { detectHardcodedSecret } from "./secrets.js";
+
+// def charge(, func (c *Client) Charge(, function charge(, fn charge(, class Billing, sub charge
+const KEYWORD_DECL_RE = /^\s*(?:export\s+)?(?:async\s+)?(?:def|func|function\*?|fn|class|interface|struct|impl|module|sub)\s+(?:\([^)]*\)\s*)?(\w+)/;
+// public Charge charge(...) {, charge(params) {, const charge = async (...) =>
+const METHOD_DECL_RE =
+  /^\s*(?:(?:public|private|protected|internal|static|final|override|async|export|abstract|synchronized)\s+)*(?:[\w<>[\],.?]+\s+)?(\w+)\s*\([^;]*\)\s*(?::\s*[\w<>[\],.?| ]+)?\s*(?:throws\s+[\w., ]+)?\{\s*$/;
+const ARROW_DECL_RE = /^\s*(?:export\s+)?(?:const|let|var)\s+(\w+)\s*=\s*(?:async\s*)?(?:\([^)]*\)|\w+)\s*=>/;
+const CONTROL_WORDS = new Set(["if", "for", "while", "switch", "catch", "with", "return", "elif", "else", "try"]);
+
+function indentOf(line: string): number {
+  return line.match(/^\s*/)![0].replace(/\t/g, "    ").length;
+}
+
+function declaredName(line: string): string | null {
+  const m = line.match(KEYWORD_DECL_RE) ?? line.match(ARROW_DECL_RE) ?? line.match(METHOD_DECL_RE);
+  return m && !CONTROL_WORDS.has(m[1]!) ? m[1]! : null;
+}
+
+/**
+ * The function or class enclosing a line: the nearest declaration above it
+ * that is indented less. Empty at the top level of a file.
+ */
+export function enclosingSymbol(lines: string[], line: number): string {
+  const target = lines[line - 1] ?? "";
+  let indent = target.trim() === "" ? Infinity : indentOf(target);
+  const names: string[] = [];
+  for (let i = line - 2; i >= 0 && indent > 0; i--) {
+    const text = lines[i]!;
+    if (text.trim() === "" || indentOf(text) >= indent) continue;
+    indent = indentOf(text);
+    const name = declaredName(text);
+    if (name) names.unshift(name);
+  }
+  return names.join(".");
+}
+
+/** What a finding is, independent of where it appears */
+export function findingIdentity(entry: DependencyEntry): string {
+  switch (entry.kind) {
+    case "package":
+      return `package:${entry.ecosystem}/${entry.name}`;
+    case "api":
+      return `api:${entry.method ?? "ANY"} ${entry.url}`;
+    case "sdk":
+      return `sdk:${entry.provider}/${entry.sdk_package}`;
+    case "infrastructure":
+      return `infra:${entry.type}/${entry.connection_ref}`;
+    case "webhook":
+      return `webhook:${entry.direction}/${entry.target_url}`;
+  }
+}
+
+function normalizeLine(text: string): string {
+  const secret = detectHardcodedSecret(text);
+  const redacted = secret ? text.split(secret.value).join("[REDACTED]") : text;
+  return redacted.trim().replace(/\s+/g, " ");
+}
+
+/**
+ * Set `fingerprint` on locations that lack one. With `source`, only the
+ * locations in `relPath` are fingerprinted, from that file's lines; without
+ * it, the location's `context` stands in for the line and there is no
+ * enclosing symbol (manifests, where neither matters).
+ */
+export function fingerprintFindings(entries: DependencyEntry[], source?: string, relPath?: string): void {
+  const lines = source?.split("\n");
+  const seen = new Map<string, number>();
+
+  for (const entry of entries) {
+    const identity = findingIdentity(entry);
+    for (const loc of entry.locations) {
+      if (loc.fingerprint || (relPath !== undefined && loc.file !== relPath)) continue;
+      const text = entry.kind === "package" ? "" : normalizeLine(lines?.[loc.line - 1] ?? loc.context ?? "");
+      const symbol = lines && entry.kind !== "package" ? enclosingSymbol(lines, loc.line) : "";
+      const hash = createHash("sha256")
+        .update([identity, basename(loc.file), symbol, text].join("\0"))
+        .digest("hex")
+        .slice(0, 16);
+      const n = (seen.get(hash) ?? 0) + 1;
+      seen.set(hash, n);
+      loc.fingerprint = n === 1 ? hash : `${hash}-${n}`;
+    }
+  }
+}
+
+/**
+ * Re-fingerprint locations that clash with one in another file: fingerprints
+ * hash only the file name, and each file is fingerprinted on its own. The
+ * first file by path keeps its fingerprint; each later one hashes its
+ * directory in too.
+ */
+export function disambiguateFingerprints(entries: DependencyEntry[]): void {
+  const byFingerprint = new Map<string, TDMLocation[]>();
+  for (const entry of entries) {
+    for (const loc of entry.locations) {
+      if (!loc.fingerprint) continue;
+      const group = byFingerprint.get(loc.fingerprint);
+      if (group) group.push(loc);
+      else byFingerprint.set(loc.fingerprint, [loc]);
+    }
+  }
+
+  for (const [fingerprint, locs] of byFingerprint) {
+    const files = [...new Set(locs.map((l) => l.file))].sort();
+    if (files.length < 2) continue;
+    for (const loc of locs) {
+      if (loc.file === files[0]) continue;
+      loc.fingerprint = createHash("sha256")
+        .update([fingerprint, dirname(loc.file)].join("\0"))
+        .digest("hex")
+        .slice(0, 16);
+    }
+  }
+}
+
+export interface FindingRef {
+  fingerprint: string;
+  /** The finding's identity, e.g. "sdk:stripe/stripe" */
+  finding: string;
+  file: string;
+  line: number;
+}
+
+function findingRefs(tdm: TDM): FindingRef[] {
+  const entries: DependencyEntry[] = [
+    ...tdm.packages.map((e) => ({ kind: "package" as const, ...e })),
+    ...tdm.apis.map((e) => ({ kind: "api" as const, ...e })),
+    ...tdm.sdks.map((e) => ({ kind: "sdk" as const, ...e })),
+    ...tdm.infrastructure.map((e) => ({ kind: "infrastructure" as const, ...e })),
+    ...tdm.webhooks.map((e) => ({ kind: "webhook" as const, ...e })),
+  ];
+  return entries.flatMap((entry) =>
+    entry.locations.flatMap((l: TDMLocation) =>
+      l.fingerprint ? [{ fingerprint: l.fingerprint, finding: findingIdentity(entry), file: l.file, line: l.line }] : [],
+    ),
+  );
+}
+
+/** Findings added since a baseline TDM, and baseline findings that are gone */
+export function diffFindings(current: TDM, baseline: TDM): { added: FindingRef[]; removed: FindingRef[] } {
+  const now = findingRefs(current);
+  const before = findingRefs(baseline);
+  const nowSet = new Set(now.map((r) => r.fingerprint));
+  const beforeSet = new Set(before.map((r) => r.fingerprint));
+  return {
+    added: now.filter((r) => !beforeSet.has(r.fingerprint)),
+    removed: before.filter((r) => !nowSet.has(r.fingerprint)),
+  };
+}
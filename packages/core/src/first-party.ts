@@ -0,0 +1,120 @@
+import type { DependencyEntry } from "./plugin.js";
+
+// ---------------------------------------------------------------------------
+// Host extraction — URLs, connection strings, and bare host:port values
+// ---------------------------------------------------------------------------
+
+/**
+ * Extract a lowercase hostname from a URL, connection string, or `host:port`.
+ * Returns null for env var names and unresolved templates.
+ */
+export function extractHost(ref: string): string | null {
+  const trimmed = ref.trim();
+  if (!trimmed || trimmed.startsWith("${")) return null;
+
+  const schemeMatch = trimmed.match(/^[a-z][a-z0-9+.-]*:\/\/(?:[^@/]*@)?([^/:?#,]+)/i);
+  if (schemeMatch) return schemeMatch[1]!.toLowerCase();
+
+  const hostPort = trimmed.match(/^([a-z0-9](?:[a-z0-9-]*[a-z0-9])?(?:\.[a-z0-9-]+)*)(?::\d+)?$/i);
+  if (hostPort && hostPort[1]!.includes(".")) return hostPort[1]!.toLowerCase();
+  if (hostPort && hostPort[1] === "localhost") return "localhost";
+
+  return null;
+}
+
+// ---------------------------------------------------------------------------
+// Domain pattern matching
+// ---------------------------------------------------------------------------
+
+/**
+ * Match a hostname against a registered domain pattern.
+ *
+ *   "api.mycompany.com"     — exact host
+ *   "*.internal"            — any subdomain of internal (db.internal, a.b.internal)
+ *   "mycompany.com"         — the domain itself and all of its subdomains
+ *   "kafka.*.amazonaws.com" — an inner "*" matches one label (kafka.us-east-1.amazonaws.com
+ *                             and its subdomains)
+ */
+export function matchesDomain(host: string, pattern: string): boolean {
+  const p = pattern.trim().toLowerCase();
+  const h = host.toLowerCase();
+  const subdomainsOnly = p.startsWith("*.");
+  const rest = subdomainsOnly ? p.slice(2) : p;
+  if (rest.includes("*")) {
+    return new RegExp(domainPatternRegex(p)).test(h);
+  }
+  if (subdomainsOnly) {
+    return h.endsWith(p.slice(1));
+  }
+  return h === p || h.endsWith("." + p);
+}
+
+/**
+ * matchesDomain's pattern as an anchored regular expression, for engines that
+ * enforce the same patterns elsewhere (OPA, egress proxies). Uses only RE2
+ * syntax.
+ */
+export function domainPatternRegex(pattern: string): string {
+  const p = pattern.trim().toLowerCase();
+  const subdomainsOnly = p.startsWith("*.");
+  const rest = subdomainsOnly ? p.slice(2) : p;
+  const body = rest
+    .split(".")
+    .map((label) => (label === "*" ? "[^.]+" : label.replace(/[\\^$.|?*+()[\]{}]/g, "\\$&")))
+    .join("\\.");
+  return `^${subdomainsOnly ? ".+\\." : "(?:.+\\.)?"}${body}$`;
+}
+
+export function isFirstPartyHost(host: string, domains: string[]): boolean {
+  return domains.some((d) => matchesDomain(host, d));
+}
+
+// ---------------------------------------------------------------------------
+// Classification
+// ---------------------------------------------------------------------------
+
+function entryHost(entry: DependencyEntry): string | null {
+  switch (entry.kind) {
+    case "api":
+      return extractHost(entry.resolved_url ?? entry.url);
+    case "infrastructure":
+      return entry.resolved_host
+        ? extractHost(entry.resolved_host)
+        : extractHost(entry.connection_ref);
+    case "webhook":
+      return extractHost(entry.target_url);
+    default:
+      return null;
+  }
+}
+
+export type FirstPartyMode = "separate" | "hide";
+
+/**
+ * Mark API, infrastructure, and webhook entries whose host belongs to the
+ * organization as `first_party`. In "hide" mode they are dropped instead.
+ */
+export function classifyFirstParty(
+  entries: DependencyEntry[],
+  domains: string[],
+  mode: FirstPartyMode = "separate",
+): DependencyEntry[] {
+  if (domains.length === 0) return entries;
+
+  const result: DependencyEntry[] = [];
+  for (const entry of entries) {
+    const host = entryHost(entry);
+    if (host && isFirstPartyHost(host, domains)) {
+      if (mode === "hide") continue;
+      if (
+        entry.kind === "api" ||
+        entry.kind === "infrastructure" ||
+        entry.kind === "webhook"
+      ) {
+        entry.first_party = true;
+      }
+    }
+    result.push(entry);
+  }
+  return result;
+}
@@ -0,0 +1,329 @@
+/**
+ * @module flow-logs
+ *
+ * Network flow, firewall, and egress-gateway logs as a runtime source
+ * (`thirdwatch ingest flow`). Most of these sources only record addresses,
+ * so destinations are attributed through the catalog's `ip_ranges`; SNI and
+ * HTTP authority are used whenever the log carries them. Supported formats:
+ *
+ *   vpc       — AWS VPC Flow Logs, default or custom fields (the header line
+ *               of S3-delivered files is honoured)
+ *   gcp       — Google Cloud VPC Flow Logs (Cloud Logging JSON export)
+ *   suricata  — Suricata EVE JSON, including AWS Network Firewall flow,
+ *               alert, and TLS logs
+ *   iptables  — netfilter LOG target lines from the kernel log
+ *   envoy     — Envoy / Istio egress gateway access logs (default format)
+ *
+ * Only outbound traffic is kept: flows to private or loopback destinations,
+ * rejected flows, and reply packets are dropped.
+ */
+
+import type { RuntimeObservation } from "./runtime.js";
+import { isPrivateIp, normalizePath } from "./runtime.js";
+import { parseIp } from "./ip-ranges.js";
+import { normalizeQueryName } from "./dns-logs.js";
+
+export type FlowLogFormat = "vpc" | "gcp" | "suricata" | "iptables" | "envoy";
+
+export const FLOW_LOG_FORMATS: FlowLogFormat[] = ["vpc", "gcp", "suricata", "iptables", "envoy"];
+
+const SOURCES: Record<FlowLogFormat, string> = {
+  vpc: "flow",
+  gcp: "flow",
+  suricata: "firewall",
+  iptables: "firewall",
+  envoy: "gateway",
+};
+
+export interface FlowLogParserOptions {
+  /** Keep flows to private and loopback destinations */
+  includePrivate?: boolean;
+  /** Clock for lines without a usable timestamp */
+  now?: () => string;
+}
+
+/** Parses one line; null for lines that are not outbound flows */
+export type FlowLogParser = (line: string) => RuntimeObservation | null;
+
+interface FlowFields {
+  ip?: string | undefined;
+  host?: string | undefined;
+  port?: number | undefined;
+  method?: string | undefined;
+  path?: string | undefined;
+  client?: string | undefined;
+  user_agent?: string | undefined;
+  timestamp: string;
+}
+
+function toIso(value: string | number | undefined, fallback: string): string {
+  if (value === undefined || value === "") return fallback;
+  const ms = typeof value === "number" || /^\d+(\.\d+)?$/.test(value)
+    ? Number(value) * (Number(value) > 1e11 ? 1 : 1000)
+    // Suricata writes offsets without a colon ("+0000")
+    : Date.parse(value.replace(/([+-]\d\d)(\d\d)$/, "$1:$2"));
+  return Number.isNaN(ms) ? fallback : new Date(ms).toISOString();
+}
+
+function portOf(value: unknown): number | undefined {
+  const n = Number(value);
+  return Number.isInteger(n) && n > 0 && n < 65536 ? n : undefined;
+}
+
+function present(value: unknown): string | undefined {
+  return typeof value === "string" && value !== "" && value !== "-" ? value : undefined;
+}
+
+// "host:port", "[v6]:port", or a bare host
+function splitHostPort(value: string): { host: string; port?: number } {
+  const bracketed = value.match(/^\[([^\]]+)\](?::(\d+))?$/);
+  if (bracketed) {
+    const port = portOf(bracketed[2]);
+    return { host: bracketed[1]!, ...(port ? { port } : {}) };
+  }
+  const m = value.match(/^([^:]+):(\d+)$/);
+  if (m) {
+    const port = portOf(m[2]);
+    return { host: m[1]!, ...(port ? { port } : {}) };
+  }
+  return { host: value };
+}
+
+function parseJson(line: string): Record<string, unknown> | null {
+  try {
+    const value: unknown = JSON.parse(line);
+    return value && typeof value === "object" ? (value as Record<string, unknown>) : null;
+  } catch {
+    return null;
+  }
+}
+
+function record(value: unknown): Record<string, unknown> {
+  return value && typeof value === "object" ? (value as Record<string, unknown>) : {};
+}
+
+// ---------------------------------------------------------------------------
+// Per-format parsers
+// ---------------------------------------------------------------------------
+
+const VPC_DEFAULT_FIELDS = [
+  "version", "account-id", "interface-id", "srcaddr", "dstaddr", "srcport", "dstport",
+  "protocol", "packets", "bytes", "start", "end", "action", "log-status",
+];
+
+function vpcParser(now: () => string): (line: string) => FlowFields | null {
+  let fields = VPC_DEFAULT_FIELDS;
+  return (line) => {
+    const tokens = line.trim().split(/\s+/);
+    if (tokens.includes("dstaddr") || tokens.includes("srcaddr")) {
+      fields = tokens;
+      return null;
+    }
+    const rec = new Map(fields.map((f, i) => [f, tokens[i]]));
+    if ((rec.get("action") ?? "ACCEPT") !== "ACCEPT") return null;
+    if ((rec.get("log-status") ?? "OK") !== "OK") return null;
+    if (rec.has("flow-direction") && rec.get("flow-direction") !== "egress") return null;
+    // TCP and UDP only; ICMP and friends carry no port
+    if (rec.has("protocol") && rec.get("protocol") !== "6" && rec.get("protocol") !== "17") return null;
+    return {
+      ip: present(rec.get("pkt-dstaddr")) ?? present(rec.get("dstaddr")),
+      port: portOf(rec.get("dstport")),
+      client: present(rec.get("instance-id")) ?? present(rec.get("srcaddr")),
+      timestamp: toIso(rec.get("start"), now()),
+    };
+  };
+}
+
+function gcpFlow(line: string, now: () => string): FlowFields | null {
+  const rec = parseJson(line);
+  if (!rec) return null;
+  const payload = record(rec.jsonPayload ?? rec);
+  const conn = record(payload.connection);
+  if (typeof conn.dest_ip !== "string") return null;
+  // Flows between two VMs are reported by both ends; count the sender's copy
+  if (payload.reporter !== undefined && payload.reporter !== "SRC") return null;
+  const pod = record(record(payload.src_gke_details).pod);
+  const vm = record(payload.src_instance);
+  return {
+    ip: conn.dest_ip,
+    port: portOf(conn.dest_port),
+    client: present(pod.pod_name) ?? present(vm.vm_name) ?? present(conn.src_ip),
+    timestamp: toIso(present(payload.start_time) ?? present(rec.timestamp), now()),
+  };
+}
+
+const SURICATA_EVENTS = new Set(["flow", "netflow", "tls", "http", "alert"]);
+// Flow IDs already counted; bounded so long-running ingests don't grow without limit
+const MAX_SEEN_FLOWS = 100_000;
+
+function suricataParser(now: () => string): (line: string) => FlowFields | null {
+  const seen = new Set<string>();
+  return (line) => {
+    const rec = parseJson(line);
+    if (!rec) return null;
+    // AWS Network Firewall wraps the EVE record in { firewall_name, event }
+    const ev = record(rec.event ?? rec);
+    if (!SURICATA_EVENTS.has(String(ev.event_type))) return null;
+    if (typeof ev.dest_ip !== "string") return null;
+    if (record(ev.alert).action === "blocked") return null;
+
+    // One connection shows up as several events (tls, then flow); count it once
+    const flowId = ev.flow_id === undefined ? undefined : String(ev.flow_id);
+    if (flowId !== undefined) {
+      if (seen.has(flowId)) return null;
+      if (seen.size >= MAX_SEEN_FLOWS) seen.clear();
+      seen.add(flowId);
+    }
+
+    const http = record(ev.http);
+    const method = present(http.http_method);
+    const url = present(http.url);
+    return {
+      ip: ev.dest_ip,
+      host: present(record(ev.tls).sni) ?? present(http.hostname),
+      port: portOf(ev.dest_port),
+      ...(method ? { method } : {}),
+      ...(url ? { path: url } : {}),
+      client: present(ev.src_ip),
+      user_agent: present(http.http_user_agent),
+      timestamp: toIso(present(ev.timestamp) ?? present(rec.event_timestamp), now()),
+    };
+  };
+}
+
+const SYSLOG_TS_RE = /^([A-Z][a-z]{2}\s+\d{1,2}\s+\d\d:\d\d:\d\d)\s/;
+const ISO_TS_RE = /^(\d{4}-\d\d-\d\dT\S+)\s/;
+
+function iptablesFlow(line: string, now: () => string): FlowFields | null {
+  if (!line.includes(" DST=")) return null;
+  const kv = new Map<string, string>();
+  for (const m of line.matchAll(/\b([A-Z]+)=(\S*)/g)) kv.set(m[1]!, m[2]!);
+  const proto = kv.get("PROTO");
+  if (proto !== "TCP" && proto !== "UDP") return null;
+  // Only the opening SYN of a TCP connection; SYN-ACKs are replies
+  if (proto === "TCP" && (!/\sSYN\s/.test(` ${line} `) || /\sACK\s/.test(` ${line} `))) return null;
+
+  let ts: string | undefined = line.match(ISO_TS_RE)?.[1];
+  const syslog = line.match(SYSLOG_TS_RE)?.[1];
+  if (!ts && syslog) ts = `${syslog} ${now().slice(0, 4)} UTC`;
+  return {
+    ip: kv.get("DST"),
+    port: portOf(kv.get("DPT")),
+    client: present(kv.get("SRC")),
+    timestamp: toIso(ts, now()),
+  };
+}
+
+const ENVOY_RE = /^\[([^\]]+)\]\s+"(\S+) (\S+) [^"]*"/;
+const UPSTREAM_RE = /^(?:\[[0-9a-f:.]+\]|[0-9.]+):\d+$/i;
+
+function envoyFlow(line: string, now: () => string): FlowFields | null {
+  const m = line.match(ENVOY_RE);
+  if (!m) return null;
+  const [, time, method, path] = m;
+
+  // %REQ(:AUTHORITY)% and %UPSTREAM_HOST% are the quoted pair ending at the
+  // upstream address; Istio's format appends unquoted fields after them
+  const quoted = [...line.matchAll(/"([^"]*)"/g)];
+  let upstreamIndex = -1;
+  for (let i = quoted.length - 1; i > 0; i--) {
+    if (UPSTREAM_RE.test(quoted[i]![1]!)) {
+      upstreamIndex = i;
+      break;
+    }
+  }
+  if (upstreamIndex < 0) return null;
+  const upstreamMatch = quoted[upstreamIndex]!;
+  const upstream = splitHostPort(upstreamMatch[1]!);
+  const authority = present(quoted[upstreamIndex - 1]![1]);
+  // %REQ(USER-AGENT)% precedes %REQ(X-REQUEST-ID)% and the authority
+  const userAgent = upstreamIndex >= 3 ? present(quoted[upstreamIndex - 3]![1]) : undefined;
+
+  // Istio tail: cluster, upstream local, downstream local, downstream remote, SNI, route
+  const tail = line.slice(upstreamMatch.index! + upstreamMatch[0].length).trim().split(/\s+/);
+  const sni = present(tail[4]);
+  const downstream = present(tail[3]);
+
+  const target: { host: string; port?: number } | undefined =
+    authority ? splitHostPort(authority) : sni ? { host: sni } : undefined;
+  const realMethod = method !== "-" ? method : undefined;
+  return {
+    ip: upstream.host.replace(/^\[|\]$/g, ""),
+    host: target?.host,
+    port: target?.port ?? upstream.port,
+    ...(realMethod ? { method: realMethod } : {}),
+    ...(realMethod && path !== "-" ? { path } : {}),
+    client: downstream ? splitHostPort(downstream).host : undefined,
+    user_agent: userAgent,
+    timestamp: toIso(time, now()),
+  };
+}
+
+// ---------------------------------------------------------------------------
+// Public API
+// ---------------------------------------------------------------------------
+
+/**
+ * Create a parser for one log stream. Parsers are stateful — VPC headers and
+ * Suricata flow IDs carry across lines — so use a fresh one per file.
+ */
+export function createFlowLogParser(
+  format: FlowLogFormat,
+  options: FlowLogParserOptions = {},
+): FlowLogParser {
+  const now = options.now ?? (() => new Date().toISOString());
+  const parse: (line: string) => FlowFields | null =
+    format === "vpc" ? vpcParser(now)
+    : format === "suricata" ? suricataParser(now)
+    : format === "gcp" ? (line) => gcpFlow(line, now)
+    : format === "iptables" ? (line) => iptablesFlow(line, now)
+    : (line) => envoyFlow(line, now);
+
+  return (line) => {
+    const f = parse(line);
+    if (!f) return null;
+    const ip = f.ip && parseIp(f.ip) ? f.ip : undefined;
+
+    let host: string | undefined;
+    if (f.host) {
+      if (parseIp(f.host)) {
+        if (!ip) return null;
+      } else {
+        // Names that resolve inside the cluster or VPC are not third parties
+        host = normalizeQueryName(f.host) ?? undefined;
+        if (!host) return null;
+      }
+    }
+    if (!host && !ip) return null;
+    if (!host && !options.includePrivate && isPrivateIp(ip!)) return null;
+
+    return {
+      source: SOURCES[format],
+      ...(host ? { host } : {}),
+      ...(ip ? { ip } : {}),
+      ...(f.port ? { port: f.port } : {}),
+      ...(f.method ? { method: f.method.toUpperCase() } : {}),
+      ...(f.path ? { path: normalizePath(f.path) } : {}),
+      ...(f.client ? { client: f.client } : {}),
+      ...(f.user_agent ? { user_agent: f.user_agent } : {}),
+      timestamp: f.timestamp,
+    };
+  };
+}
+
+/** Guess the format from a sample line */
+export function detectFlowLogFormat(line: string): FlowLogFormat | null {
+  const trimmed = line.trim();
+  if (trimmed.startsWith("{")) {
+    const rec = parseJson(trimmed);
+    if (!rec) return null;
+    const ev = record(rec.event ?? rec);
+    if (typeof ev.event_type === "string") return "suricata";
+    if (record(record(rec.jsonPayload ?? rec).connection).dest_ip !== undefined) return "gcp";
+    return null;
+  }
+  if (/\bdstaddr\b/.test(trimmed) || /^\d+\s+\d{12}\s+eni-/.test(trimmed)) return "vpc";
+  if (ENVOY_RE.test(trimmed)) return "envoy";
+  if (/\bSRC=\S+\s+DST=\S+/.test(trimmed)) return "iptables";
+  return null;
+}
@@ -0,0 +1,100 @@
+/**
+ * @module frontend
+ *
+ * Vendors loaded by the browser rather than the server, referenced from HTML
+ * templates and stylesheets:
+ *
+ *   <script async src="https://www.googletagmanager.com/gtag/js?id=G-XXXX">
+ *   <script src="//js.stripe.com/v3/"></script>
+ *   <iframe src="https://www.youtube.com/embed/abc123"></iframe>
+ *   <link href="https://fonts.googleapis.com/css2?family=Inter" rel="stylesheet">
+ *   @import url("https://fonts.googleapis.com/css2?family=Inter");
+ *   s.src = "https://widget.intercom.io/widget/" + APP_ID;   (inline loader)
+ *
+ * Each external source becomes a GET of the asset URL, query string dropped
+ * (measurement IDs and font families aren't endpoints), with usage
+ * "script_tag", "iframe", "stylesheet", or "font" so the catalog attributes it
+ * to the vendor serving it. Relative and same-page sources are skipped.
+ */
+
+import { extname } from "node:path";
+import type { DependencyEntry } from "./plugin.js";
+
+/** Templates and static assets handed to the browser */
+export const FRONTEND_EXTENSIONS = new Set([
+  ".html",
+  ".htm",
+  ".vue",
+  ".svelte",
+  ".hbs",
+  ".handlebars",
+  ".ejs",
+  ".njk",
+  ".jinja",
+  ".j2",
+  ".twig",
+  ".liquid",
+  ".mustache",
+  ".gohtml",
+  ".tmpl",
+  ".css",
+  ".scss",
+]);
+
+export function isFrontendAsset(path: string): boolean {
+  return FRONTEND_EXTENSIONS.has(extname(path));
+}
+
+const URL_VALUE = String.raw`["']?((?:https?:)?\/\/[^"'\s)>]+)`;
+
+// Tag attributes, CSS references, and loader snippets that set a script's src
+const SOURCE_PATTERNS: Array<{ pattern: RegExp; usage: string }> = [
+  { pattern: new RegExp(String.raw`<script\b[^>]*?\ssrc\s*=\s*${URL_VALUE}`, "gi"), usage: "script_tag" },
+  { pattern: new RegExp(String.raw`<iframe\b[^>]*?\ssrc\s*=\s*${URL_VALUE}`, "gi"), usage: "iframe" },
+  { pattern: new RegExp(String.raw`<link\b[^>]*?\shref\s*=\s*${URL_VALUE}`, "gi"), usage: "stylesheet" },
+  { pattern: new RegExp(String.raw`@import\s+(?:url\(\s*)?${URL_VALUE}`, "gi"), usage: "stylesheet" },
+  { pattern: new RegExp(String.raw`\bsrc\s*:\s*(?:local\([^)]*\)\s*,\s*)*url\(\s*${URL_VALUE}`, "gi"), usage: "font" },
+  { pattern: new RegExp(String.raw`\.src\s*=\s*${URL_VALUE}`, "g"), usage: "script_tag" },
+];
+
+/** Absolute https URL of an external asset, without query or fragment */
+function assetUrl(raw: string): string {
+  const url = raw.startsWith("//") ? `https:${raw}` : raw;
+  return url.replace(/[?#].*$/, "");
+}
+
+/** Find external scripts, iframes, stylesheets, and fonts in one template or stylesheet */
+export function detectFrontendAssets(source: string, relPath: string): DependencyEntry[] {
+  const entries: DependencyEntry[] = [];
+  const seen = new Set<string>();
+  const lines = source.split("\n");
+
+  for (let i = 0; i < lines.length; i++) {
+    const line = lines[i]!;
+    const trimmed = line.trim();
+    if (!trimmed || trimmed.startsWith("<!--") || trimmed.startsWith("//")) continue;
+    for (const { pattern, usage } of SOURCE_PATTERNS) {
+      for (const match of line.matchAll(pattern)) {
+        const url = assetUrl(match[1]!);
+        // Report each asset once per line
+        const key = `${i}:${url}`;
+        if (seen.has(key) || !/^https?:\/\/[a-z0-9.-]+\.[a-z]{2,}/i.test(url)) continue;
+        seen.add(key);
+        entries.push({
+          kind: "api",
+          url,
+          method: "GET",
+          locations: [{
+            file: relPath,
+            line: i + 1,
+            context: trimmed.slice(0, 200),
+            usage: usage === "stylesheet" && /\.(?:woff2?|ttf|otf|eot)$/i.test(url) ? "font" : usage,
+          }],
+          usage_count: 1,
+          confidence: "high",
+        });
+      }
+    }
+  }
+  return entries;
+}
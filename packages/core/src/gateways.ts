@@ -0,0 +1,132 @@
+/**
+ * @module gateways
+ *
+ * Egress gateway policy. `gateways` in .thirdwatch.yml declares vendors that
+ * must be reached through an internal proxy rather than directly:
+ *
+ *   gateways:
+ *     - name: llm-proxy
+ *       url: https://llm-proxy.internal
+ *       categories: [ai]
+ *     - name: payments-egress
+ *       url: https://egress.internal
+ *       vendors: [stripe, adyen]
+ *
+ * An SDK client of such a vendor is routed when its base URL (or its
+ * `<PROVIDER>_BASE_URL` variable) points at the gateway's host; a client on
+ * the vendor's default endpoint or any other host bypasses it, as does a
+ * direct HTTP call to the vendor's API. Bypasses are marked `gateway_bypass`
+ * with the gateway's severity (default "error"; `rule_settings` still wins)
+ * and their locations get a `route_via_gateway` remediation. When the line
+ * spells out the vendor's URL, the fix swaps its origin for the gateway's:
+ *
+ *   OpenAI(base_url="https://api.openai.com/v1")  → OpenAI(base_url="https://llm-proxy.internal/v1")
+ *
+ * Endpoints read from variables that aren't set can't be judged and are left
+ * alone.
+ */
+
+import type { Severity, TDMRemediation } from "@thirdwatch/tdm";
+import type { DependencyEntry } from "./plugin.js";
+import { extractHost } from "./first-party.js";
+import { lineDiff } from "./remediation.js";
+import { detectHardcodedSecret } from "./secrets.js";
+
+export interface GatewayPolicy {
+  name: string;
+  /** Origin clients use instead of the vendor's, e.g. "https://llm-proxy.internal" */
+  url: string;
+  /** Providers that must be reached through the gateway */
+  vendors?: string[];
+  /** Catalog categories whose providers must be */
+  categories?: string[];
+  /** Severity of a bypass (default: error) */
+  severity?: Severity;
+}
+
+/** The gateway a provider must go through: a vendor listing beats a category */
+export function gatewayFor(
+  gateways: GatewayPolicy[],
+  provider: string,
+  category?: string,
+): GatewayPolicy | undefined {
+  return (
+    gateways.find((g) => g.vendors?.includes(provider)) ??
+    (category ? gateways.find((g) => g.categories?.includes(category)) : undefined)
+  );
+}
+
+/** True when `url` is on the gateway's host */
+export function isGatewayUrl(url: string, gateway: GatewayPolicy): boolean {
+  const host = extractHost(url);
+  return host !== null && host === extractHost(gateway.url);
+}
+
+/** https://api.openai.com/v1/chat → https://api.openai.com */
+function originOf(url: string): string | null {
+  return url.match(/^https?:\/\/[^/?#]+/i)?.[0] ?? null;
+}
+
+/** The variable SDKs conventionally read a custom endpoint from: OPENAI_BASE_URL */
+function baseUrlVar(provider: string): string {
+  return `${provider.toUpperCase().replace(/[^A-Z0-9]+/g, "_")}_BASE_URL`;
+}
+
+export interface GatewayCheckOptions {
+  /** Resolved environment, for clients configured through `<PROVIDER>_BASE_URL` */
+  env?: Record<string, string>;
+  /** Catalog category of a provider, for gateways declared by category */
+  categoryOf?: (provider: string) => string | undefined;
+}
+
+/**
+ * Build a check that flags SDK and API entries reaching a gatewayed vendor
+ * directly. Given the file's source, it attaches remediations to locations in
+ * that file; lines holding a hardcoded secret are left to `use_env_var`.
+ */
+export function createGatewayChecker(
+  gateways: GatewayPolicy[],
+  options: GatewayCheckOptions = {},
+): (entries: DependencyEntry[], source?: string, relPath?: string) => void {
+  const env = options.env ?? {};
+
+  return (entries, source, relPath) => {
+    if (gateways.length === 0) return;
+    const lines = source?.split("\n");
+    for (const entry of entries) {
+      if (entry.kind !== "sdk" && entry.kind !== "api") continue;
+      const provider = entry.provider;
+      if (!provider) continue;
+      const gateway = gatewayFor(gateways, provider, entry.category ?? options.categoryOf?.(provider));
+      if (!gateway) continue;
+
+      const endpoint = entry.kind === "sdk" ? (entry.base_url ?? env[baseUrlVar(provider)]) : (entry.resolved_url ?? entry.url);
+      if (endpoint !== undefined && (!extractHost(endpoint) || isGatewayUrl(endpoint, gateway))) continue;
+
+      entry.gateway_bypass = gateway.name;
+      const severity = gateway.severity ?? "error";
+      if (severity !== "info") entry.severity ??= severity;
+
+      if (!lines || !relPath) continue;
+      const from = endpoint ? originOf(endpoint) : null;
+      const to = gateway.url.replace(/\/+$/, "");
+      const description =
+        entry.kind === "sdk"
+          ? `Point the ${provider} client at the ${gateway.name} gateway (${to})${from ? "" : `: set its base URL or ${baseUrlVar(provider)}`}`
+          : `Call ${provider} through the ${gateway.name} gateway (${to}) instead of directly`;
+      for (const loc of entry.locations) {
+        if (loc.file !== relPath || loc.remediation) continue;
+        const text = lines[loc.line - 1];
+        if (text === undefined || detectHardcodedSecret(text)) continue;
+        const replacement = from && text.includes(from) ? text.split(from).join(to) : null;
+        const remediation: TDMRemediation = {
+          kind: "route_via_gateway",
+          description,
+          gateway_url: to,
+          ...(replacement !== null ? { replacement, diff: lineDiff(relPath, lines, loc.line, replacement) } : {}),
+        };
+        loc.remediation = remediation;
+      }
+    }
+  };
+}
@@ -0,0 +1,74 @@
+/**
+ * @module geoip
+ *
+ * GeoIP databases fetched while an image builds or on a schedule, outside
+ * any application code:
+ *
+ *   RUN geoipupdate -v
+ *   RUN curl -o city.tar.gz "https://download.maxmind.com/app/geoip_download?edition_id=GeoLite2-City&license_key=$KEY&suffix=tar.gz"
+ *   RUN wget "https://ipinfo.io/data/free/country_asn.mmdb?token=$IPINFO_TOKEN"
+ *   EditionIDs GeoLite2-ASN GeoLite2-City    (GeoIP.conf)
+ *
+ * Each download becomes a GET of the database URL with usage
+ * "geoip_database_download" and the license key or token dropped, so the
+ * catalog attributes it to MaxMind or IPinfo. Lookups against the local
+ * database send nothing; the vendors' lookup APIs are detected in code.
+ */
+
+import { basename } from "node:path";
+import type { DependencyEntry } from "./plugin.js";
+
+const MAXMIND_DOWNLOAD = "https://download.maxmind.com/geoip/databases";
+
+/** geoipupdate's config file, which names the editions it downloads */
+export function isGeoipConfig(path: string): boolean {
+  return basename(path) === "GeoIP.conf";
+}
+
+/** Database URLs a line downloads, credentials removed */
+function downloadUrls(line: string): string[] {
+  // https://download.maxmind.com/app/geoip_download?edition_id=GeoLite2-City&...
+  const legacy = line.match(/https:\/\/download\.maxmind\.com\/app\/geoip_download\?[^\s"']*\bedition_id=([\w-]+)/);
+  if (legacy) return [`${MAXMIND_DOWNLOAD}/${legacy[1]!}/download`];
+  // https://download.maxmind.com/geoip/databases/GeoLite2-City/download?suffix=tar.gz
+  const current = line.match(/https:\/\/download\.maxmind\.com\/geoip\/databases\/([\w-]+)\/download/);
+  if (current) return [`${MAXMIND_DOWNLOAD}/${current[1]!}/download`];
+  // https://ipinfo.io/data/free/country_asn.mmdb?token=...
+  const ipinfo = line.match(/https:\/\/ipinfo\.io\/data\/([\w/.-]+)/);
+  if (ipinfo) return [`https://ipinfo.io/data/${ipinfo[1]!}`];
+  // geoipupdate reads its editions from GeoIP.conf
+  if (/(?:^|[\s;&|])geoipupdate\b/.test(line)) return ["https://updates.maxmind.com"];
+  return [];
+}
+
+/** Find GeoIP database downloads in one Dockerfile, shell script, or GeoIP.conf */
+export function detectGeoipDownloads(source: string, relPath: string): DependencyEntry[] {
+  const entries: DependencyEntry[] = [];
+  const lines = source.split("\n");
+  const conf = isGeoipConfig(relPath);
+
+  for (let i = 0; i < lines.length; i++) {
+    const trimmed = lines[i]!.trim();
+    if (!trimmed || trimmed.startsWith("#")) continue;
+    const editions = conf ? trimmed.match(/^EditionIDs?\s+(.+)$/) : null;
+    const urls = editions
+      ? editions[1]!.split(/\s+/).map((edition) => `${MAXMIND_DOWNLOAD}/${edition}/download`)
+      : downloadUrls(trimmed);
+    for (const url of urls) {
+      entries.push({
+        kind: "api",
+        url,
+        method: "GET",
+        locations: [{
+          file: relPath,
+          line: i + 1,
+          context: trimmed.replace(/\b(license_key|token)=[^&\s"']+/g, "$1=[REDACTED]").slice(0, 200),
+          usage: "geoip_database_download",
+        }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+  }
+  return entries;
+}
@@ -0,0 +1,209 @@
+/**
+ * @module impact
+ *
+ * Offboarding impact behind `thirdwatch impact --vendor twilio`: every
+ * repository, file, and call site across the inventory that depends on one
+ * vendor, and an estimate of what replacing it would take. Call sites are
+ * counted per location, so a file that sends SMS from three places is three
+ * sites to migrate. Usage is broken down by finding kind (SDK, API call,
+ * webhook, infrastructure) and, for SDKs, by intent — reads and writes
+ * usually port mechanically, while payments, webhooks, and message sends
+ * carry vendor-specific semantics and need design work.
+ *
+ * The scope estimate is deliberately coarse:
+ *
+ *   small   — at most 10 call sites in at most 2 repositories
+ *   large   — more than 100 call sites, more than 10 repositories, or
+ *             webhook handlers in more than 2 repositories
+ *   medium  — everything in between
+ *
+ * Same-category vendors the inventory already uses are listed as candidate
+ * replacements, since consolidating onto one of them avoids a new contract.
+ */
+
+import type { TDM } from "@thirdwatch/tdm";
+import type { ServiceInventory } from "./concentration.js";
+import type { SDKRegistryEntry } from "./registry.js";
+import { extractHost } from "./first-party.js";
+import { collectVendorUsages } from "./sla.js";
+import { createVendorMatcher } from "./runtime.js";
+import { normalizeVendorName, vendorNameIndex } from "./aliases.js";
+
+export type ImpactKind = "sdk" | "api" | "webhook" | "infrastructure";
+
+export type MigrationScope = "small" | "medium" | "large";
+
+export interface ImpactCallSite {
+  file: string;
+  line: number;
+  kind: ImpactKind;
+  /** Usage kind from the scan, e.g. "method_call:client.messages.create" */
+  usage?: string;
+  context?: string;
+}
+
+export interface ImpactRepository {
+  repository: string;
+  call_sites: number;
+  /** Files with at least one call site, sorted */
+  files: string[];
+  /** Call sites by finding kind */
+  kinds: Partial<Record<ImpactKind, number>>;
+  /** SDK packages pulling in the vendor, e.g. ["twilio"] */
+  sdk_packages: string[];
+  /** API URLs and infrastructure hosts reached, including runtime-only observations */
+  endpoints: string[];
+  sites: ImpactCallSite[];
+}
+
+export interface ImpactReport {
+  vendor: string;
+  display_name: string;
+  category?: string;
+  /** Most call sites first */
+  repositories: ImpactRepository[];
+  total_repositories: number;
+  total_files: number;
+  total_call_sites: number;
+  /** Call sites by finding kind across the inventory */
+  kinds: Partial<Record<ImpactKind, number>>;
+  /** SDK call sites by intent, most common first */
+  intents: Array<{ intent: string; call_sites: number }>;
+  /** Same-category vendors the inventory already uses, by number of repositories */
+  alternatives: Array<{ vendor: string; repositories: number }>;
+  scope: MigrationScope;
+}
+
+function scopeOf(callSites: number, repositories: number, webhookRepos: number): MigrationScope {
+  if (callSites > 100 || repositories > 10 || webhookRepos > 2) return "large";
+  if (callSites <= 10 && repositories <= 2) return "small";
+  return "medium";
+}
+
+/** The catalog slug for `name`, matched on slug, display name, or alias; `name` itself when none matches */
+export function resolveVendorSlug(name: string, registry: SDKRegistryEntry[]): string {
+  return vendorNameIndex(registry).get(normalizeVendorName(name)) ?? name.toLowerCase();
+}
+
+function repositoryImpact(
+  repository: string,
+  tdms: TDM[],
+  vendor: string,
+  matchVendor: (host: string) => string | null,
+  intents: Map<string, number>,
+): ImpactRepository | null {
+  const sites = new Map<string, ImpactCallSite>();
+  const kinds: Partial<Record<ImpactKind, number>> = {};
+  const packages = new Set<string>();
+  const endpoints = new Set<string>();
+  let found = false;
+
+  const add = (kind: ImpactKind, locations: TDM["sdks"][number]["locations"]) => {
+    found = true;
+    for (const loc of locations) {
+      const key = `${loc.file}:${loc.line}`;
+      if (sites.has(key)) continue;
+      sites.set(key, {
+        file: loc.file,
+        line: loc.line,
+        kind,
+        ...(loc.usage ? { usage: loc.usage } : {}),
+        ...(loc.context ? { context: loc.context } : {}),
+      });
+      kinds[kind] = (kinds[kind] ?? 0) + 1;
+    }
+  };
+
+  // The provider's canonical slug (TDMs may use an alias), else the host's vendor
+  const vendorOf = (provider: string | null | undefined, host?: string | null) =>
+    provider ? (matchVendor(provider) ?? provider) : host ? matchVendor(host) : null;
+  for (const tdm of tdms) {
+    for (const sdk of tdm.sdks) {
+      if (vendorOf(sdk.provider) !== vendor) continue;
+      packages.add(sdk.sdk_package);
+      add("sdk", sdk.locations);
+      for (const intent of sdk.intents ?? []) intents.set(intent, (intents.get(intent) ?? 0) + sdk.locations.length);
+    }
+    for (const api of tdm.apis) {
+      if (api.first_party) continue;
+      const host = extractHost(api.resolved_url ?? api.url);
+      if (vendorOf(api.provider, host) !== vendor) continue;
+      endpoints.add(api.resolved_url ?? api.url);
+      add("api", api.locations);
+    }
+    for (const wh of tdm.webhooks) {
+      if (wh.first_party || vendorOf(wh.provider) !== vendor) continue;
+      add("webhook", wh.locations);
+    }
+    for (const infra of tdm.infrastructure) {
+      if (infra.first_party) continue;
+      const host = infra.resolved_host ?? undefined;
+      if (vendorOf(infra.provider, host) !== vendor) continue;
+      endpoints.add(host ?? infra.connection_ref);
+      add("infrastructure", infra.locations);
+    }
+  }
+  if (!found) return null;
+
+  const list = [...sites.values()].sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+  return {
+    repository,
+    call_sites: list.length,
+    files: [...new Set(list.map((s) => s.file))],
+    kinds,
+    sdk_packages: [...packages].sort(),
+    endpoints: [...endpoints].sort(),
+    sites: list,
+  };
+}
+
+/** Where and how much the inventory depends on `vendor` (a catalog slug) */
+export function computeImpact(
+  inventory: ServiceInventory[],
+  vendor: string,
+  registry: SDKRegistryEntry[],
+): ImpactReport {
+  const entry = registry.find((e) => e.provider === vendor);
+  const matchVendor = createVendorMatcher(registry);
+  const intents = new Map<string, number>();
+
+  const repositories = inventory
+    .map(({ service, tdms }) => repositoryImpact(service, tdms, vendor, matchVendor, intents))
+    .filter((r): r is ImpactRepository => r !== null)
+    .sort((a, b) => b.call_sites - a.call_sites || a.repository.localeCompare(b.repository));
+
+  const kinds: Partial<Record<ImpactKind, number>> = {};
+  for (const repo of repositories) {
+    for (const [kind, n] of Object.entries(repo.kinds) as Array<[ImpactKind, number]>) kinds[kind] = (kinds[kind] ?? 0) + n;
+  }
+
+  const alternatives = new Map<string, Set<string>>();
+  if (entry?.category) {
+    const categories = new Map(registry.map((e) => [e.provider, e.category]));
+    for (const { service, tdms } of inventory) {
+      for (const usage of tdms.flatMap((tdm) => collectVendorUsages(tdm, matchVendor))) {
+        if (usage.vendor === vendor || categories.get(usage.vendor) !== entry.category) continue;
+        alternatives.set(usage.vendor, (alternatives.get(usage.vendor) ?? new Set()).add(service));
+      }
+    }
+  }
+
+  const totalCallSites = repositories.reduce((n, r) => n + r.call_sites, 0);
+  return {
+    vendor,
+    display_name: entry?.display_name ?? vendor,
+    ...(entry?.category ? { category: entry.category } : {}),
+    repositories,
+    total_repositories: repositories.length,
+    total_files: repositories.reduce((n, r) => n + r.files.length, 0),
+    total_call_sites: totalCallSites,
+    kinds,
+    intents: [...intents]
+      .map(([intent, call_sites]) => ({ intent, call_sites }))
+      .sort((a, b) => b.call_sites - a.call_sites || a.intent.localeCompare(b.intent)),
+    alternatives: [...alternatives]
+      .map(([v, services]) => ({ vendor: v, repositories: services.size }))
+      .sort((a, b) => b.repositories - a.repositories || a.vendor.localeCompare(b.vendor)),
+    scope: scopeOf(totalCallSites, repositories.length, repositories.filter((r) => r.kinds.webhook).length),
+  };
+}
@@ -17,8 +17,8 @@ export type { ThirdwatchConfig } from "./config.js";
 
 export { resolveUrl, loadEnvFile, buildEnvMap } from "./resolve.js";
 
-export { loadSDKRegistry, buildPackageProviderMap, buildUrlProviderMap, buildConstructorProviderMap, buildFactoryProviderMap, buildRegistryMaps } from "./registry.js";
-export type { SDKRegistryEntry, SDKPatternEntry, ConstructorPattern, RegistryMaps } from "./registry.js";
+export { VENDOR_CATEGORIES, COMPLIANCE_URL_KEYS, complianceLinks, loadSDKRegistry, buildPackageProviderMap, buildUrlProviderMap, buildConstructorProviderMap, buildFactoryProviderMap, buildRegistryMaps } from "./registry.js";
+export type { SDKRegistryEntry, SDKPatternEntry, ConstructorPattern, RegistryMaps, VendorCategory, CatalogExample, VendorSla, VendorRateLimit, ComplianceLinks } from "./registry.js";
 
 export { inferProvider } from "./registry-inference.js";
 export { detectByConvention } from "./registry-conventions.js";
@@ -27,3 +27,164 @@ export { scoreConfidence } from "./confidence.js";
 export type { DetectionMethod } from "./confidence.js";
 
 export { mergeManifestAndLockfile } from "./lockfile.js";
+
+export { detectHardcodedSecret, redactSecrets, annotateSecrets, resolveSecretAges } from "./secrets.js";
+export type { DetectedSecret, PendingSecret } from "./secrets.js";
+
+export { classifyFirstParty, extractHost, matchesDomain, domainPatternRegex, isFirstPartyHost } from "./first-party.js";
+export type { FirstPartyMode } from "./first-party.js";
+export { applyCatalogCategories } from "./categories.js";
+export { classifyUsageIntents, USAGE_INTENTS } from "./intent.js";
+export type { UsageIntent } from "./intent.js";
+export { flagUnusedIntegrations } from "./unused.js";
+export { createCompatibleEndpointResolver } from "./compatible-endpoints.js";
+export { createGatewayChecker, gatewayFor, isGatewayUrl } from "./gateways.js";
+export type { GatewayPolicy, GatewayCheckOptions } from "./gateways.js";
+export { labelDataFlows, DATA_CLASSIFICATIONS } from "./data-flow.js";
+export type { DataAnnotation } from "./data-flow.js";
+export { detectOidcIssuers, OIDC_CONFIG_EXTENSIONS } from "./oidc.js";
+export { detectMapsKeys } from "./maps.js";
+export { detectConfigEndpoints } from "./config-endpoints.js";
+export { environmentOf, annotateEnvironments, vendorsByEnvironment } from "./environments.js";
+export type { EnvironmentVendors } from "./environments.js";
+export { fingerprintFindings, disambiguateFingerprints, findingIdentity, enclosingSymbol, diffFindings } from "./fingerprint.js";
+export type { FindingRef } from "./fingerprint.js";
+export { suggestRemediations, lineDiff, DEFAULT_TIMEOUT_SECONDS } from "./remediation.js";
+export { openScanTarget, extractTar, extractZip, saveImage, isArchivePath, isImageReference, IMAGE_IGNORE, IMAGE_ENV_FILE } from "./artifact.js";
+export type { ScanTarget, ExtractLimits } from "./artifact.js";
+export { parseSbom, parsePurl, buildSbomTDM } from "./sbom.js";
+export type { ParsedSbom, SbomComponent, SbomFormat, SbomTDMOptions } from "./sbom.js";
+export { detectModelDownloads, isBuildScript } from "./model-hub.js";
+export { detectGeoipDownloads, isGeoipConfig } from "./geoip.js";
+export { detectTerraformBackends, isTerraformConfig } from "./terraform.js";
+export { detectFrontendAssets, isFrontendAsset, FRONTEND_EXTENSIONS } from "./frontend.js";
+
+export {
+  updateCatalog,
+  resolveCatalog,
+  loadCatalogBundle,
+  parseCatalogBundle,
+  verifyCatalogSignature,
+  currentCatalogVersion,
+  catalogCacheDir,
+  DEFAULT_CATALOG_URL,
+} from "./catalog.js";
+export type { CatalogBundle, CatalogSelection } from "./catalog.js";
+
+export { validateCatalogEntry, checkCatalogExamples, validateCatalogFiles } from "./catalog-validate.js";
+export type { CatalogValidationResult } from "./catalog-validate.js";
+
+export { compileRules, applyCustomRules } from "./custom-rules.js";
+export type { CustomRule } from "./custom-rules.js";
+export { ruleIdFor, assignRuleIds, ruleSettingFor, applyRuleSettings } from "./rule-ids.js";
+export type { RuleSetting } from "./rule-ids.js";
+
+export { createVendorMatcher, aggregateObservations, buildRuntimeTDM, observationUrl, normalizePath, isPrivateIp, readLogLines } from "./runtime.js";
+export type { RuntimeObservation, VendorMatcher, RuntimeTDMContext } from "./runtime.js";
+
+export { runEgressAgent, buildAgentScript, parseAgentLine, AgentCorrelator } from "./agent.js";
+export type { AgentEvent, AgentScriptOptions, AgentCorrelatorOptions, EgressAgentOptions } from "./agent.js";
+
+export { PodDirectory, podUidFromCgroup, workloadOf, inClusterPodLister, NODE_NAMESPACE } from "./k8s.js";
+export type { KubePod, PodLister, PodDirectoryOptions } from "./k8s.js";
+
+export { buildUsageReports, sendUsageReport, USAGE_PATH } from "./usage-report.js";
+export type { UsageReport, UsageCall, UsageReportOptions } from "./usage-report.js";
+
+export { EgressMeter, sendAlertWebhook, startMetricsServer } from "./metering.js";
+export type { EgressAlert, EgressAlertKind, EgressMeterOptions, MeterState, AlertWebhookSettings, MetricsServer } from "./metering.js";
+
+export { computeDrift } from "./drift.js";
+export type { DriftReport, DriftVendor, DriftSdk, DriftUnknownHost } from "./drift.js";
+
+export { computeSlaReport, collectVendorUsages } from "./sla.js";
+export type { SlaReport, SlaDependency, SlaReportOptions, VendorUsage } from "./sla.js";
+export { buildQuotaReport, DEFAULT_QUOTA_THRESHOLD } from "./quota.js";
+export type { QuotaReport, QuotaUsage, QuotaStatus, QuotaReportOptions } from "./quota.js";
+
+export { computeConcentration, groupByService } from "./concentration.js";
+export type { ConcentrationReport, ConcentrationOptions, VendorConcentration, CategoryConcentration, CriticalityTier, ServiceInventory } from "./concentration.js";
+
+export { buildOrgReport } from "./org-report.js";
+export type { OrgReport, OrgReportOptions, OrgVendorRow, OrgCategoryRow } from "./org-report.js";
+export { explainVendor } from "./explain.js";
+export { buildCurrencyReport, fetchLatestVersion, versionsBehind, parseVersion } from "./currency.js";
+export type { CurrencyReport, CurrencyPolicy, CurrencyViolation, SdkCurrency } from "./currency.js";
+export type { VendorExplanation } from "./explain.js";
+
+export { startRecordingProxy, parseConnectTarget } from "./proxy.js";
+export type { RecordingProxy, RecordingProxyOptions } from "./proxy.js";
+
+export { parseDnsLogLine, detectDnsLogFormat, normalizeQueryName, DNS_LOG_FORMATS } from "./dns-logs.js";
+export type { DnsLogFormat } from "./dns-logs.js";
+
+export { createFlowLogParser, detectFlowLogFormat, FLOW_LOG_FORMATS } from "./flow-logs.js";
+export type { FlowLogFormat, FlowLogParser, FlowLogParserOptions } from "./flow-logs.js";
+
+export { takeSnapshot, outboundObservations, parseProcNetTcp, parseNetstat, parseLsof, parseConntrack } from "./snapshot.js";
+export type { Snapshot, SnapshotOptions, SocketEntry, OutboundOptions } from "./snapshot.js";
+
+export { spanObservations, startOtlpReceiver } from "./otel.js";
+export type { OtlpReceiver, OtlpReceiverOptions } from "./otel.js";
+
+export { createIpRangeMatcher, parseIp, parseCidr } from "./ip-ranges.js";
+export type { IpRangeMatcher, ParsedIp, ParsedCidr } from "./ip-ranges.js";
+
+export { startExecDetector, sanitizeDetectorEntries, DETECTOR_PROTOCOL_VERSION } from "./detectors.js";
+export type { DetectorConfig, ExecDetector } from "./detectors.js";
+
+export { classifyUnmatchedWithLLM, collectUnmatched, parseSuggestions, applySuggestions, vendorSlug, isAllowedLLMEndpoint } from "./llm-classify.js";
+export type { LLMClassificationConfig, ClassificationItem, VendorSuggestion } from "./llm-classify.js";
+
+export { enrichHost, enrichUnknownApis, catalogHosts, registrableDomain, parseRdapDomain, parseCymruTxt } from "./enrich.js";
+
+export { parseCron, nextRun, loadServerConfig, repositoryName, cloneRepository } from "./schedule.js";
+export type { CronSchedule, ScheduledRepository, ServerConfig } from "./schedule.js";
+
+export { computeImpact, resolveVendorSlug } from "./impact.js";
+export type { ImpactReport, ImpactRepository, ImpactCallSite, ImpactKind, MigrationScope } from "./impact.js";
+
+export { summarizeRepository, badgeFor, renderBadge } from "./badge.js";
+export type { RepositorySummary, PolicyStatus, Badge, BadgeMetric } from "./badge.js";
+
+export { buildVendorPolicy, renderTerraformVars, buildOpaBundle, opaData, REGO_POLICY } from "./vendor-policy.js";
+export type { VendorPolicy, VendorEgress, PolicyFormat } from "./vendor-policy.js";
+
+export { compareTDMs, hasChanges, renderComparisonMarkdown } from "./compare.js";
+export type {
+  ComparisonReport,
+  CompareSide,
+  VendorDelta,
+  PackageDelta,
+  PackageChange,
+  SdkDelta,
+  SdkChange,
+  EndpointDelta,
+  EndpointChange,
+  ConnectionDelta,
+} from "./compare.js";
+
+export { inferCredentialScope, narrowUsage, overlyBroadReason } from "./credential-scope.js";
+export type { CredentialScope } from "./credential-scope.js";
+export { createUserAgentMatcher } from "./user-agents.js";
+export type { UserAgentMatch, UserAgentMatcher } from "./user-agents.js";
+export { serviceOf, annotateServices, splitByService, vendorsByService } from "./services.js";
+export type { ServiceMap, ServiceVendors } from "./services.js";
+export { buildEnrichmentDataset, loadEnrichmentDataset } from "./enrich.js";
+export type { EnrichmentDataset, EnrichOptions } from "./enrich.js";
+export { importCatalogBundle } from "./catalog.js";
+export { isOffline, assertOnline } from "./offline.js";
+export { attachSnippets, MAX_CONTEXT_LINES } from "./snippets.js";
+export {
+  normalizeVendorName,
+  isHostAlias,
+  applyVendorAliases,
+  vendorNameIndex,
+  canonicalizeProviders,
+  loadVendorAliases,
+} from "./aliases.js";
+export type { VendorAliases } from "./aliases.js";
+export { csvCell, formatCsv } from "./csv.js";
+export { diffVendors, vendorFeedRequest } from "./vendor-feed.js";
+export type { VendorChange, VendorFeedRequest } from "./vendor-feed.js";
+export { signPayload } from "./webhook-signature.js";
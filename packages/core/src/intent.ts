@@ -0,0 +1,140 @@
+/**
+ * @module intent
+ *
+ * What each SDK integration does, from the methods the file calls on it, so
+ * reports show the direction of data and not just that a vendor is present:
+ *
+ *   s3.GetObject(...)                       → data_read
+ *   s3_client.put_object(Bucket=...)        → data_write
+ *   stripe.PaymentIntent.create(...)        → payment
+ *   client.chat.completions.create(...)     → inference
+ *   openai.embeddings.create(...)           → embedding
+ *   twilio.messages.create(to=...)          → message_send
+ *   sentry_sdk.capture_exception(err)       → telemetry_export
+ *
+ * Method names are only meaningful for the vendor's kind — messages.create
+ * sends an SMS through Twilio but runs inference on Anthropic — so each rule
+ * applies to the catalog categories it was written for. Every SDK entry in a
+ * file gets the intents of the calls made in that file; an SDK whose calls
+ * match no rule gets none.
+ */
+
+import type { DependencyEntry } from "./plugin.js";
+
+/** Intents an SDK entry can carry, in report order */
+export const USAGE_INTENTS = [
+  "data_read",
+  "data_write",
+  "data_delete",
+  "payment",
+  "refund",
+  "inference",
+  "embedding",
+  "message_send",
+  "telemetry_export",
+] as const;
+
+export type UsageIntent = (typeof USAGE_INTENTS)[number];
+
+const DATA_CATEGORIES = ["storage", "database", "data-warehouse"];
+
+const INTENT_RULES: Array<{ intent: UsageIntent; categories: string[]; pattern: RegExp }> = [
+  {
+    intent: "data_read",
+    categories: DATA_CATEGORIES,
+    pattern:
+      /\b(?:GetObject|getObject|get_object|HeadObject|head_object|ListObjects(?:V2)?|list_objects(?:_v2)?|download_file(?:obj)?|download_blob|download_to_filename|download_as_(?:bytes|text)|DownloadFile|DownloadStream|NewReader|createReadStream|GetItem|get_item|BatchGetItem|batch_get_item|Query|Scan|findOne|find_one)\s*\(/,
+  },
+  {
+    intent: "data_write",
+    categories: DATA_CATEGORIES,
+    pattern:
+      /\b(?:PutObject|putObject|put_object|upload_file(?:obj)?|upload_blob|upload_from_(?:filename|string|file)|UploadFile|UploadStream|CreateMultipartUpload|create_multipart_upload|CopyObject|copy_object|NewWriter|createWriteStream|PutItem|put_item|UpdateItem|update_item|BatchWriteItem|batch_write_item|InsertOne|InsertMany|insertOne|insertMany|insert_one|insert_many|bulkWrite)\s*\(/,
+  },
+  {
+    intent: "data_delete",
+    categories: DATA_CATEGORIES,
+    pattern:
+      /\b(?:DeleteObjects?|deleteObjects?|delete_objects?|delete_blob|DeleteItem|delete_item|DeleteOne|DeleteMany|deleteOne|deleteMany|delete_one|delete_many)\s*\(/,
+  },
+  {
+    intent: "payment",
+    categories: ["payments", "billing", "commerce"],
+    pattern:
+      /\b(?:payment_?intents?|charges?|subscriptions?|invoices?|checkout\.sessions?)\.(?:create|new|confirm|capture|pay)\s*\(/i,
+  },
+  {
+    intent: "refund",
+    categories: ["payments", "billing", "commerce"],
+    pattern: /\brefunds?\.(?:create|new)\s*\(/i,
+  },
+  {
+    intent: "inference",
+    categories: ["ai"],
+    pattern:
+      /\b(?:chat\.completions\.create|ChatCompletion\.create|completions\.create|messages\.(?:create|stream)|Messages\.New|CreateChatCompletion(?:Stream)?|CreateCompletion|generate_?content|GenerateContent|invoke_model|InvokeModel|Converse(?:Stream)?|responses\.create)\s*\(/i,
+  },
+  {
+    intent: "embedding",
+    categories: ["ai"],
+    pattern: /\b(?:embeddings\.create|Embedding\.create|CreateEmbeddings?|embed_?content|EmbedContent|embed_documents|embed_query)\s*\(/i,
+  },
+  {
+    intent: "message_send",
+    categories: ["messaging", "email", "push", "communication"],
+    pattern:
+      /\b(?:messages\.create|Messages\.Create|Api\.CreateMessage|send_?(?:mail|email|message|multicast|each)?|Send(?:Email|RawEmail|Message|Multicast|Each)?|publish)\s*\(/i,
+  },
+  {
+    intent: "telemetry_export",
+    categories: ["error-tracking", "analytics", "observability"],
+    pattern:
+      /\b(?:capture_?(?:exception|message|event)|CaptureException|CaptureMessage|CaptureEvent|notify|track|identify|Enqueue|capture|log_event|logEvent|increment|Incr|gauge|Gauge|histogram|Histogram|distribution|Distribution|StartSpan|start_span|startSpan|start_as_current_span)\s*\(/,
+  },
+];
+
+// Where AWS SDK entries are split by service, the service names the kind
+const SERVICE_CATEGORIES: Record<string, string> = {
+  s3: "storage",
+  dynamodb: "database",
+  ses: "email",
+  sesv2: "email",
+  sns: "messaging",
+  bedrock: "ai",
+  bedrockruntime: "ai",
+};
+
+/** Strip comments so commented-out calls don't count */
+function codeLines(source: string): string {
+  return source
+    .split("\n")
+    .filter((line) => !/^\s*(?:\/\/|#|\*|\/\*|--)/.test(line))
+    .join("\n");
+}
+
+/**
+ * Add `intents` to the SDK entries found in one file, from the calls the file
+ * makes. `categoryOf` gives the catalog category for a provider slug.
+ */
+export function classifyUsageIntents(
+  entries: DependencyEntry[],
+  source: string,
+  categoryOf: (provider: string) => string | undefined,
+): void {
+  const sdks = entries.filter((e): e is DependencyEntry & { kind: "sdk" } => e.kind === "sdk");
+  if (sdks.length === 0) return;
+  const code = codeLines(source);
+
+  for (const sdk of sdks) {
+    const categories = new Set(
+      [sdk.category ?? categoryOf(sdk.provider), ...(sdk.services_used ?? []).map((s) => SERVICE_CATEGORIES[s])].filter(
+        (c): c is string => !!c,
+      ),
+    );
+    const intents = new Set(sdk.intents ?? []);
+    for (const rule of INTENT_RULES) {
+      if (rule.categories.some((c) => categories.has(c)) && rule.pattern.test(code)) intents.add(rule.intent);
+    }
+    if (intents.size > 0) sdk.intents = USAGE_INTENTS.filter((i) => intents.has(i));
+  }
+}
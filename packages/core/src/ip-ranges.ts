@@ -0,0 +1,153 @@
+/**
+ * @module ip-ranges
+ *
+ * IP address → vendor attribution from the catalog's `ip_ranges` (the CIDR
+ * blocks vendors publish for their APIs and networks). Used by runtime
+ * sources that only see addresses — flow logs, firewalls, and agent
+ * connections nobody resolved a name for.
+ *
+ * The most specific prefix wins, so a vendor that publishes its own /32s
+ * inside a cloud provider's /11 is attributed to the vendor, not the cloud.
+ */
+
+import type { SDKRegistryEntry } from "./registry.js";
+
+export interface ParsedIp {
+  version: 4 | 6;
+  value: bigint;
+}
+
+export interface ParsedCidr extends ParsedIp {
+  bits: number;
+}
+
+function parseIpv4(s: string): bigint | null {
+  const parts = s.split(".");
+  if (parts.length !== 4) return null;
+  let value = 0n;
+  for (const p of parts) {
+    if (!/^\d{1,3}$/.test(p) || Number(p) > 255) return null;
+    value = (value << 8n) | BigInt(p);
+  }
+  return value;
+}
+
+function parseIpv6(s: string): bigint | null {
+  let addr = s.toLowerCase();
+  // Embedded IPv4 tail, e.g. ::ffff:10.0.0.1
+  const v4 = addr.match(/^(.*:)(\d+\.\d+\.\d+\.\d+)$/);
+  if (v4) {
+    const tail = parseIpv4(v4[2]!);
+    if (tail === null) return null;
+    addr = `${v4[1]}${(tail >> 16n).toString(16)}:${(tail & 0xffffn).toString(16)}`;
+  }
+  const halves = addr.split("::");
+  if (halves.length > 2) return null;
+  const head = halves[0] ? halves[0].split(":") : [];
+  const tail = halves.length === 2 && halves[1] ? halves[1].split(":") : [];
+  const missing = 8 - head.length - tail.length;
+  if (halves.length === 1 ? missing !== 0 : missing < 1) return null;
+  const groups = [...head, ...Array<string>(missing).fill("0"), ...tail];
+  let value = 0n;
+  for (const g of groups) {
+    if (!/^[0-9a-f]{1,4}$/.test(g)) return null;
+    value = (value << 16n) | BigInt(parseInt(g, 16));
+  }
+  return value;
+}
+
+/** Parse an IPv4 or IPv6 address (brackets and zone IDs are stripped) */
+export function parseIp(ip: string): ParsedIp | null {
+  const s = ip.trim().replace(/^\[|\]$/g, "").replace(/%.*$/, "");
+  if (s.includes(":")) {
+    const value = parseIpv6(s);
+    return value === null ? null : { version: 6, value };
+  }
+  const value = parseIpv4(s);
+  return value === null ? null : { version: 4, value };
+}
+
+/** Loopback, link-local, and RFC 1918 / ULA addresses — never third-party egress */
+export function isPrivateIp(ip: string): boolean {
+  const v4 = ip.match(/^(\d+)\.(\d+)\.\d+\.\d+$/);
+  if (v4) {
+    const a = Number(v4[1]);
+    const b = Number(v4[2]);
+    return (
+      a === 10 ||
+      a === 127 ||
+      a === 0 ||
+      (a === 169 && b === 254) ||
+      (a === 172 && b >= 16 && b <= 31) ||
+      (a === 192 && b === 168) ||
+      (a === 100 && b >= 64 && b <= 127)
+    );
+  }
+  const v6 = ip.toLowerCase();
+  if (v6.startsWith("::ffff:")) return isPrivateIp(v6.slice(7));
+  return v6 === "::1" || v6 === "::" || /^f[cd]/.test(v6) || /^fe[89ab]/.test(v6);
+}
+
+/** Parse "a.b.c.d/n" or "x::/n"; a bare address is a host route */
+export function parseCidr(cidr: string): ParsedCidr | null {
+  const [addr, len, extra] = cidr.trim().split("/");
+  if (addr === undefined || extra !== undefined) return null;
+  const ip = parseIp(addr);
+  if (!ip) return null;
+  const max = ip.version === 4 ? 32 : 128;
+  if (len !== undefined && !/^\d{1,3}$/.test(len)) return null;
+  const bits = len === undefined ? max : Number(len);
+  if (bits > max) return null;
+  const shift = BigInt(max - bits);
+  return { ...ip, value: (ip.value >> shift) << shift, bits };
+}
+
+export type IpRangeMatcher = (ip: string) => string | null;
+
+/** Build an address → provider matcher from every entry's ip_ranges */
+export function createIpRangeMatcher(registry: SDKRegistryEntry[]): IpRangeMatcher {
+  // version → prefix length → network → provider, longest lengths first
+  const tables = new Map<4 | 6, Map<number, Map<bigint, string>>>([[4, new Map()], [6, new Map()]]);
+  for (const entry of registry) {
+    for (const range of entry.ip_ranges ?? []) {
+      const cidr = parseCidr(range);
+      if (!cidr) continue;
+      const byLength = tables.get(cidr.version)!;
+      let networks = byLength.get(cidr.bits);
+      if (!networks) {
+        networks = new Map();
+        byLength.set(cidr.bits, networks);
+      }
+      if (!networks.has(cidr.value)) networks.set(cidr.value, entry.provider);
+    }
+  }
+  const lengths = new Map<4 | 6, number[]>(
+    [...tables].map(([v, t]) => [v, [...t.keys()].sort((a, b) => b - a)]),
+  );
+
+  const cache = new Map<string, string | null>();
+  return (ip: string) => {
+    const cached = cache.get(ip);
+    if (cached !== undefined) return cached;
+    let parsed = parseIp(ip);
+    // IPv4-mapped IPv6 (::ffff:a.b.c.d) is matched as IPv4
+    if (parsed?.version === 6 && parsed.value >> 32n === 0xffffn) {
+      parsed = { version: 4, value: parsed.value & 0xffffffffn };
+    }
+    let provider: string | null = null;
+    if (parsed) {
+      const max = parsed.version === 4 ? 32 : 128;
+      const byLength = tables.get(parsed.version)!;
+      for (const bits of lengths.get(parsed.version)!) {
+        const shift = BigInt(max - bits);
+        const hit = byLength.get(bits)!.get((parsed.value >> shift) << shift);
+        if (hit) {
+          provider = hit;
+          break;
+        }
+      }
+    }
+    cache.set(ip, provider);
+    return provider;
+  };
+}
@@ -0,0 +1,177 @@
+/**
+ * @module k8s
+ *
+ * Pod attribution for the agent when it runs as a Kubernetes DaemonSet
+ * (`thirdwatch agent --kubernetes`). A connection's pid is mapped to its pod
+ * through the pod UID in /proc/<pid>/cgroup, and the UID to a namespace and
+ * workload through the API server's pod list for this node. Connections are
+ * then labelled "<namespace>/<workload>", where the workload is the owning
+ * Deployment, StatefulSet, DaemonSet, or Job rather than the pod, so
+ * rollups survive restarts.
+ */
+
+import { readFileSync } from "node:fs";
+import { request } from "node:https";
+import { join } from "node:path";
+
+/** The parts of a Pod the agent reads */
+export interface KubePod {
+  metadata: {
+    uid: string;
+    name: string;
+    namespace: string;
+    labels?: Record<string, string>;
+    ownerReferences?: Array<{ kind: string; name: string; controller?: boolean }>;
+  };
+}
+
+export type PodLister = () => Promise<KubePod[]>;
+
+/**
+ * Client label for host processes outside any pod. Namespace names cannot
+ * start with "_", so this never collides with a real namespace.
+ */
+export const NODE_NAMESPACE = "_node";
+
+// Both cgroup drivers embed the pod UID: ".../pod<uid>/<container>" (cgroupfs)
+// and "...-pod<uid with _>.slice/..." (systemd)
+const POD_UID_RE = /pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})/;
+
+/** Pod UID from the contents of /proc/<pid>/cgroup, or null for host processes */
+export function podUidFromCgroup(text: string): string | null {
+  const match = POD_UID_RE.exec(text);
+  return match ? match[1]!.replace(/_/g, "-") : null;
+}
+
+/** Name of the controller that owns a pod, falling back to the pod name */
+export function workloadOf(pod: KubePod): string {
+  const { name, labels, ownerReferences } = pod.metadata;
+  const owner = ownerReferences?.find((o) => o.controller) ?? ownerReferences?.[0];
+  if (!owner) return name;
+  if (owner.kind === "ReplicaSet") {
+    // Deployment-managed ReplicaSets are "<deployment>-<pod-template-hash>"
+    const hash = labels?.["pod-template-hash"];
+    if (hash && owner.name.endsWith(`-${hash}`)) return owner.name.slice(0, -hash.length - 1);
+  }
+  return owner.name;
+}
+
+export interface PodDirectoryOptions {
+  list: PodLister;
+  /** Host /proc, mounted into the agent container (default: "/proc") */
+  procRoot?: string;
+  /** Minimum time between pod list refreshes (default: 10s) */
+  refreshIntervalMs?: number;
+}
+
+const MAX_CACHED_PIDS = 50_000;
+
+/**
+ * Maps pids to workloads. `clientFor` is synchronous so it can run in the
+ * agent's event path: a pid in a pod the directory hasn't listed yet gets a
+ * provisional "uid:<uid>" label that `resolve` rewrites before a window is
+ * reported.
+ */
+export class PodDirectory {
+  private readonly list: PodLister;
+  private readonly procRoot: string;
+  private readonly refreshIntervalMs: number;
+  private readonly uidByPid = new Map<number, string | null>();
+  private readonly workloads = new Map<string, string>();
+  private lastRefresh = 0;
+
+  constructor(options: PodDirectoryOptions) {
+    this.list = options.list;
+    this.procRoot = options.procRoot ?? "/proc";
+    this.refreshIntervalMs = options.refreshIntervalMs ?? 10_000;
+  }
+
+  clientFor(pid: number): string | undefined {
+    let uid = this.uidByPid.get(pid);
+    if (uid === undefined) {
+      try {
+        uid = podUidFromCgroup(readFileSync(join(this.procRoot, String(pid), "cgroup"), "utf8"));
+      } catch {
+        // Short-lived process that already exited
+        uid = null;
+      }
+      if (this.uidByPid.size >= MAX_CACHED_PIDS) this.uidByPid.clear();
+      this.uidByPid.set(pid, uid);
+    }
+    if (!uid) return undefined;
+    return this.workloads.get(uid) ?? `uid:${uid}`;
+  }
+
+  /** Re-list this node's pods, at most once per refresh interval */
+  async refresh(): Promise<void> {
+    if (Date.now() - this.lastRefresh < this.refreshIntervalMs) return;
+    this.lastRefresh = Date.now();
+    const pods = await this.list();
+    this.workloads.clear();
+    for (const pod of pods) {
+      this.workloads.set(pod.metadata.uid, `${pod.metadata.namespace}/${workloadOf(pod)}`);
+    }
+    // Pids are reused; drop mappings so new processes are looked up again
+    this.uidByPid.clear();
+  }
+
+  /**
+   * Replace provisional labels with workloads, refreshing the pod list if
+   * needed. Pods deleted before they could be listed lose their label.
+   */
+  async resolve(observations: Array<{ client?: string }>): Promise<void> {
+    const unresolved = observations.filter((o) => o.client?.startsWith("uid:"));
+    if (unresolved.length === 0) return;
+    await this.refresh();
+    for (const obs of unresolved) {
+      const workload = this.workloads.get(obs.client!.slice(4));
+      if (workload) obs.client = workload;
+      else delete obs.client;
+    }
+  }
+}
+
+// ---------------------------------------------------------------------------
+// In-cluster API access
+// ---------------------------------------------------------------------------
+
+const SERVICE_ACCOUNT_DIR = "/var/run/secrets/kubernetes.io/serviceaccount";
+
+/**
+ * List the pods scheduled on `nodeName` with the pod's service account.
+ * Needs `get`/`list` on pods cluster-wide (the Helm chart's ClusterRole).
+ */
+export function inClusterPodLister(nodeName: string, serviceAccountDir = SERVICE_ACCOUNT_DIR): PodLister {
+  const host = process.env["KUBERNETES_SERVICE_HOST"];
+  const port = process.env["KUBERNETES_SERVICE_PORT"] ?? "443";
+  if (!host) throw new Error("Not running in a Kubernetes pod (KUBERNETES_SERVICE_HOST is unset)");
+  const ca = readFileSync(join(serviceAccountDir, "ca.crt"));
+
+  return () =>
+    new Promise<KubePod[]>((resolveFn, reject) => {
+      // Tokens are rotated by the kubelet; read the current one each time
+      const token = readFileSync(join(serviceAccountDir, "token"), "utf8").trim();
+      const path = `/api/v1/pods?fieldSelector=${encodeURIComponent(`spec.nodeName=${nodeName}`)}`;
+      const req = request(
+        { host, port, path, ca, headers: { authorization: `Bearer ${token}`, accept: "application/json" } },
+        (res) => {
+          const chunks: Buffer[] = [];
+          res.on("data", (chunk: Buffer) => chunks.push(chunk));
+          res.on("end", () => {
+            const body = Buffer.concat(chunks).toString("utf8");
+            if (res.statusCode !== 200) {
+              reject(new Error(`Listing pods failed (HTTP ${res.statusCode}): ${body.slice(0, 200)}`));
+              return;
+            }
+            try {
+              resolveFn(((JSON.parse(body) as { items?: KubePod[] }).items ?? []).filter((p) => p.metadata?.uid));
+            } catch (err) {
+              reject(err instanceof Error ? err : new Error(String(err)));
+            }
+          });
+        },
+      );
+      req.on("error", reject);
+      req.end();
+    });
+}
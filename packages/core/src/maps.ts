@@ -0,0 +1,54 @@
+/**
+ * @module maps
+ *
+ * Maps and geocoding API keys set in configuration rather than code:
+ *
+ *   google.maps.api-key=AIzaSyD...
+ *   MAPBOX_ACCESS_TOKEN: pk.eyJ1Ijoi...
+ *   "HERE_API_KEY": "${HERE_API_KEY}"
+ *
+ * Browser map widgets and mobile apps often take their key from config
+ * alone, so no call site ever shows up. Each key becomes an API entry for
+ * the provider's endpoint in the "maps" category; literal keys are flagged
+ * as secrets by the scanner like any other location.
+ */
+
+import type { DependencyEntry } from "./plugin.js";
+
+// [key name, the endpoint the key authorizes]. Names may be namespaced and
+// use any separator: GOOGLE_MAPS_API_KEY, google.maps.api-key, googleMapsApiKey
+const MAPS_KEY_NAMES: [string, string][] = [
+  [String.raw`google[._-]?maps[._-]?(?:api[._-]?)?key|\bG?MAPS[._-]API[._-]KEY`, "https://maps.googleapis.com"],
+  [String.raw`mapbox[._-]?(?:access[._-]?|public[._-]?|secret[._-]?)?token`, "https://api.mapbox.com"],
+  [String.raw`\bhere[._-]?(?:maps[._-]?)?api[._-]?key`, "https://geocode.search.hereapi.com"],
+];
+
+// The key must be assigned a value: `key: value`, `key=value`, `"key": "value"`
+const MAPS_KEYS = MAPS_KEY_NAMES.map(([name, url]): [RegExp, string] => [
+  new RegExp(String.raw`(?:${name})["']?\s*[:=]\s*["']?[^\s"',}]`, "i"),
+  url,
+]);
+
+/** Find maps API keys in one config file */
+export function detectMapsKeys(source: string, relPath: string): DependencyEntry[] {
+  const entries: DependencyEntry[] = [];
+  const lines = source.split("\n");
+
+  for (let i = 0; i < lines.length; i++) {
+    const trimmed = lines[i]!.trim();
+    if (!trimmed || trimmed.startsWith("#") || trimmed.startsWith(";") || trimmed.startsWith("//")) continue;
+    for (const [pattern, url] of MAPS_KEYS) {
+      if (!pattern.test(trimmed)) continue;
+      entries.push({
+        kind: "api",
+        url,
+        category: "maps",
+        locations: [{ file: relPath, line: i + 1, context: trimmed.slice(0, 200), usage: "api_key" }],
+        usage_count: 1,
+        confidence: "medium",
+      });
+      break;
+    }
+  }
+  return entries;
+}
@@ -0,0 +1,359 @@
+/**
+ * @module metering
+ *
+ * Egress metering for long-running runtime sources (the agent and the
+ * proxy): per-service, per-vendor call and byte counts over fixed windows,
+ * with two alert kinds —
+ *
+ *   new_vendor    a service calls a vendor (or unknown host) it has never
+ *                 called before, once the meter has finished learning
+ *   volume_spike  a window's calls or bytes to a vendor far exceed that
+ *                 pair's moving average
+ *
+ * The baseline (first-seen times and moving averages) is plain JSON so it can
+ * be persisted across restarts; otherwise every restart re-learns.
+ * Counters are exposed in the Prometheus text format.
+ */
+
+import { createServer } from "node:http";
+import type { AddressInfo } from "node:net";
+import type { RuntimeObservation, VendorMatcher } from "./runtime.js";
+import type { IpRangeMatcher } from "./ip-ranges.js";
+import { signPayload } from "./webhook-signature.js";
+
+export type EgressAlertKind = "new_vendor" | "volume_spike";
+
+export interface EgressAlert {
+  kind: EgressAlertKind;
+  service: string;
+  /** Vendor slug, or the host for destinations the catalog doesn't know */
+  vendor: string;
+  known_vendor: boolean;
+  window_start: string;
+  window_end: string;
+  calls: number;
+  bytes: number;
+  /** For volume_spike: which measure spiked and its moving average */
+  metric?: "calls" | "bytes";
+  baseline?: number;
+  message: string;
+}
+
+interface Baseline {
+  first_seen: string;
+  windows: number;
+  calls_mean: number;
+  calls_var: number;
+  bytes_mean: number;
+  bytes_var: number;
+}
+
+export interface MeterState {
+  version: 1;
+  /** Windows closed since the meter (or its persisted state) started */
+  windows: number;
+  services: Record<string, Record<string, Baseline>>;
+}
+
+export interface EgressMeterOptions {
+  matchVendor: VendorMatcher;
+  matchIp?: IpRangeMatcher;
+  /** Service for observations without a client or process */
+  defaultService: string;
+  /** Attribute by process name when there is no client (default: true) */
+  byProcess?: boolean;
+  /** Windows to learn before new_vendor alerts fire (default: 60) */
+  learningWindows?: number;
+  /** A spike is at least this multiple of the moving average (default: 3) */
+  spikeRatio?: number;
+  /** Ignore spikes below this many calls in a window (default: 20) */
+  minCalls?: number;
+  /** Persisted state from a previous run */
+  state?: MeterState;
+  now?: () => string;
+}
+
+// Weight of the newest window in the moving averages (~20-window memory)
+const ALPHA = 0.1;
+// Standard deviations above the mean, on top of spikeRatio
+const SPIKE_SIGMA = 4;
+// Windows of history a pair needs before it can spike
+const MIN_SPIKE_HISTORY = 10;
+const MAX_DESTINATIONS_PER_SERVICE = 1000;
+
+interface WindowCount {
+  calls: number;
+  bytes: number;
+  known: boolean;
+}
+
+/** Escape a Prometheus label value */
+function label(value: string): string {
+  return value.replace(/\\/g, "\\\\").replace(/"/g, '\\"').replace(/\n/g, "\\n");
+}
+
+export class EgressMeter {
+  private readonly opts: Required<Omit<EgressMeterOptions, "matchIp" | "state" | "now">> & {
+    matchIp: IpRangeMatcher | undefined;
+  };
+  private readonly now: () => string;
+  private readonly state: MeterState;
+  private window = new Map<string, Map<string, WindowCount>>();
+  private windowStart: string;
+  private readonly totals = new Map<string, { calls: number; bytes: number }>();
+  private readonly alertCounts = new Map<EgressAlertKind, number>();
+
+  constructor(options: EgressMeterOptions) {
+    this.opts = {
+      matchVendor: options.matchVendor,
+      matchIp: options.matchIp,
+      defaultService: options.defaultService,
+      byProcess: options.byProcess ?? true,
+      learningWindows: options.learningWindows ?? 60,
+      spikeRatio: options.spikeRatio ?? 3,
+      minCalls: options.minCalls ?? 20,
+    };
+    this.now = options.now ?? (() => new Date().toISOString());
+    this.state = options.state ?? { version: 1, windows: 0, services: {} };
+    this.windowStart = this.now();
+  }
+
+  private key(obs: RuntimeObservation): { service: string; vendor: string; known: boolean } | null {
+    const vendor =
+      (obs.host ? this.opts.matchVendor(obs.host) : obs.ip && this.opts.matchIp ? this.opts.matchIp(obs.ip) : null) ??
+      (obs.service ? this.opts.matchVendor(obs.service) : null);
+    const dest = vendor ?? obs.host ?? obs.ip ?? obs.service;
+    if (!dest) return null;
+    const service = obs.client ?? (this.opts.byProcess ? obs.process : undefined) ?? this.opts.defaultService;
+    return { service, vendor: dest, known: vendor !== null };
+  }
+
+  private bucket(obs: RuntimeObservation): { window: WindowCount; total: { calls: number; bytes: number } } | null {
+    const k = this.key(obs);
+    if (!k) return null;
+    const byVendor = this.window.get(k.service) ?? new Map<string, WindowCount>();
+    this.window.set(k.service, byVendor);
+    let window = byVendor.get(k.vendor);
+    if (!window) {
+      if (byVendor.size >= MAX_DESTINATIONS_PER_SERVICE) return null;
+      window = { calls: 0, bytes: 0, known: k.known };
+      byVendor.set(k.vendor, window);
+    }
+    const totalKey = `${k.service}\u0000${k.vendor}`;
+    let total = this.totals.get(totalKey);
+    if (!total) {
+      total = { calls: 0, bytes: 0 };
+      this.totals.set(totalKey, total);
+    }
+    return { window, total };
+  }
+
+  /** Count one call (or `obs.count` calls) */
+  record(obs: RuntimeObservation): void {
+    const b = this.bucket(obs);
+    if (!b) return;
+    b.window.calls += obs.count ?? 1;
+    b.total.calls += obs.count ?? 1;
+  }
+
+  /** Add bytes transferred on a connection already recorded */
+  addBytes(obs: RuntimeObservation, bytes: number): void {
+    if (!(bytes > 0)) return;
+    const b = this.bucket(obs);
+    if (!b) return;
+    b.window.bytes += bytes;
+    b.total.bytes += bytes;
+  }
+
+  /** Close the current window: update baselines and return its alerts */
+  closeWindow(): EgressAlert[] {
+    const windowEnd = this.now();
+    const learning = this.state.windows < this.opts.learningWindows;
+    const alerts: EgressAlert[] = [];
+    const base = { window_start: this.windowStart, window_end: windowEnd };
+
+    // Every known pair is updated, with zero for pairs idle this window
+    for (const [service, byVendor] of Object.entries(this.state.services)) {
+      const current = this.window.get(service) ?? new Map<string, WindowCount>();
+      this.window.set(service, current);
+      for (const vendor of Object.keys(byVendor)) {
+        if (!current.has(vendor)) current.set(vendor, { calls: 0, bytes: 0, known: true });
+      }
+    }
+
+    for (const [service, byVendor] of this.window) {
+      const baselines = (this.state.services[service] ??= {});
+      for (const [vendor, count] of byVendor) {
+        const b = baselines[vendor];
+        if (!b) {
+          baselines[vendor] = {
+            first_seen: windowEnd,
+            windows: 1,
+            calls_mean: count.calls,
+            calls_var: 0,
+            bytes_mean: count.bytes,
+            bytes_var: 0,
+          };
+          if (!learning && count.calls > 0) {
+            alerts.push({
+              kind: "new_vendor",
+              service,
+              vendor,
+              known_vendor: count.known,
+              ...base,
+              calls: count.calls,
+              bytes: count.bytes,
+              message: `${service} started calling ${vendor} (${count.calls} calls this window)`,
+            });
+          }
+          continue;
+        }
+
+        if (b.windows >= MIN_SPIKE_HISTORY) {
+          for (const metric of ["calls", "bytes"] as const) {
+            const value = count[metric];
+            const mean = b[`${metric}_mean`];
+            const sd = Math.max(Math.sqrt(b[`${metric}_var`]), Math.sqrt(mean), 1);
+            if (metric === "calls" && value < this.opts.minCalls) continue;
+            if (metric === "bytes" && (mean === 0 || count.calls < this.opts.minCalls)) continue;
+            if (value >= this.opts.spikeRatio * Math.max(mean, 1) && value - mean >= SPIKE_SIGMA * sd) {
+              alerts.push({
+                kind: "volume_spike",
+                service,
+                vendor,
+                known_vendor: count.known,
+                ...base,
+                calls: count.calls,
+                bytes: count.bytes,
+                metric,
+                baseline: Math.round(mean * 10) / 10,
+                message: `${service} → ${vendor}: ${value} ${metric} this window, ${(value / Math.max(mean, 1)).toFixed(1)}× the moving average`,
+              });
+              break;
+            }
+          }
+        }
+
+        for (const metric of ["calls", "bytes"] as const) {
+          const diff = count[metric] - b[`${metric}_mean`];
+          b[`${metric}_mean`] += ALPHA * diff;
+          b[`${metric}_var`] = (1 - ALPHA) * (b[`${metric}_var`] + ALPHA * diff * diff);
+        }
+        b.windows++;
+      }
+    }
+
+    this.state.windows++;
+    this.window = new Map();
+    this.windowStart = windowEnd;
+    for (const a of alerts) this.alertCounts.set(a.kind, (this.alertCounts.get(a.kind) ?? 0) + 1);
+    return alerts;
+  }
+
+  /** Baseline to persist between runs */
+  snapshot(): MeterState {
+    return this.state;
+  }
+
+  /** Counters in the Prometheus text exposition format */
+  metrics(): string {
+    const lines = [
+      "# HELP thirdwatch_egress_calls_total Outbound calls observed, by service and vendor.",
+      "# TYPE thirdwatch_egress_calls_total counter",
+    ];
+    const bytes = [
+      "# HELP thirdwatch_egress_bytes_total Bytes transferred on outbound connections, by service and vendor.",
+      "# TYPE thirdwatch_egress_bytes_total counter",
+    ];
+    for (const [key, total] of this.totals) {
+      const [service, vendor] = key.split("\u0000");
+      const labels = `service="${label(service!)}",vendor="${label(vendor!)}"`;
+      lines.push(`thirdwatch_egress_calls_total{${labels}} ${total.calls}`);
+      bytes.push(`thirdwatch_egress_bytes_total{${labels}} ${total.bytes}`);
+    }
+    lines.push(...bytes);
+    lines.push(
+      "# HELP thirdwatch_egress_vendor_first_seen_timestamp_seconds When a service first called a vendor.",
+      "# TYPE thirdwatch_egress_vendor_first_seen_timestamp_seconds gauge",
+    );
+    for (const [service, byVendor] of Object.entries(this.state.services)) {
+      for (const [vendor, b] of Object.entries(byVendor)) {
+        const ts = Math.floor(Date.parse(b.first_seen) / 1000);
+        lines.push(
+          `thirdwatch_egress_vendor_first_seen_timestamp_seconds{service="${label(service)}",vendor="${label(vendor)}"} ${ts}`,
+        );
+      }
+    }
+    lines.push(
+      "# HELP thirdwatch_egress_alerts_total Egress alerts raised, by kind.",
+      "# TYPE thirdwatch_egress_alerts_total counter",
+    );
+    for (const kind of ["new_vendor", "volume_spike"] as const) {
+      lines.push(`thirdwatch_egress_alerts_total{kind="${kind}"} ${this.alertCounts.get(kind) ?? 0}`);
+    }
+    lines.push(
+      "# HELP thirdwatch_egress_learning Whether the meter is still learning its baseline (no new_vendor alerts).",
+      "# TYPE thirdwatch_egress_learning gauge",
+      `thirdwatch_egress_learning ${this.state.windows < this.opts.learningWindows ? 1 : 0}`,
+    );
+    return lines.join("\n") + "\n";
+  }
+}
+
+// ---------------------------------------------------------------------------
+// Delivery
+// ---------------------------------------------------------------------------
+
+export interface AlertWebhookSettings {
+  url: string;
+  /** HMAC secret; signs the body as X-Thirdwatch-Signature like change notifications */
+  secret?: string;
+  timeoutMs?: number;
+}
+
+/** POST one alert; rejects on network errors and non-2xx responses */
+export async function sendAlertWebhook(settings: AlertWebhookSettings, alert: EgressAlert): Promise<void> {
+  const body = JSON.stringify({ version: "1", event: "egress_anomaly", alert });
+  const headers: Record<string, string> = { "Content-Type": "application/json" };
+  if (settings.secret) {
+    headers["X-Thirdwatch-Signature"] = signPayload(body, settings.secret);
+  }
+  const res = await fetch(settings.url, {
+    method: "POST",
+    headers,
+    body,
+    signal: AbortSignal.timeout(settings.timeoutMs ?? 10_000),
+  });
+  if (!res.ok) throw new Error(`Alert webhook failed (HTTP ${res.status})`);
+}
+
+export interface MetricsServer {
+  readonly port: number;
+  close(): Promise<void>;
+}
+
+/** Serve `render()` on GET /metrics */
+export async function startMetricsServer(options: {
+  port: number;
+  host?: string;
+  render: () => string;
+}): Promise<MetricsServer> {
+  const server = createServer((req, res) => {
+    if (req.method !== "GET" || req.url?.split("?")[0] !== "/metrics") {
+      res.writeHead(404).end();
+      return;
+    }
+    res.writeHead(200, { "content-type": "text/plain; version=0.0.4" }).end(options.render());
+  });
+  await new Promise<void>((resolveFn, reject) => {
+    server.once("error", reject);
+    server.listen(options.port, options.host ?? "127.0.0.1", () => {
+      server.off("error", reject);
+      resolveFn();
+    });
+  });
+  return {
+    port: (server.address() as AddressInfo).port,
+    close: () => new Promise((resolveFn) => server.close(() => resolveFn())),
+  };
+}
@@ -0,0 +1,63 @@
+/**
+ * @module model-hub
+ *
+ * Model weights pulled from the Hugging Face Hub while an image builds or a
+ * job starts, outside any application code:
+ *
+ *   RUN huggingface-cli download meta-llama/Llama-3.1-8B-Instruct
+ *   RUN git clone https://huggingface.co/BAAI/bge-small-en-v1.5 /models/bge
+ *
+ * Each download becomes a GET of the model's hub URL with usage
+ * "model_download" — the same shape the Python analyzer reports for
+ * from_pretrained() at runtime — so the catalog attributes both to Hugging Face.
+ */
+
+import { basename, extname } from "node:path";
+import type { DependencyEntry } from "./plugin.js";
+
+// org/name, optionally under datasets/ or spaces/
+const REPO = String.raw`((?:datasets\/|spaces\/)?[A-Za-z0-9][\w.-]*\/[\w.-]*[\w-])`;
+
+const DOWNLOAD_PATTERNS: RegExp[] = [
+  // huggingface-cli download org/model, hf download org/model --local-dir /models
+  new RegExp(String.raw`\b(?:huggingface-cli|hf)\s+download\s+(?:--[\w-]+(?:=\S+)?\s+)*${REPO}`),
+  // git clone / curl / wget of a hub URL: https://huggingface.co/org/model/resolve/main/model.safetensors
+  new RegExp(String.raw`https?:\/\/(?:www\.)?(?:huggingface\.co|hf\.co)\/${REPO}`),
+  // RUN python -c "from transformers import AutoModel; AutoModel.from_pretrained('org/model')"
+  new RegExp(String.raw`\b(?:from_pretrained|snapshot_download|hf_hub_download)\(\s*(?:repo_id=)?["']${REPO}["']`),
+];
+
+/** Dockerfiles and shell scripts that can fetch models at build or start time */
+export function isBuildScript(path: string): boolean {
+  const name = basename(path);
+  return (
+    /^(?:Dockerfile|Containerfile)(?:\..+)?$/.test(name) ||
+    extname(name) === ".dockerfile" ||
+    extname(name) === ".sh"
+  );
+}
+
+/** Find Hugging Face Hub downloads in one Dockerfile or shell script */
+export function detectModelDownloads(source: string, relPath: string): DependencyEntry[] {
+  const entries: DependencyEntry[] = [];
+  const lines = source.split("\n");
+
+  for (let i = 0; i < lines.length; i++) {
+    const trimmed = lines[i]!.trim();
+    if (!trimmed || trimmed.startsWith("#")) continue;
+    for (const pattern of DOWNLOAD_PATTERNS) {
+      const match = trimmed.match(pattern);
+      if (!match) continue;
+      entries.push({
+        kind: "api",
+        url: `https://huggingface.co/${match[1]!.replace(/\.git$/, "")}`,
+        method: "GET",
+        locations: [{ file: relPath, line: i + 1, context: trimmed.slice(0, 200), usage: "model_download" }],
+        usage_count: 1,
+        confidence: "high",
+      });
+      break;
+    }
+  }
+  return entries;
+}
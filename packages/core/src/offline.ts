@@ -0,0 +1,32 @@
+/**
+ * @module offline
+ *
+ * Offline (air-gapped) mode. With THIRDWATCH_OFFLINE=1, or `--offline` on
+ * the CLI, nothing reaches out to a public service:
+ *
+ *   catalog sync        → installed bundles, vendored bundles
+ *                         (`--catalog-bundle`, `thirdwatch catalog import`),
+ *                         or the registries built into the CLI
+ *   --enrich            → an enrichment dataset exported from an online
+ *                         scan (`thirdwatch catalog export-enrichment`)
+ *   package metadata    → skipped; providers are inferred from names alone
+ *   update check        → skipped
+ *
+ * Endpoints the organization configures itself (the Thirdwatch server, an
+ * `llm_classification` endpoint, notifier webhooks) are its own
+ * infrastructure and still work.
+ */
+
+const TRUTHY = new Set(["1", "true", "yes", "on"]);
+
+/** Whether offline mode is on */
+export function isOffline(env: NodeJS.ProcessEnv = process.env): boolean {
+  return TRUTHY.has((env["THIRDWATCH_OFFLINE"] ?? "").trim().toLowerCase());
+}
+
+/** Reject a network call offline mode forbids, saying what to use instead */
+export function assertOnline(what: string, instead: string): void {
+  if (isOffline()) {
+    throw new Error(`${what} needs network access, which offline mode (THIRDWATCH_OFFLINE) disables; ${instead}`);
+  }
+}
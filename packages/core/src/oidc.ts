@@ -0,0 +1,60 @@
+/**
+ * @module oidc
+ *
+ * Identity provider issuers declared in configuration rather than code:
+ *
+ *   issuer: https://acme.okta.com/oauth2/default
+ *   spring.security.oauth2.resourceserver.jwt.issuer-uri=https://accounts.google.com
+ *   AUTH0_DOMAIN=acme.us.auth0.com
+ *
+ * Each issuer becomes an API entry in the "auth" category. The catalog then
+ * attributes known hosts (Auth0, Okta, Google, Entra ID) to their vendor;
+ * self-hosted issuers (Keycloak, Dex, ...) stay unattributed but categorized.
+ */
+
+import type { DependencyEntry } from "./plugin.js";
+
+/** Config files searched for issuers; manifests are excluded by the scanner */
+export const OIDC_CONFIG_EXTENSIONS = new Set([
+  ".yml",
+  ".yaml",
+  ".toml",
+  ".ini",
+  ".properties",
+  ".json",
+  ".conf",
+]);
+
+// issuer, issuer_url, issuer-uri, authority, discovery_url, jwks_uri,
+// AUTH0_DOMAIN, okta.orgUrl — optionally namespaced (oidc.issuer, OIDC_ISSUER_URL)
+const ISSUER_KEY =
+  String.raw`(?:[\w.-]*[._-])?(?:issuer(?:[_-]?ur[il])?|authority|discovery[_-]?ur[il]|jwks[_-]?ur[il]|(?:auth0|okta)[_-]?(?:domain|org[_-]?url))`;
+
+const ISSUER_RE = new RegExp(
+  String.raw`(?:^|[\s"'{,])${ISSUER_KEY}["']?\s*[:=]\s*["']?((?:https://)?[a-z0-9-]+(?:\.[a-z0-9-]+)*\.[a-z]{2,}(?::\d+)?(?:/[^\s"',}]*)?)`,
+  "i",
+);
+
+/** Find OIDC issuer URLs in one config file */
+export function detectOidcIssuers(source: string, relPath: string): DependencyEntry[] {
+  const entries: DependencyEntry[] = [];
+  const lines = source.split("\n");
+
+  for (let i = 0; i < lines.length; i++) {
+    const trimmed = lines[i]!.trim();
+    if (!trimmed || trimmed.startsWith("#") || trimmed.startsWith(";") || trimmed.startsWith("//")) continue;
+    const match = trimmed.match(ISSUER_RE);
+    if (!match) continue;
+
+    const value = match[1]!;
+    entries.push({
+      kind: "api",
+      url: value.startsWith("https://") ? value : `https://${value}`,
+      category: "auth",
+      locations: [{ file: relPath, line: i + 1, context: trimmed.slice(0, 200) }],
+      usage_count: 1,
+      confidence: "high",
+    });
+  }
+  return entries;
+}
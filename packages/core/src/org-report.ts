@@ -0,0 +1,126 @@
+/**
+ * @module org-report
+ *
+ * Organization rollup behind `thirdwatch report --merge`: every repository's
+ * scan results folded into a vendors × repositories matrix, a breakdown per
+ * category, and the most widely used vendors that are not on the approved
+ * list. It works from TDM files alone, so a platform team can build it in CI
+ * from collected scan artifacts without running the server.
+ */
+
+import type { ServiceInventory } from "./concentration.js";
+import type { ComplianceLinks, SDKRegistryEntry } from "./registry.js";
+import { complianceLinks } from "./registry.js";
+import { collectVendorUsages } from "./sla.js";
+import { createVendorMatcher } from "./runtime.js";
+
+export interface OrgVendorRow {
+  vendor: string;
+  display_name: string;
+  category?: string;
+  /** Privacy policy, DPA, subprocessor list, and trust center; absent when the catalog lists none */
+  compliance?: ComplianceLinks;
+  /** Data classification labels of its findings in any repository, e.g. ["pci", "pii"] */
+  data_classification?: string[];
+  /** Usages per repository; repositories that do not use the vendor are absent */
+  usages: Record<string, number>;
+  /** Number of repositories using the vendor */
+  repositories: number;
+  total_usages: number;
+  /** undefined when no approved list was given */
+  approved?: boolean;
+}
+
+export interface OrgCategoryRow {
+  category: string;
+  vendors: string[];
+  /** Repositories using at least one vendor in the category */
+  repositories: number;
+  total_usages: number;
+}
+
+export interface OrgReport {
+  generated_at: string;
+  /** Column order of the matrix */
+  repositories: string[];
+  /** Most widely used first */
+  vendors: OrgVendorRow[];
+  /** Most widely used first; vendors the catalog does not categorize are "uncategorized" */
+  categories: OrgCategoryRow[];
+  /** Unapproved vendors, most widely used first; empty without an approved list */
+  top_unapproved: OrgVendorRow[];
+}
+
+export interface OrgReportOptions {
+  /** Vendor slugs the organization has approved */
+  approved?: string[];
+  /** Length of top_unapproved (default: 10) */
+  top?: number;
+  now?: Date;
+}
+
+const byReach = (a: { repositories: number; total_usages: number }, b: { repositories: number; total_usages: number }) =>
+  b.repositories - a.repositories || b.total_usages - a.total_usages;
+
+export function buildOrgReport(
+  inventory: ServiceInventory[],
+  registry: SDKRegistryEntry[],
+  options: OrgReportOptions = {},
+): OrgReport {
+  const entries = new Map(registry.map((e) => [e.provider, e]));
+  const matchVendor = createVendorMatcher(registry);
+  // Approved names may be display names or aliases ("Amazon SES")
+  const approved = options.approved ? new Set(options.approved.map((v) => matchVendor(v) ?? v)) : null;
+  const rows = new Map<string, OrgVendorRow>();
+
+  for (const { service, tdms } of inventory) {
+    for (const usage of tdms.flatMap((tdm) => collectVendorUsages(tdm, matchVendor))) {
+      let row = rows.get(usage.vendor);
+      if (!row) {
+        const entry = entries.get(usage.vendor);
+        const compliance = entry ? complianceLinks(entry) : {};
+        row = {
+          vendor: usage.vendor,
+          display_name: entry?.display_name ?? usage.vendor,
+          ...(entry?.category ? { category: entry.category } : {}),
+          ...(Object.keys(compliance).length > 0 ? { compliance } : {}),
+          usages: {},
+          repositories: 0,
+          total_usages: 0,
+          ...(approved ? { approved: approved.has(usage.vendor) } : {}),
+        };
+        rows.set(usage.vendor, row);
+      }
+      if (usage.classification) {
+        row.data_classification = [...new Set([...(row.data_classification ?? []), ...usage.classification])].sort();
+      }
+      const previous = row.usages[service];
+      if (previous === undefined) row.repositories++;
+      row.usages[service] = (previous ?? 0) + usage.count;
+      row.total_usages += usage.count;
+    }
+  }
+
+  const vendors = [...rows.values()].sort((a, b) => byReach(a, b) || a.vendor.localeCompare(b.vendor));
+
+  const groups = new Map<string, { vendors: string[]; repos: Set<string>; total_usages: number }>();
+  for (const v of vendors) {
+    const category = v.category ?? "uncategorized";
+    const group = groups.get(category) ?? { vendors: [], repos: new Set<string>(), total_usages: 0 };
+    group.vendors.push(v.vendor);
+    for (const repo of Object.keys(v.usages)) group.repos.add(repo);
+    group.total_usages += v.total_usages;
+    groups.set(category, group);
+  }
+  const categories = [...groups]
+    .map(([category, g]) => ({ category, vendors: g.vendors, repositories: g.repos.size, total_usages: g.total_usages }))
+    .sort((a, b) => byReach(a, b) || a.category.localeCompare(b.category));
+
+  return {
+    generated_at: (options.now ?? new Date()).toISOString(),
+    repositories: inventory.map((s) => s.service).sort(),
+    vendors,
+    categories,
+    top_unapproved: approved ? vendors.filter((v) => !v.approved).slice(0, options.top ?? 10) : [],
+  };
+}
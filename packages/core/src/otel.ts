@@ -0,0 +1,235 @@
+/**
+ * @module otel
+ *
+ * OpenTelemetry traces as a runtime source (`thirdwatch ingest otel`). Every
+ * CLIENT span is one outbound call; its destination comes from the HTTP
+ * semantic conventions (url.full / http.url, server.address, net.peer.name)
+ * or, for spans with no address, from peer.service. The emitting service's
+ * service.name is recorded as the client, and user_agent.original (where the
+ * instrumentation records it) identifies the SDK that made the call.
+ *
+ * Input is OTLP/JSON — an ExportTraceServiceRequest per line, as written by
+ * the collector's `file` exporter — or OTLP/HTTP JSON pushed to the built-in
+ * receiver (`otlphttp` exporter with `encoding: json`).
+ */
+
+import { createServer } from "node:http";
+import type { IncomingMessage, ServerResponse } from "node:http";
+import type { AddressInfo } from "node:net";
+import { createGunzip } from "node:zlib";
+import type { RuntimeObservation } from "./runtime.js";
+import { isPrivateIp, normalizePath } from "./runtime.js";
+import { parseIp } from "./ip-ranges.js";
+import { normalizeQueryName } from "./dns-logs.js";
+
+type AttrValue = string | number | boolean;
+
+// SPAN_KIND_CLIENT; producers may also emit the enum name
+const CLIENT_KINDS = new Set<unknown>([3, "3", "SPAN_KIND_CLIENT"]);
+
+function record(value: unknown): Record<string, unknown> {
+  return value && typeof value === "object" ? (value as Record<string, unknown>) : {};
+}
+
+function list(value: unknown): unknown[] {
+  return Array.isArray(value) ? value : [];
+}
+
+/** Flatten OTLP KeyValue[] into a map of scalar values */
+function attributes(value: unknown): Map<string, AttrValue> {
+  const out = new Map<string, AttrValue>();
+  for (const kv of list(value)) {
+    const { key, value: v } = record(kv);
+    if (typeof key !== "string") continue;
+    const any = record(v);
+    const scalar = any.stringValue ?? any.intValue ?? any.doubleValue ?? any.boolValue;
+    if (typeof scalar === "string" || typeof scalar === "number" || typeof scalar === "boolean") {
+      out.set(key, scalar);
+    }
+  }
+  return out;
+}
+
+function str(attrs: Map<string, AttrValue>, ...keys: string[]): string | undefined {
+  for (const k of keys) {
+    const v = attrs.get(k);
+    if (v !== undefined && v !== "") return String(v);
+  }
+  return undefined;
+}
+
+function nanosToIso(value: unknown, fallback: string): string {
+  const digits = typeof value === "number" ? Math.trunc(value).toString() : value;
+  if (typeof digits !== "string" || !/^\d+$/.test(digits)) return fallback;
+  const ms = Number(BigInt(digits) / 1_000_000n);
+  return ms > 0 ? new Date(ms).toISOString() : fallback;
+}
+
+function fromSpan(
+  span: Record<string, unknown>,
+  serviceName: string | undefined,
+  now: () => string,
+): RuntimeObservation | null {
+  if (!CLIENT_KINDS.has(span.kind)) return null;
+  const attrs = attributes(span.attributes);
+
+  let host: string | undefined;
+  let port: number | undefined;
+  let path: string | undefined;
+  const url = str(attrs, "url.full", "http.url");
+  if (url) {
+    try {
+      const u = new URL(url);
+      host = u.hostname.replace(/^\[|\]$/g, "");
+      port = Number(u.port || (u.protocol === "http:" ? 80 : 443));
+      path = normalizePath(u.pathname);
+    } catch {
+      // Malformed URL attribute; fall back to the address attributes
+    }
+  }
+  host ??= str(attrs, "server.address", "net.peer.name", "http.host", "net.sock.peer.addr", "network.peer.address");
+  const portAttr = Number(str(attrs, "server.port", "net.peer.port"));
+  if (port === undefined && Number.isInteger(portAttr) && portAttr > 0) port = portAttr;
+  const service = str(attrs, "peer.service");
+
+  const dest: Pick<RuntimeObservation, "host" | "ip"> = {};
+  if (host) {
+    if (parseIp(host)) {
+      if (isPrivateIp(host)) return null;
+      dest.ip = host;
+    } else {
+      // Cluster-internal and first-hop names are not third parties
+      const name = normalizeQueryName(host);
+      if (!name) return null;
+      dest.host = name;
+    }
+  } else if (!service) {
+    return null;
+  }
+
+  const method = str(attrs, "http.request.method", "http.method");
+  const userAgent = str(attrs, "user_agent.original", "http.user_agent");
+  return {
+    source: "otel",
+    ...dest,
+    ...(!host && service ? { service: service.toLowerCase() } : {}),
+    ...(port ? { port } : {}),
+    ...(method ? { method: method.toUpperCase() } : {}),
+    ...(path && method ? { path } : {}),
+    ...(serviceName ? { client: serviceName } : {}),
+    ...(userAgent ? { user_agent: userAgent } : {}),
+    timestamp: nanosToIso(span.startTimeUnixNano, now()),
+  };
+}
+
+/** Extract observations from an OTLP/JSON ExportTraceServiceRequest */
+export function spanObservations(
+  request: unknown,
+  now: () => string = () => new Date().toISOString(),
+): RuntimeObservation[] {
+  const out: RuntimeObservation[] = [];
+  for (const rs of list(record(request).resourceSpans)) {
+    const resource = attributes(record(record(rs).resource).attributes);
+    const serviceName = str(resource, "service.name");
+    // instrumentationLibrarySpans is the pre-1.0 name of scopeSpans
+    const scopes = [...list(record(rs).scopeSpans), ...list(record(rs).instrumentationLibrarySpans)];
+    for (const scope of scopes) {
+      for (const span of list(record(scope).spans)) {
+        const obs = fromSpan(record(span), serviceName, now);
+        if (obs) out.push(obs);
+      }
+    }
+  }
+  return out;
+}
+
+// ---------------------------------------------------------------------------
+// OTLP/HTTP receiver
+// ---------------------------------------------------------------------------
+
+export interface OtlpReceiverOptions {
+  /** Listen port (default: 4318, the OTLP/HTTP port; 0 picks a free port) */
+  port?: number;
+  /** Listen address (default: 127.0.0.1) */
+  host?: string;
+  onObservation?: (obs: RuntimeObservation) => void;
+  /** Largest accepted request body in bytes (default: 16 MiB) */
+  maxBodyBytes?: number;
+}
+
+export interface OtlpReceiver {
+  readonly port: number;
+  readonly observations: RuntimeObservation[];
+  close(): Promise<void>;
+}
+
+function readBody(req: IncomingMessage, limit: number): Promise<string> {
+  return new Promise((resolveFn, reject) => {
+    const input = req.headers["content-encoding"] === "gzip" ? req.pipe(createGunzip()) : req;
+    const chunks: Buffer[] = [];
+    let size = 0;
+    input.on("data", (chunk: Buffer) => {
+      size += chunk.length;
+      if (size > limit) {
+        reject(new Error("body too large"));
+        req.destroy();
+        return;
+      }
+      chunks.push(chunk);
+    });
+    input.on("end", () => resolveFn(Buffer.concat(chunks).toString("utf8")));
+    input.on("error", reject);
+  });
+}
+
+/** Start an OTLP/HTTP JSON receiver for POST /v1/traces */
+export async function startOtlpReceiver(options: OtlpReceiverOptions = {}): Promise<OtlpReceiver> {
+  const observations: RuntimeObservation[] = [];
+  const limit = options.maxBodyBytes ?? 16 * 1024 * 1024;
+
+  const handle = async (req: IncomingMessage, res: ServerResponse) => {
+    if (req.method !== "POST" || req.url?.split("?")[0] !== "/v1/traces") {
+      res.writeHead(404).end();
+      return;
+    }
+    if (!String(req.headers["content-type"] ?? "").includes("json")) {
+      // Protobuf would need the OTLP schema; ask the exporter for JSON instead
+      res.writeHead(415, { "content-type": "text/plain" }).end("Use encoding: json\n");
+      return;
+    }
+    let request: unknown;
+    try {
+      request = JSON.parse(await readBody(req, limit));
+    } catch {
+      res.writeHead(400).end();
+      return;
+    }
+    for (const obs of spanObservations(request)) {
+      observations.push(obs);
+      options.onObservation?.(obs);
+    }
+    res.writeHead(200, { "content-type": "application/json" }).end("{}");
+  };
+
+  const server = createServer((req, res) => {
+    void handle(req, res);
+  });
+  await new Promise<void>((resolveFn, reject) => {
+    server.once("error", reject);
+    server.listen(options.port ?? 4318, options.host ?? "127.0.0.1", () => {
+      server.off("error", reject);
+      resolveFn();
+    });
+  });
+
+  return {
+    port: (server.address() as AddressInfo).port,
+    observations,
+    close(): Promise<void> {
+      return new Promise((resolveFn) => {
+        server.close(() => resolveFn());
+        server.closeAllConnections();
+      });
+    },
+  };
+}
@@ -0,0 +1,30 @@
+/**
+ * @module paths
+ *
+ * Scan-relative paths in one form on every platform. TDMs, fingerprints,
+ * baselines, and `services` prefixes all use forward slashes, so a scan on a
+ * Windows runner (src\pay\charge.py) reports the same files as one on Linux
+ * (src/pay/charge.py) and the two can be diffed.
+ */
+
+import { relative, sep } from "node:path";
+import type { DependencyEntry } from "./plugin.js";
+
+/** `path` with the platform separator replaced by "/" */
+export function toPosixPath(path: string, separator: string = sep): string {
+  return separator === "/" ? path : path.split(separator).join("/");
+}
+
+/** `file` relative to `root`, with forward slashes */
+export function relativePosix(root: string, file: string): string {
+  return toPosixPath(relative(root, file));
+}
+
+/** Rewrite the files analyzers reported (locations, manifests) to forward slashes */
+export function normalizeEntryPaths(entries: DependencyEntry[], separator: string = sep): void {
+  if (separator === "/") return;
+  for (const entry of entries) {
+    for (const loc of entry.locations) loc.file = toPosixPath(loc.file, separator);
+    if (entry.kind === "package") entry.manifest_file = toPosixPath(entry.manifest_file, separator);
+  }
+}
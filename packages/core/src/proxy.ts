@@ -0,0 +1,219 @@
+/**
+ * @module proxy
+ *
+ * Recording forward proxy behind `thirdwatch proxy`. Point a staging
+ * deployment at it with HTTP_PROXY / HTTPS_PROXY and every outbound request
+ * is recorded as a runtime observation:
+ *
+ *   - plain HTTP (absolute-URI requests): method, host, normalized path, and
+ *     User-Agent (matched to an SDK and version through the catalog)
+ *   - HTTPS (CONNECT tunnels): host and port, plus the User-Agent if the
+ *     client sends one on CONNECT — TLS is never intercepted
+ *
+ * Request and response bodies are streamed through untouched and never read.
+ * Repeat requests fold into one observation per destination, method,
+ * normalized path, and User-Agent, with a count and first and last seen, so
+ * a proxy left running for days holds one record per endpoint, not per call.
+ */
+
+import { createServer, request as httpRequest } from "node:http";
+import type { IncomingMessage, ServerResponse, OutgoingHttpHeaders } from "node:http";
+import { connect, isIP } from "node:net";
+import type { Socket, AddressInfo } from "node:net";
+import type { RuntimeObservation } from "./runtime.js";
+import { normalizePath } from "./runtime.js";
+
+// Hop-by-hop headers are meaningful only between the client and this proxy
+const HOP_BY_HOP = new Set([
+  "connection",
+  "keep-alive",
+  "proxy-authenticate",
+  "proxy-authorization",
+  "proxy-connection",
+  "te",
+  "trailer",
+  "transfer-encoding",
+  "upgrade",
+]);
+
+export interface RecordingProxyOptions {
+  /** Listen port (default: 8080; 0 picks a free port) */
+  port?: number;
+  /** Listen address (default: 127.0.0.1) */
+  host?: string;
+  /** Called for every request or tunnel, before it is folded into `observations` */
+  onObservation?: (obs: RuntimeObservation) => void;
+  /** Bytes sent plus received for a recorded request or tunnel, once it ends */
+  onTraffic?: (obs: RuntimeObservation, bytes: number) => void;
+  /** Clock, for tests */
+  now?: () => string;
+}
+
+export interface RecordingProxy {
+  /** Port actually bound */
+  readonly port: number;
+  /** One per destination, method, path, and User-Agent, with `count` and `last_seen` */
+  readonly observations: RuntimeObservation[];
+  close(): Promise<void>;
+}
+
+/** Split "host:port" / "[v6]:port" from a CONNECT request target */
+export function parseConnectTarget(target: string): { host: string; port: number } | null {
+  let url: URL;
+  try {
+    url = new URL(`http://${target}`);
+  } catch {
+    return null;
+  }
+  const port = Number(url.port || 443);
+  const host = url.hostname.replace(/^\[|\]$/g, "");
+  if (!host || !Number.isInteger(port) || port <= 0 || port > 65535) return null;
+  return { host, port };
+}
+
+function destination(host: string): Pick<RuntimeObservation, "host" | "ip"> {
+  return isIP(host) ? { ip: host } : { host: host.toLowerCase() };
+}
+
+function forwardHeaders(headers: IncomingMessage["headers"]): OutgoingHttpHeaders {
+  const out: OutgoingHttpHeaders = {};
+  for (const [name, value] of Object.entries(headers)) {
+    if (value !== undefined && !HOP_BY_HOP.has(name)) out[name] = value;
+  }
+  return out;
+}
+
+/** Start the proxy; resolves once it is listening */
+export async function startRecordingProxy(
+  options: RecordingProxyOptions = {},
+): Promise<RecordingProxy> {
+  const now = options.now ?? (() => new Date().toISOString());
+  const observed = new Map<string, RuntimeObservation>();
+  const sockets = new Set<Socket>();
+
+  const record = (obs: RuntimeObservation) => {
+    const key = [obs.host ?? obs.ip, obs.port, obs.method ?? "", obs.path ?? "", obs.user_agent ?? ""].join("|");
+    const seen = observed.get(key);
+    if (seen) {
+      seen.count = (seen.count ?? 1) + 1;
+      seen.last_seen = obs.timestamp;
+    } else {
+      observed.set(key, { ...obs });
+    }
+    options.onObservation?.(obs);
+    return obs;
+  };
+
+  const handleRequest = (req: IncomingMessage, res: ServerResponse) => {
+    let target: URL;
+    try {
+      target = new URL(req.url ?? "");
+    } catch {
+      res.writeHead(400).end("thirdwatch proxy expects absolute-URI requests\n");
+      return;
+    }
+    if (target.protocol !== "http:") {
+      res.writeHead(400).end("Only http:// URLs can be proxied without CONNECT\n");
+      return;
+    }
+
+    const hostname = target.hostname.replace(/^\[|\]$/g, "");
+    const port = Number(target.port || 80);
+    const obs = record({
+      source: "proxy",
+      ...destination(hostname),
+      port,
+      method: (req.method ?? "GET").toUpperCase(),
+      path: normalizePath(target.pathname),
+      ...(req.headers["user-agent"] ? { user_agent: req.headers["user-agent"] } : {}),
+      timestamp: now(),
+    });
+    let bytes = 0;
+    req.on("data", (chunk: Buffer) => {
+      bytes += chunk.length;
+    });
+
+    const upstream = httpRequest(
+      {
+        host: hostname,
+        port,
+        method: req.method,
+        path: `${target.pathname}${target.search}`,
+        headers: forwardHeaders(req.headers),
+      },
+      (upstreamRes) => {
+        res.writeHead(upstreamRes.statusCode ?? 502, forwardHeaders(upstreamRes.headers));
+        upstreamRes.on("data", (chunk: Buffer) => {
+          bytes += chunk.length;
+        });
+        upstreamRes.on("end", () => options.onTraffic?.(obs, bytes));
+        upstreamRes.pipe(res);
+      },
+    );
+    upstream.on("error", () => {
+      if (!res.headersSent) res.writeHead(502);
+      res.end();
+    });
+    req.pipe(upstream);
+  };
+
+  const server = createServer(handleRequest);
+
+  server.on("connect", (req: IncomingMessage, client: Socket, head: Buffer) => {
+    const target = parseConnectTarget(req.url ?? "");
+    if (!target) {
+      client.end("HTTP/1.1 400 Bad Request\r\n\r\n");
+      return;
+    }
+    // Few HTTP stacks send a User-Agent on CONNECT, but when one does it names the SDK behind the tunnel
+    const obs = record({
+      source: "proxy",
+      ...destination(target.host),
+      port: target.port,
+      ...(req.headers["user-agent"] ? { user_agent: req.headers["user-agent"] } : {}),
+      timestamp: now(),
+    });
+
+    const upstream = connect(target.port, target.host, () => {
+      client.write("HTTP/1.1 200 Connection Established\r\n\r\n");
+      if (head.length > 0) upstream.write(head);
+      upstream.pipe(client);
+      client.pipe(upstream);
+    });
+    sockets.add(upstream);
+    upstream.on("close", () => {
+      sockets.delete(upstream);
+      options.onTraffic?.(obs, upstream.bytesRead + upstream.bytesWritten);
+    });
+    upstream.on("error", () => {
+      client.end("HTTP/1.1 502 Bad Gateway\r\n\r\n");
+    });
+    client.on("error", () => upstream.destroy());
+  });
+
+  server.on("connection", (socket: Socket) => {
+    sockets.add(socket);
+    socket.on("close", () => sockets.delete(socket));
+  });
+
+  await new Promise<void>((resolveFn, reject) => {
+    server.once("error", reject);
+    server.listen(options.port ?? 8080, options.host ?? "127.0.0.1", () => {
+      server.off("error", reject);
+      resolveFn();
+    });
+  });
+
+  return {
+    port: (server.address() as AddressInfo).port,
+    get observations() {
+      return [...observed.values()];
+    },
+    close(): Promise<void> {
+      return new Promise((resolveFn) => {
+        server.close(() => resolveFn());
+        for (const socket of sockets) socket.destroy();
+      });
+    },
+  };
+}
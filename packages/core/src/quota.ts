@@ -0,0 +1,166 @@
+/**
+ * @module quota
+ *
+ * Rate-limit headroom behind `thirdwatch quota`. Runtime TDMs from the agent
+ * carry how many requests reached each vendor host between the first and last
+ * observation; the catalog's `rate_limits` say how many the vendor allows per
+ * window. Their ratio is the integration's utilization:
+ *
+ *   api.stripe.com   41,000 requests over 10 min   → 68 req/s vs 100/s   68%
+ *   *.myshopify.com  3,000 requests over 20 min     → 2.5 req/s vs 2/s   125%
+ *
+ * Requests to hosts the same limit covers are added up, across every TDM
+ * passed (one per instance of a service, say), since vendors count them
+ * against the same account. A vendor with several published limits is
+ * measured against the first whose domains match, normally the entry tier;
+ * `plans` picks another and `assume` substitutes a contracted limit.
+ *
+ * Rates are averages over the observation span, so bursts that trip a
+ * per-second limit inside a quiet hour don't show up: treat "ok" as "not
+ * saturated on average", not as "never throttled".
+ */
+
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry, VendorRateLimit } from "./registry.js";
+import { createVendorMatcher } from "./runtime.js";
+import { extractHost, matchesDomain } from "./first-party.js";
+
+/** Utilization at which an integration is reported as near its quota */
+export const DEFAULT_QUOTA_THRESHOLD = 0.8;
+
+export type QuotaStatus = "ok" | "near" | "over";
+
+export interface QuotaUsage {
+  vendor: string;
+  display_name: string;
+  /** Requests allowed per window */
+  requests: number;
+  window_seconds: number;
+  plan?: string;
+  scope?: string;
+  /** Where the limit is published, or "assumed" for `assume` overrides */
+  source: string;
+  /** Hosts whose traffic counts against the limit */
+  hosts: string[];
+  /** Requests observed across the runtime TDMs */
+  observed_requests: number;
+  /** Average request rate over the observation spans */
+  requests_per_second: number;
+  /** Average requests per limit window, comparable to `requests` */
+  requests_per_window: number;
+  /** requests_per_window / requests; above 1 means the limit is exceeded on average */
+  utilization: number;
+  status: QuotaStatus;
+}
+
+export interface QuotaReport {
+  threshold: number;
+  /** Highest utilization first */
+  usages: QuotaUsage[];
+  /** Vendors with observed traffic but no published or assumed limit */
+  unknown: string[];
+  /** Vendors at or above the threshold */
+  near_quota: string[];
+}
+
+export interface QuotaReportOptions {
+  /** Utilization at which to report an integration (default 0.8) */
+  threshold?: number;
+  /** Catalog plan to measure a vendor against, by vendor slug (case-insensitive substring) */
+  plans?: Record<string, string>;
+  /** Contracted limits by vendor slug, overriding the catalog */
+  assume?: Record<string, { requests: number; window_seconds: number }>;
+}
+
+function round(value: number, digits: number): number {
+  const f = 10 ** digits;
+  return Math.round(value * f) / f;
+}
+
+function limitFor(
+  limits: VendorRateLimit[],
+  host: string,
+  plan: string | undefined,
+): VendorRateLimit | undefined {
+  const matching = limits.filter((l) => !l.domains || l.domains.some((d) => matchesDomain(host, d)));
+  if (plan) {
+    const wanted = plan.toLowerCase();
+    return matching.find((l) => l.plan?.toLowerCase().includes(wanted)) ?? matching[0];
+  }
+  return matching[0];
+}
+
+/** Quota utilization for runtime-observed vendor traffic */
+export function buildQuotaReport(
+  tdms: TDM[],
+  registry: SDKRegistryEntry[],
+  options: QuotaReportOptions = {},
+): QuotaReport {
+  const threshold = options.threshold ?? DEFAULT_QUOTA_THRESHOLD;
+  const entries = new Map(registry.map((e) => [e.provider, e]));
+  const matchVendor = createVendorMatcher(registry);
+
+  const usages = new Map<string, QuotaUsage>();
+  const unknown = new Set<string>();
+  for (const api of tdms.flatMap((tdm) => tdm.apis)) {
+    if (!api.runtime || api.first_party) continue;
+    const host = extractHost(api.resolved_url ?? api.url);
+    if (!host) continue;
+    const vendor = api.provider ?? matchVendor(host);
+    if (!vendor) continue;
+    const entry = entries.get(vendor);
+    const assumed = options.assume?.[vendor];
+    const limit: VendorRateLimit | undefined = assumed
+      ? { ...assumed, url: "assumed" }
+      : limitFor(entry?.rate_limits ?? [], host, options.plans?.[vendor]);
+    if (!limit) {
+      unknown.add(vendor);
+      continue;
+    }
+
+    const key = `${vendor}\0${limit.plan ?? ""}\0${limit.requests}/${limit.window_seconds}`;
+    let usage = usages.get(key);
+    if (!usage) {
+      usage = {
+        vendor,
+        display_name: entry?.display_name ?? vendor,
+        requests: limit.requests,
+        window_seconds: limit.window_seconds,
+        ...(limit.plan ? { plan: limit.plan } : {}),
+        ...(limit.scope ? { scope: limit.scope } : {}),
+        source: limit.url,
+        hosts: [],
+        observed_requests: 0,
+        requests_per_second: 0,
+        requests_per_window: 0,
+        utilization: 0,
+        status: "ok",
+      };
+      usages.set(key, usage);
+    }
+    // A span shorter than the window can't say more than "this many in one window"
+    const span = (Date.parse(api.runtime.last_seen) - Date.parse(api.runtime.first_seen)) / 1000;
+    const seconds = Math.max(Number.isFinite(span) ? span : 0, limit.window_seconds);
+    usage.observed_requests += api.runtime.count;
+    usage.requests_per_second += api.runtime.count / seconds;
+    if (!usage.hosts.includes(host)) usage.hosts.push(host);
+  }
+
+  for (const usage of usages.values()) {
+    const perWindow = usage.requests_per_second * usage.window_seconds;
+    const utilization = perWindow / usage.requests;
+    usage.requests_per_second = round(usage.requests_per_second, 3);
+    usage.requests_per_window = round(perWindow, 1);
+    usage.utilization = round(utilization, 3);
+    usage.status = utilization > 1 ? "over" : utilization >= threshold ? "near" : "ok";
+    usage.hosts.sort();
+  }
+
+  const sorted = [...usages.values()].sort((a, b) => b.utilization - a.utilization || a.vendor.localeCompare(b.vendor));
+  return {
+    threshold,
+    usages: sorted,
+    unknown: [...unknown].filter((v) => !sorted.some((u) => u.vendor === v)).sort(),
+    near_quota: [...new Set(sorted.filter((u) => u.status !== "ok").map((u) => u.vendor))],
+  };
+}
@@ -48,6 +48,7 @@ const KNOWN_BRANDS = new Set([
   "openai",
   "anthropic",
   "cohere",
+  "mistral",
   "replicate",
   "twilio",
   "sendgrid",
@@ -100,6 +101,9 @@ const API_DOMAIN_TO_PROVIDER = new Map<string, string>([
   ["api.linear.app", "linear"],
   ["api.replicate.com", "replicate"],
   ["api.cohere.ai", "cohere"],
+  ["api.cohere.com", "cohere"],
+  ["api.mistral.ai", "mistral"],
+  ["generativelanguage.googleapis.com", "gemini"],
   ["api.pinecone.io", "pinecone"],
   ["api.datadoghq.com", "datadog"],
   ["api.pagerduty.com", "pagerduty"],
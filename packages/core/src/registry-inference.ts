@@ -14,6 +14,8 @@
  * for manifest packages that have no match in the curated registry.
  */
 
+import { isOffline } from "./offline.js";
+
 interface PackageMetadata {
   homepage?: string | undefined;
   keywords?: string[] | undefined;
@@ -47,6 +49,7 @@ const KNOWN_PROVIDER_SLUGS = new Set([
   "openai",
   "anthropic",
   "cohere",
+  "mistral",
   "replicate",
   "twilio",
   "sendgrid",
@@ -92,6 +95,7 @@ export async function inferProvider(
   if (nameSlug) return nameSlug;
 
   // Strategy 2: Fetch metadata from registry (optional, may fail offline)
+  if (isOffline()) return null;
   try {
     const meta = await fetchPackageMetadata(packageName, ecosystem);
     if (!meta) return null;
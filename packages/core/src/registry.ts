@@ -12,11 +12,42 @@ export interface ConstructorPattern {
   services_field?: string;
 }
 
+/** Vendor categories accepted in registry entries (mirrors schema/registry.schema.json) */
+export const VENDOR_CATEGORIES = [
+  "payments", "banking", "billing", "exchange-rates", "commerce", "shipping",
+  "messaging", "push", "email", "communication", "auth", "identity",
+  "identity-verification", "ai", "analytics", "observability", "error-tracking",
+  "incident", "feature-flags", "database", "data-warehouse", "cache", "queue",
+  "message-broker", "workflow", "search", "storage", "cdn", "hosting", "cloud",
+  "baas", "cms", "crm", "enrichment", "documents", "support", "productivity",
+  "devtools", "maps", "ip-intelligence", "media", "security", "bot-mitigation",
+  "other",
+] as const;
+
+export type VendorCategory = (typeof VENDOR_CATEGORIES)[number];
+
+/** A snippet or URL an entry must detect — checked by `thirdwatch catalog validate` */
+export interface CatalogExample {
+  ecosystem?: string;
+  code?: string;
+  url?: string;
+}
+
 export interface SDKRegistryEntry {
   provider: string;
   display_name: string;
+  category?: VendorCategory;
   homepage?: string;
   changelog_url?: string;
+  docs_url?: string;
+  status_page_url?: string;
+  privacy_policy_url?: string;
+  /** Data processing agreement */
+  dpa_url?: string;
+  subprocessors_url?: string;
+  trust_center_url?: string;
+  /** Other names the vendor goes by ("Amazon SES"), resolved to `provider` when aggregating */
+  aliases?: string[];
   patterns: {
     npm?: SDKPatternEntry[];
     pypi?: SDKPatternEntry[];
@@ -26,9 +57,68 @@ export interface SDKRegistryEntry {
     packagist?: SDKPatternEntry[];
   };
   known_api_base_urls?: string[];
+  /** API hostnames; "*.x.com" = subdomains only, "x.com" = domain + subdomains, "a.*.x.com" = one label */
+  domains?: string[];
+  /** Published CIDR blocks, for attributing runtime traffic seen only by IP */
+  ip_ranges?: string[];
+  /** Published SLA, for composite availability reports (`thirdwatch sla`) */
+  sla?: VendorSla;
+  /** Published API rate limits, for quota-usage reports (`thirdwatch quota`) */
+  rate_limits?: VendorRateLimit[];
+  /** SDK user-agent fingerprints, for attributing runtime traffic to an SDK and version */
+  user_agents?: UserAgentFingerprint[];
   env_var_patterns?: string[];
   constructors?: Record<string, ConstructorPattern[]>;
   factories?: Record<string, string[]>;
+  examples?: CatalogExample[];
+}
+
+/** A published uptime commitment, as a percentage (99.95 = "three and a half nines") */
+export interface VendorSla {
+  uptime: number;
+  /** Where the commitment is published */
+  url: string;
+  /** Plan or tier the commitment applies to, e.g. "Enterprise" */
+  plan?: string;
+  /** Per-service commitments that differ from the vendor-wide one */
+  services?: Array<{ name: string; uptime: number; domains: string[] }>;
+}
+
+/** A published API rate limit: `requests` per `window_seconds` */
+export interface VendorRateLimit {
+  requests: number;
+  window_seconds: number;
+  /** Where the limit is published */
+  url: string;
+  /** Plan or tier the limit applies to, e.g. "Free" */
+  plan?: string;
+  /** What the limit is counted against, e.g. "per account" */
+  scope?: string;
+  /** Hosts the limit applies to; absent means all of the vendor's hosts */
+  domains?: string[];
+}
+
+/** An SDK's User-Agent header: `pattern` is a regex whose first capture group is the SDK version */
+export interface UserAgentFingerprint {
+  pattern: string;
+  /** Package the SDK ships as, as it appears in manifests */
+  sdk_package: string;
+  ecosystem?: string;
+}
+
+/** Links reviewers need for DPIA and subprocessor reviews, in display order */
+export const COMPLIANCE_URL_KEYS = ["privacy_policy_url", "dpa_url", "subprocessors_url", "trust_center_url"] as const;
+
+export type ComplianceLinks = Partial<Record<(typeof COMPLIANCE_URL_KEYS)[number], string>>;
+
+/** The compliance links an entry publishes; empty when it has none */
+export function complianceLinks(entry: SDKRegistryEntry): ComplianceLinks {
+  const links: ComplianceLinks = {};
+  for (const key of COMPLIANCE_URL_KEYS) {
+    const url = entry[key];
+    if (url) links[key] = url;
+  }
+  return links;
 }
 
 export interface RegistryMaps {
@@ -38,7 +128,7 @@ export interface RegistryMaps {
   urlProviders: Map<string, string>;
 }
 
-function isValidRegistryEntry(value: unknown): value is SDKRegistryEntry {
+export function isValidRegistryEntry(value: unknown): value is SDKRegistryEntry {
   if (value == null || typeof value !== "object") return false;
   const obj = value as Record<string, unknown>;
   if (typeof obj.provider !== "string") return false;
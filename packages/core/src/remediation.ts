@@ -0,0 +1,273 @@
+/**
+ * @module remediation
+ *
+ * Machine-readable fixes for findings a policy would flag, attached to the
+ * location as `remediation` so the PR bot can offer them as one-click
+ * suggestions:
+ *
+ *   use_env_var   stripe.api_key = "sk_live_..."  → os.environ["STRIPE_SECRET_KEY"]
+ *                 dsn: postgres://app:pw@db/app   → dsn: ${DATABASE_URL}
+ *   add_timeout   requests.get(url)               → requests.get(url, timeout=10)
+ *                 fetch(url)                      → fetch(url, { signal: AbortSignal.timeout(10_000) })
+ *
+ * When the rewrite is mechanical the remediation carries `replacement`, the
+ * new text of the line (what a PR suggestion block needs), and `diff`, a
+ * unified diff of it with a line of context on each side. Otherwise it
+ * carries a description alone (Go's http.Get has no per-call timeout, for
+ * instance). Secrets are redacted from the diff's removed line like any
+ * other context, so apply `replacement` rather than the diff to such lines.
+ */
+
+import { extname } from "node:path";
+import type { TDMRemediation } from "@thirdwatch/tdm";
+import type { DependencyEntry } from "./plugin.js";
+import { detectHardcodedSecret } from "./secrets.js";
+
+/** Timeout suggested for calls that have none, in seconds */
+export const DEFAULT_TIMEOUT_SECONDS = 10;
+
+// Environment variable each credential kind conventionally lives in
+const SECRET_ENV_VARS: Record<string, string> = {
+  stripe_secret_key: "STRIPE_SECRET_KEY",
+  aws_access_key_id: "AWS_ACCESS_KEY_ID",
+  github_token: "GITHUB_TOKEN",
+  gitlab_token: "GITLAB_TOKEN",
+  bitbucket_token: "BITBUCKET_TOKEN",
+  anthropic_api_key: "ANTHROPIC_API_KEY",
+  openai_api_key: "OPENAI_API_KEY",
+  google_api_key: "GOOGLE_API_KEY",
+  mapbox_token: "MAPBOX_ACCESS_TOKEN",
+  huggingface_token: "HF_TOKEN",
+  replicate_api_token: "REPLICATE_API_TOKEN",
+  slack_token: "SLACK_BOT_TOKEN",
+  slack_webhook_url: "SLACK_WEBHOOK_URL",
+  teams_webhook_url: "TEAMS_WEBHOOK_URL",
+  supabase_secret_key: "SUPABASE_SECRET_KEY",
+  fcm_server_key: "FCM_SERVER_KEY",
+  datadog_api_key: "DD_API_KEY",
+  newrelic_license_key: "NEW_RELIC_LICENSE_KEY",
+  newrelic_api_key: "NEW_RELIC_API_KEY",
+  honeycomb_api_key: "HONEYCOMB_API_KEY",
+};
+
+// Credentials that are a whole URL, so the variable holds the URL
+const URL_SECRET_KINDS = new Set(["connection_string_password", "error_tracking_dsn", "slack_webhook_url", "teams_webhook_url"]);
+
+const DATABASE_TYPES = new Set(["postgresql", "mysql", "mariadb", "mssql", "sqlserver", "oracle", "cockroachdb"]);
+
+/** The environment variable a secret found on `entry` should move to */
+function envVarFor(kind: string, entry: DependencyEntry): string {
+  if (SECRET_ENV_VARS[kind]) return SECRET_ENV_VARS[kind]!;
+  const slug = (value: string) => value.toUpperCase().replace(/[^A-Z0-9]+/g, "_");
+  if (kind === "connection_string_password" && entry.kind === "infrastructure") {
+    return DATABASE_TYPES.has(entry.type) ? "DATABASE_URL" : `${slug(entry.type)}_URL`;
+  }
+  if (kind === "error_tracking_dsn") return `${entry.kind === "sdk" || entry.kind === "api" ? slug(entry.provider ?? "SENTRY") : "SENTRY"}_DSN`;
+  return slug(kind);
+}
+
+type Language = "python" | "javascript" | "go" | "java" | "php" | "rust" | "ruby" | "config";
+
+function languageOf(relPath: string): Language | null {
+  switch (extname(relPath)) {
+    case ".py":
+      return "python";
+    case ".js":
+    case ".mjs":
+    case ".cjs":
+    case ".jsx":
+    case ".ts":
+    case ".mts":
+    case ".cts":
+    case ".tsx":
+      return "javascript";
+    case ".go":
+      return "go";
+    case ".java":
+    case ".kt":
+      return "java";
+    case ".php":
+      return "php";
+    case ".rs":
+      return "rust";
+    case ".rb":
+      return "ruby";
+    case ".yml":
+    case ".yaml":
+    case ".json":
+    case ".toml":
+    case ".ini":
+    case ".properties":
+    case ".conf":
+    case ".env":
+      return "config";
+    default:
+      return null;
+  }
+}
+
+const ENV_LOOKUP: Record<Exclude<Language, "config">, { expr: (name: string) => string; note?: string }> = {
+  python: { expr: (n) => `os.environ["${n}"]`, note: "import os" },
+  javascript: { expr: (n) => `process.env.${n}` },
+  go: { expr: (n) => `os.Getenv("${n}")`, note: `import "os"` },
+  java: { expr: (n) => `System.getenv("${n}")` },
+  php: { expr: (n) => `getenv('${n}')` },
+  rust: { expr: (n) => `std::env::var("${n}")?` },
+  ruby: { expr: (n) => `ENV["${n}"]` },
+};
+
+/** A one-line unified diff with a line of context on each side, secrets redacted */
+export function lineDiff(relPath: string, lines: string[], line: number, replacement: string): string {
+  const start = Math.max(1, line - 1);
+  const end = Math.min(lines.length, line + 1);
+  const clean = (text: string) => {
+    const secret = detectHardcodedSecret(text);
+    return secret ? text.split(secret.value).join("[REDACTED]") : text;
+  };
+  const hunk: string[] = [];
+  for (let n = start; n <= end; n++) {
+    if (n === line) hunk.push(`-${clean(lines[n - 1]!)}`, `+${replacement}`);
+    else hunk.push(` ${clean(lines[n - 1]!)}`);
+  }
+  const count = end - start + 1;
+  return [`--- a/${relPath}`, `+++ b/${relPath}`, `@@ -${start},${count} +${start},${count} @@`, ...hunk, ""].join("\n");
+}
+
+function escapeRegExp(value: string): string {
+  return value.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+}
+
+function envVarRemediation(
+  entry: DependencyEntry,
+  text: string,
+  relPath: string,
+  lines: string[],
+  line: number,
+): TDMRemediation | null {
+  const secret = detectHardcodedSecret(text);
+  if (!secret) return null;
+  const name = envVarFor(secret.kind, entry);
+  const language = languageOf(relPath);
+  const description = `Move the hardcoded ${secret.kind.replace(/_/g, " ")} to the ${name} environment variable and rotate it`;
+
+  // The string literal holding the secret: "sk_live_...", 'postgres://...@db'
+  const literal = text.match(new RegExp(`(["'\`])[^"'\`\\n]*${escapeRegExp(secret.value)}[^"'\`\\n]*\\1`));
+  let replacement: string | null = null;
+  let note: string | undefined;
+  if (language === "config") {
+    // Keep JSON's quotes; YAML and .properties values may be bare
+    replacement = literal
+      ? text.replace(literal[0], `${literal[1]}\${${name}}${literal[1]}`)
+      : text.replace(secret.value, `\${${name}}`);
+  } else if (language && literal) {
+    // The variable replaces the whole literal: the key itself, or a URL
+    // (DSN, webhook) whose credential can't be split out of it
+    const whole = literal[0] === `${literal[1]}${secret.value}${literal[1]}`;
+    if (whole || URL_SECRET_KINDS.has(secret.kind)) {
+      replacement = text.replace(literal[0], ENV_LOOKUP[language].expr(name));
+      note = ENV_LOOKUP[language].note;
+    }
+  }
+  return {
+    kind: "use_env_var",
+    description: note && replacement !== null ? `${description} (requires ${note})` : description,
+    env_var: name,
+    ...(replacement !== null
+      ? { replacement, diff: lineDiff(relPath, lines, line, replacement) }
+      : {}),
+  };
+}
+
+/** Index just past the parenthesis closing the one opened at `open`, or -1 */
+function closingParen(text: string, open: number): number {
+  let depth = 0;
+  let quote: string | null = null;
+  for (let i = open; i < text.length; i++) {
+    const c = text[i]!;
+    if (quote) {
+      if (c === "\\") i++;
+      else if (c === quote) quote = null;
+    } else if (c === '"' || c === "'" || c === "`") quote = c;
+    else if (c === "(") depth++;
+    else if (c === ")" && --depth === 0) return i + 1;
+  }
+  return -1;
+}
+
+const PY_HTTP_CALL_RE = /\b(?:requests|httpx)\.(?:get|post|put|patch|delete|head|options|request)\(/;
+const JS_FETCH_RE = /\bfetch\(/;
+const JS_AXIOS_RE = /\baxios\.(?:get|delete|head|options)\(/;
+const GO_DEFAULT_CLIENT_RE = /\bhttp\.(?:Get|Post|PostForm|Head)\(/;
+
+function timeoutRemediation(text: string, relPath: string, lines: string[], line: number): TDMRemediation | null {
+  const language = languageOf(relPath);
+  const rewrite = (call: RegExpMatchArray, insert: (args: string) => string | null): string | null => {
+    const open = call.index! + call[0].length - 1;
+    const close = closingParen(text, open);
+    if (close === -1) return null;
+    const args = text.slice(open + 1, close - 1);
+    const rewritten = insert(args);
+    return rewritten === null ? null : text.slice(0, open + 1) + rewritten + text.slice(close - 1);
+  };
+  const remediation = (description: string, replacement: string | null): TDMRemediation => ({
+    kind: "add_timeout",
+    description,
+    ...(replacement !== null ? { replacement, diff: lineDiff(relPath, lines, line, replacement) } : {}),
+  });
+
+  if (language === "python") {
+    const call = text.match(PY_HTTP_CALL_RE);
+    if (!call || /\btimeout\s*=/.test(text)) return null;
+    return remediation(
+      `Pass a timeout; requests and httpx calls without one can hang a worker indefinitely`,
+      rewrite(call, (args) => `${args}, timeout=${DEFAULT_TIMEOUT_SECONDS}`),
+    );
+  }
+  if (language === "javascript") {
+    const fetchCall = text.match(JS_FETCH_RE);
+    if (fetchCall && !/\bsignal\s*:/.test(text)) {
+      return remediation(
+        "Abort the request after a deadline; fetch has no default timeout",
+        // Only fetch(url): an options object may span lines or be a variable
+        rewrite(fetchCall, (args) =>
+          args.trim() && !args.includes(",") ? `${args}, { signal: AbortSignal.timeout(${DEFAULT_TIMEOUT_SECONDS}_000) }` : null,
+        ),
+      );
+    }
+    const axiosCall = text.match(JS_AXIOS_RE);
+    if (axiosCall && !/\btimeout\s*:/.test(text)) {
+      return remediation(
+        "Set a timeout; axios waits forever by default",
+        rewrite(axiosCall, (args) => (args.trim() && !args.includes(",") ? `${args}, { timeout: ${DEFAULT_TIMEOUT_SECONDS}_000 }` : null)),
+      );
+    }
+    return null;
+  }
+  if (language === "go" && GO_DEFAULT_CLIENT_RE.test(text)) {
+    return remediation(
+      `Use an http.Client with Timeout set (or a request built with context.WithTimeout); http.DefaultClient never times out`,
+      null,
+    );
+  }
+  return null;
+}
+
+/**
+ * Attach remediations to the locations of one file's findings. Runs before
+ * secrets are redacted, since the fix has to find the literal to replace.
+ */
+export function suggestRemediations(entries: DependencyEntry[], source: string, relPath: string): void {
+  const lines = source.split("\n");
+  for (const entry of entries) {
+    if (entry.kind === "package") continue;
+    for (const loc of entry.locations) {
+      if (loc.file !== relPath || loc.remediation) continue;
+      const text = lines[loc.line - 1];
+      if (text === undefined) continue;
+      const remediation =
+        envVarRemediation(entry, text, relPath, lines, loc.line) ??
+        (entry.kind === "api" ? timeoutRemediation(text, relPath, lines, loc.line) : null);
+      if (remediation) loc.remediation = remediation;
+    }
+  }
+}
@@ -0,0 +1,150 @@
+/**
+ * @module rule-ids
+ *
+ * Stable detector rule IDs and linter-style per-rule settings. Every finding
+ * gets a `rule_id` derived from what was detected and by which analyzer:
+ *
+ *   TW-PKG-NPM               package declared in an npm manifest
+ *   TW-STRIPE-SDK-GO         Stripe SDK used from Go
+ *   TW-STRIPE-API            call to a Stripe host from the catalog
+ *   TW-HTTP-URL              call to an unrecognized host
+ *   TW-UNCLASSIFIED-ENDPOINT call or raw socket to a public IP address
+ *   TW-INFRA-POSTGRESQL      direct PostgreSQL connection
+ *   TW-WEBHOOK-INBOUND-CALLBACK
+ *
+ * `rule_settings` in .thirdwatch.yml turns rules off or changes their
+ * severity; keys may use `*` wildcards and the most specific key wins:
+ *
+ *   rule_settings:
+ *     TW-HTTP-URL: off
+ *     TW-*-SDK-*: warning
+ *     TW-OPENAI-SDK-PYTHON: error
+ *
+ * `category:<name>` keys apply to every finding in a catalog category, e.g.
+ * `category:email: error`. A matching rule ID key always takes precedence.
+ */
+
+import type { Severity } from "@thirdwatch/tdm";
+import type { DependencyEntry } from "./plugin.js";
+import { isPrivateIp, parseIp } from "./ip-ranges.js";
+
+export type RuleSetting = Severity | "off";
+
+export const RULE_ID_PATTERN = /^[A-Za-z0-9][A-Za-z0-9_.-]*$/;
+
+function part(value: string): string {
+  return value
+    .toUpperCase()
+    .replace(/[^A-Z0-9]+/g, "-")
+    .replace(/^-+|-+$/g, "");
+}
+
+/**
+ * A URL whose host is a literal public address (tcp://34.120.18.9:9000,
+ * http://[2600:1f18::1]/v1) has no name to attribute, so it stays an
+ * unclassified external endpoint until someone says who runs it.
+ */
+function isPublicIpLiteral(url: string): boolean {
+  const host = url.match(/^[a-z][a-z0-9+.-]*:\/\/(?:[^@/]*@)?(\[[^\]]+\]|[^/:?#]+)/i)?.[1];
+  if (!host) return false;
+  const ip = host.replace(/^\[|\]$/g, "");
+  return parseIp(ip) !== null && !isPrivateIp(ip);
+}
+
+/** Derive the built-in rule ID for an entry found by `language`'s analyzer */
+export function ruleIdFor(entry: DependencyEntry, language?: string): string {
+  switch (entry.kind) {
+    case "package":
+      return `TW-PKG-${part(entry.ecosystem)}`;
+    case "api":
+      if (entry.provider) return `TW-${part(entry.provider)}-API`;
+      return isPublicIpLiteral(entry.url) ? "TW-UNCLASSIFIED-ENDPOINT" : "TW-HTTP-URL";
+    case "sdk":
+      return language
+        ? `TW-${part(entry.provider)}-SDK-${part(language)}`
+        : `TW-${part(entry.provider)}-SDK`;
+    case "infrastructure":
+      return `TW-INFRA-${part(entry.type)}`;
+    case "webhook":
+      return `TW-WEBHOOK-${part(entry.direction)}`;
+  }
+}
+
+/** Fill in `rule_id` on entries that don't already carry a valid one */
+export function assignRuleIds(entries: DependencyEntry[], language?: string): void {
+  for (const entry of entries) {
+    if (entry.rule_id && RULE_ID_PATTERN.test(entry.rule_id)) continue;
+    entry.rule_id = ruleIdFor(entry, language);
+  }
+}
+
+interface CompiledSetting {
+  re: RegExp;
+  setting: RuleSetting;
+  /** Non-wildcard characters — more literal keys are more specific */
+  specificity: number;
+}
+
+const CATEGORY_PREFIX = "category:";
+
+function compileSettings(
+  settings: Record<string, RuleSetting>,
+  scope: "rule" | "category" = "rule",
+): CompiledSetting[] {
+  return Object.entries(settings)
+    .filter(([key]) => key.toLowerCase().startsWith(CATEGORY_PREFIX) === (scope === "category"))
+    .map(([key, setting]) => (scope === "category" ? [key.slice(CATEGORY_PREFIX.length), setting] : [key, setting]) as const)
+    .map(([key, setting]) => ({
+      re: new RegExp(
+        `^${key.split("*").map((s) => s.replace(/[.+?^${}()|[\]\\]/g, "\\$&")).join(".*")}$`,
+        "i",
+      ),
+      setting,
+      specificity: key.replace(/\*/g, "").length,
+    }))
+    .sort((a, b) => b.specificity - a.specificity);
+}
+
+/** Resolve the setting for one rule ID, or undefined when no key matches */
+export function ruleSettingFor(
+  ruleId: string,
+  settings: Record<string, RuleSetting>,
+): RuleSetting | undefined {
+  return compileSettings(settings).find((s) => s.re.test(ruleId))?.setting;
+}
+
+/**
+ * Apply `rule_settings`: drop entries whose rule (or category) is off and
+ * record the configured severity on the rest. Entries without a matching key
+ * keep their default severity: absent, meaning "info", or the one a
+ * high-sensitivity category set (see categories.ts).
+ */
+export function applyRuleSettings(
+  entries: DependencyEntry[],
+  settings: Record<string, RuleSetting>,
+): DependencyEntry[] {
+  const compiled = compileSettings(settings);
+  const byCategory = compileSettings(settings, "category");
+  if (compiled.length === 0 && byCategory.length === 0) return entries;
+
+  const cache = new Map<string, RuleSetting | undefined>();
+  const categoryCache = new Map<string, RuleSetting | undefined>();
+  const result: DependencyEntry[] = [];
+  for (const entry of entries) {
+    const id = entry.rule_id ?? ruleIdFor(entry);
+    if (!cache.has(id)) cache.set(id, compiled.find((s) => s.re.test(id))?.setting);
+    let setting = cache.get(id);
+    const category = "category" in entry ? entry.category : undefined;
+    if (setting === undefined && category) {
+      if (!categoryCache.has(category)) {
+        categoryCache.set(category, byCategory.find((s) => s.re.test(category))?.setting);
+      }
+      setting = categoryCache.get(category);
+    }
+    if (setting === "off") continue;
+    if (setting === "info") delete entry.severity;
+    else if (setting) entry.severity = setting;
+    result.push(entry);
+  }
+  return result;
+}
@@ -0,0 +1,293 @@
+/**
+ * @module runtime
+ *
+ * Shared model for runtime observations — outbound connections seen in live
+ * traffic rather than found in code. Observation sources (the eBPF agent and
+ * friends) produce RuntimeObservation records; this module classifies their
+ * destinations against the vendor catalog and folds them into a TDM with the
+ * same shape as a static scan, so the two can be diffed and merged.
+ */
+
+import { createReadStream } from "node:fs";
+import { createInterface } from "node:readline";
+import { createGunzip } from "node:zlib";
+import type { TDM, TDMApi, TDMRuntimeSdk } from "@thirdwatch/tdm";
+import { TDM_SCHEMA_VERSION } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "./registry.js";
+import { normalizeVendorName, vendorNameIndex } from "./aliases.js";
+import { extractHost, matchesDomain } from "./first-party.js";
+import { ruleIdFor } from "./rule-ids.js";
+import { createIpRangeMatcher } from "./ip-ranges.js";
+import type { IpRangeMatcher } from "./ip-ranges.js";
+import { createUserAgentMatcher } from "./user-agents.js";
+import type { UserAgentMatcher } from "./user-agents.js";
+
+const SCANNER_VERSION = "0.1.0";
+
+export interface RuntimeObservation {
+  /** Observation source, e.g. "agent" */
+  source: string;
+  /** Destination hostname (from DNS, TLS SNI, or a Host header) */
+  host?: string;
+  /** Destination IP address */
+  ip?: string;
+  /** Logical destination name when no address is known (OpenTelemetry peer.service) */
+  service?: string;
+  port?: number;
+  /** HTTP method, when the source sees requests (proxy) */
+  method?: string;
+  /** Normalized request path, when the source sees requests (proxy) */
+  path?: string;
+  /** Name of the process that opened the connection */
+  process?: string;
+  /** Client address or workload ID, for sources that see many clients (logs) */
+  client?: string;
+  /** User-Agent header, when the source sees requests (proxy, Envoy, OpenTelemetry) */
+  user_agent?: string;
+  /** ISO 8601 timestamp */
+  timestamp: string;
+  /** Number of connections this record stands for (default: 1) */
+  count?: number;
+  /** When the last of `count` connections was seen (default: `timestamp`, the first) */
+  last_seen?: string;
+}
+
+// ---------------------------------------------------------------------------
+// Vendor classification
+// ---------------------------------------------------------------------------
+
+export type VendorMatcher = (host: string) => string | null;
+
+/**
+ * Build a host → provider matcher from the catalog's known_api_base_urls and
+ * domains. The longest matching pattern wins, so "api.openai.azure.com" can
+ * belong to a different entry than "azure.com". Dot-less names (service
+ * and vendor names rather than hosts) resolve through slugs, display names,
+ * and aliases, so "Amazon SES" matches aws-ses.
+ */
+export function createVendorMatcher(registry: SDKRegistryEntry[]): VendorMatcher {
+  const patterns: Array<{ pattern: string; provider: string }> = [];
+  const names = vendorNameIndex(registry);
+  for (const entry of registry) {
+    for (const url of entry.known_api_base_urls ?? []) {
+      const host = extractHost(url);
+      if (host) patterns.push({ pattern: host, provider: entry.provider });
+    }
+    for (const domain of entry.domains ?? []) {
+      patterns.push({ pattern: domain, provider: entry.provider });
+    }
+  }
+  patterns.sort((a, b) => b.pattern.length - a.pattern.length);
+
+  const cache = new Map<string, string | null>();
+  return (host: string) => {
+    const h = host.toLowerCase().replace(/\.$/, "");
+    if (!cache.has(h)) {
+      cache.set(
+        h,
+        h.includes(".")
+          ? patterns.find((p) => matchesDomain(h, p.pattern))?.provider ?? null
+          : names.get(normalizeVendorName(h)) ?? null,
+      );
+    }
+    return cache.get(h)!;
+  };
+}
+
+// ---------------------------------------------------------------------------
+// Address helpers
+// ---------------------------------------------------------------------------
+
+// Defined beside the IP parsers so rule IDs can use it without importing this module
+export { isPrivateIp } from "./ip-ranges.js";
+
+const ID_SEGMENT_RE = /^(?:\d+|[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}|[0-9a-f]{16,}|(?=[A-Za-z0-9_-]*\d)[A-Za-z0-9_-]{20,})$/i;
+
+/**
+ * Collapse identifiers in a request path so one endpoint doesn't explode into
+ * thousands of entries: "/v1/customers/cus_8FkLmN2pQrStUvWx/charges?x=1"
+ * → "/v1/customers/{id}/charges". The query string is dropped.
+ */
+export function normalizePath(path: string): string {
+  const bare = path.split(/[?#]/)[0] ?? "";
+  const normalized = bare
+    .split("/")
+    .map((seg) => (ID_SEGMENT_RE.test(seg) ? "{id}" : seg))
+    .join("/");
+  return normalized === "/" ? "" : normalized.replace(/\/+$/, "");
+}
+
+/** URL an observation is reported under, e.g. "https://api.stripe.com" */
+export function observationUrl(obs: RuntimeObservation): string | null {
+  const target = obs.host ?? obs.ip;
+  if (!target) return obs.service ? `service://${obs.service}` : null;
+  const host = target.includes(":") ? `[${target}]` : target;
+  const path = obs.path ?? "";
+  switch (obs.port) {
+    case 443:
+    case undefined:
+      return `https://${host}${path}`;
+    case 80:
+      return `http://${host}${path}`;
+    default:
+      return path ? `http://${host}:${obs.port}${path}` : `tcp://${host}:${obs.port}`;
+  }
+}
+
+// ---------------------------------------------------------------------------
+// Aggregation
+// ---------------------------------------------------------------------------
+
+const HTTP_METHODS = new Set(["GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "CONNECT", "TRACE"]);
+
+/**
+ * Fold observations into one API entry per method and URL. Entries with a
+ * hostname are high confidence; bare IPs are medium since the destination
+ * could not be named, and are attributed through `matchIp` (catalog
+ * ip_ranges) when given. User-Agents are attributed to SDKs through
+ * `matchUserAgent` (catalog user_agents) when given; a recognized SDK also
+ * names the vendor of a host the catalog doesn't list.
+ */
+export function aggregateObservations(
+  observations: RuntimeObservation[],
+  matchVendor: VendorMatcher,
+  matchIp?: IpRangeMatcher,
+  matchUserAgent?: UserAgentMatcher,
+): TDMApi[] {
+  const byUrl = new Map<
+    string,
+    { api: TDMApi; processes: Set<string>; clients: Set<string>; sdks: Map<string, TDMRuntimeSdk> }
+  >();
+  const countSdk = (sdks: Map<string, TDMRuntimeSdk>, obs: RuntimeObservation, provider: string | null | undefined) => {
+    const match = obs.user_agent && matchUserAgent ? matchUserAgent(obs.user_agent, provider) : null;
+    if (!match) return;
+    const key = `${match.ecosystem ?? ""}:${match.sdk_package}@${match.version ?? ""}`;
+    const sdk = sdks.get(key) ?? {
+      sdk_package: match.sdk_package,
+      ...(match.ecosystem ? { ecosystem: match.ecosystem } : {}),
+      ...(match.version ? { version: match.version } : {}),
+      count: 0,
+    };
+    sdk.count += obs.count ?? 1;
+    sdks.set(key, sdk);
+  };
+
+  for (const obs of observations) {
+    const url = observationUrl(obs);
+    if (!url) continue;
+    const count = obs.count ?? 1;
+    const method = HTTP_METHODS.has(obs.method ?? "") ? (obs.method as TDMApi["method"]) : undefined;
+    const key = `${method ?? "ANY"}:${url}`;
+
+    const existing = byUrl.get(key);
+    if (existing) {
+      const rt = existing.api.runtime!;
+      rt.count += count;
+      existing.api.usage_count = rt.count;
+      if (obs.timestamp < rt.first_seen) rt.first_seen = obs.timestamp;
+      if ((obs.last_seen ?? obs.timestamp) > rt.last_seen) rt.last_seen = obs.last_seen ?? obs.timestamp;
+      if (obs.process) existing.processes.add(obs.process);
+      if (obs.client) existing.clients.add(obs.client);
+      countSdk(existing.sdks, obs, existing.api.provider);
+      continue;
+    }
+
+    const provider =
+      (obs.host ? matchVendor(obs.host) : obs.ip && matchIp ? matchIp(obs.ip) : null) ??
+      (obs.service ? matchVendor(obs.service) : null) ??
+      (obs.user_agent && matchUserAgent ? matchUserAgent(obs.user_agent)?.provider ?? null : null);
+    const api: TDMApi = {
+      url,
+      ...(method ? { method } : {}),
+      provider,
+      runtime: {
+        source: obs.source,
+        count,
+        first_seen: obs.timestamp,
+        last_seen: obs.last_seen ?? obs.timestamp,
+      },
+      locations: [{ file: `runtime:${obs.source}`, line: 1 }],
+      usage_count: count,
+      confidence: obs.host ? "high" : "medium",
+    };
+    api.rule_id = ruleIdFor({ kind: "api", ...api });
+    const sdks = new Map<string, TDMRuntimeSdk>();
+    countSdk(sdks, obs, provider);
+    byUrl.set(key, {
+      api,
+      processes: new Set(obs.process ? [obs.process] : []),
+      clients: new Set(obs.client ? [obs.client] : []),
+      sdks,
+    });
+  }
+
+  return [...byUrl.values()].map(({ api, processes, clients, sdks }) => {
+    if (processes.size > 0) api.runtime!.processes = [...processes].sort().slice(0, 100);
+    if (clients.size > 0) api.runtime!.clients = [...clients].sort().slice(0, 100);
+    if (sdks.size > 0) {
+      api.runtime!.sdks = [...sdks.values()]
+        .sort((a, b) => b.count - a.count || a.sdk_package.localeCompare(b.sdk_package))
+        .slice(0, 100);
+    }
+    return api;
+  });
+}
+
+export interface RuntimeTDMContext {
+  /** Wall-clock observation window */
+  duration: number;
+  catalogVersion?: string;
+}
+
+/** Build a TDM from runtime observations — only `apis` is populated */
+export function buildRuntimeTDM(
+  observations: RuntimeObservation[],
+  registry: SDKRegistryEntry[],
+  context: RuntimeTDMContext,
+): TDM {
+  const apis = aggregateObservations(
+    observations,
+    createVendorMatcher(registry),
+    createIpRangeMatcher(registry),
+    createUserAgentMatcher(registry),
+  );
+  const metadata: TDM["metadata"] = {
+    scan_timestamp: new Date().toISOString(),
+    scanner_version: SCANNER_VERSION,
+    languages_detected: [],
+    total_dependencies_found: apis.length,
+    scan_duration_ms: context.duration,
+  };
+  if (context.catalogVersion !== undefined) {
+    metadata.catalog_version = context.catalogVersion;
+  }
+  return {
+    version: TDM_SCHEMA_VERSION,
+    metadata,
+    packages: [],
+    apis,
+    sdks: [],
+    infrastructure: [],
+    webhooks: [],
+  };
+}
+
+// ---------------------------------------------------------------------------
+// Log input — shared by the log ingestion sources
+// ---------------------------------------------------------------------------
+
+/**
+ * Stream a log file line by line; `.gz` files (as exported to S3 / GCS) are
+ * decompressed on the fly. Blank lines are skipped.
+ */
+export async function readLogLines(
+  path: string,
+  onLine: (line: string) => void,
+): Promise<void> {
+  const file = createReadStream(path);
+  const input = path.endsWith(".gz") ? file.pipe(createGunzip()) : file;
+  const lines = createInterface({ input, crlfDelay: Infinity });
+  for await (const line of lines) {
+    if (line.trim()) onLine(line);
+  }
+}
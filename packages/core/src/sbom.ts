@@ -0,0 +1,245 @@
+/**
+ * @module sbom
+ *
+ * SBOM ingestion behind `thirdwatch ingest sbom`, for services whose source
+ * we can't scan but whose SBOMs we already collect. Components are read from
+ *
+ *   CycloneDX JSON   components[] (nested components too), by purl
+ *   CycloneDX XML    <purl> elements
+ *   SPDX JSON        packages[].externalRefs of type purl
+ *   SPDX tag-value   ExternalRef: PACKAGE-MANAGER purl ...
+ *
+ * Every component with a package URL becomes a TDM package, so `outdated` and
+ * the dependency views work on the result as on a scan. Components the
+ * catalog names as a vendor SDK (`com.stripe:stripe-java`, `@aws-sdk/*`) are
+ * reported as SDK dependencies as well, located at their line in the SBOM.
+ * An SBOM says what is installed, not what is called: there are no API call
+ * sites, and an SDK that is bundled but unused is still listed.
+ */
+
+import { createHash } from "node:crypto";
+import type { TDM } from "@thirdwatch/tdm";
+import type { DependencyEntry } from "./plugin.js";
+import type { SDKRegistryEntry } from "./registry.js";
+import { buildTDM } from "./build-tdm.js";
+import { fingerprintFindings } from "./fingerprint.js";
+
+export type SbomFormat = "cyclonedx-json" | "cyclonedx-xml" | "spdx-json" | "spdx-tag";
+
+export interface SbomComponent {
+  name: string;
+  version: string;
+  /** TDM ecosystem: npm, pypi, go, maven, cargo, packagist, rubygems, nuget, ... */
+  ecosystem: string;
+  purl: string;
+}
+
+export interface ParsedSbom {
+  format: SbomFormat;
+  /** The component the SBOM describes, e.g. "checkout-service" */
+  subject?: string;
+  components: SbomComponent[];
+  /** Components without a package URL, which can't be matched */
+  skipped: number;
+}
+
+// purl types whose TDM ecosystem name differs
+const PURL_ECOSYSTEMS: Record<string, string> = {
+  golang: "go",
+  composer: "packagist",
+  gem: "rubygems",
+};
+
+/** pkg:npm/%40aws-sdk/client-s3@3.400.0 → @aws-sdk/client-s3 3.400.0 (npm) */
+export function parsePurl(purl: string): SbomComponent | null {
+  const m = purl.match(/^pkg:([a-z0-9.+-]+)\/([^@?#]+)(?:@([^?#]+))?/i);
+  if (!m) return null;
+  const type = m[1]!.toLowerCase();
+  const segments = m[2]!.split("/").map((s) => decodeURIComponent(s));
+  const version = m[3] ? decodeURIComponent(m[3]) : "";
+  const ecosystem = PURL_ECOSYSTEMS[type] ?? type;
+  let name: string;
+  if (type === "maven") {
+    // namespace is the group id
+    name = segments.length > 1 ? `${segments.slice(0, -1).join(".")}:${segments.at(-1)}` : segments[0]!;
+  } else if (type === "pypi") {
+    name = segments.join("/").toLowerCase().replace(/[_.]+/g, "-");
+  } else {
+    name = segments.join("/");
+  }
+  return { name, version, ecosystem, purl };
+}
+
+interface CycloneDxComponent {
+  purl?: string;
+  components?: CycloneDxComponent[];
+}
+
+function cycloneDxComponents(list: CycloneDxComponent[] | undefined, out: string[], skipped: { n: number }): void {
+  for (const c of list ?? []) {
+    if (c.purl) out.push(c.purl);
+    else skipped.n++;
+    cycloneDxComponents(c.components, out, skipped);
+  }
+}
+
+/** Detect the format and read the components of an SBOM */
+export function parseSbom(text: string): ParsedSbom {
+  const trimmed = text.trimStart();
+  const purls: string[] = [];
+  const skipped = { n: 0 };
+  let format: SbomFormat;
+  let subject: string | undefined;
+
+  if (trimmed.startsWith("{")) {
+    const doc = JSON.parse(text) as {
+      bomFormat?: string;
+      metadata?: { component?: { name?: string } };
+      components?: CycloneDxComponent[];
+      spdxVersion?: string;
+      name?: string;
+      documentDescribes?: string[];
+      packages?: Array<{ SPDXID?: string; name?: string; externalRefs?: Array<{ referenceType?: string; referenceLocator?: string }> }>;
+    };
+    if (doc.bomFormat === "CycloneDX") {
+      format = "cyclonedx-json";
+      subject = doc.metadata?.component?.name;
+      cycloneDxComponents(doc.components, purls, skipped);
+    } else if (doc.spdxVersion) {
+      format = "spdx-json";
+      const described = new Set(doc.documentDescribes ?? []);
+      for (const pkg of doc.packages ?? []) {
+        // The package the document describes is the service itself
+        if (pkg.SPDXID && described.has(pkg.SPDXID)) {
+          subject ??= pkg.name;
+          continue;
+        }
+        const purl = pkg.externalRefs?.find((r) => r.referenceType === "purl")?.referenceLocator;
+        if (purl) purls.push(purl);
+        else skipped.n++;
+      }
+      subject ??= doc.name;
+    } else {
+      throw new Error("Unrecognized JSON SBOM: expected CycloneDX (bomFormat) or SPDX (spdxVersion)");
+    }
+  } else if (trimmed.startsWith("<")) {
+    if (!/<bom[\s>]/.test(text)) throw new Error("Unrecognized XML SBOM: expected a CycloneDX <bom> document");
+    format = "cyclonedx-xml";
+    const metadata = text.match(/<metadata>[\s\S]*?<component[^>]*>[\s\S]*?<name>([^<]+)<\/name>/);
+    if (metadata) subject = metadata[1]!.trim();
+    for (const m of text.matchAll(/<purl>\s*([^<\s]+)\s*<\/purl>/g)) purls.push(m[1]!.replace(/&amp;/g, "&"));
+    // Skip the subject's own purl, which sits in <metadata>
+    const metadataPurl = text.match(/<metadata>[\s\S]*?<\/metadata>/)?.[0].match(/<purl>\s*([^<\s]+)/)?.[1];
+    if (metadataPurl && purls.includes(metadataPurl)) purls.splice(purls.indexOf(metadataPurl), 1);
+  } else if (/^SPDXVersion:/m.test(text)) {
+    format = "spdx-tag";
+    subject = text.match(/^DocumentName:\s*(.+)$/m)?.[1]?.trim();
+    for (const m of text.matchAll(/^ExternalRef:\s*PACKAGE[-_]MANAGER\s+purl\s+(\S+)/gm)) purls.push(m[1]!);
+    const packages = text.match(/^PackageName:/gm)?.length ?? 0;
+    skipped.n = Math.max(0, packages - purls.length);
+  } else {
+    throw new Error("Unrecognized SBOM: expected CycloneDX JSON or XML, or SPDX JSON or tag-value");
+  }
+
+  const components: SbomComponent[] = [];
+  for (const purl of purls) {
+    const component = parsePurl(purl);
+    if (component) components.push(component);
+    else skipped.n++;
+  }
+  return { format, ...(subject ? { subject } : {}), components, skipped: skipped.n };
+}
+
+/** Provider a package belongs to per the catalog; globs such as "@aws-sdk/*" and Go major suffixes match */
+function packageMatcher(registry: SDKRegistryEntry[]): (ecosystem: string, name: string) => SDKRegistryEntry | undefined {
+  const byEcosystem = new Map<string, Array<{ pattern: string; entry: SDKRegistryEntry }>>();
+  for (const entry of registry) {
+    for (const [ecosystem, patterns] of Object.entries(entry.patterns)) {
+      if (!Array.isArray(patterns)) continue;
+      const list = byEcosystem.get(ecosystem) ?? [];
+      for (const p of patterns) list.push({ pattern: p.package, entry });
+      byEcosystem.set(ecosystem, list);
+    }
+  }
+  return (ecosystem, name) => {
+    const patterns = byEcosystem.get(ecosystem) ?? [];
+    const exact = patterns.find((p) => p.pattern === name);
+    if (exact) return exact.entry;
+    return patterns.find(({ pattern }) =>
+      pattern.endsWith("*")
+        ? name.startsWith(pattern.slice(0, -1))
+        : // github.com/stripe/stripe-go/v78, github.com/aws/aws-sdk-go-v2/service/s3
+          ecosystem === "go" && name.startsWith(`${pattern}/`),
+    )?.entry;
+  };
+}
+
+/** 1-based line of the first occurrence of `needle`, or 1 */
+function lineOf(lines: string[], needle: string): number {
+  const i = lines.findIndex((l) => l.includes(needle));
+  return i === -1 ? 1 : i + 1;
+}
+
+export interface SbomTDMOptions {
+  /** Path of the SBOM as it should appear in locations, e.g. "sboms/checkout.cdx.json" */
+  file: string;
+  /** Service name for metadata.repository (default: the SBOM's subject) */
+  service?: string;
+  duration?: number;
+  catalogVersion?: string;
+}
+
+/** A TDM of the packages an SBOM lists and the vendor SDKs among them */
+export function buildSbomTDM(text: string, registry: SDKRegistryEntry[], options: SbomTDMOptions): { tdm: TDM; sbom: ParsedSbom } {
+  const sbom = parseSbom(text);
+  const lines = text.split("\n");
+  const match = packageMatcher(registry);
+  const entries: DependencyEntry[] = [];
+  const seen = new Set<string>();
+
+  for (const c of sbom.components) {
+    const key = `${c.ecosystem}\0${c.name}\0${c.version}`;
+    if (seen.has(key)) continue;
+    seen.add(key);
+    const location = { file: options.file, line: lineOf(lines, c.purl) };
+    entries.push({
+      kind: "package",
+      name: c.name,
+      ecosystem: c.ecosystem,
+      current_version: c.version || "unknown",
+      manifest_file: options.file,
+      locations: [location],
+      usage_count: 1,
+      confidence: "high",
+    });
+    const entry = match(c.ecosystem, c.name);
+    if (entry) {
+      entries.push({
+        kind: "sdk",
+        provider: entry.provider,
+        ...(entry.category ? { category: entry.category } : {}),
+        sdk_package: c.name,
+        locations: [location],
+        usage_count: 1,
+        // Installed, but not necessarily called
+        confidence: "medium",
+      });
+    }
+  }
+  fingerprintFindings(entries);
+
+  const service = options.service ?? sbom.subject;
+  const tdm = buildTDM(entries, {
+    root: "",
+    plugins: [],
+    duration: options.duration ?? 0,
+    ...(service ? { repository: service } : {}),
+    ...(options.catalogVersion ? { catalogVersion: options.catalogVersion } : {}),
+    artifact: {
+      type: "sbom",
+      reference: options.file,
+      digest: `sha256:${createHash("sha256").update(text).digest("hex")}`,
+    },
+  });
+  return { tdm, sbom };
+}
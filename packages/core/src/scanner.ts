@@ -1,15 +1,50 @@
 import { readFile, stat } from "node:fs/promises";
 import { availableParallelism } from "node:os";
-import { basename, extname, relative } from "node:path";
+import { basename, extname } from "node:path";
 import fg from "fast-glob";
-import type { TDM } from "@thirdwatch/tdm";
+import type { TDM, TDMArtifact, TDMScanStage } from "@thirdwatch/tdm";
 import type { LanguageAnalyzerPlugin, DependencyEntry, AnalyzerContext } from "./plugin.js";
 import { buildTDM } from "./build-tdm.js";
 import { mergeManifestAndLockfile } from "./lockfile.js";
 import { loadConfig, loadIgnore } from "./config.js";
 import { loadEnvFile, buildEnvMap } from "./resolve.js";
 import { loadSDKRegistry, buildRegistryMaps } from "./registry.js";
-import type { RegistryMaps } from "./registry.js";
+import type { RegistryMaps, SDKRegistryEntry } from "./registry.js";
+import { annotateSecrets, resolveSecretAges } from "./secrets.js";
+import type { PendingSecret } from "./secrets.js";
+import { classifyFirstParty } from "./first-party.js";
+import { applyCatalogCategories } from "./categories.js";
+import { classifyUsageIntents } from "./intent.js";
+import { flagUnusedIntegrations } from "./unused.js";
+import { createCompatibleEndpointResolver } from "./compatible-endpoints.js";
+import { createGatewayChecker } from "./gateways.js";
+import { labelDataFlows } from "./data-flow.js";
+import { detectOidcIssuers, OIDC_CONFIG_EXTENSIONS } from "./oidc.js";
+import { detectMapsKeys } from "./maps.js";
+import { detectConfigEndpoints } from "./config-endpoints.js";
+import { annotateEnvironments } from "./environments.js";
+import { fingerprintFindings, disambiguateFingerprints } from "./fingerprint.js";
+import { suggestRemediations } from "./remediation.js";
+import { createVendorMatcher } from "./runtime.js";
+import { detectModelDownloads, isBuildScript } from "./model-hub.js";
+import { detectGeoipDownloads, isGeoipConfig } from "./geoip.js";
+import { detectTerraformBackends, isTerraformConfig } from "./terraform.js";
+import { detectFrontendAssets, isFrontendAsset } from "./frontend.js";
+import { enrichUnknownApis, catalogHosts } from "./enrich.js";
+import type { EnrichmentDataset } from "./enrich.js";
+import { classifyUnmatchedWithLLM } from "./llm-classify.js";
+import type { LLMClassificationConfig } from "./llm-classify.js";
+import { startExecDetector } from "./detectors.js";
+import { compileRules, applyCustomRules, RULE_CONFIG_EXTENSIONS } from "./custom-rules.js";
+import type { CustomRule } from "./custom-rules.js";
+import type { ExecDetector } from "./detectors.js";
+import type { CatalogBundle } from "./catalog.js";
+import { assignRuleIds, applyRuleSettings } from "./rule-ids.js";
+import { annotateServices } from "./services.js";
+import { attachSnippets } from "./snippets.js";
+import { applyVendorAliases, canonicalizeProviders } from "./aliases.js";
+import { normalizeEntryPaths, relativePosix } from "./paths.js";
+import { detectFallbackEndpoints, isFallbackCandidate, looksBinary } from "./fallback.js";
 
 // ---------------------------------------------------------------------------
 // ScanOptions — public configuration for scan()
@@ -32,6 +67,34 @@ export interface ScanOptions {
   concurrency?: number;
   /** Path to the registries directory for SDK registry YAML files */
   registriesDir?: string;
+  /** Remote catalog bundle to use instead of the registries directory */
+  catalog?: CatalogBundle;
+  /** Walk git history to date hardcoded secrets (default: true) */
+  secretHistory?: boolean;
+  /** Look up RDAP / ASN / TLS ownership for hosts not in the catalog (default: false) */
+  enrichUnknown?: boolean;
+  /** Enrichment to use before, or offline instead of, lookups (see `buildEnrichmentDataset`) */
+  enrichmentDataset?: EnrichmentDataset;
+  /** Ask an LLM to classify unmatched dependencies (default: false) */
+  llmClassify?: boolean;
+  /**
+   * Endpoint for `llmClassify` chosen by the caller, with its API key.
+   * Without it, `llm_classification` in .thirdwatch.yml names the endpoint
+   * and no key is sent, since the scanned repository controls that file.
+   */
+  llm?: LLMClassificationConfig;
+  /**
+   * Run custom detectors declared under `detectors` in .thirdwatch.yml
+   * (default: false). Detectors are arbitrary commands, so only enable this
+   * for a config you trust — never one taken from an untrusted pull request.
+   */
+  detectors?: boolean;
+  /** Extract URLs and hosts from files no analyzer supports (default: true) */
+  fallback?: boolean;
+  /** Source lines to attach either side of each location (default: `context_lines` in .thirdwatch.yml, else 0) */
+  contextLines?: number;
+  /** The archive or image `root` was extracted from (see `openScanTarget`) */
+  artifact?: TDMArtifact;
 }
 
 // ---------------------------------------------------------------------------
@@ -46,10 +109,20 @@ export interface ScanResult {
 }
 
 export interface ScanError {
+  /** File that failed; empty when the failure isn't tied to one file */
   filePath: string;
   error: string;
+  /** What was running when it failed */
+  stage: TDMScanStage;
+  /** Plugin language, custom detector name, or post-processing step */
+  detector?: string;
 }
 
+// Most errors a TDM lists; the ScanResult keeps all of them
+const MAX_TDM_ERRORS = 1000;
+
+const errorMessage = (err: unknown) => (err instanceof Error ? err.message : String(err));
+
 // ---------------------------------------------------------------------------
 // Manifest file patterns recognized across languages
 // ---------------------------------------------------------------------------
@@ -134,19 +207,56 @@ export async function scan(options: ScanOptions): Promise<ScanResult> {
     resolveEnv = true,
     useProcessEnv = false,
     registriesDir,
+    secretHistory = true,
+    enrichUnknown = false,
+    llmClassify = false,
+    detectors: runDetectors = false,
+    fallback: runFallback = true,
   } = options;
 
   // Load config
   const config = await loadConfig(root, options.configFile);
+  const llm: LLMClassificationConfig | undefined =
+    options.llm ??
+    (config.llm_classification ? { endpoint: config.llm_classification.endpoint, model: config.llm_classification.model } : undefined);
+  if (llmClassify && !llm) {
+    throw new Error("LLM classification requires an endpoint: pass one in the scan options or add llm_classification to .thirdwatch.yml");
+  }
   const maxFileSizeBytes = (config.max_file_size_mb ?? 1) * 1024 * 1024;
+  const contextLines = options.contextLines ?? config.context_lines ?? 0;
+
+  // A file or detector that throws is recorded and skipped, so the scan still
+  // returns everything else it found
+  const errors: ScanError[] = [];
+  const isolate = (failure: Omit<ScanError, "error">, fn: () => void): void => {
+    try {
+      fn();
+    } catch (err) {
+      errors.push({ ...failure, error: errorMessage(err) });
+    }
+  };
+  const isolateAsync = async <T>(failure: Omit<ScanError, "error">, fallback: T, fn: () => Promise<T>): Promise<T> => {
+    try {
+      return await fn();
+    } catch (err) {
+      errors.push({ ...failure, error: errorMessage(err) });
+      return fallback;
+    }
+  };
 
-  // Build extension → plugin map
-  const pluginMap = new Map<string, LanguageAnalyzerPlugin>();
-  for (const plugin of plugins) {
+  // Build extension → plugins map (custom detectors share extensions with built-ins)
+  const pluginMap = new Map<string, LanguageAnalyzerPlugin[]>();
+  const addPlugin = (plugin: LanguageAnalyzerPlugin) => {
     for (const ext of plugin.extensions) {
-      pluginMap.set(ext, plugin);
+      pluginMap.set(ext, [...(pluginMap.get(ext) ?? []), plugin]);
     }
-  }
+  };
+  for (const plugin of plugins) addPlugin(plugin);
+  const detectorConfigs = runDetectors ? config.detectors ?? [] : [];
+  const detectorExtensions = new Set(detectorConfigs.flatMap((d) => d.extensions));
+
+  // Declarative rules run on every analyzed file plus config files
+  const rules = compileRules((config.rules ?? []) as CustomRule[]);
 
   // Load ignore patterns
   const ig = await loadIgnore(root);
@@ -166,13 +276,25 @@ export async function scan(options: ScanOptions): Promise<ScanResult> {
 
   // Load SDK registry and build per-plugin lookup maps
   const registryMapsByPlugin = new Map<LanguageAnalyzerPlugin, RegistryMaps>();
-  if (registriesDir) {
-    const registry = await loadSDKRegistry(registriesDir);
+  let registry: SDKRegistryEntry[] = [];
+  if (options.catalog || registriesDir) {
+    registry = options.catalog?.entries ?? (await loadSDKRegistry(registriesDir!));
     for (const plugin of plugins) {
       const ecosystem = LANGUAGE_ECOSYSTEMS[plugin.language] ?? plugin.language;
       registryMapsByPlugin.set(plugin, buildRegistryMaps(registry, ecosystem));
     }
   }
+  // The organization's own names and hosts for vendors, so findings use one slug per vendor
+  registry = applyVendorAliases(registry, config.vendor_aliases);
+
+  // OpenAI- and S3-compatible clients are reported under the provider behind their base URL
+  const resolveCompatibleEndpoints = createCompatibleEndpointResolver(registry, resolvedEnv, config.gateways);
+  const catalogCategories = new Map(registry.flatMap((e) => (e.category ? [[e.provider, e.category] as const] : [])));
+  // Vendors that must be reached through an egress gateway
+  const checkGateways = createGatewayChecker(config.gateways ?? [], {
+    env: resolvedEnv,
+    categoryOf: (provider) => catalogCategories.get(provider),
+  });
 
   // Discover all files
   const allFiles = await fg.glob("**/*", {
@@ -185,7 +307,7 @@ export async function scan(options: ScanOptions): Promise<ScanResult> {
 
   // Apply ignore filter using relative paths
   const filteredFiles = allFiles.filter((f) => {
-    const rel = relative(root, f);
+    const rel = relativePosix(root, f);
     return !ig.ignores(rel);
   });
 
@@ -199,7 +321,12 @@ export async function scan(options: ScanOptions): Promise<ScanResult> {
   });
 
   // Source files: only files with extensions matching a registered plugin
-  const sourceFiles = filteredFiles.filter((f) => pluginMap.has(extname(f)));
+  const sourceFiles = filteredFiles.filter(
+    (f) =>
+      pluginMap.has(extname(f)) ||
+      detectorExtensions.has(extname(f)) ||
+      (rules.length > 0 && RULE_CONFIG_EXTENSIONS.has(extname(f))),
+  );
 
   // Collect entries from manifests (parallel across plugins)
   const manifestResults = await Promise.all(
@@ -207,9 +334,22 @@ export async function scan(options: ScanOptions): Promise<ScanResult> {
       .filter((p): p is LanguageAnalyzerPlugin & { analyzeManifests: NonNullable<LanguageAnalyzerPlugin["analyzeManifests"]> } =>
         p.analyzeManifests != null,
       )
-      .map((p) => p.analyzeManifests!(manifestFiles, root)),
+      .map(async (p) => {
+        try {
+          return await p.analyzeManifests(manifestFiles, root);
+        } catch {
+          // Retry manifest by manifest so one malformed file doesn't cost the rest
+          const perFile = await Promise.all(
+            manifestFiles.map((f) =>
+              isolateAsync({ filePath: f, stage: "manifests", detector: p.language }, [], () => p.analyzeManifests([f], root)),
+            ),
+          );
+          return perFile.flat();
+        }
+      }),
   );
   const manifestEntries = manifestResults.flat();
+  normalizeEntryPaths(manifestEntries);
 
   // Merge lockfile resolved versions into manifest constraint entries
   const LOCKFILE_NAMES = new Set([
@@ -228,12 +368,101 @@ export async function scan(options: ScanOptions): Promise<ScanResult> {
   );
 
   const mergedManifestEntries = mergeManifestAndLockfile(manifestOnly, lockfileOnly);
+  assignRuleIds(mergedManifestEntries);
+  fingerprintFindings(mergedManifestEntries);
+
+  // OIDC issuers and maps API keys declared in config files (application.yml,
+  // appsettings.json, ...), model and GeoIP database downloads baked into
+  // images (Dockerfile, entrypoint.sh, GeoIP.conf), Terraform state
+  // backends (main.tf), and browser-side scripts, iframes, and fonts loaded
+  // by templates and stylesheets (index.html, app.css), plus the vendor
+  // endpoints and keys an environment's config overlay points at
+  const matchVendor = createVendorMatcher(registry);
+  const manifestSet = new Set(manifestFiles);
+  const pendingSecrets: PendingSecret[] = [];
+  const isConfigFile = (f: string) =>
+    (OIDC_CONFIG_EXTENSIONS.has(extname(f)) || isBuildScript(f) || isTerraformConfig(f) || isFrontendAsset(f)) &&
+    !manifestSet.has(f);
+  const configResults = await Promise.all(
+    filteredFiles
+      .filter(isConfigFile)
+      .map(async (f) => {
+        try {
+          if ((await stat(f)).size > maxFileSizeBytes) return [];
+          const source = await readFile(f, "utf-8");
+          const rel = relativePosix(root, f);
+          const tagged = (entries: DependencyEntry[]) => {
+            annotateEnvironments(entries, source, rel);
+            fingerprintFindings(entries, source, rel);
+            suggestRemediations(entries, source, rel);
+            attachSnippets(entries, source, rel, contextLines);
+            return entries;
+          };
+          if (isTerraformConfig(f)) return tagged(detectTerraformBackends(source, rel));
+          if (isFrontendAsset(f)) return tagged(detectFrontendAssets(source, rel));
+          if (isBuildScript(f)) return tagged([...detectModelDownloads(source, rel), ...detectGeoipDownloads(source, rel)]);
+          if (isGeoipConfig(f)) return tagged(detectGeoipDownloads(source, rel));
+          const entries = [...detectOidcIssuers(source, rel), ...detectMapsKeys(source, rel)];
+          const covered = new Set(entries.flatMap((e) => e.locations.map((l) => l.line)));
+          entries.push(
+            ...detectConfigEndpoints(source, rel, matchVendor).filter((e) => !covered.has(e.locations[0]!.line)),
+          );
+          tagged(entries);
+          checkGateways(entries, source, rel);
+          pendingSecrets.push(...annotateSecrets(entries, source, rel));
+          return entries;
+        } catch (err) {
+          errors.push({ filePath: f, stage: "config", error: errorMessage(err) });
+          return [];
+        }
+      }),
+  );
+  const configEntries = configResults.flat();
+  assignRuleIds(configEntries);
+
+  // Files nothing above understands (Lua, Elixir, Makefiles, one-off
+  // configs) still get URLs, connection strings, and catalog hosts pulled
+  // out, so coverage thins out instead of stopping at the supported languages
+  const sourceSet = new Set(sourceFiles);
+  const fallbackTasks = (runFallback ? filteredFiles : [])
+    .filter((f) => !sourceSet.has(f) && !manifestSet.has(f) && !isConfigFile(f) && isFallbackCandidate(f))
+    .map((f) => () =>
+      isolateAsync({ filePath: f, stage: "fallback" }, [] as DependencyEntry[], async () => {
+        if ((await stat(f)).size > maxFileSizeBytes) return [];
+        const source = await readFile(f, "utf-8");
+        if (looksBinary(source)) return [];
+        const rel = relativePosix(root, f);
+        const entries = detectFallbackEndpoints(source, rel, matchVendor);
+        annotateEnvironments(entries, source, rel);
+        fingerprintFindings(entries, source, rel);
+        suggestRemediations(entries, source, rel);
+        checkGateways(entries, source, rel);
+        pendingSecrets.push(...annotateSecrets(entries, source, rel));
+        attachSnippets(entries, source, rel, contextLines);
+        return entries;
+      }),
+    );
+  const fallbackEntries = (await pLimit(fallbackTasks, concurrency)).flat();
+  assignRuleIds(fallbackEntries);
+
+  // Start custom detectors from .thirdwatch.yml
+  const detectors: ExecDetector[] = [];
+  for (const d of detectorConfigs) {
+    // A detector that fails to start is reported; the built-in analyzers still run
+    const detector = await isolateAsync({ filePath: "", stage: "detector", detector: d.name }, null, () =>
+      startExecDetector(
+        { name: d.name, command: d.command, extensions: d.extensions, ...(d.args ? { args: d.args } : {}) },
+        root,
+      ),
+    );
+    if (!detector) continue;
+    detectors.push(detector);
+    addPlugin(detector);
+  }
 
   // Analyze source files with concurrency control
-  const errors: ScanError[] = [];
-
   type TaskResult = { entries: DependencyEntry[]; skipped: boolean };
-  const tasks = sourceFiles.map((filePath) => async (): Promise<TaskResult> => {
+  const analyzeFile = async (filePath: string): Promise<TaskResult> => {
     // Skip files that are too large
     try {
       const fileStat = await stat(filePath);
@@ -244,45 +473,149 @@ export async function scan(options: ScanOptions): Promise<ScanResult> {
       return { entries: [], skipped: true };
     }
 
-    const plugin = pluginMap.get(extname(filePath));
-    if (!plugin) return { entries: [], skipped: false };
+    const filePlugins = pluginMap.get(extname(filePath)) ?? [];
+    if (filePlugins.length === 0 && rules.length === 0) return { entries: [], skipped: false };
 
+    let source: string;
     try {
-      const source = await readFile(filePath, "utf-8");
-      const ctx: AnalyzerContext = {
-        filePath,
-        source,
-        scanRoot: root,
-        resolvedEnv,
-      };
-      const maps = registryMapsByPlugin.get(plugin);
-      if (maps) ctx.registryMaps = maps;
-      const entries = await plugin.analyze(ctx);
-      return { entries, skipped: false };
+      source = await readFile(filePath, "utf-8");
     } catch (err) {
-      errors.push({
-        filePath,
-        error: err instanceof Error ? err.message : String(err),
-      });
+      errors.push({ filePath, stage: "read", error: errorMessage(err) });
       return { entries: [], skipped: false };
     }
-  });
 
-  const fileResults = await pLimit(tasks, concurrency);
+    const entries: DependencyEntry[] = [];
+    for (const plugin of filePlugins) {
+      try {
+        const ctx: AnalyzerContext = {
+          filePath,
+          source,
+          scanRoot: root,
+          resolvedEnv,
+        };
+        const maps = registryMapsByPlugin.get(plugin);
+        if (maps) ctx.registryMaps = maps;
+        const found = await plugin.analyze(ctx);
+        normalizeEntryPaths(found);
+        resolveCompatibleEndpoints(found);
+        // API rule IDs wait for catalog matching below, which names the vendor
+        assignRuleIds(found.filter((e) => e.kind !== "api"), plugin.language);
+        entries.push(...found);
+      } catch (err) {
+        errors.push({ filePath, stage: "analyze", detector: plugin.language, error: errorMessage(err) });
+      }
+    }
+
+    // Each step runs on its own, so one that throws leaves the others' annotations
+    const rel = relativePosix(root, filePath);
+    const step = (name: string, fn: () => void) => isolate({ filePath, stage: "post-process", detector: name }, fn);
+    // What each SDK is used for, and which clients are set up but never called
+    step("intents", () => classifyUsageIntents(entries, source, (provider) => catalogCategories.get(provider)));
+    step("unused", () => flagUnusedIntegrations(entries, source, rel));
+    step("custom-rules", () => {
+      entries.push(...applyCustomRules(rules, source, rel));
+    });
+    step("environments", () => annotateEnvironments(entries, source, rel));
+    step("fingerprints", () => fingerprintFindings(entries, source, rel));
+    step("gateways", () => checkGateways(entries, source, rel));
+    step("remediation", () => suggestRemediations(entries, source, rel));
+    step("secrets", () => {
+      pendingSecrets.push(...annotateSecrets(entries, source, rel));
+    });
+    step("snippets", () => attachSnippets(entries, source, rel, contextLines));
+    return { entries, skipped: false };
+  };
+  const tasks = sourceFiles.map((filePath) => () =>
+    isolateAsync({ filePath, stage: "analyze" }, { entries: [], skipped: false }, () => analyzeFile(filePath)),
+  );
+
+  let fileResults: TaskResult[];
+  try {
+    fileResults = await pLimit(tasks, concurrency);
+  } finally {
+    await Promise.all(detectors.map((d) => d.close()));
+  }
+
+  // Date hardcoded secrets so findings can be prioritized for rotation
+  if (secretHistory && pendingSecrets.length > 0) {
+    await isolateAsync({ filePath: "", stage: "secret-history" }, undefined, () => resolveSecretAges(pendingSecrets, root));
+  }
+
   const filesSkipped = fileResults.filter((r) => r.skipped).length;
-  const allEntries: DependencyEntry[] = [
+  let allEntries: DependencyEntry[] = [
     ...mergedManifestEntries,
+    ...configEntries,
+    ...fallbackEntries,
     ...fileResults.flatMap((r) => r.entries),
   ];
 
+  // Files were fingerprinted one at a time; split clashes between same-named files
+  disambiguateFingerprints(allEntries);
+
+  // One slug per vendor, whatever a custom rule or detector called it
+  canonicalizeProviders(allEntries, matchVendor);
+
+  // Catalog vendors and categories, so `rule_settings` can target
+  // TW-STRIPE-API or `category:<name>`
+  applyCatalogCategories(allEntries, registry);
+  assignRuleIds(allEntries);
+
+  // Logical services from `services`, so annotations and reports can name them
+  if (config.services) annotateServices(allEntries, config.services);
+
+  // Which way data moves and how it is classified, then `annotations`
+  labelDataFlows(allEntries, config.annotations);
+
+  // Per-rule enable/disable and severity from `rule_settings`
+  if (config.rule_settings) {
+    allEntries = applyRuleSettings(allEntries, config.rule_settings);
+  }
+
+  // Separate (or hide) calls to the organization's own services
+  if (config.first_party) {
+    allEntries = classifyFirstParty(
+      allEntries,
+      config.first_party.domains,
+      config.first_party.mode,
+    );
+  }
+
   const duration = Date.now() - startMs;
 
   const tdm = buildTDM(allEntries, {
     root,
     plugins,
     duration,
+    ...(options.catalog ? { catalogVersion: options.catalog.version } : {}),
+    ...(options.artifact ? { artifact: options.artifact } : {}),
   });
 
+  // Identify who operates hosts the catalog doesn't know about
+  if (enrichUnknown && tdm.apis.length > 0) {
+    const knownHosts = [
+      ...catalogHosts(registry.flatMap((e) => e.known_api_base_urls ?? [])),
+      ...registry.flatMap((e) => e.domains ?? []),
+    ];
+    await isolateAsync({ filePath: "", stage: "enrichment" }, undefined, () =>
+      enrichUnknownApis(tdm.apis, knownHosts, options.enrichmentDataset ? { dataset: options.enrichmentDataset } : {}),
+    );
+  }
+
+  // Suggest vendors for whatever the catalog missed — names and hosts only
+  if (llmClassify && llm) {
+    await isolateAsync({ filePath: "", stage: "llm-classification" }, 0, () => classifyUnmatchedWithLLM(tdm, registry, llm));
+  }
+
+  // What failed, so readers know the results are partial
+  if (errors.length > 0) {
+    tdm.errors = errors.slice(0, MAX_TDM_ERRORS).map((e) => ({
+      ...(e.filePath ? { file: relativePosix(root, e.filePath) } : {}),
+      stage: e.stage,
+      ...(e.detector ? { detector: e.detector } : {}),
+      message: e.error.slice(0, 1024),
+    }));
+  }
+
   return {
     tdm,
     filesScanned: sourceFiles.length - filesSkipped,
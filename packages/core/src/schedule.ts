@@ -0,0 +1,218 @@
+/**
+ * @module schedule
+ *
+ * Cron schedules and the repository list behind `thirdwatch server
+ * --schedule`, which re-clones and rescans repositories whose CI runs too
+ * rarely to keep the inventory current. The repository list is YAML:
+ *
+ *   schedule: "0 4 * * *"             # optional; --schedule overrides it
+ *   repositories:
+ *     - url: https://github.com/acme/checkout.git
+ *       branch: main                  # default: the remote's default branch
+ *     - url: git@github.com:acme/billing.git
+ *       name: billing                 # default: github.com/acme/billing
+ *
+ * Schedules are standard five-field cron expressions (minute, hour, day of
+ * month, month, day of week) with lists, ranges, steps, month and weekday
+ * names, and the @hourly / @daily / @weekly / @monthly / @yearly shorthands.
+ * They are evaluated in UTC. As in Vixie cron, when both day fields are
+ * restricted a day matching either one runs.
+ */
+
+import { execFile } from "node:child_process";
+import { readFile } from "node:fs/promises";
+import { promisify } from "node:util";
+import * as yaml from "js-yaml";
+import { z } from "zod";
+
+const execFileAsync = promisify(execFile);
+
+export interface CronSchedule {
+  expression: string;
+  minutes: Set<number>;
+  hours: Set<number>;
+  days: Set<number>;
+  months: Set<number>;
+  /** 0 = Sunday */
+  weekdays: Set<number>;
+  /** Neither day field starts with "*" (as in cron, a stepped wildcard is unrestricted), so either may match */
+  eitherDay: boolean;
+}
+
+const ALIASES: Record<string, string> = {
+  "@hourly": "0 * * * *",
+  "@daily": "0 0 * * *",
+  "@midnight": "0 0 * * *",
+  "@weekly": "0 0 * * 0",
+  "@monthly": "0 0 1 * *",
+  "@yearly": "0 0 1 1 *",
+  "@annually": "0 0 1 1 *",
+};
+
+const MONTH_NAMES = ["jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"];
+const WEEKDAY_NAMES = ["sun", "mon", "tue", "wed", "thu", "fri", "sat"];
+
+interface Field {
+  name: string;
+  min: number;
+  max: number;
+  names?: string[];
+  /** Value of names[0] */
+  base?: number;
+}
+
+const FIELDS: Field[] = [
+  { name: "minute", min: 0, max: 59 },
+  { name: "hour", min: 0, max: 23 },
+  { name: "day of month", min: 1, max: 31 },
+  { name: "month", min: 1, max: 12, names: MONTH_NAMES, base: 1 },
+  // 7 is Sunday too
+  { name: "day of week", min: 0, max: 7, names: WEEKDAY_NAMES, base: 0 },
+];
+
+function parseValue(text: string, field: Field): number {
+  const named = field.names?.indexOf(text.toLowerCase()) ?? -1;
+  const value = named !== -1 ? named + field.base! : /^\d+$/.test(text) ? Number(text) : NaN;
+  if (!Number.isInteger(value) || value < field.min || value > field.max) {
+    throw new Error(`${field.name} "${text}" is not between ${field.min} and ${field.max}`);
+  }
+  return value;
+}
+
+function parseField(text: string, field: Field): Set<number> {
+  const values = new Set<number>();
+  for (const part of text.split(",")) {
+    const m = part.match(/^(\*|[a-z0-9]+(?:-[a-z0-9]+)?)(?:\/(\d+))?$/i);
+    if (!m) throw new Error(`unreadable ${field.name} "${part}"`);
+    const step = m[2] !== undefined ? Number(m[2]) : 1;
+    if (step < 1) throw new Error(`${field.name} step must be at least 1`);
+    let from = field.min;
+    let to = field.max;
+    if (m[1] !== "*") {
+      const [a, b] = m[1]!.split("-");
+      from = parseValue(a!, field);
+      // "5/15" means 5 through the maximum, every 15
+      to = b !== undefined ? parseValue(b, field) : m[2] !== undefined ? field.max : from;
+      if (to < from) throw new Error(`${field.name} range "${m[1]}" runs backwards`);
+    }
+    for (let v = from; v <= to; v += step) values.add(v);
+  }
+  return values;
+}
+
+/** Parse a five-field cron expression or @-shorthand; throws on anything else */
+export function parseCron(expression: string): CronSchedule {
+  const expanded = ALIASES[expression.trim().toLowerCase()] ?? expression.trim();
+  const parts = expanded.split(/\s+/);
+  if (parts.length !== 5) {
+    throw new Error(`Invalid cron expression "${expression}": expected 5 fields (minute hour day month weekday)`);
+  }
+  try {
+    const [minutes, hours, days, months, weekdays] = parts.map((p, i) => parseField(p, FIELDS[i]!));
+    if (weekdays!.delete(7)) weekdays!.add(0);
+    return {
+      expression,
+      minutes: minutes!,
+      hours: hours!,
+      days: days!,
+      months: months!,
+      weekdays: weekdays!,
+      eitherDay: !parts[2]!.startsWith("*") && !parts[4]!.startsWith("*"),
+    };
+  } catch (err) {
+    throw new Error(`Invalid cron expression "${expression}": ${err instanceof Error ? err.message : String(err)}`);
+  }
+}
+
+function dayMatches(schedule: CronSchedule, t: Date): boolean {
+  const dom = schedule.days.has(t.getUTCDate());
+  const dow = schedule.weekdays.has(t.getUTCDay());
+  return schedule.eitherDay ? dom || dow : dom && dow;
+}
+
+/** The first minute strictly after `after` the schedule fires at */
+export function nextRun(schedule: CronSchedule, after: Date): Date {
+  const t = new Date(after.getTime());
+  t.setUTCSeconds(0, 0);
+  t.setUTCMinutes(t.getUTCMinutes() + 1);
+  // Every valid expression fires within a leap-year cycle
+  const limit = after.getTime() + 5 * 366 * 24 * 60 * 60 * 1000;
+  while (t.getTime() < limit) {
+    if (!schedule.months.has(t.getUTCMonth() + 1)) {
+      t.setUTCMonth(t.getUTCMonth() + 1, 1);
+      t.setUTCHours(0, 0, 0, 0);
+    } else if (!dayMatches(schedule, t)) {
+      t.setUTCDate(t.getUTCDate() + 1);
+      t.setUTCHours(0, 0, 0, 0);
+    } else if (!schedule.hours.has(t.getUTCHours())) {
+      t.setUTCHours(t.getUTCHours() + 1, 0, 0, 0);
+    } else if (!schedule.minutes.has(t.getUTCMinutes())) {
+      t.setUTCMinutes(t.getUTCMinutes() + 1, 0, 0);
+    } else {
+      return t;
+    }
+  }
+  throw new Error(`Cron expression "${schedule.expression}" never fires`);
+}
+
+// ---------------------------------------------------------------------------
+// Repository list
+// ---------------------------------------------------------------------------
+
+// Anything git accepts as a remote, but never an option
+const GitArgument = z.string().min(1).refine((v) => !v.startsWith("-"), { message: "must not start with '-'" });
+
+const ScheduledRepositorySchema = z.object({
+  url: GitArgument,
+  branch: GitArgument.optional(),
+  /** metadata.repository of the uploaded TDM */
+  name: z.string().min(1).optional(),
+});
+
+const ServerConfigSchema = z.object({
+  schedule: z.string().optional(),
+  repositories: z.array(ScheduledRepositorySchema).min(1),
+});
+
+export type ScheduledRepository = z.infer<typeof ScheduledRepositorySchema>;
+export type ServerConfig = z.infer<typeof ServerConfigSchema>;
+
+/** Read and validate a repository list */
+export async function loadServerConfig(path: string): Promise<ServerConfig> {
+  const parsed = yaml.load(await readFile(path, "utf-8"), { schema: yaml.FAILSAFE_SCHEMA });
+  try {
+    const config = ServerConfigSchema.parse(parsed);
+    if (config.schedule) parseCron(config.schedule);
+    return config;
+  } catch (err) {
+    throw new Error(`Invalid ${path}: ${err instanceof Error ? err.message : String(err)}`);
+  }
+}
+
+/**
+ * The name a clone URL's TDM is reported under:
+ *
+ *   https://token@github.com/acme/checkout.git  → github.com/acme/checkout
+ *   git@github.com:acme/billing.git             → github.com/acme/billing
+ *   /srv/git/search.git                         → search
+ */
+export function repositoryName(repo: ScheduledRepository): string {
+  if (repo.name) return repo.name;
+  const url = repo.url.replace(/\/+$/, "").replace(/\.git$/, "");
+  const remote = url.match(/^[a-z][a-z0-9+.-]*:\/\/(?:[^@/]*@)?([^/:]+)(?::\d+)?\/(.+)$/i);
+  if (remote) return `${remote[1]!.toLowerCase()}/${remote[2]}`;
+  const scp = url.match(/^(?:[^@/]+@)?([^/:\\]+):(?![\\/])(.+)$/);
+  if (scp) return `${scp[1]!.toLowerCase()}/${scp[2]}`;
+  return url.split(/[\\/]/).pop() ?? url;
+}
+
+/** Shallow-clone `repo` into the empty directory `dest`; returns the commit checked out */
+export async function cloneRepository(repo: ScheduledRepository, dest: string): Promise<string> {
+  const args = ["clone", "--depth", "1", "--single-branch", "--no-tags"];
+  if (repo.branch) args.push("--branch", repo.branch);
+  // Never prompt for credentials in a daemon
+  const env = { ...process.env, GIT_TERMINAL_PROMPT: "0" };
+  await execFileAsync("git", [...args, "--", repo.url, dest], { env, timeout: 10 * 60_000, maxBuffer: 16 * 1024 * 1024 });
+  const { stdout } = await execFileAsync("git", ["-C", dest, "rev-parse", "HEAD"], { env });
+  return stdout.trim();
+}
@@ -0,0 +1,127 @@
+/**
+ * @module services
+ *
+ * Logical services in a monorepo, from directory prefixes mapped in
+ * .thirdwatch.yml, so findings are reported per service rather than per
+ * file path:
+ *
+ *   services:
+ *     services/payments: payments-api
+ *     services/payments/worker: payments-worker
+ *     web: storefront
+ *
+ * The longest prefix wins, and prefixes match whole path segments
+ * (services/payments does not cover services/payments-legacy). Locations
+ * outside every prefix are left untagged and belong to the repository as a
+ * whole.
+ */
+
+import type { TDM } from "@thirdwatch/tdm";
+import type { DependencyEntry } from "./plugin.js";
+import { extractHost } from "./first-party.js";
+
+export type ServiceMap = Record<string, string>;
+
+function normalizePrefix(prefix: string): string {
+  return prefix.replace(/\\/g, "/").replace(/^\.\//, "").replace(/\/+$/, "");
+}
+
+/** The service whose directory prefix covers `path`, or null */
+export function serviceOf(path: string, services: ServiceMap): string | null {
+  const file = path.replace(/\\/g, "/");
+  let best: { length: number; service: string } | null = null;
+  for (const [prefix, service] of Object.entries(services)) {
+    const dir = normalizePrefix(prefix);
+    // An empty prefix ("." or "") maps the whole repository
+    if (dir && dir !== "." && file !== dir && !file.startsWith(`${dir}/`)) continue;
+    const length = dir === "." ? 0 : dir.length;
+    if (!best || length > best.length) best = { length, service };
+  }
+  return best?.service ?? null;
+}
+
+/** Tag every location, and every package's manifest, with the service it belongs to */
+export function annotateServices(entries: DependencyEntry[], services: ServiceMap): void {
+  if (Object.keys(services).length === 0) return;
+  for (const entry of entries) {
+    for (const loc of entry.locations) {
+      const service = serviceOf(loc.file, services);
+      if (service) loc.service = service;
+    }
+    if (entry.kind === "package") {
+      const service = serviceOf(entry.manifest_file, services);
+      if (service) entry.services = [service];
+    }
+  }
+}
+
+type Located = { locations: Array<{ service?: string }>; usage_count?: number };
+
+/**
+ * Split a scan into one TDM per service. Findings used by several services
+ * appear in each with that service's locations, and packages in each service
+ * whose manifest declares them. Untagged findings stay in the TDM keyed by
+ * `fallback` (the repository); a scan without services comes back whole.
+ */
+export function splitByService(tdm: TDM, fallback: string): Map<string, TDM> {
+  const parts = new Map<string, TDM>();
+  const part = (service: string) => {
+    let t = parts.get(service);
+    if (!t) {
+      t = { ...tdm, packages: [], apis: [], sdks: [], infrastructure: [], webhooks: [] };
+      parts.set(service, t);
+    }
+    return t;
+  };
+  const narrow = <T extends Located>(finding: T, service: string, shared: boolean): T => {
+    if (!shared) return finding;
+    const locations = finding.locations.filter((l) => (l.service ?? fallback) === service);
+    return { ...finding, locations, ...(finding.usage_count !== undefined ? { usage_count: locations.length } : {}) };
+  };
+  const spread = <T extends Located>(list: T[], key: "apis" | "sdks" | "infrastructure" | "webhooks") => {
+    for (const finding of list) {
+      const services = new Set(finding.locations.map((l) => l.service ?? fallback));
+      if (services.size === 0) services.add(fallback);
+      for (const service of services) (part(service)[key] as T[]).push(narrow(finding, service, services.size > 1));
+    }
+  };
+  for (const pkg of tdm.packages) {
+    const services = new Set(pkg.services?.length ? pkg.services : [fallback]);
+    for (const service of services) part(service).packages.push(narrow(pkg, service, services.size > 1));
+  }
+  spread(tdm.apis, "apis");
+  spread(tdm.sdks, "sdks");
+  spread(tdm.infrastructure, "infrastructure");
+  spread(tdm.webhooks, "webhooks");
+  if (parts.size <= 1) return new Map([[parts.keys().next().value ?? fallback, tdm]]);
+  return parts;
+}
+
+export interface ServiceVendors {
+  service: string;
+  /** Provider slugs (or hosts for unattributed APIs) the service uses */
+  vendors: string[];
+}
+
+/**
+ * Vendors per service. Findings with a location outside every mapped
+ * directory are listed under "(unmapped)".
+ */
+export function vendorsByService(tdm: TDM): ServiceVendors[] {
+  const groups = new Map<string, Set<string>>();
+  const add = (vendor: string | null | undefined, locations: Array<{ service?: string }>) => {
+    if (!vendor) return;
+    for (const service of new Set(locations.map((l) => l.service ?? "(unmapped)"))) {
+      if (!groups.has(service)) groups.set(service, new Set());
+      groups.get(service)!.add(vendor);
+    }
+  };
+  for (const api of tdm.apis) if (!api.first_party) add(api.provider ?? extractHost(api.resolved_url ?? api.url), api.locations);
+  for (const sdk of tdm.sdks) add(sdk.provider, sdk.locations);
+  for (const infra of tdm.infrastructure) if (!infra.first_party) add(infra.provider ?? infra.type, infra.locations);
+  for (const hook of tdm.webhooks) add(hook.provider, hook.locations);
+
+  return [...groups]
+    .map(([service, vendors]) => ({ service, vendors: [...vendors].sort() }))
+    .sort((a, b) => Number(a.service === "(unmapped)") - Number(b.service === "(unmapped)") || a.service.localeCompare(b.service));
+}
@@ -0,0 +1,208 @@
+/**
+ * @module sla
+ *
+ * Composite availability behind `thirdwatch sla`. A service that needs every
+ * vendor it calls can be no more available than the product of their
+ * published uptime commitments: Stripe at 99.99% and OpenAI at 99.9% cap the
+ * service at 99.89% before any of its own failures. The report lists each
+ * dependency's commitment (from the catalog's `sla` field, or contracted
+ * numbers passed as `assume`), the serial ceiling, the ceiling if vendors in
+ * the same category could fail over to each other, and the single points of
+ * failure — vendors with no same-category alternative in the service.
+ *
+ * Commitments are upper bounds, not forecasts: vendors without a published
+ * SLA are listed as unknown and can only lower the real ceiling.
+ */
+
+import type { TDM } from "@thirdwatch/tdm";
+import type { SDKRegistryEntry } from "./registry.js";
+import { createVendorMatcher } from "./runtime.js";
+import { extractHost, matchesDomain } from "./first-party.js";
+
+/** Minutes in the 30-day month SLA credits are usually computed over */
+const MINUTES_PER_MONTH = 30 * 24 * 60;
+
+export interface SlaDependency {
+  vendor: string;
+  display_name: string;
+  category?: string;
+  /** Per-service commitment the dependency falls under, e.g. "RDS Multi-AZ" */
+  scope?: string;
+  /** Uptime percentage; null when the vendor publishes none */
+  uptime: number | null;
+  /** Plan the commitment applies to, e.g. "Enterprise" */
+  plan?: string;
+  /** Where the commitment is published, or "assumed" for `assume` overrides */
+  source?: string;
+  /** Downtime per 30-day month the commitment allows */
+  monthly_downtime_minutes: number | null;
+  /** Hosts the service reaches the vendor on */
+  hosts: string[];
+  /** Code locations and runtime entries referencing the vendor */
+  usages: number;
+  /** No other vendor in the same category is used by the service */
+  single_point_of_failure: boolean;
+  /** Other vendors the service uses in the same category */
+  alternatives: string[];
+}
+
+export interface SlaReport {
+  service: string;
+  /** Weakest commitment first; vendors without one last */
+  dependencies: SlaDependency[];
+  /** Product of the known commitments, in percent; null when none are known */
+  composite_uptime: number | null;
+  /** As above, with same-category vendors treated as failover for each other */
+  composite_with_failover: number | null;
+  monthly_downtime_minutes: number | null;
+  /** Vendors without a published SLA */
+  unknown: string[];
+  single_points_of_failure: string[];
+}
+
+export interface SlaReportOptions {
+  /** Service name (default: the first repository named in the TDMs) */
+  service?: string;
+  /** Contracted uptimes by vendor slug, overriding the catalog */
+  assume?: Record<string, number>;
+}
+
+export interface VendorUsage {
+  vendor: string;
+  host?: string;
+  count: number;
+  /** The finding's data_classification labels */
+  classification?: string[];
+}
+
+const labels = (entry: { data_classification?: string[] }) =>
+  entry.data_classification ? { classification: entry.data_classification } : {};
+
+function round(value: number, digits: number): number {
+  const f = 10 ** digits;
+  return Math.round(value * f) / f;
+}
+
+function downtime(uptime: number | null): number | null {
+  return uptime === null ? null : round(((100 - uptime) / 100) * MINUTES_PER_MONTH, 1);
+}
+
+/**
+ * Every third-party vendor the TDM references, with the host it was reached
+ * on. Providers are resolved through `matchVendor` too, so a TDM that names
+ * a vendor by an alias ("amazon-ses") counts toward its canonical slug.
+ */
+export function collectVendorUsages(tdm: TDM, matchVendor: (host: string) => string | null): VendorUsage[] {
+  const usages: VendorUsage[] = [];
+  const canonical = (provider: string) => matchVendor(provider) ?? provider;
+  for (const sdk of tdm.sdks) usages.push({ vendor: canonical(sdk.provider), count: sdk.locations.length, ...labels(sdk) });
+  for (const api of tdm.apis) {
+    if (api.first_party) continue;
+    const host = extractHost(api.resolved_url ?? api.url) ?? undefined;
+    const vendor = api.provider ? canonical(api.provider) : host ? matchVendor(host) : null;
+    if (vendor) usages.push({ vendor, ...(host ? { host } : {}), count: api.locations.length || 1, ...labels(api) });
+  }
+  for (const wh of tdm.webhooks) {
+    if (wh.first_party || !wh.provider) continue;
+    usages.push({ vendor: canonical(wh.provider), count: wh.locations.length, ...labels(wh) });
+  }
+  for (const infra of tdm.infrastructure) {
+    if (infra.first_party) continue;
+    const host = infra.resolved_host ?? undefined;
+    const vendor = infra.provider ? canonical(infra.provider) : host ? matchVendor(host) : null;
+    if (vendor) usages.push({ vendor, ...(host ? { host } : {}), count: infra.locations.length, ...labels(infra) });
+  }
+  return usages;
+}
+
+/** 1 - Π(1 - a): available while any member is */
+function parallel(fractions: number[]): number {
+  return 1 - fractions.reduce((down, a) => down * (1 - a), 1);
+}
+
+/** Report for one service; pass its static scan and runtime reports together */
+export function computeSlaReport(
+  tdms: TDM[],
+  registry: SDKRegistryEntry[],
+  options: SlaReportOptions = {},
+): SlaReport {
+  const entries = new Map(registry.map((e) => [e.provider, e]));
+  const matchVendor = createVendorMatcher(registry);
+
+  // One dependency per vendor and per-service commitment hit
+  const deps = new Map<string, SlaDependency>();
+  for (const usage of tdms.flatMap((tdm) => collectVendorUsages(tdm, matchVendor))) {
+    const entry = entries.get(usage.vendor);
+    const sla = entry?.sla;
+    const scoped = usage.host
+      ? sla?.services?.find((s) => s.domains.some((d) => matchesDomain(usage.host!, d)))
+      : undefined;
+    const key = `${usage.vendor}\0${scoped?.name ?? ""}`;
+    let dep = deps.get(key);
+    if (!dep) {
+      const assumed = options.assume?.[usage.vendor];
+      const uptime = assumed ?? scoped?.uptime ?? sla?.uptime ?? null;
+      dep = {
+        vendor: usage.vendor,
+        display_name: entry?.display_name ?? usage.vendor,
+        ...(entry?.category ? { category: entry.category } : {}),
+        ...(scoped ? { scope: scoped.name } : {}),
+        uptime,
+        ...(assumed === undefined && sla?.plan ? { plan: sla.plan } : {}),
+        ...(assumed !== undefined ? { source: "assumed" } : sla ? { source: sla.url } : {}),
+        monthly_downtime_minutes: downtime(uptime),
+        hosts: [],
+        usages: 0,
+        single_point_of_failure: true,
+        alternatives: [],
+      };
+      deps.set(key, dep);
+    }
+    dep.usages += usage.count;
+    if (usage.host && !dep.hosts.includes(usage.host)) dep.hosts.push(usage.host);
+  }
+
+  // Same-category vendors are potential failover for each other
+  const byCategory = new Map<string, SlaDependency[]>();
+  for (const dep of deps.values()) {
+    const group = dep.category ?? `\0${dep.vendor}`;
+    byCategory.set(group, [...(byCategory.get(group) ?? []), dep]);
+  }
+  for (const group of byCategory.values()) {
+    const vendors = [...new Set(group.map((d) => d.vendor))];
+    for (const dep of group) {
+      dep.alternatives = vendors.filter((v) => v !== dep.vendor).sort();
+      dep.single_point_of_failure = dep.alternatives.length === 0;
+    }
+  }
+
+  const dependencies = [...deps.values()].sort(
+    (a, b) => (a.uptime ?? Infinity) - (b.uptime ?? Infinity) || a.vendor.localeCompare(b.vendor),
+  );
+  const known = dependencies.filter((d) => d.uptime !== null);
+
+  let composite: number | null = null;
+  let withFailover: number | null = null;
+  if (known.length > 0) {
+    composite = known.reduce((a, d) => a * (d.uptime! / 100), 1);
+    withFailover = [...byCategory.values()].reduce((a, group) => {
+      // A vendor's scoped commitments are serial; vendors in a group are parallel
+      const perVendor = new Map<string, number>();
+      for (const d of group) {
+        if (d.uptime !== null) perVendor.set(d.vendor, (perVendor.get(d.vendor) ?? 1) * (d.uptime / 100));
+      }
+      return perVendor.size > 0 ? a * parallel([...perVendor.values()]) : a;
+    }, 1);
+  }
+
+  const spofs = [...new Set(dependencies.filter((d) => d.single_point_of_failure).map((d) => d.vendor))];
+  return {
+    service: options.service ?? tdms.find((t) => t.metadata.repository)?.metadata.repository ?? "service",
+    dependencies,
+    composite_uptime: composite === null ? null : round(composite * 100, 4),
+    composite_with_failover: withFailover === null ? null : round(withFailover * 100, 4),
+    monthly_downtime_minutes: composite === null ? null : downtime(composite * 100),
+    unknown: [...new Set(dependencies.filter((d) => d.uptime === null).map((d) => d.vendor))],
+    single_points_of_failure: spofs,
+  };
+}
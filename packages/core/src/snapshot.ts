@@ -0,0 +1,340 @@
+/**
+ * @module snapshot
+ *
+ * Point-in-time connection inventory behind `thirdwatch snapshot` — "what is
+ * this box talking to right now". Open sockets come from the kernel's socket
+ * tables (/proc/net/tcp, which is what netstat and ss read) on Linux and from
+ * lsof or netstat elsewhere; the conntrack table adds connections from
+ * containers and other network namespaces NATed through the host.
+ *
+ * Only established outbound connections are kept: sockets on a listening
+ * port and connections to the host's own addresses are inbound.
+ */
+
+import { execFile } from "node:child_process";
+import { readFile, readdir, readlink } from "node:fs/promises";
+import { reverse } from "node:dns/promises";
+import { networkInterfaces } from "node:os";
+import { join } from "node:path";
+import type { RuntimeObservation } from "./runtime.js";
+import { isPrivateIp } from "./runtime.js";
+
+export interface SocketEntry {
+  localIp: string;
+  localPort: number;
+  remoteIp: string;
+  remotePort: number;
+  /** "ESTABLISHED", "SYN_SENT", "LISTEN", … */
+  state: string;
+  pid?: number;
+  process?: string;
+  /** Kernel socket inode (/proc/net/tcp only) */
+  inode?: string;
+}
+
+const OPEN_STATES = new Set(["ESTABLISHED", "SYN_SENT"]);
+
+// ---------------------------------------------------------------------------
+// Parsers
+// ---------------------------------------------------------------------------
+
+const PROC_TCP_STATES: Record<string, string> = { "01": "ESTABLISHED", "02": "SYN_SENT", "0A": "LISTEN" };
+
+/** /proc/net/tcp stores addresses as host-order 32-bit words in hex */
+function procHexIp(hex: string): string {
+  const words = hex.match(/.{8}/g) ?? [];
+  const bytes = words.flatMap((w) => [6, 4, 2, 0].map((i) => parseInt(w.slice(i, i + 2), 16)));
+  if (bytes.length === 4) return bytes.join(".");
+  if (bytes.slice(0, 10).every((b) => b === 0) && bytes[10] === 0xff && bytes[11] === 0xff) {
+    return bytes.slice(12).join(".");
+  }
+  const groups: string[] = [];
+  for (let i = 0; i < 16; i += 2) groups.push(((bytes[i]! << 8) | bytes[i + 1]!).toString(16));
+  // Compress the longest run of zero groups, as inet_ntop does
+  let [start, len] = [-1, 0];
+  for (let i = 0; i < 8; i++) {
+    let j = i;
+    while (j < 8 && groups[j] === "0") j++;
+    if (j - i > len) [start, len] = [i, j - i];
+  }
+  if (len < 2) return groups.join(":");
+  return `${groups.slice(0, start).join(":")}::${groups.slice(start + len).join(":")}`;
+}
+
+/** Parse /proc/net/tcp or /proc/net/tcp6 */
+export function parseProcNetTcp(text: string): SocketEntry[] {
+  const out: SocketEntry[] = [];
+  for (const line of text.split("\n").slice(1)) {
+    const f = line.trim().split(/\s+/);
+    const [local, remote, st] = [f[1], f[2], f[3]];
+    const state = st ? PROC_TCP_STATES[st.toUpperCase()] : undefined;
+    if (!local || !remote || !state) continue;
+    const [lip, lport] = local.split(":");
+    const [rip, rport] = remote.split(":");
+    if (!lip || !lport || !rip || !rport) continue;
+    out.push({
+      localIp: procHexIp(lip),
+      localPort: parseInt(lport, 16),
+      remoteIp: procHexIp(rip),
+      remotePort: parseInt(rport, 16),
+      state,
+      ...(f[9] && f[9] !== "0" ? { inode: f[9] } : {}),
+    });
+  }
+  return out;
+}
+
+/** Split "10.0.0.5:443", "[2606:4700::1]:443", or BSD "10.0.0.5.443" */
+function splitEndpoint(endpoint: string): { ip: string; port: number } | null {
+  const m = endpoint.match(/^\[?(.*?)\]?[.:](\d+|\*)$/);
+  if (!m || !m[1] || m[1] === "*") return null;
+  return { ip: m[1].replace(/%.*$/, ""), port: m[2] === "*" ? 0 : Number(m[2]) };
+}
+
+/** Parse `netstat -tanp` (Linux), `netstat -an -p tcp` (macOS, BSD), or `netstat -an` (Windows) */
+export function parseNetstat(text: string): SocketEntry[] {
+  const out: SocketEntry[] = [];
+  for (const line of text.split("\n")) {
+    let f = line.trim().split(/\s+/);
+    // Windows has no Recv-Q / Send-Q columns
+    if (f[0] === "TCP") f = ["tcp", "0", "0", ...f.slice(1)];
+    if (!/^tcp[46]?$/.test(f[0] ?? "")) continue;
+    const local = splitEndpoint(f[3] ?? "");
+    const state = f[5] === "ESTAB" ? "ESTABLISHED" : f[5] === "LISTENING" ? "LISTEN" : f[5];
+    if (!local || !state) continue;
+    const remote = splitEndpoint(f[4] ?? "") ?? { ip: "*", port: 0 };
+    const [pid, ...name] = (f[6] ?? "").split("/");
+    out.push({
+      localIp: local.ip,
+      localPort: local.port,
+      remoteIp: remote.ip,
+      remotePort: remote.port,
+      state,
+      ...(pid && /^\d+$/.test(pid) ? { pid: Number(pid) } : {}),
+      ...(name.length > 0 ? { process: name.join("/") } : {}),
+    });
+  }
+  return out;
+}
+
+/** Parse `lsof -nP -iTCP` */
+export function parseLsof(text: string): SocketEntry[] {
+  const out: SocketEntry[] = [];
+  for (const line of text.split("\n")) {
+    const m = line.match(/^(\S+)\s+(\d+)\s.*\sTCP\s+(\S+?)(?:->(\S+))?\s+\((\w+)\)\s*$/);
+    if (!m) continue;
+    const local = splitEndpoint(m[3]!);
+    const remote = m[4] ? splitEndpoint(m[4]) : { ip: "*", port: 0 };
+    if (!local || !remote) continue;
+    out.push({
+      localIp: local.ip,
+      localPort: local.port,
+      remoteIp: remote.ip,
+      remotePort: remote.port,
+      state: m[5]!,
+      pid: Number(m[2]),
+      // lsof escapes spaces in command names as \x20
+      process: m[1]!.replace(/\\x20/g, " "),
+    });
+  }
+  return out;
+}
+
+/**
+ * Parse /proc/net/nf_conntrack or `conntrack -L`. The first src/dst tuple is
+ * the original direction, so "local" is the initiator. UDP is kept only for
+ * port 443 (QUIC).
+ */
+export function parseConntrack(text: string): SocketEntry[] {
+  const out: SocketEntry[] = [];
+  for (const line of text.split("\n")) {
+    const proto = line.match(/\b(tcp|udp)\s+\d+\s+\d+\s+/)?.[1];
+    if (!proto) continue;
+    const field = (k: string) => line.match(new RegExp(`\\b${k}=(\\S+)`))?.[1];
+    const [src, dst, sport, dport] = [field("src"), field("dst"), field("sport"), field("dport")];
+    if (!src || !dst || !sport || !dport) continue;
+    const tcpState = line.match(/\s(ESTABLISHED|SYN_SENT)\s/)?.[1];
+    if (proto === "tcp" ? !tcpState : dport !== "443") continue;
+    out.push({
+      localIp: src,
+      localPort: Number(sport),
+      remoteIp: dst,
+      remotePort: Number(dport),
+      state: tcpState ?? "ESTABLISHED",
+    });
+  }
+  return out;
+}
+
+// ---------------------------------------------------------------------------
+// Outbound filter
+// ---------------------------------------------------------------------------
+
+export interface OutboundOptions {
+  includePrivate?: boolean;
+  /** The host's own addresses; connections to them are inbound */
+  localAddresses?: Set<string>;
+  now?: () => string;
+}
+
+/** Keep open outbound connections from socket entries, one observation each */
+export function outboundObservations(entries: SocketEntry[], options: OutboundOptions = {}): RuntimeObservation[] {
+  const listening = new Set(entries.filter((e) => e.state === "LISTEN").map((e) => e.localPort));
+  const local = options.localAddresses ?? new Set<string>();
+  const timestamp = (options.now ?? (() => new Date().toISOString()))();
+  const seen = new Set<string>();
+  const out: RuntimeObservation[] = [];
+
+  for (const e of entries) {
+    if (!OPEN_STATES.has(e.state) || e.remotePort === 0) continue;
+    const remoteIp = e.remoteIp.replace(/^::ffff:/i, "");
+    if (listening.has(e.localPort) || local.has(remoteIp)) continue;
+    if (!options.includePrivate && isPrivateIp(remoteIp)) continue;
+    // The same connection can appear in both the socket and conntrack tables
+    const key = `${e.localIp.replace(/^::ffff:/i, "")}:${e.localPort}>${remoteIp}:${e.remotePort}`;
+    if (seen.has(key)) continue;
+    seen.add(key);
+    out.push({
+      source: "snapshot",
+      ip: remoteIp,
+      port: e.remotePort,
+      timestamp,
+      ...(e.process ? { process: e.process } : {}),
+    });
+  }
+  return out;
+}
+
+// ---------------------------------------------------------------------------
+// Collection
+// ---------------------------------------------------------------------------
+
+function run(cmd: string, args: string[]): Promise<string> {
+  return new Promise((resolveFn, reject) => {
+    execFile(cmd, args, { timeout: 10_000, maxBuffer: 64 * 1024 * 1024 }, (err, stdout) => {
+      // lsof exits 1 when some files could not be inspected but still prints the rest
+      if (err && !stdout) reject(err);
+      else resolveFn(stdout);
+    });
+  });
+}
+
+/** Attach pid and command to /proc/net/tcp entries through /proc/<pid>/fd */
+async function attachProcesses(entries: SocketEntry[], procRoot: string): Promise<void> {
+  const wanted = new Map(entries.filter((e) => e.inode).map((e) => [e.inode!, e]));
+  if (wanted.size === 0) return;
+  const pids = (await readdir(procRoot)).filter((d) => /^\d+$/.test(d));
+  for (const pid of pids) {
+    let fds: string[];
+    try {
+      fds = await readdir(join(procRoot, pid, "fd"));
+    } catch {
+      continue; // Exited, or not ours without root
+    }
+    let comm: string | undefined;
+    for (const fd of fds) {
+      const target = await readlink(join(procRoot, pid, "fd", fd)).catch(() => "");
+      const inode = target.match(/^socket:\[(\d+)\]$/)?.[1];
+      const entry = inode ? wanted.get(inode) : undefined;
+      if (!entry) continue;
+      comm ??= (await readFile(join(procRoot, pid, "comm"), "utf8").catch(() => "")).trim();
+      entry.pid = Number(pid);
+      if (comm) entry.process = comm;
+    }
+  }
+}
+
+export interface SnapshotOptions extends OutboundOptions {
+  /** Read the conntrack table on Linux (default: true) */
+  conntrack?: boolean;
+  /** Name bare IPs with PTR lookups (default: true) */
+  reverseDns?: boolean;
+  /** Override for tests (default: process.platform) */
+  platform?: NodeJS.Platform;
+  procRoot?: string;
+}
+
+export interface Snapshot {
+  observations: RuntimeObservation[];
+  /** Tables that could be read, e.g. ["/proc/net/tcp", "conntrack"] */
+  sources: string[];
+}
+
+async function reverseNames(ips: string[]): Promise<Map<string, string>> {
+  const names = new Map<string, string>();
+  const queue = [...ips];
+  const worker = async () => {
+    for (let ip = queue.shift(); ip; ip = queue.shift()) {
+      const timeout = new Promise<string[]>((resolveFn) => setTimeout(() => resolveFn([]), 2000).unref());
+      const ptr = await Promise.race([reverse(ip).catch(() => [] as string[]), timeout]);
+      if (ptr[0]) names.set(ip, ptr[0].toLowerCase().replace(/\.$/, ""));
+    }
+  };
+  await Promise.all(Array.from({ length: 16 }, worker));
+  return names;
+}
+
+function hostAddresses(): Set<string> {
+  const out = new Set<string>();
+  for (const list of Object.values(networkInterfaces())) {
+    for (const addr of list ?? []) out.add(addr.address);
+  }
+  return out;
+}
+
+/** Read the host's connection tables and return its open outbound connections */
+export async function takeSnapshot(options: SnapshotOptions = {}): Promise<Snapshot> {
+  const platform = options.platform ?? process.platform;
+  const procRoot = options.procRoot ?? "/proc";
+  const entries: SocketEntry[] = [];
+  const sources: string[] = [];
+
+  if (platform === "linux") {
+    const proc: SocketEntry[] = [];
+    for (const table of ["tcp", "tcp6"]) {
+      const text = await readFile(join(procRoot, "net", table), "utf8").catch(() => null);
+      if (text === null) continue;
+      proc.push(...parseProcNetTcp(text));
+      sources.push(`/proc/net/${table}`);
+    }
+    await attachProcesses(proc, procRoot);
+    entries.push(...proc);
+    if (options.conntrack !== false) {
+      const text =
+        (await readFile(join(procRoot, "net", "nf_conntrack"), "utf8").catch(() => null)) ??
+        (await run("conntrack", ["-L"]).catch(() => null));
+      if (text !== null) {
+        entries.push(...parseConntrack(text));
+        sources.push("conntrack");
+      }
+    }
+  } else {
+    const lsof = await run("lsof", ["-nP", "-iTCP"]).catch(() => null);
+    if (lsof !== null) {
+      entries.push(...parseLsof(lsof));
+      sources.push("lsof");
+    } else {
+      const netstat = await run("netstat", platform === "win32" ? ["-ano", "-p", "TCP"] : ["-an", "-p", "tcp"]).catch(
+        () => null,
+      );
+      if (netstat !== null) {
+        entries.push(...parseNetstat(netstat));
+        sources.push("netstat");
+      }
+    }
+  }
+  if (sources.length === 0) throw new Error("No connection table could be read (tried /proc, conntrack, lsof, netstat)");
+
+  const observations = outboundObservations(entries, {
+    ...options,
+    localAddresses: options.localAddresses ?? hostAddresses(),
+  });
+  if (options.reverseDns !== false) {
+    const names = await reverseNames([...new Set(observations.map((o) => o.ip!))]);
+    for (const obs of observations) {
+      const name = names.get(obs.ip!);
+      if (name) obs.host = name;
+    }
+  }
+  return { observations, sources };
+}
@@ -0,0 +1,61 @@
+/**
+ * @module terraform
+ *
+ * Terraform state kept in HCP Terraform (Terraform Cloud) or Terraform
+ * Enterprise, declared in .tf files rather than code:
+ *
+ *   terraform {
+ *     cloud { organization = "acme" }                → app.terraform.io
+ *     backend "remote" { hostname = "tfe.acme.com" } → tfe.acme.com
+ *   }
+ *
+ * Every plan and apply reads and writes that state, so each block becomes an
+ * API entry for the host's workspace API; the catalog attributes
+ * app.terraform.io to HCP Terraform.
+ */
+
+import { extname } from "node:path";
+import type { DependencyEntry } from "./plugin.js";
+
+const DEFAULT_HOSTNAME = "app.terraform.io";
+
+// cloud { ... } and backend "remote" { ... }; other backends (s3, gcs) are
+// reported by the storage they point at
+const BLOCK_START_RE = /^(?:cloud|backend\s+"remote")\s*\{/;
+const HOSTNAME_RE = /^hostname\s*=\s*"([a-z0-9.-]+)"/i;
+
+export function isTerraformConfig(path: string): boolean {
+  return extname(path) === ".tf";
+}
+
+/** Find HCP Terraform / Terraform Enterprise backends in one .tf file */
+export function detectTerraformBackends(source: string, relPath: string): DependencyEntry[] {
+  const entries: DependencyEntry[] = [];
+  const lines = source.split("\n");
+
+  for (let i = 0; i < lines.length; i++) {
+    const trimmed = lines[i]!.trim();
+    if (!BLOCK_START_RE.test(trimmed)) continue;
+
+    // The hostname, if any, sits somewhere inside the block
+    let hostname = DEFAULT_HOSTNAME;
+    let depth = 0;
+    for (let j = i; j < lines.length; j++) {
+      const line = lines[j]!.trim();
+      if (line.startsWith("#") || line.startsWith("//")) continue;
+      const host = line.replace(BLOCK_START_RE, "").trim().match(HOSTNAME_RE);
+      if (host) hostname = host[1]!.toLowerCase();
+      depth += (line.match(/\{/g)?.length ?? 0) - (line.match(/\}/g)?.length ?? 0);
+      if (depth <= 0) break;
+    }
+
+    entries.push({
+      kind: "api",
+      url: `https://${hostname}/api/v2`,
+      locations: [{ file: relPath, line: i + 1, context: trimmed.slice(0, 200), usage: "terraform_backend" }],
+      usage_count: 1,
+      confidence: "high",
+    });
+  }
+  return entries;
+}
@@ -0,0 +1,75 @@
+/**
+ * @module unused
+ *
+ * Integrations that are set up but never used: the SDK is imported and a
+ * client constructed, and nothing in the file calls it.
+ *
+ *   rdb := redis.NewClient(&redis.Options{Addr: addr})
+ *   _ = rdb                                  → unused
+ *   client = OpenAI(api_key=key)             (never referenced again) → unused
+ *   conn, _ := pgx.Connect(ctx, dsn)
+ *   defer conn.Close(ctx)                    → unused; closing isn't using
+ *
+ * Such entries are marked `unused` so teams can remove the dependency and
+ * rotate or delete the credentials it was configured with. The check is
+ * conservative: a client stored on a struct field or object property, a
+ * package-level call (charge.New(params)), or any other reference to the
+ * client's variable counts as use.
+ */
+
+import type { DependencyEntry } from "./plugin.js";
+
+// Import statements in the supported languages
+const IMPORT_RE = /^\s*(?:import\b|from\s+\S+\s+import\b|use\s|using\s|require\s|extern crate\b|(?:const|let|var)\s+\w+\s*=\s*require\()/;
+
+// x := ..., conn, _ := ..., const x = ..., $x = ..., S3Client x = ...; not obj.x = ...
+const HANDLE_RE =
+  /^\s*(?:(?:const|let|var|val|final|private|public|protected|static|readonly|mut)\s+)*(?:[\w<>[\]?]+\s+)?\$?(\w+)(?:\s*,\s*\w+)*\s*(?::=|=)(?!=)\s*(.*)$/;
+
+// Right-hand sides that build a client rather than call the vendor
+const CONSTRUCTOR_RE =
+  /\bnew\s+[\w.\\]+\s*[({]|\bNew\w+\s*\(|\b(?!New\b)[A-Z]\w*\s*\(|\b\w*(?:[Cc]lient|[Cc]onnect|[Ss]ession|[Rr]esource)\w*\s*\(|\.(?:builder|build|getInstance|initialize_app)\s*\(/;
+
+// Methods that release a client without using it
+const RELEASE_METHODS = "Close|close|Shutdown|shutdown|Disconnect|disconnect|Quit|quit|Flush|flush";
+
+/** The variable a location's line assigns a newly constructed client to */
+function constructedHandle(line: string): string | null {
+  if (IMPORT_RE.test(line)) return null;
+  const m = line.match(HANDLE_RE);
+  if (!m || m[1] === "_" || !CONSTRUCTOR_RE.test(m[2]!)) return null;
+  return m[1]!;
+}
+
+/** Mark SDK and infrastructure entries of one file whose clients the file never uses */
+export function flagUnusedIntegrations(entries: DependencyEntry[], source: string, relPath: string): void {
+  const lines = source.split("\n");
+
+  for (const entry of entries) {
+    if (entry.kind !== "sdk" && entry.kind !== "infrastructure") continue;
+    const here = entry.locations.filter((l) => l.file === relPath);
+    if (here.length === 0) continue;
+
+    const handles: Array<{ name: string; line: number }> = [];
+    let usedDirectly = false;
+    for (const loc of here) {
+      const text = lines[loc.line - 1] ?? "";
+      const context = loc.context ?? text;
+      if (IMPORT_RE.test(context)) continue;
+      const name = constructedHandle(text);
+      if (name) handles.push({ name, line: loc.line });
+      else usedDirectly = true;
+    }
+    if (usedDirectly || handles.length === 0) continue;
+
+    const used = handles.some(({ name, line }) => {
+      const ref = new RegExp(`(?<![\\w.])\\$?${name}\\b`);
+      // Discarding (_ = client) or closing the client doesn't count
+      const nonUse = new RegExp(`^\\s*_\\s*=\\s*${name}\\s*;?\\s*$|\\b${name}\\.(?:${RELEASE_METHODS})\\s*\\(`, "g");
+      return lines.some(
+        (text, i) => i !== line - 1 && !/^\s*(?:\/\/|#)/.test(text) && ref.test(text.replace(nonUse, "")),
+      );
+    });
+    if (!used) entry.unused = true;
+  }
+}
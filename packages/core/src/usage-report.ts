@@ -0,0 +1,101 @@
+/**
+ * @module usage-report
+ *
+ * Windows of runtime observations in the server's live-usage format
+ * (POST /api/v1/runtime/usage) — the same reports the instrumentation
+ * libraries send. The agent uses this to push per-workload rollups from a
+ * cluster: each observation's client ("<namespace>/<workload>") becomes the
+ * reporting service.
+ */
+
+import type { RuntimeObservation, VendorMatcher } from "./runtime.js";
+import type { IpRangeMatcher } from "./ip-ranges.js";
+
+export const USAGE_PATH = "/api/v1/runtime/usage";
+
+export interface UsageCall {
+  kind: string;
+  host: string;
+  vendor?: string;
+  method?: string;
+  count: number;
+  errors: number;
+  latency_ms_total: number;
+  latency_ms_max: number;
+}
+
+export interface UsageReport {
+  service: string;
+  environment?: string;
+  window_start: string;
+  window_end: string;
+  calls: UsageCall[];
+}
+
+export interface UsageReportOptions {
+  matchVendor: VendorMatcher;
+  matchIp?: IpRangeMatcher;
+  windowStart: string;
+  windowEnd: string;
+  /** Service for observations without a client */
+  defaultService: string;
+  /** Cluster or deployment label */
+  environment?: string;
+}
+
+/** Group observations into one report per client, one call entry per destination */
+export function buildUsageReports(
+  observations: RuntimeObservation[],
+  options: UsageReportOptions,
+): UsageReport[] {
+  const byService = new Map<string, Map<string, UsageCall>>();
+  for (const obs of observations) {
+    const host = obs.host ?? obs.ip ?? obs.service;
+    if (!host) continue;
+    const service = obs.client ?? options.defaultService;
+    const calls = byService.get(service) ?? new Map<string, UsageCall>();
+    byService.set(service, calls);
+
+    const kind = obs.method ? "http" : "tcp";
+    const key = `${kind}|${host}|${obs.method ?? ""}`;
+    const existing = calls.get(key);
+    if (existing) {
+      existing.count += obs.count ?? 1;
+      continue;
+    }
+    const vendor =
+      (obs.host ? options.matchVendor(obs.host) : obs.ip && options.matchIp ? options.matchIp(obs.ip) : null) ??
+      (obs.service ? options.matchVendor(obs.service) : null);
+    calls.set(key, {
+      kind,
+      host,
+      ...(vendor ? { vendor } : {}),
+      ...(obs.method ? { method: obs.method } : {}),
+      count: obs.count ?? 1,
+      // Connection-level sources see neither failures nor latency
+      errors: 0,
+      latency_ms_total: 0,
+      latency_ms_max: 0,
+    });
+  }
+
+  return [...byService].map(([service, calls]) => ({
+    service,
+    ...(options.environment ? { environment: options.environment } : {}),
+    window_start: options.windowStart,
+    window_end: options.windowEnd,
+    calls: [...calls.values()],
+  }));
+}
+
+/** POST one report; rejects on network errors and non-2xx responses */
+export async function sendUsageReport(endpoint: string, apiKey: string, report: UsageReport): Promise<void> {
+  const res = await fetch(`${endpoint.replace(/\/+$/, "")}${USAGE_PATH}`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json", "x-api-key": apiKey },
+    body: JSON.stringify(report),
+  });
+  if (!res.ok) {
+    throw new Error(`Usage report for ${report.service} failed (HTTP ${res.status}): ${(await res.text()).slice(0, 200)}`);
+  }
+}
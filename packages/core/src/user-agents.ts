@@ -0,0 +1,87 @@
+/**
+ * @module user-agents
+ *
+ * User-Agent → SDK attribution from the catalog's `user_agents` fingerprints.
+ * Most vendor SDKs announce themselves and their version in every request:
+ *
+ *   Stripe/v1 PythonBindings/7.1.0              stripe 7.1.0 (pypi)
+ *   OpenAI/JS 4.56.0                            openai 4.56.0 (npm)
+ *   Boto3/1.34.2 md/Botocore#1.34.2 ua/2.0 …    boto3 1.34.2 (pypi)
+ *
+ * Runtime sources that see request headers (the proxy, Envoy access logs,
+ * OpenTelemetry spans) use this to say which SDK and version sent the
+ * traffic, so it can be lined up with the packages a static scan found.
+ */
+
+import type { SDKRegistryEntry } from "./registry.js";
+
+export interface UserAgentMatch {
+  provider: string;
+  sdk_package: string;
+  ecosystem?: string;
+  /** Version from the fingerprint's first capture group */
+  version?: string;
+}
+
+/** Match a User-Agent, preferring fingerprints of `provider` (the destination's vendor) when given */
+export type UserAgentMatcher = (userAgent: string, provider?: string | null) => UserAgentMatch | null;
+
+interface Fingerprint {
+  re: RegExp;
+  provider: string;
+  sdk_package: string;
+  ecosystem?: string;
+}
+
+export function createUserAgentMatcher(registry: SDKRegistryEntry[]): UserAgentMatcher {
+  const fingerprints: Fingerprint[] = [];
+  for (const entry of registry) {
+    for (const ua of entry.user_agents ?? []) {
+      let re: RegExp;
+      try {
+        re = new RegExp(ua.pattern);
+      } catch {
+        continue; // `thirdwatch catalog validate` reports it
+      }
+      fingerprints.push({
+        re,
+        provider: entry.provider,
+        sdk_package: ua.sdk_package,
+        ...(ua.ecosystem ? { ecosystem: ua.ecosystem } : {}),
+      });
+    }
+  }
+
+  const test = (fp: Fingerprint, userAgent: string): UserAgentMatch | null => {
+    const m = fp.re.exec(userAgent);
+    if (!m) return null;
+    return {
+      provider: fp.provider,
+      sdk_package: fp.sdk_package,
+      ...(fp.ecosystem ? { ecosystem: fp.ecosystem } : {}),
+      ...(m[1] ? { version: m[1] } : {}),
+    };
+  };
+
+  const cache = new Map<string, UserAgentMatch | null>();
+  return (userAgent, provider) => {
+    const key = `${provider ?? ""}\n${userAgent}`;
+    if (!cache.has(key)) {
+      if (cache.size >= 10_000) cache.clear();
+      let match: UserAgentMatch | null = null;
+      // The destination's own SDKs first: a generic HTTP stack may also name a vendor
+      if (provider) {
+        for (const fp of fingerprints) {
+          if (fp.provider === provider && (match = test(fp, userAgent))) break;
+        }
+      }
+      if (!match) {
+        for (const fp of fingerprints) {
+          if ((match = test(fp, userAgent))) break;
+        }
+      }
+      cache.set(key, match);
+    }
+    return cache.get(key)!;
+  };
+}
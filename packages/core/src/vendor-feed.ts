@@ -0,0 +1,62 @@
+/**
+ * @module vendor-feed
+ *
+ * Vendor change feed behind the cloud API's `/api/v1/vendor-feed`: which
+ * vendors appeared in or left a repository between two uploads, and the
+ * signed webhook request that delivers those events to subscribers.
+ *
+ *   POST https://hooks.acme.io/thirdwatch
+ *   X-Thirdwatch-Signature: sha256=<hmac of the body>
+ *
+ *   {"version":"1","event":"vendor_change","events":[{"vendor":"openai","change":"added",...}]}
+ *
+ * Vendors are counted as `collectVendorUsages` counts them, so an alias and
+ * its canonical slug are one vendor and first-party endpoints are none.
+ */
+
+import type { TDM } from "@thirdwatch/tdm";
+import { collectVendorUsages } from "./sla.js";
+import { signPayload } from "./webhook-signature.js";
+
+export interface VendorChange {
+  vendor: string;
+  change: "added" | "removed";
+}
+
+function vendorsOf(tdm: TDM, matchVendor: (host: string) => string | null): Set<string> {
+  return new Set(collectVendorUsages(tdm, matchVendor).map((u) => u.vendor));
+}
+
+/**
+ * Vendors added and removed from `previous` to `next`, sorted by vendor. With
+ * no previous baseline (a repository's first upload) every vendor is added.
+ */
+export function diffVendors(
+  previous: TDM | null,
+  next: TDM,
+  matchVendor: (host: string) => string | null,
+): VendorChange[] {
+  const after = vendorsOf(next, matchVendor);
+  const before = previous ? vendorsOf(previous, matchVendor) : new Set<string>();
+  return [
+    ...[...after].filter((v) => !before.has(v)).map((vendor) => ({ vendor, change: "added" as const })),
+    ...[...before].filter((v) => !after.has(v)).map((vendor) => ({ vendor, change: "removed" as const })),
+  ].sort((a, b) => a.vendor.localeCompare(b.vendor));
+}
+
+export interface VendorFeedRequest {
+  body: string;
+  headers: Record<string, string>;
+}
+
+/**
+ * The webhook body for `events` and its headers, signed like notifier
+ * webhooks (X-Thirdwatch-Signature: sha256=<hmac>) when the subscription
+ * has a secret.
+ */
+export function vendorFeedRequest(events: unknown[], secret?: string | null): VendorFeedRequest {
+  const body = JSON.stringify({ version: "1", event: "vendor_change", events });
+  const headers: Record<string, string> = { "Content-Type": "application/json" };
+  if (secret) headers["X-Thirdwatch-Signature"] = signPayload(body, secret);
+  return { body, headers };
+}
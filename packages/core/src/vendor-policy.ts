@@ -0,0 +1,212 @@
+/**
+ * @module vendor-policy
+ *
+ * Enforcement artifacts generated from the approved-vendor list, behind
+ * `thirdwatch policy` and GET /api/v1/vendors/approvals/policy, so the list
+ * that gates code review also drives infrastructure policy:
+ *
+ *   terraform — a .tfvars file with the approved vendors and the egress
+ *               domains and CIDR blocks the catalog lists for them
+ *   opa       — a bundle (.tar.gz) with the same data and a Rego policy:
+ *               `allow_egress` for hosts and IPs, and `deny` for scan
+ *               results that use an unapproved vendor
+ *
+ * Domains keep the catalog's pattern syntax ("x.com" covers its subdomains,
+ * "*.x.com" only its subdomains); the OPA bundle carries each pattern's
+ * regular expression so the policy matches exactly as thirdwatch does.
+ */
+
+import { gzipSync } from "node:zlib";
+import type { SDKRegistryEntry } from "./registry.js";
+import { domainPatternRegex, extractHost, matchesDomain } from "./first-party.js";
+
+export interface VendorEgress {
+  display_name: string;
+  category?: string;
+  /** Catalog domain patterns plus API hosts they don't already cover, sorted */
+  domains: string[];
+  cidrs: string[];
+}
+
+export interface VendorPolicy {
+  generated_at: string;
+  approved_vendors: string[];
+  /** Every approved vendor's domains, sorted and deduplicated */
+  allowed_egress_domains: string[];
+  allowed_egress_cidrs: string[];
+  vendor_egress: Record<string, VendorEgress>;
+  /** Approved slugs the catalog doesn't know; they contribute no domains */
+  unknown_vendors: string[];
+}
+
+export type PolicyFormat = "terraform" | "opa" | "json";
+
+export function buildVendorPolicy(
+  approved: string[],
+  registry: SDKRegistryEntry[],
+  options: { now?: Date } = {},
+): VendorPolicy {
+  const entries = new Map(registry.map((e) => [e.provider, e]));
+  const slugs = [...new Set(approved.map((s) => s.toLowerCase()))].sort();
+  const vendorEgress: Record<string, VendorEgress> = {};
+  const unknown: string[] = [];
+
+  for (const slug of slugs) {
+    const entry = entries.get(slug);
+    if (!entry) {
+      unknown.push(slug);
+      continue;
+    }
+    const domains = new Set((entry.domains ?? []).map((d) => d.toLowerCase()));
+    for (const url of entry.known_api_base_urls ?? []) {
+      const host = extractHost(url);
+      if (host && ![...domains].some((d) => matchesDomain(host, d))) domains.add(host);
+    }
+    vendorEgress[slug] = {
+      display_name: entry.display_name,
+      ...(entry.category ? { category: entry.category } : {}),
+      domains: [...domains].sort(),
+      cidrs: [...(entry.ip_ranges ?? [])].sort(),
+    };
+  }
+
+  const all = Object.values(vendorEgress);
+  return {
+    generated_at: (options.now ?? new Date()).toISOString(),
+    approved_vendors: slugs,
+    allowed_egress_domains: [...new Set(all.flatMap((v) => v.domains))].sort(),
+    allowed_egress_cidrs: [...new Set(all.flatMap((v) => v.cidrs))].sort(),
+    vendor_egress: vendorEgress,
+    unknown_vendors: unknown,
+  };
+}
+
+// ---------------------------------------------------------------------------
+// Terraform
+// ---------------------------------------------------------------------------
+
+function hclList(values: string[], indent: string): string {
+  if (values.length === 0) return "[]";
+  return `[\n${values.map((v) => `${indent}  ${JSON.stringify(v)},`).join("\n")}\n${indent}]`;
+}
+
+/**
+ * Variable values for a .tfvars file. Declare approved_vendors,
+ * allowed_egress_domains, and allowed_egress_cidrs as list(string), and
+ * vendor_egress as map(object({ domains = list(string), cidrs = list(string) })).
+ */
+export function renderTerraformVars(policy: VendorPolicy): string {
+  const vendors = Object.entries(policy.vendor_egress).map(
+    ([slug, v]) =>
+      `  ${JSON.stringify(slug)} = {\n` +
+      `    domains = ${hclList(v.domains, "    ")}\n` +
+      `    cidrs   = ${hclList(v.cidrs, "    ")}\n` +
+      `  }`,
+  );
+  return [
+    `# Generated by thirdwatch from the approved-vendor list at ${policy.generated_at}. Do not edit.`,
+    ...(policy.unknown_vendors.length > 0
+      ? [`# Not in the vendor catalog, so no egress is listed: ${policy.unknown_vendors.join(", ")}`]
+      : []),
+    "",
+    `approved_vendors = ${hclList(policy.approved_vendors, "")}`,
+    "",
+    `allowed_egress_domains = ${hclList(policy.allowed_egress_domains, "")}`,
+    "",
+    `allowed_egress_cidrs = ${hclList(policy.allowed_egress_cidrs, "")}`,
+    "",
+    vendors.length > 0 ? `vendor_egress = {\n${vendors.join("\n")}\n}` : "vendor_egress = {}",
+    "",
+  ].join("\n");
+}
+
+// ---------------------------------------------------------------------------
+// OPA
+// ---------------------------------------------------------------------------
+
+export const REGO_POLICY = `# Generated by thirdwatch. Evaluates against the approved-vendor list in data.thirdwatch.
+package thirdwatch.vendors
+
+import rego.v1
+
+# Egress: input {"host": "api.stripe.com"} or {"ip": "3.18.12.63"}
+default allow_egress := false
+
+allow_egress if {
+	host := lower(trim_suffix(input.host, "."))
+	some d in data.thirdwatch.domain_patterns
+	regex.match(d.regex, host)
+}
+
+allow_egress if {
+	some cidr in data.thirdwatch.allowed_egress_cidrs
+	net.cidr_contains(cidr, input.ip)
+}
+
+# Code review: input is a thirdwatch scan result (TDM); one message per unapproved vendor
+used_vendors contains sdk.provider if {
+	some sdk in input.sdks
+}
+
+used_vendors contains api.provider if {
+	some api in input.apis
+	is_string(api.provider)
+}
+
+used_vendors contains hook.provider if {
+	some hook in input.webhooks
+	is_string(hook.provider)
+}
+
+deny contains msg if {
+	some vendor in used_vendors
+	not vendor in data.thirdwatch.approved_vendors
+	msg := sprintf("%s is not an approved vendor", [vendor])
+}
+`;
+
+/** A minimal ustar archive of regular files */
+function tar(files: Array<{ name: string; data: Buffer }>, mtime: Date): Buffer {
+  const blocks: Buffer[] = [];
+  for (const file of files) {
+    const header = Buffer.alloc(512);
+    header.write(file.name, 0, 100, "utf8");
+    header.write("0000644\0", 100, "ascii");
+    header.write("0000000\0", 108, "ascii");
+    header.write("0000000\0", 116, "ascii");
+    header.write(file.data.length.toString(8).padStart(11, "0") + "\0", 124, "ascii");
+    header.write(Math.floor(mtime.getTime() / 1000).toString(8).padStart(11, "0") + "\0", 136, "ascii");
+    header.write("        ", 148, "ascii");
+    header.write("0", 156, "ascii");
+    header.write("ustar\0", 257, "ascii");
+    header.write("00", 263, "ascii");
+    let sum = 0;
+    for (const byte of header) sum += byte;
+    header.write(sum.toString(8).padStart(6, "0") + "\0 ", 148, "ascii");
+    blocks.push(header, file.data, Buffer.alloc((512 - (file.data.length % 512)) % 512));
+  }
+  blocks.push(Buffer.alloc(1024));
+  return Buffer.concat(blocks);
+}
+
+/** The data document the Rego policy reads as data.thirdwatch */
+export function opaData(policy: VendorPolicy) {
+  return {
+    approved_vendors: policy.approved_vendors,
+    allowed_egress_domains: policy.allowed_egress_domains,
+    allowed_egress_cidrs: policy.allowed_egress_cidrs,
+    domain_patterns: policy.allowed_egress_domains.map((pattern) => ({ pattern, regex: domainPatternRegex(pattern) })),
+    vendor_egress: policy.vendor_egress,
+  };
+}
+
+/** An OPA bundle (`opa run --bundle`, or served to OPA's bundle API) rooted at "thirdwatch" */
+export function buildOpaBundle(policy: VendorPolicy): Buffer {
+  const manifest = { revision: policy.generated_at, roots: ["thirdwatch"] };
+  const files = [
+    { name: ".manifest", data: Buffer.from(JSON.stringify(manifest, null, 2) + "\n") },
+    { name: "thirdwatch/data.json", data: Buffer.from(JSON.stringify(opaData(policy), null, 2) + "\n") },
+    { name: "thirdwatch/vendors.rego", data: Buffer.from(REGO_POLICY) },
+  ];
+  return gzipSync(tar(files, new Date(policy.generated_at)));
+}
@@ -0,0 +1,16 @@
+/**
+ * @module webhook-signature
+ *
+ * The signature on every webhook Thirdwatch sends: change notifications,
+ * egress alerts, and the vendor feed. Receivers recompute the HMAC-SHA256
+ * of the raw body with their shared secret and compare it to the header:
+ *
+ *   X-Thirdwatch-Signature: sha256=<hex hmac of the body>
+ */
+
+import { createHmac } from "node:crypto";
+
+/** The X-Thirdwatch-Signature value for `payload`, the exact body sent */
+export function signPayload(payload: string, secret: string): string {
+  return "sha256=" + createHmac("sha256", secret).update(payload).digest("hex");
+}
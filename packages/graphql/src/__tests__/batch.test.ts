@@ -0,0 +1,72 @@
+import { describe, it, expect } from "vitest";
+import { batchLoader } from "../batch.js";
+import { execute } from "../execute.js";
+import type { Schema } from "../execute.js";
+
+describe("batchLoader", () => {
+  it("answers keys requested together with one call", async () => {
+    const calls: string[][] = [];
+    const load = batchLoader(async (keys: string[]) => {
+      calls.push(keys);
+      return new Map(keys.map((k) => [k, k.toUpperCase()]));
+    }, "");
+    const first = load("a");
+    // A key requested a few microtasks later still joins the batch
+    const second = Promise.resolve().then(() => Promise.resolve()).then(() => load("b"));
+    expect(await Promise.all([first, second, load("a")])).toEqual(["A", "B", "A"]);
+    expect(calls).toEqual([["a", "b"]]);
+  });
+
+  it("caches answers and starts a new batch for new keys", async () => {
+    const calls: string[][] = [];
+    const load = batchLoader(async (keys: string[]) => {
+      calls.push(keys);
+      return new Map(keys.map((k) => [k, k.length]));
+    }, 0);
+    expect(await load("one")).toBe(3);
+    expect(await Promise.all([load("one"), load("three")])).toEqual([3, 5]);
+    expect(calls).toEqual([["one"], ["three"]]);
+  });
+
+  it("resolves keys the batch left out to the fallback", async () => {
+    const load = batchLoader(async () => new Map<string, string[]>(), []);
+    expect(await load("missing")).toEqual([]);
+  });
+
+  it("rejects every key of a failed batch", async () => {
+    const load = batchLoader<string, number>(async () => {
+      throw new Error("database unavailable");
+    }, 0);
+    const results = await Promise.allSettled([load("a"), load("b")]);
+    expect(results.map((r) => r.status)).toEqual(["rejected", "rejected"]);
+  });
+
+  it("turns one query per list item into one per request", async () => {
+    const calls: string[][] = [];
+    const schema: Schema<{ changes: (repo: string) => Promise<string[]> }> = {
+      query: "Query",
+      types: {
+        Query: { fields: { repositories: { type: "[Repository!]!", resolve: () => [{ name: "a" }, { name: "b" }, { name: "c" }] } } },
+        Repository: {
+          fields: {
+            name: { type: "String!" },
+            changes: { type: "[String!]!", resolve: (r: { name: string }, _, ctx) => ctx.changes(r.name) },
+          },
+        },
+      },
+    };
+    const changes = batchLoader(async (repos: string[]) => {
+      calls.push(repos);
+      return new Map(repos.filter((r) => r !== "b").map((r) => [r, [`${r}-1`]]));
+    }, [] as string[]);
+    const result = await execute(schema, { query: "{ repositories { name changes } }" }, { changes });
+    expect(result.data).toEqual({
+      repositories: [
+        { name: "a", changes: ["a-1"] },
+        { name: "b", changes: [] },
+        { name: "c", changes: ["c-1"] },
+      ],
+    });
+    expect(calls).toEqual([["a", "b", "c"]]);
+  });
+});
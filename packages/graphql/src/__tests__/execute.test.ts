@@ -0,0 +1,329 @@
+import { describe, it, expect } from "vitest";
+import { execute, printSchema, MAX_DEPTH, MAX_QUERY_LENGTH } from "../execute.js";
+import type { Schema } from "../execute.js";
+
+interface Repo {
+  name: string;
+  stars: number | null;
+  owner: { login: string | null };
+}
+
+const REPOS: Repo[] = [
+  { name: "checkout", stars: 12, owner: { login: "acme" } },
+  { name: "ledger", stars: null, owner: { login: null } },
+];
+
+interface Context {
+  user: string;
+}
+
+const schema: Schema<Context> = {
+  query: "Query",
+  types: {
+    Query: {
+      description: "Entry points",
+      fields: {
+        hello: {
+          type: "String!",
+          args: { name: { type: "String", defaultValue: "world" } },
+          resolve: (_, args: { name: string }) => `hello ${args.name}`,
+        },
+        whoami: { type: "String!", resolve: (_, __, ctx) => ctx.user },
+        repos: { type: "[Repo!]!", resolve: () => REPOS },
+        nullableRepos: { type: "[Repo]", resolve: () => REPOS },
+        repo: {
+          type: "Repo",
+          args: { name: { type: "String!" } },
+          resolve: (_, args: { name: string }) => REPOS.find((r) => r.name === args.name) ?? null,
+        },
+        echo: {
+          type: "[String!]",
+          description: "Returns its argument",
+          args: { values: { type: "[String!]" } },
+          resolve: (_, args: { values?: string[] | null }) => args.values ?? null,
+        },
+        double: { type: "Int", args: { n: { type: "Int!" } }, resolve: (_, args: { n: number }) => args.n * 2 },
+        broken: {
+          type: "String",
+          resolve: () => {
+            throw new Error("upstream timed out");
+          },
+        },
+        brokenRequired: {
+          type: "String!",
+          resolve: () => {
+            throw new Error("upstream timed out");
+          },
+        },
+        when: { type: "String", resolve: () => new Date("2026-10-14T10:00:00.000Z") },
+        wrongType: { type: "Int", resolve: () => "seven" },
+        node: { type: "Node", resolve: () => ({ id: 1 }) },
+      },
+    },
+    Repo: {
+      fields: {
+        name: { type: "String!" },
+        stars: { type: "Int" },
+        owner: { type: "Owner!" },
+      },
+    },
+    Owner: {
+      fields: {
+        login: { type: "String!" },
+        greeting: { type: "String" },
+      },
+    },
+    Node: {
+      fields: {
+        id: { type: "ID!" },
+        next: { type: "Node", resolve: (n: { id: number }) => ({ id: n.id + 1 }) },
+      },
+    },
+  },
+};
+
+const ctx: Context = { user: "jdoe" };
+
+function run(query: string, variables?: Record<string, unknown>, operationName?: string) {
+  return execute(schema, { query, variables, operationName }, ctx);
+}
+
+describe("execute", () => {
+  it("resolves fields, defaults, context, and aliases", async () => {
+    const result = await run(`{ hello greet: hello(name: "ops") whoami repos { name stars } }`);
+    expect(result).toEqual({
+      data: {
+        hello: "hello world",
+        greet: "hello ops",
+        whoami: "jdoe",
+        repos: [
+          { name: "checkout", stars: 12 },
+          { name: "ledger", stars: null },
+        ],
+      },
+    });
+  });
+
+  it("calls property functions on the parent with arguments and context", async () => {
+    const result = await execute(
+      {
+        query: "Query",
+        types: { Query: { fields: { greeting: { type: "String", args: { name: { type: "String!" } } } } } },
+      },
+      { query: `{ greeting(name: "ops") }` },
+      ctx,
+    );
+    expect(result.data).toEqual({ greeting: null });
+
+    const root = {
+      query: "Query",
+      types: {
+        Query: { fields: { owner: { type: "Owner", resolve: () => ({ greeting: (_: unknown, c: Context) => `hi ${c.user}` }) } } },
+        Owner: { fields: { greeting: { type: "String" } } },
+      },
+    };
+    expect(await execute(root, { query: "{ owner { greeting } }" }, ctx)).toEqual({ data: { owner: { greeting: "hi jdoe" } } });
+  });
+
+  it("answers __typename and serializes dates as strings", async () => {
+    const result = await run(`{ __typename when repo(name: "checkout") { __typename } }`);
+    expect(result.data).toEqual({ __typename: "Query", when: "2026-10-14T10:00:00.000Z", repo: { __typename: "Repo" } });
+  });
+
+  it("merges fields from fragments sharing a response key", async () => {
+    const result = await run(`
+      { repo(name: "checkout") { ...Name ... on Repo { owner { login } } owner { greeting } } }
+      fragment Name on Repo { name }
+    `);
+    expect(result.data).toEqual({ repo: { name: "checkout", owner: { login: "acme", greeting: null } } });
+  });
+
+  it("applies @skip and @include", async () => {
+    const result = await run(`query ($skip: Boolean!) { hello @skip(if: $skip) whoami @include(if: false) double(n: 2) }`, {
+      skip: true,
+    });
+    expect(result.data).toEqual({ double: 4 });
+  });
+
+  it("selects the operation by name", async () => {
+    const query = `query A { hello } query B { whoami }`;
+    expect((await run(query, undefined, "B")).data).toEqual({ whoami: "jdoe" });
+    expect(await run(query)).toEqual({
+      errors: [{ message: "Must provide operationName when the document contains several operations" }],
+    });
+    expect(await run(query, undefined, "C")).toEqual({ errors: [{ message: 'Unknown operation named "C"' }] });
+  });
+});
+
+describe("variables", () => {
+  it("coerces variables and uses their defaults", async () => {
+    const query = `query ($n: Int = 21, $values: [String!]) { double(n: $n) echo(values: $values) }`;
+    expect((await run(query)).data).toEqual({ double: 42, echo: null });
+    expect((await run(query, { n: 5, values: ["a", "b"] })).data).toEqual({ double: 10, echo: ["a", "b"] });
+  });
+
+  it("accepts a single value where a list is expected", async () => {
+    expect((await run(`query ($v: [String!]) { echo(values: $v) }`, { v: "solo" })).data).toEqual({ echo: ["solo"] });
+  });
+
+  it("treats an unset variable like an omitted argument", async () => {
+    expect((await run(`query ($name: String) { hello(name: $name) }`)).data).toEqual({ hello: "hello world" });
+  });
+
+  it("rejects values of the wrong type", async () => {
+    expect(await run(`query ($n: Int!) { double(n: $n) }`, { n: "2" })).toEqual({
+      errors: [{ message: 'Variable "$n": expected Int, got "2"' }],
+    });
+    expect(await run(`query ($n: Int!) { double(n: $n) }`, { n: 2 ** 40 })).toEqual({
+      errors: [{ message: `Variable "$n": expected Int, got ${2 ** 40}` }],
+    });
+    expect(await run(`query ($n: Int!) { double(n: $n) }`)).toEqual({
+      errors: [{ message: 'Variable "$n": expected non-null Int!' }],
+    });
+  });
+
+  it("reports a bad literal argument on its field", async () => {
+    const result = await run(`{ double(n: "two") hello }`);
+    expect(result).toEqual({
+      data: { double: null, hello: "hello world" },
+      errors: [{ message: 'Argument "n" of "Query.double": expected Int, got "two"', path: ["double"] }],
+    });
+  });
+});
+
+describe("errors and null propagation", () => {
+  it("nulls a failing nullable field and keeps the rest", async () => {
+    const result = await run(`{ broken hello }`);
+    expect(result).toEqual({
+      data: { broken: null, hello: "hello world" },
+      errors: [{ message: "upstream timed out", path: ["broken"] }],
+    });
+  });
+
+  it("nulls the nearest nullable parent of a failing non-null field", async () => {
+    const result = await run(`{ repo(name: "ledger") { name owner { login } } hello }`);
+    expect(result).toEqual({
+      data: { repo: null, hello: "hello world" },
+      errors: [{ message: "Cannot return null for non-nullable field", path: ["repo", "owner", "login"] }],
+    });
+  });
+
+  it("nulls only the failing item of a list with nullable items", async () => {
+    const result = await run(`{ nullableRepos { name owner { login } } }`);
+    expect(result).toEqual({
+      data: { nullableRepos: [{ name: "checkout", owner: { login: "acme" } }, null] },
+      errors: [{ message: "Cannot return null for non-nullable field", path: ["nullableRepos", 1, "owner", "login"] }],
+    });
+  });
+
+  it("nulls all data when a non-null root field fails", async () => {
+    expect(await run(`{ hello brokenRequired }`)).toEqual({
+      data: null,
+      errors: [{ message: "upstream timed out", path: ["brokenRequired"] }],
+    });
+    expect(await run(`{ repos { owner { login } } }`)).toEqual({
+      data: null,
+      errors: [{ message: "Cannot return null for non-nullable field", path: ["repos", 1, "owner", "login"] }],
+    });
+  });
+
+  it("reports a resolver value of the wrong type", async () => {
+    expect(await run(`{ wrongType }`)).toEqual({
+      data: { wrongType: null },
+      errors: [{ message: 'Result: expected Int, got "seven"', path: ["wrongType"] }],
+    });
+  });
+
+  it("returns syntax errors without data", async () => {
+    expect(await run(`{ hello`)).toEqual({
+      errors: [{ message: "Syntax error: expected a name, found end of document at position 7" }],
+    });
+  });
+
+  it("refuses mutations and over-long queries", async () => {
+    expect(await run(`mutation { hello }`)).toEqual({
+      errors: [{ message: "Only queries are supported; this API is read-only" }],
+    });
+    expect(await run(`{ hello }`.padEnd(MAX_QUERY_LENGTH + 1, " "))).toEqual({
+      errors: [{ message: `Query is longer than ${MAX_QUERY_LENGTH} characters` }],
+    });
+  });
+});
+
+describe("validation", () => {
+  async function errors(query: string): Promise<string[]> {
+    const result = await run(query);
+    expect(result.data).toBeUndefined();
+    return (result.errors ?? []).map((e) => e.message);
+  }
+
+  it("rejects unknown fields, arguments, and directives", async () => {
+    expect(await errors(`{ nope repo(name: "a", extra: 1) { name } hello @cache }`)).toEqual([
+      'Cannot query field "nope" on type "Query"',
+      'Unknown argument "extra" on field "Query.repo"',
+      'Unknown directive "@cache"',
+    ]);
+  });
+
+  it("requires required arguments and the if of @skip", async () => {
+    expect(await errors(`{ repo { name } hello @skip }`)).toEqual([
+      'Field "Query.repo" argument "name" of type "String!" is required',
+      'Directive "@skip" requires argument "if"',
+    ]);
+  });
+
+  it("checks selections against leaf and object types", async () => {
+    expect(await errors(`{ hello { length } repos }`)).toEqual([
+      'Field "hello" must not have a selection since type "String!" has no subfields',
+      'Field "repos" of type "[Repo!]!" must have a selection of subfields',
+    ]);
+  });
+
+  it("rejects undefined variables and non-scalar variable types", async () => {
+    expect(await errors(`query ($r: Repo) { double(n: $n) }`)).toEqual([
+      'Variable "$r" has unknown input type "Repo"',
+      'Variable "$n" is not defined',
+    ]);
+  });
+
+  it("rejects unknown and mistyped fragments", async () => {
+    expect(await errors(`{ ...Missing repo(name: "a") { ...OnOwner ... on Owner { login } } } fragment OnOwner on Owner { login }`)).toEqual([
+      'Unknown fragment "Missing"',
+      'Fragment "OnOwner" on "Owner" cannot be spread on "Repo"',
+      'Inline fragment on "Owner" cannot be spread on "Repo"',
+    ]);
+  });
+
+  it("rejects fragment cycles instead of recursing", async () => {
+    expect(
+      await errors(`
+        { repo(name: "a") { ...A } }
+        fragment A on Repo { name ...B }
+        fragment B on Repo { stars ...A }
+      `),
+    ).toEqual(['Fragment "A" spreads itself']);
+    expect(await errors(`{ repo(name: "a") { ...Self } } fragment Self on Repo { ...Self }`)).toEqual([
+      'Fragment "Self" spreads itself',
+    ]);
+  });
+
+  it("limits query depth, counting through fragments", async () => {
+    const nested = (depth: number) => `{ node ${"{ next ".repeat(depth - 2)}{ id }${" }".repeat(depth - 2)} }`;
+    expect((await run(nested(MAX_DEPTH))).errors).toBeUndefined();
+    expect(await errors(nested(MAX_DEPTH + 1))).toEqual([`Query is nested deeper than ${MAX_DEPTH} levels`]);
+
+    const viaFragment = `{ node { ...Deep } } fragment Deep on Node { ${"next { ".repeat(MAX_DEPTH - 1)}id${" }".repeat(MAX_DEPTH - 1)} }`;
+    expect(await errors(viaFragment)).toEqual([`Query is nested deeper than ${MAX_DEPTH} levels`]);
+  });
+});
+
+describe("printSchema", () => {
+  it("prints SDL with descriptions, arguments, and defaults", () => {
+    const sdl = printSchema(schema);
+    expect(sdl.startsWith("schema {\n  query: Query\n}\n\n")).toBe(true);
+    expect(sdl).toContain('"""Entry points"""\ntype Query {');
+    expect(sdl).toContain('  hello(name: String = "world"): String!');
+    expect(sdl).toContain('  """Returns its argument"""\n  echo(values: [String!]): [String!]');
+    expect(sdl).toContain("type Node {\n  id: ID!\n  next: Node\n}");
+  });
+});
@@ -0,0 +1,126 @@
+import { describe, it, expect } from "vitest";
+import { GraphQLError, namedType, parse, parseType, printType } from "../language.js";
+import type { FieldNode } from "../language.js";
+
+function firstField(source: string): FieldNode {
+  const sel = parse(source).operations[0]!.selections[0]!;
+  if (sel.kind !== "Field") throw new Error(`expected a field, got ${sel.kind}`);
+  return sel;
+}
+
+describe("parse", () => {
+  it("parses a shorthand query", () => {
+    const doc = parse("{ repositories { name } }");
+    expect(doc.operations).toHaveLength(1);
+    expect(doc.operations[0]!.operation).toBe("query");
+    expect(doc.operations[0]!.name).toBeUndefined();
+    const field = firstField("{ repositories { name } }");
+    expect(field.name).toBe("repositories");
+    expect(field.selections).toEqual([
+      { kind: "Field", alias: undefined, name: "name", args: {}, directives: [], selections: undefined },
+    ]);
+  });
+
+  it("parses named operations with variables and defaults", () => {
+    const doc = parse(`query Inventory($vendor: [String!]!, $limit: Int = 20) { changes(limit: $limit) { id } }`);
+    const op = doc.operations[0]!;
+    expect(op.name).toBe("Inventory");
+    expect(op.variables).toEqual([
+      {
+        name: "vendor",
+        type: { kind: "NonNull", type: { kind: "List", type: { kind: "NonNull", type: { kind: "Named", name: "String" } } } },
+        defaultValue: undefined,
+      },
+      { name: "limit", type: { kind: "Named", name: "Int" }, defaultValue: { kind: "Int", value: 20 } },
+    ]);
+  });
+
+  it("parses aliases and every literal kind", () => {
+    const field = firstField(
+      `{ top: search(i: -3, f: 1.5e2, s: "a\\n\\u0041", b: true, n: null, e: ACTIVE, l: [1, 2], o: { k: $v }) }`,
+    );
+    expect(field.alias).toBe("top");
+    expect(field.name).toBe("search");
+    expect(field.args).toEqual({
+      i: { kind: "Int", value: -3 },
+      f: { kind: "Float", value: 150 },
+      s: { kind: "String", value: "a\nA" },
+      b: { kind: "Boolean", value: true },
+      n: { kind: "Null" },
+      e: { kind: "Enum", value: "ACTIVE" },
+      l: { kind: "List", values: [{ kind: "Int", value: 1 }, { kind: "Int", value: 2 }] },
+      o: { kind: "Object", fields: { k: { kind: "Variable", name: "v" } } },
+    });
+  });
+
+  it("dedents block strings", () => {
+    const field = firstField(`{ f(s: """
+        first
+          indented
+      """) }`);
+    expect(field.args["s"]).toEqual({ kind: "String", value: "first\n  indented" });
+  });
+
+  it("ignores comments, commas, and a BOM", () => {
+    const field = firstField(`﻿# inventory\n{ a, # trailing\n b }`);
+    expect(field.name).toBe("a");
+  });
+
+  it("parses fragments, inline fragments, and directives", () => {
+    const doc = parse(`
+      query { repositories { ...Basics ... on Repository @include(if: $x) { languages } } }
+      fragment Basics on Repository { name scannedAt @skip(if: true) }
+    `);
+    const repos = doc.operations[0]!.selections[0] as FieldNode;
+    expect(repos.selections![0]).toEqual({ kind: "FragmentSpread", name: "Basics", directives: [] });
+    expect(repos.selections![1]).toMatchObject({
+      kind: "InlineFragment",
+      typeCondition: "Repository",
+      directives: [{ name: "include", args: { if: { kind: "Variable", name: "x" } } }],
+    });
+    expect(doc.fragments.get("Basics")).toMatchObject({ name: "Basics", typeCondition: "Repository" });
+  });
+
+  it("reports syntax errors with their position", () => {
+    expect(() => parse("{ repositories { name }")).toThrow('Syntax error: expected a name, found end of document at position 23');
+    expect(() => parse("{ a(x: ) }")).toThrow('Syntax error: expected a value, found ")" at position 7');
+    expect(() => parse('{ a(s: "open) }')).toThrow("Syntax error: unterminated string at position 7");
+    expect(() => parse("{ a(x: 01) }")).toThrow("Syntax error: invalid number at position 7");
+    expect(() => parse("{ a(x: 1a) }")).toThrow("Syntax error: invalid number at position 7");
+    expect(() => parse('{ a(s: "\\q") }')).toThrow("Syntax error: invalid escape at position 8");
+    expect(() => parse("{ a.b }")).toThrow('Syntax error: unexpected "." at position 3');
+    expect(() => parse("{ a ~ }")).toThrow('Syntax error: unexpected character "~" at position 4');
+    expect(() => parse("{ }")).toThrow("Syntax error: expected a selection");
+    expect(() => parse("")).toThrow("Syntax error: expected an operation or fragment, found end of document at position 0");
+  });
+
+  it("throws GraphQLError for invalid documents", () => {
+    expect(() => parse("{ a }")).not.toThrow();
+    expect(() => parse("{ a(x: 1, x: 2) }")).toThrow(GraphQLError);
+    expect(() => parse("{ a(x: 1, x: 2) }")).toThrow('There can be only one argument named "x"');
+    expect(() => parse("{ a } fragment F on T { a } fragment F on T { b }")).toThrow(
+      'There can be only one fragment named "F"',
+    );
+    expect(() => parse("fragment on on T { a }")).toThrow("expected a fragment name");
+  });
+
+  it("rejects variables in default values", () => {
+    expect(() => parse("query ($a: Int = $b) { a }")).toThrow('Syntax error: expected a value, found "$"');
+  });
+});
+
+describe("type references", () => {
+  it("round-trips through parseType and printType", () => {
+    for (const source of ["String", "String!", "[String]", "[String!]!", "[[Int]!]"]) {
+      expect(printType(parseType(source))).toBe(source);
+    }
+  });
+
+  it("finds the named type under wrappers", () => {
+    expect(namedType(parseType("[Repository!]!"))).toBe("Repository");
+  });
+
+  it("rejects trailing input", () => {
+    expect(() => parseType("String! x")).toThrow('Syntax error: expected end of type, found "x" at position 8');
+  });
+});
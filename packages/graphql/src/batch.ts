@@ -0,0 +1,39 @@
+// ---------------------------------------------------------------------------
+// Per-request batching — resolvers for the items of a list run concurrently,
+// so a field that would query once per item (repository → changes) instead
+// asks a loader, which answers every key requested before the next
+// macrotask with one `loadMany` call and caches the answers for the request.
+// ---------------------------------------------------------------------------
+
+export type Loader<K, V> = (key: K) => Promise<V>;
+
+/**
+ * `loadMany` receives each distinct key once and returns a map of results;
+ * keys it leaves out resolve to `missing`. If it throws, every key in the
+ * batch rejects with the error. Create one loader per request: answers are
+ * cached for the loader's lifetime.
+ */
+export function batchLoader<K, V>(loadMany: (keys: K[]) => Promise<Map<K, V>>, missing: V): Loader<K, V> {
+  const cache = new Map<K, Promise<V>>();
+  let queue: K[] = [];
+  let batch: Promise<Map<K, V>> | undefined;
+
+  return (key) => {
+    let value = cache.get(key);
+    if (value) return value;
+    if (!batch) {
+      // setImmediate runs after every pending promise job, so resolvers that
+      // start on different microtasks of the same pass still share a batch
+      batch = new Promise<void>((resolve) => setImmediate(resolve)).then(() => {
+        const keys = queue;
+        queue = [];
+        batch = undefined;
+        return loadMany(keys);
+      });
+    }
+    queue.push(key);
+    value = batch.then((results) => (results.has(key) ? results.get(key)! : missing));
+    cache.set(key, value);
+    return value;
+  };
+}
@@ -0,0 +1,469 @@
+// ---------------------------------------------------------------------------
+// GraphQL execution — a code-first schema of object types and scalars,
+// validation of a query against it, and execution with per-field error
+// handling: a failing nullable field becomes null with an entry in `errors`,
+// a failing non-null field nulls its parent instead.
+// ---------------------------------------------------------------------------
+
+import {
+  GraphQLError,
+  namedType,
+  parse,
+  parseType,
+  printType,
+} from "./language.js";
+import type {
+  DirectiveNode,
+  DocumentNode,
+  FieldNode,
+  OperationNode,
+  SelectionNode,
+  TypeNode,
+  ValueNode,
+} from "./language.js";
+
+/**
+ * Resolvers receive the parent object, coerced arguments, and the request
+ * context. Fields without one read the parent's property of the same name,
+ * calling it with (args, context) when it is a function.
+ */
+export type Resolver<C> = (parent: any, args: any, context: C) => unknown;
+
+export interface ArgumentDefinition {
+  /** Type reference, e.g. "[String!]" */
+  type: string;
+  defaultValue?: unknown;
+  description?: string;
+}
+
+export interface FieldDefinition<C> {
+  type: string;
+  args?: Record<string, ArgumentDefinition>;
+  description?: string;
+  resolve?: Resolver<C>;
+}
+
+export interface ObjectTypeDefinition<C> {
+  description?: string;
+  fields: Record<string, FieldDefinition<C>>;
+}
+
+export interface Schema<C> {
+  query: string;
+  types: Record<string, ObjectTypeDefinition<C>>;
+}
+
+export interface ExecutionRequest {
+  query: string;
+  variables?: Record<string, unknown> | null | undefined;
+  operationName?: string | null | undefined;
+}
+
+export interface ExecutionResult {
+  data?: Record<string, unknown> | null;
+  errors?: Array<{ message: string; path?: Array<string | number> }>;
+}
+
+const SCALARS = new Set(["String", "Int", "Float", "Boolean", "ID"]);
+/** Deeper queries are almost always accidental fan-out (repository → vendor → repository → …) */
+export const MAX_DEPTH = 8;
+export const MAX_QUERY_LENGTH = 20_000;
+
+// ---------------------------------------------------------------------------
+// Schema printing
+// ---------------------------------------------------------------------------
+
+function describe(description: string | undefined, indent: string): string {
+  return description ? `${indent}"""${description}"""\n` : "";
+}
+
+function printLiteral(value: unknown): string {
+  return Array.isArray(value) ? `[${value.map(printLiteral).join(", ")}]` : JSON.stringify(value);
+}
+
+/** SDL for the schema, served so portal developers can see what they can ask */
+export function printSchema<C>(schema: Schema<C>): string {
+  const blocks = Object.entries(schema.types).map(([name, type]) => {
+    const fields = Object.entries(type.fields).map(([fieldName, field]) => {
+      const args = Object.entries(field.args ?? {}).map(
+        ([argName, arg]) => `${argName}: ${arg.type}${arg.defaultValue !== undefined ? ` = ${printLiteral(arg.defaultValue)}` : ""}`,
+      );
+      return `${describe(field.description, "  ")}  ${fieldName}${args.length > 0 ? `(${args.join(", ")})` : ""}: ${field.type}`;
+    });
+    return `${describe(type.description, "")}type ${name} {\n${fields.join("\n")}\n}`;
+  });
+  return `schema {\n  query: ${schema.query}\n}\n\n${blocks.join("\n\n")}\n`;
+}
+
+// ---------------------------------------------------------------------------
+// Validation
+// ---------------------------------------------------------------------------
+
+class Validator<C> {
+  readonly errors: string[] = [];
+  private readonly schema: Schema<C>;
+  private readonly doc: DocumentNode;
+  private readonly variables: Set<string>;
+
+  constructor(schema: Schema<C>, doc: DocumentNode, operation: OperationNode) {
+    this.schema = schema;
+    this.doc = doc;
+    this.variables = new Set(operation.variables.map((v) => v.name));
+    for (const v of operation.variables) {
+      const name = namedType(v.type);
+      if (!SCALARS.has(name)) this.errors.push(`Variable "$${v.name}" has unknown input type "${printType(v.type)}"`);
+    }
+    this.selections(schema.query, operation.selections, 1, []);
+  }
+
+  private values(args: Record<string, ValueNode>): void {
+    const visit = (value: ValueNode) => {
+      if (value.kind === "Variable" && !this.variables.has(value.name)) {
+        this.errors.push(`Variable "$${value.name}" is not defined`);
+      } else if (value.kind === "List") {
+        value.values.forEach(visit);
+      } else if (value.kind === "Object") {
+        Object.values(value.fields).forEach(visit);
+      }
+    };
+    Object.values(args).forEach(visit);
+  }
+
+  private directives(directives: DirectiveNode[]): void {
+    for (const d of directives) {
+      if (d.name !== "skip" && d.name !== "include") this.errors.push(`Unknown directive "@${d.name}"`);
+      else if (!("if" in d.args)) this.errors.push(`Directive "@${d.name}" requires argument "if"`);
+      this.values(d.args);
+    }
+  }
+
+  private selections(typeName: string, selections: SelectionNode[], depth: number, fragments: string[]): void {
+    if (depth > MAX_DEPTH) {
+      this.errors.push(`Query is nested deeper than ${MAX_DEPTH} levels`);
+      return;
+    }
+    const type = this.schema.types[typeName]!;
+    for (const sel of selections) {
+      this.directives(sel.directives);
+      if (sel.kind === "FragmentSpread") {
+        const fragment = this.doc.fragments.get(sel.name);
+        if (!fragment) {
+          this.errors.push(`Unknown fragment "${sel.name}"`);
+        } else if (fragments.includes(sel.name)) {
+          this.errors.push(`Fragment "${sel.name}" spreads itself`);
+        } else if (fragment.typeCondition !== typeName) {
+          this.errors.push(`Fragment "${sel.name}" on "${fragment.typeCondition}" cannot be spread on "${typeName}"`);
+        } else {
+          this.selections(typeName, fragment.selections, depth, [...fragments, sel.name]);
+        }
+        continue;
+      }
+      if (sel.kind === "InlineFragment") {
+        if (sel.typeCondition && sel.typeCondition !== typeName) {
+          this.errors.push(`Inline fragment on "${sel.typeCondition}" cannot be spread on "${typeName}"`);
+        } else {
+          this.selections(typeName, sel.selections, depth, fragments);
+        }
+        continue;
+      }
+      this.field(typeName, type, sel, depth, fragments);
+    }
+  }
+
+  private field(typeName: string, type: ObjectTypeDefinition<C>, sel: FieldNode, depth: number, fragments: string[]): void {
+    this.values(sel.args);
+    if (sel.name === "__typename") {
+      if (sel.selections) this.errors.push(`Field "__typename" must not have a selection`);
+      return;
+    }
+    const field = type.fields[sel.name];
+    if (!field) {
+      this.errors.push(`Cannot query field "${sel.name}" on type "${typeName}"`);
+      return;
+    }
+    const defs = field.args ?? {};
+    for (const arg of Object.keys(sel.args)) {
+      if (!(arg in defs)) this.errors.push(`Unknown argument "${arg}" on field "${typeName}.${sel.name}"`);
+    }
+    for (const [arg, def] of Object.entries(defs)) {
+      if (def.type.endsWith("!") && def.defaultValue === undefined && !(arg in sel.args)) {
+        this.errors.push(`Field "${typeName}.${sel.name}" argument "${arg}" of type "${def.type}" is required`);
+      }
+    }
+    const target = namedType(parseType(field.type));
+    if (SCALARS.has(target)) {
+      if (sel.selections) this.errors.push(`Field "${sel.name}" must not have a selection since type "${field.type}" has no subfields`);
+    } else if (!sel.selections) {
+      this.errors.push(`Field "${sel.name}" of type "${field.type}" must have a selection of subfields`);
+    } else {
+      this.selections(target, sel.selections, depth + 1, fragments);
+    }
+  }
+}
+
+// ---------------------------------------------------------------------------
+// Values
+// ---------------------------------------------------------------------------
+
+function coerceScalar(name: string, value: unknown, where: string): unknown {
+  switch (name) {
+    case "String":
+      if (typeof value === "string") return value;
+      break;
+    case "ID":
+      if (typeof value === "string") return value;
+      if (typeof value === "number" && Number.isInteger(value)) return String(value);
+      break;
+    case "Int":
+      if (typeof value === "number" && Number.isInteger(value) && Math.abs(value) <= 2 ** 31) return value;
+      break;
+    case "Float":
+      if (typeof value === "number" && Number.isFinite(value)) return value;
+      break;
+    case "Boolean":
+      if (typeof value === "boolean") return value;
+      break;
+  }
+  throw new GraphQLError(`${where}: expected ${name}, got ${JSON.stringify(value)}`);
+}
+
+/** Coerce a JSON variable value or a resolved literal to an input type */
+function coerceInput(type: TypeNode, value: unknown, where: string): unknown {
+  if (type.kind === "NonNull") {
+    if (value === null || value === undefined) throw new GraphQLError(`${where}: expected non-null ${printType(type)}`);
+    return coerceInput(type.type, value, where);
+  }
+  if (value === null || value === undefined) return null;
+  if (type.kind === "List") {
+    // A single value is accepted where a list is expected
+    const items = Array.isArray(value) ? value : [value];
+    return items.map((item) => coerceInput(type.type, item, where));
+  }
+  return coerceScalar(type.name, value, where);
+}
+
+function literal(node: ValueNode, variables: Record<string, unknown>): unknown {
+  switch (node.kind) {
+    case "Variable":
+      return variables[node.name];
+    case "Null":
+      return null;
+    case "List":
+      return node.values.map((v) => literal(v, variables));
+    case "Object":
+      return Object.fromEntries(Object.entries(node.fields).map(([k, v]) => [k, literal(v, variables)]));
+    default:
+      return node.value;
+  }
+}
+
+function coerceArguments(
+  defs: Record<string, ArgumentDefinition>,
+  nodes: Record<string, ValueNode>,
+  variables: Record<string, unknown>,
+  where: string,
+): Record<string, unknown> {
+  const args: Record<string, unknown> = {};
+  for (const [name, def] of Object.entries(defs)) {
+    const node = nodes[name];
+    // An unset variable behaves like an omitted argument
+    const raw = node === undefined || (node.kind === "Variable" && !(node.name in variables))
+      ? def.defaultValue
+      : literal(node, variables);
+    if (raw === undefined && !def.type.endsWith("!")) continue;
+    args[name] = coerceInput(parseType(def.type), raw, `Argument "${name}" of ${where}`);
+  }
+  return args;
+}
+
+// ---------------------------------------------------------------------------
+// Execution
+// ---------------------------------------------------------------------------
+
+class Execution<C> {
+  readonly errors: GraphQLError[] = [];
+  private readonly schema: Schema<C>;
+  private readonly doc: DocumentNode;
+  private readonly variables: Record<string, unknown>;
+  private readonly context: C;
+  private readonly types = new Map<string, TypeNode>();
+
+  constructor(schema: Schema<C>, doc: DocumentNode, variables: Record<string, unknown>, context: C) {
+    this.schema = schema;
+    this.doc = doc;
+    this.variables = variables;
+    this.context = context;
+  }
+
+  private typeRef(source: string): TypeNode {
+    let type = this.types.get(source);
+    if (!type) {
+      type = parseType(source);
+      this.types.set(source, type);
+    }
+    return type;
+  }
+
+  private included(directives: DirectiveNode[]): boolean {
+    for (const d of directives) {
+      const value = literal(d.args["if"] ?? { kind: "Null" }, this.variables);
+      if (d.name === "skip" && value === true) return false;
+      if (d.name === "include" && value !== true) return false;
+    }
+    return true;
+  }
+
+  /** Flatten fragments into response keys; fields sharing a key merge their selections */
+  private collect(selections: SelectionNode[], into = new Map<string, FieldNode[]>()): Map<string, FieldNode[]> {
+    for (const sel of selections) {
+      if (!this.included(sel.directives)) continue;
+      if (sel.kind === "Field") {
+        const key = sel.alias ?? sel.name;
+        into.set(key, [...(into.get(key) ?? []), sel]);
+      } else if (sel.kind === "InlineFragment") {
+        this.collect(sel.selections, into);
+      } else {
+        this.collect(this.doc.fragments.get(sel.name)!.selections, into);
+      }
+    }
+    return into;
+  }
+
+  async selectionSet(
+    typeName: string,
+    selections: SelectionNode[],
+    parent: unknown,
+    path: Array<string | number>,
+  ): Promise<Record<string, unknown>> {
+    const type = this.schema.types[typeName]!;
+    const fields = [...this.collect(selections)];
+    const values = await Promise.all(
+      fields.map(([key, nodes]) => this.field(typeName, type, nodes, parent, [...path, key])),
+    );
+    return Object.fromEntries(fields.map(([key], i) => [key, values[i]]));
+  }
+
+  private async field(
+    typeName: string,
+    type: ObjectTypeDefinition<C>,
+    nodes: FieldNode[],
+    parent: unknown,
+    path: Array<string | number>,
+  ): Promise<unknown> {
+    const node = nodes[0]!;
+    if (node.name === "__typename") return typeName;
+    const def = type.fields[node.name]!;
+    const fieldType = this.typeRef(def.type);
+    return this.catching(fieldType, path, async () => {
+      const args = coerceArguments(def.args ?? {}, node.args, this.variables, `"${typeName}.${node.name}"`);
+      let value: unknown;
+      if (def.resolve) {
+        value = await def.resolve(parent, args, this.context);
+      } else {
+        const prop = (parent as Record<string, unknown> | null)?.[node.name];
+        value = typeof prop === "function" ? await prop.call(parent, args, this.context) : prop;
+      }
+      const sub = nodes.flatMap((n) => n.selections ?? []);
+      return this.complete(fieldType, value, sub, path);
+    });
+  }
+
+  /** Run `fn`; errors null a nullable position and propagate through non-null ones */
+  private async catching(type: TypeNode, path: Array<string | number>, fn: () => Promise<unknown>): Promise<unknown> {
+    try {
+      return await fn();
+    } catch (err) {
+      const located = err instanceof GraphQLError && err.path
+        ? err
+        : new GraphQLError(err instanceof Error ? err.message : String(err), path);
+      if (type.kind === "NonNull") throw located;
+      this.errors.push(located);
+      return null;
+    }
+  }
+
+  private async complete(
+    type: TypeNode,
+    value: unknown,
+    selections: SelectionNode[],
+    path: Array<string | number>,
+  ): Promise<unknown> {
+    if (type.kind === "NonNull") {
+      const completed = await this.complete(type.type, value, selections, path);
+      if (completed === null) throw new GraphQLError("Cannot return null for non-nullable field", path);
+      return completed;
+    }
+    if (value === null || value === undefined) return null;
+    if (type.kind === "List") {
+      if (!Array.isArray(value)) throw new GraphQLError("Expected a list", path);
+      return Promise.all(
+        value.map((item, i) =>
+          this.catching(type.type, [...path, i], () => this.complete(type.type, item, selections, [...path, i])),
+        ),
+      );
+    }
+    if (SCALARS.has(type.name)) {
+      if (value instanceof Date && (type.name === "String" || type.name === "ID")) return value.toISOString();
+      return coerceScalar(type.name, value, "Result");
+    }
+    return this.selectionSet(type.name, selections, value, path);
+  }
+}
+
+function selectOperation(doc: DocumentNode, operationName: string | null | undefined): OperationNode {
+  if (operationName) {
+    const op = doc.operations.find((o) => o.name === operationName);
+    if (!op) throw new GraphQLError(`Unknown operation named "${operationName}"`);
+    return op;
+  }
+  if (doc.operations.length !== 1) {
+    throw new GraphQLError("Must provide operationName when the document contains several operations");
+  }
+  return doc.operations[0]!;
+}
+
+function fail(messages: string[]): ExecutionResult {
+  return { errors: messages.map((message) => ({ message })) };
+}
+
+/** Parse, validate, and execute a query; never throws for bad input */
+export async function execute<C>(schema: Schema<C>, request: ExecutionRequest, context: C): Promise<ExecutionResult> {
+  if (request.query.length > MAX_QUERY_LENGTH) return fail([`Query is longer than ${MAX_QUERY_LENGTH} characters`]);
+
+  let doc: DocumentNode;
+  let operation: OperationNode;
+  const variables: Record<string, unknown> = {};
+  try {
+    doc = parse(request.query);
+    operation = selectOperation(doc, request.operationName);
+    if (operation.operation !== "query") {
+      return fail([`Only queries are supported; this API is read-only`]);
+    }
+    const validation = new Validator(schema, doc, operation);
+    if (validation.errors.length > 0) return fail(validation.errors);
+    for (const v of operation.variables) {
+      const provided = request.variables?.[v.name];
+      const raw = provided !== undefined ? provided : v.defaultValue ? literal(v.defaultValue, {}) : undefined;
+      if (raw === undefined && v.type.kind !== "NonNull") continue;
+      variables[v.name] = coerceInput(v.type, raw, `Variable "$${v.name}"`);
+    }
+  } catch (err) {
+    return fail([err instanceof Error ? err.message : String(err)]);
+  }
+
+  const execution = new Execution(schema, doc, variables, context);
+  let data: Record<string, unknown> | null;
+  try {
+    data = await execution.selectionSet(schema.query, operation.selections, null, []);
+  } catch (err) {
+    // A non-null root field failed
+    execution.errors.push(err instanceof GraphQLError ? err : new GraphQLError(String(err)));
+    data = null;
+  }
+  return execution.errors.length > 0
+    ? {
+        data,
+        errors: execution.errors.map((e) => ({ message: e.message, ...(e.path ? { path: e.path } : {}) })),
+      }
+    : { data };
+}
@@ -0,0 +1,28 @@
+export { GraphQLError, parse, parseType, printType, namedType } from "./language.js";
+export type {
+  DocumentNode,
+  OperationNode,
+  FragmentNode,
+  SelectionNode,
+  FieldNode,
+  FragmentSpreadNode,
+  InlineFragmentNode,
+  VariableDefinitionNode,
+  DirectiveNode,
+  ValueNode,
+  TypeNode,
+} from "./language.js";
+
+export { execute, printSchema, MAX_DEPTH, MAX_QUERY_LENGTH } from "./execute.js";
+export type {
+  Resolver,
+  ArgumentDefinition,
+  FieldDefinition,
+  ObjectTypeDefinition,
+  Schema,
+  ExecutionRequest,
+  ExecutionResult,
+} from "./execute.js";
+
+export { batchLoader } from "./batch.js";
+export type { Loader } from "./batch.js";
@@ -0,0 +1,453 @@
+// ---------------------------------------------------------------------------
+// GraphQL query language — lexer and parser for executable documents.
+// Covers what read-only inventory queries need: operations with variables,
+// aliases, arguments of every literal kind, fragments, and @skip/@include.
+// Type-system definitions (SDL) are not parsed; the schema is declared in code.
+// ---------------------------------------------------------------------------
+
+export class GraphQLError extends Error {
+  readonly path: Array<string | number> | undefined;
+
+  constructor(message: string, path?: Array<string | number>) {
+    super(message);
+    this.path = path;
+  }
+}
+
+export type ValueNode =
+  | { kind: "Variable"; name: string }
+  | { kind: "Int"; value: number }
+  | { kind: "Float"; value: number }
+  | { kind: "String"; value: string }
+  | { kind: "Boolean"; value: boolean }
+  | { kind: "Null" }
+  | { kind: "Enum"; value: string }
+  | { kind: "List"; values: ValueNode[] }
+  | { kind: "Object"; fields: Record<string, ValueNode> };
+
+export type TypeNode =
+  | { kind: "Named"; name: string }
+  | { kind: "List"; type: TypeNode }
+  | { kind: "NonNull"; type: TypeNode };
+
+export interface DirectiveNode {
+  name: string;
+  args: Record<string, ValueNode>;
+}
+
+export interface FieldNode {
+  kind: "Field";
+  alias: string | undefined;
+  name: string;
+  args: Record<string, ValueNode>;
+  directives: DirectiveNode[];
+  selections: SelectionNode[] | undefined;
+}
+
+export interface FragmentSpreadNode {
+  kind: "FragmentSpread";
+  name: string;
+  directives: DirectiveNode[];
+}
+
+export interface InlineFragmentNode {
+  kind: "InlineFragment";
+  typeCondition: string | undefined;
+  directives: DirectiveNode[];
+  selections: SelectionNode[];
+}
+
+export type SelectionNode = FieldNode | FragmentSpreadNode | InlineFragmentNode;
+
+export interface VariableDefinitionNode {
+  name: string;
+  type: TypeNode;
+  defaultValue: ValueNode | undefined;
+}
+
+export interface OperationNode {
+  operation: "query" | "mutation" | "subscription";
+  name: string | undefined;
+  variables: VariableDefinitionNode[];
+  selections: SelectionNode[];
+}
+
+export interface FragmentNode {
+  name: string;
+  typeCondition: string;
+  selections: SelectionNode[];
+}
+
+export interface DocumentNode {
+  operations: OperationNode[];
+  fragments: Map<string, FragmentNode>;
+}
+
+// ---------------------------------------------------------------------------
+// Lexer
+// ---------------------------------------------------------------------------
+
+type TokenKind = "punct" | "name" | "int" | "float" | "string" | "eof";
+
+interface Token {
+  kind: TokenKind;
+  value: string;
+  pos: number;
+}
+
+const PUNCT = new Set(["!", "$", "&", "(", ")", ":", "=", "@", "[", "]", "{", "|", "}"]);
+const NAME_START = /[_A-Za-z]/;
+const NAME_CHAR = /[_0-9A-Za-z]/;
+const NUMBER_RE = /-?(?:0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?/y;
+const ESCAPES: Record<string, string> = { '"': '"', "\\": "\\", "/": "/", b: "\b", f: "\f", n: "\n", r: "\r", t: "\t" };
+
+/** Common indentation removal and blank-line trimming for """block strings""" */
+function blockStringValue(raw: string): string {
+  const lines = raw.split(/\r\n|[\n\r]/);
+  let indent = Infinity;
+  for (const line of lines.slice(1)) {
+    const leading = line.length - line.trimStart().length;
+    if (leading < line.length) indent = Math.min(indent, leading);
+  }
+  const out = lines.map((line, i) => (i === 0 || indent === Infinity ? line : line.slice(indent)));
+  while (out.length > 0 && out[0]!.trim() === "") out.shift();
+  while (out.length > 0 && out[out.length - 1]!.trim() === "") out.pop();
+  return out.join("\n");
+}
+
+function tokenize(source: string): Token[] {
+  const tokens: Token[] = [];
+  let i = 0;
+  while (i < source.length) {
+    const c = source[i]!;
+    // Whitespace, commas, and the BOM are insignificant
+    if (c === " " || c === "\t" || c === "\n" || c === "\r" || c === "," || c === "\uFEFF") {
+      i++;
+    } else if (c === "#") {
+      while (i < source.length && source[i] !== "\n" && source[i] !== "\r") i++;
+    } else if (c === ".") {
+      if (source.startsWith("...", i)) {
+        tokens.push({ kind: "punct", value: "...", pos: i });
+        i += 3;
+      } else {
+        throw new GraphQLError(`Syntax error: unexpected "." at position ${i}`);
+      }
+    } else if (PUNCT.has(c)) {
+      tokens.push({ kind: "punct", value: c, pos: i });
+      i++;
+    } else if (NAME_START.test(c)) {
+      const start = i;
+      while (i < source.length && NAME_CHAR.test(source[i]!)) i++;
+      tokens.push({ kind: "name", value: source.slice(start, i), pos: start });
+    } else if (c === "-" || (c >= "0" && c <= "9")) {
+      NUMBER_RE.lastIndex = i;
+      const match = NUMBER_RE.exec(source);
+      if (!match) throw new GraphQLError(`Syntax error: invalid number at position ${i}`);
+      const next = source[i + match[0].length];
+      // "01" and "1a" are one invalid token, not two valid ones
+      if (next !== undefined && (NAME_CHAR.test(next) || next === ".")) {
+        throw new GraphQLError(`Syntax error: invalid number at position ${i}`);
+      }
+      tokens.push({ kind: match[1] || match[2] ? "float" : "int", value: match[0], pos: i });
+      i += match[0].length;
+    } else if (source.startsWith('"""', i)) {
+      const start = i;
+      i += 3;
+      let raw = "";
+      for (;;) {
+        if (i >= source.length) throw new GraphQLError(`Syntax error: unterminated string at position ${start}`);
+        if (source.startsWith('\\"""', i)) {
+          raw += '"""';
+          i += 4;
+        } else if (source.startsWith('"""', i)) {
+          i += 3;
+          break;
+        } else {
+          raw += source[i++];
+        }
+      }
+      tokens.push({ kind: "string", value: blockStringValue(raw), pos: start });
+    } else if (c === '"') {
+      const start = i++;
+      let value = "";
+      for (;;) {
+        const ch = source[i];
+        if (ch === undefined || ch === "\n" || ch === "\r") {
+          throw new GraphQLError(`Syntax error: unterminated string at position ${start}`);
+        }
+        i++;
+        if (ch === '"') break;
+        if (ch !== "\\") {
+          value += ch;
+          continue;
+        }
+        const esc = source[i++];
+        if (esc === "u") {
+          const hex = source.slice(i, i + 4);
+          if (!/^[0-9a-fA-F]{4}$/.test(hex)) throw new GraphQLError(`Syntax error: invalid escape at position ${i - 2}`);
+          value += String.fromCharCode(parseInt(hex, 16));
+          i += 4;
+        } else if (esc !== undefined && ESCAPES[esc] !== undefined) {
+          value += ESCAPES[esc];
+        } else {
+          throw new GraphQLError(`Syntax error: invalid escape at position ${i - 2}`);
+        }
+      }
+      tokens.push({ kind: "string", value, pos: start });
+    } else {
+      throw new GraphQLError(`Syntax error: unexpected character "${c}" at position ${i}`);
+    }
+  }
+  tokens.push({ kind: "eof", value: "", pos: source.length });
+  return tokens;
+}
+
+// ---------------------------------------------------------------------------
+// Parser
+// ---------------------------------------------------------------------------
+
+class Parser {
+  private readonly tokens: Token[];
+  private index = 0;
+
+  constructor(source: string) {
+    this.tokens = tokenize(source);
+  }
+
+  private peek(): Token {
+    return this.tokens[this.index]!;
+  }
+
+  private next(): Token {
+    return this.tokens[this.index++]!;
+  }
+
+  private is(value: string): boolean {
+    const t = this.peek();
+    return t.kind === "punct" && t.value === value;
+  }
+
+  private isName(value?: string): boolean {
+    const t = this.peek();
+    return t.kind === "name" && (value === undefined || t.value === value);
+  }
+
+  private fail(expected: string): never {
+    const t = this.peek();
+    const found = t.kind === "eof" ? "end of document" : `"${t.value}"`;
+    throw new GraphQLError(`Syntax error: expected ${expected}, found ${found} at position ${t.pos}`);
+  }
+
+  private expect(value: string): void {
+    if (!this.is(value)) this.fail(`"${value}"`);
+    this.index++;
+  }
+
+  private name(): string {
+    if (!this.isName()) this.fail("a name");
+    return this.next().value;
+  }
+
+  document(): DocumentNode {
+    const doc: DocumentNode = { operations: [], fragments: new Map() };
+    do {
+      if (this.isName("fragment")) {
+        const fragment = this.fragment();
+        if (doc.fragments.has(fragment.name)) {
+          throw new GraphQLError(`There can be only one fragment named "${fragment.name}"`);
+        }
+        doc.fragments.set(fragment.name, fragment);
+      } else {
+        doc.operations.push(this.operation());
+      }
+    } while (this.peek().kind !== "eof");
+    return doc;
+  }
+
+  private operation(): OperationNode {
+    if (this.is("{")) return { operation: "query", name: undefined, variables: [], selections: this.selectionSet() };
+    if (!this.isName("query") && !this.isName("mutation") && !this.isName("subscription")) {
+      this.fail("an operation or fragment");
+    }
+    const operation = this.next().value as OperationNode["operation"];
+    const name = this.isName() ? this.name() : undefined;
+    const variables: VariableDefinitionNode[] = [];
+    if (this.is("(")) {
+      this.next();
+      while (!this.is(")")) {
+        this.expect("$");
+        const varName = this.name();
+        this.expect(":");
+        const type = this.type();
+        let defaultValue: ValueNode | undefined;
+        if (this.is("=")) {
+          this.next();
+          defaultValue = this.value(true);
+        }
+        this.directives();
+        variables.push({ name: varName, type, defaultValue });
+      }
+      this.next();
+    }
+    this.directives();
+    return { operation, name, variables, selections: this.selectionSet() };
+  }
+
+  private fragment(): FragmentNode {
+    this.next();
+    const name = this.name();
+    if (name === "on") this.fail("a fragment name");
+    if (!this.isName("on")) this.fail('"on"');
+    this.next();
+    const typeCondition = this.name();
+    this.directives();
+    return { name, typeCondition, selections: this.selectionSet() };
+  }
+
+  private selectionSet(): SelectionNode[] {
+    this.expect("{");
+    const selections: SelectionNode[] = [];
+    while (!this.is("}")) selections.push(this.selection());
+    this.next();
+    if (selections.length === 0) this.fail("a selection");
+    return selections;
+  }
+
+  private selection(): SelectionNode {
+    if (this.is("...")) {
+      this.next();
+      if (this.isName() && !this.isName("on")) {
+        return { kind: "FragmentSpread", name: this.name(), directives: this.directives() };
+      }
+      let typeCondition: string | undefined;
+      if (this.isName("on")) {
+        this.next();
+        typeCondition = this.name();
+      }
+      return { kind: "InlineFragment", typeCondition, directives: this.directives(), selections: this.selectionSet() };
+    }
+    let alias: string | undefined;
+    let name = this.name();
+    if (this.is(":")) {
+      this.next();
+      alias = name;
+      name = this.name();
+    }
+    const args = this.arguments(false);
+    const directives = this.directives();
+    const selections = this.is("{") ? this.selectionSet() : undefined;
+    return { kind: "Field", alias, name, args, directives, selections };
+  }
+
+  private arguments(constant: boolean): Record<string, ValueNode> {
+    const args: Record<string, ValueNode> = {};
+    if (!this.is("(")) return args;
+    this.next();
+    while (!this.is(")")) {
+      const name = this.name();
+      if (name in args) throw new GraphQLError(`There can be only one argument named "${name}"`);
+      this.expect(":");
+      args[name] = this.value(constant);
+    }
+    this.next();
+    return args;
+  }
+
+  private directives(): DirectiveNode[] {
+    const directives: DirectiveNode[] = [];
+    while (this.is("@")) {
+      this.next();
+      directives.push({ name: this.name(), args: this.arguments(false) });
+    }
+    return directives;
+  }
+
+  private value(constant: boolean): ValueNode {
+    const t = this.peek();
+    if (t.kind === "punct") {
+      if (t.value === "$" && !constant) {
+        this.next();
+        return { kind: "Variable", name: this.name() };
+      }
+      if (t.value === "[") {
+        this.next();
+        const values: ValueNode[] = [];
+        while (!this.is("]")) values.push(this.value(constant));
+        this.next();
+        return { kind: "List", values };
+      }
+      if (t.value === "{") {
+        this.next();
+        const fields: Record<string, ValueNode> = {};
+        while (!this.is("}")) {
+          const name = this.name();
+          this.expect(":");
+          fields[name] = this.value(constant);
+        }
+        this.next();
+        return { kind: "Object", fields };
+      }
+      this.fail("a value");
+    }
+    this.next();
+    switch (t.kind) {
+      case "int":
+        return { kind: "Int", value: Number(t.value) };
+      case "float":
+        return { kind: "Float", value: Number(t.value) };
+      case "string":
+        return { kind: "String", value: t.value };
+      case "name":
+        if (t.value === "true" || t.value === "false") return { kind: "Boolean", value: t.value === "true" };
+        if (t.value === "null") return { kind: "Null" };
+        return { kind: "Enum", value: t.value };
+      default:
+        this.index--;
+        return this.fail("a value");
+    }
+  }
+
+  type(): TypeNode {
+    let type: TypeNode;
+    if (this.is("[")) {
+      this.next();
+      const inner = this.type();
+      this.expect("]");
+      type = { kind: "List", type: inner };
+    } else {
+      type = { kind: "Named", name: this.name() };
+    }
+    if (this.is("!")) {
+      this.next();
+      return { kind: "NonNull", type };
+    }
+    return type;
+  }
+
+  end(): void {
+    if (this.peek().kind !== "eof") this.fail("end of type");
+  }
+}
+
+export function parse(source: string): DocumentNode {
+  return new Parser(source).document();
+}
+
+/** Parse a type reference such as "[Repository!]!" */
+export function parseType(source: string): TypeNode {
+  const parser = new Parser(source);
+  const type = parser.type();
+  parser.end();
+  return type;
+}
+
+export function printType(type: TypeNode): string {
+  if (type.kind === "Named") return type.name;
+  if (type.kind === "List") return `[${printType(type.type)}]`;
+  return `${printType(type.type)}!`;
+}
+
+/** The named type at the bottom of list and non-null wrappers */
+export function namedType(type: TypeNode): string {
+  return type.kind === "Named" ? type.name : namedType(type.type);
+}
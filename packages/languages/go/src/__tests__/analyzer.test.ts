@@ -40,11 +40,20 @@ describe("GoPlugin", () => {
     it("detects AWS S3 SDK via NewFromConfig with canonical service name", () => {
       const sdks = entries.filter((e) => e.kind === "sdk");
       const awsSDK = sdks.find(
-        (e) => e.kind === "sdk" && e.provider === "aws" && e.services_used?.includes("s3"),
+        (e) => e.kind === "sdk" && e.provider === "aws-s3" && e.services_used?.includes("s3"),
       );
       expect(awsSDK).toBeDefined();
     });
 
+    it("reports AWS per service, with the region from config.WithRegion", () => {
+      const aws = entries.filter((e) => e.kind === "sdk" && e.provider.startsWith("aws"));
+      expect(aws).toHaveLength(1);
+      const s3 = aws[0]!;
+      expect(s3.kind === "sdk" && s3.sdk_package).toBe("github.com/aws/aws-sdk-go-v2/service/s3");
+      expect(s3.kind === "sdk" && s3.regions).toEqual(["us-west-2"]);
+      expect(s3.locations.map((l) => l.line)).toEqual([1, 36]);
+    });
+
     it("detects Stripe SDK via charge.New", () => {
       const stripeSDK = entries.find(
         (e) => e.kind === "sdk" && e.provider === "stripe",
@@ -86,7 +95,7 @@ describe("GoPlugin", () => {
     it("detects SDK from imports without duplicates", () => {
       const sdks = entries.filter((e) => e.kind === "sdk");
       const providers = new Set(sdks.map((e) => e.kind === "sdk" ? e.provider : ""));
-      expect(providers.has("aws")).toBe(true);
+      expect(providers.has("aws-s3")).toBe(true);
       expect(providers.has("stripe")).toBe(true);
       expect(providers.has("openai")).toBe(true);
     });
@@ -131,4 +140,1143 @@ describe("GoPlugin", () => {
       }
     });
   });
+
+  describe("analyze — cloud/azure.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "cloud/azure.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+    });
+
+    it("reports each Azure service as its own provider", () => {
+      const providers = entries.flatMap((e) => (e.kind === "sdk" ? [e.provider] : [])).sort();
+      expect(providers).toEqual([
+        "azure-blob-storage",
+        "azure-cosmos-db",
+        "azure-entra-id",
+        "azure-openai",
+        "azure-service-bus",
+      ]);
+    });
+
+    it("adds constructor call sites to the import entry", () => {
+      const blob = entries.find((e) => e.kind === "sdk" && e.provider === "azure-blob-storage");
+      expect(blob?.locations.map((l) => l.line)).toEqual([1, 21]);
+    });
+
+    it("detects service endpoints passed to SDK clients", () => {
+      const urls = entries.flatMap((e) => (e.kind === "api" ? [e.url] : []));
+      expect(urls).toEqual([
+        "https://acmeuploads.blob.core.windows.net/",
+        "https://acme-orders.documents.azure.com:443/",
+      ]);
+    });
+  });
+
+  describe("analyze — cloud/gcp.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "cloud/gcp.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+    });
+
+    it("reports each GCP service as its own provider, with a fallback for the rest", () => {
+      const providers = entries.flatMap((e) => (e.kind === "sdk" ? [e.provider] : [])).sort();
+      expect(providers).toEqual([
+        "gcp",
+        "gcp-bigquery",
+        "gcp-cloud-storage",
+        "gcp-firestore",
+        "gcp-pubsub",
+        "gcp-spanner",
+        "gcp-vertex-ai",
+      ]);
+    });
+
+    it("adds constructor call sites to the import entry", () => {
+      const vertex = entries.find((e) => e.kind === "sdk" && e.provider === "gcp-vertex-ai");
+      expect(vertex?.locations.map((l) => l.line)).toEqual([1, 41]);
+    });
+
+    it("detects googleapis.com endpoints", () => {
+      const urls = entries.flatMap((e) => (e.kind === "api" ? [e.url] : []));
+      expect(urls).toEqual(["https://us-central1-aiplatform.googleapis.com"]);
+    });
+
+    it("ignores GCP constructor names without the service import", async () => {
+      const source = 'package main\n\nfunc main() {\n\tstorage.NewClient(ctx)\n}\n';
+      const result = await plugin.analyze({
+        filePath: resolve(fixturesRoot, "other.go"),
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+      expect(result).toEqual([]);
+    });
+  });
+
+  describe("analyze — notify/sms.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "notify/sms.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+    });
+
+    it("detects the Twilio SDK via twilio.NewRestClient", () => {
+      const twilio = entries.find((e) => e.kind === "sdk" && e.provider === "twilio");
+      expect(twilio?.locations.map((l) => l.line)).toEqual([1, 13]);
+    });
+
+    it("detects raw REST calls to Twilio and Vonage without an SDK", () => {
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [`${e.method} ${e.url}`] : []));
+      expect(apis).toEqual([
+        "POST https://rest.nexmo.com/sms/json",
+        "GET https://api.twilio.com/2010-04-01/Accounts/AC123/Calls.json",
+      ]);
+      expect(entries.some((e) => e.kind === "sdk" && e.provider === "vonage")).toBe(false);
+    });
+
+    it("detects the Vonage SDK via vonage.NewSMSClient", async () => {
+      const source = [
+        "package notify",
+        "",
+        'import "github.com/vonage/vonage-go-sdk"',
+        "",
+        "func Send() {",
+        '\tauth := vonage.CreateAuthFromKeySecret("key", "secret")',
+        "\tsms := vonage.NewSMSClient(auth)",
+        "\t_ = sms",
+        "}",
+      ].join("\n");
+      const result = await plugin.analyze({
+        filePath: resolve(fixturesRoot, "notify/vonage.go"),
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+      const vonage = result.filter((e) => e.kind === "sdk" && e.provider === "vonage");
+      expect(vonage).toHaveLength(1);
+      expect(vonage[0]!.locations.map((l) => l.line)).toEqual([1, 7]);
+    });
+  });
+
+  describe("analyze — notify/email.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "notify/email.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+    });
+
+    it("detects email delivery SDKs", () => {
+      const providers = entries.flatMap((e) => (e.kind === "sdk" ? [e.provider] : [])).sort();
+      expect(providers).toEqual(["mailgun", "postmark", "sendgrid"]);
+      const mailgun = entries.find((e) => e.kind === "sdk" && e.provider === "mailgun");
+      expect(mailgun?.locations.map((l) => l.line)).toEqual([1, 24]);
+    });
+
+    it("reports SMTP relays as smtp:// endpoints", () => {
+      const urls = entries.flatMap((e) => (e.kind === "api" ? [e.url] : []));
+      expect(urls).toEqual([
+        "smtp://smtp.sendgrid.net:587",
+        "smtp://email-smtp.eu-west-1.amazonaws.com:587",
+      ]);
+    });
+  });
+
+  describe("analyze — telemetry/apm.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "telemetry/apm.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+    });
+
+    it("detects APM and metrics SDKs", () => {
+      const providers = entries.flatMap((e) => (e.kind === "sdk" ? [e.provider] : [])).sort();
+      expect(providers).toEqual(["datadog", "honeycomb", "newrelic"]);
+    });
+
+    it("adds tracer, metrics, and agent setup call sites", () => {
+      const lines = (provider: string) =>
+        entries.find((e) => e.kind === "sdk" && e.provider === provider)?.locations.map((l) => l.line);
+      expect(lines("datadog")).toEqual([1, 16, 18]);
+      expect(lines("newrelic")).toEqual([1, 25, 27]);
+      expect(lines("honeycomb")).toEqual([1, 35]);
+    });
+
+    it("detects intake endpoints with and without a scheme", () => {
+      const urls = entries.flatMap((e) => (e.kind === "api" ? [e.url] : []));
+      expect(urls).toEqual(["https://api.eu1.honeycomb.io", "https://api.honeycomb.io:443"]);
+    });
+
+    it("ignores tracer.Start without a Datadog import", async () => {
+      const source = 'package main\n\nfunc main() {\n\ttracer.Start()\n}\n';
+      const result = await plugin.analyze({
+        filePath: resolve(fixturesRoot, "other.go"),
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+      expect(result).toEqual([]);
+    });
+  });
+
+  describe("analyze — errors/report.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "errors/report.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+    });
+
+    it("detects error-tracking SDK initialization", () => {
+      const lines = (provider: string) =>
+        entries.find((e) => e.kind === "sdk" && e.provider === provider)?.locations.map((l) => l.line);
+      expect(lines("sentry")).toEqual([1, 15]);
+      expect(lines("bugsnag")).toEqual([1, 22]);
+      expect(lines("rollbar")).toEqual([1, 28]);
+    });
+
+    it("reports the DSN endpoint with its key redacted", () => {
+      const urls = entries.flatMap((e) => (e.kind === "api" ? [e.url] : []));
+      expect(urls).toEqual(["https://<redacted>@o42.ingest.sentry.io/4504"]);
+    });
+
+    it("records request payloads and user data sent to Sentry", () => {
+      const sentry = entries.find((e) => e.kind === "sdk" && e.provider === "sentry");
+      expect(sentry?.kind === "sdk" && sentry.data_exported).toEqual(["request_payloads", "user_data"]);
+      const rollbar = entries.find((e) => e.kind === "sdk" && e.provider === "rollbar");
+      expect(rollbar?.kind === "sdk" && rollbar.data_exported).toBeUndefined();
+    });
+  });
+
+  describe("analyze — notify/chat.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "notify/chat.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+    });
+
+    it("detects Slack, Teams, and Graph clients", () => {
+      const providers = entries.flatMap((e) => (e.kind === "sdk" ? [e.provider] : [])).sort();
+      expect(providers).toEqual(["azure-entra-id", "microsoft-graph", "microsoft-teams", "slack"]);
+      const slack = entries.find((e) => e.kind === "sdk" && e.provider === "slack");
+      expect(slack?.locations.map((l) => l.line)).toEqual([1, 18, 22]);
+    });
+
+    it("reports incoming webhooks with the token path redacted", () => {
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [`${e.method} ${e.url}`] : []));
+      expect(apis).toEqual([
+        "POST https://hooks.slack.com/services/<redacted>",
+        "POST https://acme.webhook.office.com/webhookb2/<redacted>",
+      ]);
+    });
+
+    it("records the Graph workloads called", () => {
+      const graph = entries.find((e) => e.kind === "sdk" && e.provider === "microsoft-graph");
+      expect(graph?.kind === "sdk" && graph.services_used).toEqual(["teams"]);
+    });
+  });
+
+  describe("analyze — scm/sync.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "scm/sync.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+    });
+
+    it("detects GitHub, GitLab, and Bitbucket clients", () => {
+      const lines = (provider: string) =>
+        entries.find((e) => e.kind === "sdk" && e.provider === provider)?.locations.map((l) => l.line);
+      expect(lines("github")).toEqual([1, 15]);
+      expect(lines("gitlab")).toEqual([1, 20]);
+      expect(lines("bitbucket")).toEqual([1, 26]);
+    });
+
+    it("detects REST and GraphQL calls made without a client", () => {
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [`${e.method} ${e.url}`] : []));
+      expect(apis).toEqual([
+        "POST https://api.github.com/graphql",
+        "GET https://gitlab.com/api/v4/projects/42/merge_requests",
+      ]);
+    });
+
+    it("ignores github.NewClient without the go-github import", async () => {
+      const source = 'package main\n\nfunc main() {\n\tgithub.NewClient(nil)\n}\n';
+      const result = await plugin.analyze({
+        filePath: resolve(fixturesRoot, "other.go"),
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+      expect(result).toEqual([]);
+    });
+  });
+
+  describe("analyze — auth/idp.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "auth/idp.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+    });
+
+    it("detects Auth0 and Okta SDKs", () => {
+      const lines = (provider: string) =>
+        entries.find((e) => e.kind === "sdk" && e.provider === provider)?.locations.map((l) => l.line);
+      expect(lines("auth0")).toEqual([1, 22]);
+      expect(lines("okta")).toEqual([1, 29]);
+    });
+
+    it("reports issuers and tenants in the auth category", () => {
+      const issuers = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.category]] : []));
+      expect(issuers).toEqual([
+        ["https://accounts.google.com", "auth"],
+        ["https://acme.us.auth0.com", "auth"],
+        ["https://acme.okta.com", "auth"],
+        ["https://acme.okta.com/oauth2/default", "auth"],
+      ]);
+    });
+  });
+
+  describe("analyze — warehouse/analytics.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "warehouse/analytics.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+    });
+
+    it("detects Snowflake, BigQuery, and Redshift Data API clients", () => {
+      const sdks = entries.flatMap((e) => (e.kind === "sdk" ? [[e.provider, e.sdk_package]] : []));
+      expect(sdks).toEqual(
+        expect.arrayContaining([
+          ["snowflake", "gosnowflake"],
+          ["gcp-bigquery", "cloud.google.com/go/bigquery"],
+          ["aws-redshift", "github.com/aws/aws-sdk-go-v2/service/redshiftdata"],
+        ]),
+      );
+    });
+
+    it("types warehouse connections apart from plain Postgres", () => {
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.type, e.connection_ref, e.locations[0]!.line]] : [],
+      );
+      expect(infra).toEqual([
+        ["snowflake", "SNOWFLAKE_DSN", 16],
+        ["redshift", "postgres://<redacted>@analytics.abc123xyz.us-east-1.redshift.amazonaws.com:5439/dev", 21],
+        ["postgresql", "postgres://<redacted>@orders-db.internal:5432/orders", 26],
+      ]);
+    });
+  });
+
+  describe("analyze — queue/*.go", () => {
+    const analyzeFixture = async (name: string) => {
+      const filePath = resolve(fixturesRoot, name);
+      const source = await readFile(filePath, "utf-8");
+      return plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+    };
+    const kafka = (entries: DependencyEntry[]) =>
+      entries.flatMap((e) =>
+        e.kind === "infrastructure" && e.type === "kafka"
+          ? [[e.resolved_host ?? e.connection_ref, e.locations[0]!.line]]
+          : [],
+      );
+
+    it("finds sarama and confluent-kafka-go brokers in nearby config", async () => {
+      expect(kafka(await analyzeFixture("queue/events.go"))).toEqual([
+        ["b-1.orders.abc123.c2.kafka.us-east-1.amazonaws.com", 13],
+        ["kafka-audit-acme.aivencloud.com", 18],
+      ]);
+    });
+
+    it("finds kafka-go brokers from kafka.TCP and os.Getenv", async () => {
+      const entries = await analyzeFixture("queue/stream.go");
+      expect(kafka(entries)).toEqual([
+        ["pkc-abc12.us-east-1.aws.confluent.cloud", 11],
+        ["KAFKA_BROKERS", 18],
+      ]);
+      const reader = entries.find((e) => e.kind === "infrastructure" && e.connection_ref === "KAFKA_BROKERS");
+      expect(reader?.confidence).toBe("medium");
+    });
+
+    it("detects RabbitMQ and NATS connections with their broker URLs", async () => {
+      const entries = await analyzeFixture("queue/broker.go");
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.type, e.connection_ref, e.locations[0]!.line]] : [],
+      );
+      expect(infra).toEqual([
+        ["rabbitmq", "amqps://<redacted>@kangaroo.rmq.cloudamqp.com/orders", 12],
+        ["rabbitmq", "BILLING_AMQP_URL", 17],
+        ["nats", "tls://connect.ngs.global", 22],
+        ["nats", "nats://nats.messaging.svc.cluster.local:4222", 27],
+      ]);
+    });
+  });
+
+  describe("analyze — store/mongo.go", () => {
+    it("reports each mongo-go-driver connection once, where its URI is applied", async () => {
+      const filePath = resolve(fixturesRoot, "store/mongo.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.type, e.connection_ref, e.locations[0]!.line]] : [],
+      );
+      expect(infra).toEqual([
+        ["mongodb", "mongodb+srv://<redacted>@cluster0.abcde.mongodb.net/?retryWrites=true", 13],
+        ["mongodb", "mongodb://<redacted>@mongo-0.mongo.internal:27017/sessions", 19],
+        ["mongodb", "AUDIT_MONGODB_URI", 24],
+      ]);
+    });
+  });
+
+  describe("analyze — store/sql.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "store/sql.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+    });
+
+    it("reports MySQL, Postgres, Cassandra, and ClickHouse connections with redacted DSNs", () => {
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.type, e.connection_ref, e.locations[0]!.line]] : [],
+      );
+      expect(infra).toEqual([
+        ["mysql", "<redacted>@tcp(aws.connect.psdb.cloud:3306)/accounts?tls=true", 16],
+        ["mysql", "orders.cluster-abc123.us-east-1.rds.amazonaws.com:3306", 28],
+        [
+          "postgresql",
+          "host=billing.abc123.eu-west-1.rds.amazonaws.com port=5432 user=billing password=<redacted> dbname=billing",
+          37,
+        ],
+        ["cassandra", "cassandra-0.events.internal,cassandra-1.events.internal", 42],
+        ["cassandra", "secure-connect-profiles.zip", 49],
+        ["clickhouse", "abc123.us-east-1.aws.clickhouse.cloud:9440", 58],
+        ["clickhouse", "clickhouse://<redacted>@clickhouse.analytics.internal:9000/rollups", 66],
+      ]);
+    });
+
+    it("resolves hosts from non-URL DSNs and attributes Astra clusters", () => {
+      const hosts = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.resolved_host ?? null, e.provider ?? null]] : [],
+      );
+      expect(hosts).toEqual([
+        ["aws.connect.psdb.cloud", null],
+        ["orders.cluster-abc123.us-east-1.rds.amazonaws.com", null],
+        ["billing.abc123.eu-west-1.rds.amazonaws.com", null],
+        ["cassandra-0.events.internal", null],
+        [null, "datastax-astra"],
+        ["abc123.us-east-1.aws.clickhouse.cloud", null],
+        [null, null],
+      ]);
+      expect(entries.find((e) => e.kind === "sdk" && e.provider === "datastax-astra")).toBeDefined();
+    });
+  });
+
+  describe("analyze — store/cache.go", () => {
+    it("finds go-redis addresses in the options literal or just above", async () => {
+      const filePath = resolve(fixturesRoot, "store/cache.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.type, e.resolved_host ?? e.connection_ref, e.locations[0]!.line]] : [],
+      );
+      expect(infra).toEqual([
+        ["redis", "eu1-pleasant-moth-31337.upstash.io", 11],
+        ["redis", "clustercfg.sessions.abc123.use1.cache.amazonaws.com", 19],
+        ["redis", "redis-12345.c1.us-east-1-2.ec2.redns.redis-cloud.com", 27],
+        ["redis", "CACHE_ADDR", 32],
+      ]);
+    });
+  });
+
+  describe("analyze — search/index.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "search/index.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+    });
+
+    it("reports Elasticsearch and OpenSearch clusters, decoding Elastic Cloud IDs", () => {
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.type, e.resolved_host ?? e.connection_ref, e.locations[0]!.line]] : [],
+      );
+      expect(infra).toEqual([
+        ["elasticsearch", "a1b2c3d4e5f6.us-central1.gcp.cloud.es.io", 16],
+        ["elasticsearch", "logs-es.internal", 24],
+        ["opensearch", "search-audit-abc123.us-east-1.es.amazonaws.com", 31],
+      ]);
+    });
+
+    it("detects hosted search SDKs and their cloud endpoints", () => {
+      const sdks = entries.flatMap((e) => (e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : []));
+      expect(sdks).toEqual([
+        ["algolia", [1, 39]],
+        ["meilisearch", [1, 40]],
+        ["typesense", [1, 41]],
+      ]);
+      const urls = entries.flatMap((e) => (e.kind === "api" ? [e.url] : []));
+      expect(urls).toEqual(["https://ms-abc123.lon.meilisearch.io", "https://xyz123-1.a1.typesense.net"]);
+    });
+  });
+
+  describe("analyze — platform/*.go", () => {
+    async function analyzeFixture(name: string): Promise<DependencyEntry[]> {
+      const filePath = resolve(fixturesRoot, "platform", name);
+      const source = await readFile(filePath, "utf-8");
+      return plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+    }
+
+    it("reports Vault clusters from config.Address, WithAddress, or VAULT_ADDR", async () => {
+      const entries = await analyzeFixture("vault.go");
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.type, e.resolved_host ?? e.connection_ref, e.locations[0]!.line]] : [],
+      );
+      expect(infra).toEqual([
+        ["vault", "vault-cluster-public-vault-abc123.def456.z1.hashicorp.cloud", 15],
+        ["vault", "PKI_VAULT_ADDR", 20],
+        ["vault", "VAULT_ADDR", 25],
+      ]);
+      const sdks = entries.flatMap((e) => (e.kind === "sdk" ? [[e.provider, e.sdk_package]] : []));
+      expect(sdks).toEqual([["hashicorp-vault", "vault-client-go"]]);
+    });
+
+    it("tells Consul's api package apart and detects HCP Terraform clients", async () => {
+      const entries = await analyzeFixture("consul.go");
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.type, e.connection_ref, e.resolved_host]] : [],
+      );
+      expect(infra).toEqual([["consul", "consul.service.internal:8500", "consul.service.internal"]]);
+      const sdks = entries.flatMap((e) => (e.kind === "sdk" ? [[e.provider, e.locations.length]] : []));
+      expect(sdks).toEqual([
+        ["hashicorp-consul", 1],
+        ["terraform-cloud", 2],
+      ]);
+    });
+  });
+
+  describe("analyze — workflow/orders.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "workflow/orders.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+    });
+
+    it("reports Temporal frontends from HostPort, falling back to the SDK default", () => {
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure"
+          ? [[e.type, e.connection_ref, e.resolved_host ?? null, e.locations[0]!.line]]
+          : [],
+      );
+      expect(infra).toEqual([
+        ["temporal", "orders-prod.a1b2c.tmprl.cloud:7233", "orders-prod.a1b2c.tmprl.cloud", 15],
+        ["temporal", "TEMPORAL_ADDRESS", null, 23],
+        ["temporal", "localhost:7233", null, 28],
+      ]);
+    });
+
+    it("detects Temporal, Step Functions, and Google Workflows clients", () => {
+      const sdks = entries.flatMap((e) => (e.kind === "sdk" ? [e.provider] : []));
+      expect(sdks).toEqual(expect.arrayContaining(["temporal", "aws-step-functions", "gcp-workflows"]));
+      expect(sdks).not.toContain("gcp");
+    });
+  });
+
+  describe("analyze — flags/flags.go", () => {
+    it("detects feature-flag SDK initialization and self-hosted flag services", async () => {
+      const filePath = resolve(fixturesRoot, "flags/flags.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const sdks = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line), e.base_url ?? null]] : [],
+      );
+      expect(sdks).toEqual([
+        ["flagsmith", [1, 27], null],
+        ["unleash", [1, 19], "https://unleash.platform.internal/api/"],
+        ["launchdarkly", [1, 14], null],
+      ]);
+    });
+  });
+
+  describe("analyze — tracking/track.go", () => {
+    it("detects analytics SDKs, their ingestion endpoints, and identify traits", async () => {
+      const filePath = resolve(fixturesRoot, "tracking/track.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const sdks = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line), e.base_url ?? null, e.data_exported ?? null]] : [],
+      );
+      expect(sdks).toEqual([
+        ["amplitude", [1, 33], null, null],
+        ["mixpanel", [1, 28], null, null],
+        ["segment", [1, 13], "https://events.eu1.segmentapis.com", ["user_traits"]],
+      ]);
+    });
+
+    it("tells RudderStack's analytics.New apart from Segment's by the import", async () => {
+      const source = [
+        "package tracking",
+        'import "github.com/rudderlabs/analytics-go/v4"',
+        "func New() analytics.Client {",
+        '\treturn analytics.NewWithConfig(key, analytics.Config{DataPlaneUrl: "https://acme.dataplane.rudderstack.com"})',
+        "}",
+      ].join("\n");
+      const entries = await plugin.analyze({ filePath: "/app/rudder.go", source, scanRoot: "/app", resolvedEnv: {} });
+      const sdks = entries.flatMap((e) => (e.kind === "sdk" ? [[e.provider, e.base_url ?? null]] : []));
+      expect(sdks).toEqual([["rudderstack", "https://acme.dataplane.rudderstack.com"]]);
+    });
+  });
+
+  describe("analyze — payments/processors.go", () => {
+    it("detects PayPal, Braintree, Adyen, and Square clients with the environment they target", async () => {
+      const filePath = resolve(fixturesRoot, "payments/processors.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const sdks = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line), e.base_url ?? null]] : [],
+      );
+      expect(sdks).toEqual([
+        ["adyen", [1, 32], "https://checkout-test.adyen.com"],
+        ["braintree", [1, 22], "https://api.sandbox.braintreegateway.com"],
+        ["paypal", [1, 17], "https://api-m.paypal.com"],
+        ["square", [1, 40], "https://connect.squareup.com"],
+      ]);
+    });
+  });
+
+  describe("analyze — banking/accounts.go", () => {
+    it("detects the Plaid client and open-banking API calls", async () => {
+      const filePath = resolve(fixturesRoot, "banking/accounts.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const plaid = entries.find((e) => e.kind === "sdk" && e.provider === "plaid");
+      expect(plaid?.kind === "sdk" && [plaid.locations.map((l) => l.line), plaid.base_url]).toEqual([
+        [1, 17],
+        "https://production.plaid.com",
+      ]);
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.method]] : []));
+      expect(apis).toEqual([
+        ["https://api.truelayer.com/data/v1/accounts", "GET"],
+        ["https://production.api.yodlee.com/ysl/transactions", "GET"],
+      ]);
+    });
+  });
+
+  describe("analyze — billing/subscriptions.go", () => {
+    it("detects billing and tax SDKs and direct AvaTax calls", async () => {
+      const filePath = resolve(fixturesRoot, "billing/subscriptions.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const sdks = entries.flatMap((e) => (e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : []));
+      expect(sdks).toEqual([
+        ["chargebee", [1, 16]],
+        ["recurly", [1, 21]],
+        ["taxjar", [1, 26]],
+      ]);
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.method]] : []));
+      expect(apis).toEqual([["https://rest.avatax.com/api/v2/transactions/create", "POST"]]);
+    });
+  });
+
+  describe("analyze — commerce/orders.go", () => {
+    it("detects the Shopify client bound to its shop and commerce API calls", async () => {
+      const filePath = resolve(fixturesRoot, "commerce/orders.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const shopify = entries.find((e) => e.kind === "sdk" && e.provider === "shopify");
+      expect(shopify?.kind === "sdk" && [shopify.locations.map((l) => l.line), shopify.base_url]).toEqual([
+        [1, 15],
+        "https://acme-store.myshopify.com",
+      ]);
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.method]] : []));
+      expect(apis).toEqual([
+        ["https://acme-store.myshopify.com/admin/api/2024-07/graphql.json", "POST"],
+        ["https://wholesale.acme.example/wp-json/wc/v3/orders", "GET"],
+        ["https://api.ebay.com/sell/fulfillment/v1/order", "GET"],
+      ]);
+    });
+  });
+
+  describe("analyze — kyc/verify.go", () => {
+    it("reports identity-verification API calls made without an SDK", async () => {
+      const filePath = resolve(fixturesRoot, "kyc/verify.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.method, e.locations[0]!.line]] : []));
+      expect(apis).toEqual([
+        ["https://api.eu.onfido.com/v3.6/documents", "POST", 12],
+        ["https://withpersona.com/api/v1/inquiries", "POST", 22],
+        ["https://account.amer-1.jumio.ai/api/v1/accounts", "POST", 32],
+      ]);
+    });
+  });
+
+  describe("analyze — shipping/labels.go", () => {
+    it("detects shipping SDKs and carrier API calls", async () => {
+      const filePath = resolve(fixturesRoot, "shipping/labels.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const sdks = entries.flatMap((e) => (e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : []));
+      expect(sdks).toEqual([
+        ["easypost", [1, 15]],
+        ["shippo", [1, 20]],
+      ]);
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [e.url] : []));
+      expect(apis).toEqual([
+        "https://apis.fedex.com/rate/v1/rates/quotes",
+        "https://onlinetools.ups.com/api/shipments/v2409/ship",
+      ]);
+    });
+  });
+
+  describe("analyze — geo/geocode.go", () => {
+    it("detects the Google Maps client and Mapbox geocoding calls", async () => {
+      const filePath = resolve(fixturesRoot, "geo/geocode.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const found = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : e.kind === "api" ? [[e.url, e.locations.map((l) => l.line)]] : [],
+      );
+      expect(found).toEqual([
+        ["google-maps", [1, 13]],
+        ["https://api.mapbox.com/search/geocode/v6/forward", [18]],
+      ]);
+    });
+  });
+
+  describe("analyze — geo/ip.go", () => {
+    it("detects IP lookup clients and APIs but not local GeoIP readers", async () => {
+      const filePath = resolve(fixturesRoot, "geo/ip.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const found = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : e.kind === "api" ? [[e.url, e.locations.map((l) => l.line)]] : [],
+      );
+      expect(found).toEqual([
+        ["ipinfo", [1, 13]],
+        ["https://api.ipstack.com/check", [23]],
+      ]);
+    });
+  });
+
+  describe("analyze — fx/rates.go", () => {
+    it("detects the Open Exchange Rates client and exchange-rate API calls", async () => {
+      const filePath = resolve(fixturesRoot, "fx/rates.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const found = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : e.kind === "api" ? [[e.url, e.locations.map((l) => l.line)]] : [],
+      );
+      expect(found).toEqual([
+        ["openexchangerates", [1, 15]],
+        ["https://data.fixer.io/api/latest", [20]],
+        ["https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml", [30]],
+      ]);
+    });
+  });
+
+  describe("analyze — oncall/page.go", () => {
+    it("detects incident-management clients and event ingestion calls", async () => {
+      const filePath = resolve(fixturesRoot, "oncall/page.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const found = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : e.kind === "api" ? [[e.url, e.locations.map((l) => l.line)]] : [],
+      );
+      expect(found).toEqual([
+        ["pagerduty", [1, 16]],
+        ["opsgenie", [1, 25]],
+        ["https://api.incident.io/v2/incidents", [30]],
+      ]);
+    });
+  });
+
+  describe("analyze — growth/enrich.go", () => {
+    it("detects enrichment clients and lookup calls", async () => {
+      const filePath = resolve(fixturesRoot, "growth/enrich.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const found = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : e.kind === "api" ? [[e.url, e.locations.map((l) => l.line)]] : [],
+      );
+      expect(found).toEqual([
+        ["peopledatalabs", [1, 14]],
+        ["https://api.hunter.io/v2/email-finder", [19]],
+      ]);
+    });
+  });
+
+  describe("analyze — feeds/market.go", () => {
+    it("reports raw TCP dials and IP-literal calls, skipping private addresses", async () => {
+      const filePath = resolve(fixturesRoot, "feeds/market.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.locations[0]!.line]] : []));
+      expect(apis).toEqual([
+        ["tcp://34.120.18.9:9878", 12],
+        ["tcp://drop.clearing-example.net:7001", 17],
+        ["http://52.14.20.7:8080/eod/latest.csv", 27],
+      ]);
+    });
+  });
+
+  describe("analyze — realtime/stream.go", () => {
+    it("reports WebSocket endpoints dialed with gorilla/websocket and nhooyr.io/websocket", async () => {
+      const filePath = resolve(fixturesRoot, "realtime/stream.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.locations[0]!.line]] : []));
+      expect(apis).toEqual([
+        ["wss://stream.binance.com:9443/ws/btcusdt@trade", 13],
+        ["wss://realtime.ably.io/?format=json", 19],
+        ["wss://ws.finnhub.io", 24],
+      ]);
+    });
+  });
+
+  describe("analyze — partners/transfer.go", () => {
+    it("reports SMTP, FTP, and SFTP servers outside the private network", async () => {
+      const filePath = resolve(fixturesRoot, "partners/transfer.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.locations[0]!.line]] : []));
+      expect(apis).toEqual([
+        ["smtp://mail.acmebank-partner.com:587", 16],
+        ["ftp://ftp.retailer-feeds.com:21", 21],
+        ["sftp://sftp.processor-example.com:22", 26],
+      ]);
+    });
+  });
+
+  describe("analyze — auth/login.go", () => {
+    it("reports OAuth token endpoints, naming the provider behind x/oauth2 endpoints", async () => {
+      const filePath = resolve(fixturesRoot, "auth/login.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const tokens = entries.flatMap((e) =>
+        e.kind === "api" ? [[e.url, e.provider, e.category, e.locations[0]!.line]] : [],
+      );
+      expect(tokens).toEqual([
+        ["https://github.com/login/oauth/access_token", "github", "auth", 17],
+        ["https://login.microsoftonline.com/acme.onmicrosoft.com/oauth2/v2.0/token", "azure-entra-id", "auth", 24],
+        ["https://slack.com/api/oauth.access", "slack", "auth", 28],
+        ["https://auth.logistics-partner.com/oauth2/token", undefined, "auth", 34],
+      ]);
+    });
+  });
+
+  describe("analyze — server/webhooks.go", () => {
+    it("reports inbound webhook routes with the sender their verifier names", async () => {
+      const filePath = resolve(fixturesRoot, "server/webhooks.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const webhooks = entries.flatMap((e) =>
+        e.kind === "webhook" ? [[e.direction, e.target_url, e.provider, e.locations.map((l) => l.line)]] : [],
+      );
+      expect(webhooks).toEqual([
+        ["inbound_callback", "/webhooks/stripe", "stripe", [13, 21]],
+        ["inbound_callback", "/webhooks/shopify/orders", "shopify", [14, 30]],
+        ["inbound_callback", "/hooks/github", undefined, [15]],
+      ]);
+    });
+  });
+
+  describe("analyze — graphql/clients.go", () => {
+    it("reports the endpoints of GraphQL clients", async () => {
+      const filePath = resolve(fixturesRoot, "graphql/clients.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.method, e.locations[0]!.line]] : []));
+      expect(apis).toEqual([
+        ["https://api.github.com/graphql", "POST", 15],
+        ["https://acme.myshopify.com/api/2024-07/graphql.json", "POST", 20],
+        ["${CMS_GRAPHQL_URL}", "POST", 28],
+      ]);
+    });
+  });
+
+  describe("analyze — legacy/soap.go", () => {
+    it("reports the service URLs of SOAP clients", async () => {
+      const filePath = resolve(fixturesRoot, "legacy/soap.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.method, e.locations[0]!.line]] : []));
+      expect(apis).toEqual([
+        ["https://ws.freight-carrier.example/BookingService.svc", "POST", 13],
+        ["https://ec.europa.eu/taxation_customs/vies/services/checkVatService", "POST", 18],
+      ]);
+    });
+  });
+
+  describe("analyze — objects/r2.go", () => {
+    it("records the endpoint of an S3 client pointed at another storage provider", async () => {
+      const filePath = resolve(fixturesRoot, "objects/r2.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const sdks = entries.flatMap((e) => (e.kind === "sdk" ? [[e.provider, e.base_url]] : []));
+      expect(sdks).toContainEqual(["aws-s3", "https://8d5c1a2f9e0b4c7d.r2.cloudflarestorage.com"]);
+      expect(sdks).toContainEqual(["minio", undefined]);
+    });
+  });
+
+  describe("analyze — workspace/sync.go", () => {
+    it("reports Google Workspace APIs as one SDK with the services imported", async () => {
+      const filePath = resolve(fixturesRoot, "workspace/sync.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const workspace = entries.filter((e) => e.kind === "sdk" && e.provider === "google-workspace");
+      expect(workspace).toHaveLength(1);
+      expect(workspace[0]!.kind === "sdk" && workspace[0]!.services_used).toEqual(["calendar", "drive"]);
+      expect(workspace[0]!.locations.map((l) => l.line)).toEqual([1, 16, 21]);
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [e.url] : []));
+      expect(apis).toEqual(["https://api.zoom.us/v2/users/me/meetings"]);
+    });
+  });
+
+  describe("analyze — documents/sign.go", () => {
+    it("detects the DocuSign client and e-signature API calls", async () => {
+      const filePath = resolve(fixturesRoot, "documents/sign.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const found = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : e.kind === "api" ? [[e.url, e.locations.map((l) => l.line)]] : [],
+      );
+      expect(found).toEqual([
+        ["docusign", [1]],
+        ["https://api.hellosign.com/v3/signature_request/send", [21]],
+        ["https://api.pandadoc.com/public/v1/documents", [31]],
+      ]);
+    });
+  });
+
+  describe("analyze — media/uploads.go", () => {
+    it("detects image and video processing SDKs and upload calls", async () => {
+      const filePath = resolve(fixturesRoot, "media/uploads.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const found = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : e.kind === "api" ? [[e.url, e.locations.map((l) => l.line)]] : [],
+      );
+      expect(found).toEqual([
+        ["cloudinary", [1, 17]],
+        ["imgix", [1, 22]],
+        ["mux", [1, 27]],
+        ["transloadit", [1, 37]],
+        ["https://api.cloudinary.com/v1_1/acme/image/upload", [42]],
+      ]);
+    });
+  });
+
+  describe("analyze — cdn/purge.go", () => {
+    it("detects CDN management clients and purge calls", async () => {
+      const filePath = resolve(fixturesRoot, "cdn/purge.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const found = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : e.kind === "api" ? [[e.url, e.locations.map((l) => l.line)]] : [],
+      );
+      expect(found).toEqual([
+        ["akamai", [1, 26]],
+        ["cloudflare", [1, 16]],
+        ["fastly", [1, 21]],
+        ["https://api.cloudflare.com/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/purge_cache", [32]],
+        ["https://akab-hqbvzjbjtcbss3xv.purge.akamaiapis.net/ccu/v3/invalidate/url/production", [42]],
+      ]);
+    });
+  });
+
+  describe("analyze — push/notify.go", () => {
+    it("detects push SDKs, the APNs gateway they target, and FCM calls", async () => {
+      const filePath = resolve(fixturesRoot, "push/notify.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const found = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : e.kind === "api" ? [[e.url, e.locations.map((l) => l.line)]] : [],
+      );
+      expect(found).toEqual([
+        ["onesignal", [1, 22]],
+        ["expo", [1, 27]],
+        ["apns", [1, 17]],
+        ["https://fcm.googleapis.com/v1/projects/acme-mobile/messages:send", [32]],
+      ]);
+      const apns = entries.find((e) => e.kind === "sdk" && e.provider === "apns");
+      expect(apns?.kind === "sdk" && apns.base_url).toBe("https://api.sandbox.push.apple.com");
+    });
+  });
+
+  describe("analyze — captcha/verify.go", () => {
+    it("detects reCAPTCHA Enterprise and siteverify calls", async () => {
+      const filePath = resolve(fixturesRoot, "captcha/verify.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const found = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations.map((l) => l.line)]] : e.kind === "api" ? [[e.url, e.locations.map((l) => l.line)]] : [],
+      );
+      expect(found).toEqual([
+        ["recaptcha", [1, 14]],
+        ["https://www.google.com/recaptcha/api/siteverify", [19]],
+        ["https://api.hcaptcha.com/siteverify", [27]],
+        ["https://challenges.cloudflare.com/turnstile/v0/siteverify", [35]],
+      ]);
+    });
+  });
+
+  describe("analyze — baas/backend.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "baas/backend.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+    });
+
+    it("detects the Firebase Admin SDK and the services it uses", () => {
+      const firebase = entries.find((e) => e.kind === "sdk" && e.provider === "firebase");
+      expect(firebase?.locations.map((l) => l.line)).toEqual([1, 15]);
+      expect(firebase?.kind === "sdk" && firebase.services_used).toEqual(["messaging", "database"]);
+    });
+
+    it("detects the Supabase client", () => {
+      const supabase = entries.find((e) => e.kind === "sdk" && e.provider === "supabase");
+      expect(supabase?.locations.map((l) => l.line)).toEqual([1, 47]);
+    });
+
+    it("reports Realtime Database, FCM, and Supabase project URLs", () => {
+      const urls = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.locations[0]!.line]] : []));
+      expect(urls).toEqual([
+        ["https://acme-default-rtdb.firebaseio.com", 16],
+        ["https://fcm.googleapis.com/fcm/send", 39],
+        ["https://xyzcompany.supabase.co", 47],
+      ]);
+    });
+  });
+
+  describe("analyze — llm/providers.go", () => {
+    let entries: DependencyEntry[];
+
+    beforeAll(async () => {
+      const filePath = resolve(fixturesRoot, "llm/providers.go");
+      const source = await readFile(filePath, "utf-8");
+      entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+    });
+
+    it("detects each LLM provider SDK with its client constructor", () => {
+      const lines = (provider: string) =>
+        entries.find((e) => e.kind === "sdk" && e.provider === provider)?.locations.map((l) => l.line);
+      expect(lines("anthropic")).toEqual([1, 17]);
+      expect(lines("cohere")).toEqual([1, 20]);
+      expect(lines("mistral")).toEqual([1, 23]);
+      expect(lines("gemini")).toEqual([1, 26]);
+    });
+
+    it("attributes genai.NewClient to Gemini, not Vertex AI, from the import", () => {
+      expect(entries.some((e) => e.kind === "sdk" && e.provider === "gcp-vertex-ai")).toBe(false);
+    });
+
+    it("reports LLM endpoints called without an SDK", () => {
+      const urls = entries.flatMap((e) => (e.kind === "api" ? [e.url] : []));
+      expect(urls).toContain("https://api.cohere.com/v2/embed");
+      expect(urls).toContain(
+        "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent",
+      );
+    });
+  });
+
+  describe("analyze — llm/gateway.go", () => {
+    it("records the base URL of an OpenAI client pointed at a compatible server", async () => {
+      const filePath = resolve(fixturesRoot, "llm/gateway.go");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+
+      const openai = entries.find((e) => e.kind === "sdk" && e.provider === "openai");
+      expect(openai?.kind === "sdk" && openai.base_url).toBe("http://localhost:8000/v1");
+    });
+  });
 });
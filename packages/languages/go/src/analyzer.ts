@@ -1,7 +1,8 @@
 import { relative } from "node:path";
 import type { AnalyzerContext, DependencyEntry } from "@thirdwatch/core";
+import { isPrivateIp } from "@thirdwatch/core";
 import type { Confidence } from "@thirdwatch/tdm";
-import { detectImports } from "./imports.js";
+import { defaultAlias, detectImports } from "./imports.js";
 
 // ---------------------------------------------------------------------------
 // SDK import path prefixes → [provider, sdk_package]
@@ -9,16 +10,187 @@ import { detectImports } from "./imports.js";
 
 const SDK_PROVIDERS: Record<string, [string, string]> = {
   "github.com/stripe/stripe-go": ["stripe", "stripe-go"],
+  "github.com/plutov/paypal": ["paypal", "plutov/paypal"],
+  "github.com/braintree-go/braintree-go": ["braintree", "braintree-go"],
+  "github.com/adyen/adyen-go-api-library": ["adyen", "adyen-go-api-library"],
+  "github.com/square/square-go-sdk": ["square", "square-go-sdk"],
+  "github.com/plaid/plaid-go": ["plaid", "plaid-go"],
+  // Subscription billing and sales tax
+  "github.com/chargebee/chargebee-go": ["chargebee", "chargebee-go"],
+  "github.com/recurly/recurly-client-go": ["recurly", "recurly-client-go"],
+  "github.com/taxjar/taxjar-go": ["taxjar", "taxjar-go"],
+  "github.com/bold-commerce/go-shopify": ["shopify", "go-shopify"],
+  "github.com/EasyPost/easypost-go": ["easypost", "easypost-go"],
+  "github.com/coldbrewcloud/go-shippo": ["shippo", "go-shippo"],
+  "googlemaps.github.io/maps": ["google-maps", "google-maps-services-go"],
+  // Object storage outside AWS (S3 clients pointed at these are resolved in core)
+  "github.com/minio/minio-go": ["minio", "minio-go"],
+  "github.com/Backblaze/blazer": ["backblaze-b2", "blazer"],
+  // E-signature (jfcote87/esign is the common DocuSign client; there is no official one)
+  "github.com/jfcote87/esign": ["docusign", "jfcote87/esign"],
+  // Image and video processing
+  "github.com/cloudinary/cloudinary-go": ["cloudinary", "cloudinary-go"],
+  "github.com/imgix/imgix-go": ["imgix", "imgix-go"],
+  "github.com/muxinc/mux-go": ["mux", "mux-go"],
+  "github.com/transloadit/go-sdk": ["transloadit", "transloadit/go-sdk"],
+  // CDN and edge management APIs
+  "github.com/cloudflare/cloudflare-go": ["cloudflare", "cloudflare-go"],
+  "github.com/fastly/go-fastly": ["fastly", "go-fastly"],
+  "github.com/akamai/AkamaiOPEN-edgegrid-golang": ["akamai", "edgegrid-golang"],
+  // Push delivery
+  "github.com/sideshow/apns2": ["apns", "apns2"],
+  "github.com/OneSignal/onesignal-go-api": ["onesignal", "onesignal-go-api"],
+  "github.com/oliveroneill/exponent-server-sdk-golang": ["expo", "exponent-server-sdk-golang"],
+  // IP intelligence (geoip2-golang reads a local database and is not egress)
+  "github.com/ipinfo/go": ["ipinfo", "ipinfo/go"],
+  // Exchange rates (dinero is a client for Open Exchange Rates)
+  "github.com/mattevans/dinero": ["openexchangerates", "dinero"],
+  // Contact and company enrichment
+  "github.com/peopledatalabs/peopledatalabs-go": ["peopledatalabs", "peopledatalabs-go"],
   "github.com/aws/aws-sdk-go-v2": ["aws", "aws-sdk-go-v2"],
   "github.com/aws/aws-sdk-go": ["aws", "aws-sdk-go"],
   "github.com/sashabaranov/go-openai": ["openai", "go-openai"],
+  "github.com/openai/openai-go": ["openai", "openai-go"],
+  // GCP: one provider per service; other cloud.google.com/go clients fall back to "gcp"
+  "cloud.google.com/go/storage": ["gcp-cloud-storage", "cloud.google.com/go/storage"],
+  "cloud.google.com/go/bigquery": ["gcp-bigquery", "cloud.google.com/go/bigquery"],
+  "cloud.google.com/go/pubsub": ["gcp-pubsub", "cloud.google.com/go/pubsub"],
+  "cloud.google.com/go/firestore": ["gcp-firestore", "cloud.google.com/go/firestore"],
+  "cloud.google.com/go/spanner": ["gcp-spanner", "cloud.google.com/go/spanner"],
+  "cloud.google.com/go/vertexai": ["gcp-vertex-ai", "cloud.google.com/go/vertexai"],
+  "cloud.google.com/go/aiplatform": ["gcp-vertex-ai", "cloud.google.com/go/aiplatform"],
+  "cloud.google.com/go/workflows": ["gcp-workflows", "cloud.google.com/go/workflows"],
+  "cloud.google.com/go/recaptchaenterprise": ["recaptcha", "cloud.google.com/go/recaptchaenterprise"],
   "cloud.google.com/go": ["gcp", "google-cloud-go"],
   "github.com/twilio/twilio-go": ["twilio", "twilio-go"],
+  "github.com/vonage/vonage-go-sdk": ["vonage", "vonage-go-sdk"],
+  "github.com/nexmo-community/nexmo-go": ["vonage", "nexmo-go"],
   "github.com/sendgrid/sendgrid-go": ["sendgrid", "sendgrid-go"],
+  "github.com/mailgun/mailgun-go": ["mailgun", "mailgun-go"],
+  "github.com/mrz1836/postmark": ["postmark", "mrz1836/postmark"],
+  "github.com/keighl/postmark": ["postmark", "keighl/postmark"],
+  "github.com/resend/resend-go": ["resend", "resend-go"],
   "github.com/slack-go/slack": ["slack", "slack-go"],
+  "github.com/atc0005/go-teams-notify": ["microsoft-teams", "go-teams-notify"],
+  "github.com/microsoftgraph/msgraph-sdk-go": ["microsoft-graph", "msgraph-sdk-go"],
+  // LLM providers
   "github.com/anthropics/anthropic-sdk-go": ["anthropic", "anthropic-sdk-go"],
+  "github.com/liushuangls/go-anthropic": ["anthropic", "go-anthropic"],
+  "github.com/cohere-ai/cohere-go": ["cohere", "cohere-go"],
+  "github.com/gage-technologies/mistral-go": ["mistral", "mistral-go"],
+  "github.com/google/generative-ai-go": ["gemini", "generative-ai-go"],
+  "google.golang.org/genai": ["gemini", "google.golang.org/genai"],
+  "github.com/replicate/replicate-go": ["replicate", "replicate-go"],
+  // Backend-as-a-service
+  "firebase.google.com/go": ["firebase", "firebase-admin-go"],
+  "github.com/supabase-community/supabase-go": ["supabase", "supabase-go"],
+  "github.com/supabase-community/postgrest-go": ["supabase", "postgrest-go"],
+  "github.com/supabase-community/storage-go": ["supabase", "storage-go"],
+  "github.com/supabase-community/gotrue-go": ["supabase", "gotrue-go"],
+  "github.com/nedpals/supabase-go": ["supabase", "nedpals/supabase-go"],
+  // Identity providers
+  "github.com/auth0/go-auth0": ["auth0", "go-auth0"],
+  "github.com/auth0/go-jwt-middleware": ["auth0", "go-jwt-middleware"],
+  "github.com/okta/okta-sdk-golang": ["okta", "okta-sdk-golang"],
+  "github.com/okta/okta-jwt-verifier-golang": ["okta", "okta-jwt-verifier-golang"],
+  // Source control: automation that holds SCM tokens
+  "github.com/google/go-github": ["github", "go-github"],
+  "github.com/shurcooL/githubv4": ["github", "githubv4"],
+  "gitlab.com/gitlab-org/api/client-go": ["gitlab", "gitlab.com/gitlab-org/api/client-go"],
+  "github.com/xanzy/go-gitlab": ["gitlab", "go-gitlab"],
+  "github.com/ktrysmt/go-bitbucket": ["bitbucket", "go-bitbucket"],
+  // Data warehouses (BigQuery is under GCP, Redshift under AWS service clients)
+  "github.com/snowflakedb/gosnowflake": ["snowflake", "gosnowflake"],
+  "github.com/datastax/gocql-astra": ["datastax-astra", "gocql-astra"],
+  // Hosted search (Elasticsearch and OpenSearch clusters are infrastructure)
+  "github.com/algolia/algoliasearch-client-go": ["algolia", "algoliasearch-client-go"],
+  "github.com/meilisearch/meilisearch-go": ["meilisearch", "meilisearch-go"],
+  "github.com/typesense/typesense-go": ["typesense", "typesense-go"],
+  // HashiCorp: Vault and Consul clusters are also reported as infrastructure
+  "github.com/hashicorp/vault/api": ["hashicorp-vault", "vault/api"],
+  "github.com/hashicorp/vault-client-go": ["hashicorp-vault", "vault-client-go"],
+  "github.com/hashicorp/consul/api": ["hashicorp-consul", "consul/api"],
+  "github.com/hashicorp/go-tfe": ["terraform-cloud", "go-tfe"],
+  // Workflow platforms (Step Functions is under AWS service clients, Google
+  // Workflows under GCP); Temporal clusters are also reported as infrastructure
+  "go.temporal.io/sdk": ["temporal", "go.temporal.io/sdk"],
+  // Feature flags
+  "github.com/launchdarkly/go-server-sdk": ["launchdarkly", "go-server-sdk"],
+  "gopkg.in/launchdarkly/go-server-sdk": ["launchdarkly", "go-server-sdk"],
+  "github.com/splitio/go-client": ["split", "splitio/go-client"],
+  "github.com/Unleash/unleash-client-go": ["unleash", "unleash-client-go"],
+  "github.com/Flagsmith/flagsmith-go-client": ["flagsmith", "flagsmith-go-client"],
+  // Product analytics
+  "github.com/segmentio/analytics-go": ["segment", "segmentio/analytics-go"],
+  "gopkg.in/segmentio/analytics-go": ["segment", "segmentio/analytics-go"],
+  "github.com/rudderlabs/analytics-go": ["rudderstack", "rudderlabs/analytics-go"],
+  "github.com/mixpanel/mixpanel-go": ["mixpanel", "mixpanel-go"],
+  "github.com/dukex/mixpanel": ["mixpanel", "dukex/mixpanel"],
+  "github.com/amplitude/analytics-go": ["amplitude", "amplitude/analytics-go"],
+  "github.com/getsentry/sentry-go": ["sentry", "sentry-go"],
+  "github.com/bugsnag/bugsnag-go": ["bugsnag", "bugsnag-go"],
+  "github.com/rollbar/rollbar-go": ["rollbar", "rollbar-go"],
+  // Observability: APM tracers, metrics clients, and event SDKs
+  "gopkg.in/DataDog/dd-trace-go.v1": ["datadog", "dd-trace-go"],
+  "github.com/DataDog/dd-trace-go": ["datadog", "dd-trace-go"],
+  "github.com/DataDog/datadog-go": ["datadog", "datadog-go"],
+  "github.com/DataDog/datadog-api-client-go": ["datadog", "datadog-api-client-go"],
+  // Incident management: alerting and on-call schedules
+  "github.com/PagerDuty/go-pagerduty": ["pagerduty", "go-pagerduty"],
+  "github.com/opsgenie/opsgenie-go-sdk-v2": ["opsgenie", "opsgenie-go-sdk-v2"],
+  "github.com/newrelic/go-agent": ["newrelic", "go-agent"],
+  "github.com/honeycombio/libhoney-go": ["honeycomb", "libhoney-go"],
+  "github.com/honeycombio/beeline-go": ["honeycomb", "beeline-go"],
+  "github.com/honeycombio/otel-config-go": ["honeycomb", "otel-config-go"],
+  // Azure: one provider per service, not a single "azure"
+  "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob": ["azure-blob-storage", "azblob"],
+  "github.com/Azure/azure-storage-blob-go": ["azure-blob-storage", "azure-storage-blob-go"],
+  "github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos": ["azure-cosmos-db", "azcosmos"],
+  "github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus": ["azure-service-bus", "azservicebus"],
+  "github.com/Azure/azure-service-bus-go": ["azure-service-bus", "azure-service-bus-go"],
+  "github.com/Azure/azure-sdk-for-go/sdk/azidentity": ["azure-entra-id", "azidentity"],
+  "github.com/Azure/azure-sdk-for-go/sdk/ai/azopenai": ["azure-openai", "azopenai"],
 };
 
+// ---------------------------------------------------------------------------
+// AWS service clients with their own catalog entry; other services stay "aws"
+// ---------------------------------------------------------------------------
+
+const AWS_SERVICE_PROVIDERS: Record<string, string> = {
+  s3: "aws-s3",
+  dynamodb: "aws-dynamodb",
+  sqs: "aws-sqs",
+  sns: "aws-sns",
+  ses: "aws-ses",
+  sesv2: "aws-ses",
+  lambda: "aws-lambda",
+  secretsmanager: "aws-secrets-manager",
+  bedrock: "aws-bedrock",
+  bedrockruntime: "aws-bedrock",
+  bedrockagent: "aws-bedrock",
+  bedrockagentruntime: "aws-bedrock",
+  redshift: "aws-redshift",
+  redshiftdata: "aws-redshift",
+  redshiftserverless: "aws-redshift",
+  kafka: "aws-msk",
+  rds: "aws-rds",
+  elasticache: "aws-elasticache",
+  sfn: "aws-step-functions",
+  opensearch: "aws-opensearch",
+  opensearchserverless: "aws-opensearch",
+  elasticsearchservice: "aws-opensearch",
+};
+
+// github.com/aws/aws-sdk-go-v2/service/s3, github.com/aws/aws-sdk-go/service/sqs
+const AWS_SERVICE_IMPORT_RE = /^github\.com\/aws\/aws-sdk-go(-v2)?\/service\/(\w+)/;
+
+// Google Workspace APIs, one package per API: google.golang.org/api/calendar/v3, .../drive/v3
+const GOOGLE_WORKSPACE_IMPORT_RE =
+  /^google\.golang\.org\/api\/(calendar|drive|gmail|sheets|docs|slides|forms|admin|people|chat|tasks)\//;
+
+// Region literals: config.WithRegion("us-west-2"), Region: aws.String("eu-west-1")
+const AWS_REGION_RE = /"((?:us|eu|ap|sa|ca|me|af|il|mx|cn)(?:-gov)?-[a-z]+-\d)"/g;
+
 // ---------------------------------------------------------------------------
 // HTTP patterns: [regex, kind tag]
 // ---------------------------------------------------------------------------
@@ -43,10 +215,349 @@ const SDK_CONSTRUCTORS: [RegExp, string, string][] = [
   [/(\w+)\.NewFromConfig\(/, "aws", "aws-sdk-go-v2"],
   // Stripe: charge.New(params), customer.New(params)
   [/(?:charge|customer|paymentintent|subscription|invoice)\.New\(/, "stripe", "stripe-go"],
+  // Other processors: paypal.NewClient(id, secret, paypal.APIBaseLive),
+  // braintree.New(braintree.Production, ...), adyen.NewClient(&common.Config{...}),
+  // and Square's client.NewClient(option.WithToken(...))
+  [/\bpaypal\.NewClient\(/, "paypal", "plutov/paypal"],
+  [/\bbraintree\.New(?:WithAccessToken|WithHttpClient)?\(/, "braintree", "braintree-go"],
+  [/\badyen\.NewClient\(/, "adyen", "adyen-go-api-library"],
+  [/\b(?:squareclient|client)\.NewClient\(/, "square", "square-go-sdk"],
+  // Plaid: plaid.NewAPIClient(plaid.NewConfiguration())
+  [/\bplaid\.NewAPIClient\(/, "plaid", "plaid-go"],
+  // Billing and tax: chargebee.Configure(key, site), recurly.NewClient(key),
+  // taxjar.NewClient(taxjar.Config{APIKey: ...})
+  [/\bchargebee\.Configure\(/, "chargebee", "chargebee-go"],
+  [/\brecurly\.NewClient\(/, "recurly", "recurly-client-go"],
+  [/\btaxjar\.NewClient\(/, "taxjar", "taxjar-go"],
+  // Shopify: goshopify.NewClient(app, "acme", token)
+  [/\bgoshopify\.NewClient\(/, "shopify", "go-shopify"],
+  // Shipping: easypost.New(key), go-shippo's client.NewClient(token)
+  [/\beasypost\.New\(/, "easypost", "easypost-go"],
+  [/\b(?:shippo|client)\.NewClient\(/, "shippo", "go-shippo"],
+  // Google Maps: maps.NewClient(maps.WithAPIKey(key))
+  [/\bmaps\.NewClient\(/, "google-maps", "google-maps-services-go"],
+  // Object storage: minio.New("play.min.io", opts), b2.NewClient(ctx, id, key)
+  [/\bminio\.New\(/, "minio", "minio-go"],
+  [/\bb2\.NewClient\(/, "backblaze-b2", "blazer"],
+  // Media: cloudinary.NewFromURL(url), cloudinary.NewFromParams(cloud, key, secret),
+  // imgix.NewURLBuilder("acme.imgix.net"), muxgo.NewAPIClient(cfg), transloadit.NewClient(opts)
+  [/\bcloudinary\.New(?:FromURL|FromParams)?\(/, "cloudinary", "cloudinary-go"],
+  [/\bimgix\.NewURLBuilder\(/, "imgix", "imgix-go"],
+  [/\bmuxgo\.NewAPIClient\(/, "mux", "mux-go"],
+  [/\btransloadit\.NewClient\(/, "transloadit", "transloadit/go-sdk"],
+  // CDN management: cloudflare.NewWithAPIToken(token), cloudflare.NewClient(opts...),
+  // fastly.NewClient(key), edgegrid.New(edgegrid.WithEnv(true))
+  [/\bcloudflare\.New(?:WithAPIToken|Client)?\(/, "cloudflare", "cloudflare-go"],
+  [/\bfastly\.NewClient\(/, "fastly", "go-fastly"],
+  [/\bedgegrid\.New\(/, "akamai", "edgegrid-golang"],
+  // Push: apns2.NewTokenClient(token), apns2.NewClient(cert),
+  // onesignal.NewAPIClient(cfg), expo.NewPushClient(nil)
+  [/\bapns2\.New(?:Token)?Client\(/, "apns", "apns2"],
+  [/\bonesignal\.NewAPIClient\(/, "onesignal", "onesignal-go-api"],
+  [/\bexpo\.NewPushClient\(/, "expo", "exponent-server-sdk-golang"],
+  // IPinfo: ipinfo.NewClient(nil, cache, token)
+  [/\bipinfo\.NewClient\(/, "ipinfo", "ipinfo/go"],
+  // Open Exchange Rates: dinero.NewClient(appID, "USD", 20*time.Minute)
+  [/\bdinero\.NewClient\(/, "openexchangerates", "dinero"],
+  // People Data Labs: pdl.New(apiKey), under the alias its docs use
+  [/\b(?:pdl|peopledatalabs)\.New\(/, "peopledatalabs", "peopledatalabs-go"],
+  // reCAPTCHA Enterprise: recaptcha.NewClient(ctx) from recaptchaenterprise/v2/apiv1
+  [/\brecaptcha(?:enterprise)?\.NewClient\(/, "recaptcha", "cloud.google.com/go/recaptchaenterprise"],
   // OpenAI: openai.NewClient(...)
-  [/openai\.NewClient\(/, "openai", "go-openai"],
-  // GCP: storage.NewClient(ctx), pubsub.NewClient(ctx, ...)
-  [/(storage|pubsub|bigquery|firestore|spanner)\.NewClient\(/, "gcp", "google-cloud-go"],
+  [/\bopenai\.NewClient\(/, "openai", "go-openai"],
+  // LLM providers: anthropic.NewClient(...), cohereclient.NewClient(...),
+  // mistral.NewMistralClientDefault(key), genai.NewClient(ctx, ...) for the Gemini API
+  [/\banthropic\.NewClient\(/, "anthropic", "anthropic-sdk-go"],
+  [/\bcohereclient\.NewClient\(/, "cohere", "cohere-go"],
+  [/\bmistral\.NewMistralClient\w*\(/, "mistral", "mistral-go"],
+  [/\bgenai\.NewClient\(/, "gemini", "generative-ai-go"],
+  // Replicate: replicate.NewClient(replicate.WithTokenFromEnv())
+  [/\breplicate\.NewClient\(/, "replicate", "replicate-go"],
+  // Twilio: twilio.NewRestClient(), twilio.NewRestClientWithParams(...)
+  [/\btwilio\.NewRestClient\w*\(/, "twilio", "twilio-go"],
+  // Vonage: vonage.NewSMSClient(auth), vonage.NewVerifyClient(auth)
+  [/\bvonage\.New\w+Client\(/, "vonage", "vonage-go-sdk"],
+  // Email: sendgrid.NewSendClient(key), mailgun.NewMailgun(domain, key), postmark.NewClient(server, account)
+  [/\bsendgrid\.NewSendClient\(/, "sendgrid", "sendgrid-go"],
+  [/\bmailgun\.NewMailgun\w*\(/, "mailgun", "mailgun-go"],
+  [/\bpostmark\.NewClient\(/, "postmark", "mrz1836/postmark"],
+  [/\bresend\.NewClient\(/, "resend", "resend-go"],
+  // Chat: slack.New(token), slack.PostWebhook(url, msg), goteamsnotify.NewTeamsClient()
+  [/\bslack\.(?:New|PostWebhook\w*)\(/, "slack", "slack-go"],
+  [/\bgoteamsnotify\.NewTeamsClient\(/, "microsoft-teams", "go-teams-notify"],
+  // Microsoft Graph: msgraphsdk.NewGraphServiceClientWithCredentials(cred, scopes)
+  [/\bmsgraphsdk\.NewGraphServiceClient\w*\(/, "microsoft-graph", "msgraph-sdk-go"],
+  // Google Workspace: calendar.NewService(ctx, option.WithTokenSource(ts)), drive.NewService(...)
+  [/\b(?:calendar|drive|gmail|sheets|docs|slides|forms|admin|people|chat|tasks)\.NewService\(/, "google-workspace", "google.golang.org/api"],
+  // Firebase: firebase.NewApp(ctx, &firebase.Config{...}); Supabase: supabase.NewClient(url, key, nil)
+  [/\bfirebase\.NewApp\(/, "firebase", "firebase-admin-go"],
+  [/\bsupabase\.(?:NewClient|CreateClient)\(/, "supabase", "supabase-go"],
+  // Identity: management.New(domain, ...), authentication.New(ctx, domain, ...), okta.NewClient(ctx, ...)
+  [/\b(?:management|authentication)\.New\(/, "auth0", "go-auth0"],
+  [/\bokta\.NewClient\(/, "okta", "okta-sdk-golang"],
+  // Source control: github.NewClient(httpClient), githubv4.NewClient(httpClient),
+  // gitlab.NewClient(token), bitbucket.NewOAuthbearerToken(token)
+  [/\bgithub\.NewClient\(/, "github", "go-github"],
+  [/\bgithubv4\.NewClient\(/, "github", "githubv4"],
+  [/\bgitlab\.(?:NewClient|NewOAuthClient|NewJobClient|NewBasicAuthClient)\(/, "gitlab", "go-gitlab"],
+  [/\bbitbucket\.(?:NewOAuthbearerToken|NewBasicAuth|NewOAuthClientCredentials)\(/, "bitbucket", "go-bitbucket"],
+  // Error tracking: sentry.Init(sentry.ClientOptions{...}), bugsnag.Configure(...), rollbar.SetToken(...)
+  [/\bsentry\.(?:Init|NewClient)\(/, "sentry", "sentry-go"],
+  [/\bbugsnag\.Configure\(/, "bugsnag", "bugsnag-go"],
+  [/\brollbar\.(?:SetToken|New)\(/, "rollbar", "rollbar-go"],
+  // Datadog: tracer.Start(...), profiler.Start(...), statsd.New("127.0.0.1:8125")
+  [/\btracer\.Start\(/, "datadog", "dd-trace-go"],
+  [/\bprofiler\.Start\(/, "datadog", "dd-trace-go"],
+  [/\bstatsd\.New\(/, "datadog", "datadog-go"],
+  // Incident management: pagerduty.NewClient(token), pagerduty.ManageEventWithContext(ctx, event)
+  // for the Events API, and opsgenie-go-sdk-v2's alert.NewClient(&client.Config{...})
+  [/\bpagerduty\.(?:NewClient|ManageEvent(?:WithContext)?)\(/, "pagerduty", "go-pagerduty"],
+  [/\b(?:alert|incident|schedule)\.NewClient\(/, "opsgenie", "opsgenie-go-sdk-v2"],
+  // New Relic: newrelic.NewApplication(newrelic.ConfigLicense(...))
+  [/\bnewrelic\.(?:NewApplication|ConfigLicense)\(/, "newrelic", "go-agent"],
+  // Honeycomb: libhoney.Init(libhoney.Config{...}), beeline.Init(beeline.Config{...})
+  [/\blibhoney\.Init\(/, "honeycomb", "libhoney-go"],
+  [/\bbeeline\.Init\(/, "honeycomb", "beeline-go"],
+  // GCP: storage.NewClient(ctx), pubsub.NewClient(ctx, ...), genai.NewClient(ctx, project, region)
+  [/\bstorage\.NewClient\(/, "gcp-cloud-storage", "cloud.google.com/go/storage"],
+  [/\bbigquery\.NewClient\(/, "gcp-bigquery", "cloud.google.com/go/bigquery"],
+  [/\bpubsub\.NewClient\(/, "gcp-pubsub", "cloud.google.com/go/pubsub"],
+  [/\bfirestore\.NewClient\w*\(/, "gcp-firestore", "cloud.google.com/go/firestore"],
+  [/\bspanner\.NewClient\w*\(/, "gcp-spanner", "cloud.google.com/go/spanner"],
+  [/\bgenai\.NewClient\(/, "gcp-vertex-ai", "cloud.google.com/go/vertexai"],
+  [/\baiplatform\.New\w+Client\(/, "gcp-vertex-ai", "cloud.google.com/go/aiplatform"],
+  // Workflows: workflows.NewClient(ctx), executions.NewClient(ctx)
+  [/\b(?:workflows|executions)\.NewClient\(/, "gcp-workflows", "cloud.google.com/go/workflows"],
+  // Azure: azblob.NewClient(url, cred, nil), azblob.NewClientFromConnectionString(...)
+  [/azblob\.NewClient\w*\(/, "azure-blob-storage", "azblob"],
+  [/azcosmos\.NewClient\w*\(/, "azure-cosmos-db", "azcosmos"],
+  [/azservicebus\.NewClient\w*\(/, "azure-service-bus", "azservicebus"],
+  [/azidentity\.New\w*Credential\(/, "azure-entra-id", "azidentity"],
+  [/azopenai\.NewClient\w*\(/, "azure-openai", "azopenai"],
+  // Search: search.NewClient(appID, apiKey), meilisearch.New(host, ...), typesense.NewClient(...)
+  [/\bsearch\.NewClient\w*\(/, "algolia", "algoliasearch-client-go"],
+  [/\bmeilisearch\.New(?:Client)?\(/, "meilisearch", "meilisearch-go"],
+  [/\btypesense\.NewClient\(/, "typesense", "typesense-go"],
+  // Feature flags: ld.MakeClient(sdkKey, 5*time.Second), client.NewSplitFactory(key, cfg),
+  // unleash.Initialize(unleash.WithUrl(...)), flagsmith.NewClient(key, ...)
+  [/\b(?:ld|ldclient)\.Make(?:Custom)?Client\(/, "launchdarkly", "go-server-sdk"],
+  [/\.NewSplitFactory\(/, "split", "splitio/go-client"],
+  [/\bunleash\.(?:Initialize|NewClient)\(/, "unleash", "unleash-client-go"],
+  [/\bflagsmith\.NewClient\(/, "flagsmith", "flagsmith-go-client"],
+  // Product analytics: analytics.New(writeKey) and analytics.NewWithConfig(...) are both
+  // Segment and RudderStack; mixpanel.NewApiClient(token), amplitude.NewClient(config)
+  [/\banalytics\.New(?:WithConfig)?\(/, "segment", "segmentio/analytics-go"],
+  [/\banalytics\.New(?:WithConfig)?\(/, "rudderstack", "rudderlabs/analytics-go"],
+  [/\bmixpanel\.New(?:ApiClient)?\(/, "mixpanel", "mixpanel-go"],
+  [/\bamplitude\.NewClient\(/, "amplitude", "amplitude/analytics-go"],
+  // HCP Terraform: tfe.NewClient(&tfe.Config{Token: ...})
+  [/\btfe\.NewClient\(/, "terraform-cloud", "go-tfe"],
+];
+
+// Providers whose constructor names are common enough to need the import too
+// (genai.NewClient is both Gemini and Vertex AI; the import decides which)
+const IMPORT_GATED_PROVIDERS = new Set([
+  "datadog", "github", "gitlab", "bitbucket", "auth0", "gemini", "algolia", "terraform-cloud",
+  "segment", "rudderstack", "square", "shippo", "google-maps", "recaptcha", "google-workspace",
+  "opsgenie", "peopledatalabs",
+]);
+
+// OAuth 2.0 token endpoints, where the app exchanges client credentials and codes.
+// Literal ones: oauth2.Endpoint{TokenURL: "https://idp.example.com/token"} and
+// clientcredentials.Config{TokenURL: ...}. Named ones come from golang.org/x/oauth2's
+// provider packages (github.Endpoint, microsoft.AzureADEndpoint("acme.onmicrosoft.com"))
+// and its endpoints package (endpoints.GitHub, endpoints.AzureAD(tenant)).
+const TOKEN_URL_RE = /\bTokenURL:\s*"(https:\/\/[^"\s]+)"/;
+const OAUTH2_IMPORT_RE = /^golang\.org\/x\/oauth2\/(\w+)$/;
+
+// x/oauth2 provider → [token URL, catalog provider]; the microsoft package's
+// AzureADEndpoint is "azuread", and {tenant} defaults to "common"
+const OAUTH2_ENDPOINTS: Record<string, [string, string | null]> = {
+  github: ["https://github.com/login/oauth/access_token", "github"],
+  gitlab: ["https://gitlab.com/oauth/token", "gitlab"],
+  bitbucket: ["https://bitbucket.org/site/oauth2/access_token", "bitbucket"],
+  google: ["https://oauth2.googleapis.com/token", "google-identity"],
+  azuread: ["https://login.microsoftonline.com/{tenant}/oauth2/v2.0/token", "azure-entra-id"],
+  slack: ["https://slack.com/api/oauth.access", "slack"],
+  facebook: ["https://graph.facebook.com/v3.2/oauth/access_token", null],
+  linkedin: ["https://www.linkedin.com/oauth/v2/accessToken", null],
+  amazon: ["https://api.amazon.com/auth/o2/token", null],
+  spotify: ["https://accounts.spotify.com/api/token", null],
+};
+
+// OIDC issuers and IdP tenants: oidc.NewProvider(ctx, "https://accounts.google.com"),
+// Issuer: "https://acme.okta.com/oauth2/default", management.New("acme.us.auth0.com", ...)
+const ISSUER_RE =
+  /(?:\boidc\.NewProvider\(\s*\w+,\s*|\bIssuer(?:URL)?:\s*|\bWithOrgUrl\(\s*|\b(?:management|authentication)\.New\(\s*(?:\w+,\s*)?)"((?:https:\/\/)?[\w-]+(?:\.[\w-]+)*\.[a-z]{2,}(?:\/[^"\s]*)?)"/;
+
+// ---------------------------------------------------------------------------
+// Cloud and telemetry endpoints passed to SDK clients rather than net/http, e.g.
+// azblob.NewClient("https://acct.blob.core.windows.net/", ...) or
+// option.WithEndpoint("https://us-central1-aiplatform.googleapis.com") or
+// firebase.Config{DatabaseURL: "https://acme-default-rtdb.firebaseio.com"}
+// ---------------------------------------------------------------------------
+
+const SERVICE_ENDPOINT_RE =
+  /"(https:\/\/[\w%.-]+\.(?:windows\.net|azure\.com|azure\.net|googleapis\.com|anthropic\.com|cohere\.ai|cohere\.com|mistral\.ai|replicate\.com|huggingface\.co|huggingface\.cloud|firebaseio\.com|firebasedatabase\.app|supabase\.co|meilisearch\.io|typesense\.net|datadoghq\.com|datadoghq\.eu|newrelic\.com|nr-data\.net|honeycomb\.io)(?:[:/][^"\s]*)?)"/g;
+
+// Telemetry intake configured as host:port, e.g. otlptracegrpc.WithEndpoint("api.honeycomb.io:443")
+// or libhoney.Config{APIHost: "https://api.eu1.honeycomb.io"}
+const TELEMETRY_ENDPOINT_RE =
+  /(?:\.WithEndpoint|APIHost:|Host:)\(?\s*"((?:https?:\/\/)?[\w.-]+\.(?:datadoghq\.com|datadoghq\.eu|newrelic\.com|nr-data\.net|honeycomb\.io)(?::\d+)?)"/;
+
+// ---------------------------------------------------------------------------
+// Chat incoming webhooks. The path is the credential, so it is redacted from
+// reported URLs: hooks.slack.com/services/<redacted>
+// ---------------------------------------------------------------------------
+
+const WEBHOOK_URL_RE =
+  /"(https:\/\/(?:hooks\.slack\.com\/(?:services|workflows|triggers)\/|[\w-]+\.webhook\.office\.com\/webhookb2\/|outlook\.office\.com\/webhook\/)[^"\s]+)"/;
+
+const WEBHOOK_TOKEN_RE =
+  /^(https:\/\/(?:hooks\.slack\.com\/(?:services|workflows|triggers)|[\w-]+\.webhook\.office\.com\/webhookb2|outlook\.office\.com\/webhook))\/.+$/;
+
+// Graph request builders name the workload: client.Teams().ByTeamId(id).Channels()
+const GRAPH_SERVICE_RE = /\.(Teams|Chats|Users|Groups|Sites|Drives|Me|Calendars|Contacts|Planner|Communications)\(\)/g;
+
+// OpenAI-compatible base URLs: openai.DefaultAzureConfig(key, "https://acme.openai.azure.com"),
+// cfg.BaseURL = "http://localhost:8000/v1", option.WithBaseURL(os.Getenv("LLM_BASE_URL")).
+// Core reports the provider behind the URL instead of "openai".
+const OPENAI_BASE_URL_RE =
+  /(?:\bopenai\.DefaultAzureConfig\(\s*[^,]+,\s*|\.BaseURL\s*=\s*|\boption\.WithBaseURL\(\s*|\bazure\.WithEndpoint\(\s*)(?:"([^"]+)"|os\.Getenv\(\s*"(\w+)"\s*\))/;
+
+// Services the SDK talks to when not the vendor's default endpoint: feature-flag
+// servers (unleash.WithUrl("https://unleash.internal/api/"), flagsmith.WithBaseURL(...))
+// analytics ingestion (analytics.Config{Endpoint: "https://events.eu1.segmentapis.com"},
+// DataPlaneUrl: "https://acme.dataplane.rudderstack.com", amplitude.Config{ServerURL: ...}),
+// and S3-compatible storage (o.BaseEndpoint = aws.String("https://<account>.r2.cloudflarestorage.com")).
+// Core reports the storage provider behind an S3 endpoint instead of "aws-s3".
+const SDK_BASE_URL_PATTERNS: [RegExp, string][] = [
+  [/\bunleash\.WithUrl\(\s*(?:"([^"]+)"|os\.Getenv\(\s*"(\w+)"\s*\))/, "unleash"],
+  [/\bflagsmith\.WithBaseURL\(\s*(?:"([^"]+)"|os\.Getenv\(\s*"(\w+)"\s*\))/, "flagsmith"],
+  [/\bldcomponents\.RelayProxyEndpoints\(\s*(?:"([^"]+)"|os\.Getenv\(\s*"(\w+)"\s*\))/, "launchdarkly"],
+  [/\bEndpoint:\s*(?:"([^"]+)"|os\.Getenv\(\s*"(\w+)"\s*\))/, "segment"],
+  [/\bDataPlaneUrl:\s*(?:"([^"]+)"|os\.Getenv\(\s*"(\w+)"\s*\))/, "rudderstack"],
+  [/\bServerURL:\s*(?:"([^"]+)"|os\.Getenv\(\s*"(\w+)"\s*\))/, "amplitude"],
+  [/\b(?:Base)?Endpoint(?:\s*=\s*|:\s*)aws\.String\(\s*(?:"([^"]+)"|os\.Getenv\(\s*"(\w+)"\s*\))/, "aws-s3"],
+];
+
+// Payment, banking, and push SDK environments name the API host, so sandbox keys show up as such:
+// paypal.NewClient(id, secret, paypal.APIBaseSandBox), cfg.UseEnvironment(plaid.Production),
+// apns2.NewTokenClient(token).Development()
+const SDK_ENVIRONMENTS: [RegExp, string, string][] = [
+  [/\bpaypal\.APIBaseLive\b/, "paypal", "https://api-m.paypal.com"],
+  [/\bpaypal\.APIBaseSandBox\b/, "paypal", "https://api-m.sandbox.paypal.com"],
+  [/\bbraintree\.Production\b/, "braintree", "https://api.braintreegateway.com"],
+  [/\bbraintree\.Sandbox\b/, "braintree", "https://api.sandbox.braintreegateway.com"],
+  [/\bcommon\.TestEnv\b/, "adyen", "https://checkout-test.adyen.com"],
+  [/\bsquare\.Environments\.Production\b/, "square", "https://connect.squareup.com"],
+  [/\bsquare\.Environments\.Sandbox\b/, "square", "https://connect.squareupsandbox.com"],
+  [/\bplaid\.Production\b/, "plaid", "https://production.plaid.com"],
+  [/\bplaid\.Sandbox\b/, "plaid", "https://sandbox.plaid.com"],
+  [/\bapns2\.HostDevelopment\b|\.Development\(\)/, "apns", "https://api.sandbox.push.apple.com"],
+  [/\bapns2\.HostProduction\b|\.Production\(\)/, "apns", "https://api.push.apple.com"],
+];
+
+// Shopify clients are bound to one shop: goshopify.NewClient(app, "acme", token)
+// or "acme.myshopify.com" → https://acme.myshopify.com
+const SHOPIFY_SHOP_RE = /\bgoshopify\.NewClient\(\s*\w+,\s*"([\w-]+)(?:\.myshopify\.com)?"/;
+
+// Firebase service clients come off the app: app.Firestore(ctx), app.Messaging(ctx)
+const FIREBASE_SERVICE_RE = /\.(Firestore|Database|DatabaseWithURL|Messaging|Auth|Storage|AppCheck|RemoteConfig)\(\s*ctx\b/g;
+
+// ---------------------------------------------------------------------------
+// Error-tracking DSNs embed the project key: https://<key>@o1.ingest.sentry.io/42
+// ---------------------------------------------------------------------------
+
+const DSN_RE = /"(https?:\/\/[a-f0-9]{32}(?::[a-f0-9]{32})?@[\w.-]+(?::\d+)?\/[\w/-]*\d)"/;
+
+// Error trackers always receive stack traces; these markers mean more leaves the service
+const DATA_EXPORT_MARKERS: [RegExp, string, string][] = [
+  // HTTP middleware attaches the request (URL, headers, body) to every event
+  [/\bsentry(?:http|gin|echo|fiber|iris|negroni|fasthttp)\.New\(/, "sentry", "request_payloads"],
+  [/\bSendDefaultPII:\s*true\b/, "sentry", "user_data"],
+  [/\bbugsnag(?:gin|negroni|martini|revel)?\.(?:Handler|HandlerFunc|AutoNotify)\(/, "bugsnag", "request_payloads"],
+  [/\brollbar\.(?:RequestError|RequestErrorWithExtras|RequestMessage)\(/, "rollbar", "request_payloads"],
+  [/\brollbar\.SetPerson\(/, "rollbar", "user_data"],
+  // Analytics identify calls attach profile traits (email, name, plan) to the user
+  [/\banalytics\.(?:NewTraits\(\)|Traits\{)/, "segment", "user_traits"],
+  [/\banalytics\.(?:NewTraits\(\)|Traits\{)/, "rudderstack", "user_traits"],
+  [/\b(?:mixpanel\.NewPeopleProperties\(|\.PeopleSet\()/, "mixpanel", "user_traits"],
+  [/\bamplitude\.Identify\{/, "amplitude", "user_traits"],
+];
+
+// ---------------------------------------------------------------------------
+// SMTP relays, reported as smtp:// API entries so the host maps to a vendor:
+// smtp.SendMail("smtp.sendgrid.net:587", ...), gomail.NewDialer("smtp.mailgun.org", 587, ...)
+// ---------------------------------------------------------------------------
+
+const SMTP_PATTERNS: RegExp[] = [
+  /\bsmtp\.(?:SendMail|Dial)\(\s*"([\w.-]+\.[a-z]+)(?::(\d+))?"/i,
+  /\.NewDialer\(\s*"([\w.-]+\.[a-z]+)",\s*(\d+)/i,
+];
+
+// File transfer to partners: jlaffaye/ftp's ftp.Dial("ftp.partner.com:21", ...), and
+// ssh.Dial("tcp", "sftp.partner.com:22", cfg), reported as sftp:// when the file
+// imports pkg/sftp (whose client runs over that connection) and ssh:// otherwise
+const FTP_DIAL_RE = /\bftp\.(?:Dial|DialTimeout|Connect)\(\s*"([^"\s]+)"/;
+const SSH_DIAL_RE = /\bssh\.Dial\(\s*"tcp[46]?",\s*"([^"\s]+)"/;
+
+// Raw sockets, reported as tcp:// and udp:// API entries. Not every dependency
+// speaks HTTP: net.Dial("tcp", "34.120.18.9:9000"), net.DialTimeout("udp", addr, d),
+// tls.Dial("tcp", "feed.example.net:7001", cfg), dialer.DialContext(ctx, "tcp", addr)
+const NET_DIAL_RE =
+  /\b(?:net\.Dial(?:Timeout)?|tls\.Dial(?:WithDialer)?|(?<!\bssh)\.Dial(?:Context)?)\(\s*(?:[\w.&]+,\s*)?"(tcp|udp)[46]?",\s*"([^"\s]+)"/;
+
+// WebSocket clients, reported with their ws:// or wss:// URL:
+// websocket.DefaultDialer.Dial("wss://stream.example.com/ws", nil) and
+// dialer.DialContext(ctx, url, header) from gorilla/websocket;
+// websocket.Dial(ctx, "wss://...", opts) from nhooyr.io/websocket and coder/websocket
+const WEBSOCKET_DIAL_RE = /\.Dial(?:Context)?\(\s*(?:\w+,\s*)?"(wss?:\/\/[^"\s]+)"/;
+
+// GraphQL clients, reported as a POST of their endpoint (GET for genqlient's
+// NewClientUsingGet): graphql.NewClient("https://api.github.com/graphql", hc) from
+// machinebox/graphql, shurcooL/graphql, hasura/go-graphql-client, and genqlient
+const GRAPHQL_CLIENT_IMPORTS = new Set([
+  "github.com/machinebox/graphql",
+  "github.com/shurcooL/graphql",
+  "github.com/hasura/go-graphql-client",
+  "github.com/Khan/genqlient/graphql",
+]);
+const GRAPHQL_CLIENT_RE = /\b(\w+)\.NewClient(UsingGet)?\(\s*(?:"(https?:\/\/[^"\s]+)"|os\.Getenv\(\s*"(\w+)"\s*\))/;
+
+// SOAP services, reported as a POST of the service URL (a trailing ?wsdl dropped):
+// soap.NewClient("https://partner.example.com/Orders.svc") from hooklift/gowsdl and
+// globusdigital/soap, gosoap.SoapClient("https://.../Rates.asmx?WSDL", hc)
+const SOAP_CLIENT_IMPORTS = new Set([
+  "github.com/hooklift/gowsdl/soap",
+  "github.com/globusdigital/soap",
+  "github.com/tiaguinho/gosoap",
+]);
+const SOAP_CLIENT_RE = /\b(\w+)\.(?:NewClient|SoapClient)\(\s*"(https?:\/\/[^"\s]+)"/;
+
+// ---------------------------------------------------------------------------
+// Inbound webhooks: routes third parties call back, and how they're verified
+// ---------------------------------------------------------------------------
+
+// mux.HandleFunc("POST /webhooks/stripe", h), r.Post("/hooks/github", h), e.POST("/ipn/paypal", h)
+const WEBHOOK_ROUTE_RE =
+  /\.(?:HandleFunc|Handle|Post|POST|Put|PUT|Any|Match)\(\s*"(?:(?:POST|PUT)\s+)?(\/[^"\s]*)"/;
+const WEBHOOK_PATH_RE = /(?:^|[/_-])(?:webhooks?|hooks?|callbacks?|ipn)(?:$|[/_-])/i;
+
+// SDK verifiers and signature headers name the sender
+const WEBHOOK_VERIFIERS: [RegExp, string][] = [
+  [/\bwebhook\.ConstructEvent(?:WithOptions)?\(|"Stripe-Signature"/, "stripe"],
+  [/\bgithub\.(?:ValidatePayload|ParseWebHook)\(|"X-Hub-Signature-256"|"X-GitHub-Event"/, "github"],
+  [/"X-Gitlab-(?:Token|Event)"/, "gitlab"],
+  [/\bslack\.NewSecretsVerifier\(|"X-Slack-Signature"/, "slack"],
+  [/\bNewRequestValidator\(|"X-Twilio-Signature"/, "twilio"],
+  [/"X-Twilio-Email-Event-Webhook-Signature"/, "sendgrid"],
+  [/"X-Shopify-Hmac-Sha256"/i, "shopify"],
+  [/"X-Square-Hmacsha256-Signature"/i, "square"],
+  [/"Plaid-Verification"/, "plaid"],
+  [/"X-PagerDuty-Signature"/, "pagerduty"],
+  [/"X-Zm-Signature"/, "zoom"],
+  [/"X-DocuSign-Signature-1"/, "docusign"],
 ];
 
 // ---------------------------------------------------------------------------
@@ -54,26 +565,88 @@ const SDK_CONSTRUCTORS: [RegExp, string, string][] = [
 // ---------------------------------------------------------------------------
 
 const INFRA_PATTERNS: [RegExp, string][] = [
-  [/sql\.Open\(\s*"(postgres|pgx|mysql|sqlite3)"/, "match_group"],
+  [/sql\.Open\(\s*"(postgres|pgx|mysql|sqlite3|snowflake|clickhouse|sqlserver|mssql)"/, "match_group"],
   [/pgx\.Connect\(/, "postgresql"],
   [/pgxpool\.New\(/, "postgresql"],
-  [/redis\.NewClient\(/, "redis"],
-  [/redis\.NewClusterClient\(/, "redis"],
-  [/mongo\.Connect\(/, "mongodb"],
-  [/amqp\.Dial\(/, "rabbitmq"],
-  [/kafka\.NewWriter\(/, "kafka"],
-  [/kafka\.NewReader\(/, "kafka"],
-  [/elasticsearch\.NewClient\(/, "elasticsearch"],
+  // mongo-go-driver: the URI is usually built with options.Client().ApplyURI(...)
+  [/\bmongo\.(?:Connect|NewClient)\(/, "mongodb"],
+  [/^(?!.*\bmongo\.(?:Connect|NewClient)\().*\boptions\.Client\(\)\.ApplyURI\(/, "mongodb"],
+  // rabbitmq/amqp091-go (and streadway/amqp), nats-io/nats.go
+  [/\bamqp\.Dial(?:TLS|Config)?\(/, "rabbitmq"],
+  [/\bnats\.Connect\(/, "nats"],
+  // gocql: gocql.NewCluster("10.0.0.1", "10.0.0.2")
+  [/\bgocql\.NewCluster\(/, "cassandra"],
 ];
 
+// Kafka broker lists: []string{"b-1:9092"}, "bootstrap.servers": "a:9092,b:9092",
+// kafka.TCP("a:9092") — as literals or os.Getenv("KAFKA_BROKERS")
+const KAFKA_BROKERS_RE =
+  /(?:\[\]string\{\s*|"bootstrap\.servers"\s*:\s*|kafka\.TCP\(\s*|strings\.Split\(\s*)(?:"([^"]+)"|os\.Getenv\(\s*"([^"]+)"\s*\))/;
+
+// Search cluster addresses: Addresses: []string{"https://..."}, CloudID: "name:base64"
+const SEARCH_ADDRESSES_RE =
+  /(?:Addresses:\s*\[\]string\{\s*|CloudID:\s*)(?:"([^"]+)"|os\.Getenv\(\s*"([^"]+)"\s*\))/;
+
+// Database addresses in driver configs: mysql.Config{Addr: "..."}, clickhouse.Options{Addr: []string{"..."}},
+// redis.ClusterOptions{Addrs: []string{"..."}}
+const DB_ADDRESS_RE = /\bAddrs?:\s*(?:\[\]string\{\s*)?(?:"([^"]+)"|os\.Getenv\(\s*"([^"]+)"\s*\))/;
+
+// Clients configured by a struct literal rather than a connection string:
+// [constructor, infra type, address pattern]
+const CONFIG_INFRA_PATTERNS: [RegExp, string, RegExp][] = [
+  // segmentio/kafka-go and confluent-kafka-go both import as "kafka"
+  [/\bkafka\.New(?:Writer|Reader|Producer|Consumer|AdminClient)\(/, "kafka", KAFKA_BROKERS_RE],
+  [/\bkafka\.Writer\{/, "kafka", KAFKA_BROKERS_RE],
+  [/\bsarama\.New(?:SyncProducer|AsyncProducer|Consumer|ConsumerGroup|Client|ClusterAdmin)\(/, "kafka", KAFKA_BROKERS_RE],
+  // go-elasticsearch and opensearch-go
+  [/\belasticsearch\.New(?:Client|TypedClient|DefaultClient)\(/, "elasticsearch", SEARCH_ADDRESSES_RE],
+  [/\bopensearch(?:api)?\.NewClient\(/, "opensearch", SEARCH_ADDRESSES_RE],
+  // go-redis: redis.Options{Addr: ...}, redis.ClusterOptions{Addrs: ...}
+  [/\bredis\.New(?:Client|ClusterClient|UniversalClient)\(/, "redis", DB_ADDRESS_RE],
+  // go-sql-driver/mysql and clickhouse-go
+  [/\bmysql\.NewConnector\(/, "mysql", DB_ADDRESS_RE],
+  [/\bclickhouse\.Open(?:DB)?\(/, "clickhouse", DB_ADDRESS_RE],
+];
+
+// DataStax Astra: gocqlastra.NewClusterFromBundle("secure-connect.zip", ...), NewClusterFromURL(...)
+const ASTRA_CLUSTER_RE = /\bgocqlastra\.NewClusterFrom(?:Bundle|URL)\(\s*(?:"([^"]+)"|os\.Getenv\(\s*"([^"]+)"\s*\))?/;
+
+// api.Config{Address: "..."}, config.Address = "...", vault.WithAddress("...")
+const HASHICORP_ADDRESS_RE =
+  /(?:\bAddress:\s*|\.Address\s*=\s*|\bWithAddress\(\s*)(?:"([^"]+)"|os\.Getenv\(\s*"([^"]+)"\s*\))/;
+
+// client.Options{HostPort: "acme.a1b2c.tmprl.cloud:7233"}
+const TEMPORAL_HOSTPORT_RE = /\bHostPort:\s*(?:"([^"]+)"|os\.Getenv\(\s*"([^"]+)"\s*\))/;
+
+// Clients whose package names ("api", "client") are too common to match alone, so
+// the import decides: import path → [infra type, package name, address pattern,
+// address used when none is configured (DefaultConfig()'s env var, the SDK default)]
+const IMPORTED_CLIENTS: Record<string, [string, string, RegExp, string]> = {
+  "github.com/hashicorp/vault/api": ["vault", "api", HASHICORP_ADDRESS_RE, "VAULT_ADDR"],
+  "github.com/hashicorp/vault-client-go": ["vault", "vault", HASHICORP_ADDRESS_RE, "VAULT_ADDR"],
+  "github.com/hashicorp/consul/api": ["consul", "api", HASHICORP_ADDRESS_RE, "CONSUL_HTTP_ADDR"],
+  "go.temporal.io/sdk/client": ["temporal", "client", TEMPORAL_HOSTPORT_RE, "localhost:7233"],
+};
+
+// api.NewClient(cfg), vault.New(...), client.Dial(opts), client.NewLazyClient(opts)
+const IMPORTED_CLIENT_RE = /\b(\w+)\.(?:New(?:Client|LazyClient)?|Dial(?:Context)?)\(/;
+
+// Redshift speaks the Postgres protocol; its hosts tell it apart
+const REDSHIFT_HOST_RE = /\.redshift(?:-serverless)?\.amazonaws\.com\b/;
+
 // Connection string URL patterns
 const CONN_STRING_PATTERNS: [RegExp, string][] = [
-  [/postgresql:\/\/[^\s"']+/, "postgresql"],
-  [/postgres:\/\/[^\s"']+/, "postgresql"],
+  [/(?:postgres(?:ql)?|redshift):\/\/[^\s"']+\.redshift(?:-serverless)?\.amazonaws\.com[^\s"']*/, "redshift"],
+  [/postgresql:\/\/(?![^\s"']*\.redshift)[^\s"']+/, "postgresql"],
+  [/postgres:\/\/(?![^\s"']*\.redshift)[^\s"']+/, "postgresql"],
+  [/snowflake:\/\/[^\s"']+/, "snowflake"],
   [/mysql:\/\/[^\s"']+/, "mysql"],
+  [/clickhouse:\/\/[^\s"']+/, "clickhouse"],
+  [/sqlserver:\/\/[^\s"']+/, "sqlserver"],
   [/mongodb(\+srv)?:\/\/[^\s"'`]+/, "mongodb"],
-  [/redis:\/\/[^\s"']+/, "redis"],
-  [/amqp:\/\/[^\s"']+/, "rabbitmq"],
+  [/rediss?:\/\/[^\s"']+/, "redis"],
+  [/amqps?:\/\/[^\s"']+/, "rabbitmq"],
+  [/nats:\/\/[^\s"',]+/, "nats"],
 ];
 
 // ---------------------------------------------------------------------------
@@ -88,11 +661,74 @@ export function analyzeGo(context: AnalyzerContext): DependencyEntry[] {
   // Parse imports to resolve SDK providers
   const imports = detectImports(context.source);
 
+  // x/oauth2 endpoint references: github.Endpoint, endpoints.AzureAD("tenant")
+  const oauth2Endpoints: [RegExp, string | null][] = [];
+  for (const [alias, importPath] of imports) {
+    const pkg = importPath.match(OAUTH2_IMPORT_RE)?.[1];
+    if (pkg === "endpoints") {
+      oauth2Endpoints.push([new RegExp(String.raw`\b${alias}\.(\w+)\b(?:\(\s*"([^"]*)"\s*\))?`), null]);
+    } else if (pkg === "microsoft") {
+      oauth2Endpoints.push([new RegExp(String.raw`\b${alias}\.AzureADEndpoint\(\s*"([^"]*)"\s*\)`), "azuread"]);
+    } else if (pkg && OAUTH2_ENDPOINTS[pkg]) {
+      oauth2Endpoints.push([new RegExp(String.raw`\b${alias}\.Endpoint\b`), pkg]);
+    }
+  }
+
+  // Aliases of imported GraphQL client packages
+  const graphqlAliases = new Set([...imports].filter(([, path]) => GRAPHQL_CLIENT_IMPORTS.has(path)).map(([alias]) => alias));
+
+  // Aliases of imported SOAP client packages
+  const soapAliases = new Set([...imports].filter(([, path]) => SOAP_CLIENT_IMPORTS.has(path)).map(([alias]) => alias));
+
+  // SSH connections carry SFTP when the file uses pkg/sftp
+  const usesSftp = [...imports.values()].includes("github.com/pkg/sftp");
+
   // Track emitted SDK providers to avoid duplicates (P1 #1)
   const emittedSdkProviders = new Map<string, DependencyEntry>();
 
+  // Service packages of one SDK: a single entry per provider listing the services imported
+  const addServiceImport = (provider: string, sdkPackage: string, service: string, importPath: string) => {
+    const existing = emittedSdkProviders.get(provider);
+    if (existing && existing.kind === "sdk") {
+      if (!existing.services_used?.includes(service)) {
+        existing.services_used = [...(existing.services_used ?? []), service];
+      }
+      return;
+    }
+    const entry: DependencyEntry = {
+      kind: "sdk",
+      provider,
+      sdk_package: sdkPackage,
+      services_used: [service],
+      locations: [{ file: rel, line: 1, context: `import "${importPath}"` }],
+      usage_count: 1,
+      confidence: "high",
+    };
+    emittedSdkProviders.set(provider, entry);
+    entries.push(entry);
+  };
+
   // Detect SDK entries from imports (deduplicated by provider)
   for (const [, importPath] of imports) {
+    // AWS service clients: one provider per service with its own catalog entry
+    const awsService = importPath.match(AWS_SERVICE_IMPORT_RE);
+    if (awsService) {
+      const service = awsService[2]!;
+      addServiceImport(
+        AWS_SERVICE_PROVIDERS[service] ?? "aws",
+        awsService[1] ? awsService[0] : "aws-sdk-go",
+        service,
+        importPath,
+      );
+      continue;
+    }
+
+    const workspaceApi = importPath.match(GOOGLE_WORKSPACE_IMPORT_RE);
+    if (workspaceApi) {
+      addServiceImport("google-workspace", "google.golang.org/api", workspaceApi[1]!, importPath);
+      continue;
+    }
+
     for (const [prefix, [provider, sdkPackage]] of Object.entries(SDK_PROVIDERS)) {
       if (importPath === prefix || importPath.startsWith(prefix + "/")) {
         if (!emittedSdkProviders.has(provider)) {
@@ -112,6 +748,30 @@ export function analyzeGo(context: AnalyzerContext): DependencyEntry[] {
     }
   }
 
+  // aws-sdk-go-v2/config or /aws alone adds nothing once a service client is reported
+  const awsCore = emittedSdkProviders.get("aws");
+  if (
+    awsCore &&
+    awsCore.kind === "sdk" &&
+    !awsCore.services_used &&
+    [...emittedSdkProviders.keys()].some((p) => p.startsWith("aws-"))
+  ) {
+    emittedSdkProviders.delete("aws");
+    entries.splice(entries.indexOf(awsCore), 1);
+  }
+
+  // Vault, Consul, and Temporal client packages by the name this file uses for them
+  const importedClients = new Map<string, [string, RegExp, string]>();
+  for (const [alias, importPath] of imports) {
+    const client = IMPORTED_CLIENTS[importPath];
+    if (!client) continue;
+    const name = alias === defaultAlias(importPath) ? client[1] : alias;
+    importedClients.set(name, [client[0], client[2], client[3]]);
+  }
+
+  // Clients share one aws.Config, so a region literal anywhere applies to all of them
+  const awsRegions = new Set<string>();
+
   for (let i = 0; i < lines.length; i++) {
     const line = lines[i]!;
     const lineNum = i + 1;
@@ -120,6 +780,8 @@ export function analyzeGo(context: AnalyzerContext): DependencyEntry[] {
     // Skip comments
     if (trimmed.startsWith("//")) continue;
 
+    for (const m of line.matchAll(AWS_REGION_RE)) awsRegions.add(m[1]!);
+
     // --- HTTP detection ---
     for (const [pattern, kind] of HTTP_PATTERNS) {
       const match = line.match(pattern);
@@ -138,6 +800,7 @@ export function analyzeGo(context: AnalyzerContext): DependencyEntry[] {
       }
 
       const confidence: Confidence = /^https?:\/\//.test(url) ? "high" : "medium";
+      url = redactWebhookUrl(url);
 
       entries.push({
         kind: "api",
@@ -149,28 +812,213 @@ export function analyzeGo(context: AnalyzerContext): DependencyEntry[] {
       });
     }
 
+    // --- Service endpoint literals (not already seen as an HTTP call) ---
+    for (const m of line.matchAll(SERVICE_ENDPOINT_RE)) {
+      const url = m[1]!;
+      const seen = entries.some(
+        (e) => e.kind === "api" && e.url === url && e.locations.some((l) => l.line === lineNum),
+      );
+      if (seen) continue;
+      entries.push({
+        kind: "api",
+        url,
+        locations: [{ file: rel, line: lineNum, context: trimmed }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+
+    // --- Telemetry intake hosts without a scheme ---
+    const intake = line.match(TELEMETRY_ENDPOINT_RE);
+    if (intake) {
+      const url = /^https?:\/\//.test(intake[1]!) ? intake[1]! : `https://${intake[1]!}`;
+      const seen = entries.some(
+        (e) => e.kind === "api" && e.url === url && e.locations.some((l) => l.line === lineNum),
+      );
+      if (!seen) {
+        entries.push({
+          kind: "api",
+          url,
+          locations: [{ file: rel, line: lineNum, context: trimmed }],
+          usage_count: 1,
+          confidence: "high",
+        });
+      }
+    }
+
+    // --- GraphQL endpoints ---
+    const graphql = graphqlAliases.size > 0 ? line.match(GRAPHQL_CLIENT_RE) : null;
+    if (graphql && graphqlAliases.has(graphql[1]!)) {
+      entries.push({
+        kind: "api",
+        url: graphql[3] ?? `\${${graphql[4]!}}`,
+        method: graphql[2] ? "GET" : "POST",
+        locations: [{ file: rel, line: lineNum, context: trimmed, usage: "graphql_client" }],
+        usage_count: 1,
+        confidence: graphql[3] ? "high" : "medium",
+      });
+    }
+
+    // --- SOAP services ---
+    const soap = soapAliases.size > 0 ? line.match(SOAP_CLIENT_RE) : null;
+    if (soap && soapAliases.has(soap[1]!)) {
+      entries.push({
+        kind: "api",
+        url: soap[2]!.replace(/\?wsdl$/i, ""),
+        method: "POST",
+        locations: [{ file: rel, line: lineNum, context: trimmed, usage: "soap_client" }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+
+    // --- OAuth token endpoints, reported in the auth category ---
+    const tokenUrl = line.match(TOKEN_URL_RE);
+    let token: [string, string | null] | undefined = tokenUrl ? [tokenUrl[1]!, null] : undefined;
+    for (const [pattern, pkg] of token ? [] : oauth2Endpoints) {
+      const ref = line.match(pattern);
+      const known = ref ? OAUTH2_ENDPOINTS[pkg ?? ref[1]!.toLowerCase()] : undefined;
+      if (!ref || !known) continue;
+      const tenant = (pkg ? ref[1] : ref[2]) || "common";
+      token = [known[0].replace("{tenant}", tenant), known[1]];
+      break;
+    }
+    if (token) {
+      entries.push({
+        kind: "api",
+        url: token[0],
+        method: "POST",
+        ...(token[1] ? { provider: token[1] } : {}),
+        category: "auth",
+        locations: [{ file: rel, line: lineNum, context: trimmed, usage: "oauth2_token_endpoint" }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+
+    // --- OIDC issuers, reported in the auth category ---
+    const issuer = line.match(ISSUER_RE);
+    if (issuer) {
+      entries.push({
+        kind: "api",
+        url: issuer[1]!.startsWith("https://") ? issuer[1]! : `https://${issuer[1]!}`,
+        category: "auth",
+        locations: [{ file: rel, line: lineNum, context: trimmed }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+
+    // --- Chat webhooks passed to SDKs rather than net/http ---
+    const webhook = line.match(WEBHOOK_URL_RE);
+    if (webhook) {
+      const url = redactWebhookUrl(webhook[1]!);
+      const seen = entries.some(
+        (e) => e.kind === "api" && e.url === url && e.locations.some((l) => l.line === lineNum),
+      );
+      if (!seen) {
+        entries.push({
+          kind: "api",
+          url,
+          method: "POST",
+          locations: [{ file: rel, line: lineNum, context: trimmed }],
+          usage_count: 1,
+          confidence: "high",
+        });
+      }
+    }
+
+    // --- Error-tracking DSNs, reported with the key redacted ---
+    const dsn = line.match(DSN_RE);
+    if (dsn) {
+      entries.push({
+        kind: "api",
+        url: redactConnString(dsn[1]!),
+        locations: [{ file: rel, line: lineNum, context: trimmed }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+
+    // --- SMTP relay hosts ---
+    for (const pattern of SMTP_PATTERNS) {
+      const match = line.match(pattern);
+      if (!match) continue;
+      entries.push({
+        kind: "api",
+        url: `smtp://${match[1]!.toLowerCase()}${match[2] ? `:${match[2]}` : ""}`,
+        locations: [{ file: rel, line: lineNum, context: trimmed }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+
+    // --- Raw TCP/UDP dials to anything but loopback and private addresses ---
+    const dial = line.match(NET_DIAL_RE);
+    const dialHost = dial?.[2]!.replace(/:\d+$/, "").replace(/^\[|\]$/g, "");
+    if (dial && dialHost && dialHost !== "localhost" && !isPrivateIp(dialHost)) {
+      entries.push({
+        kind: "api",
+        url: `${dial[1]!}://${dial[2]!.toLowerCase()}`,
+        locations: [{ file: rel, line: lineNum, context: trimmed, usage: "net_dial" }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+
+    // --- WebSocket dials to external hosts ---
+    const ws = line.match(WEBSOCKET_DIAL_RE);
+    const wsHost = ws ? addressHost(ws[1]!) : null;
+    if (ws && wsHost && wsHost !== "localhost" && !isPrivateIp(wsHost)) {
+      entries.push({
+        kind: "api",
+        url: ws[1]!,
+        locations: [{ file: rel, line: lineNum, context: trimmed, usage: "websocket_dial" }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+
+    // --- FTP, SFTP, and SSH servers outside the private network ---
+    const ftp = line.match(FTP_DIAL_RE);
+    const ssh = line.match(SSH_DIAL_RE);
+    const transfer = ftp ? ["ftp", ftp[1]!] : ssh ? [usesSftp ? "sftp" : "ssh", ssh[1]!] : null;
+    const transferHost = transfer ? addressHost(transfer[1]!) : null;
+    if (transfer && transferHost && transferHost !== "localhost" && !isPrivateIp(transferHost)) {
+      entries.push({
+        kind: "api",
+        url: `${transfer[0]!}://${transfer[1]!.toLowerCase()}`,
+        locations: [{ file: rel, line: lineNum, context: trimmed }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+
     // --- SDK constructor detection (deduplicated by provider) ---
-    for (const [pattern, provider, sdkPackage] of SDK_CONSTRUCTORS) {
+    for (const [pattern, tableProvider, sdkPackage] of SDK_CONSTRUCTORS) {
       const match = line.match(pattern);
       if (!match) continue;
+      let provider = tableProvider;
 
-      // Gate GCP constructor detection on actual GCP import presence (P3 #9)
-      if (provider === "gcp") {
-        const isGcpImported = [...imports.values()].some((p) => p.startsWith("cloud.google.com/go"));
-        if (!isGcpImported) continue;
-      }
+      // Gate constructor detection on the service's import (P3 #9): storage.NewClient,
+      // tracer.Start, and github.NewClient are common names elsewhere
+      if (
+        (provider.startsWith("gcp-") || IMPORT_GATED_PROVIDERS.has(provider)) &&
+        !emittedSdkProviders.has(provider)
+      ) continue;
 
       // Resolve alias to canonical service name via imports map (P1 #2)
       let serviceName: string | undefined;
-      if ((provider === "aws" || provider === "gcp") && match[1]) {
+      if (provider === "aws" && match[1]) {
         const resolvedPath = imports.get(match[1]) ?? match[1];
         serviceName = resolvedPath.split("/").pop() ?? match[1];
+        provider = AWS_SERVICE_PROVIDERS[serviceName] ?? "aws";
       }
 
       // Enrich existing entry or create a new one (P1 #1)
       const existing = emittedSdkProviders.get(provider);
       if (existing && existing.kind === "sdk") {
-        if (serviceName) {
+        if (serviceName && !existing.services_used?.includes(serviceName)) {
           existing.services_used = [...(existing.services_used ?? []), serviceName];
         }
         existing.locations.push({ file: rel, line: lineNum, context: trimmed });
@@ -196,24 +1044,86 @@ export function analyzeGo(context: AnalyzerContext): DependencyEntry[] {
       if (!match) continue;
 
       // For sql.Open, the type comes from the match group
-      const resolvedType = infraType === "match_group" ? mapDriverType(match[1]!) : infraType;
+      let resolvedType = infraType === "match_group" ? mapDriverType(match[1]!) : infraType;
 
       // Try to extract connection string from the line (skip driver name for sql.Open)
       const allQuoted = [...line.matchAll(/["']([^"']+)["']/g)].map((m) => m[1]!);
       const envMatch = line.match(/os\.Getenv\(\s*["']([^"']+)["']\s*\)/);
-      // For sql.Open("driver", "connStr"), pick the second quoted string as connection ref
-      const connValue = allQuoted.length > 1 ? allQuoted[allQuoted.length - 1]! : allQuoted[0];
+      // For sql.Open("driver", "connStr"), pick the second quoted string as connection ref;
+      // nats.Connect(url, nats.UserCredentials("user.creds")) takes the URL first
+      // and gocql.NewCluster takes every contact point
+      const connValue =
+        resolvedType === "cassandra" && allQuoted.length > 0
+          ? allQuoted.join(",")
+          : allQuoted.length > 1 && resolvedType !== "nats"
+            ? allQuoted[allQuoted.length - 1]!
+            : allQuoted[0];
       const connectionRef = envMatch?.[1] ?? redactConnString(connValue ?? "unknown");
+      const dsnHost = !envMatch && connValue ? parseDsnHost(connValue) : null;
+      if (resolvedType === "postgresql" && REDSHIFT_HOST_RE.test(connectionRef)) resolvedType = "redshift";
+      // mongo.Connect(ctx, opts) with the URI applied on another line is reported there
+      if (
+        resolvedType === "mongodb" && !connValue && !envMatch &&
+        !/\.ApplyURI\(/.test(line) && /\.ApplyURI\(/.test(context.source)
+      ) continue;
 
       entries.push({
         kind: "infrastructure",
         type: resolvedType,
         connection_ref: connectionRef,
+        ...(dsnHost ? { resolved_host: dsnHost } : {}),
         locations: [{ file: rel, line: lineNum, context: trimmed }],
         confidence: envMatch ? "medium" : "high",
       });
     }
 
+    // --- Clients configured by struct literal: the address sits in the config
+    // or in a variable declared just above ---
+    for (const [pattern, infraType, addressRe] of CONFIG_INFRA_PATTERNS) {
+      if (!pattern.test(line)) continue;
+      const address = findConfigRef(lines, i, addressRe);
+      const host = address?.literal ? addressHost(address.ref) : null;
+      entries.push({
+        kind: "infrastructure",
+        type: infraType,
+        connection_ref: address ? redactConnString(address.ref) : "unknown",
+        ...(host ? { resolved_host: host } : {}),
+        locations: [{ file: rel, line: lineNum, context: trimmed }],
+        confidence: address && !address.literal ? "medium" : "high",
+      });
+      break;
+    }
+
+    // --- DataStax Astra: the cluster is always Astra's, wherever the bundle points ---
+    const astra = line.match(ASTRA_CLUSTER_RE);
+    if (astra) {
+      entries.push({
+        kind: "infrastructure",
+        type: "cassandra",
+        provider: "datastax-astra",
+        connection_ref: astra[2] ?? astra[1] ?? "unknown",
+        locations: [{ file: rel, line: lineNum, context: trimmed }],
+        confidence: astra[2] ? "medium" : "high",
+      });
+    }
+
+    // --- Vault, Consul, Temporal: the address is configured nearby or defaulted ---
+    const clientCall = importedClients.size > 0 ? line.match(IMPORTED_CLIENT_RE) : null;
+    const importedClient = clientCall ? importedClients.get(clientCall[1]!) : undefined;
+    if (importedClient) {
+      const [infraType, addressRe, defaultAddress] = importedClient;
+      const address = findConfigRef(lines, i, addressRe);
+      const host = address?.literal ? addressHost(address.ref) : null;
+      entries.push({
+        kind: "infrastructure",
+        type: infraType,
+        connection_ref: address ? redactConnString(address.ref) : defaultAddress,
+        ...(host ? { resolved_host: host } : {}),
+        locations: [{ file: rel, line: lineNum, context: trimmed }],
+        confidence: address?.literal ? "high" : "medium",
+      });
+    }
+
     // --- Connection string URL patterns ---
     for (const [pattern, infraType] of CONN_STRING_PATTERNS) {
       const connMatch = line.match(pattern);
@@ -237,9 +1147,184 @@ export function analyzeGo(context: AnalyzerContext): DependencyEntry[] {
     }
   }
 
+  const graph = emittedSdkProviders.get("microsoft-graph");
+  if (graph && graph.kind === "sdk") {
+    const services = new Set([...context.source.matchAll(GRAPH_SERVICE_RE)].map((m) => m[1]!.toLowerCase()));
+    if (services.size > 0) graph.services_used = [...services];
+  }
+
+  const openai = emittedSdkProviders.get("openai");
+  const baseUrl = context.source.match(OPENAI_BASE_URL_RE);
+  if (openai && openai.kind === "sdk" && baseUrl) {
+    openai.base_url = baseUrl[1] ?? `\${${baseUrl[2]!}}`;
+  }
+
+  for (const [pattern, provider] of SDK_BASE_URL_PATTERNS) {
+    const entry = emittedSdkProviders.get(provider);
+    const url = context.source.match(pattern);
+    if (entry && entry.kind === "sdk" && url) entry.base_url = url[1] ?? `\${${url[2]!}}`;
+  }
+
+  for (const [pattern, provider, url] of SDK_ENVIRONMENTS) {
+    const entry = emittedSdkProviders.get(provider);
+    if (entry && entry.kind === "sdk" && !entry.base_url && pattern.test(context.source)) entry.base_url = url;
+  }
+
+  const shopify = emittedSdkProviders.get("shopify");
+  const shop = context.source.match(SHOPIFY_SHOP_RE);
+  if (shopify && shopify.kind === "sdk" && shop) shopify.base_url = `https://${shop[1]!}.myshopify.com`;
+
+  const firebase = emittedSdkProviders.get("firebase");
+  if (firebase && firebase.kind === "sdk") {
+    const services = new Set(
+      [...context.source.matchAll(FIREBASE_SERVICE_RE)].map((m) =>
+        m[1]!.replace(/WithURL$/, "").replace(/(?<=[a-z])(?=[A-Z])/g, "_").toLowerCase(),
+      ),
+    );
+    if (services.size > 0) firebase.services_used = [...services];
+  }
+
+  for (const [pattern, provider, data] of DATA_EXPORT_MARKERS) {
+    const entry = emittedSdkProviders.get(provider);
+    if (!entry || entry.kind !== "sdk" || !pattern.test(context.source)) continue;
+    entry.data_exported = [...new Set([...(entry.data_exported ?? []), data])];
+  }
+
+  entries.push(...detectInboundWebhooks(lines, rel));
+
+  if (awsRegions.size > 0) {
+    for (const entry of entries) {
+      if (entry.kind === "sdk" && (entry.provider === "aws" || entry.provider.startsWith("aws-"))) {
+        entry.regions = [...awsRegions];
+      }
+    }
+  }
+
+  return entries;
+}
+
+/**
+ * Find a client's address (Kafka brokers, search cluster URLs, Vault) for a
+ * client created on `lineIndex`: first in the call and its config literal, then
+ * in the 15 lines above within the same function. `re` captures a literal in
+ * group 1 or an env var in group 2.
+ */
+function findConfigRef(
+  lines: string[],
+  lineIndex: number,
+  re: RegExp,
+): { ref: string; literal: boolean } | null {
+  const candidates: string[] = [];
+  let depth = 0;
+  for (let j = lineIndex; j < Math.min(lines.length, lineIndex + 20); j++) {
+    const line = lines[j]!;
+    candidates.push(line);
+    depth += (line.match(/[({]/g)?.length ?? 0) - (line.match(/[)}]/g)?.length ?? 0);
+    if (depth <= 0) break;
+  }
+  // Look back no further than the enclosing function
+  for (let j = lineIndex - 1; j >= Math.max(0, lineIndex - 15) && !/^func\s/.test(lines[j]!); j--) {
+    candidates.push(lines[j]!);
+  }
+  for (const line of candidates) {
+    const m = line.match(re);
+    if (m) return m[1] ? { ref: m[1], literal: true } : { ref: m[2]!, literal: false };
+  }
+  return null;
+}
+
+/**
+ * Inbound callback routes, one entry per route. The provider is the verifier
+ * named in the route ("/webhooks/stripe") or, failing that, the only one the
+ * file uses; core attributes the rest by path. Verifiers with no route in the
+ * file (the router lives elsewhere) are reported with an unknown path.
+ */
+function detectInboundWebhooks(lines: string[], rel: string): DependencyEntry[] {
+  const routes: { path: string; line: number; context: string }[] = [];
+  const verifiers = new Map<string, { line: number; context: string }>();
+  for (let i = 0; i < lines.length; i++) {
+    const trimmed = lines[i]!.trim();
+    if (trimmed.startsWith("//")) continue;
+    const route = trimmed.match(WEBHOOK_ROUTE_RE);
+    if (route && WEBHOOK_PATH_RE.test(route[1]!)) routes.push({ path: route[1]!, line: i + 1, context: trimmed });
+    for (const [pattern, provider] of WEBHOOK_VERIFIERS) {
+      if (!verifiers.has(provider) && pattern.test(trimmed)) verifiers.set(provider, { line: i + 1, context: trimmed });
+    }
+  }
+
+  const entries: DependencyEntry[] = [];
+  const claimed = new Set<string>();
+  for (const route of routes) {
+    const segments = route.path.toLowerCase().split(/[/_-]/);
+    const provider =
+      [...verifiers.keys()].find((p) => segments.includes(p)) ??
+      (verifiers.size === 1 ? [...verifiers.keys()][0]! : undefined);
+    const verifier = provider ? verifiers.get(provider) : undefined;
+    if (provider) claimed.add(provider);
+    entries.push({
+      kind: "webhook",
+      direction: "inbound_callback",
+      target_url: route.path,
+      ...(provider ? { provider } : {}),
+      locations: [
+        { file: rel, line: route.line, context: route.context },
+        ...(verifier && verifier.line !== route.line ? [{ file: rel, line: verifier.line, context: verifier.context }] : []),
+      ],
+      confidence: provider ? "high" : "medium",
+    });
+  }
+  for (const [provider, verifier] of verifiers) {
+    if (claimed.has(provider)) continue;
+    entries.push({
+      kind: "webhook",
+      direction: "inbound_callback",
+      target_url: "unknown",
+      provider,
+      locations: [{ file: rel, line: verifier.line, context: verifier.context }],
+      confidence: "medium",
+    });
+  }
   return entries;
 }
 
+/** First host of a configured address: "b-1:9092,b-2:9092", "https://es:9200", or an Elastic Cloud ID */
+function addressHost(ref: string): string | null {
+  const cloud = decodeCloudId(ref);
+  if (cloud) return cloud;
+  const first = ref.split(",")[0]!.trim();
+  return first.match(/^(?:[a-z][\w+.-]*:\/\/)?(?:[^@/]*@)?([\w-]+(?:\.[\w-]+)*)/i)?.[1] ?? null;
+}
+
+/**
+ * Host of a database/sql DSN that isn't URL-shaped (URLs are parsed downstream):
+ *   user:pass@tcp(db.example.com:3306)/app      — go-sql-driver/mysql
+ *   host=db.example.com port=5432 user=app      — lib/pq, pgx key/value
+ *   server=db.example.com;user id=app           — go-mssqldb
+ *   10.0.0.1,10.0.0.2                           — gocql contact points
+ */
+function parseDsnHost(dsn: string): string | null {
+  if (/^[a-z][\w+.-]*:\/\//i.test(dsn)) return null;
+  const host =
+    dsn.match(/@(?:tcp|tcp6)\(\[?([\w.-]+)/)?.[1] ??
+    dsn.match(/(?:^|[\s;])(?:host|server|data source)\s*=\s*(?:tcp:)?([\w.-]+)/i)?.[1] ??
+    dsn.match(/^([\w-]+(?:\.[\w-]+)+)(?::\d+)?(?:,|$)/)?.[1];
+  return host ?? null;
+}
+
+/**
+ * Elastic Cloud IDs are "<name>:base64(<domain>:<port>$<es-uuid>$<kibana-uuid>)";
+ * the cluster host is <es-uuid>.<domain>. Returns null for anything else.
+ */
+function decodeCloudId(ref: string): string | null {
+  const encoded = ref.match(/^[\w-]*:([A-Za-z0-9+/]+={0,2})$/)?.[1];
+  if (!encoded) return null;
+  const [domain, esUuid] = Buffer.from(encoded, "base64").toString("utf-8").split("$");
+  const hostname = domain?.replace(/:\d+$/, "");
+  return hostname && esUuid && /^[\w.-]+\.[a-z]{2,}$/.test(hostname) && /^\w+$/.test(esUuid)
+    ? `${esUuid}.${hostname}`
+    : null;
+}
+
 function mapDriverType(driver: string): string {
   switch (driver) {
     case "postgres":
@@ -249,12 +1334,22 @@ function mapDriverType(driver: string): string {
       return "mysql";
     case "sqlite3":
       return "sqlite";
+    case "mssql":
+      return "sqlserver";
     default:
       return driver;
   }
 }
 
-/** Redact credentials from connection string URLs (P1 #3) */
+/** Redact the token path of Slack / Teams incoming webhook URLs */
+function redactWebhookUrl(url: string): string {
+  return url.replace(WEBHOOK_TOKEN_RE, "$1/<redacted>");
+}
+
+/** Redact credentials from connection string URLs and DSNs (P1 #3) */
 function redactConnString(raw: string): string {
-  return raw.replace(/:\/\/[^@]+@/, "://<redacted>@");
+  return raw
+    .replace(/:\/\/[^@]+@/, "://<redacted>@")
+    .replace(/^[^@/\s]+@(?=(?:tcp6?|unix)\()/, "<redacted>@")
+    .replace(/\b(password|pwd)\s*=\s*[^\s;&]+/gi, "$1=<redacted>");
 }
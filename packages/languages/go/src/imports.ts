@@ -1,3 +1,15 @@
+/**
+ * Identifier an unnamed import is referenced by in this map: the last path
+ * element, skipping a major-version suffix ("github.com/x/sdk/v4" → "sdk",
+ * "gopkg.in/yaml.v3" → "yaml").
+ */
+export function defaultAlias(path: string): string {
+  const parts = path.split("/");
+  const last = parts.pop()!;
+  const element = /^v\d+$/.test(last) && parts.length > 1 ? parts.pop()! : last;
+  return element.replace(/\.v\d+$/, "");
+}
+
 /**
  * Parse Go import declarations and return a map of alias → full import path.
  */
@@ -8,8 +20,7 @@ export function detectImports(source: string): Map<string, string> {
   const singleRe = /import\s+"([^"]+)"/g;
   for (const m of source.matchAll(singleRe)) {
     const fullPath = m[1]!;
-    const alias = fullPath.split("/").pop()!;
-    imports.set(alias, fullPath);
+    imports.set(defaultAlias(fullPath), fullPath);
   }
 
   // Block import: import ( ... )
@@ -22,16 +33,16 @@ export function detectImports(source: string): Map<string, string> {
       // Named import: s3 "github.com/aws/aws-sdk-go-v2/service/s3"
       const named = trimmed.match(/^(\w+)\s+"([^"]+)"/);
       if (named) {
-        if (named[1] === "_") continue; // blank import — side-effect only
-        imports.set(named[1]!, named[2]!);
+        // Blank import (database drivers): no identifier to resolve, but the
+        // package is still a dependency
+        imports.set(named[1] === "_" ? `_${named[2]!}` : named[1]!, named[2]!);
         continue;
       }
       // Unnamed import: "github.com/stripe/stripe-go/v78"
       const unnamed = trimmed.match(/^"([^"]+)"/);
       if (unnamed) {
         const fullPath = unnamed[1]!;
-        const alias = fullPath.split("/").pop()!;
-        imports.set(alias, fullPath);
+        imports.set(defaultAlias(fullPath), fullPath);
       }
     }
   }
@@ -32,14 +32,20 @@ describe("JavaPlugin", () => {
 
     it("detects AWS S3 SDK via S3Client.builder()", () => {
       const awsSDK = entries.find(
-        (e) => e.kind === "sdk" && e.provider === "aws",
+        (e) => e.kind === "sdk" && e.provider === "aws-s3",
       );
       expect(awsSDK).toBeDefined();
       if (awsSDK && awsSDK.kind === "sdk") {
-        expect(awsSDK.services_used).toContain("s3");
+        expect(awsSDK.services_used).toEqual(["s3"]);
+        expect(awsSDK.regions).toEqual(["us-east-1"]);
       }
     });
 
+    it("does not report the regions import as a separate AWS dependency", () => {
+      const aws = entries.filter((e) => e.kind === "sdk" && e.provider.startsWith("aws"));
+      expect(aws).toHaveLength(1);
+    });
+
     it("detects Stripe SDK via Charge.create()", () => {
       const stripeSDK = entries.find(
         (e) => e.kind === "sdk" && e.provider === "stripe",
@@ -75,6 +81,7 @@ describe("JavaPlugin", () => {
       expect(kafka).toBeDefined();
       if (kafka && kafka.kind === "infrastructure") {
         expect(kafka.connection_ref).toBe("localhost:9092");
+        expect(kafka.resolved_host).toBe("localhost");
       }
     });
 
@@ -162,6 +169,19 @@ describe("JavaPlugin", () => {
     });
   });
 
+  describe("analyze — vat/CheckVatService.java", () => {
+    it("reports JAX-WS services by their WSDL location", async () => {
+      const filePath = resolve(fixturesRoot, "src/main/java/com/example/vat/CheckVatService.java");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({ filePath, source, scanRoot: fixturesRoot, resolvedEnv: {} });
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.method, e.locations[0]!.line]] : []));
+      expect(apis).toEqual([
+        ["https://ec.europa.eu/taxation_customs/vies/services/checkVatService", "POST", 12],
+        ["https://ws.freight-carrier.example/BookingService.svc", "POST", 22],
+      ]);
+    });
+  });
+
   describe("handles .kt files", () => {
     it("reports .kt in extensions", () => {
       expect(plugin.extensions).toContain(".kt");
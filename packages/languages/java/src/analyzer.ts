@@ -17,6 +17,9 @@ const HTTP_PATTERNS: [RegExp, string][] = [
   [/webClient\.(get|post|put|patch|delete)\(\)\.uri\(\s*"([^"]+)"/, "WEBCLIENT"],
   // @FeignClient(url = "https://...")
   [/@FeignClient\([^)]*url\s*=\s*"([^"]+)"/, "FEIGN"],
+  // JAX-WS: @WebServiceClient(wsdlLocation = "https://.../Service?wsdl"), new URL("...?wsdl")
+  [/\bwsdlLocation\s*=\s*"(https?:\/\/[^"]+)"/, "SOAP"],
+  [/new URL\(\s*"(https?:\/\/[^"]+\?wsdl)"/i, "SOAP"],
 ];
 
 // Annotation-based HTTP patterns (Retrofit only)
@@ -36,15 +39,28 @@ const ANNOTATION_HTTP_PATTERNS: [RegExp, string][] = [
 
 const SDK_PATTERNS: [RegExp, string, string][] = [
   // AWS: S3Client.builder(), SqsClient.create()
-  [/(S3|Sqs|Sns|DynamoDb|Lambda|Ses|Iam|Sts|Ec2|Ecs)Client\.(builder|create)\(/, "aws", "aws-sdk-java-v2"],
+  [
+    /(S3|Sqs|Sns|DynamoDb|Lambda|SesV2|Ses|SecretsManager|BedrockRuntime|Bedrock|Iam|Sts|Ec2|Ecs)Client\.(builder|create)\(/,
+    "aws",
+    "aws-sdk-java-v2",
+  ],
   // Stripe: Charge.create(), PaymentIntent.create()
   [/(?:Charge|PaymentIntent|Customer|Subscription|Invoice|Refund)\.create\(/, "stripe", "stripe-java"],
   // Firebase: FirebaseApp.initializeApp()
   [/FirebaseApp\.initializeApp\(/, "firebase", "firebase-admin-java"],
   // Twilio: Twilio.init()
   [/Twilio\.init\(/, "twilio", "twilio-java"],
+  // Vonage: VonageClient.builder()
+  [/VonageClient\.builder\(/, "vonage", "vonage-java-sdk"],
+  // Error tracking: Sentry.init(...), new Bugsnag("key"), Rollbar.init(...)
+  [/\bSentry\.init\(/, "sentry", "sentry-java"],
+  [/new\s+Bugsnag\(/, "bugsnag", "bugsnag-java"],
+  [/\bRollbar\.init\(/, "rollbar", "rollbar-java"],
   // SendGrid: new SendGrid()
   [/new\s+SendGrid\(/, "sendgrid", "sendgrid-java"],
+  // LLM providers: AnthropicOkHttpClient.fromEnv(), Cohere.builder()
+  [/AnthropicOkHttpClient\.(?:builder|fromEnv)\(/, "anthropic", "anthropic-java"],
+  [/\bCohere\.builder\(/, "cohere", "cohere-java"],
 ];
 
 // ---------------------------------------------------------------------------
@@ -69,21 +85,123 @@ const JDBC_URL_PATTERNS: [RegExp, string][] = [
   [/jdbc:oracle:[^\s"']+/, "oracle"],
   [/jdbc:sqlserver:[^\s"']+/, "sqlserver"],
   [/jdbc:h2:[^\s"']+/, "h2"],
+  [/jdbc:snowflake:\/\/[^\s"']+/, "snowflake"],
+  [/jdbc:redshift(?::iam)?:\/\/[^\s"']+/, "redshift"],
 ];
 
 // Connection string URL patterns
 const CONN_STRING_PATTERNS: [RegExp, string][] = [
   [/mongodb(\+srv)?:\/\/[^\s"']+/, "mongodb"],
-  [/redis:\/\/[^\s"']+/, "redis"],
+  [/rediss?:\/\/[^\s"']+/, "redis"],
 ];
 
+// AWS service clients with their own catalog entry; other services stay "aws"
+const AWS_SERVICE_PROVIDERS: Record<string, string> = {
+  s3: "aws-s3",
+  dynamodb: "aws-dynamodb",
+  sqs: "aws-sqs",
+  sns: "aws-sns",
+  ses: "aws-ses",
+  sesv2: "aws-ses",
+  lambda: "aws-lambda",
+  secretsmanager: "aws-secrets-manager",
+  bedrock: "aws-bedrock",
+  bedrockruntime: "aws-bedrock",
+  bedrockagent: "aws-bedrock",
+  bedrockagentruntime: "aws-bedrock",
+  redshift: "aws-redshift",
+  redshiftdata: "aws-redshift",
+  redshiftserverless: "aws-redshift",
+  kafka: "aws-msk",
+  rds: "aws-rds",
+  elasticache: "aws-elasticache",
+  sfn: "aws-step-functions",
+  opensearch: "aws-opensearch",
+  opensearchserverless: "aws-opensearch",
+  elasticsearch: "aws-opensearch",
+};
+
+// software.amazon.awssdk.services.s3.S3Client → s3
+const AWS_SERVICE_IMPORT_RE = /^software\.amazon\.awssdk\.services\.(\w+)\./;
+
+// .region(Region.US_EAST_1) or .region(Region.of("eu-west-1"))
+const AWS_REGION_RE = /Region\.(?:of\(\s*"([a-z]{2}(?:-gov)?-[a-z]+-\d)"|([A-Z]{2}(?:_GOV)?_[A-Z]+_\d)\b)/g;
+
+function awsRegions(text: string): string[] {
+  const regions = new Set<string>();
+  for (const m of text.matchAll(AWS_REGION_RE)) {
+    regions.add(m[1] ?? m[2]!.toLowerCase().replace(/_/g, "-"));
+  }
+  return [...regions];
+}
+
 // SDK import patterns → [provider, sdk_package]
 const SDK_IMPORT_PREFIXES: Record<string, [string, string]> = {
   "software.amazon.awssdk": ["aws", "aws-sdk-java-v2"],
   "com.stripe": ["stripe", "stripe-java"],
+  "com.paypal": ["paypal", "checkout-sdk"],
+  "com.braintreegateway": ["braintree", "braintree-java"],
+  "com.adyen": ["adyen", "adyen-java-api-library"],
+  "com.squareup.square": ["square", "square"],
+  "com.plaid.client": ["plaid", "plaid-java"],
+  "com.truelayer.java": ["truelayer", "truelayer-java"],
+  "com.chargebee": ["chargebee", "chargebee-java"],
+  "com.recurly.v3": ["recurly", "recurly-api-client"],
+  "net.avalara.avatax": ["avalara", "avatax-rest-client"],
+  "com.taxjar": ["taxjar", "taxjar-java"],
+  "com.amazon.SellingPartnerAPIAA": ["amazon-selling-partner", "sellingpartner-api-aa-java"],
+  "com.onfido": ["onfido", "onfido-api-java"],
+  "com.easypost": ["easypost", "easypost-api-client"],
+  "com.shippo": ["shippo", "shippo-java-client"],
+  "com.google.maps": ["google-maps", "google-maps-services"],
+  "com.mapbox.api": ["mapbox", "mapbox-sdk-services"],
+  "com.maxmind.geoip2.WebServiceClient": ["maxmind", "geoip2"],
+  "io.ipinfo.api": ["ipinfo", "ipinfo-api"],
+  "com.google.cloud.recaptchaenterprise": ["recaptcha", "google-cloud-recaptchaenterprise"],
+  "com.eatthepath.pushy.apns": ["apns", "pushy"],
+  "com.onesignal.client": ["onesignal", "onesignal-java-client"],
+  "com.fastly.api": ["fastly", "fastly-api"],
+  "com.akamai.edgegrid": ["akamai", "edgegrid-signer"],
+  "io.minio": ["minio", "minio"],
+  "com.cloudinary": ["cloudinary", "cloudinary-http5"],
+  "com.imgix": ["imgix", "imgix-java"],
+  "com.mux": ["mux", "mux-sdk-java"],
+  "com.transloadit.sdk": ["transloadit", "transloadit"],
+  "com.docusign.esign": ["docusign", "docusign-esign-java"],
+  "com.dropbox.sign": ["hellosign", "dropbox-sign"],
+  "com.microsoft.graph": ["microsoft-graph", "microsoft-graph"],
+  "com.google.api.services.calendar": ["google-workspace", "google-api-services-calendar"],
+  "com.google.api.services.drive": ["google-workspace", "google-api-services-drive"],
+  "com.google.api.services.gmail": ["google-workspace", "google-api-services-gmail"],
+  "com.google.api.services.sheets": ["google-workspace", "google-api-services-sheets"],
   "com.google.firebase": ["firebase", "firebase-admin-java"],
   "com.twilio": ["twilio", "twilio-java"],
+  "com.vonage": ["vonage", "vonage-java-sdk"],
+  "org.kohsuke.github": ["github", "github-api"],
+  "org.gitlab4j": ["gitlab", "gitlab4j-api"],
+  "io.sentry": ["sentry", "sentry-java"],
+  "com.bugsnag": ["bugsnag", "bugsnag-java"],
+  "com.rollbar": ["rollbar", "rollbar-java"],
   "com.sendgrid": ["sendgrid", "sendgrid-java"],
+  "com.anthropic": ["anthropic", "anthropic-java"],
+  "com.cohere": ["cohere", "cohere-java"],
+  "com.google.genai": ["gemini", "google-genai"],
+  "net.snowflake.client": ["snowflake", "snowflake-jdbc"],
+  "com.amazon.redshift": ["aws-redshift", "redshift-jdbc42"],
+  "org.springframework.vault": ["hashicorp-vault", "spring-vault-core"],
+  "io.github.jopenlibs.vault": ["hashicorp-vault", "vault-java-driver"],
+  "com.bettercloud.vault": ["hashicorp-vault", "vault-java-driver"],
+  "org.kiwiproject.consul": ["hashicorp-consul", "consul-client"],
+  "com.ecwid.consul": ["hashicorp-consul", "consul-api"],
+  "io.temporal": ["temporal", "temporal-sdk"],
+  "com.launchdarkly.sdk": ["launchdarkly", "launchdarkly-java-server-sdk"],
+  "io.split.client": ["split", "split-java-client"],
+  "io.getunleash": ["unleash", "unleash-client-java"],
+  "com.flagsmith": ["flagsmith", "flagsmith-java-client"],
+  "com.segment.analytics": ["segment", "analytics-java"],
+  "com.rudderstack.sdk.java.analytics": ["rudderstack", "rudder-sdk-java"],
+  "com.mixpanel.mixpanelapi": ["mixpanel", "mixpanel-java"],
+  "com.amplitude": ["amplitude", "amplitude-java"],
 };
 
 // ---------------------------------------------------------------------------
@@ -117,6 +235,33 @@ export function analyzeJava(context: AnalyzerContext): DependencyEntry[] {
     const importMatch = trimmed.match(/^import\s+(?:static\s+)?([a-zA-Z0-9_.]+)\s*;/);
     if (importMatch) {
       const importPath = importMatch[1]!;
+
+      // AWS service clients: one provider per service with its own catalog entry
+      const awsService = importPath.match(AWS_SERVICE_IMPORT_RE);
+      if (awsService) {
+        const service = awsService[1]!;
+        const provider = AWS_SERVICE_PROVIDERS[service] ?? "aws";
+        const existing = emittedSdkProviders.get(provider);
+        if (existing && existing.kind === "sdk") {
+          if (!existing.services_used?.includes(service)) {
+            existing.services_used = [...(existing.services_used ?? []), service];
+          }
+        } else {
+          const entry: DependencyEntry = {
+            kind: "sdk",
+            provider,
+            sdk_package: "aws-sdk-java-v2",
+            services_used: [service],
+            locations: [{ file: rel, line: i + 1, context: trimmed }],
+            usage_count: 1,
+            confidence: "high",
+          };
+          emittedSdkProviders.set(provider, entry);
+          entries.push(entry);
+        }
+        continue;
+      }
+
       for (const [prefix, [provider, sdkPackage]] of Object.entries(SDK_IMPORT_PREFIXES)) {
         if (importPath.startsWith(prefix)) {
           if (!emittedSdkProviders.has(provider)) {
@@ -137,6 +282,18 @@ export function analyzeJava(context: AnalyzerContext): DependencyEntry[] {
     }
   }
 
+  // software.amazon.awssdk.regions.Region alone adds nothing once a service client is reported
+  const awsCore = emittedSdkProviders.get("aws");
+  if (
+    awsCore &&
+    awsCore.kind === "sdk" &&
+    !awsCore.services_used &&
+    [...emittedSdkProviders.keys()].some((p) => p.startsWith("aws-"))
+  ) {
+    emittedSdkProviders.delete("aws");
+    entries.splice(entries.indexOf(awsCore), 1);
+  }
+
   inBlockComment = false;
   for (let i = 0; i < lines.length; i++) {
     const line = lines[i]!;
@@ -174,6 +331,10 @@ export function analyzeJava(context: AnalyzerContext): DependencyEntry[] {
       } else if (kind === "FEIGN") {
         url = match[1] ?? "unknown";
         method = "GET"; // Feign clients define methods separately
+      } else if (kind === "SOAP") {
+        // Operations are POSTed to the service; the ?wsdl suffix only fetches its contract
+        url = match[1]!.replace(/\?wsdl$/i, "");
+        method = "POST";
       } else {
         url = match[1] ?? "unknown";
       }
@@ -184,7 +345,7 @@ export function analyzeJava(context: AnalyzerContext): DependencyEntry[] {
         kind: "api",
         url,
         method: method as "GET" | "POST" | "PUT" | "PATCH" | "DELETE" | "HEAD",
-        locations: [{ file: rel, line: lineNum, context: trimmed }],
+        locations: [{ file: rel, line: lineNum, context: trimmed, ...(kind === "SOAP" ? { usage: "soap_client" } : {}) }],
         usage_count: 1,
         confidence,
       });
@@ -209,21 +370,29 @@ export function analyzeJava(context: AnalyzerContext): DependencyEntry[] {
     }
 
     // --- SDK constructor detection (deduplicated by provider) ---
-    for (const [pattern, provider, sdkPackage] of SDK_PATTERNS) {
+    for (const [pattern, tableProvider, sdkPackage] of SDK_PATTERNS) {
       const match = line.match(pattern);
       if (!match) continue;
+      let provider = tableProvider;
 
       let serviceName: string | undefined;
+      let regions: string[] = [];
       if (provider === "aws" && match[1]) {
         // Extract service from class name: S3Client → s3, DynamoDbClient → dynamodb
         serviceName = match[1].toLowerCase().replace(/client$/, "");
+        provider = AWS_SERVICE_PROVIDERS[serviceName] ?? "aws";
+        // Builder chains usually span a few lines: S3Client.builder()\n.region(...)
+        regions = awsRegions(lines.slice(i, i + 5).join(" "));
       }
 
       const existing = emittedSdkProviders.get(provider);
       if (existing && existing.kind === "sdk") {
-        if (serviceName) {
+        if (serviceName && !existing.services_used?.includes(serviceName)) {
           existing.services_used = [...(existing.services_used ?? []), serviceName];
         }
+        if (regions.length > 0) {
+          existing.regions = [...new Set([...(existing.regions ?? []), ...regions])];
+        }
         existing.locations.push({ file: rel, line: lineNum, context: trimmed });
       } else {
         const entry: DependencyEntry = {
@@ -231,6 +400,7 @@ export function analyzeJava(context: AnalyzerContext): DependencyEntry[] {
           provider,
           sdk_package: sdkPackage,
           ...(serviceName ? { services_used: [serviceName] } : {}),
+          ...(regions.length > 0 ? { regions } : {}),
           api_methods: [trimmed.slice(0, 80)],
           locations: [{ file: rel, line: lineNum, context: trimmed }],
           usage_count: 1,
@@ -275,10 +445,13 @@ export function analyzeJava(context: AnalyzerContext): DependencyEntry[] {
       } else if (infraType === "kafka") {
         // Kafka brokers are configured via Properties, not inline — scan nearby lines
         const bootstrapServers = findKafkaBootstrapServers(lines, i);
+        // The first broker identifies the cluster (Confluent Cloud, MSK, Aiven, ...)
+        const host = bootstrapServers === "unknown" ? undefined : bootstrapServers.split(",")[0]!.trim().replace(/:\d+$/, "");
         entries.push({
           kind: "infrastructure",
           type: infraType,
           connection_ref: bootstrapServers,
+          ...(host ? { resolved_host: host } : {}),
           locations: [{ file: rel, line: lineNum, context: trimmed }],
           confidence: "high",
         });
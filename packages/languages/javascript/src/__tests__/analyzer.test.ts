@@ -88,10 +88,30 @@ describe("JavaScriptPlugin", () => {
       );
       expect(openai).toBeDefined();
 
-      const aws = sdks.filter(
-        (e) => e.kind === "sdk" && e.provider === "aws",
+      const s3 = sdks.find((e) => e.kind === "sdk" && e.provider === "aws-s3");
+      expect(s3?.kind === "sdk" && s3.sdk_package).toBe("@aws-sdk/client-s3");
+      expect(s3?.kind === "sdk" && s3.regions).toEqual(["us-east-1"]);
+
+      const sqs = sdks.find((e) => e.kind === "sdk" && e.provider === "aws-sqs");
+      expect(sqs).toBeDefined();
+    });
+
+    it("records the endpoint of S3 clients pointed at other storage in objects.ts", async () => {
+      const filePath = resolve(fixturesRoot, "src/objects.ts");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+        registryMaps,
+      });
+
+      const clients = entries.flatMap((e) =>
+        e.kind === "sdk" && e.provider === "aws-s3" ? [[e.locations[0]!.line, e.base_url]] : [],
       );
-      expect(aws.length).toBeGreaterThanOrEqual(2); // S3Client + SQSClient
+      expect(clients).toContainEqual([5, "https://8d5c1a2f9e0b4c7d.r2.cloudflarestorage.com"]);
+      expect(clients).toContainEqual([11, "${SPACES_ENDPOINT}"]);
     });
 
     it("detects infrastructure connections in sdk-usage.ts", async () => {
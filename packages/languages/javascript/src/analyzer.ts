@@ -21,6 +21,15 @@ const INFRA_CONSTRUCTORS: Record<string, string> = {
   MongoClient: "mongodb",
 };
 
+// AWS clients take their region in the constructor: new S3Client({ region: "us-east-1" })
+const AWS_REGION_RE = /region:\s*["']([a-z]{2}(?:-gov)?-[a-z]+-\d)["']/;
+
+// OpenAI-compatible gateways: new OpenAI({ baseURL: "http://localhost:8000/v1" })
+const OPENAI_BASE_URL_RE = /\bbaseURL:\s*(?:["'`]([^"'`]+)["'`]|process\.env\.(\w+)|process\.env\[["'](\w+)["']\])/;
+
+// S3-compatible storage: new S3Client({ endpoint: "https://<account>.r2.cloudflarestorage.com" })
+const S3_ENDPOINT_RE = /\bendpoint:\s*(?:["'`]([^"'`]+)["'`]|process\.env\.(\w+)|process\.env\[["'](\w+)["']\])/;
+
 const VALID_HTTP_METHODS = new Set([
   "GET",
   "POST",
@@ -180,10 +189,18 @@ export function analyzeJavaScript(context: AnalyzerContext): DependencyEntry[] {
       const ctorName = newMatch[1]!;
       const sdk = sdkConstructors.get(ctorName);
       if (sdk) {
+        // The config object can span a few lines; stop at the end of the call
+        const call = [line.slice(newMatch.index), ...lines.slice(i + 1, i + 5)].join(" ").split(")")[0]!;
+        const region = sdk[0] === "aws" || sdk[0].startsWith("aws-") ? call.match(AWS_REGION_RE)?.[1] : undefined;
+        const baseUrl =
+          sdk[0] === "openai" ? call.match(OPENAI_BASE_URL_RE) : sdk[0] === "aws-s3" ? call.match(S3_ENDPOINT_RE) : null;
+        const baseUrlEnv = baseUrl?.[2] ?? baseUrl?.[3];
         entries.push({
           kind: "sdk",
           provider: sdk[0],
           sdk_package: sdk[1],
+          ...(region ? { regions: [region] } : {}),
+          ...(baseUrl ? { base_url: baseUrl[1] ?? `\${${baseUrlEnv!}}` } : {}),
           locations: [
             {
               file: rel,
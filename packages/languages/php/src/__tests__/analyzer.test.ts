@@ -100,11 +100,13 @@ describe("PhpPlugin", () => {
       expect(stripe).toBeDefined();
     });
 
-    it("detects AWS SDK", () => {
-      const aws = entries.find(
-        (e) => e.kind === "sdk" && e.provider === "aws",
-      );
-      expect(aws).toBeDefined();
+    it("detects AWS SDK per service, with the client's region", () => {
+      const aws = entries.filter((e) => e.kind === "sdk" && e.provider.startsWith("aws"));
+      expect(aws).toHaveLength(1);
+      const s3 = aws[0]!;
+      expect(s3.kind === "sdk" && s3.provider).toBe("aws-s3");
+      expect(s3.kind === "sdk" && s3.services_used).toEqual(["s3"]);
+      expect(s3.kind === "sdk" && s3.regions).toEqual(["us-east-1"]);
     });
 
     it("detects Twilio SDK from use import", () => {
@@ -219,6 +221,23 @@ echo 'hello';`;
       expect(sdks).toHaveLength(0);
     });
 
+    it("detects SOAP services by their WSDL URL", async () => {
+      const source = `<?php
+$vies = new \\SoapClient('https://ec.europa.eu/taxation_customs/vies/services/checkVatService?wsdl');
+$freight = new SoapClient("https://ws.freight-carrier.example/BookingService.svc?WSDL", ['trace' => 1]);`;
+      const result = await plugin.analyze({
+        filePath: resolve(fixturesRoot, "app.php"),
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+      });
+      const apis = result.flatMap((e) => (e.kind === "api" ? [[e.url, e.method, e.locations[0]!.usage]] : []));
+      expect(apis).toEqual([
+        ["https://ec.europa.eu/taxation_customs/vies/services/checkVatService", "POST", "soap_client"],
+        ["https://ws.freight-carrier.example/BookingService.svc", "POST", "soap_client"],
+      ]);
+    });
+
     it("detects SDK from grouped use imports", async () => {
       const source = `<?php
 use Stripe\\{Charge, PaymentIntent};
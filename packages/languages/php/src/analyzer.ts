@@ -63,13 +63,82 @@ function detectUseImports(source: string): Map<string, { fullPath: string; line:
 // Pre-computed for performance (avoid Object.entries in hot loop)
 const SDK_IMPORT_PREFIX_ENTRIES = Object.entries({
   Stripe: ["stripe", "stripe/stripe-php"],
+  PayPalCheckoutSdk: ["paypal", "paypal/paypal-checkout-sdk"],
+  Braintree: ["braintree", "braintree/braintree_php"],
+  Adyen: ["adyen", "adyen/php-api-library"],
+  Square: ["square", "square/square"],
+  ChargeBee: ["chargebee", "chargebee/chargebee-php"],
+  Recurly: ["recurly", "recurly/recurly-client"],
+  Avalara: ["avalara", "avalara/avataxclient"],
+  TaxJar: ["taxjar", "taxjar/taxjar-php"],
+  Shopify: ["shopify", "shopify/shopify-api"],
+  "Automattic\\WooCommerce": ["woocommerce", "automattic/woocommerce"],
+  "Bigcommerce\\Api": ["bigcommerce", "bigcommerce/api"],
+  Onfido: ["onfido", "onfido/onfido-php"],
+  EasyPost: ["easypost", "easypost/easypost-php"],
+  Shippo: ["shippo", "shippo/shippo-php"],
+  "GeoIp2\\WebService": ["maxmind", "geoip2/geoip2"],
+  "ipinfo\\ipinfo": ["ipinfo", "ipinfo/ipinfo"],
+  ReCaptcha: ["recaptcha", "google/recaptcha"],
+  Pushok: ["apns", "edamov/pushok"],
+  "onesignal\\client": ["onesignal", "onesignal/onesignal-php-api"],
+  ExpoSDK: ["expo", "ctwillie/expo-server-sdk-php"],
+  Fastly: ["fastly", "fastly/fastly"],
+  "Akamai\\Open\\EdgeGrid": ["akamai", "akamai-open/edgegrid-client"],
+  Cloudinary: ["cloudinary", "cloudinary/cloudinary_php"],
+  Imgix: ["imgix", "imgix/imgix-php"],
+  MuxPhp: ["mux", "muxinc/mux-php"],
+  transloadit: ["transloadit", "transloadit/php-sdk"],
+  "DocuSign\\eSign": ["docusign", "docusign/esign-client"],
+  "Dropbox\\Sign": ["hellosign", "dropbox/sign"],
   Aws: ["aws", "aws/aws-sdk-php"],
   Twilio: ["twilio", "twilio/sdk"],
+  Vonage: ["vonage", "vonage/client"],
   "Kreait\\Firebase": ["firebase", "kreait/firebase-php"],
   SendGrid: ["sendgrid", "sendgrid/sendgrid"],
   Sentry: ["sentry", "sentry/sentry"],
+  Github: ["github", "knplabs/github-api"],
+  Gitlab: ["gitlab", "m4tthumphrey/php-gitlab-api"],
+  Bugsnag: ["bugsnag", "bugsnag/bugsnag"],
+  Rollbar: ["rollbar", "rollbar/rollbar"],
+  LaunchDarkly: ["launchdarkly", "launchdarkly/server-sdk"],
+  "Unleash\\Client": ["unleash", "unleash/client"],
+  Flagsmith: ["flagsmith", "flagsmith/flagsmith-php-client"],
+  Segment: ["segment", "segmentio/analytics-php"],
 } as Record<string, [string, string]>);
 
+// AWS service clients with their own catalog entry; other services stay "aws"
+const AWS_SERVICE_PROVIDERS: Record<string, string> = {
+  s3: "aws-s3",
+  dynamodb: "aws-dynamodb",
+  sqs: "aws-sqs",
+  sns: "aws-sns",
+  ses: "aws-ses",
+  sesv2: "aws-ses",
+  lambda: "aws-lambda",
+  secretsmanager: "aws-secrets-manager",
+  bedrock: "aws-bedrock",
+  bedrockruntime: "aws-bedrock",
+  bedrockagent: "aws-bedrock",
+  bedrockagentruntime: "aws-bedrock",
+  redshift: "aws-redshift",
+  redshiftdata: "aws-redshift",
+  redshiftserverless: "aws-redshift",
+  kafka: "aws-msk",
+  rds: "aws-rds",
+  elasticache: "aws-elasticache",
+  sfn: "aws-step-functions",
+  opensearchservice: "aws-opensearch",
+  opensearchserverless: "aws-opensearch",
+  elasticsearchservice: "aws-opensearch",
+};
+
+// Aws\S3\S3Client → s3
+const AWS_SERVICE_NAMESPACE_RE = /^Aws\\(\w+)\\/;
+
+// new S3Client(['region' => 'eu-west-1', ...])
+const AWS_REGION_RE = /['"]region['"]\s*=>\s*['"]([a-z]{2}(?:-gov)?-[a-z]+-\d)['"]/;
+
 // ---------------------------------------------------------------------------
 // HTTP client patterns: [regex, kind tag]
 // ---------------------------------------------------------------------------
@@ -89,6 +158,8 @@ const HTTP_PATTERNS: [RegExp, string][] = [
   [/Http::(get|post|put|patch|delete)\(\s*['"]([^'"]+)['"]/, "LARAVEL_HTTP"],
   // base_uri / base_url config
   [/['"]base_uri['"]\s*=>\s*['"]([^'"]+)['"]/, "BASE_URI"],
+  // new SoapClient('https://partner.example.com/Service.svc?wsdl')
+  [/new\s+\\?SoapClient\(\s*['"](https?:\/\/[^'"]+)['"]/, "SOAP"],
 ];
 
 // ---------------------------------------------------------------------------
@@ -99,15 +170,20 @@ const SDK_PATTERNS: [RegExp, string, string][] = [
   // Stripe: \Stripe\Stripe::setApiKey, \Stripe\Charge::create, etc.
   [/\\?Stripe\\(?:Stripe::setApiKey|Charge|PaymentIntent|Customer|Subscription|Invoice)/, "stripe", "stripe/stripe-php"],
   // AWS: new \Aws\S3\S3Client, new \Aws\Sqs\SqsClient, etc.
-  [/new\s+\\?Aws\\\w+\\\w+Client/, "aws", "aws/aws-sdk-php"],
+  [/new\s+\\?Aws\\(\w+)\\\w+Client/, "aws", "aws/aws-sdk-php"],
   // Twilio: new \Twilio\Rest\Client
   [/\\?Twilio\\Rest\\Client/, "twilio", "twilio/sdk"],
+  // Vonage: new \Vonage\Client(
+  [/new\s+\\?Vonage\\Client\(/, "vonage", "vonage/client"],
   // Firebase: \Kreait\Firebase\Factory
   [/\\?Kreait\\Firebase\\Factory/, "firebase", "kreait/firebase-php"],
   // SendGrid: new \SendGrid(
   [/new\s+\\?SendGrid\(/, "sendgrid", "sendgrid/sendgrid"],
   // Sentry: \Sentry\init
   [/\\?Sentry\\init\(/, "sentry", "sentry/sentry"],
+  // Bugsnag: \Bugsnag\Client::make('key'), Rollbar: \Rollbar\Rollbar::init([...])
+  [/\\?Bugsnag\\Client::make\(/, "bugsnag", "bugsnag/bugsnag"],
+  [/\\?Rollbar\\Rollbar::init\(/, "rollbar", "rollbar/rollbar"],
 ];
 
 // ---------------------------------------------------------------------------
@@ -137,7 +213,7 @@ const CONN_STRING_PATTERNS: [RegExp, string][] = [
   [/mysql:\/\/[^\s"']+/, "mysql"],
   [/pgsql:\/\/[^\s"']+/, "postgresql"],
   [/mongodb(\+srv)?:\/\/[^\s"'`]+/, "mongodb"],
-  [/redis:\/\/[^\s"']+/, "redis"],
+  [/rediss?:\/\/[^\s"']+/, "redis"],
 ];
 
 // Pre-compiled for first quoted-string extraction (avoid per-line object alloc)
@@ -163,6 +239,32 @@ export function analyzePhp(context: AnalyzerContext): DependencyEntry[] {
 
   // Detect SDK from `use` imports
   for (const [, { fullPath, line: importLine }] of imports) {
+    // AWS service clients: one provider per service with its own catalog entry
+    const awsService = fullPath.match(AWS_SERVICE_NAMESPACE_RE);
+    if (awsService) {
+      const service = awsService[1]!.toLowerCase();
+      const provider = AWS_SERVICE_PROVIDERS[service] ?? "aws";
+      const existing = emittedSdkProviders.get(provider);
+      if (existing && existing.kind === "sdk") {
+        if (!existing.services_used?.includes(service)) {
+          existing.services_used = [...(existing.services_used ?? []), service];
+        }
+      } else {
+        const entry: DependencyEntry = {
+          kind: "sdk",
+          provider,
+          sdk_package: "aws/aws-sdk-php",
+          services_used: [service],
+          locations: [{ file: rel, line: importLine, context: `use ${fullPath}` }],
+          usage_count: 1,
+          confidence: "high",
+        };
+        emittedSdkProviders.set(provider, entry);
+        entries.push(entry);
+      }
+      continue;
+    }
+
     for (const [prefix, [provider, sdkPackage]] of SDK_IMPORT_PREFIX_ENTRIES) {
       if (fullPath === prefix || fullPath.startsWith(prefix + "\\")) {
         if (!emittedSdkProviders.has(provider)) {
@@ -218,6 +320,10 @@ export function analyzePhp(context: AnalyzerContext): DependencyEntry[] {
         url = match[1] ?? "unknown";
       } else if (kind === "BASE_URI") {
         url = match[1] ?? "unknown";
+      } else if (kind === "SOAP") {
+        // Operations are POSTed to the service; the ?wsdl suffix only fetches its contract
+        method = "POST";
+        url = match[1]!.replace(/\?wsdl$/i, "");
       } else if (kind === "GUZZLE" || kind === "LARAVEL_HTTP") {
         method = match[1]!.toUpperCase();
         url = match[2] ?? "unknown";
@@ -232,19 +338,35 @@ export function analyzePhp(context: AnalyzerContext): DependencyEntry[] {
         kind: "api",
         url,
         method: method as "GET" | "POST" | "PUT" | "PATCH" | "DELETE" | "HEAD",
-        locations: [{ file: rel, line: lineNum, context: trimmed }],
+        locations: [{ file: rel, line: lineNum, context: trimmed, ...(kind === "SOAP" ? { usage: "soap_client" } : {}) }],
         usage_count: 1,
         confidence,
       });
     }
 
     // --- SDK detection (deduplicated by provider) ---
-    for (const [pattern, provider, sdkPackage] of SDK_PATTERNS) {
+    for (const [pattern, tableProvider, sdkPackage] of SDK_PATTERNS) {
       const match = line.match(pattern);
       if (!match) continue;
+      let provider = tableProvider;
+
+      let serviceName: string | undefined;
+      let region: string | undefined;
+      if (provider === "aws" && match[1]) {
+        serviceName = match[1].toLowerCase();
+        provider = AWS_SERVICE_PROVIDERS[serviceName] ?? "aws";
+        // The client config array often spans the next few lines
+        region = lines.slice(i, i + 5).join(" ").match(AWS_REGION_RE)?.[1];
+      }
 
       const existing = emittedSdkProviders.get(provider);
       if (existing && existing.kind === "sdk") {
+        if (serviceName && !existing.services_used?.includes(serviceName)) {
+          existing.services_used = [...(existing.services_used ?? []), serviceName];
+        }
+        if (region && !existing.regions?.includes(region)) {
+          existing.regions = [...(existing.regions ?? []), region];
+        }
         existing.locations.push({ file: rel, line: lineNum, context: trimmed });
         existing.usage_count++;
       } else {
@@ -252,6 +374,8 @@ export function analyzePhp(context: AnalyzerContext): DependencyEntry[] {
           kind: "sdk",
           provider,
           sdk_package: sdkPackage,
+          ...(serviceName ? { services_used: [serviceName] } : {}),
+          ...(region ? { regions: [region] } : {}),
           api_methods: [trimmed.slice(0, 80)],
           locations: [{ file: rel, line: lineNum, context: trimmed }],
           usage_count: 1,
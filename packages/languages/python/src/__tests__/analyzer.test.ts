@@ -123,14 +123,59 @@ describe("PythonPlugin", () => {
       });
 
       const sdks = entries.filter((e) => e.kind === "sdk");
-      const awsSDK = sdks.filter((e) => e.kind === "sdk" && e.provider === "aws");
+      const awsSDK = sdks.filter((e) => e.kind === "sdk" && e.provider.startsWith("aws"));
       expect(awsSDK.length).toBeGreaterThanOrEqual(2);
 
       const s3 = awsSDK.find((e) => e.kind === "sdk" && e.services_used?.includes("s3"));
       expect(s3).toBeDefined();
+      expect(s3?.kind === "sdk" && s3.provider).toBe("aws-s3");
+      expect(s3?.kind === "sdk" && s3.regions).toEqual(["us-east-1"]);
 
       const sqs = awsSDK.find((e) => e.kind === "sdk" && e.services_used?.includes("sqs"));
-      expect(sqs).toBeDefined();
+      expect(sqs?.kind === "sdk" && sqs.provider).toBe("aws-sqs");
+
+      const dynamodb = awsSDK.find((e) => e.kind === "sdk" && e.services_used?.includes("dynamodb"));
+      expect(dynamodb?.kind === "sdk" && dynamodb.provider).toBe("aws-dynamodb");
+      expect(dynamodb?.kind === "sdk" && dynamodb.regions).toBeUndefined();
+    });
+
+    it("records the endpoint of S3 clients pointed at other storage in objects.py", async () => {
+      const filePath = resolve(fixturesRoot, "infra/objects.py");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+        registryMaps,
+      });
+
+      const clients = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations[0]!.line, e.base_url]] : [],
+      );
+      expect(clients).toEqual([
+        ["aws-s3", 7, "https://8d5c1a2f9e0b4c7d.r2.cloudflarestorage.com"],
+        ["aws-s3", 12, "${BACKUP_S3_ENDPOINT}"],
+        ["aws-s3", 15, undefined],
+      ]);
+    });
+
+    it("reports SOAP services by their WSDL URL in vat.py", async () => {
+      const filePath = resolve(fixturesRoot, "infra/vat.py");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+        registryMaps,
+      });
+
+      const apis = entries.flatMap((e) => (e.kind === "api" ? [[e.url, e.method, e.locations[0]!.line]] : []));
+      expect(apis).toEqual([
+        ["https://ec.europa.eu/taxation_customs/vies/services/checkVatService", "POST", 6],
+        ["https://ws.freight-carrier.example/BookingService.svc", "POST", 7],
+      ]);
     });
 
     it("detects redis infrastructure in storage.py", async () => {
@@ -191,5 +236,150 @@ describe("PythonPlugin", () => {
       );
       expect(redisUrl).toBeDefined();
     });
+
+    it("detects warehouse clients and connections in warehouse.py", async () => {
+      const filePath = resolve(fixturesRoot, "infra/warehouse.py");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+        registryMaps,
+      });
+
+      const providers = entries.flatMap((e) => (e.kind === "sdk" ? [e.provider] : []));
+      expect(providers).toEqual(expect.arrayContaining(["snowflake", "aws-redshift", "gcp-bigquery"]));
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.type, e.resolved_host, e.locations[0]!.line]] : [],
+      );
+      expect(infra).toEqual([
+        ["snowflake", "acme-analytics.snowflakecomputing.com", 10],
+        ["redshift", "analytics.abc123xyz.us-east-1.redshift.amazonaws.com", 14],
+      ]);
+    });
+
+    it("detects RabbitMQ and NATS connections in messaging.py", async () => {
+      const filePath = resolve(fixturesRoot, "infra/messaging.py");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+        registryMaps,
+      });
+
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.type, e.connection_ref, e.locations[0]!.line]] : [],
+      );
+      expect(infra).toEqual([
+        ["rabbitmq", "CLOUDAMQP_URL", 8],
+        ["nats", "tls://connect.ngs.global", 13],
+      ]);
+    });
+
+    it("detects redis.from_url and managed Redis hosts in cache.py", async () => {
+      const filePath = resolve(fixturesRoot, "infra/cache.py");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+        registryMaps,
+      });
+
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.type, e.resolved_host ?? e.connection_ref, e.locations[0]!.line]] : [],
+      );
+      expect(infra).toEqual([
+        ["redis", "UPSTASH_REDIS_URL", 5],
+        ["redis", "master.sessions.abc123.use1.cache.amazonaws.com", 6],
+      ]);
+    });
+
+    it("detects Vault and Consul clients in secrets.py", async () => {
+      const filePath = resolve(fixturesRoot, "infra/secrets.py");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+        registryMaps,
+      });
+
+      const infra = entries.flatMap((e) =>
+        e.kind === "infrastructure" ? [[e.type, e.resolved_host ?? e.connection_ref, e.locations[0]!.line]] : [],
+      );
+      expect(infra).toEqual([
+        ["vault", "vault.platform.internal", 6],
+        ["consul", "consul.service.internal", 7],
+      ]);
+      const providers = entries.flatMap((e) => (e.kind === "sdk" ? [e.provider] : []));
+      expect(providers).toEqual(expect.arrayContaining(["hashicorp-vault", "hashicorp-consul"]));
+    });
+
+    it("detects Hugging Face Hub downloads in models.py, skipping local paths", async () => {
+      const filePath = resolve(fixturesRoot, "ai/models.py");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+        registryMaps,
+      });
+
+      const downloads = entries.flatMap((e) =>
+        e.kind === "api" && e.locations[0]?.usage === "model_download" ? [[e.url, e.locations[0].line]] : [],
+      );
+      expect(downloads).toEqual([
+        ["https://huggingface.co/distilbert/distilbert-base-uncased-finetuned-sst-2-english", 12],
+        ["https://huggingface.co/dslim/bert-base-NER", 14],
+        ["https://huggingface.co/sentence-transformers/all-MiniLM-L6-v2", 15],
+        ["https://huggingface.co/stabilityai/sdxl-turbo", 16],
+      ]);
+    });
+
+    it("detects the Hugging Face inference client and Replicate calls in models.py", async () => {
+      const filePath = resolve(fixturesRoot, "ai/models.py");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+        registryMaps,
+      });
+
+      const sdkLines = (provider: string) =>
+        entries.filter((e) => e.kind === "sdk" && e.provider === provider).map((e) => e.locations[0]!.line);
+      expect(sdkLines("huggingface")).toEqual([18]);
+      expect(sdkLines("replicate")).toEqual([26]);
+    });
+
+    it("records base URLs of OpenAI clients in gateway.py", async () => {
+      const filePath = resolve(fixturesRoot, "ai/gateway.py");
+      const source = await readFile(filePath, "utf-8");
+      const entries = await plugin.analyze({
+        filePath,
+        source,
+        scanRoot: fixturesRoot,
+        resolvedEnv: {},
+        registryMaps,
+      });
+
+      const clients = entries.flatMap((e) =>
+        e.kind === "sdk" ? [[e.provider, e.locations[0]!.line, e.base_url]] : [],
+      );
+      expect(clients).toEqual([
+        ["openai", 7, "https://api.groq.com/openai/v1"],
+        ["openai", 11, "${LLM_GATEWAY_URL}"],
+        ["azure-openai", 12, undefined],
+        ["openai", 13, undefined],
+      ]);
+    });
   });
 });
@@ -14,20 +14,81 @@ const INFRA_FUNCS: Record<string, string> = {
   "pymongo.MongoClient": "mongodb",
   "redis.Redis": "redis",
   "redis.StrictRedis": "redis",
+  "redis.from_url": "redis",
   "elasticsearch.Elasticsearch": "elasticsearch",
   "create_engine": "postgresql",
+  "snowflake.connector.connect": "snowflake",
+  "redshift_connector.connect": "redshift",
+  "pika.BlockingConnection": "rabbitmq",
+  "nats.connect": "nats",
+  "hvac.Client": "vault",
+  "consul.Consul": "consul",
 };
 
+// Redshift speaks the Postgres protocol; its hosts tell it apart
+const REDSHIFT_HOST_RE = /\.redshift(?:-serverless)?\.amazonaws\.com\b/;
+
 // Connection string URL patterns
 const CONN_STRING_PATTERNS: [RegExp, string][] = [
-  [/postgresql:\/\/[^\s"']+/, "postgresql"],
-  [/postgres:\/\/[^\s"']+/, "postgresql"],
+  [/(?:postgres(?:ql)?|redshift)(?:\+\w+)?:\/\/[^\s"']+\.redshift(?:-serverless)?\.amazonaws\.com[^\s"']*/, "redshift"],
+  [/postgresql:\/\/(?![^\s"']*\.redshift)[^\s"']+/, "postgresql"],
+  [/postgres:\/\/(?![^\s"']*\.redshift)[^\s"']+/, "postgresql"],
+  [/snowflake:\/\/[^\s"']+/, "snowflake"],
   [/mysql:\/\/[^\s"']+/, "mysql"],
   [/mongodb(\+srv)?:\/\/[^\s"']+/, "mongodb"],
-  [/redis:\/\/[^\s"']+/, "redis"],
-  [/amqp:\/\/[^\s"']+/, "rabbitmq"],
+  [/rediss?:\/\/[^\s"']+/, "redis"],
+  [/amqps?:\/\/[^\s"']+/, "rabbitmq"],
+  [/nats:\/\/[^\s"',]+/, "nats"],
 ];
 
+// AWS service clients with their own catalog entry; other services stay "aws"
+const AWS_SERVICE_PROVIDERS: Record<string, string> = {
+  s3: "aws-s3",
+  dynamodb: "aws-dynamodb",
+  sqs: "aws-sqs",
+  sns: "aws-sns",
+  ses: "aws-ses",
+  sesv2: "aws-ses",
+  lambda: "aws-lambda",
+  secretsmanager: "aws-secrets-manager",
+  bedrock: "aws-bedrock",
+  "bedrock-runtime": "aws-bedrock",
+  "bedrock-agent": "aws-bedrock",
+  "bedrock-agent-runtime": "aws-bedrock",
+  redshift: "aws-redshift",
+  "redshift-data": "aws-redshift",
+  "redshift-serverless": "aws-redshift",
+  kafka: "aws-msk",
+  rds: "aws-rds",
+  elasticache: "aws-elasticache",
+  stepfunctions: "aws-step-functions",
+  opensearch: "aws-opensearch",
+  opensearchserverless: "aws-opensearch",
+  es: "aws-opensearch",
+};
+
+// boto3.client("s3", region_name="eu-west-1")
+const AWS_REGION_RE = /region_name\s*=\s*["']([a-z]{2}(?:-gov)?-[a-z]+-\d)["']/;
+
+// OpenAI(base_url="http://localhost:8000/v1"), OpenAI(base_url=os.environ["LLM_BASE_URL"])
+const OPENAI_BASE_URL_RE =
+  /\bbase_url\s*=\s*(?:["']([^"']+)["']|os\.(?:environ\[\s*["'](\w+)["']\s*\]|environ\.get\(\s*["'](\w+)["']|getenv\(\s*["'](\w+)["']))/;
+
+// S3-compatible storage: boto3.client("s3", endpoint_url="https://<account>.r2.cloudflarestorage.com")
+const S3_ENDPOINT_RE =
+  /\bendpoint_url\s*=\s*(?:["']([^"']+)["']|os\.(?:environ\[\s*["'](\w+)["']\s*\]|environ\.get\(\s*["'](\w+)["']|getenv\(\s*["'](\w+)["']))/;
+
+// Hugging Face Hub downloads at runtime: AutoModel.from_pretrained("org/model"),
+// pipeline("ner", model="org/model"), hf_hub_download(repo_id="org/model", filename=...)
+const HF_DOWNLOAD_RE =
+  /\b(?:\w+\.from_pretrained|snapshot_download|hf_hub_download|SentenceTransformer|pipeline)\(\s*(?:["'][\w-]+["']\s*,\s*)?(?:(?:repo_id|model|model_name_or_path|pretrained_model_name_or_path)\s*=\s*)?["']([A-Za-z0-9][\w.-]*\/[\w.-]*[\w-])["']/;
+
+// SOAP clients in files that import zeep or suds, reported as a POST of the service
+// URL: zeep.Client("https://partner.example.com/Orders.svc?wsdl"), Client(wsdl=url)
+const SOAP_IMPORT_RE = /^\s*(?:from|import)\s+(?:zeep|suds)\b/m;
+const SOAP_CLIENT_RE = /\b(?:zeep\.)?(?:Client|AsyncClient|CachingClient)\(/;
+const SOAP_WSDL_ARG_RE = /^\(\s*(?:(?:wsdl|url)\s*=\s*)?["'](https?:\/\/[^"']+)["']/;
+
 export function analyzePython(context: AnalyzerContext): DependencyEntry[] {
   const sdkProviders = context.registryMaps?.packageProviders ?? new Map<string, string>();
   const sdkConstructors = context.registryMaps?.constructorProviders ?? new Map<string, [string, string]>();
@@ -36,6 +97,7 @@ export function analyzePython(context: AnalyzerContext): DependencyEntry[] {
   const rel = relative(context.scanRoot, context.filePath);
 
   const sdkDetectedOnLine = new Set<string>();
+  const usesSoap = SOAP_IMPORT_RE.test(context.source);
 
   for (let i = 0; i < lines.length; i++) {
     const line = lines[i]!;
@@ -79,20 +141,48 @@ export function analyzePython(context: AnalyzerContext): DependencyEntry[] {
       }
     }
 
+    // Detect SOAP services; operations are POSTed, the ?wsdl suffix only fetches the contract.
+    // The WSDL argument often starts on the next line: zeep.Client(\n    wsdl="...",
+    const soapCall = usesSoap ? line.match(SOAP_CLIENT_RE) : null;
+    const soapArgs = soapCall
+      ? [line.slice(soapCall.index! + soapCall[0].length - 1), ...lines.slice(i + 1, i + 3)].join(" ")
+      : "";
+    const soap = soapArgs.match(SOAP_WSDL_ARG_RE);
+    if (soap) {
+      entries.push({
+        kind: "api",
+        url: soap[1]!.replace(/\?wsdl$/i, ""),
+        method: "POST",
+        locations: [{ file: rel, line: lineNum, context: line.trim(), usage: "soap_client" }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+
     // Detect SDK usage: boto3.client("s3"), stripe.Charge.create(...), etc.
     for (const [module, provider] of sdkProviders) {
       // Match: boto3.client("s3"), boto3.resource("dynamodb")
       if (module === "boto3") {
         const boto3Match = line.match(/(?:^|[^\w])boto3\.(client|resource)\(\s*["']([^"']+)["']/);
         if (boto3Match) {
-          const sdkKey = `${provider}:${lineNum}`;
+          const service = boto3Match[2]!;
+          const serviceProvider = AWS_SERVICE_PROVIDERS[service] ?? provider;
+          const sdkKey = `${serviceProvider}:${lineNum}`;
           if (!sdkDetectedOnLine.has(sdkKey)) {
             sdkDetectedOnLine.add(sdkKey);
+            // Keyword arguments often continue on the next lines
+            const call = [line.slice(boto3Match.index), ...lines.slice(i + 1, i + 5)].join(" ");
+            const args = call.split(")")[0]!;
+            const region = args.match(AWS_REGION_RE)?.[1];
+            const endpoint = serviceProvider === "aws-s3" ? args.match(S3_ENDPOINT_RE) : null;
+            const endpointEnv = endpoint?.[2] ?? endpoint?.[3] ?? endpoint?.[4];
             entries.push({
               kind: "sdk",
-              provider,
+              provider: serviceProvider,
               sdk_package: module,
-              services_used: [boto3Match[2]!],
+              services_used: [service],
+              ...(region ? { regions: [region] } : {}),
+              ...(endpoint ? { base_url: endpoint[1] ?? `\${${endpointEnv!}}` } : {}),
               api_methods: [`boto3.${boto3Match[1]}("${boto3Match[2]}")`],
               locations: [{ file: rel, line: lineNum, context: line.trim() }],
               usage_count: 1,
@@ -124,8 +214,8 @@ export function analyzePython(context: AnalyzerContext): DependencyEntry[] {
       }
     }
 
-    // Detect SDK from constructor calls: OpenAI(...), TwilioClient(...)
-    const constructorMatch = line.match(/(?:^|\s|=)\s*(\w+)\s*\(/);
+    // Detect SDK from constructor calls: OpenAI(...), TwilioClient(...), genai.Client(...)
+    const constructorMatch = line.match(/(?:^|\s|=)\s*([\w.]+)\s*\(/);
     if (constructorMatch) {
       const ctorName = constructorMatch[1]!;
       const ctorMapping = sdkConstructors.get(ctorName);
@@ -133,10 +223,15 @@ export function analyzePython(context: AnalyzerContext): DependencyEntry[] {
         const sdkKey = `${ctorMapping[0]}:${lineNum}`;
         if (!sdkDetectedOnLine.has(sdkKey)) {
           sdkDetectedOnLine.add(sdkKey);
+          // OpenAI-compatible gateways: keyword arguments often continue on the next lines
+          const call = [line.slice(constructorMatch.index), ...lines.slice(i + 1, i + 5)].join(" ").split(")")[0]!;
+          const baseUrl = ctorMapping[0] === "openai" ? call.match(OPENAI_BASE_URL_RE) : null;
+          const baseUrlEnv = baseUrl?.[2] ?? baseUrl?.[3] ?? baseUrl?.[4];
           entries.push({
             kind: "sdk",
             provider: ctorMapping[0],
             sdk_package: ctorMapping[1],
+            ...(baseUrl ? { base_url: baseUrl[1] ?? `\${${baseUrlEnv!}}` } : {}),
             locations: [{ file: rel, line: lineNum, context: line.trim(), usage: `constructor:${ctorName}` }],
             usage_count: 1,
             confidence: "high",
@@ -145,29 +240,50 @@ export function analyzePython(context: AnalyzerContext): DependencyEntry[] {
       }
     }
 
-    // Detect infrastructure: psycopg2.connect(...), redis.Redis(...), etc.
     const trimmedLine = line.trim();
     if (trimmedLine.startsWith("#")) continue;
+
+    // Detect model downloads from the Hugging Face Hub
+    const hfMatch = line.match(HF_DOWNLOAD_RE);
+    if (hfMatch) {
+      entries.push({
+        kind: "api",
+        url: `https://huggingface.co/${hfMatch[1]!}`,
+        method: "GET",
+        locations: [{ file: rel, line: lineNum, context: trimmedLine, usage: "model_download" }],
+        usage_count: 1,
+        confidence: "high",
+      });
+    }
+
+    // Detect infrastructure: psycopg2.connect(...), redis.Redis(...), etc.
     for (const [func, infraType] of Object.entries(INFRA_FUNCS)) {
       const escapedFunc = func.replace(/\./g, "\\.");
       const infraRegex = new RegExp(`\\b${escapedFunc}\\b`);
       if (infraRegex.test(line)) {
-        const envMatch = line.match(/os\.environ\[["']([^"']+)["']\]|os\.environ\.get\(["']([^"']+)["']\)/);
+        // An explicit url= wins over other arguments: hvac.Client(url="https://vault:8200", token=os.environ[...])
+        const urlArg = line.match(/\burl\s*=\s*["']([a-z][\w+.-]*:\/\/[^"']+)["']/);
+        const envMatch = urlArg ? null : line.match(/os\.environ\[["']([^"']+)["']\]|os\.environ\.get\(["']([^"']+)["']\)/);
         const connRefMatch = line.match(/["']([^"']+)["']/);
-        const connection_ref = envMatch?.[1] ?? envMatch?.[2] ?? connRefMatch?.[1] ?? "unknown";
+        const connection_ref = urlArg?.[1] ?? envMatch?.[1] ?? envMatch?.[2] ?? connRefMatch?.[1] ?? "unknown";
 
         // Try to extract host from Redis-style calls: redis.Redis(host="cache.internal")
-        const hostMatch = line.match(/host\s*=\s*["']([^"']+)["']/);
+        const hostMatch =
+          line.match(/host\s*=\s*["']([^"']+)["']/) ?? urlArg?.[1]!.match(/:\/\/(?:[^@/]*@)?([^/:?#]+)/) ?? null;
+        // Snowflake connects by account identifier: account="acme-analytics"
+        const accountMatch = infraType === "snowflake" ? line.match(/account\s*=\s*["']([\w.-]+)["']/) : null;
 
         const entry: DependencyEntry = {
           kind: "infrastructure",
-          type: infraType,
+          type: infraType === "postgresql" && REDSHIFT_HOST_RE.test(connection_ref) ? "redshift" : infraType,
           connection_ref,
           locations: [{ file: rel, line: lineNum, context: line.trim() }],
           confidence: envMatch ? "medium" : "high",
         };
         if (hostMatch) {
           entry.resolved_host = hostMatch[1]!;
+        } else if (accountMatch) {
+          entry.resolved_host = `${accountMatch[1]!}.snowflakecomputing.com`;
         }
         entries.push(entry);
       }
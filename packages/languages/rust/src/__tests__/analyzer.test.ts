@@ -39,11 +39,19 @@ describe("RustPlugin", () => {
 
     it("detects AWS SDK via aws_sdk_s3::Client::new", () => {
       const awsSDK = entries.find(
-        (e) => e.kind === "sdk" && e.provider === "aws" && e.services_used?.includes("s3"),
+        (e) => e.kind === "sdk" && e.provider === "aws-s3" && e.services_used?.includes("s3"),
       );
       expect(awsSDK).toBeDefined();
     });
 
+    it("reports AWS per service crate, with the region from the shared config", () => {
+      const aws = entries.filter((e) => e.kind === "sdk" && e.provider.startsWith("aws"));
+      expect(aws).toHaveLength(1);
+      const s3 = aws[0]!;
+      expect(s3.kind === "sdk" && s3.sdk_package).toBe("aws-sdk-s3");
+      expect(s3.kind === "sdk" && s3.regions).toEqual(["eu-west-1"]);
+    });
+
     it("detects Stripe SDK via stripe::Charge", () => {
       const stripeSDK = entries.find(
         (e) => e.kind === "sdk" && e.provider === "stripe",
@@ -91,8 +99,8 @@ describe("RustPlugin", () => {
     });
 
     it("handles use ... as aliasing", () => {
-      // S3Client alias maps to aws_sdk_s3::Client, should trigger aws SDK
-      const awsSDK = entries.find((e) => e.kind === "sdk" && e.provider === "aws");
+      // S3Client alias maps to aws_sdk_s3::Client, should trigger the S3 SDK
+      const awsSDK = entries.find((e) => e.kind === "sdk" && e.provider === "aws-s3");
       expect(awsSDK).toBeDefined();
     });
 
@@ -12,8 +12,47 @@ const SDK_CRATE_PREFIXES: Record<string, [string, string]> = {
   aws_config: ["aws", "aws-sdk-rust"],
   stripe: ["stripe", "stripe-rust"],
   async_openai: ["openai", "async-openai"],
+  hf_hub: ["huggingface", "hf-hub"],
+  vaultrs: ["hashicorp-vault", "vaultrs"],
+  launchdarkly_server_sdk: ["launchdarkly", "launchdarkly-server-sdk"],
+  unleash_api_client: ["unleash", "unleash-api-client"],
+  flagsmith: ["flagsmith", "flagsmith"],
+  segment: ["segment", "segment"],
+  rudderanalytics: ["rudderstack", "rudderanalytics"],
 };
 
+// ---------------------------------------------------------------------------
+// AWS service clients with their own catalog entry; other services stay "aws"
+// ---------------------------------------------------------------------------
+
+const AWS_SERVICE_PROVIDERS: Record<string, string> = {
+  s3: "aws-s3",
+  dynamodb: "aws-dynamodb",
+  sqs: "aws-sqs",
+  sns: "aws-sns",
+  ses: "aws-ses",
+  sesv2: "aws-ses",
+  lambda: "aws-lambda",
+  secretsmanager: "aws-secrets-manager",
+  bedrock: "aws-bedrock",
+  bedrockruntime: "aws-bedrock",
+  bedrockagent: "aws-bedrock",
+  bedrockagentruntime: "aws-bedrock",
+  redshift: "aws-redshift",
+  redshiftdata: "aws-redshift",
+  redshiftserverless: "aws-redshift",
+  kafka: "aws-msk",
+  rds: "aws-rds",
+  elasticache: "aws-elasticache",
+  sfn: "aws-step-functions",
+  opensearch: "aws-opensearch",
+  opensearchserverless: "aws-opensearch",
+  elasticsearch: "aws-opensearch",
+};
+
+// Region::new("eu-west-1"), .region("us-east-1")
+const AWS_REGION_RE = /"((?:us|eu|ap|sa|ca|me|af|il|mx|cn)(?:-gov)?-[a-z]+-\d)"/g;
+
 // ---------------------------------------------------------------------------
 // HTTP patterns: [regex, kind tag]
 // ---------------------------------------------------------------------------
@@ -36,14 +75,16 @@ const HTTP_PATTERNS: [RegExp, string][] = [
 const SDK_CONSTRUCTORS: [RegExp, string, string][] = [
   // AWS: aws_sdk_s3::Client::new, aws_sdk_sqs::Client::new
   [/aws_sdk_(\w+)::Client::new\(/, "aws", "aws-sdk-rust"],
-  // aws_config::load_defaults
-  [/aws_config::load_defaults\(/, "aws", "aws-sdk-rust"],
+  // aws_config::load_defaults, aws_config::defaults, aws_config::from_env
+  [/aws_config::(?:load_defaults|defaults|from_env)\(/, "aws", "aws-sdk-rust"],
   // stripe::Client::new
   [/stripe::Client::new\(/, "stripe", "stripe-rust"],
   // stripe resource operations: stripe::Charge::create, etc.
   [/stripe::(Charge|PaymentIntent|Customer|Subscription)/, "stripe", "stripe-rust"],
   // async_openai::Client::new
   [/async_openai::Client::new\(/, "openai", "async-openai"],
+  // hf_hub::api::sync::Api::new(), hf_hub::api::tokio::ApiBuilder::new()
+  [/hf_hub::api::(?:sync|tokio)::Api(?:Builder)?::new\(/, "huggingface", "hf-hub"],
 ];
 
 // ---------------------------------------------------------------------------
@@ -69,7 +110,7 @@ const CONN_STRING_PATTERNS: [RegExp, string][] = [
   [/postgres:\/\/[^\s"']+/, "postgresql"],
   [/mysql:\/\/[^\s"']+/, "mysql"],
   [/mongodb(\+srv)?:\/\/[^\s"'`]+/, "mongodb"],
-  [/redis:\/\/[^\s"']+/, "redis"],
+  [/rediss?:\/\/[^\s"']+/, "redis"],
   [/amqp:\/\/[^\s"']+/, "rabbitmq"],
 ];
 
@@ -109,6 +150,33 @@ export function analyzeRust(context: AnalyzerContext): DependencyEntry[] {
   // Detect SDK entries from use statements (deduplicated by provider)
   for (const [, usePath] of imports) {
     const crateRoot = usePath.split("::")[0]!;
+
+    // AWS service crates: one provider per service with its own catalog entry
+    const awsService = crateRoot.match(/^aws_sdk_(\w+)$/);
+    if (awsService) {
+      const service = awsService[1]!;
+      const provider = AWS_SERVICE_PROVIDERS[service] ?? "aws";
+      const existing = emittedSdkProviders.get(provider);
+      if (existing && existing.kind === "sdk") {
+        if (!existing.services_used?.includes(service)) {
+          existing.services_used = [...(existing.services_used ?? []), service];
+        }
+      } else {
+        const entry: DependencyEntry = {
+          kind: "sdk",
+          provider,
+          sdk_package: `aws-sdk-${service}`,
+          services_used: [service],
+          locations: [{ file: rel, line: 1, context: `use ${usePath}` }],
+          usage_count: 1,
+          confidence: "high",
+        };
+        emittedSdkProviders.set(provider, entry);
+        entries.push(entry);
+      }
+      continue;
+    }
+
     for (const [prefix, [provider, sdkPackage]] of Object.entries(SDK_CRATE_PREFIXES)) {
       if (crateRoot === prefix || crateRoot.startsWith(prefix + "_")) {
         if (!emittedSdkProviders.has(provider)) {
@@ -130,6 +198,9 @@ export function analyzeRust(context: AnalyzerContext): DependencyEntry[] {
 
   let inBlockComment = false;
 
+  // Clients share one SdkConfig, so a region literal anywhere applies to all of them
+  const awsRegions = new Set<string>();
+
   for (let i = 0; i < lines.length; i++) {
     const line = lines[i]!;
     const lineNum = i + 1;
@@ -148,6 +219,8 @@ export function analyzeRust(context: AnalyzerContext): DependencyEntry[] {
       continue;
     }
 
+    for (const m of line.matchAll(AWS_REGION_RE)) awsRegions.add(m[1]!);
+
     // --- HTTP detection ---
     for (const [pattern, kind] of HTTP_PATTERNS) {
       const match = line.match(pattern);
@@ -187,20 +260,24 @@ export function analyzeRust(context: AnalyzerContext): DependencyEntry[] {
     }
 
     // --- SDK constructor detection (deduplicated by provider) ---
-    for (const [pattern, provider, sdkPackage] of SDK_CONSTRUCTORS) {
+    for (const [pattern, tableProvider, tablePackage] of SDK_CONSTRUCTORS) {
       const match = line.match(pattern);
       if (!match) continue;
+      let provider = tableProvider;
+      let sdkPackage = tablePackage;
 
       // Resolve service name for AWS (e.g., aws_sdk_s3 → s3)
       let serviceName: string | undefined;
       if (provider === "aws" && match[1] && pattern.source.includes("aws_sdk_")) {
         serviceName = match[1];
+        provider = AWS_SERVICE_PROVIDERS[serviceName] ?? "aws";
+        sdkPackage = `aws-sdk-${serviceName}`;
       }
 
       // Enrich existing entry or create a new one
       const existing = emittedSdkProviders.get(provider);
       if (existing && existing.kind === "sdk") {
-        if (serviceName) {
+        if (serviceName && !existing.services_used?.includes(serviceName)) {
           existing.services_used = [...(existing.services_used ?? []), serviceName];
         }
         existing.locations.push({ file: rel, line: lineNum, context: trimmed });
@@ -232,11 +309,17 @@ export function analyzeRust(context: AnalyzerContext): DependencyEntry[] {
       const connValue =
         infraType === "kafka" ? findRustKafkaBootstrapServers(lines, i) : (allQuoted[0] ?? "unknown");
       const connectionRef = envMatch?.[1] ?? redactConnString(connValue);
+      // The first broker identifies the cluster (Confluent Cloud, MSK, Aiven, ...)
+      const kafkaHost =
+        infraType === "kafka" && !envMatch && connValue !== "unknown"
+          ? connValue.split(",")[0]!.trim().replace(/:\d+$/, "")
+          : undefined;
 
       entries.push({
         kind: "infrastructure",
         type: infraType,
         connection_ref: connectionRef,
+        ...(kafkaHost ? { resolved_host: kafkaHost } : {}),
         locations: [{ file: rel, line: lineNum, context: trimmed }],
         confidence: envMatch ? "medium" : "high",
       });
@@ -265,6 +348,25 @@ export function analyzeRust(context: AnalyzerContext): DependencyEntry[] {
     }
   }
 
+  // use aws_config / load_defaults alone adds nothing once a service client is reported
+  const awsCore = emittedSdkProviders.get("aws");
+  if (
+    awsCore &&
+    awsCore.kind === "sdk" &&
+    !awsCore.services_used &&
+    entries.some((e) => e.kind === "sdk" && e.provider.startsWith("aws-"))
+  ) {
+    entries.splice(entries.indexOf(awsCore), 1);
+  }
+
+  if (awsRegions.size > 0) {
+    for (const entry of entries) {
+      if (entry.kind === "sdk" && (entry.provider === "aws" || entry.provider.startsWith("aws-"))) {
+        entry.regions = [...awsRegions];
+      }
+    }
+  }
+
   return entries;
 }
 
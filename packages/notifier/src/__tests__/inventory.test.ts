@@ -0,0 +1,150 @@
+import { describe, it, expect } from "vitest";
+import { createServer } from "node:http";
+import type { IncomingMessage, ServerResponse } from "node:http";
+import type { AddressInfo } from "node:net";
+import { renderConfluenceStorage } from "../inventory.js";
+import type { InventoryPage } from "../inventory.js";
+import { publishToConfluence } from "../adapters/confluence.js";
+import { publishToNotion } from "../adapters/notion.js";
+
+const page: InventoryPage = {
+  organization: "acme",
+  generated_at: "2026-10-14T10:00:00.000Z",
+  total_repositories: 2,
+  vendors: [
+    {
+      vendor: "stripe",
+      display_name: "Stripe",
+      category: "payments",
+      repositories: ["acme/billing", "acme/checkout"],
+      total_usages: 12,
+      data_classification: ["pci"],
+      approval: "approved",
+      compliance_url: "https://stripe.com/legal/dpa",
+    },
+    { vendor: "acme-<internal>", display_name: "Acme <Internal>", repositories: ["acme/checkout"], total_usages: 1, approval: "unreviewed" },
+  ],
+};
+
+interface Recorded {
+  method: string;
+  url: string;
+  headers: IncomingMessage["headers"];
+  body: any;
+}
+
+/** A local HTTP server answering with `respond`; returns its origin and the requests it saw */
+async function mockServer(respond: (req: Recorded, res: ServerResponse) => void) {
+  const requests: Recorded[] = [];
+  const server = createServer((req, res) => {
+    let data = "";
+    req.on("data", (chunk) => (data += chunk));
+    req.on("end", () => {
+      const recorded = { method: req.method!, url: req.url!, headers: req.headers, body: data ? JSON.parse(data) : undefined };
+      requests.push(recorded);
+      res.setHeader("Content-Type", "application/json");
+      respond(recorded, res);
+    });
+  });
+  await new Promise<void>((r) => server.listen(0, "127.0.0.1", r));
+  const { port } = server.address() as AddressInfo;
+  return { origin: `http://127.0.0.1:${port}`, requests, close: () => server.close() };
+}
+
+describe("renderConfluenceStorage", () => {
+  it("renders one escaped table row per vendor", () => {
+    const html = renderConfluenceStorage(page);
+    expect(html).toContain("latest scan of 2 repositories");
+    expect(html).toContain("<strong>2</strong> vendors, <strong>1</strong> not yet reviewed");
+    expect(html).toContain('<td><a href="https://stripe.com/legal/dpa">Stripe</a></td><td>payments</td>');
+    expect(html).toContain('<ac:parameter ac:name="title">approved</ac:parameter>');
+    expect(html).toContain("<td>Acme &lt;Internal&gt;</td>");
+    expect(html).toContain("<td>acme/billing, acme/checkout</td>");
+  });
+});
+
+describe("publishToConfluence", () => {
+  it("overwrites the page with the next version", async () => {
+    const server = await mockServer((req, res) => {
+      res.end(req.method === "GET" ? JSON.stringify({ title: "Vendors", version: { number: 7 } }) : "{}");
+    });
+    const version = await publishToConfluence(
+      { baseUrl: `${server.origin}/wiki/`, pageId: "123", email: "ops@acme.io", apiToken: "tok" },
+      page,
+    );
+    server.close();
+
+    expect(version).toBe(8);
+    expect(server.requests.map((r) => `${r.method} ${r.url}`)).toEqual([
+      "GET /wiki/rest/api/content/123?expand=version",
+      "PUT /wiki/rest/api/content/123",
+    ]);
+    expect(server.requests[0]!.headers.authorization).toBe(`Basic ${Buffer.from("ops@acme.io:tok").toString("base64")}`);
+    expect(server.requests[1]!.body).toMatchObject({ title: "Vendors", version: { number: 8 }, body: { storage: { representation: "storage" } } });
+  });
+});
+
+describe("publishToNotion", () => {
+  it("adds missing columns, then creates, updates, skips, and archives rows", async () => {
+    const row = (id: string, slug: string, extra: Record<string, unknown> = {}) => ({
+      id,
+      properties: { Slug: { type: "rich_text", rich_text: [{ plain_text: slug }] }, ...extra },
+    });
+    const server = await mockServer((req, res) => {
+      if (req.method === "GET") {
+        res.end(JSON.stringify({ properties: { Name: { type: "title" }, Status: { type: "select" } } }));
+      } else if (req.url.endsWith("/query")) {
+        res.end(JSON.stringify({
+          results: [
+            row("p-stripe", "stripe"),
+            row("p-internal", "acme-<internal>", {
+              Name: { type: "title", title: [{ plain_text: "Acme <Internal>" }] },
+              Category: { type: "select", select: null },
+              Status: { type: "select", select: { name: "unreviewed" } },
+              Repositories: { type: "number", number: 1 },
+              Usages: { type: "number", number: 1 },
+              Data: { type: "multi_select", multi_select: [] },
+              "Used in": { type: "rich_text", rich_text: [{ plain_text: "acme/checkout" }] },
+              Compliance: { type: "url", url: null },
+            }),
+            row("p-pusher", "pusher"),
+          ],
+          has_more: false,
+          next_cursor: null,
+        }));
+      } else {
+        res.end("{}");
+      }
+    });
+    const result = await publishToNotion({ token: "secret_x", databaseId: "db1", baseUrl: server.origin }, page);
+    server.close();
+
+    expect(result).toEqual({ created: 0, updated: 1, archived: 1, unchanged: 1 });
+    const calls = server.requests.map((r) => `${r.method} ${r.url}`);
+    expect(calls).toEqual([
+      "GET /v1/databases/db1",
+      "PATCH /v1/databases/db1",
+      "POST /v1/databases/db1/query",
+      "PATCH /v1/pages/p-stripe",
+      "PATCH /v1/pages/p-pusher",
+    ]);
+    expect(server.requests[0]!.headers["notion-version"]).toBe("2022-06-28");
+    expect(Object.keys(server.requests[1]!.body.properties)).toEqual(["Slug", "Category", "Repositories", "Usages", "Data", "Used in", "Compliance"]);
+    expect(server.requests[3]!.body.properties).toMatchObject({
+      Name: { title: [{ text: { content: "Stripe" } }] },
+      Repositories: { number: 2 },
+      Data: { multi_select: [{ name: "pci" }] },
+    });
+    expect(server.requests[4]!.body).toEqual({ archived: true });
+  });
+
+  it("refuses to retype an existing column", async () => {
+    const server = await mockServer((_req, res) => {
+      res.end(JSON.stringify({ properties: { Name: { type: "title" }, Status: { type: "status" } } }));
+    });
+    await expect(publishToNotion({ token: "t", databaseId: "db1", baseUrl: server.origin }, page)).rejects.toThrow(
+      'Notion column "Status" must be of type select, not status',
+    );
+    server.close();
+  });
+});
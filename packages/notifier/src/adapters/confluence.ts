@@ -0,0 +1,57 @@
+import type { InventoryPage } from "../inventory.js";
+import { renderConfluenceStorage } from "../inventory.js";
+
+// ---------------------------------------------------------------------------
+// Confluence settings
+// ---------------------------------------------------------------------------
+
+export interface ConfluenceSettings {
+  /** Site URL including the context path, e.g. https://acme.atlassian.net/wiki */
+  baseUrl: string;
+  /** Page to overwrite; create it once by hand and copy the ID from its URL */
+  pageId: string;
+  /** Atlassian account email; with it the token is a Cloud API token (basic auth), without it a Data Center personal access token */
+  email?: string | undefined;
+  apiToken: string;
+  /** Page title (default: keeps the page's current title) */
+  title?: string | undefined;
+  timeoutMs?: number | undefined;
+}
+
+// ---------------------------------------------------------------------------
+// Publishing
+// ---------------------------------------------------------------------------
+
+/** Replace the page body with the inventory; returns the new page version */
+export async function publishToConfluence(settings: ConfluenceSettings, page: InventoryPage): Promise<number> {
+  const url = `${settings.baseUrl.replace(/\/+$/, "")}/rest/api/content/${encodeURIComponent(settings.pageId)}`;
+  const headers: Record<string, string> = {
+    Accept: "application/json",
+    "Content-Type": "application/json",
+    Authorization: settings.email
+      ? `Basic ${Buffer.from(`${settings.email}:${settings.apiToken}`).toString("base64")}`
+      : `Bearer ${settings.apiToken}`,
+  };
+  const timeoutMs = settings.timeoutMs ?? 10_000;
+
+  const current = await fetch(`${url}?expand=version`, { headers, signal: AbortSignal.timeout(timeoutMs) });
+  if (!current.ok) throw new Error(`Confluence returned HTTP ${current.status} reading page ${settings.pageId}`);
+  const existing = (await current.json()) as { title: string; version: { number: number } };
+
+  // Confluence rejects an update unless it names the next version
+  const version = existing.version.number + 1;
+  const res = await fetch(url, {
+    method: "PUT",
+    headers,
+    body: JSON.stringify({
+      id: settings.pageId,
+      type: "page",
+      title: settings.title ?? existing.title,
+      version: { number: version, message: "Updated by thirdwatch" },
+      body: { storage: { value: renderConfluenceStorage(page), representation: "storage" } },
+    }),
+    signal: AbortSignal.timeout(timeoutMs),
+  });
+  if (!res.ok) throw new Error(`Confluence returned HTTP ${res.status} updating page ${settings.pageId}`);
+  return version;
+}
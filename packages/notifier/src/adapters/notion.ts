@@ -0,0 +1,178 @@
+import type { InventoryPage, InventoryVendor } from "../inventory.js";
+
+// ---------------------------------------------------------------------------
+// Notion settings
+// ---------------------------------------------------------------------------
+
+export interface NotionSettings {
+  /** Internal integration token; share the database with the integration */
+  token: string;
+  /** Database ID from the database URL */
+  databaseId: string;
+  /** API origin (default: https://api.notion.com) */
+  baseUrl?: string | undefined;
+  timeoutMs?: number | undefined;
+}
+
+export interface NotionPublishResult {
+  created: number;
+  updated: number;
+  archived: number;
+  unchanged: number;
+}
+
+// ---------------------------------------------------------------------------
+// Database schema — one row per vendor, keyed by "Slug". Missing columns are
+// added on the first publish; the title column keeps whatever name it has.
+// ---------------------------------------------------------------------------
+
+const NOTION_VERSION = "2022-06-28";
+// Notion rejects rich text longer than this
+const MAX_TEXT = 2000;
+// Longest wait honored for a rate-limited request
+const MAX_RETRY_AFTER_MS = 10_000;
+
+const COLUMNS = {
+  Slug: "rich_text",
+  Category: "select",
+  Status: "select",
+  Repositories: "number",
+  Usages: "number",
+  Data: "multi_select",
+  "Used in": "rich_text",
+  Compliance: "url",
+} as const;
+
+type Property = Record<string, any>;
+
+interface NotionPage {
+  id: string;
+  properties: Record<string, Property>;
+}
+
+function text(value: string): Array<{ type: "text"; text: { content: string } }> {
+  return value ? [{ type: "text", text: { content: value.slice(0, MAX_TEXT) } }] : [];
+}
+
+function vendorProperties(titleColumn: string, v: InventoryVendor): Record<string, Property> {
+  return {
+    [titleColumn]: { title: text(v.display_name) },
+    Slug: { rich_text: text(v.vendor) },
+    Category: { select: v.category ? { name: v.category } : null },
+    Status: { select: { name: v.approval } },
+    Repositories: { number: v.repositories.length },
+    Usages: { number: v.total_usages },
+    // Option names can't contain commas
+    Data: { multi_select: (v.data_classification ?? []).map((name) => ({ name: name.replace(/,/g, " ") })) },
+    "Used in": { rich_text: text(v.repositories.join(", ")) },
+    Compliance: { url: v.compliance_url ?? null },
+  };
+}
+
+/** A comparable string for a property value, as sent or as returned by Notion */
+function plain(property: Property | undefined): string {
+  if (!property) return "";
+  const runs = (property["title"] ?? property["rich_text"]) as Array<{ plain_text?: string; text?: { content: string } }> | undefined;
+  if (runs) return runs.map((r) => r.plain_text ?? r.text?.content ?? "").join("");
+  if ("select" in property) return property["select"]?.name ?? "";
+  if ("multi_select" in property) return property["multi_select"].map((o: { name: string }) => o.name).sort().join(",");
+  if ("number" in property) return String(property["number"] ?? "");
+  if ("url" in property) return property["url"] ?? "";
+  return "";
+}
+
+function unchanged(page: NotionPage, desired: Record<string, Property>): boolean {
+  return Object.entries(desired).every(([name, value]) => plain(page.properties[name]) === plain(value));
+}
+
+// ---------------------------------------------------------------------------
+// Publishing
+// ---------------------------------------------------------------------------
+
+/**
+ * Sync the database to the inventory: create rows for new vendors, update
+ * rows whose values changed, and archive rows for vendors no longer in use.
+ */
+export async function publishToNotion(settings: NotionSettings, page: InventoryPage): Promise<NotionPublishResult> {
+  const origin = (settings.baseUrl ?? "https://api.notion.com").replace(/\/+$/, "");
+  const timeoutMs = settings.timeoutMs ?? 10_000;
+
+  async function request<T>(method: string, path: string, body?: unknown, retried = false): Promise<T> {
+    const res = await fetch(`${origin}/v1${path}`, {
+      method,
+      headers: {
+        Authorization: `Bearer ${settings.token}`,
+        "Notion-Version": NOTION_VERSION,
+        "Content-Type": "application/json",
+      },
+      ...(body !== undefined ? { body: JSON.stringify(body) } : {}),
+      signal: AbortSignal.timeout(timeoutMs),
+    });
+    if (res.status === 429 && !retried) {
+      const wait = Math.min(Number(res.headers.get("retry-after") ?? "1") * 1000, MAX_RETRY_AFTER_MS);
+      await new Promise((resolve) => setTimeout(resolve, wait));
+      return request<T>(method, path, body, true);
+    }
+    if (!res.ok) {
+      const err = (await res.json().catch(() => ({}))) as { message?: string };
+      throw new Error(`Notion returned HTTP ${res.status} for ${method} ${path}${err.message ? `: ${err.message}` : ""}`);
+    }
+    return (await res.json()) as T;
+  }
+
+  const database = `/databases/${encodeURIComponent(settings.databaseId)}`;
+  const schema = await request<{ properties: Record<string, { type: string }> }>("GET", database);
+  const titleColumn = Object.entries(schema.properties).find(([, p]) => p.type === "title")?.[0] ?? "Name";
+  const missing: Record<string, Property> = {};
+  for (const [name, type] of Object.entries(COLUMNS)) {
+    const existing = schema.properties[name];
+    if (!existing) missing[name] = { [type]: {} };
+    else if (existing.type !== type) {
+      throw new Error(`Notion column "${name}" must be of type ${type}, not ${existing.type}`);
+    }
+  }
+  await request("PATCH", database, {
+    ...(Object.keys(missing).length > 0 ? { properties: missing } : {}),
+    description: text(
+      `Generated by thirdwatch from ${page.total_repositories} repositories at ${page.generated_at}. ` +
+        `Rows are overwritten on each scan.`,
+    ),
+  });
+
+  const rows = new Map<string, NotionPage>();
+  let cursor: string | undefined;
+  do {
+    const result = await request<{ results: NotionPage[]; has_more: boolean; next_cursor: string | null }>(
+      "POST",
+      `${database}/query`,
+      { page_size: 100, ...(cursor ? { start_cursor: cursor } : {}) },
+    );
+    for (const row of result.results) {
+      const slug = plain(row.properties["Slug"]);
+      if (slug) rows.set(slug, row);
+    }
+    cursor = result.has_more && result.next_cursor ? result.next_cursor : undefined;
+  } while (cursor);
+
+  const counts: NotionPublishResult = { created: 0, updated: 0, archived: 0, unchanged: 0 };
+  // Sequential: Notion allows about three requests per second
+  for (const vendor of page.vendors) {
+    const properties = vendorProperties(titleColumn, vendor);
+    const row = rows.get(vendor.vendor);
+    rows.delete(vendor.vendor);
+    if (!row) {
+      await request("POST", "/pages", { parent: { database_id: settings.databaseId }, properties });
+      counts.created++;
+    } else if (unchanged(row, properties)) {
+      counts.unchanged++;
+    } else {
+      await request("PATCH", `/pages/${row.id}`, { properties });
+      counts.updated++;
+    }
+  }
+  for (const row of rows.values()) {
+    await request("PATCH", `/pages/${row.id}`, { archived: true });
+    counts.archived++;
+  }
+  return counts;
+}
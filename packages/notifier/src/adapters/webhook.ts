@@ -1,4 +1,4 @@
-import { createHmac } from "node:crypto";
+import { signPayload } from "@thirdwatch/core";
 import type { ImpactAssessment } from "@thirdwatch/analyzer";
 import type { NotifierAdapter, NotificationResult } from "../types.js";
 
@@ -37,14 +37,10 @@ export interface WebhookPayload {
 }
 
 // ---------------------------------------------------------------------------
-// HMAC-SHA256 signature
+// HMAC-SHA256 signature, shared with the egress alert and vendor feed webhooks
 // ---------------------------------------------------------------------------
 
-export function signPayload(payload: string, secret: string): string {
-  return (
-    "sha256=" + createHmac("sha256", secret).update(payload).digest("hex")
-  );
-}
+export { signPayload };
 
 // ---------------------------------------------------------------------------
 // Webhook adapter
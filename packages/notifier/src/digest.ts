@@ -0,0 +1,156 @@
+// ---------------------------------------------------------------------------
+// Team digests — a team's third-party footprint, what changed since the last
+// digest, and what needs attention. Built by the worker on each team's
+// schedule and delivered to Slack and/or email.
+// ---------------------------------------------------------------------------
+
+export interface DigestVendorChange {
+  repository: string;
+  vendor: string;
+  change: "added" | "removed";
+  commit: string | null;
+}
+
+export interface DigestOpenChange {
+  dependency: string;
+  priority: string;
+  title: string;
+  url?: string | undefined;
+  repositories: string[];
+}
+
+export interface TeamDigestInput {
+  team: string;
+  periodStart: string;
+  periodEnd: string;
+  /** Current vendors per repository the team owns */
+  footprint: Array<{ repository: string; vendors: string[] }>;
+  /** Vendor feed events in the period, for the team's repositories */
+  vendorChanges: DigestVendorChange[];
+  /** Unresolved high-priority dependency changes affecting the team */
+  openChanges: DigestOpenChange[];
+  /** Vendors the team's policy disallows */
+  deniedVendors: string[];
+}
+
+export interface TeamDigest {
+  team: string;
+  period_start: string;
+  period_end: string;
+  repositories: number;
+  /** Vendors in use, most widely used first */
+  vendors: Array<{ vendor: string; repositories: string[] }>;
+  added: DigestVendorChange[];
+  removed: DigestVendorChange[];
+  /** Denied vendors still in use */
+  violations: Array<{ vendor: string; repositories: string[] }>;
+  open_changes: DigestOpenChange[];
+}
+
+/** Repository patterns use "*" as a wildcard, e.g. "github.com/acme/payments-*" */
+export function matchesRepository(repository: string, patterns: string[]): boolean {
+  return patterns.some((p) => {
+    const re = new RegExp(
+      "^" + p.split("*").map((s) => s.replace(/[.+?^${}()|[\]\\]/g, "\\$&")).join(".*") + "$",
+    );
+    return re.test(repository);
+  });
+}
+
+export function buildTeamDigest(input: TeamDigestInput): TeamDigest {
+  const byVendor = new Map<string, string[]>();
+  for (const { repository, vendors } of input.footprint) {
+    for (const vendor of vendors) byVendor.set(vendor, [...(byVendor.get(vendor) ?? []), repository]);
+  }
+  const vendors = [...byVendor]
+    .map(([vendor, repositories]) => ({ vendor, repositories: repositories.sort() }))
+    .sort((a, b) => b.repositories.length - a.repositories.length || a.vendor.localeCompare(b.vendor));
+  const denied = new Set(input.deniedVendors);
+
+  return {
+    team: input.team,
+    period_start: input.periodStart,
+    period_end: input.periodEnd,
+    repositories: input.footprint.length,
+    vendors,
+    added: input.vendorChanges.filter((c) => c.change === "added"),
+    removed: input.vendorChanges.filter((c) => c.change === "removed"),
+    violations: vendors.filter((v) => denied.has(v.vendor)),
+    open_changes: input.openChanges,
+  };
+}
+
+function day(iso: string): string {
+  return iso.slice(0, 10);
+}
+
+function changeLine(c: DigestVendorChange): string {
+  return `${c.vendor} in ${c.repository}${c.commit ? ` (${c.commit.slice(0, 7)})` : ""}`;
+}
+
+export function digestSubject(digest: TeamDigest): string {
+  const attention = digest.violations.length + digest.open_changes.length;
+  return (
+    `[thirdwatch] ${digest.team}: ${digest.vendors.length} vendors, ` +
+    `${digest.added.length} added, ${digest.removed.length} removed` +
+    (attention > 0 ? `, ${attention} need attention` : "")
+  );
+}
+
+/** Plain-text rendering, used as the email body */
+export function renderDigestText(digest: TeamDigest): string {
+  const lines = [
+    `Third-party digest for ${digest.team}, ${day(digest.period_start)} to ${day(digest.period_end)}`,
+    "",
+    `${digest.vendors.length} vendors across ${digest.repositories} repositories.`,
+  ];
+  const section = (title: string, items: string[]) => {
+    if (items.length === 0) return;
+    lines.push("", `${title} (${items.length})`, ...items.map((i) => `  - ${i}`));
+  };
+  section("Policy violations", digest.violations.map((v) => `${v.vendor} is not allowed, used in ${v.repositories.join(", ")}`));
+  section(
+    "Open high-priority changes",
+    digest.open_changes.map((c) => `[${c.priority}] ${c.dependency}: ${c.title}${c.url ? ` ${c.url}` : ""}`),
+  );
+  section("New vendors", digest.added.map(changeLine));
+  section("Removed vendors", digest.removed.map(changeLine));
+  section("Current footprint", digest.vendors.map((v) => `${v.vendor} (${v.repositories.length})`));
+  return lines.join("\n") + "\n";
+}
+
+/** Slack incoming-webhook payload */
+export function renderDigestSlack(digest: TeamDigest): { text: string; blocks: unknown[] } {
+  const bullet = (items: string[]) => items.map((i) => `• ${i}`).join("\n");
+  const blocks: unknown[] = [
+    { type: "header", text: { type: "plain_text", text: `Third-party digest: ${digest.team}` } },
+    {
+      type: "section",
+      text: {
+        type: "mrkdwn",
+        text: `${digest.vendors.length} vendors across ${digest.repositories} repositories, ${day(digest.period_start)} to ${day(digest.period_end)}`,
+      },
+    },
+  ];
+  const section = (title: string, items: string[]) => {
+    if (items.length === 0) return;
+    // Slack caps section text at 3000 characters
+    blocks.push({ type: "section", text: { type: "mrkdwn", text: `*${title}*\n${bullet(items.slice(0, 20))}`.slice(0, 3000) } });
+  };
+  section(":no_entry: Policy violations", digest.violations.map((v) => `*${v.vendor}* in ${v.repositories.join(", ")}`));
+  section(":rotating_light: Open high-priority changes", digest.open_changes.map((c) => `[${c.priority}] *${c.dependency}*: ${c.title}`));
+  section(":new: New vendors", digest.added.map(changeLine));
+  section(":wastebasket: Removed vendors", digest.removed.map(changeLine));
+  return { text: digestSubject(digest), blocks };
+}
+
+/** POST a digest to a Slack incoming webhook */
+export async function sendSlackDigest(webhookUrl: string, digest: TeamDigest, timeoutMs = 10_000): Promise<void> {
+  const res = await fetch(webhookUrl, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(renderDigestSlack(digest)),
+    signal: AbortSignal.timeout(timeoutMs),
+  });
+  if (!res.ok) throw new Error(`Slack webhook returned HTTP ${res.status}`);
+}
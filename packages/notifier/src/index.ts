@@ -18,3 +18,25 @@ export { InMemoryDeduplicationStore } from "./dedup.js";
 
 export { WebhookAdapter, signPayload } from "./adapters/webhook.js";
 export type { WebhookSettings, WebhookPayload } from "./adapters/webhook.js";
+
+export { sendMail } from "./adapters/smtp.js";
+export type { SmtpSettings, MailMessage } from "./adapters/smtp.js";
+
+export {
+  buildTeamDigest,
+  renderDigestText,
+  renderDigestSlack,
+  sendSlackDigest,
+  digestSubject,
+  matchesRepository,
+} from "./digest.js";
+export type { TeamDigest, TeamDigestInput, DigestVendorChange, DigestOpenChange } from "./digest.js";
+
+export { renderConfluenceStorage } from "./inventory.js";
+export type { InventoryPage, InventoryVendor, ApprovalStatus } from "./inventory.js";
+
+export { publishToConfluence } from "./adapters/confluence.js";
+export type { ConfluenceSettings } from "./adapters/confluence.js";
+
+export { publishToNotion } from "./adapters/notion.js";
+export type { NotionSettings, NotionPublishResult } from "./adapters/notion.js";
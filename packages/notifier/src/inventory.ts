@@ -0,0 +1,68 @@
+// ---------------------------------------------------------------------------
+// Vendor inventory published to Confluence and Notion after each scan, so the
+// vendor-management process works from the current scan instead of a copy
+// ---------------------------------------------------------------------------
+
+export type ApprovalStatus = "approved" | "denied" | "unreviewed";
+
+export interface InventoryVendor {
+  vendor: string;
+  display_name: string;
+  category?: string | undefined;
+  /** Repositories using the vendor, sorted */
+  repositories: string[];
+  total_usages: number;
+  /** Data classification labels, e.g. ["pci", "pii"] */
+  data_classification?: string[] | undefined;
+  approval: ApprovalStatus;
+  /** Vendor's privacy policy or DPA, when the catalog has one */
+  compliance_url?: string | undefined;
+}
+
+export interface InventoryPage {
+  /** Organization name, used in the page heading */
+  organization: string;
+  generated_at: string;
+  total_repositories: number;
+  /** Most widely used first */
+  vendors: InventoryVendor[];
+}
+
+function escapeXml(text: string): string {
+  return text.replace(/[<>&"']/g, (c) => ({ "<": "&lt;", ">": "&gt;", "&": "&amp;", '"': "&quot;", "'": "&apos;" })[c]!);
+}
+
+const STATUS_COLORS: Record<ApprovalStatus, string> = {
+  approved: "Green",
+  denied: "Red",
+  unreviewed: "Grey",
+};
+
+/** Confluence storage format (XHTML) for the inventory page body */
+export function renderConfluenceStorage(page: InventoryPage): string {
+  const status = (s: ApprovalStatus) =>
+    `<ac:structured-macro ac:name="status"><ac:parameter ac:name="colour">${STATUS_COLORS[s]}</ac:parameter>` +
+    `<ac:parameter ac:name="title">${s}</ac:parameter></ac:structured-macro>`;
+  const rows = page.vendors.map((v) => {
+    const name = v.compliance_url
+      ? `<a href="${escapeXml(v.compliance_url)}">${escapeXml(v.display_name)}</a>`
+      : escapeXml(v.display_name);
+    return (
+      `<tr><td>${name}</td><td>${escapeXml(v.category ?? "")}</td><td>${status(v.approval)}</td>` +
+      `<td>${v.repositories.length}</td><td>${v.total_usages}</td>` +
+      `<td>${escapeXml((v.data_classification ?? []).join(", "))}</td>` +
+      `<td>${escapeXml(v.repositories.join(", "))}</td></tr>`
+    );
+  });
+  const unreviewed = page.vendors.filter((v) => v.approval === "unreviewed").length;
+  return [
+    `<p>Generated by thirdwatch from the latest scan of ${page.total_repositories} ` +
+      `${page.total_repositories === 1 ? "repository" : "repositories"} at ${escapeXml(page.generated_at)}. ` +
+      `Edits to this page are overwritten on the next scan.</p>`,
+    `<p><strong>${page.vendors.length}</strong> vendors, <strong>${unreviewed}</strong> not yet reviewed.</p>`,
+    `<table><tbody>`,
+    `<tr><th>Vendor</th><th>Category</th><th>Status</th><th>Repositories</th><th>Usages</th><th>Data</th><th>Used in</th></tr>`,
+    ...rows,
+    `</tbody></table>`,
+  ].join("");
+}
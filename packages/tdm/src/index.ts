@@ -2,14 +2,28 @@ export { TDM_SCHEMA_VERSION } from "./types.js";
 export type {
   TDM,
   TDMMetadata,
+  TDMArtifact,
   TDMPackage,
   TDMApi,
   TDMSdk,
   TDMInfrastructure,
   TDMWebhook,
   TDMLocation,
+  TDMEnrichment,
+  TDMSuggestion,
+  TDMRuntime,
+  TDMRuntimeSdk,
+  TDMScanError,
+  TDMScanStage,
+  TDMSecret,
+  TDMSnippet,
+  TDMCredentialPrivilege,
+  TDMRemediation,
+  TDMRemediationKind,
   TDMValidationIssue,
   Confidence,
+  Severity,
+  DataFlow,
   ChangeCategory,
   Priority,
 } from "./types.js";
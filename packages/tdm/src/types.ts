@@ -6,6 +6,12 @@ export const TDM_SCHEMA_VERSION = "1.0" as const;
 
 export type Confidence = "high" | "medium" | "low";
 
+/** Rule severity, configurable per rule under `rule_settings` in .thirdwatch.yml */
+export type Severity = "error" | "warning" | "info";
+
+/** Direction of data between the service and a dependency */
+export type DataFlow = "send" | "receive" | "bidirectional";
+
 export type ChangeCategory =
   | "breaking"
   | "deprecation"
@@ -41,8 +47,76 @@ export interface TDMLocation {
   line: number;
   /** Short code snippet for human readability */
   context?: string;
+  /** Surrounding source lines, with detected secrets redacted, when the scan asked for context */
+  snippet?: TDMSnippet;
   /** Usage kind, e.g. "import" or "method_call:stripe.Charge.create" */
   usage?: string;
+  /** Hardcoded credential found on this line, if any */
+  secret?: TDMSecret;
+  /** Environment the location belongs to: "development", "test", "staging", or "production" */
+  environment?: string;
+  /** Logical service the file belongs to, from `services` in .thirdwatch.yml */
+  service?: string;
+  /** Hash of the finding and its enclosing symbol and line text, stable across file moves and line shifts */
+  fingerprint?: string;
+  /** Suggested fix for what the location does, for bots and editors to apply */
+  remediation?: TDMRemediation;
+}
+
+// ---------------------------------------------------------------------------
+// Snippet — source around a location, for review without the repository
+// ---------------------------------------------------------------------------
+
+export interface TDMSnippet {
+  /** 1-indexed line number of `lines[0]` */
+  start_line: number;
+  /** Source lines from `start_line` on, secrets replaced with [REDACTED] */
+  lines: string[];
+}
+
+// ---------------------------------------------------------------------------
+// Secret — a hardcoded credential, dated via git history
+// ---------------------------------------------------------------------------
+
+export interface TDMSecret {
+  /** Credential kind, e.g. "stripe_secret_key" or "connection_string_password" */
+  kind: string;
+  /** SHA of the commit that first introduced the secret */
+  introduced_commit?: string;
+  /** ISO 8601 author date of the introducing commit */
+  introduced_at?: string;
+  /** Whole days the secret has been live in the repository */
+  age_days?: number;
+  /** Key type the credential's format encodes, e.g. "restricted_key" or "classic_token" */
+  key_type?: string;
+  /** What the key type can do; absent when the format doesn't tell (e.g. AWS keys) */
+  privilege?: TDMCredentialPrivilege;
+  /** Why the credential is broader than the integration using it needs */
+  overly_broad?: string;
+}
+
+/**
+ * "admin" manages the account or organization, "full" has unrestricted API
+ * access, "scoped" is limited to granted permissions, "public" is meant to
+ * ship to clients.
+ */
+export type TDMCredentialPrivilege = "admin" | "full" | "scoped" | "public";
+
+export type TDMRemediationKind = "use_env_var" | "add_timeout" | "route_via_gateway";
+
+export interface TDMRemediation {
+  /** What the fix does: move a hardcoded credential to an env var, bound a call with a timeout, or send it through the egress gateway */
+  kind: TDMRemediationKind;
+  /** Human-readable instruction */
+  description: string;
+  /** Environment variable the credential should move to (use_env_var) */
+  env_var?: string;
+  /** Gateway URL the call or client should use instead of the vendor's (route_via_gateway) */
+  gateway_url?: string;
+  /** New text of the line, for a PR suggestion */
+  replacement?: string;
+  /** Unified diff of the change; a secret on the line is redacted from it */
+  diff?: string;
 }
 
 // ---------------------------------------------------------------------------
@@ -65,6 +139,18 @@ export interface TDMMetadata {
   total_dependencies_found: number;
   /** Wall-clock scan time in milliseconds */
   scan_duration_ms: number;
+  /** Version of the vendor catalog bundle used, when not the built-in one */
+  catalog_version?: string;
+  /** The archive, container image, or SBOM read, when not a source directory */
+  artifact?: TDMArtifact;
+}
+
+export interface TDMArtifact {
+  type: "archive" | "image" | "sbom";
+  /** File name of the archive or SBOM, or the image reference, e.g. "ghcr.io/acme/checkout:1.4" */
+  reference: string;
+  /** sha256 of the archive or SBOM, or the image's config digest (its image ID) */
+  digest?: string;
 }
 
 // ---------------------------------------------------------------------------
@@ -74,6 +160,10 @@ export interface TDMMetadata {
 export interface TDMPackage {
   /** Stable identifier in PURL format, e.g. "pkg:pypi/stripe@7.0.0" */
   id?: string;
+  /** Detector rule that produced the entry, e.g. "TW-STRIPE-SDK-GO" */
+  rule_id?: string;
+  /** Severity from `rule_settings`; absent means the default, "info" */
+  severity?: Severity;
   /** Package name, e.g. "stripe" or "@aws-sdk/client-s3" */
   name: string;
   /** Package ecosystem */
@@ -84,6 +174,8 @@ export interface TDMPackage {
   version_constraint?: string;
   /** Path to the manifest file, e.g. "requirements.txt" */
   manifest_file: string;
+  /** Logical services whose manifests declare the package, from `services` in .thirdwatch.yml */
+  services?: string[];
   /** All locations where this package is imported or used */
   locations: TDMLocation[];
   /** Number of distinct usage sites */
@@ -99,16 +191,36 @@ export interface TDMPackage {
 export interface TDMApi {
   /** Stable identifier, e.g. "api:stripe/charges-post" */
   id?: string;
+  /** Detector rule that produced the entry, e.g. "TW-STRIPE-SDK-GO" */
+  rule_id?: string;
+  /** Severity from `rule_settings`; absent means the default, "info" */
+  severity?: Severity;
+  /** Whether data goes to the dependency, comes from it, or both; inferred or set by `annotations` */
+  data_flow?: DataFlow;
+  /** Data classification labels, e.g. ["pii", "pci"]; inferred or set by `annotations` */
+  data_classification?: string[];
   /** Literal URL or template, e.g. "${BASE_URL}/v2/users" */
   url: string;
   /** HTTP verb — one of the standard HTTP methods */
   method?: "GET" | "POST" | "PUT" | "PATCH" | "DELETE" | "HEAD" | "OPTIONS" | "CONNECT" | "TRACE";
   /** Auto-detected provider slug, e.g. "stripe"; null when unknown */
   provider?: string | null;
+  /** Vendor category, e.g. "payments", "observability" (from the catalog or a custom rule) */
+  category?: string;
   /** URL after env var resolution attempt */
   resolved_url?: string;
   /** Header name patterns found at the call site */
   headers?: string[];
+  /** True when the host is registered as first-party (internal) */
+  first_party?: boolean;
+  /** Egress gateway (from `gateways` in .thirdwatch.yml) the call should go through but doesn't */
+  gateway_bypass?: string;
+  /** Ownership metadata for hosts not in the catalog (opt-in, `--enrich`) */
+  enrichment?: TDMEnrichment;
+  /** Unconfirmed classification suggested by an LLM (opt-in, `--llm-classify`) */
+  suggestion?: TDMSuggestion;
+  /** Runtime evidence when the entry was observed rather than found in code */
+  runtime?: TDMRuntime;
   /** All locations where this URL is referenced */
   locations: TDMLocation[];
   /** Number of distinct call sites */
@@ -117,6 +229,78 @@ export interface TDMApi {
   confidence: Confidence;
 }
 
+// ---------------------------------------------------------------------------
+// TDMEnrichment — who operates an unknown external host
+// ---------------------------------------------------------------------------
+
+export interface TDMEnrichment {
+  /** Domain registrant organization from RDAP, when not redacted */
+  registrant?: string;
+  /** Domain registrar from RDAP */
+  registrar?: string;
+  /** ISO 8601 domain registration date from RDAP */
+  registered_at?: string;
+  /** IPv4 address the host resolved to */
+  ip?: string;
+  /** Autonomous system number hosting the IP */
+  asn?: number;
+  /** Autonomous system name, e.g. "AMAZON-02, US" */
+  as_name?: string;
+  /** Organization (O) in the TLS certificate subject */
+  cert_subject_org?: string;
+  /** Organization (O) of the TLS certificate issuer */
+  cert_issuer_org?: string;
+}
+
+// ---------------------------------------------------------------------------
+// TDMSuggestion — machine-suggested classification awaiting human review
+// ---------------------------------------------------------------------------
+
+export interface TDMSuggestion {
+  /** What produced the suggestion */
+  source: "llm";
+  /** Model identifier that produced it, e.g. "gpt-4o-mini" */
+  model: string;
+  /** Suggested vendor slug, e.g. "acme-logs" */
+  vendor?: string;
+  /** Suggested vendor category, e.g. "payments", "observability" */
+  category?: string;
+  /** One-sentence rationale from the model */
+  reasoning?: string;
+}
+
+// ---------------------------------------------------------------------------
+// TDMRuntime — evidence from observing live traffic (`thirdwatch agent`, ...)
+// ---------------------------------------------------------------------------
+
+export interface TDMRuntime {
+  /** Observation source, e.g. "agent", "proxy", "dns" */
+  source: string;
+  /** Number of connections / requests observed */
+  count: number;
+  /** ISO 8601 timestamp of the first observation */
+  first_seen: string;
+  /** ISO 8601 timestamp of the most recent observation */
+  last_seen: string;
+  /** Process names that made the connections */
+  processes?: string[];
+  /** Client addresses or workload IDs the traffic came from (log sources) */
+  clients?: string[];
+  /** SDKs identified from the requests' User-Agent headers */
+  sdks?: TDMRuntimeSdk[];
+}
+
+export interface TDMRuntimeSdk {
+  /** Package the SDK ships as, e.g. "stripe" */
+  sdk_package: string;
+  /** Package ecosystem, e.g. "pypi" */
+  ecosystem?: string;
+  /** SDK version the User-Agent reports */
+  version?: string;
+  /** Requests sent by this SDK and version */
+  count: number;
+}
+
 // ---------------------------------------------------------------------------
 // TDMSdk — usage of a provider SDK library
 // ---------------------------------------------------------------------------
@@ -124,14 +308,38 @@ export interface TDMApi {
 export interface TDMSdk {
   /** Stable identifier, e.g. "sdk:aws/boto3" */
   id?: string;
+  /** Detector rule that produced the entry, e.g. "TW-STRIPE-SDK-GO" */
+  rule_id?: string;
+  /** Severity from `rule_settings`; absent means the default, "info" */
+  severity?: Severity;
+  /** Whether data goes to the dependency, comes from it, or both; inferred or set by `annotations` */
+  data_flow?: DataFlow;
+  /** Data classification labels, e.g. ["pii", "pci"]; inferred or set by `annotations` */
+  data_classification?: string[];
   /** Provider slug, e.g. "aws", "stripe", "openai" */
   provider: string;
+  /** Vendor category, e.g. "payments", "observability" (from the catalog or a custom rule) */
+  category?: string;
   /** The specific SDK package, e.g. "boto3" or "@aws-sdk/client-s3" */
   sdk_package: string;
   /** Sub-services used, e.g. ["s3", "sqs"] for AWS */
   services_used?: string[];
+  /** Cloud regions the clients are configured for, e.g. ["us-east-1"] */
+  regions?: string[];
+  /** Endpoint the client is configured for instead of the vendor default, e.g. a vLLM gateway */
+  base_url?: string;
+  /** Application data the SDK sends to the vendor, e.g. ["stack_traces", "request_payloads"] */
+  data_exported?: string[];
   /** Specific API methods called, e.g. ["stripe.Charge.create"] */
   api_methods?: string[];
+  /** What the code does with the SDK, from the methods it calls, e.g. ["data_read", "data_write"] */
+  intents?: string[];
+  /** The client is constructed but never called, wherever it is set up */
+  unused?: boolean;
+  /** Egress gateway (from `gateways` in .thirdwatch.yml) the client should use but doesn't */
+  gateway_bypass?: string;
+  /** Unconfirmed classification suggested by an LLM (opt-in, `--llm-classify`) */
+  suggestion?: TDMSuggestion;
   /** All locations where the SDK is used */
   locations: TDMLocation[];
   /** Number of distinct usage sites */
@@ -147,6 +355,14 @@ export interface TDMSdk {
 export interface TDMInfrastructure {
   /** Stable identifier, e.g. "infra:postgresql/DATABASE_URL" */
   id?: string;
+  /** Detector rule that produced the entry, e.g. "TW-STRIPE-SDK-GO" */
+  rule_id?: string;
+  /** Severity from `rule_settings`; absent means the default, "info" */
+  severity?: Severity;
+  /** Whether data goes to the dependency, comes from it, or both; inferred or set by `annotations` */
+  data_flow?: DataFlow;
+  /** Data classification labels, e.g. ["pii", "pci"]; inferred or set by `annotations` */
+  data_classification?: string[];
   /** Infrastructure type */
   type:
     | "postgresql"
@@ -161,10 +377,18 @@ export interface TDMInfrastructure {
     | "gcs"
     | "azure-blob"
     | string;
+  /** Vendor operating the host, e.g. "mongodb" for *.mongodb.net; absent when self-hosted or unknown */
+  provider?: string;
+  /** Vendor category of a managed service, e.g. "data-warehouse" (from the catalog) */
+  category?: string;
   /** Raw connection reference (may be an env var name like "DATABASE_URL") */
   connection_ref: string;
   /** Resolved hostname after env var lookup; null if unresolvable */
   resolved_host?: string | null;
+  /** True when the host is registered as first-party (internal) */
+  first_party?: boolean;
+  /** The connection is opened but never used, wherever it is set up */
+  unused?: boolean;
   /** All locations where this connection is configured */
   locations: TDMLocation[];
   /** Detection confidence */
@@ -178,6 +402,14 @@ export interface TDMInfrastructure {
 export interface TDMWebhook {
   /** Stable identifier, e.g. "webhook:outbound/stripe-endpoint" */
   id?: string;
+  /** Detector rule that produced the entry, e.g. "TW-STRIPE-SDK-GO" */
+  rule_id?: string;
+  /** Severity from `rule_settings`; absent means the default, "info" */
+  severity?: Severity;
+  /** Whether data goes to the dependency, comes from it, or both; inferred or set by `annotations` */
+  data_flow?: DataFlow;
+  /** Data classification labels, e.g. ["pii", "pci"]; inferred or set by `annotations` */
+  data_classification?: string[];
   /** "outbound_registration" = code registers a URL with a provider;
    *  "inbound_callback" = code exposes an endpoint that receives events */
   direction: "outbound_registration" | "inbound_callback";
@@ -185,6 +417,8 @@ export interface TDMWebhook {
   target_url: string;
   /** Provider slug if known, e.g. "stripe" */
   provider?: string;
+  /** True when the target host is registered as first-party (internal) */
+  first_party?: boolean;
   /** All locations where this webhook is configured */
   locations: TDMLocation[];
   /** Detection confidence */
@@ -210,4 +444,32 @@ export interface TDM {
   infrastructure: TDMInfrastructure[];
   /** Webhook registrations and callbacks */
   webhooks: TDMWebhook[];
+  /** What failed during the scan; present only when the results are partial */
+  errors?: TDMScanError[];
+}
+
+// ---------------------------------------------------------------------------
+// TDMScanError — a file or step the scan could not complete
+// ---------------------------------------------------------------------------
+
+export type TDMScanStage =
+  | "manifests"
+  | "config"
+  | "detector"
+  | "read"
+  | "analyze"
+  | "post-process"
+  | "fallback"
+  | "secret-history"
+  | "enrichment"
+  | "llm-classification";
+
+export interface TDMScanError {
+  /** File that failed, relative to the scan root; absent for scan-wide steps */
+  file?: string;
+  /** What was running when it failed */
+  stage: TDMScanStage;
+  /** Plugin language, custom detector name, or post-processing step, e.g. "python" */
+  detector?: string;
+  message: string;
 }
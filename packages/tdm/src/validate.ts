@@ -29,9 +29,28 @@ const TDM_SCHEMA = {
     sdks: { type: "array", items: { $ref: "#/$defs/TDMSdk" }, maxItems: 10000 },
     infrastructure: { type: "array", items: { $ref: "#/$defs/TDMInfrastructure" }, maxItems: 10000 },
     webhooks: { type: "array", items: { $ref: "#/$defs/TDMWebhook" }, maxItems: 10000 },
+    errors: { type: "array", items: { $ref: "#/$defs/TDMScanError" }, maxItems: 1000 },
   },
   $defs: {
+    TDMScanError: {
+      type: "object",
+      required: ["stage", "message"],
+      additionalProperties: false,
+      properties: {
+        file: { type: "string", maxLength: 4096 },
+        stage: { type: "string", enum: ["manifests", "config", "detector", "read", "analyze", "post-process", "fallback", "secret-history", "enrichment", "llm-classification"] },
+        detector: { type: "string", maxLength: 128 },
+        message: { type: "string", maxLength: 1024 },
+      },
+    },
     Confidence: { type: "string", enum: ["high", "medium", "low"] },
+    Severity: { type: "string", enum: ["error", "warning", "info"] },
+    DataFlow: { type: "string", enum: ["send", "receive", "bidirectional"] },
+    DataClassification: {
+      type: "array",
+      items: { type: "string", pattern: "^[a-z][a-z0-9_-]*$", maxLength: 32 },
+      maxItems: 20,
+    },
     TDMLocation: {
       type: "object",
       required: ["file", "line"],
@@ -40,7 +59,49 @@ const TDM_SCHEMA = {
         file: { type: "string", maxLength: 4096 },
         line: { type: "integer", minimum: 1 },
         context: { type: "string", maxLength: 512 },
+        snippet: { $ref: "#/$defs/TDMSnippet" },
         usage: { type: "string", maxLength: 256 },
+        secret: { $ref: "#/$defs/TDMSecret" },
+        environment: { type: "string", maxLength: 64 },
+        service: { type: "string", maxLength: 256 },
+        fingerprint: { type: "string", maxLength: 64 },
+        remediation: { $ref: "#/$defs/TDMRemediation" },
+      },
+    },
+    TDMSnippet: {
+      type: "object",
+      required: ["start_line", "lines"],
+      additionalProperties: false,
+      properties: {
+        start_line: { type: "integer", minimum: 1 },
+        lines: { type: "array", items: { type: "string", maxLength: 1024 }, maxItems: 41 },
+      },
+    },
+    TDMRemediation: {
+      type: "object",
+      required: ["kind", "description"],
+      additionalProperties: false,
+      properties: {
+        kind: { type: "string", enum: ["use_env_var", "add_timeout", "route_via_gateway"] },
+        description: { type: "string", maxLength: 1024 },
+        env_var: { type: "string", maxLength: 256 },
+        gateway_url: { type: "string", maxLength: 2048 },
+        replacement: { type: "string", maxLength: 4096 },
+        diff: { type: "string", maxLength: 8192 },
+      },
+    },
+    TDMSecret: {
+      type: "object",
+      required: ["kind"],
+      additionalProperties: false,
+      properties: {
+        kind: { type: "string", maxLength: 64 },
+        introduced_commit: { type: "string", pattern: "^[0-9a-f]{7,64}$" },
+        introduced_at: { type: "string", format: "date-time", maxLength: 64 },
+        age_days: { type: "integer", minimum: 0 },
+        key_type: { type: "string", maxLength: 64 },
+        privilege: { type: "string", enum: ["admin", "full", "scoped", "public"] },
+        overly_broad: { type: "string", maxLength: 512 },
       },
     },
     TDMMetadata: {
@@ -60,6 +121,17 @@ const TDM_SCHEMA = {
         languages_detected: { type: "array", items: { type: "string", maxLength: 64 }, maxItems: 100 },
         total_dependencies_found: { type: "integer", minimum: 0 },
         scan_duration_ms: { type: "integer", minimum: 0 },
+        catalog_version: { type: "string", maxLength: 64 },
+        artifact: {
+          type: "object",
+          required: ["type", "reference"],
+          additionalProperties: false,
+          properties: {
+            type: { type: "string", enum: ["archive", "image", "sbom"] },
+            reference: { type: "string", maxLength: 512 },
+            digest: { type: "string", pattern: "^sha256:[a-f0-9]{64}$" },
+          },
+        },
       },
     },
     TDMPackage: {
@@ -68,11 +140,14 @@ const TDM_SCHEMA = {
       additionalProperties: false,
       properties: {
         id: { type: "string", maxLength: 256 },
+        rule_id: { type: "string", pattern: "^[A-Za-z0-9][A-Za-z0-9_.-]*$", maxLength: 128 },
+        severity: { $ref: "#/$defs/Severity" },
         name: { type: "string", maxLength: 256 },
         ecosystem: { type: "string", maxLength: 256 },
         current_version: { type: "string", maxLength: 128 },
         version_constraint: { type: "string", maxLength: 128 },
         manifest_file: { type: "string", maxLength: 4096 },
+        services: { type: "array", items: { type: "string", maxLength: 256 }, maxItems: 100 },
         locations: { type: "array", items: { $ref: "#/$defs/TDMLocation" }, minItems: 1, maxItems: 1000 },
         usage_count: { type: "integer", minimum: 0 },
         confidence: { $ref: "#/$defs/Confidence" },
@@ -84,29 +159,102 @@ const TDM_SCHEMA = {
       additionalProperties: false,
       properties: {
         id: { type: "string", maxLength: 256 },
+        rule_id: { type: "string", pattern: "^[A-Za-z0-9][A-Za-z0-9_.-]*$", maxLength: 128 },
+        severity: { $ref: "#/$defs/Severity" },
+        data_flow: { $ref: "#/$defs/DataFlow" },
+        data_classification: { $ref: "#/$defs/DataClassification" },
         url: { type: "string", maxLength: 2048, pattern: "^(https?://|\\$\\{)" },
         method: {
           type: "string",
           enum: ["GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "CONNECT", "TRACE"],
         },
         provider: { type: ["string", "null"], maxLength: 256 },
+        category: { type: "string", maxLength: 64 },
         resolved_url: { type: "string", maxLength: 2048 },
         headers: { type: "array", items: { type: "string", maxLength: 256 }, maxItems: 100 },
+        first_party: { type: "boolean" },
+        gateway_bypass: { type: "string", maxLength: 128 },
+        enrichment: { $ref: "#/$defs/TDMEnrichment" },
+        suggestion: { $ref: "#/$defs/TDMSuggestion" },
+        runtime: { $ref: "#/$defs/TDMRuntime" },
         locations: { type: "array", items: { $ref: "#/$defs/TDMLocation" }, minItems: 1, maxItems: 1000 },
         usage_count: { type: "integer", minimum: 0 },
         confidence: { $ref: "#/$defs/Confidence" },
       },
     },
+    TDMEnrichment: {
+      type: "object",
+      additionalProperties: false,
+      properties: {
+        registrant: { type: "string", maxLength: 256 },
+        registrar: { type: "string", maxLength: 256 },
+        registered_at: { type: "string", maxLength: 64 },
+        ip: { type: "string", maxLength: 64 },
+        asn: { type: "integer", minimum: 0 },
+        as_name: { type: "string", maxLength: 256 },
+        cert_subject_org: { type: "string", maxLength: 256 },
+        cert_issuer_org: { type: "string", maxLength: 256 },
+      },
+    },
+    TDMSuggestion: {
+      type: "object",
+      required: ["source", "model"],
+      additionalProperties: false,
+      properties: {
+        source: { type: "string", enum: ["llm"] },
+        model: { type: "string", maxLength: 256 },
+        vendor: { type: "string", maxLength: 64 },
+        category: { type: "string", maxLength: 64 },
+        reasoning: { type: "string", maxLength: 1024 },
+      },
+    },
+    TDMRuntime: {
+      type: "object",
+      required: ["source", "count", "first_seen", "last_seen"],
+      additionalProperties: false,
+      properties: {
+        source: { type: "string", maxLength: 32 },
+        count: { type: "integer", minimum: 0 },
+        first_seen: { type: "string", format: "date-time", maxLength: 64 },
+        last_seen: { type: "string", format: "date-time", maxLength: 64 },
+        processes: { type: "array", items: { type: "string", maxLength: 256 }, maxItems: 100 },
+        clients: { type: "array", items: { type: "string", maxLength: 256 }, maxItems: 100 },
+        sdks: { type: "array", items: { $ref: "#/$defs/TDMRuntimeSdk" }, maxItems: 100 },
+      },
+    },
+    TDMRuntimeSdk: {
+      type: "object",
+      required: ["sdk_package", "count"],
+      additionalProperties: false,
+      properties: {
+        sdk_package: { type: "string", maxLength: 256 },
+        ecosystem: { type: "string", maxLength: 32 },
+        version: { type: "string", maxLength: 64 },
+        count: { type: "integer", minimum: 0 },
+      },
+    },
     TDMSdk: {
       type: "object",
       required: ["provider", "sdk_package", "locations", "usage_count", "confidence"],
       additionalProperties: false,
       properties: {
         id: { type: "string", maxLength: 256 },
+        rule_id: { type: "string", pattern: "^[A-Za-z0-9][A-Za-z0-9_.-]*$", maxLength: 128 },
+        severity: { $ref: "#/$defs/Severity" },
+        data_flow: { $ref: "#/$defs/DataFlow" },
+        data_classification: { $ref: "#/$defs/DataClassification" },
         provider: { type: "string", maxLength: 256 },
+        category: { type: "string", maxLength: 64 },
         sdk_package: { type: "string", maxLength: 256 },
         services_used: { type: "array", items: { type: "string", maxLength: 256 }, maxItems: 100 },
+        regions: { type: "array", items: { type: "string", maxLength: 64 }, maxItems: 100 },
+        base_url: { type: "string", maxLength: 2048 },
+        data_exported: { type: "array", items: { type: "string", maxLength: 64 }, maxItems: 20 },
         api_methods: { type: "array", items: { type: "string", maxLength: 256 }, maxItems: 100 },
+        intents: { type: "array", items: { type: "string", maxLength: 64 }, maxItems: 20 },
+        unused: { type: "boolean" },
+        gateway_bypass: { type: "string", maxLength: 128 },
+        suggestion: { $ref: "#/$defs/TDMSuggestion" },
         locations: { type: "array", items: { $ref: "#/$defs/TDMLocation" }, minItems: 1, maxItems: 1000 },
         usage_count: { type: "integer", minimum: 0 },
         confidence: { $ref: "#/$defs/Confidence" },
@@ -118,9 +266,17 @@ const TDM_SCHEMA = {
       additionalProperties: false,
       properties: {
         id: { type: "string", maxLength: 256 },
+        rule_id: { type: "string", pattern: "^[A-Za-z0-9][A-Za-z0-9_.-]*$", maxLength: 128 },
+        severity: { $ref: "#/$defs/Severity" },
+        data_flow: { $ref: "#/$defs/DataFlow" },
+        data_classification: { $ref: "#/$defs/DataClassification" },
         type: { type: "string", maxLength: 256 },
+        provider: { type: "string", maxLength: 256 },
+        category: { type: "string", maxLength: 64 },
         connection_ref: { type: "string", maxLength: 512 },
         resolved_host: { type: ["string", "null"], maxLength: 512 },
+        first_party: { type: "boolean" },
+        unused: { type: "boolean" },
         locations: { type: "array", items: { $ref: "#/$defs/TDMLocation" }, minItems: 1, maxItems: 1000 },
         confidence: { $ref: "#/$defs/Confidence" },
       },
@@ -131,9 +287,14 @@ const TDM_SCHEMA = {
       additionalProperties: false,
       properties: {
         id: { type: "string", maxLength: 256 },
+        rule_id: { type: "string", pattern: "^[A-Za-z0-9][A-Za-z0-9_.-]*$", maxLength: 128 },
+        severity: { $ref: "#/$defs/Severity" },
+        data_flow: { $ref: "#/$defs/DataFlow" },
+        data_classification: { $ref: "#/$defs/DataClassification" },
         direction: { type: "string", enum: ["outbound_registration", "inbound_callback"] },
         target_url: { type: "string", maxLength: 2048, pattern: "^(https?://|\\$\\{|/)" },
         provider: { type: "string", maxLength: 256 },
+        first_party: { type: "boolean" },
         locations: { type: "array", items: { $ref: "#/$defs/TDMLocation" }, minItems: 1, maxItems: 1000 },
         confidence: { $ref: "#/$defs/Confidence" },
       },
@@ -0,0 +1,46 @@
+#!/usr/bin/env node
+// scripts/build-catalog-bundle.mjs — Pack registries/sdks/*.yml into a signed catalog bundle
+//
+// Usage: CATALOG_SIGNING_KEY="$(cat key.pem)" node scripts/build-catalog-bundle.mjs <version> [outDir]
+//
+// Writes <outDir>/<version>/catalog.json, catalog.json.sig, and <outDir>/latest.json,
+// matching the layout `thirdwatch catalog update` downloads.
+
+import { readFileSync, readdirSync, mkdirSync, writeFileSync } from "node:fs";
+import { resolve, join, dirname } from "node:path";
+import { fileURLToPath } from "node:url";
+import { sign } from "node:crypto";
+import yaml from "js-yaml";
+
+const __dirname = dirname(fileURLToPath(import.meta.url));
+const ROOT = resolve(__dirname, "..");
+const REGISTRY_DIR = join(ROOT, "registries", "sdks");
+
+const [version, outDir = join(ROOT, "dist", "catalog")] = process.argv.slice(2);
+if (!version || !/^[0-9A-Za-z][0-9A-Za-z.+-]{0,63}$/.test(version)) {
+  console.error("Usage: node scripts/build-catalog-bundle.mjs <version> [outDir]");
+  process.exit(2);
+}
+
+const signingKey = process.env.CATALOG_SIGNING_KEY;
+if (!signingKey) {
+  console.error("CATALOG_SIGNING_KEY (Ed25519 private key, PEM) is required");
+  process.exit(2);
+}
+
+const entries = readdirSync(REGISTRY_DIR)
+  .filter((f) => f.endsWith(".yml"))
+  .sort()
+  .map((f) => yaml.load(readFileSync(join(REGISTRY_DIR, f), "utf8")));
+
+const bundle = { version, created_at: new Date().toISOString(), entries };
+const data = Buffer.from(JSON.stringify(bundle));
+const signature = sign(null, data, signingKey).toString("base64");
+
+const versionDir = join(outDir, version);
+mkdirSync(versionDir, { recursive: true });
+writeFileSync(join(versionDir, "catalog.json"), data);
+writeFileSync(join(versionDir, "catalog.json.sig"), signature + "\n");
+writeFileSync(join(outDir, "latest.json"), JSON.stringify({ version }) + "\n");
+
+console.log(`Built catalog ${version}: ${entries.length} vendors → ${versionDir}`);
@@ -0,0 +1,173 @@
+#!/usr/bin/env node
+// scripts/update-ip-ranges.mjs — Refresh catalog ip_ranges from vendors' published IP lists
+//
+// Usage: node scripts/update-ip-ranges.mjs [provider...]
+//
+// Rewrites the `ip_ranges:` block of registries/sdks/<provider>.yml in place (other
+// fields and comments are left untouched). Run before building a catalog bundle.
+
+import { readFileSync, writeFileSync } from "node:fs";
+import { resolve, join, dirname } from "node:path";
+import { fileURLToPath } from "node:url";
+
+const __dirname = dirname(fileURLToPath(import.meta.url));
+const REGISTRY_DIR = join(resolve(__dirname, ".."), "registries", "sdks");
+
+const lines = (text) => text.split(/\r?\n/).map((l) => l.trim()).filter((l) => l && !l.startsWith("#"));
+
+const SOURCES = {
+  aws: {
+    urls: ["https://ip-ranges.amazonaws.com/ip-ranges.json"],
+    extract: ([body]) => {
+      const data = JSON.parse(body);
+      return [
+        ...data.prefixes.map((p) => p.ip_prefix),
+        ...data.ipv6_prefixes.map((p) => p.ipv6_prefix),
+      ];
+    },
+  },
+  cloudflare: {
+    urls: ["https://www.cloudflare.com/ips-v4", "https://www.cloudflare.com/ips-v6"],
+    extract: (bodies) => bodies.flatMap(lines),
+  },
+  github: {
+    urls: ["https://api.github.com/meta"],
+    extract: ([body]) => {
+      const meta = JSON.parse(body);
+      return [...(meta.api ?? []), ...(meta.web ?? []), ...(meta.git ?? [])];
+    },
+  },
+  stripe: {
+    urls: ["https://stripe.com/files/ips/ips_api.txt"],
+    extract: (bodies) => bodies.flatMap(lines).map((ip) => (ip.includes("/") ? ip : `${ip}/32`)),
+  },
+};
+
+// ---------------------------------------------------------------------------
+// CIDR aggregation — cloud provider lists are heavily fragmented
+// ---------------------------------------------------------------------------
+
+function parse(cidr) {
+  const [addr, len] = cidr.split("/");
+  const v6 = addr.includes(":");
+  const max = v6 ? 128 : 32;
+  let value = 0n;
+  if (v6) {
+    const [head, tail = ""] = addr.split("::");
+    const h = head ? head.split(":") : [];
+    const t = tail ? tail.split(":") : [];
+    const groups = addr.includes("::") ? [...h, ...Array(8 - h.length - t.length).fill("0"), ...t] : h;
+    for (const g of groups) value = (value << 16n) | BigInt(parseInt(g, 16));
+  } else {
+    for (const p of addr.split(".")) value = (value << 8n) | BigInt(p);
+  }
+  const bits = len === undefined ? max : Number(len);
+  const shift = BigInt(max - bits);
+  return { v6, max, bits, value: (value >> shift) << shift };
+}
+
+function format({ v6, bits, value }) {
+  if (!v6) {
+    const octets = [24n, 16n, 8n, 0n].map((s) => String((value >> s) & 0xffn));
+    return `${octets.join(".")}/${bits}`;
+  }
+  const groups = [];
+  for (let i = 7; i >= 0; i--) groups.push(((value >> BigInt(i * 16)) & 0xffffn).toString(16));
+  // Compress the longest run of zero groups
+  let best = [-1, 0];
+  for (let i = 0; i < 8; ) {
+    if (groups[i] !== "0") { i++; continue; }
+    let j = i;
+    while (j < 8 && groups[j] === "0") j++;
+    if (j - i > best[1]) best = [i, j - i];
+    i = j;
+  }
+  const text = best[1] > 1
+    ? `${groups.slice(0, best[0]).join(":")}::${groups.slice(best[0] + best[1]).join(":")}`
+    : groups.join(":");
+  return `${text}/${bits}`;
+}
+
+function aggregate(cidrs) {
+  const byVersion = { v4: [], v6: [] };
+  for (const c of cidrs) {
+    const p = parse(c);
+    byVersion[p.v6 ? "v6" : "v4"].push(p);
+  }
+  const out = [];
+  for (const list of [byVersion.v4, byVersion.v6]) {
+    list.sort((a, b) => (a.value < b.value ? -1 : a.value > b.value ? 1 : a.bits - b.bits));
+    // Drop prefixes covered by an earlier, shorter one
+    let merged = [];
+    for (const p of list) {
+      const last = merged[merged.length - 1];
+      if (last && last.bits <= p.bits && (p.value >> BigInt(p.max - last.bits)) === (last.value >> BigInt(p.max - last.bits))) continue;
+      merged.push(p);
+    }
+    // Join adjacent siblings into their parent until nothing changes
+    let changed = true;
+    while (changed) {
+      changed = false;
+      const next = [];
+      for (const p of merged) {
+        const last = next[next.length - 1];
+        if (last && last.bits === p.bits && last.bits > 0) {
+          const parentShift = BigInt(p.max - p.bits + 1);
+          if ((last.value >> parentShift) === (p.value >> parentShift) && last.value !== p.value) {
+            next[next.length - 1] = { ...last, bits: last.bits - 1 };
+            changed = true;
+            continue;
+          }
+        }
+        next.push(p);
+      }
+      merged = next;
+    }
+    out.push(...merged.map(format));
+  }
+  return out;
+}
+
+// ---------------------------------------------------------------------------
+// YAML block rewrite
+// ---------------------------------------------------------------------------
+
+function replaceBlock(yamlText, ranges) {
+  const block = ["ip_ranges:", ...ranges.map((r) => `  - "${r}"`)].join("\n") + "\n";
+  const existing = /^ip_ranges:\n(?:[ \t]+.*\n|\n(?=[ \t]))*/m;
+  if (existing.test(yamlText)) return yamlText.replace(existing, block);
+  const anchor = /^env_var_patterns:/m;
+  if (anchor.test(yamlText)) return yamlText.replace(anchor, `${block}\nenv_var_patterns:`);
+  return `${yamlText.replace(/\n*$/, "\n")}\n${block}`;
+}
+
+const requested = process.argv.slice(2);
+const providers = requested.length > 0 ? requested : Object.keys(SOURCES);
+let failed = false;
+
+for (const provider of providers) {
+  const source = SOURCES[provider];
+  if (!source) {
+    console.error(`No published IP list configured for '${provider}'`);
+    failed = true;
+    continue;
+  }
+  try {
+    const bodies = await Promise.all(
+      source.urls.map(async (url) => {
+        const res = await fetch(url, { headers: { "user-agent": "thirdwatch-catalog" } });
+        if (!res.ok) throw new Error(`${url}: HTTP ${res.status}`);
+        return res.text();
+      }),
+    );
+    const ranges = aggregate([...new Set(source.extract(bodies))]);
+    const file = join(REGISTRY_DIR, `${provider}.yml`);
+    writeFileSync(file, replaceBlock(readFileSync(file, "utf8"), ranges));
+    console.log(`${provider}: ${ranges.length} ranges`);
+  } catch (err) {
+    console.error(`${provider}: ${err instanceof Error ? err.message : String(err)}`);
+    failed = true;
+  }
+}
+
+process.exit(failed ? 1 : 0);
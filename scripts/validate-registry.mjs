@@ -23,6 +23,14 @@ const SDK_PATTERN_KEYS = new Set(
 const CONSTRUCTOR_KEYS = new Set(
   Object.keys(schema.$defs.ConstructorPattern.properties),
 );
+const EXAMPLE_KEYS = new Set(
+  Object.keys(schema.$defs.CatalogExample.properties),
+);
+const CATEGORIES = new Set(schema.properties.category.enum);
+const DOMAIN_RE = new RegExp(schema.properties.domains.items.pattern);
+const IP_RANGE_RE = new RegExp(schema.properties.ip_ranges.items.pattern);
+const SLA_KEYS = new Set(Object.keys(schema.properties.sla.properties));
+const RATE_LIMIT_KEYS = new Set(Object.keys(schema.properties.rate_limits.items.properties));
 const REQUIRED_TOP = schema.required; // ["provider", "display_name", "patterns"]
 const REQUIRED_SDK_PATTERN = schema.$defs.SDKPatternEntry.required; // ["package"]
 const REQUIRED_CONSTRUCTOR = schema.$defs.ConstructorPattern.required; // ["name"]
@@ -232,6 +240,118 @@ function validateEntry(entry) {
     }
   }
 
+  // category
+  if (entry.category != null && !CATEGORIES.has(entry.category)) {
+    errors.push(`'category' must be one of: ${[...CATEGORIES].join(", ")}`);
+  }
+
+  // docs_url, status_page_url, and the compliance links
+  for (const key of ["docs_url", "status_page_url", "privacy_policy_url", "dpa_url", "subprocessors_url", "trust_center_url"]) {
+    if (entry[key] != null && typeof entry[key] !== "string") {
+      errors.push(`'${key}' must be a string`);
+    }
+  }
+
+  // domains
+  if (entry.domains != null) {
+    if (!Array.isArray(entry.domains)) {
+      errors.push("'domains' must be an array");
+    } else {
+      for (let i = 0; i < entry.domains.length; i++) {
+        if (typeof entry.domains[i] !== "string" || !DOMAIN_RE.test(entry.domains[i])) {
+          errors.push(`domains[${i}] must be a lowercase hostname (got ${JSON.stringify(entry.domains[i])})`);
+        }
+      }
+    }
+  }
+
+  // ip_ranges
+  if (entry.ip_ranges != null) {
+    if (!Array.isArray(entry.ip_ranges)) {
+      errors.push("'ip_ranges' must be an array");
+    } else {
+      for (let i = 0; i < entry.ip_ranges.length; i++) {
+        if (typeof entry.ip_ranges[i] !== "string" || !IP_RANGE_RE.test(entry.ip_ranges[i])) {
+          errors.push(`ip_ranges[${i}] must be a CIDR block (got ${JSON.stringify(entry.ip_ranges[i])})`);
+        }
+      }
+    }
+  }
+
+  // sla
+  if (entry.sla != null) {
+    const isUptime = (v) => typeof v === "number" && v > 0 && v <= 100;
+    if (typeof entry.sla !== "object" || Array.isArray(entry.sla)) {
+      errors.push("'sla' must be an object");
+    } else {
+      for (const key of Object.keys(entry.sla)) {
+        if (!SLA_KEYS.has(key)) errors.push(`unknown key '${key}' in sla`);
+      }
+      if (!isUptime(entry.sla.uptime)) errors.push("sla.uptime must be a percentage in (0, 100]");
+      if (typeof entry.sla.url !== "string" || !/^https?:\/\//.test(entry.sla.url)) {
+        errors.push("sla.url must be an http(s) URL");
+      }
+      if (entry.sla.services != null) {
+        if (!Array.isArray(entry.sla.services)) {
+          errors.push("sla.services must be an array");
+        } else {
+          entry.sla.services.forEach((svc, i) => {
+            if (svc == null || typeof svc.name !== "string" || !isUptime(svc.uptime) || !Array.isArray(svc.domains)) {
+              errors.push(`sla.services[${i}] needs 'name', 'uptime', and 'domains'`);
+            }
+          });
+        }
+      }
+    }
+  }
+
+  // rate_limits
+  if (entry.rate_limits != null) {
+    const isPositiveInteger = (v) => Number.isInteger(v) && v > 0;
+    if (!Array.isArray(entry.rate_limits)) {
+      errors.push("'rate_limits' must be an array");
+    } else {
+      entry.rate_limits.forEach((limit, i) => {
+        if (limit == null || typeof limit !== "object" || Array.isArray(limit)) {
+          errors.push(`rate_limits[${i}] must be an object`);
+          return;
+        }
+        for (const key of Object.keys(limit)) {
+          if (!RATE_LIMIT_KEYS.has(key)) errors.push(`unknown key '${key}' in rate_limits[${i}]`);
+        }
+        if (!isPositiveInteger(limit.requests)) errors.push(`rate_limits[${i}].requests must be a positive integer`);
+        if (!isPositiveInteger(limit.window_seconds)) errors.push(`rate_limits[${i}].window_seconds must be a positive integer`);
+        if (typeof limit.url !== "string" || !/^https?:\/\//.test(limit.url)) {
+          errors.push(`rate_limits[${i}].url must be an http(s) URL`);
+        }
+        if (limit.domains != null && !Array.isArray(limit.domains)) errors.push(`rate_limits[${i}].domains must be an array`);
+      });
+    }
+  }
+
+  // examples (fixtures are executed by `thirdwatch catalog validate`)
+  if (entry.examples != null) {
+    if (!Array.isArray(entry.examples)) {
+      errors.push("'examples' must be an array");
+    } else {
+      for (let i = 0; i < entry.examples.length; i++) {
+        const ex = entry.examples[i];
+        if (ex == null || typeof ex !== "object" || Array.isArray(ex)) {
+          errors.push(`examples[${i}] must be an object`);
+          continue;
+        }
+        for (const key of Object.keys(ex)) {
+          if (!EXAMPLE_KEYS.has(key)) {
+            errors.push(`unknown key '${key}' in examples[${i}]`);
+          }
+        }
+        if (ex.code == null && ex.url == null) {
+          errors.push(`examples[${i}] needs 'code' or 'url'`);
+        }
+      }
+    }
+  }
+
   return errors;
 }
 
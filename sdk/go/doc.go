@@ -0,0 +1,23 @@
+// Package thirdwatch reports an application's live third-party calls to a
+// thirdwatch server, complementing static scans with per-service usage:
+// which hosts each service actually calls, how often, how many of those
+// calls fail, and how long they take.
+//
+// Wrap outbound HTTP clients with Reporter.Transport or Reporter.WrapClient,
+// and database / cache drivers that accept a custom dialer with
+// Reporter.Dialer. Anything else can report through Reporter.Record.
+//
+//	r, err := thirdwatch.NewReporter(thirdwatch.Config{
+//		Endpoint: "https://thirdwatch.example.com",
+//		APIKey:   os.Getenv("THIRDWATCH_API_KEY"),
+//		Service:  "checkout",
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer r.Close(context.Background())
+//	http.DefaultClient = r.WrapClient(http.DefaultClient)
+//
+// Only destinations are recorded — never URL paths, headers, or bodies.
+// Calls to loopback, private, and cluster-internal hosts are ignored.
+package thirdwatch
@@ -0,0 +1,3 @@
+module github.com/poojitha-rachuri/thirdwatch/sdk/go
+
+go 1.22
@@ -0,0 +1,295 @@
+package thirdwatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UsagePath is the server endpoint reports are posted to.
+const UsagePath = "/api/v1/runtime/usage"
+
+const defaultFlushInterval = time.Minute
+
+// Config configures a Reporter.
+type Config struct {
+	// Endpoint is the thirdwatch server base URL.
+	Endpoint string
+	// APIKey is sent as the x-api-key header.
+	APIKey string
+	// Service names the reporting application; usage is grouped by it.
+	Service string
+	// Environment optionally labels the deployment, e.g. "production".
+	Environment string
+	// FlushInterval is how often stats are sent (default: one minute).
+	FlushInterval time.Duration
+	// Vendors maps host suffixes to vendor slugs, for hosts the server's
+	// catalog does not know ("payments.partner.io" → "partner").
+	Vendors map[string]string
+	// Client sends the reports (default: a client with a 10s timeout).
+	// Its own requests are never recorded.
+	Client *http.Client
+	// OnError is called when a report fails to send. Stats from a failed
+	// window are dropped.
+	OnError func(error)
+}
+
+// Call is a single outbound call.
+type Call struct {
+	// Kind is the protocol or driver: "http", "postgres", "redis", ...
+	Kind string
+	// Host is the destination hostname or IP, without port.
+	Host string
+	// Method is the HTTP method, if any.
+	Method string
+	// Duration is the call latency (or connect latency for dialers).
+	Duration time.Duration
+	// Failed marks transport errors and server-side (5xx) failures.
+	Failed bool
+}
+
+type statKey struct {
+	kind, host, method string
+}
+
+type stat struct {
+	count, errors int64
+	totalMs       float64
+	maxMs         float64
+}
+
+// Reporter aggregates calls in memory and periodically posts them to the
+// thirdwatch server. It is safe for concurrent use.
+type Reporter struct {
+	cfg      Config
+	url      string
+	selfHost string
+
+	mu          sync.Mutex
+	stats       map[statKey]*stat
+	windowStart time.Time
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewReporter validates cfg and starts the background flush loop.
+func NewReporter(cfg Config) (*Reporter, error) {
+	if cfg.Service == "" {
+		return nil, errors.New("thirdwatch: Config.Service is required")
+	}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("thirdwatch: invalid Config.Endpoint %q", cfg.Endpoint)
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	r := &Reporter{
+		cfg:         cfg,
+		url:         strings.TrimRight(cfg.Endpoint, "/") + UsagePath,
+		selfHost:    strings.ToLower(u.Hostname()),
+		stats:       make(map[statKey]*stat),
+		windowStart: time.Now(),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go r.loop()
+	return r, nil
+}
+
+// Record adds one call to the current window. Calls to internal
+// destinations and to the thirdwatch server itself are ignored.
+func (r *Reporter) Record(c Call) {
+	host := normalizeHost(c.Host)
+	if host == "" || host == r.selfHost || isInternal(host) {
+		return
+	}
+	kind := c.Kind
+	if kind == "" {
+		kind = "http"
+	}
+	ms := float64(c.Duration) / float64(time.Millisecond)
+	key := statKey{kind: kind, host: host, method: strings.ToUpper(c.Method)}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.stats[key]
+	if !ok {
+		s = &stat{}
+		r.stats[key] = s
+	}
+	s.count++
+	if c.Failed {
+		s.errors++
+	}
+	s.totalMs += ms
+	if ms > s.maxMs {
+		s.maxMs = ms
+	}
+}
+
+type reportCall struct {
+	Kind           string  `json:"kind"`
+	Host           string  `json:"host"`
+	Vendor         string  `json:"vendor,omitempty"`
+	Method         string  `json:"method,omitempty"`
+	Count          int64   `json:"count"`
+	Errors         int64   `json:"errors"`
+	LatencyMsTotal float64 `json:"latency_ms_total"`
+	LatencyMsMax   float64 `json:"latency_ms_max"`
+}
+
+type report struct {
+	Service     string       `json:"service"`
+	Environment string       `json:"environment,omitempty"`
+	WindowStart string       `json:"window_start"`
+	WindowEnd   string       `json:"window_end"`
+	Calls       []reportCall `json:"calls"`
+}
+
+// Flush sends the current window now. It is a no-op when nothing was
+// recorded.
+func (r *Reporter) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	stats := r.stats
+	start := r.windowStart
+	r.stats = make(map[statKey]*stat)
+	r.windowStart = time.Now()
+	r.mu.Unlock()
+
+	if len(stats) == 0 {
+		return nil
+	}
+
+	rep := report{
+		Service:     r.cfg.Service,
+		Environment: r.cfg.Environment,
+		WindowStart: start.UTC().Format(time.RFC3339Nano),
+		WindowEnd:   time.Now().UTC().Format(time.RFC3339Nano),
+		Calls:       make([]reportCall, 0, len(stats)),
+	}
+	for k, s := range stats {
+		rep.Calls = append(rep.Calls, reportCall{
+			Kind:           k.kind,
+			Host:           k.host,
+			Vendor:         r.vendorFor(k.host),
+			Method:         k.method,
+			Count:          s.count,
+			Errors:         s.errors,
+			LatencyMsTotal: s.totalMs,
+			LatencyMsMax:   s.maxMs,
+		})
+	}
+	sort.Slice(rep.Calls, func(i, j int) bool {
+		a, b := rep.Calls[i], rep.Calls[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Method < b.Method
+	})
+
+	body, err := json.Marshal(rep)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+	if r.cfg.APIKey != "" {
+		req.Header.Set("x-api-key", r.cfg.APIKey)
+	}
+	resp, err := r.cfg.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("thirdwatch: send report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("thirdwatch: send report: server returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close stops the flush loop and sends whatever is left.
+func (r *Reporter) Close(ctx context.Context) error {
+	r.closeOnce.Do(func() { close(r.stop) })
+	<-r.done
+	return r.Flush(ctx)
+}
+
+func (r *Reporter) loop() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), r.cfg.FlushInterval)
+			if err := r.Flush(ctx); err != nil && r.cfg.OnError != nil {
+				r.cfg.OnError(err)
+			}
+			cancel()
+		}
+	}
+}
+
+// vendorFor applies Config.Vendors; the longest matching suffix wins.
+func (r *Reporter) vendorFor(host string) string {
+	best, vendor := -1, ""
+	for suffix, v := range r.cfg.Vendors {
+		s := strings.ToLower(strings.TrimPrefix(suffix, "*."))
+		if (host == s || strings.HasSuffix(host, "."+s)) && len(s) > best {
+			best, vendor = len(s), v
+		}
+	}
+	return vendor
+}
+
+func normalizeHost(host string) string {
+	h := strings.ToLower(strings.TrimSpace(host))
+	if sh, _, err := net.SplitHostPort(h); err == nil {
+		h = sh
+	}
+	return strings.TrimSuffix(strings.Trim(h, "[]"), ".")
+}
+
+var internalSuffixes = []string{
+	".cluster.local", ".svc", ".internal", ".local", ".localdomain",
+}
+
+// isInternal reports loopback, private, and cluster-internal destinations.
+func isInternal(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified()
+	}
+	if !strings.Contains(host, ".") {
+		return true
+	}
+	for _, s := range internalSuffixes {
+		if strings.HasSuffix(host, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package thirdwatch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type captured struct {
+	mu      sync.Mutex
+	reports []report
+	apiKeys []string
+}
+
+func newServer(t *testing.T) (*httptest.Server, *captured) {
+	t.Helper()
+	c := &captured{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != UsagePath {
+			http.NotFound(w, req)
+			return
+		}
+		var rep report
+		if err := json.NewDecoder(req.Body).Decode(&rep); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.mu.Lock()
+		c.reports = append(c.reports, rep)
+		c.apiKeys = append(c.apiKeys, req.Header.Get("x-api-key"))
+		c.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, c
+}
+
+func TestNewReporterValidatesConfig(t *testing.T) {
+	if _, err := NewReporter(Config{Endpoint: "https://tw.example.com"}); err == nil {
+		t.Error("expected error for missing Service")
+	}
+	if _, err := NewReporter(Config{Endpoint: "tw.example.com", Service: "svc"}); err == nil {
+		t.Error("expected error for endpoint without scheme")
+	}
+}
+
+func TestRecordAggregatesAndFlushes(t *testing.T) {
+	srv, got := newServer(t)
+	r, err := NewReporter(Config{
+		Endpoint:    srv.URL,
+		APIKey:      "tw_key",
+		Service:     "checkout",
+		Environment: "staging",
+		Vendors:     map[string]string{"partner.io": "partner"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Record(Call{Host: "API.Stripe.com", Method: "post", Duration: 120 * time.Millisecond})
+	r.Record(Call{Host: "api.stripe.com:443", Method: "POST", Duration: 80 * time.Millisecond, Failed: true})
+	r.Record(Call{Kind: "postgres", Host: "db.partner.io", Duration: 5 * time.Millisecond})
+	// Internal destinations are never reported
+	r.Record(Call{Host: "10.0.0.5"})
+	r.Record(Call{Host: "inventory.default.svc.cluster.local"})
+	r.Record(Call{Host: "localhost"})
+
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.reports) != 1 {
+		t.Fatalf("got %d reports, want 1", len(got.reports))
+	}
+	rep := got.reports[0]
+	if rep.Service != "checkout" || rep.Environment != "staging" || got.apiKeys[0] != "tw_key" {
+		t.Errorf("unexpected report header: %+v key=%q", rep, got.apiKeys[0])
+	}
+	want := []reportCall{
+		{Kind: "http", Host: "api.stripe.com", Method: "POST", Count: 2, Errors: 1, LatencyMsTotal: 200, LatencyMsMax: 120},
+		{Kind: "postgres", Host: "db.partner.io", Vendor: "partner", Count: 1, LatencyMsTotal: 5, LatencyMsMax: 5},
+	}
+	if len(rep.Calls) != len(want) {
+		t.Fatalf("calls = %+v", rep.Calls)
+	}
+	for i := range want {
+		if rep.Calls[i] != want[i] {
+			t.Errorf("calls[%d] = %+v, want %+v", i, rep.Calls[i], want[i])
+		}
+	}
+}
+
+func TestFlushSkipsEmptyWindowsAndReportsErrors(t *testing.T) {
+	r, err := NewReporter(Config{Endpoint: "http://127.0.0.1:1", Service: "svc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Flush(context.Background()); err != nil {
+		t.Errorf("empty flush: %v", err)
+	}
+	r.Record(Call{Host: "api.stripe.com"})
+	if err := r.Close(context.Background()); err == nil {
+		t.Error("expected send error")
+	}
+}
+
+func TestTransportRecordsRequests(t *testing.T) {
+	srv, got := newServer(t)
+	r, err := NewReporter(Config{Endpoint: srv.URL, Service: "svc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Route every request to a local upstream while keeping the requested host
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasPrefix(req.URL.Path, "/fail") {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer upstream.Close()
+	base := &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, upstream.Listener.Addr().String())
+		},
+	}
+	client := r.WrapClient(&http.Client{Transport: base})
+
+	for _, path := range []string{"/v1/charges", "/fail"} {
+		resp, err := client.Get("http://api.stripe.com" + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := got.reports[0].Calls
+	if len(calls) != 1 || calls[0].Host != "api.stripe.com" || calls[0].Count != 2 || calls[0].Errors != 1 {
+		t.Errorf("calls = %+v", calls)
+	}
+}
+
+func TestDialerRecordsConnections(t *testing.T) {
+	srv, got := newServer(t)
+	r, err := NewReporter(Config{Endpoint: srv.URL, Service: "svc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing := func(context.Context, string, string) (net.Conn, error) {
+		return nil, errors.New("refused")
+	}
+	dial := r.Dialer("redis", failing)
+	if _, err := dial(context.Background(), "tcp", "cache.upstash.io:6379"); err == nil {
+		t.Fatal("expected dial error")
+	}
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := reportCall{Kind: "redis", Host: "cache.upstash.io", Count: 1, Errors: 1}
+	c := got.reports[0].Calls[0]
+	c.LatencyMsTotal, c.LatencyMsMax = 0, 0
+	if c != want {
+		t.Errorf("call = %+v, want %+v", c, want)
+	}
+}
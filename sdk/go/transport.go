@@ -0,0 +1,69 @@
+package thirdwatch
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+type transport struct {
+	r    *Reporter
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	t.r.Record(Call{
+		Kind:     "http",
+		Host:     req.URL.Hostname(),
+		Method:   req.Method,
+		Duration: time.Since(start),
+		Failed:   err != nil || resp.StatusCode >= 500,
+	})
+	return resp, err
+}
+
+// Transport wraps base (http.DefaultTransport if nil) so every request it
+// sends is recorded.
+func (r *Reporter) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{r: r, base: base}
+}
+
+// WrapClient returns a copy of c whose transport is recorded. A nil c
+// wraps a zero http.Client.
+func (r *Reporter) WrapClient(c *http.Client) *http.Client {
+	var out http.Client
+	if c != nil {
+		out = *c
+	}
+	out.Transport = r.Transport(out.Transport)
+	return &out
+}
+
+// DialFunc matches net.Dialer.DialContext, the hook most database and
+// cache drivers accept for custom connections.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Dialer wraps dial (a default net.Dialer if nil) so each new connection is
+// recorded as a call of the given kind, with the connect time as latency.
+// Pooled drivers therefore report connections, not queries.
+func (r *Reporter) Dialer(kind string, dial DialFunc) DialFunc {
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second}).DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		start := time.Now()
+		conn, err := dial(ctx, network, addr)
+		host, _, splitErr := net.SplitHostPort(addr)
+		if splitErr != nil {
+			host = addr
+		}
+		r.Record(Call{Kind: kind, Host: host, Duration: time.Since(start), Failed: err != nil})
+		return conn, err
+	}
+}